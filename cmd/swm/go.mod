@@ -7,6 +7,10 @@ require (
 	github.com/gofrs/flock v0.13.0
 	github.com/hashicorp/go-hclog v1.6.3
 	github.com/hashicorp/go-plugin v1.8.0
+	github.com/kalbasit/swm/plugins/forge-github v0.0.0
+	github.com/kalbasit/swm/plugins/picker-fzf v0.0.0
+	github.com/kalbasit/swm/plugins/session-tmux v0.0.0
+	github.com/kalbasit/swm/plugins/vcs-git v0.0.0
 	github.com/kalbasit/swm/proto v0.0.0
 	github.com/kalbasit/swm/sdk/go v0.0.0
 	github.com/pelletier/go-toml/v2 v2.4.3
@@ -15,12 +19,15 @@ require (
 	golang.org/x/sys v0.47.0
 	golang.org/x/term v0.45.0
 	google.golang.org/grpc v1.83.0
+	google.golang.org/protobuf v1.36.12
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/fatih/color v1.19.0 // indirect
 	github.com/golang/protobuf v1.5.4 // indirect
+	github.com/google/go-github/v88 v88.0.0 // indirect
+	github.com/google/go-querystring v1.2.0 // indirect
 	github.com/hashicorp/yamux v0.1.2 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/kr/text v0.2.0 // indirect
@@ -33,11 +40,14 @@ require (
 	golang.org/x/net v0.58.0 // indirect
 	golang.org/x/text v0.41.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20260810153831-ec0a7760b754 // indirect
-	google.golang.org/protobuf v1.36.12 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace (
+	github.com/kalbasit/swm/plugins/forge-github => ../../plugins/forge-github
+	github.com/kalbasit/swm/plugins/picker-fzf => ../../plugins/picker-fzf
+	github.com/kalbasit/swm/plugins/session-tmux => ../../plugins/session-tmux
+	github.com/kalbasit/swm/plugins/vcs-git => ../../plugins/vcs-git
 	github.com/kalbasit/swm/proto => ../../proto
 	github.com/kalbasit/swm/sdk/go => ../../sdk/go
 )
@@ -0,0 +1,152 @@
+// Package bundled serves the plugins linked into swm in the swm process, over
+// an in-memory gRPC connection, for when their binaries are not installed.
+package bundled
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	forgegithub "github.com/kalbasit/swm/plugins/forge-github/bundle"
+	pickerfzf "github.com/kalbasit/swm/plugins/picker-fzf/bundle"
+	sessiontmux "github.com/kalbasit/swm/plugins/session-tmux/bundle"
+	vcsgit "github.com/kalbasit/swm/plugins/vcs-git/bundle"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/sdk/go/hostcall"
+	"github.com/kalbasit/swm/sdk/go/trace"
+)
+
+// bufSize is the size of the in-memory connection's buffer.
+const bufSize = 1 << 20
+
+var errNotBundled = errors.New("plugin not bundled with swm")
+
+// Has reports whether the plugin name of capability is bundled with swm.
+func Has(capability, name string) bool {
+	switch capability + "-" + name {
+	case "forge-github", "picker-fzf", "session-tmux", "vcs-git":
+		return true
+	default:
+		return false
+	}
+}
+
+// Serve starts the bundled plugin name of capability and returns a
+// connection to it, dialed with opts, and a func stopping it. The plugin
+// calls back the Host service at hostSocket, if given, with the caller token
+// token, like the plugin binary does with SWM_HOST_SOCKET and
+// SWM_CALLER_TOKEN.
+func Serve(capability, name, hostSocket, token string, opts ...grpc.DialOption) (*grpc.ClientConn, func(), error) {
+	var (
+		host     pluginv1.HostClient
+		hostConn *grpc.ClientConn
+	)
+
+	if hostSocket != "" {
+		var err error
+
+		hostConn, err = grpc.NewClient(hostSocket,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithUnaryInterceptor(callerInterceptor(token)))
+		if err != nil {
+			return nil, nil, fmt.Errorf("connecting bundled plugin %s-%s to host socket: %w", capability, name, err)
+		}
+
+		host = pluginv1.NewHostClient(hostConn)
+	}
+
+	closeHost := func() {
+		if hostConn != nil {
+			hostConn.Close() //nolint:errcheck,gosec // best-effort close of a local connection
+		}
+	}
+
+	srv := trace.GRPCServer(nil)
+
+	if err := register(srv, capability, name, host); err != nil {
+		closeHost()
+
+		return nil, nil, err
+	}
+
+	lis := bufconn.Listen(bufSize)
+
+	go srv.Serve(lis) //nolint:errcheck // stopped by the returned func
+
+	conn, err := grpc.NewClient("passthrough:///"+capability+"-"+name, append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)...)
+	if err != nil {
+		srv.Stop()
+		closeHost()
+
+		return nil, nil, fmt.Errorf("connecting to bundled plugin %s-%s: %w", capability, name, err)
+	}
+
+	stop := func() {
+		conn.Close() //nolint:errcheck,gosec // best-effort close of a local connection
+		srv.Stop()
+		closeHost()
+	}
+
+	return conn, stop, nil
+}
+
+// callerInterceptor sets the caller token token, as the SWM_CALLER_TOKEN
+// variable of a plugin binary does, in the host calls of a bundled plugin,
+// which shares the swm process's environment.
+func callerInterceptor(token string) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+		md.Set(hostcall.CallerTokenKey, token)
+
+		return invoker(metadata.NewOutgoingContext(ctx, md), method, req, reply, cc, opts...)
+	}
+}
+
+// register registers the bundled plugin name of capability with srv.
+func register(srv *grpc.Server, capability, name string, host pluginv1.HostClient) error {
+	switch capability + "-" + name {
+	case "forge-github":
+		pluginv1.RegisterForgeServer(srv, forgegithub.New(host))
+	case "picker-fzf":
+		picker, err := pickerfzf.New()
+		if err != nil {
+			return fmt.Errorf("starting bundled plugin %s-%s: %w", capability, name, err)
+		}
+
+		pluginv1.RegisterPickerServer(srv, picker)
+	case "session-tmux":
+		session, err := sessiontmux.New(host)
+		if err != nil {
+			return fmt.Errorf("starting bundled plugin %s-%s: %w", capability, name, err)
+		}
+
+		pluginv1.RegisterSessionServer(srv, session)
+	case "vcs-git":
+		vcs, err := vcsgit.New()
+		if err != nil {
+			return fmt.Errorf("starting bundled plugin %s-%s: %w", capability, name, err)
+		}
+
+		pluginv1.RegisterVCSServer(srv, vcs)
+	default:
+		return fmt.Errorf("%w: %s-%s", errNotBundled, capability, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/cobra"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/pluginrec"
+)
+
+// NewBugReportCmd returns the `swm bug-report` command.
+func NewBugReportCmd(cfg *config.Config) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "bug-report <recording>",
+		Short: "Redact a recording of plugin calls for a bug report",
+		Long: "Write a copy of a recording made with SWM_RECORD=<file> to stdout, or to --output, " +
+			"for attaching to a bug report. Paths below the code root, the XDG directories and the " +
+			"home directory become $CODE_ROOT, $XDG_DATA_HOME and so on, and tokens, passwords and " +
+			"other secrets become REDACTED. Review the result before sharing it: names of stories, " +
+			"branches and repositories are kept.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in, err := os.Open(args[0]) //nolint:gosec // the user chose the path
+			if err != nil {
+				return fmt.Errorf("opening recording: %w", err)
+			}
+			defer in.Close() //nolint:errcheck // read-only
+
+			if output == "" {
+				return pluginrec.Redact(in, cmd.OutOrStdout(), redactedPaths(cfg))
+			}
+
+			out, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+			if err != nil {
+				return fmt.Errorf("creating %s: %w", output, err)
+			}
+
+			if err := pluginrec.Redact(in, out, redactedPaths(cfg)); err != nil {
+				out.Close() //nolint:errcheck,gosec // the redaction error matters more
+
+				return err
+			}
+
+			if err := out.Close(); err != nil {
+				return fmt.Errorf("writing %s: %w", output, err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "write the redacted recording to `file`")
+
+	return cmd
+}
+
+// redactedPaths returns the directories whose paths a bug report hides, with
+// the variable each is replaced by.
+func redactedPaths(cfg *config.Config) map[string]string {
+	paths := map[string]string{
+		cfg.CodeRoot:                       "$CODE_ROOT",
+		cmp.Or(cfg.DataHome, xdg.DataHome): "$XDG_DATA_HOME",
+		xdg.ConfigHome:                     "$XDG_CONFIG_HOME",
+		xdg.CacheHome:                      "$XDG_CACHE_HOME",
+		xdg.RuntimeDir:                     "$XDG_RUNTIME_DIR",
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		paths[home] = "$HOME"
+	}
+
+	return paths
+}
@@ -0,0 +1,44 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli"
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+)
+
+func TestBugReportCmd(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	codeRoot := filepath.Join(dir, "code")
+	recording := filepath.Join(dir, "calls.jsonl")
+
+	require.NoError(t, os.WriteFile(recording, []byte(`{"plugin":"vcs-git",`+
+		`"method":"/swm.plugin.v1.VCS/GetUserIdentity","requests":[{"repoPath":"`+codeRoot+`/repositories/x"}]}`+"\n"),
+		0o600))
+
+	var out bytes.Buffer
+
+	cmd := cli.NewBugReportCmd(&config.Config{CodeRoot: codeRoot})
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{recording})
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, out.String(), `"repoPath":"$CODE_ROOT/repositories/x"`)
+
+	// With --output, the redacted recording goes to the file.
+	report := filepath.Join(dir, "report.jsonl")
+
+	cmd = cli.NewBugReportCmd(&config.Config{CodeRoot: codeRoot})
+	cmd.SetArgs([]string{recording, "--output", report})
+	require.NoError(t, cmd.Execute())
+
+	data, err := os.ReadFile(report) //nolint:gosec // test file
+	require.NoError(t, err)
+	require.NotContains(t, string(data), codeRoot)
+}
@@ -1,30 +1,23 @@
 package cli
 
 import (
-	"errors"
 	"fmt"
-	"io"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/spf13/cobra"
 
-	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
-
+	"github.com/kalbasit/swm/cmd/swm/internal/clone"
 	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
 	"github.com/kalbasit/swm/cmd/swm/internal/hookexec"
 )
 
-// errUnexpectedVCSPlugin is returned when the vcs plugin is not the expected type.
-var errUnexpectedVCSPlugin = errors.New("unexpected vcs plugin type")
-
 // NewCloneCmd returns the `swm clone` command.
 func NewCloneCmd(mgr PluginManager, resolver *layout.Resolver, hooks hookexec.Runner) *cobra.Command {
 	return &cobra.Command{
 		Use:   "clone <url>",
 		Short: "Clone a repository to its canonical path",
-		Args:  cobra.ExactArgs(1),
+		Long: "Clone a repository to its canonical path. With several vcs plugins configured, the " +
+			"URL goes to the plugin claiming its scheme (e.g. hg+ssh://), or to the default one.",
+		Args: cobra.ExactArgs(1),
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			mgr.Warm(cmd.Context(), "vcs") //nolint:errcheck,gosec // Warm always returns nil; errors deferred to Get
 
@@ -34,85 +27,28 @@ func NewCloneCmd(mgr PluginManager, resolver *layout.Resolver, hooks hookexec.Ru
 			url := args[0]
 			ctx := cmd.Context()
 
-			raw, err := mgr.Get(ctx, "vcs")
+			name, err := mgr.VCSForURL(ctx, url)
 			if err != nil {
-				return fmt.Errorf("loading vcs plugin: %w", err)
-			}
-
-			vcs, ok := raw.(pluginv1.VCSClient)
-			if !ok {
-				return fmt.Errorf("%w: %T", errUnexpectedVCSPlugin, raw)
+				return fmt.Errorf("routing %q to a vcs plugin: %w", url, err)
 			}
 
-			id, err := vcs.ParseRemoteURL(ctx, &pluginv1.ParseRemoteURLRequest{Url: url})
+			vcs, err := mgr.GetVCS(ctx, name)
 			if err != nil {
-				return fmt.Errorf("parsing URL %q: %w", url, err)
-			}
-
-			canonical := resolver.CanonicalPath(id)
-
-			if _, err := os.Stat(filepath.Join(canonical, ".git")); err == nil {
-				cmd.Printf("already cloned at %s\n", canonical)
-
-				return nil
-			}
-
-			projectPath := strings.Join(id.GetSegments(), "/")
-			codeRoot := resolver.CodeRoot()
-
-			if err := hooks.Run(ctx, hookexec.RunConfig{
-				Event:       "pre-clone",
-				CodeRoot:    codeRoot,
-				ProjectHost: id.GetHost(),
-				ProjectPath: projectPath,
-				WorkDir:     codeRoot,
-			}); err != nil {
-				return fmt.Errorf("pre-clone hook: %w", err)
+				return fmt.Errorf("loading vcs plugin: %w", err)
 			}
 
-			stream, err := vcs.Clone(ctx, &pluginv1.CloneRequest{
-				Url:             url,
-				DestinationPath: canonical,
-			})
+			res, err := clone.Run(ctx, vcs, resolver, hooks, url, cmd.ErrOrStderr())
 			if err != nil {
-				return fmt.Errorf("cloning %q: %w", url, err)
+				return err
 			}
 
-			for {
-				evt, recvErr := stream.Recv()
-				if errors.Is(recvErr, io.EOF) {
-					break
-				}
-
-				if recvErr != nil {
-					return fmt.Errorf("cloning %q: %w", url, recvErr)
-				}
+			if res.Existing {
+				cmd.Printf("already cloned at %s\n", res.Path)
 
-				if pid := evt.GetProjectId(); pid != nil {
-					id = pid
-					projectPath = strings.Join(id.GetSegments(), "/")
-
-					continue
-				}
-
-				if line := evt.GetProgressLine(); line != "" {
-					fmt.Fprint(cmd.ErrOrStderr(), line) //nolint:errcheck // writing progress to stderr is best-effort
-				}
-			}
-
-			if err := hooks.Run(ctx, hookexec.RunConfig{
-				Event:       "post-clone",
-				CodeRoot:    codeRoot,
-				ProjectHost: id.GetHost(),
-				ProjectPath: projectPath,
-				RepoPath:    canonical,
-				WorkDir:     canonical,
-			}); err != nil {
-				// post-clone hooks are informational; log the failure but don't abort.
-				cmd.PrintErrf("post-clone hook failed (ignored): %v\n", err)
+				return nil
 			}
 
-			cmd.Printf("cloned to %s\n", canonical)
+			cmd.Printf("cloned to %s\n", res.Path)
 
 			return nil
 		},
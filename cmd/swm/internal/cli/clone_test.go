@@ -70,6 +70,22 @@ func TestCloneCmd_AlreadyCloned(t *testing.T) {
 	require.False(t, vcs.cloneCalled)
 }
 
+func TestCloneCmd_RoutesByScheme(t *testing.T) {
+	t.Parallel()
+
+	codeRoot := t.TempDir()
+	resolver := layout.NewResolver(codeRoot, "_default")
+	git, hg := &stubVCS{}, &stubVCS{}
+	mgr := &stubMgr{vcs: git, namedVCS: map[string]pluginv1.VCSClient{"hg": hg}, urlVCS: map[string]string{"hg+ssh": "hg"}}
+
+	cmd := cli.NewCloneCmd(mgr, resolver, hookexec.Noop)
+	cmd.SetArgs([]string{"hg+ssh://hg.example.com/kalbasit/swm"})
+
+	require.NoError(t, cmd.Execute())
+	require.True(t, hg.cloneCalled)
+	require.False(t, git.cloneCalled)
+}
+
 func TestCloneCmd_PreRunE_WarmsVCS(t *testing.T) {
 	t.Parallel()
 
@@ -142,12 +158,20 @@ func TestCloneCmd_ProgressWrittenToStderr(t *testing.T) {
 type stubMgr struct {
 	vcs  pluginv1.VCSClient
 	sess pluginv1.SessionClient
+	// namedVCS holds the non-default vcs plugins by name; urlVCS routes URL
+	// schemes to them.
+	namedVCS map[string]pluginv1.VCSClient
+	urlVCS   map[string]string
 }
 
 func (s *stubMgr) Close() error {
 	return nil
 }
 
+func (s *stubMgr) Discover(capability, _ string) (string, string, error) {
+	return "", "", fmt.Errorf("%w: %s", errNoPlugin, capability)
+}
+
 func (s *stubMgr) Get(_ context.Context, capability string) (any, error) {
 	switch capability {
 	case "vcs":
@@ -167,6 +191,32 @@ func (s *stubMgr) GetForge(_ context.Context, _ string) (pluginv1.ForgeClient, e
 	return nil, fmt.Errorf("%w: no forge configured", errNoPlugin)
 }
 
+func (s *stubMgr) GetVCS(_ context.Context, name string) (pluginv1.VCSClient, error) {
+	if vcs, ok := s.namedVCS[name]; ok {
+		return vcs, nil
+	}
+
+	if name == "" && s.vcs != nil {
+		return s.vcs, nil
+	}
+
+	return nil, fmt.Errorf("%w: vcs %q", errNoPlugin, name)
+}
+
+func (s *stubMgr) Inspect(_ context.Context, capability, _, _ string) (*pluginv1.PluginInfo, error) {
+	return nil, fmt.Errorf("%w: %s", errNoPlugin, capability)
+}
+
+func (s *stubMgr) VCSForPath(context.Context, string) (string, error) {
+	return "", nil
+}
+
+func (s *stubMgr) VCSForURL(_ context.Context, url string) (string, error) {
+	scheme, _, _ := strings.Cut(url, "://")
+
+	return s.urlVCS[scheme], nil
+}
+
 func (s *stubMgr) Warm(_ context.Context, _ ...string) error {
 	return nil
 }
@@ -244,12 +294,28 @@ func (s *stubVCS) DetectProjectAtPath(
 	panic("stub")
 }
 
+func (s *stubVCS) GetUserIdentity(
+	context.Context,
+	*pluginv1.UserIdentityRequest,
+	...grpc.CallOption,
+) (*pluginv1.UserIdentity, error) {
+	panic("stub")
+}
+
+func (s *stubVCS) GetWorktreeStatus(
+	context.Context,
+	*pluginv1.WorktreeStatusRequest,
+	...grpc.CallOption,
+) (*pluginv1.WorktreeStatus, error) {
+	panic("stub")
+}
+
 func (s *stubVCS) Info(
 	context.Context,
 	*pluginv1.Empty,
 	...grpc.CallOption,
 ) (*pluginv1.VCSInfo, error) {
-	panic("stub")
+	return &pluginv1.VCSInfo{ProjectMarkers: []string{".git"}}, nil
 }
 
 func (s *stubVCS) ListBranches(
@@ -276,6 +342,14 @@ func (s *stubVCS) RemoveWorktree(
 	panic("stub")
 }
 
+func (s *stubVCS) ValidateBranchName(
+	context.Context,
+	*pluginv1.ValidateBranchNameRequest,
+	...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	panic("stub")
+}
+
 var _ pluginv1.VCSClient = (*stubVCS)(nil)
 
 // stubSessionClient implements pluginv1.SessionClient for workspace tests.
@@ -283,6 +357,22 @@ type stubSessionClient struct {
 	openWorkspaceFn func(*pluginv1.OpenWorkspaceRequest) (*pluginv1.Workspace, error)
 }
 
+func (s *stubSessionClient) AttachCommand(
+	context.Context,
+	*pluginv1.AttachCommandRequest,
+	...grpc.CallOption,
+) (*pluginv1.AttachCommandResponse, error) {
+	panic("stub")
+}
+
+func (s *stubSessionClient) ClosePaneGroup(
+	context.Context,
+	*pluginv1.ClosePaneGroupRequest,
+	...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	panic("stub")
+}
+
 func (s *stubSessionClient) CloseWorkspace(
 	context.Context,
 	*pluginv1.CloseWorkspaceRequest,
@@ -315,6 +405,22 @@ func (s *stubSessionClient) IsInsideWorkspace(
 	panic("stub")
 }
 
+func (s *stubSessionClient) ListPaneGroups(
+	context.Context,
+	*pluginv1.ListPaneGroupsRequest,
+	...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.PaneGroup], error) {
+	panic("stub")
+}
+
+func (s *stubSessionClient) ListServices(
+	context.Context,
+	*pluginv1.ListServicesRequest,
+	...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.Service], error) {
+	panic("stub")
+}
+
 func (s *stubSessionClient) ListWorkspaces(
 	context.Context,
 	*pluginv1.Empty,
@@ -343,6 +449,38 @@ func (s *stubSessionClient) OpenWorkspace(
 	return &pluginv1.Workspace{WorkspaceId: "sock", StoryName: req.GetStoryName()}, nil
 }
 
+func (s *stubSessionClient) RestoreWorkspace(
+	context.Context,
+	*pluginv1.RestoreWorkspaceRequest,
+	...grpc.CallOption,
+) (*pluginv1.RestoreWorkspaceResponse, error) {
+	panic("stub")
+}
+
+func (s *stubSessionClient) SaveWorkspace(
+	context.Context,
+	*pluginv1.SaveWorkspaceRequest,
+	...grpc.CallOption,
+) (*pluginv1.SaveWorkspaceResponse, error) {
+	panic("stub")
+}
+
+func (s *stubSessionClient) StartService(
+	context.Context,
+	*pluginv1.StartServiceRequest,
+	...grpc.CallOption,
+) (*pluginv1.Service, error) {
+	panic("stub")
+}
+
+func (s *stubSessionClient) StopService(
+	context.Context,
+	*pluginv1.StopServiceRequest,
+	...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	panic("stub")
+}
+
 func (s *stubSessionClient) SwitchTo(
 	context.Context,
 	*pluginv1.SwitchToRequest,
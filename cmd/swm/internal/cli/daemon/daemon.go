@@ -0,0 +1,152 @@
+// Package daemon implements the `swm daemon` CLI subcommands.
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/daemon"
+)
+
+// stopWait bounds how long `swm daemon stop` waits for the daemon to exit.
+const stopWait = 10 * time.Second
+
+var errStillRunning = errors.New("swm daemon did not stop")
+
+// NewDaemonCmd builds the `swm daemon` command group. The daemon listens on
+// socketPath and loads the config at cfgPath.
+func NewDaemonCmd(cfgPath string, store coreStory.Store, socketPath string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Keep plugins running between invocations",
+		Long: "Run a daemon that keeps plugin processes and the repository scan warm between " +
+			"swm invocations. While it runs, swm forwards vcs, forge and tracker calls to it; " +
+			"session and picker plugins always run in the invoking swm.",
+	}
+
+	cmd.AddCommand(newRunCmd(cfgPath, store, socketPath))
+	cmd.AddCommand(newStatusCmd(socketPath))
+	cmd.AddCommand(newStopCmd(socketPath))
+
+	return cmd
+}
+
+func newRunCmd(cfgPath string, store coreStory.Store, socketPath string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "run",
+		Short: "Run the daemon in the foreground",
+		Long: "Run the daemon in the foreground until it is stopped, interrupted, or idle for " +
+			"daemon.idle_timeout. The daemon reloads its plugins when the config file changes.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			srv, err := daemon.New(cfgPath, cmd.Root().Version, store, daemon.WithSocketPath(socketPath))
+			if err != nil {
+				return fmt.Errorf("starting swm daemon: %w", err)
+			}
+
+			return srv.Run(ctx)
+		},
+	}
+}
+
+func newStatusCmd(socketPath string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the daemon runs and which plugins it keeps",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := daemon.Dial(cmd.Context(), socketPath)
+			if err != nil {
+				return err
+			}
+			defer client.Close() //nolint:errcheck // best-effort close on exit
+
+			st, err := client.Status(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			idle := "never"
+			if d := st.GetIdleTimeout().AsDuration(); d > 0 {
+				idle = "after " + d.String() + " idle"
+			}
+
+			plugins := make([]string, 0, len(st.GetPlugins()))
+			for _, p := range st.GetPlugins() {
+				plugins = append(plugins, fmt.Sprintf("%s:%s %s", p.GetCapability(), p.GetName(), p.GetVersion()))
+			}
+
+			if len(plugins) == 0 {
+				plugins = append(plugins, "-")
+			}
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0) //nolint:mnd // column padding
+
+			fmt.Fprintf(tw, "pid:\t%d\n", st.GetPid())
+			fmt.Fprintf(tw, "version:\t%s\n", st.GetVersion())
+			fmt.Fprintf(tw, "config:\t%s\n", st.GetConfigPath())
+			fmt.Fprintf(tw, "socket:\t%s\n", socketPath)
+			fmt.Fprintf(tw, "uptime:\t%s\n", time.Since(st.GetStartedAt().AsTime()).Round(time.Second))
+			fmt.Fprintf(tw, "stops:\t%s\n", idle)
+			fmt.Fprintf(tw, "plugins:\t%s\n", strings.Join(plugins, ", "))
+
+			if err := tw.Flush(); err != nil {
+				return fmt.Errorf("writing status: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newStopCmd(socketPath string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the daemon",
+		Long:  "Stop the daemon, and the plugins it runs. It is not an error when no daemon runs.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := daemon.Dial(cmd.Context(), socketPath)
+			if errors.Is(err, daemon.ErrNotRunning) {
+				cmd.Println("swm daemon is not running")
+
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+			defer client.Close() //nolint:errcheck // best-effort close on exit
+
+			if err := client.Stop(cmd.Context()); err != nil {
+				return err
+			}
+
+			// The daemon removes its socket once it has shut down.
+			for deadline := time.Now().Add(stopWait); time.Now().Before(deadline); {
+				if _, err := os.Stat(socketPath); errors.Is(err, os.ErrNotExist) {
+					cmd.Println("swm daemon stopped")
+
+					return nil
+				}
+
+				time.Sleep(50 * time.Millisecond) //nolint:mnd // poll interval
+			}
+
+			return fmt.Errorf("%w within %s", errStillRunning, stopWait)
+		},
+	}
+}
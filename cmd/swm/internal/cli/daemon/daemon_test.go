@@ -0,0 +1,87 @@
+package daemon_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	clidaemon "github.com/kalbasit/swm/cmd/swm/internal/cli/daemon"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	"github.com/kalbasit/swm/cmd/swm/internal/daemon"
+)
+
+// runDaemonCmd runs `swm daemon` with args and returns its output.
+func runDaemonCmd(ctx context.Context, t *testing.T, socketPath string, args ...string) (string, error) {
+	t.Helper()
+
+	cfgPath := filepath.Join(filepath.Dir(socketPath), "config.toml")
+
+	var out bytes.Buffer
+
+	cmd := clidaemon.NewDaemonCmd(cfgPath, story.NewJSONStore(t.TempDir()), socketPath)
+	cmd.SetArgs(args)
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+
+	err := cmd.ExecuteContext(ctx)
+
+	return out.String(), err
+}
+
+func TestDaemonCmd_NotRunning(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "daemon.sock")
+
+	_, err := runDaemonCmd(t.Context(), t, socketPath, "status")
+	require.ErrorIs(t, err, daemon.ErrNotRunning)
+
+	out, err := runDaemonCmd(t.Context(), t, socketPath, "stop")
+	require.NoError(t, err)
+	require.Contains(t, out, "swm daemon is not running")
+}
+
+func TestDaemonCmd_RunStatusStop(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "daemon.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := runDaemonCmd(ctx, t, socketPath, "run")
+		done <- err
+	}()
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(socketPath)
+
+		return err == nil
+	}, 10*time.Second, 20*time.Millisecond)
+
+	out, err := runDaemonCmd(t.Context(), t, socketPath, "status")
+	require.NoError(t, err)
+	require.Contains(t, out, "pid:")
+	require.Contains(t, out, "after 30m0s idle")
+	require.Contains(t, out, "plugins:  -")
+
+	out, err = runDaemonCmd(t.Context(), t, socketPath, "stop")
+	require.NoError(t, err)
+	require.Contains(t, out, "swm daemon stopped")
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "swm daemon run did not return")
+	}
+}
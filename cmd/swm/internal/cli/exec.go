@@ -0,0 +1,490 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/ports"
+)
+
+// Exec result statuses.
+const (
+	execStatusOK      = "ok"
+	execStatusFailed  = "failed"
+	execStatusSkipped = "skipped"
+)
+
+// execStopDelay is how long a cancelled command has to exit after SIGTERM
+// before it is killed.
+const execStopDelay = 5 * time.Second
+
+var (
+	errExecFailed         = errors.New("command failed")
+	errExecNoProjects     = errors.New("no attached project matches")
+	errExecInvalidProject = errors.New("invalid --project pattern")
+	errExecParallel       = errors.New("--parallel must be at least 1")
+)
+
+// execResult is the outcome of running the command in one project, as printed
+// by `swm exec --json`.
+type execResult struct {
+	Project      string `json:"project"`
+	WorktreePath string `json:"worktree_path"`
+	Status       string `json:"status"`
+	// ExitCode is the command's exit code, or -1 when it could not be started
+	// or was skipped.
+	ExitCode   int    `json:"exit_code"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+	// Stdout and Stderr are only captured with --json.
+	Stdout string `json:"stdout,omitempty"`
+	Stderr string `json:"stderr,omitempty"`
+}
+
+// execTarget is an attached project the command runs in.
+type execTarget struct {
+	key      string
+	project  coreStory.Project
+	worktree string
+	repo     string
+	// env is the story's environment with the project's overrides.
+	env []string
+}
+
+// execOptions are the flags of `swm exec`.
+type execOptions struct {
+	storyName string
+	parallel  int
+	projects  []string
+	group     bool
+	failFast  bool
+	jsonOut   bool
+}
+
+// NewExecCmd returns the `swm exec` command.
+func NewExecCmd(cfg *config.Config, store coreStory.Store, resolver *layout.Resolver) *cobra.Command {
+	var opts execOptions
+
+	cmd := &cobra.Command{
+		Use:   "exec [flags] -- <command> [args...]",
+		Short: "Run a command in every worktree of a story",
+		Long: "Run a command in the worktree of every project attached to a story. The story is " +
+			"taken from --story, $SWM_STORY, the working directory or the default story. The " +
+			"command runs with the story's environment (see `swm story env`) and SWM_STORY, " +
+			"SWM_PROJECT_HOST, SWM_PROJECT_PATH, SWM_WORKTREE_PATH and SWM_REPO_PATH set, as " +
+			"hooks get them; use `sh -c` for pipelines.\n\n" +
+			"Output lines are prefixed with the project, or grouped per project with --group, and " +
+			"a summary of exit codes is printed at the end. The command fails when any project " +
+			"fails. With --json, output is captured and a single JSON document is printed instead.",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			if opts.parallel < 1 {
+				return errExecParallel
+			}
+
+			storyName, err := execStoryName(cfg, resolver, opts.storyName)
+			if err != nil {
+				return err
+			}
+
+			s, err := store.Get(ctx, storyName)
+			if err != nil {
+				return fmt.Errorf("loading story %q: %w", storyName, err)
+			}
+
+			targets, err := execTargets(cfg, resolver, s, opts.projects)
+			if err != nil {
+				return err
+			}
+
+			results := runExec(ctx, cmd.OutOrStdout(), cmd.ErrOrStderr(), s.Name, targets, args, opts)
+
+			if opts.jsonOut {
+				if err := printExecJSON(cmd.OutOrStdout(), s.Name, results); err != nil {
+					return err
+				}
+			} else if err := printExecSummary(cmd.ErrOrStderr(), results); err != nil {
+				return err
+			}
+
+			var failed int
+
+			for _, r := range results {
+				if r.Status != execStatusOK {
+					failed++
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%w in %d of %d projects", errExecFailed, failed, len(results))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.storyName, "story", "s", "", "story name (default: detected)")
+	cmd.Flags().IntVarP(&opts.parallel, "parallel", "p", 1, "number of projects to run concurrently")
+	cmd.Flags().StringArrayVar(&opts.projects, "project", nil,
+		"only run in projects matching this glob (host/owner/repo or owner/repo); repeatable")
+	cmd.Flags().BoolVar(&opts.group, "group", false,
+		"print each project's output in one block once it finishes instead of prefixing lines")
+	cmd.Flags().BoolVar(&opts.failFast, "fail-fast", false,
+		"stop starting new commands and cancel running ones after the first failure")
+	cmd.Flags().BoolVar(&opts.jsonOut, "json", false, "capture output and print the results as JSON")
+
+	//nolint:errcheck,gosec // RegisterFlagCompletionFunc only fails for unknown flags
+	cmd.RegisterFlagCompletionFunc("story", func(
+		cmd *cobra.Command, _ []string, _ string,
+	) ([]string, cobra.ShellCompDirective) {
+		stories, err := store.List(cmd.Context())
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		names := make([]string, 0, len(stories))
+		for _, s := range stories {
+			names = append(names, s.Name)
+		}
+
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// execStoryName returns flagValue, $SWM_STORY, the story of the working
+// directory or the default story, in that order.
+func execStoryName(cfg *config.Config, resolver *layout.Resolver, flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+
+	if name := os.Getenv("SWM_STORY"); name != "" {
+		return name, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting working directory: %w", err)
+	}
+
+	if name := resolver.StoryNameFromPath(cwd); name != "" {
+		return name, nil
+	}
+
+	return cfg.DefaultStory, nil
+}
+
+// execTargets returns the projects of s matching any of patterns (all
+// projects when patterns is empty), in attach order.
+func execTargets(
+	cfg *config.Config,
+	resolver *layout.Resolver,
+	s *coreStory.Story,
+	patterns []string,
+) ([]execTarget, error) {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("%w %q: %w", errExecInvalidProject, p, err)
+		}
+	}
+
+	var targets []execTarget
+
+	for _, p := range s.Projects {
+		projectPath := strings.Join(p.Segments, "/")
+		key := p.Host + "/" + projectPath
+
+		if !matchesAny(patterns, key, projectPath) {
+			continue
+		}
+
+		env, err := ports.StoryEnvironment(resolver, s, key, cfg.HistfilePath(s.Name), cfg.Ports.BlockSize)
+		if err != nil {
+			return nil, fmt.Errorf("reading ports of %s: %w", key, err)
+		}
+
+		id := &pluginv1.ProjectID{Host: p.Host, Segments: p.Segments}
+		targets = append(targets, execTarget{
+			key:      key,
+			project:  p,
+			worktree: resolver.WorktreePath(s.Name, id),
+			repo:     resolver.CanonicalPath(id),
+			env:      coreStory.Environ(env),
+		})
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("%w in story %q", errExecNoProjects, s.Name)
+	}
+
+	return targets, nil
+}
+
+// matchesAny reports whether key or projectPath matches one of patterns. An
+// empty pattern list matches everything.
+func matchesAny(patterns []string, key, projectPath string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, p := range patterns {
+		// Patterns are validated by execTargets, so Match cannot fail here.
+		if ok, _ := path.Match(p, key); ok { //nolint:errcheck // see above
+			return true
+		}
+
+		if ok, _ := path.Match(p, projectPath); ok { //nolint:errcheck // see above
+			return true
+		}
+	}
+
+	return false
+}
+
+// runExec runs argv in every target, at most opts.parallel at a time, and
+// returns one result per target in target order.
+func runExec(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	storyName string,
+	targets []execTarget,
+	argv []string,
+	opts execOptions,
+) []execResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]execResult, len(targets))
+
+	var (
+		mu  sync.Mutex // serialises writes to stdout and stderr
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, opts.parallel)
+	)
+
+	for i, t := range targets {
+		sem <- struct{}{}
+
+		if ctx.Err() != nil {
+			<-sem
+
+			results[i] = execResult{
+				Project: t.key, WorktreePath: t.worktree, Status: execStatusSkipped, ExitCode: -1,
+			}
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i] = runExecTarget(ctx, &mu, stdout, stderr, storyName, t, argv, opts)
+
+			if opts.failFast && results[i].Status != execStatusOK {
+				cancel()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// runExecTarget runs argv in t's worktree. Output is captured with --json,
+// buffered with --group, and otherwise streamed line by line with a project
+// prefix; mu guards stdout and stderr.
+func runExecTarget(
+	ctx context.Context,
+	mu *sync.Mutex,
+	stdout, stderr io.Writer,
+	storyName string,
+	t execTarget,
+	argv []string,
+	opts execOptions,
+) execResult {
+	res := execResult{Project: t.key, WorktreePath: t.worktree}
+
+	c := exec.CommandContext(ctx, argv[0], argv[1:]...) //nolint:gosec // running the user's command is the point
+	c.Dir = t.worktree
+	// Ask the command to stop when swm is interrupted or --fail-fast gives
+	// up, and kill it only when it does not.
+	c.Cancel = func() error { return c.Process.Signal(syscall.SIGTERM) }
+	c.WaitDelay = execStopDelay
+	c.Env = append(
+		append(os.Environ(), t.env...),
+		"SWM_STORY="+storyName,
+		"SWM_PROJECT_HOST="+t.project.Host,
+		"SWM_PROJECT_PATH="+strings.Join(t.project.Segments, "/"),
+		"SWM_WORKTREE_PATH="+t.worktree,
+		"SWM_REPO_PATH="+t.repo,
+	)
+
+	var (
+		outBuf, errBuf bytes.Buffer
+		outPW, errPW   *prefixWriter
+	)
+
+	switch {
+	case opts.jsonOut:
+		c.Stdout, c.Stderr = &outBuf, &errBuf
+	case opts.group:
+		// Both streams share one buffer so the block keeps their interleaving.
+		c.Stdout, c.Stderr = &outBuf, &outBuf
+	default:
+		outPW = &prefixWriter{mu: mu, w: stdout, prefix: "[" + t.key + "] "}
+		errPW = &prefixWriter{mu: mu, w: stderr, prefix: "[" + t.key + "] "}
+		c.Stdout, c.Stderr = outPW, errPW
+	}
+
+	start := time.Now()
+	err := c.Run()
+	res.DurationMS = time.Since(start).Milliseconds()
+
+	if outPW != nil {
+		outPW.flush()
+		errPW.flush()
+	}
+
+	switch {
+	case opts.jsonOut:
+		res.Stdout, res.Stderr = outBuf.String(), errBuf.String()
+	case opts.group:
+		writeGroup(mu, stdout, t.key, outBuf.Bytes())
+	}
+
+	res.Status, res.ExitCode = execStatusOK, 0
+
+	if err != nil {
+		res.Status, res.ExitCode, res.Error = execStatusFailed, -1, err.Error()
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			res.ExitCode = exitErr.ExitCode()
+		}
+	}
+
+	return res
+}
+
+// writeGroup writes the --group block of output for project key to w.
+func writeGroup(mu *sync.Mutex, w io.Writer, key string, out []byte) {
+	if len(out) > 0 && !bytes.HasSuffix(out, []byte("\n")) {
+		out = append(out, '\n')
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	fmt.Fprintf(w, "==> %s <==\n%s", key, out)
+}
+
+// printExecSummary writes a table of each project's status, exit code and
+// duration to w.
+func printExecSummary(w io.Writer, results []execResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:mnd // column padding
+
+	fmt.Fprintln(tw, "PROJECT\tSTATUS\tEXIT\tDURATION")
+
+	for _, r := range results {
+		exit := "-"
+		if r.Status != execStatusSkipped {
+			exit = strconv.Itoa(r.ExitCode)
+		}
+
+		duration := (time.Duration(r.DurationMS) * time.Millisecond).String()
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Project, r.Status, exit, duration)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("writing exec summary: %w", err)
+	}
+
+	return nil
+}
+
+// printExecJSON writes the results of `swm exec --json` to w.
+func printExecJSON(w io.Writer, storyName string, results []execResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	doc := struct {
+		Story   string       `json:"story"`
+		Results []execResult `json:"results"`
+	}{Story: storyName, Results: results}
+
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encoding exec results: %w", err)
+	}
+
+	return nil
+}
+
+// prefixWriter writes complete lines to w, each preceded by prefix, holding
+// mu per line so concurrent projects do not interleave mid-line.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+// Write buffers p and writes every complete line it contains.
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			return len(b), nil
+		}
+
+		p.writeLine(p.buf[:i+1])
+		p.buf = p.buf[i+1:]
+	}
+}
+
+// flush writes a trailing partial line, terminated with a newline.
+func (p *prefixWriter) flush() {
+	if len(p.buf) == 0 {
+		return
+	}
+
+	p.writeLine(append(p.buf, '\n'))
+	p.buf = nil
+}
+
+func (p *prefixWriter) writeLine(line []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fmt.Fprint(p.w, p.prefix+string(line))
+}
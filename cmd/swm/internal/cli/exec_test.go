@@ -0,0 +1,215 @@
+package cli_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli"
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+)
+
+const testExecStory = "feat-x"
+
+// execFixtureDeps holds the dependencies of a `swm exec` command under test.
+type execFixtureDeps struct {
+	Cfg      *config.Config
+	Store    coreStory.Store
+	Resolver *layout.Resolver
+}
+
+// execFixture creates a story attached to repos (owner/repo on github.com)
+// with a worktree directory for each, and returns a configured exec command.
+func execFixture(t *testing.T, repos ...string) (*execFixtureDeps, string) {
+	t.Helper()
+
+	codeRoot := t.TempDir()
+	store := coreStory.NewJSONStore(t.TempDir())
+
+	s, err := store.Create(t.Context(), testExecStory, "feat/"+testExecStory)
+	require.NoError(t, err)
+
+	for _, r := range repos {
+		segments := strings.Split(r, "/")
+		s.Projects = append(s.Projects, coreStory.Project{Host: testGitHubHost, Segments: segments})
+
+		wt := filepath.Join(append([]string{codeRoot, "stories", testExecStory, testGitHubHost}, segments...)...)
+		require.NoError(t, os.MkdirAll(wt, 0o750))
+	}
+
+	require.NoError(t, store.Update(t.Context(), s))
+
+	cfg := &config.Config{CodeRoot: codeRoot, DefaultStory: "_default"}
+
+	return &execFixtureDeps{
+		Cfg:      cfg,
+		Store:    store,
+		Resolver: layout.NewResolver(codeRoot, "_default"),
+	}, codeRoot
+}
+
+func runExecCmd(t *testing.T, f *execFixtureDeps, args ...string) (string, string, error) {
+	t.Helper()
+
+	var stdout, stderr strings.Builder
+
+	cmd := cli.NewExecCmd(f.Cfg, f.Store, f.Resolver)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs(append([]string{"--story", testExecStory}, args...))
+
+	err := cmd.Execute()
+
+	return stdout.String(), stderr.String(), err
+}
+
+func TestExecCmd_RunsInEveryWorktreeWithEnv(t *testing.T) {
+	t.Parallel()
+
+	f, codeRoot := execFixture(t, "kalbasit/swm", "kalbasit/dotfiles")
+
+	stdout, stderr, err := runExecCmd(t, f, "--",
+		"sh", "-c", `echo "$SWM_STORY $SWM_PROJECT_HOST/$SWM_PROJECT_PATH $(pwd) $SWM_REPO_PATH"`)
+	require.NoError(t, err)
+
+	for _, repo := range []string{"kalbasit/swm", "kalbasit/dotfiles"} {
+		wt := filepath.Join(codeRoot, "stories", testExecStory, testGitHubHost, repo)
+		canonical := filepath.Join(codeRoot, "repositories", testGitHubHost, repo)
+		require.Contains(t, stdout,
+			"[github.com/"+repo+"] "+testExecStory+" github.com/"+repo+" "+wt+" "+canonical+"\n")
+	}
+
+	require.Contains(t, stderr, "PROJECT")
+	require.Contains(t, stderr, "github.com/kalbasit/dotfiles")
+}
+
+func TestExecCmd_StoryEnv(t *testing.T) {
+	t.Parallel()
+
+	f, _ := execFixture(t, "kalbasit/swm", "kalbasit/dotfiles")
+
+	s, err := f.Store.Get(t.Context(), testExecStory)
+	require.NoError(t, err)
+
+	s.Env = map[string]string{"AWS_PROFILE": "dev", "PORT": "3000"}
+	s.ProjectEnv = map[string]map[string]string{"github.com/kalbasit/swm": {"PORT": "3001"}}
+	require.NoError(t, f.Store.Update(t.Context(), s))
+
+	f.Cfg.Story.Histfile = true
+	f.Cfg.DataHome = "/data"
+
+	stdout, _, err := runExecCmd(t, f, "--", "sh", "-c", `echo "$AWS_PROFILE $PORT $HISTFILE"`)
+	require.NoError(t, err)
+
+	histfile := filepath.Join("/data", "swm", "stories", testExecStory, "history")
+	require.Contains(t, stdout, "[github.com/kalbasit/swm] dev 3001 "+histfile+"\n")
+	require.Contains(t, stdout, "[github.com/kalbasit/dotfiles] dev 3000 "+histfile+"\n")
+}
+
+func TestExecCmd_StoryPorts(t *testing.T) {
+	t.Parallel()
+
+	f, codeRoot := execFixture(t, "kalbasit/swm", "kalbasit/dotfiles")
+
+	s, err := f.Store.Get(t.Context(), testExecStory)
+	require.NoError(t, err)
+
+	s.PortBase = 20300
+	require.NoError(t, f.Store.Update(t.Context(), s))
+
+	f.Cfg.Ports = config.Ports{Base: 20000, BlockSize: 100}
+
+	project := filepath.Join(codeRoot, "stories", testExecStory, testGitHubHost, "kalbasit", "swm", ".swm")
+	require.NoError(t, os.MkdirAll(project, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "project.toml"), []byte("[ports]\napi = 5\n"), 0o600))
+
+	stdout, _, err := runExecCmd(t, f, "--", "sh", "-c", `echo "$SWM_PORT_BASE ${SWM_PORT_API:-none}"`)
+	require.NoError(t, err)
+
+	require.Contains(t, stdout, "[github.com/kalbasit/swm] 20300 20305\n")
+	require.Contains(t, stdout, "[github.com/kalbasit/dotfiles] 20300 none\n")
+}
+
+func TestExecCmd_FailureFailsCommand(t *testing.T) {
+	t.Parallel()
+
+	f, _ := execFixture(t, "kalbasit/swm", "kalbasit/dotfiles")
+
+	stdout, _, err := runExecCmd(t, f, "--json", "--",
+		"sh", "-c", `echo out; echo err >&2; [ "$SWM_PROJECT_PATH" = kalbasit/swm ] || exit 3`)
+	require.ErrorContains(t, err, "command failed in 1 of 2 projects")
+
+	var got struct {
+		Story   string `json:"story"`
+		Results []struct {
+			Project  string `json:"project"`
+			Status   string `json:"status"`
+			ExitCode int    `json:"exit_code"`
+			Stdout   string `json:"stdout"`
+			Stderr   string `json:"stderr"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &got), stdout)
+
+	require.Equal(t, testExecStory, got.Story)
+	require.Len(t, got.Results, 2)
+	require.Equal(t, "github.com/kalbasit/swm", got.Results[0].Project)
+	require.Equal(t, "ok", got.Results[0].Status)
+	require.Equal(t, "out\n", got.Results[0].Stdout)
+	require.Equal(t, "err\n", got.Results[0].Stderr)
+	require.Equal(t, "failed", got.Results[1].Status)
+	require.Equal(t, 3, got.Results[1].ExitCode)
+}
+
+func TestExecCmd_ProjectFilter(t *testing.T) {
+	t.Parallel()
+
+	f, _ := execFixture(t, "kalbasit/swm", "kalbasit/dotfiles", "other/tool")
+
+	stdout, _, err := runExecCmd(t, f, "--project", "kalbasit/*", "--project", "github.com/other/tool",
+		"--", "sh", "-c", "echo hi")
+	require.NoError(t, err)
+	require.Equal(t, 3, strings.Count(stdout, "hi\n"))
+
+	stdout, _, err = runExecCmd(t, f, "--project", "*/dot*", "--", "sh", "-c", "echo hi")
+	require.NoError(t, err)
+	require.Equal(t, "[github.com/kalbasit/dotfiles] hi\n", stdout)
+
+	_, _, err = runExecCmd(t, f, "--project", "nomatch", "--", "true")
+	require.ErrorContains(t, err, "no attached project matches")
+}
+
+func TestExecCmd_FailFastSkipsRemaining(t *testing.T) {
+	t.Parallel()
+
+	f, _ := execFixture(t, "kalbasit/swm", "kalbasit/dotfiles", "other/tool")
+
+	_, stderr, err := runExecCmd(t, f, "--fail-fast", "--", "false")
+	require.ErrorContains(t, err, "command failed in 3 of 3 projects")
+	require.Equal(t, 1, strings.Count(stderr, "failed"))
+	require.Equal(t, 2, strings.Count(stderr, "skipped"))
+}
+
+func TestExecCmd_GroupAndParallel(t *testing.T) {
+	t.Parallel()
+
+	f, _ := execFixture(t, "kalbasit/swm", "kalbasit/dotfiles")
+
+	stdout, _, err := runExecCmd(t, f, "--group", "--parallel", "2", "--",
+		"sh", "-c", "echo one; echo two")
+	require.NoError(t, err)
+	require.Contains(t, stdout, "==> github.com/kalbasit/swm <==\none\ntwo\n")
+	require.Contains(t, stdout, "==> github.com/kalbasit/dotfiles <==\none\ntwo\n")
+
+	_, _, err = runExecCmd(t, f, "--parallel", "0", "--", "true")
+	require.ErrorContains(t, err, "--parallel must be at least 1")
+}
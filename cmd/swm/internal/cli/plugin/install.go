@@ -0,0 +1,50 @@
+package plugin
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+)
+
+// NewInstallCmd builds the `swm plugin install` command.
+func NewInstallCmd(cfg *config.Config) *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "install <git-url|path>",
+		Short: "Install a plugin from a git repository or a local directory",
+		Long: "Install a plugin from a git URL (github.com/foo/bar, https://..., git@...), a " +
+			"local bare repository or a local directory, which is copied. The plugin's " +
+			"swm-plugin.toml declares its name, capability and build command; the build " +
+			"command is shown and must be confirmed before it runs, unless --yes is given.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			installer := newInstaller(cmd, cfg)
+
+			staged, err := installer.Fetch(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			if !confirmBuild(cmd, staged, yes) {
+				cmd.Println("aborted")
+
+				return staged.Cleanup()
+			}
+
+			p, err := installer.Install(ctx, staged, nil)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Installed %s as %s\n", p.ShortName(), p.BinaryPath())
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "run the build command without asking")
+
+	return cmd
+}
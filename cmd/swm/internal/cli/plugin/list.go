@@ -0,0 +1,163 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/plugininstall"
+	"github.com/kalbasit/swm/cmd/swm/internal/pluginmgr"
+	"github.com/kalbasit/swm/cmd/swm/internal/sandbox"
+)
+
+// sandboxUnavailable is the SANDBOX column of a plugin configured to run
+// sandboxed on a system that cannot sandbox it; swm refuses to launch it.
+const sandboxUnavailable = "unavailable"
+
+// listedPlugin is a row of `swm plugin list`: an installed or a configured
+// plugin.
+type listedPlugin struct {
+	capability string
+	name       string
+	version    string // from the install manifest
+	source     string
+}
+
+// NewListCmd builds the `swm plugin list` command.
+func NewListCmd(cfg *config.Config, inspector Inspector) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List installed and configured plugins",
+		Long: "List installed plugins, and the configured ones installed otherwise, with the " +
+			"name, version, provided capabilities and requirements they report, whether they " +
+			"run sandboxed, the binary swm runs and the discovery tier it was found in " +
+			"(SWM_PLUGIN_PATH, config, xdg, PATH, or bundled for the plugins built into swm), and " +
+			"the source they were installed from.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			installed, err := newInstaller(cmd, cfg).List()
+			if err != nil {
+				return err
+			}
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0) //nolint:mnd // column padding
+
+			sandboxErr := sandbox.Available()
+
+			fmt.Fprintln(tw, "NAME\tCAPABILITY\tVERSION\tPROVIDES\tREQUIRES\tSANDBOX\tTIER\tBINARY\tSOURCE")
+
+			for _, p := range listedPlugins(cfg, installed) {
+				fullName := p.capability + "-" + p.name
+				version, provides, requires := p.version, "-", "-"
+
+				binary, tier, err := inspector.Discover(p.capability, p.name)
+				if err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "warning: plugin %s: %v\n", fullName, err)
+				} else if info, err := inspector.Inspect(cmd.Context(), p.capability, p.name, binary); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "warning: plugin %s: %v\n", fullName, err)
+				} else {
+					version = info.GetVersion()
+					provides = formatProvides(info)
+					requires = formatRequires(info)
+				}
+
+				sandboxed := sandboxStatus(cfg, fullName, sandboxErr)
+				if sandboxed == sandboxUnavailable {
+					fmt.Fprintf(cmd.ErrOrStderr(), "warning: plugin %s: %v\n", fullName, sandboxErr)
+				}
+
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					p.name, p.capability, orDash(version), provides, requires, sandboxed,
+					orDash(tier), orDash(binary), orDash(p.source))
+			}
+
+			if err := tw.Flush(); err != nil {
+				return fmt.Errorf("writing plugins: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// formatProvides renders the capabilities a plugin provides.
+func formatProvides(info *pluginv1.PluginInfo) string {
+	names := make([]string, 0, len(info.GetProvides()))
+	for _, c := range info.GetProvides() {
+		names = append(names, pluginmgr.CapabilityName(c.GetType()))
+	}
+
+	return orDash(strings.Join(names, ","))
+}
+
+// formatRequires renders the capabilities a plugin requires, with their
+// minimum versions.
+func formatRequires(info *pluginv1.PluginInfo) string {
+	deps := make([]string, 0, len(info.GetRequires()))
+
+	for _, d := range info.GetRequires() {
+		dep := pluginmgr.CapabilityName(d.GetCapability())
+		if d.GetMinVersion() != "" {
+			dep += ">=" + d.GetMinVersion()
+		}
+
+		deps = append(deps, dep)
+	}
+
+	return orDash(strings.Join(deps, ","))
+}
+
+// sandboxStatus renders whether the plugin fullName runs sandboxed, given
+// the error sandbox.Available reported.
+func sandboxStatus(cfg *config.Config, fullName string, sandboxErr error) string {
+	if _, ok := cfg.Plugins.Sandbox[fullName]; !ok {
+		return "-"
+	}
+
+	if sandboxErr != nil {
+		return sandboxUnavailable
+	}
+
+	return "landlock"
+}
+
+// listedPlugins returns the installed plugins, followed by the configured
+// plugins that are not installed.
+func listedPlugins(cfg *config.Config, installed []*plugininstall.Plugin) []listedPlugin {
+	listed := make([]listedPlugin, 0, len(installed))
+	seen := make(map[string]bool, len(installed))
+
+	for _, p := range installed {
+		listed = append(listed, listedPlugin{
+			capability: p.Plugin.Capability,
+			name:       p.ShortName(),
+			version:    p.Plugin.Version,
+			source:     p.Source,
+		})
+		seen[p.Plugin.Capability+"-"+p.ShortName()] = true
+	}
+
+	for _, fullName := range cfg.Plugins.Configured() {
+		capability, name, _ := strings.Cut(fullName, "-")
+		if !seen[fullName] {
+			listed = append(listed, listedPlugin{capability: capability, name: name})
+			seen[fullName] = true
+		}
+	}
+
+	return listed
+}
+
+// orDash returns s, or "-" when s is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -0,0 +1,83 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/plugininstall"
+)
+
+var (
+	errPluginName = errors.New("plugin must be named with its capability, e.g. vcs-git")
+	errPinBundled = errors.New("plugin is built into swm; install its binary to pin it")
+)
+
+// NewPinCmd builds the `swm plugin pin` command, which records checksums in
+// the config file at cfgPath.
+func NewPinCmd(cfgPath string, cfg *config.Config, inspector Inspector) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pin [<name>...]",
+		Short: "Pin plugin binaries by checksum",
+		Long: "Record the SHA-256 of the binary swm finds for each plugin in the " +
+			"[plugins.checksums] table of config.toml. swm then refuses to launch a plugin " +
+			"whose binary changed, such as one shadowed by another binary earlier on PATH. " +
+			"A plugin is named with its capability (vcs-git). Without names, every " +
+			"configured plugin is pinned; pin again after upgrading a plugin.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names := args
+			if len(names) == 0 {
+				names = cfg.Plugins.Configured()
+			}
+
+			if len(names) == 0 {
+				cmd.Println("No plugins configured")
+
+				return nil
+			}
+
+			fileCfg, err := config.LoadForWrite(cfgPath)
+			if err != nil {
+				return fmt.Errorf("loading config: %w", err)
+			}
+
+			if fileCfg.Plugins.Checksums == nil {
+				fileCfg.Plugins.Checksums = make(map[string]string, len(names))
+			}
+
+			for _, name := range names {
+				capability, short, ok := strings.Cut(name, "-")
+				if !ok {
+					return fmt.Errorf("%w: %q", errPluginName, name)
+				}
+
+				binary, tier, err := inspector.Discover(capability, short)
+				if err != nil {
+					return err
+				}
+
+				if binary == "" {
+					return fmt.Errorf("%w: %s", errPinBundled, name)
+				}
+
+				sum, err := plugininstall.Checksum(binary)
+				if err != nil {
+					return err
+				}
+
+				fileCfg.Plugins.Checksums[name] = sum
+
+				cmd.Printf("Pinned %s to %s (%s, sha256 %s)\n", name, binary, tier, sum)
+			}
+
+			if err := config.Save(cfgPath, fileCfg); err != nil {
+				return fmt.Errorf("saving config: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
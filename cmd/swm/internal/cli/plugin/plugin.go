@@ -0,0 +1,97 @@
+// Package plugin implements the `swm plugin` CLI subcommands.
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/cobra"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/plugininstall"
+)
+
+// Inspector finds plugin binaries and reads their Info().
+type Inspector interface {
+	// Discover returns the binary swm runs for the named plugin of
+	// capability, and the discovery tier it was found in.
+	Discover(capability, name string) (string, string, error)
+	// Inspect returns the Info() of binary, the named plugin of capability;
+	// an empty binary is the plugin built into swm.
+	Inspect(ctx context.Context, capability, name, binary string) (*pluginv1.PluginInfo, error)
+}
+
+// NewPluginCmd builds the `swm plugin` command group. Pins are recorded in
+// the config file at cfgPath.
+func NewPluginCmd(cfgPath string, cfg *config.Config, inspector Inspector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Install and manage plugins",
+		Long: "Install and manage plugins built from source. Installed plugins live in " +
+			"$XDG_DATA_HOME/swm/plugins/<name>/, where swm finds them, and their binaries " +
+			"are linked into $XDG_DATA_HOME/swm/bin/.",
+	}
+
+	cmd.AddCommand(NewInstallCmd(cfg))
+	cmd.AddCommand(NewListCmd(cfg, inspector))
+	cmd.AddCommand(NewPinCmd(cfgPath, cfg, inspector))
+	cmd.AddCommand(NewRemoveCmd(cfg))
+	cmd.AddCommand(NewUpgradeCmd(cfg))
+
+	return cmd
+}
+
+// confirmBuild shows the build command of staged and asks whether to run it.
+// It returns true without asking when yes is set.
+func confirmBuild(cmd *cobra.Command, staged *plugininstall.Staged, yes bool) bool {
+	cmd.Printf("Plugin %s %s (%s) builds with:\n  %s\n",
+		staged.Plugin.Capability+"-"+staged.ShortName(), staged.Plugin.Version, staged.Source,
+		quoteArgs(staged.BuildCommand()))
+
+	if yes {
+		return true
+	}
+
+	cmd.Printf("Run it? [y/N]: ")
+
+	var resp string
+
+	if _, err := fmt.Fscan(cmd.InOrStdin(), &resp); err != nil {
+		return false
+	}
+
+	resp = strings.ToLower(strings.TrimSpace(resp))
+
+	return resp == "y" || resp == "yes"
+}
+
+// newInstaller returns the installer of cfg's data home, writing build output
+// to the command's stderr.
+func newInstaller(cmd *cobra.Command, cfg *config.Config) *plugininstall.Installer {
+	dataHome := cfg.DataHome
+	if dataHome == "" {
+		dataHome = xdg.DataHome
+	}
+
+	return plugininstall.New(dataHome, cmd.ErrOrStderr(), cmd.ErrOrStderr())
+}
+
+// quoteArgs renders argv as a shell command line.
+func quoteArgs(argv []string) string {
+	parts := make([]string, len(argv))
+
+	for i, arg := range argv {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$`*?;&|<>()") {
+			arg = strconv.Quote(arg)
+		}
+
+		parts[i] = arg
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,250 @@
+package plugin_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/plugin"
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/sandbox"
+)
+
+const testBinary = "swm-plugin-vcs-demo"
+
+var (
+	errNoInfo   = errors.New("no info")
+	errNotFound = errors.New("not found")
+)
+
+// stubInspector finds the binaries in found, keyed by plugin name, and
+// returns info for every binary, or err when set.
+type stubInspector struct {
+	found    map[string]string
+	info     *pluginv1.PluginInfo
+	err      error
+	binaries []string
+}
+
+func (s *stubInspector) Discover(capability, name string) (string, string, error) {
+	binary, ok := s.found[capability+"-"+name]
+	if !ok {
+		return "", "", errNotFound
+	}
+
+	return binary, "xdg", nil
+}
+
+func (s *stubInspector) Inspect(_ context.Context, _, _, binary string) (*pluginv1.PluginInfo, error) {
+	s.binaries = append(s.binaries, binary)
+
+	return s.info, s.err
+}
+
+// writePluginSource writes a vcs plugin named demo whose build command
+// creates an empty binary.
+func writePluginSource(t *testing.T, version string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	manifest := "[plugin]\nname = \"demo\"\ncapability = \"vcs\"\nversion = \"" + version + "\"\n\n" +
+		"[build]\ncommand = [\"touch\", \"" + testBinary + "\"]\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "swm-plugin.toml"), []byte(manifest), 0o600))
+
+	return dir
+}
+
+// runPluginCmd runs `swm plugin` with args and stdin and returns its stdout.
+// Its config file is config.toml in cfg's data home.
+func runPluginCmd(
+	t *testing.T,
+	cfg *config.Config,
+	inspector plugin.Inspector,
+	stdin string,
+	args ...string,
+) (string, error) {
+	t.Helper()
+
+	cmd := plugin.NewPluginCmd(filepath.Join(cfg.DataHome, "config.toml"), cfg, inspector)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	var out bytes.Buffer
+
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+func TestInstallCmd_ConfirmsBuild(t *testing.T) {
+	t.Parallel()
+
+	src := writePluginSource(t, "0.1.0")
+	cfg := &config.Config{DataHome: t.TempDir()}
+	binary := filepath.Join(cfg.DataHome, "swm", "plugins", "demo", testBinary)
+
+	out, err := runPluginCmd(t, cfg, &stubInspector{}, "n\n", "install", src)
+	require.NoError(t, err)
+	require.Contains(t, out, "Plugin vcs-demo 0.1.0 ("+src+") builds with:\n  touch "+testBinary+"\n")
+	require.Contains(t, out, "aborted")
+	require.NoFileExists(t, binary)
+
+	entries, err := os.ReadDir(filepath.Join(cfg.DataHome, "swm", "plugins"))
+	require.NoError(t, err)
+	require.Empty(t, entries, "an aborted install leaves nothing behind")
+
+	out, err = runPluginCmd(t, cfg, &stubInspector{}, "y\n", "install", src)
+	require.NoError(t, err)
+	require.Contains(t, out, "Installed demo as "+binary)
+	require.FileExists(t, binary)
+
+	_, err = runPluginCmd(t, cfg, &stubInspector{}, "", "install", "--yes", src)
+	require.ErrorContains(t, err, "plugin already installed: demo")
+}
+
+func TestListCmd_ShowsPluginInfo(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{DataHome: t.TempDir()}
+	src := writePluginSource(t, "0.1.0")
+
+	_, err := runPluginCmd(t, cfg, &stubInspector{}, "", "install", "-y", src)
+	require.NoError(t, err)
+
+	binary := filepath.Join(cfg.DataHome, "swm", "plugins", "demo", testBinary)
+	inspector := &stubInspector{
+		found: map[string]string{"vcs-demo": binary},
+		info: &pluginv1.PluginInfo{
+			Name:     "demo",
+			Version:  "0.1.1",
+			Provides: []*pluginv1.Capability{{Type: pluginv1.CapabilityType_CAPABILITY_TYPE_VCS}},
+			Requires: []*pluginv1.CapabilityDep{
+				{Capability: pluginv1.CapabilityType_CAPABILITY_TYPE_SESSION, MinVersion: "1.0.0"},
+			},
+		},
+	}
+
+	out, err := runPluginCmd(t, cfg, inspector, "", "list")
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t,
+		[]string{"NAME", "CAPABILITY", "VERSION", "PROVIDES", "REQUIRES", "SANDBOX", "TIER", "BINARY", "SOURCE"},
+		strings.Fields(lines[0]))
+	require.Equal(t, []string{"demo", "vcs", "0.1.1", "vcs", "session>=1.0.0", "-", "xdg", binary, src},
+		strings.Fields(lines[1]))
+	require.Equal(t, []string{binary}, inspector.binaries)
+
+	// A plugin that cannot be found is listed with its manifest version.
+	out, err = runPluginCmd(t, cfg, &stubInspector{err: errNoInfo}, "", "list")
+	require.NoError(t, err)
+	require.Equal(t, []string{"demo", "vcs", "0.1.0", "-", "-", "-", "-", "-", src},
+		strings.Fields(strings.Split(out, "\n")[1]))
+
+	// A sandboxed plugin shows whether this system can sandbox it.
+	cfg.Plugins.Sandbox = map[string]config.Sandbox{"vcs-demo": {}}
+
+	want := "landlock"
+	if sandbox.Available() != nil {
+		want = "unavailable"
+	}
+
+	out, err = runPluginCmd(t, cfg, &stubInspector{err: errNoInfo}, "", "list")
+	require.NoError(t, err)
+	require.Equal(t, want, strings.Fields(strings.Split(out, "\n")[1])[5])
+
+	// Configured plugins that are not installed are listed after the installed ones.
+	cfg.Plugins.VCS = config.Names{"git", "demo"}
+
+	out, err = runPluginCmd(t, cfg, &stubInspector{found: map[string]string{"vcs-git": "/bin/swm-plugin-vcs-git"}},
+		"", "list")
+	require.NoError(t, err)
+
+	lines = strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, []string{"git", "vcs", "-", "-", "-", "-", "xdg", "/bin/swm-plugin-vcs-git", "-"},
+		strings.Fields(lines[2]))
+}
+
+func TestPinCmd(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{DataHome: t.TempDir()}
+	cfg.Plugins.VCS = config.Names{"git"}
+	cfgPath := filepath.Join(cfg.DataHome, "config.toml")
+
+	binary := filepath.Join(t.TempDir(), "swm-plugin-vcs-git")
+	require.NoError(t, os.WriteFile(binary, []byte("git plugin"), 0o600))
+	require.NoError(t, os.WriteFile(cfgPath, []byte("code_root = \"/code\"\n"), 0o600))
+
+	inspector := &stubInspector{found: map[string]string{"vcs-git": binary}}
+
+	out, err := runPluginCmd(t, cfg, inspector, "", "pin")
+	require.NoError(t, err)
+	require.Contains(t, out, "Pinned vcs-git to "+binary+" (xdg, sha256 ")
+
+	pinned, err := config.Load(cfgPath)
+	require.NoError(t, err)
+	require.Equal(t, "/code", pinned.CodeRoot)
+	require.Equal(t, map[string]string{
+		"vcs-git": "c73ad26e33b0085187525f019ae920d36d1f2e9fd9a142c414aae5384766a8e9",
+	}, pinned.Plugins.Checksums)
+
+	_, err = runPluginCmd(t, cfg, inspector, "", "pin", "git")
+	require.ErrorContains(t, err, "plugin must be named with its capability")
+
+	_, err = runPluginCmd(t, cfg, inspector, "", "pin", "vcs-hg")
+	require.ErrorIs(t, err, errNotFound)
+}
+
+func TestUpgradeAndRemoveCmd(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{DataHome: t.TempDir()}
+	src := writePluginSource(t, "0.1.0")
+
+	_, err := runPluginCmd(t, cfg, &stubInspector{}, "", "install", "-y", src)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(src, "swm-plugin.toml"), []byte(
+		"[plugin]\nname = \"demo\"\ncapability = \"vcs\"\nversion = \"0.2.0\"\n\n"+
+			"[build]\ncommand = [\"touch\", \""+testBinary+"\"]\n"), 0o600))
+
+	out, err := runPluginCmd(t, cfg, &stubInspector{}, "n\n", "upgrade")
+	require.NoError(t, err)
+	require.Contains(t, out, "skipped")
+
+	out, err = runPluginCmd(t, cfg, &stubInspector{err: errNoInfo}, "", "list")
+	require.NoError(t, err)
+	require.Contains(t, out, "0.1.0")
+
+	out, err = runPluginCmd(t, cfg, &stubInspector{}, "", "upgrade", "--yes", "vcs-demo")
+	require.NoError(t, err)
+	require.Contains(t, out, "Upgraded demo")
+
+	out, err = runPluginCmd(t, cfg, &stubInspector{err: errNoInfo}, "", "list")
+	require.NoError(t, err)
+	require.Contains(t, out, "0.2.0")
+
+	out, err = runPluginCmd(t, cfg, &stubInspector{}, "", "remove", "demo")
+	require.NoError(t, err)
+	require.Equal(t, "Removed demo\n", out)
+	require.NoDirExists(t, filepath.Join(cfg.DataHome, "swm", "plugins", "demo"))
+
+	_, err = runPluginCmd(t, cfg, &stubInspector{}, "", "remove", "demo")
+	require.ErrorContains(t, err, "plugin not installed: demo")
+}
@@ -0,0 +1,59 @@
+package plugin
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+)
+
+// NewRemoveCmd builds the `swm plugin remove` command.
+func NewRemoveCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove <name>...",
+		Short: "Remove installed plugins",
+		Long: "Remove installed plugins and the links to their binaries. A plugin is named " +
+			"as in config.toml (zellij) or with its capability (session-zellij).",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			installer := newInstaller(cmd, cfg)
+
+			for _, name := range args {
+				p, err := installer.Get(name)
+				if err != nil {
+					return err
+				}
+
+				if err := installer.Remove(p); err != nil {
+					return err
+				}
+
+				cmd.Printf("Removed %s\n", p.ShortName())
+			}
+
+			return nil
+		},
+	}
+
+	cmd.ValidArgsFunction = installedCompletion(cfg)
+
+	return cmd
+}
+
+// installedCompletion completes the names of installed plugins.
+func installedCompletion(
+	cfg *config.Config,
+) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		plugins, err := newInstaller(cmd, cfg).List()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		names := make([]string, 0, len(plugins))
+		for _, p := range plugins {
+			names = append(names, p.ShortName())
+		}
+
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,93 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/plugininstall"
+)
+
+var errNoSource = errors.New("plugin has no recorded source; reinstall it")
+
+// NewUpgradeCmd builds the `swm plugin upgrade` command.
+func NewUpgradeCmd(cfg *config.Config) *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "upgrade [<name>...]",
+		Short: "Rebuild installed plugins from their source",
+		Long: "Fetch installed plugins again from the source they were installed from, and " +
+			"rebuild and replace them. Without names, every installed plugin is upgraded. " +
+			"Each build command is shown and must be confirmed, unless --yes is given; a " +
+			"failed build leaves the installed plugin untouched.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			installer := newInstaller(cmd, cfg)
+
+			var plugins []*plugininstall.Plugin
+
+			if len(args) == 0 {
+				all, err := installer.List()
+				if err != nil {
+					return err
+				}
+
+				plugins = all
+			}
+
+			for _, name := range args {
+				p, err := installer.Get(name)
+				if err != nil {
+					return err
+				}
+
+				plugins = append(plugins, p)
+			}
+
+			for _, p := range plugins {
+				if err := upgrade(cmd, installer, p, yes); err != nil {
+					return fmt.Errorf("upgrading %s: %w", p.ShortName(), err)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "run the build commands without asking")
+
+	cmd.ValidArgsFunction = installedCompletion(cfg)
+
+	return cmd
+}
+
+// upgrade fetches p from its source again, and builds and installs it in
+// place of p once its build command is confirmed.
+func upgrade(cmd *cobra.Command, installer *plugininstall.Installer, p *plugininstall.Plugin, yes bool) error {
+	if p.Source == "" {
+		return errNoSource
+	}
+
+	ctx := cmd.Context()
+
+	staged, err := installer.Fetch(ctx, p.Source)
+	if err != nil {
+		return err
+	}
+
+	if !confirmBuild(cmd, staged, yes) {
+		cmd.Println("skipped")
+
+		return staged.Cleanup()
+	}
+
+	if _, err := installer.Install(ctx, staged, p); err != nil {
+		return err
+	}
+
+	cmd.Printf("Upgraded %s\n", p.ShortName())
+
+	return nil
+}
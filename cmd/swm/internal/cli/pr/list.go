@@ -5,8 +5,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -14,6 +16,7 @@ import (
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
 	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/statuscache"
 )
 
 // forgeManager is the subset of the plugin manager used by pr commands.
@@ -44,7 +47,7 @@ func NewListCmd(store coreStory.Store, mgr forgeManager, cfg *config.Config) *co
 				return fmt.Errorf("loading story %q: %w", storyName, err)
 			}
 
-			return listPRs(ctx, cmd.OutOrStdout(), mgr, s)
+			return listPRs(ctx, cmd.OutOrStdout(), mgr, s, statuscache.New(cfg.CacheHome))
 		},
 	}
 
@@ -53,7 +56,13 @@ func NewListCmd(store coreStory.Store, mgr forgeManager, cfg *config.Config) *co
 	return cmd
 }
 
-func listPRs(ctx context.Context, out io.Writer, mgr forgeManager, s *coreStory.Story) error {
+// listPRs prints the open pull requests of every project in s. Pull requests
+// opened from the story branch are also recorded in cache for `swm status`.
+func listPRs(
+	ctx context.Context, out io.Writer, mgr forgeManager, s *coreStory.Story, cache *statuscache.Cache,
+) error {
+	storyPRs := make(map[string]*statuscache.PR)
+
 	for _, proj := range s.Projects {
 		forge, err := mgr.GetForge(ctx, proj.Host)
 		if err != nil {
@@ -86,7 +95,30 @@ func listPRs(ctx context.Context, out io.Writer, mgr forgeManager, s *coreStory.
 
 			//nolint:errcheck // output write errors are non-actionable
 			fmt.Fprintf(out, "#%d\t%s\t%s\n", pr.GetNumber(), pr.GetTitle(), pr.GetUrl())
+
+			if s.BranchName != "" && pr.GetHeadBranch() == s.BranchName {
+				storyPRs[statuscache.ProjectKey(proj.Host, proj.Segments)] = statuscache.PRFromProto(pr)
+			}
+		}
+	}
+
+	if len(storyPRs) == 0 {
+		return nil
+	}
+
+	if err := cache.Update(s.Name, func(entries statuscache.Entries) {
+		for key, pr := range storyPRs {
+			entry, ok := entries[key]
+			if !ok {
+				entry = &statuscache.Entry{}
+				entries[key] = entry
+			}
+
+			entry.PR = pr
+			entry.UpdatedAt = time.Now().UTC()
 		}
+	}); err != nil {
+		slog.WarnContext(ctx, "updating status cache", "story", s.Name, "err", err)
 	}
 
 	return nil
@@ -17,6 +17,7 @@ import (
 
 	"github.com/kalbasit/swm/cmd/swm/internal/cli/pr"
 	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/statuscache"
 )
 
 const (
@@ -155,7 +156,7 @@ func TestPRList_Success(t *testing.T) {
 		testGitHubHost: &stubForgeClient{prs: prs},
 	}}
 
-	cfg := &config.Config{DefaultStory: testDefaultStory}
+	cfg := &config.Config{DefaultStory: testDefaultStory, CacheHome: t.TempDir()}
 
 	var out bytes.Buffer
 
@@ -182,7 +183,7 @@ func TestPRList_NoPRs(t *testing.T) {
 		testGitHubHost: &stubForgeClient{prs: nil},
 	}}
 
-	cfg := &config.Config{DefaultStory: testDefaultStory}
+	cfg := &config.Config{DefaultStory: testDefaultStory, CacheHome: t.TempDir()}
 
 	var out bytes.Buffer
 
@@ -207,7 +208,7 @@ func TestPRList_NoForgeForHost(t *testing.T) {
 	// No forges configured.
 	mgr := &stubForgeManager{forges: map[string]pluginv1.ForgeClient{}}
 
-	cfg := &config.Config{DefaultStory: testDefaultStory}
+	cfg := &config.Config{DefaultStory: testDefaultStory, CacheHome: t.TempDir()}
 
 	var out bytes.Buffer
 
@@ -235,7 +236,7 @@ func TestPRList_FallsBackToDefaultStory(t *testing.T) {
 		}},
 	}}
 
-	cfg := &config.Config{DefaultStory: testDefaultStory}
+	cfg := &config.Config{DefaultStory: testDefaultStory, CacheHome: t.TempDir()}
 
 	var out bytes.Buffer
 
@@ -247,3 +248,33 @@ func TestPRList_FallsBackToDefaultStory(t *testing.T) {
 	require.NoError(t, cmd.Execute())
 	require.Contains(t, out.String(), "Default PR")
 }
+
+func TestPRList_CachesStoryBranchPR(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{story: &coreStory.Story{
+		Name:       testPRStoryName,
+		BranchName: "feat/feat-x",
+		Projects:   []coreStory.Project{{Host: testGitHubHost, Segments: []string{"o", "r"}}},
+	}}
+
+	mgr := &stubForgeManager{forges: map[string]pluginv1.ForgeClient{
+		testGitHubHost: &stubForgeClient{prs: []*pluginv1.PullRequest{
+			{Number: 1, Title: "Other", HeadBranch: "other", State: pluginv1.PullRequestState_PULL_REQUEST_STATE_OPEN},
+			{Number: 2, Title: "Story", HeadBranch: "feat/feat-x", State: pluginv1.PullRequestState_PULL_REQUEST_STATE_OPEN},
+		}},
+	}}
+
+	cfg := &config.Config{DefaultStory: testDefaultStory, CacheHome: t.TempDir()}
+
+	cmd := pr.NewListCmd(store, mgr, cfg)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{flagStory, testPRStoryName})
+
+	require.NoError(t, cmd.Execute())
+
+	entries, err := statuscache.New(cfg.CacheHome).Load(testPRStoryName)
+	require.NoError(t, err)
+	require.Contains(t, entries, "github.com/o/r")
+	require.Equal(t, &statuscache.PR{Number: 2, State: "open"}, entries["github.com/o/r"].PR)
+}
@@ -6,26 +6,41 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	cliconfig "github.com/kalbasit/swm/cmd/swm/internal/cli/config"
+	clidaemon "github.com/kalbasit/swm/cmd/swm/internal/cli/daemon"
+	cliplugin "github.com/kalbasit/swm/cmd/swm/internal/cli/plugin"
 	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
 	"github.com/kalbasit/swm/cmd/swm/internal/cli/pr"
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/services"
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/status"
 	"github.com/kalbasit/swm/cmd/swm/internal/cli/story"
 	"github.com/kalbasit/swm/cmd/swm/internal/cli/workspace"
 	"github.com/kalbasit/swm/cmd/swm/internal/config"
 	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/ports"
+	"github.com/kalbasit/swm/cmd/swm/internal/daemon"
 	"github.com/kalbasit/swm/cmd/swm/internal/hookexec"
 )
 
+// traceFlag names the flag that records a trace; see TracePath.
+const traceFlag = "trace"
+
 // PluginManager is the interface the CLI uses to retrieve plugin clients.
 type PluginManager interface {
 	Get(ctx context.Context, capability string) (any, error)
 	GetForge(ctx context.Context, hostname string) (pluginv1.ForgeClient, error)
+	GetVCS(ctx context.Context, name string) (pluginv1.VCSClient, error)
+	VCSForPath(ctx context.Context, path string) (string, error)
+	VCSForURL(ctx context.Context, url string) (string, error)
 	Warm(ctx context.Context, capabilities ...string) error
+	Discover(capability, name string) (string, string, error)
+	Inspect(ctx context.Context, capability, name, binary string) (*pluginv1.PluginInfo, error)
 	Close() error
 }
 
@@ -58,28 +73,43 @@ func NewRootCmd(
 	}
 
 	root.PersistentFlags().StringVar(&logLevel, "log-level", "warn", "log level (debug, info, warn, error)")
+	// main reads --trace itself, to trace what runs before the command.
+	root.PersistentFlags().String(traceFlag, "",
+		"write a Chrome trace of this run to `file`, for ui.perfetto.dev (also $SWM_TRACE)")
 
 	hooks := hookexec.RunnerFunc(func(ctx context.Context, rc hookexec.RunConfig) error {
 		if rc.ConfigHome == "" {
 			rc.ConfigHome = cfg.HooksConfigHome
 		}
 
+		if rc.Env == nil && rc.StoryName != "" {
+			rc.Env = storyEnviron(ctx, cfg, store, resolver, rc)
+		}
+
 		return hookexec.Run(ctx, rc)
 	})
 
 	storyGroup := &cobra.Command{Use: "story", Short: "Manage stories"}
-	storyGroup.AddCommand(story.NewCreateCmd(store, cfg.CodeRoot, hooks, cfg.Story.BranchNameTemplate))
+	storyGroup.AddCommand(story.NewCreateCmd(store, mgr, cfg.CodeRoot, hooks, cfg.Story.BranchNameTemplate))
 	storyGroup.AddCommand(story.NewListCmd(store, cfg.DefaultStory))
 	storyGroup.AddCommand(story.NewRemoveCmd(store, mgr, resolver, hooks))
 	storyGroup.AddCommand(story.NewAttachCmd(store, mgr, resolver, hooks, cfg.DefaultStory))
+	storyGroup.AddCommand(story.NewPruneCmd(store, mgr, resolver, hooks))
+	storyGroup.AddCommand(story.NewEnvCmd(store, resolver))
+	storyGroup.AddCommand(story.NewPortsCmd(store, resolver, cfg.Ports.BlockSize))
 	root.AddCommand(storyGroup)
 
 	root.AddCommand(NewCloneCmd(mgr, resolver, hooks))
+	root.AddCommand(NewExecCmd(cfg, store, resolver))
+	root.AddCommand(NewBugReportCmd(cfg))
 
 	wsGroup := &cobra.Command{Use: "workspace", Short: "Manage workspaces"}
 	wsGroup.AddCommand(workspace.NewOpenCmd(cfg, store, mgr, resolver, hooks, openOpts...))
-	wsGroup.AddCommand(workspace.NewListCmd(store, cfg.DefaultStory))
-	wsGroup.AddCommand(workspace.NewCloseCmd(store, mgr))
+	wsGroup.AddCommand(workspace.NewListCmd(store, mgr, cfg.DefaultStory))
+	wsGroup.AddCommand(workspace.NewCloseCmd(cfg, store, mgr))
+	wsGroup.AddCommand(workspace.NewSaveCmd(cfg, store, mgr))
+	wsGroup.AddCommand(workspace.NewRestoreCmd(cfg, store, mgr, resolver))
+	wsGroup.AddCommand(workspace.NewSwitchCmd(mgr))
 	root.AddCommand(wsGroup)
 
 	prGroup := &cobra.Command{Use: "pr", Short: "Manage pull requests"}
@@ -87,7 +117,67 @@ func NewRootCmd(
 	prGroup.AddCommand(pr.NewCreateCmd(mgr, resolver, store, cfg))
 	root.AddCommand(prGroup)
 
+	svcGroup := &cobra.Command{Use: "services", Short: "Manage the long-running services of a story"}
+	svcGroup.AddCommand(services.NewUpCmd(cfg, store, mgr, resolver))
+	svcGroup.AddCommand(services.NewDownCmd(cfg, store, mgr, resolver))
+	svcGroup.AddCommand(services.NewStatusCmd(cfg, store, mgr, resolver))
+	svcGroup.AddCommand(services.NewLogsCmd(cfg, store, resolver))
+	root.AddCommand(svcGroup)
+
+	root.AddCommand(status.NewStatusCmd(cfg, store, mgr, resolver))
+
 	root.AddCommand(cliconfig.NewConfigCmd(cfgPath, cfg))
+	root.AddCommand(cliplugin.NewPluginCmd(cfgPath, cfg, mgr))
+	root.AddCommand(clidaemon.NewDaemonCmd(cfgPath, store, daemon.SocketPath()))
 
 	return root
 }
+
+// TracePath returns the file the trace of this run goes to: the value of
+// --trace in args, or else $SWM_TRACE. It returns "" when there is none.
+// main needs it before the root command parses its flags.
+func TracePath(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if value, ok := strings.CutPrefix(arg, "--"+traceFlag+"="); ok {
+			return value
+		}
+
+		if arg == "--"+traceFlag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+
+	return os.Getenv("SWM_TRACE")
+}
+
+// storyEnviron returns the environment of the story a hook runs for, with the
+// overrides and ports of its project when it has one. Hooks of stories that
+// do not exist (yet, as for pre-story-create) get none.
+func storyEnviron(
+	ctx context.Context,
+	cfg *config.Config,
+	store coreStory.Store,
+	resolver *layout.Resolver,
+	rc hookexec.RunConfig,
+) []string {
+	s, err := store.Get(ctx, rc.StoryName)
+	if err != nil {
+		return nil
+	}
+
+	var key string
+	if rc.ProjectHost != "" && rc.ProjectPath != "" {
+		key = rc.ProjectHost + "/" + rc.ProjectPath
+	}
+
+	env, err := ports.StoryEnvironment(resolver, s, key, cfg.HistfilePath(rc.StoryName), cfg.Ports.BlockSize)
+	if err != nil {
+		slog.WarnContext(ctx, "hooks: cannot read named ports", "story", rc.StoryName, "err", err)
+	}
+
+	return coreStory.Environ(env)
+}
@@ -0,0 +1,30 @@
+package cli_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli"
+)
+
+func TestTracePath(t *testing.T) { //nolint:paralleltest // t.Setenv is incompatible with t.Parallel
+	t.Setenv("SWM_TRACE", "env.json")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"separate value", []string{"story", "list", "--trace", "out.json"}, "out.json"},
+		{"joined value", []string{"--trace=out.json", "status"}, "out.json"},
+		{"from environment", []string{"status"}, "env.json"},
+		{"after dashdash", []string{"exec", "--", "cmd", "--trace", "out.json"}, "env.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { //nolint:paralleltest // shares the parent's environment
+			require.Equal(t, tt.want, cli.TracePath(tt.args))
+		})
+	}
+}
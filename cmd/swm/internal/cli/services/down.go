@@ -0,0 +1,84 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	coreServices "github.com/kalbasit/swm/cmd/swm/internal/core/services"
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+)
+
+// NewDownCmd returns the `swm services down` command.
+func NewDownCmd(
+	cfg *config.Config,
+	store coreStory.Store,
+	mgr pluginManager,
+	resolver *layout.Resolver,
+) *cobra.Command {
+	d := newDeps(cfg, store, mgr, resolver)
+
+	var storyName string
+
+	cmd := &cobra.Command{
+		Use:   "down [<service>...]",
+		Short: "Stop the services of a story",
+		Long: "Stop the services of a story, or only the named ones (host/owner/repo:name or " +
+			"name), most recently started first. Stopping a story without running services " +
+			"succeeds.",
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			d.mgr.Warm(cmd.Context(), "session") //nolint:errcheck,gosec // Warm always returns nil; errors deferred to Get
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			name, err := d.storyName(storyName)
+			if err != nil {
+				return err
+			}
+
+			sess, err := d.session(ctx)
+			if err != nil {
+				return err
+			}
+
+			ws, err := findWorkspace(ctx, sess, name)
+			if err != nil || ws == nil {
+				return err
+			}
+
+			running, err := coreServices.List(ctx, sess, ws.GetWorkspaceId())
+			if err != nil {
+				return err
+			}
+
+			for _, svc := range slices.Backward(running) {
+				if !matchesName(svc, args) {
+					continue
+				}
+
+				if _, err := sess.StopService(ctx, &pluginv1.StopServiceRequest{
+					WorkspaceId: ws.GetWorkspaceId(),
+					ServiceId:   svc.GetServiceId(),
+				}); err != nil {
+					return fmt.Errorf("stopping service %q: %w", svc.GetName(), err)
+				}
+
+				cmd.Printf("stopped %s\n", svc.GetName())
+			}
+
+			return nil
+		},
+	}
+
+	d.addStoryFlag(cmd, &storyName)
+
+	return cmd
+}
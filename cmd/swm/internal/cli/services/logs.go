@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	coreServices "github.com/kalbasit/swm/cmd/swm/internal/core/services"
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+)
+
+// logsPollInterval is how often `swm services logs --follow` checks the log
+// files for new output.
+const logsPollInterval = 500 * time.Millisecond
+
+// defaultLogLines is the default of `swm services logs --lines`.
+const defaultLogLines = 100
+
+// NewLogsCmd returns the `swm services logs` command.
+func NewLogsCmd(cfg *config.Config, store coreStory.Store, resolver *layout.Resolver) *cobra.Command {
+	d := newDeps(cfg, store, nil, resolver)
+
+	var (
+		storyName string
+		follow    bool
+		lines     int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "logs [<service>...]",
+		Short: "Print the logs of the services of a story",
+		Long: "Print the last lines of the log of every service of a story, or only of the named " +
+			"ones (host/owner/repo:name or name). Logs are kept in the story's data directory " +
+			"and survive the service. With --follow, new output is printed as it is written.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			s, all, err := d.load(ctx, storyName)
+			if err != nil {
+				return err
+			}
+
+			selected, err := coreServices.Match(all, args)
+			if err != nil {
+				return err
+			}
+
+			logs := make([]serviceLog, 0, len(selected))
+			for _, svc := range selected {
+				logs = append(logs, serviceLog{name: svc.Name, path: svc.LogPath(d.logDir(s.Name))})
+			}
+
+			return printLogs(ctx, cmd.OutOrStdout(), logs, lines, follow)
+		},
+	}
+
+	d.addStoryFlag(cmd, &storyName)
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "keep printing new output until interrupted")
+	cmd.Flags().IntVarP(&lines, "lines", "n", defaultLogLines,
+		"number of lines to print from the end of each log; 0 for all")
+
+	return cmd
+}
+
+// serviceLog is the log file of a service.
+type serviceLog struct {
+	name string
+	path string
+	// offset is how much of the file has been printed.
+	offset int64
+}
+
+// printLogs writes the last n lines of every log to w, each under a
+// "==> name <==" header when there are several, and keeps polling them for
+// new output until ctx is done when follow is set.
+func printLogs(ctx context.Context, w io.Writer, logs []serviceLog, n int, follow bool) error {
+	headers := len(logs) > 1
+	last := -1
+
+	for i := range logs {
+		data, err := os.ReadFile(logs[i].path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("reading log of %s: %w", logs[i].name, err)
+		}
+
+		logs[i].offset = int64(len(data))
+		writeLog(w, headers, &last, i, logs[i].name, tail(data, n))
+	}
+
+	for follow {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(logsPollInterval):
+		}
+
+		for i := range logs {
+			data, offset, err := readFrom(logs[i].path, logs[i].offset)
+			if err != nil {
+				return fmt.Errorf("reading log of %s: %w", logs[i].name, err)
+			}
+
+			logs[i].offset = offset
+			writeLog(w, headers, &last, i, logs[i].name, data)
+		}
+	}
+
+	return nil
+}
+
+// writeLog writes data, read from log i, to w, preceded by a header naming the
+// service when headers is set and the previous output came from another log.
+func writeLog(w io.Writer, headers bool, last *int, i int, name string, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+
+	if headers && *last != i {
+		if *last != -1 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintf(w, "==> %s <==\n", name)
+	}
+
+	*last = i
+
+	w.Write(data) //nolint:errcheck,gosec // best-effort terminal output
+}
+
+// readFrom returns the content of the file at path from offset on and the
+// offset of its end. A missing file is empty; a file shorter than offset was
+// truncated and is read from the start.
+func readFrom(path string, offset int64) ([]byte, int64, error) {
+	f, err := os.Open(path) //nolint:gosec // path is a log under the story's data dir
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, offset, nil
+	}
+
+	if err != nil {
+		return nil, offset, err
+	}
+	defer f.Close()
+
+	if fi, err := f.Stat(); err == nil && fi.Size() < offset {
+		offset = 0
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+
+	data, err := io.ReadAll(f)
+
+	return data, offset + int64(len(data)), err
+}
+
+// tail returns the last n lines of data, or all of it when n is 0 or less.
+func tail(data []byte, n int) []byte {
+	if n <= 0 {
+		return data
+	}
+
+	end := len(data)
+	if end > 0 && data[end-1] == '\n' {
+		end--
+	}
+
+	for i := end - 1; i >= 0; i-- {
+		if data[i] != '\n' {
+			continue
+		}
+
+		n--
+		if n == 0 {
+			return data[i+1:]
+		}
+	}
+
+	return data
+}
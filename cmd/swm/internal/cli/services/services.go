@@ -0,0 +1,210 @@
+// Package services contains the `swm services` sub-commands.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	coreServices "github.com/kalbasit/swm/cmd/swm/internal/core/services"
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/ports"
+)
+
+var errUnexpectedSessionPlugin = errors.New("unexpected session plugin type")
+
+// pluginManager is the subset of the CLI plugin manager used by these commands.
+type pluginManager interface {
+	Get(ctx context.Context, capability string) (any, error)
+	Warm(ctx context.Context, capabilities ...string) error
+}
+
+// deps are the dependencies shared by the services commands.
+type deps struct {
+	cfg      *config.Config
+	store    coreStory.Store
+	mgr      pluginManager
+	resolver *layout.Resolver
+}
+
+// newDeps returns the dependencies of a services command.
+func newDeps(cfg *config.Config, store coreStory.Store, mgr pluginManager, resolver *layout.Resolver) *deps {
+	return &deps{cfg: cfg, store: store, mgr: mgr, resolver: resolver}
+}
+
+// addStoryFlag registers the --story flag shared by every sub-command.
+func (d *deps) addStoryFlag(cmd *cobra.Command, storyName *string) {
+	cmd.Flags().StringVarP(storyName, "story", "s", "", "story name (default: detected)")
+
+	//nolint:errcheck,gosec // RegisterFlagCompletionFunc only fails for unknown flags
+	cmd.RegisterFlagCompletionFunc("story", func(
+		cmd *cobra.Command, _ []string, _ string,
+	) ([]string, cobra.ShellCompDirective) {
+		stories, err := d.store.List(cmd.Context())
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		names := make([]string, 0, len(stories))
+		for _, s := range stories {
+			names = append(names, s.Name)
+		}
+
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// load returns the story named by flagValue (see storyName) and the services
+// declared by its projects, in start order.
+func (d *deps) load(ctx context.Context, flagValue string) (*coreStory.Story, []coreServices.Service, error) {
+	name, err := d.storyName(flagValue)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s, err := d.store.Get(ctx, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading story %q: %w", name, err)
+	}
+
+	var all []coreServices.Service
+
+	for _, p := range s.Projects {
+		id := &pluginv1.ProjectID{Host: p.Host, Segments: p.Segments}
+		key := p.Host + "/" + strings.Join(p.Segments, "/")
+
+		svcs, err := coreServices.Load(key, d.resolver.WorktreePath(s.Name, id))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		all = append(all, svcs...)
+	}
+
+	ordered, err := coreServices.Order(all)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, ordered, nil
+}
+
+// logDir returns the directory holding the service logs of storyName.
+func (d *deps) logDir(storyName string) string {
+	return filepath.Join(d.cfg.StoryDataDir(storyName), "services")
+}
+
+// session loads the session plugin.
+func (d *deps) session(ctx context.Context) (pluginv1.SessionClient, error) {
+	raw, err := d.mgr.Get(ctx, "session")
+	if err != nil {
+		return nil, fmt.Errorf("loading session plugin: %w", err)
+	}
+
+	sess, ok := raw.(pluginv1.SessionClient)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected pluginv1.SessionClient, got %T", errUnexpectedSessionPlugin, raw)
+	}
+
+	return sess, nil
+}
+
+// storyName returns flagValue, $SWM_STORY, the story of the working directory
+// or the default story, in that order.
+func (d *deps) storyName(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+
+	if name := os.Getenv("SWM_STORY"); name != "" {
+		return name, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting working directory: %w", err)
+	}
+
+	if name := d.resolver.StoryNameFromPath(cwd); name != "" {
+		return name, nil
+	}
+
+	return d.cfg.DefaultStory, nil
+}
+
+// findWorkspace returns the workspace of storyName, or nil when it is not
+// running.
+func findWorkspace(ctx context.Context, sess pluginv1.SessionClient, storyName string) (*pluginv1.Workspace, error) {
+	stream, err := sess.ListWorkspaces(ctx, &pluginv1.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("listing workspaces: %w", err)
+	}
+
+	for {
+		ws, err := stream.Recv()
+		if err != nil {
+			return nil, nil //nolint:nilerr // end of stream or broken stream: no workspace
+		}
+
+		if ws.GetStoryName() == storyName {
+			return ws, nil
+		}
+	}
+}
+
+// matchesName reports whether the running service svc is named by one of
+// names, qualified or bare. An empty names matches every service.
+func matchesName(svc *pluginv1.Service, names []string) bool {
+	if len(names) == 0 {
+		return true
+	}
+
+	for _, name := range names {
+		if svc.GetName() == name || strings.HasSuffix(svc.GetName(), ":"+name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// serviceEnv returns the environment of svc in story s: the story's
+// environment and ports for the service's project, the SWM_* variables hooks
+// get, SWM_SERVICE, and the service's own env, which wins.
+func serviceEnv(
+	cfg *config.Config,
+	resolver *layout.Resolver,
+	s *coreStory.Story,
+	svc coreServices.Service,
+) (map[string]string, error) {
+	host, projectPath, _ := strings.Cut(svc.Project, "/")
+
+	env, err := ports.StoryEnvironment(resolver, s, svc.Project, cfg.HistfilePath(s.Name), cfg.Ports.BlockSize)
+	if err != nil {
+		return nil, fmt.Errorf("reading named ports of %s: %w", svc.Project, err)
+	}
+
+	maps.Copy(env, map[string]string{
+		"SWM_STORY":         s.Name,
+		"SWM_PROJECT_HOST":  host,
+		"SWM_PROJECT_PATH":  projectPath,
+		"SWM_WORKTREE_PATH": svc.Worktree,
+		"SWM_REPO_PATH": resolver.CanonicalPath(
+			&pluginv1.ProjectID{Host: host, Segments: strings.Split(projectPath, "/")},
+		),
+		"SWM_SERVICE": svc.Name,
+	})
+	maps.Copy(env, svc.Env)
+
+	return env, nil
+}
@@ -0,0 +1,389 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/services"
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+)
+
+const (
+	testStoryName   = "feat-x"
+	testProject     = "github.com/kalbasit/swm"
+	testWorkspaceID = "/tmp/feat-x.sock"
+)
+
+const testServicesFile = `
+[services.db]
+command = "postgres"
+
+[services.web]
+command = "npm run dev"
+depends_on = ["db"]
+cwd = "frontend"
+env = { PORT = "3000" }
+`
+
+// stubSession is a SessionClient keeping started services in memory.
+type stubSession struct {
+	running  bool
+	opened   *pluginv1.OpenWorkspaceRequest
+	services []*pluginv1.Service
+	started  []*pluginv1.StartServiceRequest
+	stopped  []string
+}
+
+func (s *stubSession) AttachCommand(
+	context.Context, *pluginv1.AttachCommandRequest, ...grpc.CallOption,
+) (*pluginv1.AttachCommandResponse, error) {
+	panic("stub")
+}
+
+func (s *stubSession) ClosePaneGroup(
+	context.Context, *pluginv1.ClosePaneGroupRequest, ...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	panic("stub")
+}
+
+func (s *stubSession) CloseWorkspace(
+	context.Context, *pluginv1.CloseWorkspaceRequest, ...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	panic("stub")
+}
+
+func (s *stubSession) CurrentContext(
+	context.Context, *pluginv1.Empty, ...grpc.CallOption,
+) (*pluginv1.CurrentContextResponse, error) {
+	panic("stub")
+}
+
+func (s *stubSession) Info(context.Context, *pluginv1.Empty, ...grpc.CallOption) (*pluginv1.SessionInfo, error) {
+	panic("stub")
+}
+
+func (s *stubSession) IsInsideWorkspace(
+	context.Context, *pluginv1.Empty, ...grpc.CallOption,
+) (*pluginv1.BoolValue, error) {
+	panic("stub")
+}
+
+func (s *stubSession) ListPaneGroups(
+	context.Context, *pluginv1.ListPaneGroupsRequest, ...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.PaneGroup], error) {
+	panic("stub")
+}
+
+func (s *stubSession) ListServices(
+	context.Context, *pluginv1.ListServicesRequest, ...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.Service], error) {
+	return &sliceStream[pluginv1.Service]{items: s.services}, nil
+}
+
+func (s *stubSession) ListWorkspaces(
+	context.Context, *pluginv1.Empty, ...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.Workspace], error) {
+	var workspaces []*pluginv1.Workspace
+	if s.running {
+		workspaces = append(workspaces, &pluginv1.Workspace{WorkspaceId: testWorkspaceID, StoryName: testStoryName})
+	}
+
+	return &sliceStream[pluginv1.Workspace]{items: workspaces}, nil
+}
+
+func (s *stubSession) OpenPaneGroup(
+	context.Context, *pluginv1.OpenPaneGroupRequest, ...grpc.CallOption,
+) (*pluginv1.PaneGroup, error) {
+	panic("stub")
+}
+
+func (s *stubSession) OpenWorkspace(
+	_ context.Context, req *pluginv1.OpenWorkspaceRequest, _ ...grpc.CallOption,
+) (*pluginv1.Workspace, error) {
+	s.running = true
+	s.opened = req
+
+	return &pluginv1.Workspace{WorkspaceId: testWorkspaceID, StoryName: req.GetStoryName()}, nil
+}
+
+func (s *stubSession) RestoreWorkspace(
+	context.Context, *pluginv1.RestoreWorkspaceRequest, ...grpc.CallOption,
+) (*pluginv1.RestoreWorkspaceResponse, error) {
+	panic("stub")
+}
+
+func (s *stubSession) SaveWorkspace(
+	context.Context, *pluginv1.SaveWorkspaceRequest, ...grpc.CallOption,
+) (*pluginv1.SaveWorkspaceResponse, error) {
+	panic("stub")
+}
+
+func (s *stubSession) StartService(
+	_ context.Context, req *pluginv1.StartServiceRequest, _ ...grpc.CallOption,
+) (*pluginv1.Service, error) {
+	s.started = append(s.started, req)
+
+	svc := &pluginv1.Service{
+		ServiceId: "%" + strconv.Itoa(len(s.started)),
+		Name:      req.GetName(),
+		Pid:       int64(100 + len(s.started)),
+		Running:   true,
+	}
+	s.services = append(s.services, svc)
+
+	return svc, nil
+}
+
+func (s *stubSession) StopService(
+	_ context.Context, req *pluginv1.StopServiceRequest, _ ...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	s.stopped = append(s.stopped, req.GetServiceId())
+
+	return &pluginv1.Empty{}, nil
+}
+
+func (s *stubSession) SwitchTo(
+	context.Context, *pluginv1.SwitchToRequest, ...grpc.CallOption,
+) (*pluginv1.SwitchToResponse, error) {
+	panic("stub")
+}
+
+var _ pluginv1.SessionClient = (*stubSession)(nil)
+
+// sliceStream streams a fixed slice, then EOF.
+type sliceStream[T any] struct {
+	items []*T
+	pos   int
+}
+
+func (s *sliceStream[T]) CloseSend() error             { return nil }
+func (s *sliceStream[T]) Context() context.Context     { return context.Background() }
+func (s *sliceStream[T]) Header() (metadata.MD, error) { panic("stub") }
+
+func (s *sliceStream[T]) Recv() (*T, error) {
+	if s.pos >= len(s.items) {
+		return nil, io.EOF
+	}
+
+	item := s.items[s.pos]
+	s.pos++
+
+	return item, nil
+}
+
+func (s *sliceStream[T]) RecvMsg(any) error    { panic("stub") }
+func (s *sliceStream[T]) SendMsg(any) error    { panic("stub") }
+func (s *sliceStream[T]) Trailer() metadata.MD { panic("stub") }
+
+type stubMgr struct{ sess pluginv1.SessionClient }
+
+func (m *stubMgr) Get(context.Context, string) (any, error) { return m.sess, nil }
+func (m *stubMgr) Warm(context.Context, ...string) error    { return nil }
+
+// fixture holds the dependencies of the services commands under test.
+type fixture struct {
+	cfg      *config.Config
+	store    coreStory.Store
+	resolver *layout.Resolver
+	sess     *stubSession
+	worktree string
+}
+
+// newFixture creates a story attached to testProject whose worktree declares
+// testServicesFile.
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	codeRoot := t.TempDir()
+	store := coreStory.NewJSONStore(t.TempDir())
+
+	s, err := store.Create(t.Context(), testStoryName, "feat/"+testStoryName)
+	require.NoError(t, err)
+
+	s.Projects = []coreStory.Project{{Host: "github.com", Segments: []string{"kalbasit", "swm"}}}
+	require.NoError(t, store.Update(t.Context(), s))
+
+	wt := filepath.Join(codeRoot, "stories", testStoryName, "github.com", "kalbasit", "swm")
+	require.NoError(t, os.MkdirAll(filepath.Join(wt, ".swm"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(wt, ".swm", "services.toml"), []byte(testServicesFile), 0o600))
+
+	return &fixture{
+		cfg:      &config.Config{CodeRoot: codeRoot, DefaultStory: "_default", DataHome: t.TempDir()},
+		store:    store,
+		resolver: layout.NewResolver(codeRoot, "_default"),
+		sess:     &stubSession{},
+		worktree: wt,
+	}
+}
+
+func (f *fixture) run(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+
+	var out strings.Builder
+
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(append([]string{"--story", testStoryName}, args...))
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+func (f *fixture) up(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	return f.run(t, services.NewUpCmd(f.cfg, f.store, &stubMgr{sess: f.sess}, f.resolver), args...)
+}
+
+func TestUpCmd_StartsServicesInDependencyOrder(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+
+	out, err := f.up(t)
+	require.NoError(t, err)
+	require.Equal(t, "started "+testProject+":db (pid 101)\nstarted "+testProject+":web (pid 102)\n", out)
+
+	require.Len(t, f.sess.started, 2)
+
+	web := f.sess.started[1]
+	require.Equal(t, testWorkspaceID, web.GetWorkspaceId())
+	require.Equal(t, []string{"sh", "-c", "npm run dev"}, web.GetArgv())
+	require.Equal(t, filepath.Join(f.worktree, "frontend"), web.GetWorkDir())
+	require.Equal(t, "3000", web.GetEnv()["PORT"])
+	require.Equal(t, testStoryName, web.GetEnv()["SWM_STORY"])
+	require.Equal(t, "kalbasit/swm", web.GetEnv()["SWM_PROJECT_PATH"])
+	require.Equal(t, testProject+":web", web.GetEnv()["SWM_SERVICE"])
+	require.True(t, strings.HasPrefix(web.GetLogPath(), f.cfg.DataHome), web.GetLogPath())
+	require.True(t, strings.HasSuffix(web.GetLogPath(), filepath.Join(testProject, "web.log")), web.GetLogPath())
+}
+
+func TestUpCmd_AppliesStoryEnv(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+
+	s, err := f.store.Get(t.Context(), testStoryName)
+	require.NoError(t, err)
+
+	s.Env = map[string]string{"AWS_PROFILE": "dev", "PORT": "1"}
+	s.ProjectEnv = map[string]map[string]string{testProject: {"DATABASE_URL": "postgres:///x"}}
+	require.NoError(t, f.store.Update(t.Context(), s))
+
+	_, err = f.up(t)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"AWS_PROFILE": "dev", "PORT": "1"}, f.sess.opened.GetEnv())
+
+	web := f.sess.started[1].GetEnv()
+	require.Equal(t, "dev", web["AWS_PROFILE"])
+	require.Equal(t, "postgres:///x", web["DATABASE_URL"])
+	require.Equal(t, "3000", web["PORT"], "the service's own env wins")
+}
+
+func TestUpCmd_NamedServiceStartsDependenciesAndSkipsRunning(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+
+	_, err := f.up(t, "db")
+	require.NoError(t, err)
+
+	out, err := f.up(t, "web")
+	require.NoError(t, err)
+	require.Contains(t, out, testProject+":db already running (pid 101)")
+	require.Contains(t, out, "started "+testProject+":web")
+	require.Len(t, f.sess.started, 2)
+
+	_, err = f.up(t, "nope")
+	require.ErrorContains(t, err, `unknown service "nope"`)
+}
+
+func TestDownCmd_StopsInReverseOrder(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+	mgr := &stubMgr{sess: f.sess}
+
+	_, err := f.up(t)
+	require.NoError(t, err)
+
+	out, err := f.run(t, services.NewDownCmd(f.cfg, f.store, mgr, f.resolver), "web")
+	require.NoError(t, err)
+	require.Equal(t, "stopped "+testProject+":web\n", out)
+
+	f.sess.stopped = nil
+
+	_, err = f.run(t, services.NewDownCmd(f.cfg, f.store, mgr, f.resolver))
+	require.NoError(t, err)
+	require.Equal(t, []string{"%2", "%1"}, f.sess.stopped)
+}
+
+func TestDownCmd_NoWorkspace(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+
+	out, err := f.run(t, services.NewDownCmd(f.cfg, f.store, &stubMgr{sess: f.sess}, f.resolver))
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
+
+func TestStatusCmd(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+	f.sess.running = true
+	f.sess.services = []*pluginv1.Service{
+		{ServiceId: "%1", Name: testProject + ":db", Pid: 42, Running: true},
+		{ServiceId: "%2", Name: "github.com/kalbasit/old:worker", ExitCode: 2},
+	}
+
+	out, err := f.run(t, services.NewStatusCmd(f.cfg, f.store, &stubMgr{sess: f.sess}, f.resolver))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 4)
+	require.Regexp(t, `^SERVICE\s+STATUS\s+PID$`, lines[0])
+	require.Regexp(t, `^github.com/kalbasit/swm:db\s+running\s+42$`, lines[1])
+	require.Regexp(t, `^github.com/kalbasit/swm:web\s+stopped\s+-$`, lines[2])
+	require.Regexp(t, `^github.com/kalbasit/old:worker\s+exited \(2\)\s+-$`, lines[3])
+}
+
+func TestLogsCmd(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+
+	_, err := f.up(t)
+	require.NoError(t, err)
+
+	for _, req := range f.sess.started {
+		require.NoError(t, os.MkdirAll(filepath.Dir(req.GetLogPath()), 0o750))
+		require.NoError(t, os.WriteFile(req.GetLogPath(), []byte("one\ntwo\nthree\n"), 0o600))
+	}
+
+	out, err := f.run(t, services.NewLogsCmd(f.cfg, f.store, f.resolver), "-n", "2", "web")
+	require.NoError(t, err)
+	require.Equal(t, "two\nthree\n", out)
+
+	out, err = f.run(t, services.NewLogsCmd(f.cfg, f.store, f.resolver), "-n", "1")
+	require.NoError(t, err)
+	require.Equal(t, "==> "+testProject+":db <==\nthree\n\n==> "+testProject+":web <==\nthree\n", out)
+}
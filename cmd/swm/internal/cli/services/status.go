@@ -0,0 +1,121 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	coreServices "github.com/kalbasit/swm/cmd/swm/internal/core/services"
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+)
+
+// NewStatusCmd returns the `swm services status` command.
+func NewStatusCmd(
+	cfg *config.Config,
+	store coreStory.Store,
+	mgr pluginManager,
+	resolver *layout.Resolver,
+) *cobra.Command {
+	d := newDeps(cfg, store, mgr, resolver)
+
+	var storyName string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the services of a story are running",
+		Long: "Show every service declared by the projects of a story as running, exited (with " +
+			"its exit code) or stopped, with the PID of running services.",
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			d.mgr.Warm(cmd.Context(), "session") //nolint:errcheck,gosec // Warm always returns nil; errors deferred to Get
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			s, declared, err := d.load(ctx, storyName)
+			if err != nil {
+				return err
+			}
+
+			sess, err := d.session(ctx)
+			if err != nil {
+				return err
+			}
+
+			var running []*pluginv1.Service
+
+			ws, err := findWorkspace(ctx, sess, s.Name)
+			if err != nil {
+				return err
+			}
+
+			if ws != nil {
+				running, err = coreServices.List(ctx, sess, ws.GetWorkspaceId())
+				if err != nil {
+					return err
+				}
+			}
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0) //nolint:mnd // column padding
+
+			fmt.Fprintln(tw, "SERVICE\tSTATUS\tPID")
+
+			seen := make(map[string]bool, len(declared))
+
+			for _, svc := range declared {
+				seen[svc.Name] = true
+
+				fmt.Fprintln(tw, statusLine(svc.Name, findService(running, svc.Name)))
+			}
+
+			// Services started from a services file that has since changed.
+			for _, svc := range running {
+				if !seen[svc.GetName()] {
+					fmt.Fprintln(tw, statusLine(svc.GetName(), svc))
+				}
+			}
+
+			if err := tw.Flush(); err != nil {
+				return fmt.Errorf("writing status: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	d.addStoryFlag(cmd, &storyName)
+
+	return cmd
+}
+
+// findService returns the service named name, running or not, or nil.
+func findService(services []*pluginv1.Service, name string) *pluginv1.Service {
+	for _, svc := range services {
+		if svc.GetName() == name {
+			return svc
+		}
+	}
+
+	return nil
+}
+
+// statusLine returns the table row of service name, whose pane is svc (nil
+// when it has none).
+func statusLine(name string, svc *pluginv1.Service) string {
+	switch {
+	case svc == nil:
+		return name + "\tstopped\t-"
+	case svc.GetRunning():
+		return name + "\trunning\t" + strconv.FormatInt(svc.GetPid(), 10)
+	default:
+		return name + "\texited (" + strconv.Itoa(int(svc.GetExitCode())) + ")\t-"
+	}
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	coreServices "github.com/kalbasit/swm/cmd/swm/internal/core/services"
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/ports"
+)
+
+// NewUpCmd returns the `swm services up` command.
+func NewUpCmd(
+	cfg *config.Config,
+	store coreStory.Store,
+	mgr pluginManager,
+	resolver *layout.Resolver,
+) *cobra.Command {
+	d := newDeps(cfg, store, mgr, resolver)
+
+	var storyName string
+
+	cmd := &cobra.Command{
+		Use:   "up [<service>...]",
+		Short: "Start the services of a story",
+		Long: "Start the services of a story, or only the named ones (host/owner/repo:name or " +
+			"name) and the services they depend on, in dependency order. The story's " +
+			"workspace is opened if needed; services that are already running are left alone.",
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			d.mgr.Warm(cmd.Context(), "session") //nolint:errcheck,gosec // Warm always returns nil; errors deferred to Get
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			s, all, err := d.load(ctx, storyName)
+			if err != nil {
+				return err
+			}
+
+			selected, err := coreServices.Select(all, args)
+			if err != nil {
+				return err
+			}
+
+			if len(selected) == 0 {
+				cmd.Printf("no services defined for story %q\n", s.Name)
+
+				return nil
+			}
+
+			sess, err := d.session(ctx)
+			if err != nil {
+				return err
+			}
+
+			worktreePaths := make(map[string]string, len(s.Projects))
+
+			for _, p := range s.Projects {
+				id := &pluginv1.ProjectID{Host: p.Host, Segments: p.Segments}
+				worktreePaths[p.Host+"/"+strings.Join(p.Segments, "/")] = d.resolver.WorktreePath(s.Name, id)
+			}
+
+			env, err := ports.StoryEnvironment(d.resolver, s, "", d.cfg.HistfilePath(s.Name), d.cfg.Ports.BlockSize)
+			if err != nil {
+				return fmt.Errorf("reading named ports: %w", err)
+			}
+
+			ws, err := sess.OpenWorkspace(ctx, &pluginv1.OpenWorkspaceRequest{
+				StoryName:     s.Name,
+				WorktreePaths: worktreePaths,
+				Env:           env,
+			})
+			if err != nil {
+				return fmt.Errorf("opening workspace: %w", err)
+			}
+
+			running, err := coreServices.List(ctx, sess, ws.GetWorkspaceId())
+			if err != nil {
+				return err
+			}
+
+			for _, svc := range selected {
+				if r := findRunning(running, svc.Name); r != nil {
+					cmd.Printf("%s already running (pid %d)\n", svc.Name, r.GetPid())
+
+					continue
+				}
+
+				env, err := serviceEnv(d.cfg, d.resolver, s, svc)
+				if err != nil {
+					return err
+				}
+
+				started, err := sess.StartService(ctx, &pluginv1.StartServiceRequest{
+					WorkspaceId: ws.GetWorkspaceId(),
+					Name:        svc.Name,
+					Argv:        svc.Argv(),
+					Env:         env,
+					WorkDir:     svc.WorkDir(),
+					LogPath:     svc.LogPath(d.logDir(s.Name)),
+				})
+				if err != nil {
+					return fmt.Errorf("starting service %q: %w", svc.Name, err)
+				}
+
+				cmd.Printf("started %s (pid %d)\n", svc.Name, started.GetPid())
+			}
+
+			return nil
+		},
+	}
+
+	d.addStoryFlag(cmd, &storyName)
+
+	return cmd
+}
+
+// findRunning returns the running service named name, or nil.
+func findRunning(services []*pluginv1.Service, name string) *pluginv1.Service {
+	for _, svc := range services {
+		if svc.GetName() == name && svc.GetRunning() {
+			return svc
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,351 @@
+// Package status contains the `swm status` command, which renders a short
+// prompt segment for the current story from the on-disk status cache.
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/statuscache"
+)
+
+// DefaultFormat is the template used when --format is not given. It renders,
+// for example, "my-story github.com/kalbasit/swm feat/my-story* #12 open pending".
+const DefaultFormat = "{{.Story}}" +
+	"{{with .Project}} {{.}}{{end}}" +
+	"{{with .Branch}} {{.}}{{if $.Dirty}}*{{end}}{{end}}" +
+	"{{with .PR.Number}} #{{.}} {{$.PR.State}}{{with $.PR.Checks}} {{.}}{{end}}{{end}}"
+
+// pluginManager is the subset of the plugin manager used by --refresh.
+type pluginManager interface {
+	GetForge(ctx context.Context, hostname string) (pluginv1.ForgeClient, error)
+	GetVCS(ctx context.Context, name string) (pluginv1.VCSClient, error)
+	Warm(ctx context.Context, capabilities ...string) error
+}
+
+// Segment is the data the --format template is evaluated with.
+type Segment struct {
+	Story   string
+	Project string
+	Branch  string
+	Dirty   bool
+	Ahead   int32
+	Behind  int32
+	PR      PR
+}
+
+// PR is the cached pull request state of the current project. Fields are
+// zero when no pull request is cached, so templates can reference them
+// unconditionally.
+type PR struct {
+	Number int64
+	State  string
+	URL    string
+	Checks string
+}
+
+// NewStatusCmd returns the `swm status` command.
+func NewStatusCmd(
+	cfg *config.Config, store coreStory.Store, mgr pluginManager, resolver *layout.Resolver,
+) *cobra.Command {
+	var (
+		format    string
+		storyName string
+		refresh   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Print a prompt segment for the current story and project",
+		Long: "Print a prompt segment for the current story and project, suitable for a tmux " +
+			"status-right, starship custom module or shell prompt. The story is taken from " +
+			"--story, $SWM_STORY or the working directory, and the project from the working " +
+			"directory. Branch, dirty marker and pull request state come from an on-disk cache so the command never launches a plugin unless " +
+			"--refresh is given. Outside any story it prints nothing.\n\n" +
+			"The --format template is evaluated with .Story, .Project, .Branch, .Dirty, " +
+			".Ahead, .Behind and .PR (.Number, .State, .URL, .Checks).",
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			if refresh {
+				//nolint:errcheck,gosec // Warm always returns nil; errors deferred to Get
+				mgr.Warm(cmd.Context(), "vcs")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			tmpl, err := template.New("status").Parse(format)
+			if err != nil {
+				return fmt.Errorf("parsing --format: %w", err)
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("getting working directory: %w", err)
+			}
+
+			if storyName == "" {
+				storyName = currentStoryName(resolver, cwd)
+			}
+
+			if storyName == "" {
+				return nil
+			}
+
+			cache := statuscache.New(cfg.CacheHome)
+
+			s, err := store.Get(ctx, storyName)
+			if err != nil {
+				// A stale $SWM_STORY still gets a segment with just the story name.
+				slog.DebugContext(ctx, "loading story for status", "story", storyName, "err", err)
+
+				s = &coreStory.Story{Name: storyName}
+			}
+
+			if refresh {
+				if err := refreshCache(ctx, mgr, resolver, cache, s); err != nil {
+					return err
+				}
+			}
+
+			entries, err := cache.Load(storyName)
+			if err != nil {
+				return err
+			}
+
+			return render(cmd.OutOrStdout(), tmpl, buildSegment(resolver, s, entries, cwd))
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", DefaultFormat, "Go template for the segment")
+	cmd.Flags().StringVarP(&storyName, "story", "s", "", "story name (default: detected)")
+	cmd.Flags().BoolVar(&refresh, "refresh", false,
+		"query the VCS and forge plugins and update the cache before printing")
+
+	return cmd
+}
+
+// currentStoryName detects the story from $SWM_STORY, which session plugins
+// set in a story's workspace, then from cwd. Returns "" when neither matches.
+func currentStoryName(resolver *layout.Resolver, cwd string) string {
+	if name := os.Getenv("SWM_STORY"); name != "" {
+		return name
+	}
+
+	return resolver.StoryNameFromPath(cwd)
+}
+
+// buildSegment assembles the template data for story s, picking the attached
+// project whose worktree contains cwd.
+func buildSegment(resolver *layout.Resolver, s *coreStory.Story, entries statuscache.Entries, cwd string) Segment {
+	seg := Segment{Story: s.Name}
+
+	proj := projectAt(resolver, s, cwd)
+	if proj == nil {
+		return seg
+	}
+
+	seg.Project = statuscache.ProjectKey(proj.Host, proj.Segments)
+	seg.Branch = s.BranchName
+
+	entry, ok := entries[seg.Project]
+	if !ok {
+		return seg
+	}
+
+	if entry.Branch != "" {
+		seg.Branch = entry.Branch
+	}
+
+	seg.Dirty, seg.Ahead, seg.Behind = entry.Dirty, entry.Ahead, entry.Behind
+
+	if entry.PR != nil {
+		seg.PR = PR(*entry.PR)
+	}
+
+	return seg
+}
+
+// projectAt returns the attached project with the deepest worktree that
+// contains cwd, or nil when cwd is outside every worktree of the story.
+func projectAt(resolver *layout.Resolver, s *coreStory.Story, cwd string) *coreStory.Project {
+	var (
+		best    *coreStory.Project
+		bestLen int
+	)
+
+	for i := range s.Projects {
+		p := &s.Projects[i]
+		wt := resolver.WorktreePath(s.Name, &pluginv1.ProjectID{Host: p.Host, Segments: p.Segments})
+
+		if cwd != wt && !strings.HasPrefix(cwd, wt+string(filepath.Separator)) {
+			continue
+		}
+
+		if len(wt) > bestLen {
+			best, bestLen = p, len(wt)
+		}
+	}
+
+	return best
+}
+
+// render writes the segment followed by a newline, or nothing at all when
+// the template renders to an empty string.
+func render(w io.Writer, tmpl *template.Template, seg Segment) error {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, seg); err != nil {
+		return fmt.Errorf("rendering --format: %w", err)
+	}
+
+	if b.Len() == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintln(w, b.String()); err != nil {
+		return fmt.Errorf("writing status: %w", err)
+	}
+
+	return nil
+}
+
+// refreshCache queries each attached project's VCS plugin for its worktree and
+// the project's forge for the pull request of its branch, then stores the
+// results. A project whose VCS plugin or worktree is unavailable keeps its
+// previously cached entry, and one whose forge fails keeps its cached pull
+// request; the failures are logged and do not stop the other projects.
+func refreshCache(
+	ctx context.Context,
+	mgr pluginManager,
+	resolver *layout.Resolver,
+	cache *statuscache.Cache,
+	s *coreStory.Story,
+) error {
+	if len(s.Projects) == 0 {
+		return nil
+	}
+
+	fresh := make(statuscache.Entries, len(s.Projects))
+	// stalePR holds the projects whose forge failed, which keep their cached
+	// pull request.
+	stalePR := make(map[string]bool)
+
+	for _, p := range s.Projects {
+		id := &pluginv1.ProjectID{Host: p.Host, Segments: p.Segments}
+		key := statuscache.ProjectKey(p.Host, p.Segments)
+
+		vcs, err := mgr.GetVCS(ctx, p.VCS)
+		if err != nil {
+			slog.WarnContext(ctx, "getting VCS plugin for status", "project", key, "err", err)
+
+			continue
+		}
+
+		st, err := vcs.GetWorktreeStatus(ctx, &pluginv1.WorktreeStatusRequest{
+			WorktreePath: resolver.WorktreePath(s.Name, id),
+		})
+		if err != nil {
+			if grpcstatus.Code(err) != codes.NotFound {
+				slog.WarnContext(ctx, "getting worktree status", "project", key, "err", err)
+			}
+
+			continue
+		}
+
+		entry := &statuscache.Entry{
+			Branch:    st.GetBranch(),
+			Dirty:     st.GetDirty(),
+			Ahead:     st.GetAhead(),
+			Behind:    st.GetBehind(),
+			UpdatedAt: time.Now().UTC(),
+		}
+
+		pr, err := branchPR(ctx, mgr, id, entry.Branch)
+		if err != nil {
+			slog.WarnContext(ctx, "getting pull request for status", "project", key, "err", err)
+
+			stalePR[key] = true
+		}
+
+		entry.PR = pr
+		fresh[key] = entry
+	}
+
+	return cache.Update(s.Name, func(entries statuscache.Entries) {
+		for key, entry := range fresh {
+			if old, ok := entries[key]; ok && stalePR[key] {
+				entry.PR = old.PR
+			}
+
+			entries[key] = entry
+		}
+	})
+}
+
+// branchPR returns the most recent pull request whose head is branch, with
+// its check state. It returns nil when the project has no forge or no pull
+// request exists for the branch.
+func branchPR(
+	ctx context.Context, mgr pluginManager, id *pluginv1.ProjectID, branch string,
+) (*statuscache.PR, error) {
+	if branch == "" {
+		return nil, nil
+	}
+
+	forge, err := mgr.GetForge(ctx, id.GetHost())
+	if err != nil {
+		return nil, nil //nolint:nilerr // no forge configured for this host
+	}
+
+	stream, err := forge.ListPullRequests(ctx, &pluginv1.ListPRsRequest{
+		ProjectId:     id,
+		State:         pluginv1.PullRequestFilter_PULL_REQUEST_FILTER_ALL,
+		HeadBranch:    branch,
+		IncludeChecks: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing pull requests: %w", err)
+	}
+
+	var latest *pluginv1.PullRequest
+
+	for {
+		pr, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("receiving pull request: %w", err)
+		}
+
+		if pr.GetHeadBranch() == branch && (latest == nil || pr.GetNumber() > latest.GetNumber()) {
+			latest = pr
+		}
+	}
+
+	if latest == nil {
+		return nil, nil
+	}
+
+	return statuscache.PRFromProto(latest), nil
+}
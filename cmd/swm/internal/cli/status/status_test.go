@@ -0,0 +1,376 @@
+package status_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcstatus "google.golang.org/grpc/status"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/status"
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/statuscache"
+)
+
+const (
+	testStory      = "feat-x"
+	testBranch     = "feat/feat-x"
+	testDefault    = "_default"
+	testHost       = "github.com"
+	testProjectKey = "github.com/o/r"
+)
+
+var errNoPlugin = errors.New("no plugin")
+
+// env bundles a story store, resolver and cache rooted in temp dirs, with a
+// story attached to one project whose worktree exists on disk.
+type env struct {
+	cfg      *config.Config
+	store    coreStory.Store
+	resolver *layout.Resolver
+	worktree string
+}
+
+func newEnv(t *testing.T) *env {
+	t.Helper()
+
+	codeRoot := t.TempDir()
+	store := coreStory.NewJSONStore(t.TempDir())
+	resolver := layout.NewResolver(codeRoot, testDefault)
+
+	s, err := store.Create(context.Background(), testStory, testBranch)
+	require.NoError(t, err)
+
+	s.Projects = []coreStory.Project{{Host: testHost, Segments: []string{"o", "r"}}}
+	require.NoError(t, store.Update(context.Background(), s))
+
+	worktree := resolver.WorktreePath(testStory, &pluginv1.ProjectID{Host: testHost, Segments: []string{"o", "r"}})
+	require.NoError(t, os.MkdirAll(filepath.Join(worktree, "sub"), 0o750))
+
+	return &env{
+		cfg:      &config.Config{DefaultStory: testDefault, CacheHome: t.TempDir()},
+		store:    store,
+		resolver: resolver,
+		worktree: worktree,
+	}
+}
+
+func (e *env) run(t *testing.T, mgr *stubMgr, args ...string) (string, error) {
+	t.Helper()
+
+	if mgr == nil {
+		mgr = &stubMgr{}
+	}
+
+	var out bytes.Buffer
+
+	cmd := status.NewStatusCmd(e.cfg, e.store, mgr, e.resolver)
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+// clearStoryEnv unsets the variable the story is detected from.
+func clearStoryEnv(t *testing.T) {
+	t.Helper()
+
+	t.Setenv("SWM_STORY", "")
+}
+
+//nolint:paralleltest // t.Chdir is incompatible with t.Parallel
+func TestStatus_DefaultFormatFromCache(t *testing.T) {
+	// No t.Parallel(): uses t.Setenv and t.Chdir.
+	e := newEnv(t)
+	clearStoryEnv(t)
+	t.Setenv("SWM_STORY", testStory)
+	t.Chdir(filepath.Join(e.worktree, "sub"))
+
+	require.NoError(t, statuscache.New(e.cfg.CacheHome).Update(testStory, func(entries statuscache.Entries) {
+		entries[testProjectKey] = &statuscache.Entry{
+			Branch: testBranch,
+			Dirty:  true,
+			PR:     &statuscache.PR{Number: 2, State: "open", Checks: "pending"},
+		}
+	}))
+
+	out, err := e.run(t, nil)
+	require.NoError(t, err)
+	require.Equal(t, "feat-x github.com/o/r feat/feat-x* #2 open pending\n", out)
+}
+
+//nolint:paralleltest // t.Chdir is incompatible with t.Parallel
+func TestStatus_CustomFormatWithoutCache(t *testing.T) {
+	// No t.Parallel(): uses t.Setenv and t.Chdir.
+	e := newEnv(t)
+	clearStoryEnv(t)
+	t.Setenv("SWM_STORY", testStory)
+	t.Chdir(e.worktree)
+
+	out, err := e.run(t, nil, "--format", "{{.Story}}|{{.Project}}|{{.Branch}}|{{.PR.State}}")
+	require.NoError(t, err)
+	require.Equal(t, "feat-x|github.com/o/r|feat/feat-x|\n", out)
+}
+
+//nolint:paralleltest // t.Chdir is incompatible with t.Parallel
+func TestStatus_StoryFromWorkingDirectory(t *testing.T) {
+	// No t.Parallel(): uses t.Setenv and t.Chdir.
+	e := newEnv(t)
+	clearStoryEnv(t)
+	t.Chdir(e.worktree)
+
+	out, err := e.run(t, nil, "--format", "{{.Story}} {{.Project}}")
+	require.NoError(t, err)
+	require.Equal(t, "feat-x github.com/o/r\n", out)
+}
+
+//nolint:paralleltest // t.Chdir is incompatible with t.Parallel
+func TestStatus_OutsideAnyStoryPrintsNothing(t *testing.T) {
+	// No t.Parallel(): uses t.Setenv and t.Chdir.
+	e := newEnv(t)
+	clearStoryEnv(t)
+	// A story's tmux server is recognised by the $SWM_STORY it sets, not by
+	// its socket.
+	t.Setenv("TMUX", "/run/user/1000/swm/tmux/"+testStory+".sock,1234,0")
+	t.Chdir(t.TempDir())
+
+	out, err := e.run(t, nil)
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
+
+//nolint:paralleltest // t.Chdir is incompatible with t.Parallel
+func TestStatus_InvalidFormat(t *testing.T) {
+	// No t.Parallel(): uses t.Setenv and t.Chdir.
+	e := newEnv(t)
+	clearStoryEnv(t)
+	t.Chdir(e.worktree)
+
+	_, err := e.run(t, nil, "--format", "{{.Story")
+	require.ErrorContains(t, err, "parsing --format")
+}
+
+//nolint:paralleltest // t.Chdir is incompatible with t.Parallel
+func TestStatus_RefreshUpdatesCache(t *testing.T) {
+	// No t.Parallel(): uses t.Setenv and t.Chdir.
+	e := newEnv(t)
+	clearStoryEnv(t)
+	t.Chdir(e.worktree)
+
+	forge := &stubForge{prs: []*pluginv1.PullRequest{
+		{
+			Number:     5,
+			HeadBranch: testBranch,
+			State:      pluginv1.PullRequestState_PULL_REQUEST_STATE_MERGED,
+			CheckState: pluginv1.CheckState_CHECK_STATE_SUCCESS,
+		},
+	}}
+	mgr := &stubMgr{
+		vcs:    &stubVCS{status: &pluginv1.WorktreeStatus{Branch: testBranch, Ahead: 1}},
+		forges: map[string]pluginv1.ForgeClient{testHost: forge},
+	}
+
+	out, err := e.run(t, mgr, "--refresh")
+	require.NoError(t, err)
+	require.Equal(t, "feat-x github.com/o/r feat/feat-x #5 merged success\n", out)
+
+	require.Equal(t, e.worktree, mgr.vcs.lastReq.GetWorktreePath())
+	require.Equal(t, testBranch, forge.lastReq.GetHeadBranch())
+	require.True(t, forge.lastReq.GetIncludeChecks())
+	require.Equal(t, pluginv1.PullRequestFilter_PULL_REQUEST_FILTER_ALL, forge.lastReq.GetState())
+
+	entries, err := statuscache.New(e.cfg.CacheHome).Load(testStory)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), entries[testProjectKey].Ahead)
+	require.Equal(t, int64(5), entries[testProjectKey].PR.Number)
+}
+
+//nolint:paralleltest // t.Chdir is incompatible with t.Parallel
+func TestStatus_RefreshUsesProjectVCS(t *testing.T) {
+	// No t.Parallel(): uses t.Setenv and t.Chdir.
+	e := newEnv(t)
+	clearStoryEnv(t)
+	t.Chdir(e.worktree)
+
+	s, err := e.store.Get(context.Background(), testStory)
+	require.NoError(t, err)
+
+	s.Projects[0].VCS = "jj"
+	require.NoError(t, e.store.Update(context.Background(), s))
+
+	jj := &stubVCS{status: &pluginv1.WorktreeStatus{Branch: testBranch, Dirty: true}}
+	mgr := &stubMgr{namedVCS: map[string]*stubVCS{"jj": jj}}
+
+	out, err := e.run(t, mgr, "--refresh")
+	require.NoError(t, err)
+	require.Equal(t, "feat-x github.com/o/r feat/feat-x*\n", out)
+	require.Equal(t, e.worktree, jj.lastReq.GetWorktreePath())
+}
+
+//nolint:paralleltest // t.Chdir is incompatible with t.Parallel
+func TestStatus_RefreshSkipsMissingWorktree(t *testing.T) {
+	// No t.Parallel(): uses t.Setenv and t.Chdir.
+	e := newEnv(t)
+	clearStoryEnv(t)
+	t.Chdir(e.worktree)
+
+	mgr := &stubMgr{vcs: &stubVCS{err: grpcstatus.Error(codes.NotFound, "gone")}}
+
+	out, err := e.run(t, mgr, "--refresh")
+	require.NoError(t, err)
+	require.Equal(t, "feat-x github.com/o/r feat/feat-x\n", out)
+}
+
+//nolint:paralleltest // t.Chdir is incompatible with t.Parallel
+func TestStatus_RefreshKeepsGoingWhenForgeFails(t *testing.T) {
+	// No t.Parallel(): uses t.Setenv and t.Chdir.
+	e := newEnv(t)
+	clearStoryEnv(t)
+	t.Chdir(e.worktree)
+
+	s, err := e.store.Get(context.Background(), testStory)
+	require.NoError(t, err)
+
+	s.Projects = append(s.Projects, coreStory.Project{Host: "gitlab.com", Segments: []string{"o", "r"}})
+	require.NoError(t, e.store.Update(context.Background(), s))
+
+	cache := statuscache.New(e.cfg.CacheHome)
+	require.NoError(t, cache.Update(testStory, func(entries statuscache.Entries) {
+		entries[testProjectKey] = &statuscache.Entry{Branch: testBranch, PR: &statuscache.PR{Number: 3, State: "open"}}
+	}))
+
+	gitlab := &stubForge{prs: []*pluginv1.PullRequest{
+		{Number: 9, HeadBranch: testBranch, State: pluginv1.PullRequestState_PULL_REQUEST_STATE_OPEN},
+	}}
+	mgr := &stubMgr{
+		vcs: &stubVCS{status: &pluginv1.WorktreeStatus{Branch: testBranch, Ahead: 2}},
+		forges: map[string]pluginv1.ForgeClient{
+			testHost:     &stubForge{err: grpcstatus.Error(codes.Unauthenticated, "bad token")},
+			"gitlab.com": gitlab,
+		},
+	}
+
+	out, err := e.run(t, mgr, "--refresh")
+	require.NoError(t, err)
+	require.Equal(t, "feat-x github.com/o/r feat/feat-x #3 open\n", out)
+
+	// The failing forge's project keeps its pull request but gets the fresh
+	// worktree status, and the other project is still refreshed.
+	entries, err := cache.Load(testStory)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), entries[testProjectKey].Ahead)
+	require.Equal(t, int64(3), entries[testProjectKey].PR.Number)
+	require.Equal(t, int64(9), entries["gitlab.com/o/r"].PR.Number)
+}
+
+// stubMgr implements the status command's pluginManager for tests.
+type stubMgr struct {
+	vcs      *stubVCS
+	namedVCS map[string]*stubVCS
+	forges   map[string]pluginv1.ForgeClient
+}
+
+func (m *stubMgr) GetForge(_ context.Context, hostname string) (pluginv1.ForgeClient, error) {
+	if f, ok := m.forges[hostname]; ok {
+		return f, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", errNoPlugin, hostname)
+}
+
+func (m *stubMgr) GetVCS(_ context.Context, name string) (pluginv1.VCSClient, error) {
+	if v, ok := m.namedVCS[name]; ok {
+		return v, nil
+	}
+
+	if name == "" && m.vcs != nil {
+		return m.vcs, nil
+	}
+
+	return nil, fmt.Errorf("%w: vcs %q", errNoPlugin, name)
+}
+
+func (m *stubMgr) Warm(context.Context, ...string) error { return nil }
+
+// stubVCS embeds the client interface and only implements GetWorktreeStatus.
+type stubVCS struct {
+	pluginv1.VCSClient
+
+	status  *pluginv1.WorktreeStatus
+	err     error
+	lastReq *pluginv1.WorktreeStatusRequest
+}
+
+func (v *stubVCS) GetWorktreeStatus(
+	_ context.Context,
+	req *pluginv1.WorktreeStatusRequest,
+	_ ...grpc.CallOption,
+) (*pluginv1.WorktreeStatus, error) {
+	v.lastReq = req
+
+	return v.status, v.err
+}
+
+// stubForge embeds the client interface and only implements ListPullRequests.
+type stubForge struct {
+	pluginv1.ForgeClient
+
+	prs     []*pluginv1.PullRequest
+	err     error
+	lastReq *pluginv1.ListPRsRequest
+}
+
+func (f *stubForge) ListPullRequests(
+	_ context.Context,
+	req *pluginv1.ListPRsRequest,
+	_ ...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.PullRequest], error) {
+	f.lastReq = req
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &stubPRStream{prs: f.prs}, nil
+}
+
+// stubPRStream is a grpc.ServerStreamingClient[PullRequest] that returns a fixed list.
+type stubPRStream struct {
+	prs []*pluginv1.PullRequest
+	idx int
+}
+
+func (s *stubPRStream) CloseSend() error             { return nil }
+func (s *stubPRStream) Context() context.Context     { return context.Background() }
+func (s *stubPRStream) Header() (metadata.MD, error) { panic("stub") }
+
+func (s *stubPRStream) Recv() (*pluginv1.PullRequest, error) {
+	if s.idx >= len(s.prs) {
+		return nil, io.EOF
+	}
+
+	pr := s.prs[s.idx]
+	s.idx++
+
+	return pr, nil
+}
+
+func (s *stubPRStream) RecvMsg(any) error    { panic("stub") }
+func (s *stubPRStream) SendMsg(any) error    { panic("stub") }
+func (s *stubPRStream) Trailer() metadata.MD { return nil }
@@ -89,19 +89,19 @@ func attachProject(
 		return fmt.Errorf("loading story %q: %w", name, err)
 	}
 
-	raw, err := mgr.Get(ctx, "vcs")
+	cwd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("loading vcs plugin: %w", err)
+		return fmt.Errorf("determining working directory: %w", err)
 	}
 
-	vcs, ok := raw.(pluginv1.VCSClient)
-	if !ok {
-		return fmt.Errorf("%w: %T", errUnexpectedPluginType, raw)
+	vcsName, err := mgr.VCSForPath(ctx, cwd)
+	if err != nil {
+		return fmt.Errorf("routing %s to a vcs plugin: %w", cwd, err)
 	}
 
-	cwd, err := os.Getwd()
+	vcs, err := mgr.GetVCS(ctx, vcsName)
 	if err != nil {
-		return fmt.Errorf("determining working directory: %w", err)
+		return fmt.Errorf("loading vcs plugin: %w", err)
 	}
 
 	pid, err := vcs.DetectProjectAtPath(ctx, &pluginv1.DetectAtPathRequest{Path: cwd})
@@ -127,7 +127,7 @@ func attachProject(
 	// nothing is being created, so create hooks do not run. The default story is
 	// excluded because its worktree path is the always-present canonical checkout.
 	if name != defaultStory && worktreeExists(worktreePath) {
-		if err := attachToStore(ctx, store, st, pid); err != nil {
+		if err := attachToStore(ctx, store, st, pid, vcsName); err != nil {
 			return err
 		}
 
@@ -163,14 +163,14 @@ func attachProject(
 			// existence check above and this call. If a worktree is now present,
 			// reconcile the bookkeeping instead of failing.
 			if worktreeExists(worktreePath) {
-				return attachToStore(ctx, store, st, pid)
+				return attachToStore(ctx, store, st, pid, vcsName)
 			}
 
 			return fmt.Errorf("creating worktree: %w", err)
 		}
 	}
 
-	if err := attachToStore(ctx, store, st, pid); err != nil {
+	if err := attachToStore(ctx, store, st, pid, vcsName); err != nil {
 		return err
 	}
 
@@ -192,18 +192,21 @@ func attachProject(
 	return nil
 }
 
-// attachToStore appends the project to the story and persists it. A concurrent
-// attach that already recorded the project (ErrProjectAlreadyAttached) is
-// treated as success so the command stays idempotent.
+// attachToStore appends the project, handled by the named vcs plugin, to the
+// story and persists it. A concurrent attach that already recorded the project
+// (ErrProjectAlreadyAttached) is treated as success so the command stays
+// idempotent.
 func attachToStore(
 	ctx context.Context,
 	store coreStory.Store,
 	st *coreStory.Story,
 	pid *pluginv1.ProjectID,
+	vcsName string,
 ) error {
 	st.Projects = append(st.Projects, coreStory.Project{
 		Host:     pid.GetHost(),
 		Segments: pid.GetSegments(),
+		VCS:      vcsName,
 	})
 
 	if err := store.Update(ctx, st); err != nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
 	"github.com/kalbasit/swm/cmd/swm/internal/cli/story"
 	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
@@ -246,6 +247,26 @@ func TestAttachCmd_CreatesWorktree(t *testing.T) {
 	require.Equal(t, testKalbasitOrg+"/"+testSWMRepo, hooks.cfgs["pre-worktree-create"].ProjectPath)
 }
 
+func TestAttachCmd_RoutesToProjectVCS(t *testing.T) {
+	t.Setenv("SWM_STORY", "")
+
+	store := &stubStore{getStory: swmProjectStory(testStoryName)}
+	resolver := layout.NewResolver(t.TempDir(), defaultStoryName)
+	git := &stubVCSClient{}
+	jj := &stubVCSClient{}
+	mgr := &stubManager{vcs: git, namedVCS: map[string]pluginv1.VCSClient{"jj": jj}, pathVCS: "jj"}
+
+	cmd := newAttachCmd(t, store, mgr, resolver, &recordingHooks{})
+	cmd.SetArgs([]string{testStoryName})
+
+	require.NoError(t, cmd.Execute())
+
+	require.False(t, git.createWorktreeCalled)
+	require.True(t, jj.createWorktreeCalled)
+	require.Len(t, store.updatedStory.Projects, 1)
+	require.Equal(t, "jj", store.updatedStory.Projects[0].VCS)
+}
+
 func TestAttachCmd_Reconcile_WorktreeExists(t *testing.T) {
 	t.Setenv("SWM_STORY", "")
 
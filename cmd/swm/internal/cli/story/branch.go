@@ -2,36 +2,108 @@ package story
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"os/user"
+	"strings"
 	"text/template"
+	"time"
+	"unicode"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
 	"github.com/kalbasit/swm/cmd/swm/internal/config"
 )
 
-var errEmptyBranchName = errors.New("branch_name_template produced an empty branch name")
+var (
+	errEmptyBranchName   = errors.New("branch_name_template produced an empty branch name")
+	errInvalidBranchName = errors.New("invalid branch name")
+	errInvalidVar        = errors.New("invalid --var, expected key=value")
+)
 
-// branchNameData is the template data available in branch_name_template.
-type branchNameData struct {
+// BranchNameData is the template data available in branch_name_template.
+type BranchNameData struct {
+	// Name is the story name.
 	Name string
+	// IssueKey and Title describe the tracker issue the story is created
+	// from; both are empty for other stories.
+	IssueKey string
+	Title    string
+	// User is the operating system user name.
+	User string
+	// GitName and GitEmail are the VCS user identity, empty when unset.
+	GitName  string
+	GitEmail string
+	// Date is the current date as YYYY-MM-DD.
+	Date string
+	// Vars holds the --var key=value pairs given to `swm story create`.
+	Vars map[string]string
 }
 
-// BranchFromTemplate evaluates tpl as a Go text/template with .Name set to
-// storyName and returns the result. An empty tpl uses the default template
-// "feat/{{.Name}}". Returns an error if the template is syntactically invalid
-// or evaluates to an empty string.
+// BranchPluginManager is the subset of the plugin manager ResolveBranch uses.
+type BranchPluginManager interface {
+	Get(ctx context.Context, capability string) (any, error)
+}
+
+// NewBranchNameData returns the template data for storyName with the OS user
+// and the current date filled in.
+func NewBranchNameData(storyName string) BranchNameData {
+	return BranchNameData{
+		Name: storyName,
+		User: currentUser(),
+		Date: time.Now().Format(time.DateOnly),
+	}
+}
+
+// branchFuncs is the function map available in branch_name_template. String
+// arguments come last so the functions can be used in pipelines, e.g.
+// {{.Title | slug | truncate 30}}.
+var branchFuncs = template.FuncMap{ //nolint:gochecknoglobals // read-only function map
+	"slug":  slugify,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"truncate": func(n int, s string) string {
+		r := []rune(s)
+		if n < 0 || len(r) <= n {
+			return s
+		}
+
+		return string(r[:n])
+	},
+	"replace": func(old, replacement, s string) string {
+		return strings.ReplaceAll(s, old, replacement)
+	},
+}
+
+// BranchFromTemplate evaluates tpl with the data of NewBranchNameData for
+// storyName. See RenderBranchName.
 func BranchFromTemplate(tpl, storyName string) (string, error) {
+	return RenderBranchName(tpl, NewBranchNameData(storyName))
+}
+
+// RenderBranchName evaluates tpl as a Go text/template over data with the
+// slug, lower, upper, truncate and replace functions and returns the result.
+// An empty tpl uses the default template "feat/{{.Name}}". Returns an error if
+// the template is syntactically invalid, references a missing --var or
+// evaluates to an empty string.
+func RenderBranchName(tpl string, data BranchNameData) (string, error) {
 	if tpl == "" {
 		tpl = config.DefaultBranchNameTemplate
 	}
 
-	t, err := template.New("branch").Parse(tpl)
+	t, err := template.New("branch").Funcs(branchFuncs).Option("missingkey=error").Parse(tpl)
 	if err != nil {
 		return "", fmt.Errorf("invalid branch_name_template: %w", err)
 	}
 
 	var buf bytes.Buffer
-	if err := t.Execute(&buf, branchNameData{Name: storyName}); err != nil {
+	if err := t.Execute(&buf, data); err != nil {
 		return "", fmt.Errorf("evaluating branch_name_template: %w", err)
 	}
 
@@ -42,3 +114,107 @@ func BranchFromTemplate(tpl, storyName string) (string, error) {
 
 	return result, nil
 }
+
+// parseVars parses --var key=value flags.
+func parseVars(raw []string) (map[string]string, error) {
+	vars := make(map[string]string, len(raw))
+
+	for _, kv := range raw {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("%w: %q", errInvalidVar, kv)
+		}
+
+		vars[k] = v
+	}
+
+	return vars, nil
+}
+
+// ResolveBranch returns branch, or the branch rendered from tpl over data
+// when branch is empty, after validating it through the VCS plugin. Every
+// command creating a story names its branch through it.
+func ResolveBranch(
+	ctx context.Context, mgr BranchPluginManager, tpl, branch string, data BranchNameData,
+) (string, error) {
+	raw, err := mgr.Get(ctx, "vcs")
+	if err != nil {
+		return "", fmt.Errorf("loading vcs plugin: %w", err)
+	}
+
+	vcs, ok := raw.(pluginv1.VCSClient)
+	if !ok {
+		return "", fmt.Errorf("%w: %T", errUnexpectedPluginType, raw)
+	}
+
+	if branch == "" {
+		branch, err = RenderBranchName(tpl, withVCSIdentity(ctx, vcs, data))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if err := validateBranchName(ctx, vcs, branch); err != nil {
+		return "", err
+	}
+
+	return branch, nil
+}
+
+// withVCSIdentity fills data.GitName and data.GitEmail from the VCS plugin. A
+// failure is logged and leaves them empty: the template may not use them.
+func withVCSIdentity(ctx context.Context, vcs pluginv1.VCSClient, data BranchNameData) BranchNameData {
+	id, err := vcs.GetUserIdentity(ctx, &pluginv1.UserIdentityRequest{})
+	if err != nil {
+		slog.DebugContext(ctx, "reading vcs user identity", "err", err)
+
+		return data
+	}
+
+	data.GitName = id.GetName()
+	data.GitEmail = id.GetEmail()
+
+	return data
+}
+
+// validateBranchName asks the VCS plugin whether branch is a valid branch
+// name. Plugins that predate ValidateBranchName are not consulted.
+func validateBranchName(ctx context.Context, vcs pluginv1.VCSClient, branch string) error {
+	_, err := vcs.ValidateBranchName(ctx, &pluginv1.ValidateBranchNameRequest{BranchName: branch})
+	if err == nil || status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+
+	if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+		return fmt.Errorf("%w: %s", errInvalidBranchName, st.Message())
+	}
+
+	return fmt.Errorf("validating branch name %q: %w", branch, err)
+}
+
+// currentUser returns the OS user name, falling back to $USER.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+
+	return os.Getenv("USER")
+}
+
+// slugify lower-cases s and collapses every run of characters other than
+// ASCII letters and digits into a single "-", trimming leading and trailing
+// dashes: "Fix: login timeout!" becomes "fix-login-timeout".
+func slugify(s string) string {
+	var slug strings.Builder
+
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			slug.WriteRune(r)
+		case slug.Len() > 0 && !strings.HasSuffix(slug.String(), "-"):
+			slug.WriteByte('-')
+		}
+	}
+
+	return strings.Trim(slug.String(), "-")
+}
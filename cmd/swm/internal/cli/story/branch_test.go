@@ -2,6 +2,7 @@ package story_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -79,3 +80,82 @@ func TestBranchFromTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderBranchName(t *testing.T) {
+	t.Parallel()
+
+	data := story.BranchNameData{
+		Name:     "PROJ-7-rotate-keys",
+		IssueKey: "PROJ-7",
+		Title:    "Rotate the Deploy Keys!",
+		User:     "jdoe",
+		GitName:  "Jane Doe",
+		GitEmail: "jane@example.com",
+		Date:     "2026-10-19",
+		Vars:     map[string]string{"team": "Platform"},
+	}
+
+	tests := []struct {
+		name    string
+		tpl     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "user and issue with slug",
+			tpl:  "users/{{.User}}/{{.IssueKey}}-{{slug .Title}}",
+			want: "users/jdoe/PROJ-7-rotate-the-deploy-keys",
+		},
+		{
+			name: "date prefix",
+			tpl:  "{{.Date}}/{{.Name}}",
+			want: "2026-10-19/PROJ-7-rotate-keys",
+		},
+		{
+			name: "git identity",
+			tpl:  `{{.GitName | slug}}/{{replace "@example.com" "" .GitEmail}}`,
+			want: "jane-doe/jane",
+		},
+		{
+			name: "vars with lower and upper",
+			tpl:  "{{lower .Vars.team}}/{{upper .Name}}",
+			want: "platform/PROJ-7-ROTATE-KEYS",
+		},
+		{
+			name: "truncate in a pipeline",
+			tpl:  "feat/{{.Title | slug | truncate 10}}",
+			want: "feat/rotate-the",
+		},
+		{
+			name:    "missing var",
+			tpl:     "{{.Vars.owner}}/{{.Name}}",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := story.RenderBranchName(tc.tpl, data)
+			if tc.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNewBranchNameData(t *testing.T) {
+	t.Parallel()
+
+	data := story.NewBranchNameData(testStoryName)
+
+	require.Equal(t, testStoryName, data.Name)
+	require.Equal(t, time.Now().Format(time.DateOnly), data.Date)
+	require.NotEmpty(t, data.User)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 
 	"github.com/spf13/cobra"
 
@@ -18,6 +19,18 @@ import (
 // does not abort (the story was already created successfully).
 func CreateWithHooks(
 	ctx context.Context, store coreStory.Store, hooks hookexec.Runner, codeRoot, name, branch string,
+) error {
+	return createWithHooks(ctx, store, hooks, codeRoot, name, branch, nil)
+}
+
+// createWithHooks is CreateWithHooks that also records metadata on the story
+// before the post-story-create hooks run.
+func createWithHooks(
+	ctx context.Context,
+	store coreStory.Store,
+	hooks hookexec.Runner,
+	codeRoot, name, branch string,
+	metadata map[string]any,
 ) error {
 	preCfg := hookexec.RunConfig{
 		Event:     "pre-story-create",
@@ -29,10 +42,23 @@ func CreateWithHooks(
 		return fmt.Errorf("pre-story-create hook: %w", err)
 	}
 
-	if _, err := store.Create(ctx, name, branch); err != nil {
+	st, err := store.Create(ctx, name, branch)
+	if err != nil {
 		return fmt.Errorf("creating story %q: %w", name, err)
 	}
 
+	if len(metadata) > 0 {
+		if st.Metadata == nil {
+			st.Metadata = make(map[string]any, len(metadata))
+		}
+
+		maps.Copy(st.Metadata, metadata)
+
+		if err := store.Update(ctx, st); err != nil {
+			return fmt.Errorf("recording metadata on story %q: %w", name, err)
+		}
+	}
+
 	postCfg := hookexec.RunConfig{
 		Event:     "post-story-create",
 		CodeRoot:  codeRoot,
@@ -49,40 +75,95 @@ func CreateWithHooks(
 // NewCreateCmd returns the `swm story create` command.
 func NewCreateCmd(
 	store coreStory.Store,
+	mgr pluginManager,
 	codeRoot string,
 	hooks hookexec.Runner,
 	branchNameTemplate string,
 ) *cobra.Command {
-	var branch string
+	var (
+		branch    string
+		fromIssue bool
+		rawVars   []string
+	)
 
 	cmd := &cobra.Command{
-		Use:   "create <name>",
+		Use:   "create <name> | create --from-issue [<issue-key>]",
 		Short: "Create a new story",
-		Args:  cobra.ExactArgs(1),
+		Long: "Create a new story. With --from-issue, the argument is an issue key " +
+			"(e.g. PROJ-123) looked up through the tracker plugin: the story is named " +
+			"<key>-<title>, its branch is derived from that name, and the issue is " +
+			"recorded in the story metadata. Without a key, your open issues are " +
+			"offered in the picker.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if fromIssue {
+				return cobra.MaximumNArgs(1)(cmd, args)
+			}
+
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			caps := []string{"vcs"}
+			if fromIssue {
+				caps = append(caps, "tracker")
+			}
+
+			if fromIssue && len(args) == 0 {
+				caps = append(caps, "picker")
+			}
+
+			mgr.Warm(cmd.Context(), caps...) //nolint:errcheck,gosec // Warm always returns nil; errors deferred to Get
+
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name := args[0]
 			ctx := cmd.Context()
 
-			if branch == "" {
-				derived, err := BranchFromTemplate(branchNameTemplate, name)
-				if err != nil {
+			vars, err := parseVars(rawVars)
+			if err != nil {
+				return err
+			}
+
+			var (
+				data     BranchNameData
+				metadata map[string]any
+			)
+
+			if fromIssue {
+				issue, err := resolveIssue(ctx, cmd, mgr, args)
+				if err != nil || issue == nil {
 					return err
 				}
 
-				branch = derived
+				data = NewBranchNameData(issueStoryName(issue))
+				data.IssueKey = issue.GetKey()
+				data.Title = issue.GetTitle()
+				metadata = map[string]any{coreStory.MetadataIssue: issueMetadata(issue)}
+			} else {
+				data = NewBranchNameData(args[0])
+			}
+
+			data.Vars = vars
+
+			storyBranch, err := ResolveBranch(ctx, mgr, branchNameTemplate, branch, data)
+			if err != nil {
+				return err
 			}
 
-			if err := CreateWithHooks(ctx, store, hooks, codeRoot, name, branch); err != nil {
+			if err := createWithHooks(ctx, store, hooks, codeRoot, data.Name, storyBranch, metadata); err != nil {
 				return err
 			}
 
-			cmd.Printf("created story %q with branch %q\n", name, branch)
+			cmd.Printf("created story %q with branch %q\n", data.Name, storyBranch)
 
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&branch, "branch", "", "branch name (default: derived from config branch_name_template)")
+	cmd.Flags().BoolVar(&fromIssue, "from-issue", false,
+		"create the story from a tracker issue key, or pick one of your open issues")
+	cmd.Flags().StringArrayVar(&rawVars, "var", nil,
+		"set a key=value variable available as {{.Vars.key}} in branch_name_template (repeatable)")
 
 	return cmd
 }
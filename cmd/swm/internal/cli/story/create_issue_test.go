@@ -0,0 +1,270 @@
+package story_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/story"
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/hookexec"
+)
+
+const testIssueKey = "PROJ-123"
+
+func testIssue() *pluginv1.Issue {
+	return &pluginv1.Issue{
+		Key:    testIssueKey,
+		Title:  "Fix the login timeout (again!)",
+		Status: "To Do",
+		Url:    "https://example.atlassian.net/browse/PROJ-123",
+	}
+}
+
+func TestCreateCmd_FromIssueKey(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{}
+	tracker := &stubTracker{issue: testIssue()}
+
+	var postHookMetadata map[string]any
+
+	hooks := hookexec.RunnerFunc(func(_ context.Context, cfg hookexec.RunConfig) error {
+		if cfg.Event == eventPostStoryCreate && store.updatedStory != nil {
+			postHookMetadata = store.updatedStory.Metadata
+		}
+
+		return nil
+	})
+
+	mgr := &stubManager{vcs: &stubVCSClient{}, tracker: tracker}
+
+	cmd := story.NewCreateCmd(store, mgr, "/code", hooks, config.DefaultBranchNameTemplate)
+	cmd.SetArgs([]string{"--from-issue", testIssueKey})
+
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, testIssueKey, tracker.lastKey)
+	require.Equal(t, "PROJ-123-fix-the-login-timeout-again", store.lastCreatedName)
+	require.Equal(t, "feat/PROJ-123-fix-the-login-timeout-again", store.lastCreatedBranch)
+	require.Equal(t, map[string]any{
+		"key":   testIssueKey,
+		"title": "Fix the login timeout (again!)",
+		"url":   "https://example.atlassian.net/browse/PROJ-123",
+	}, store.updatedStory.Metadata[coreStory.MetadataIssue])
+	require.NotNil(t, postHookMetadata, "issue metadata must be recorded before post-story-create hooks run")
+}
+
+func TestCreateCmd_FromIssueBranchOverride(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{}
+	mgr := &stubManager{vcs: &stubVCSClient{}, tracker: &stubTracker{issue: testIssue()}}
+
+	cmd := story.NewCreateCmd(store, mgr, "", hookexec.Noop, config.DefaultBranchNameTemplate)
+	cmd.SetArgs([]string{"--from-issue", testIssueKey, "--branch", "PROJ-123"})
+
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "PROJ-123", store.lastCreatedBranch)
+}
+
+func TestCreateCmd_FromIssueLongTitleTruncated(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{}
+	mgr := &stubManager{vcs: &stubVCSClient{}, tracker: &stubTracker{issue: &pluginv1.Issue{
+		Key:   "OPS-7",
+		Title: "Rotate every credential used by the deployment pipeline before the quarterly audit begins",
+	}}}
+
+	cmd := story.NewCreateCmd(store, mgr, "", hookexec.Noop, config.DefaultBranchNameTemplate)
+	cmd.SetArgs([]string{"--from-issue", "OPS-7"})
+
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "OPS-7-rotate-every-credential-used-by-the-deployment", store.lastCreatedName)
+}
+
+func TestCreateCmd_FromIssuePicker(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{}
+	picker := &stubIssuePicker{selected: "1"}
+	mgr := &stubManager{
+		vcs: &stubVCSClient{},
+		tracker: &stubTracker{issues: []*pluginv1.Issue{
+			{Key: "PROJ-1", Title: "First"},
+			{Key: "PROJ-2", Title: "Second", Status: "In Progress"},
+		}},
+		picker: picker,
+	}
+
+	cmd := story.NewCreateCmd(store, mgr, "", hookexec.Noop, config.DefaultBranchNameTemplate)
+	cmd.SetArgs([]string{"--from-issue"})
+
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "PROJ-2-second", store.lastCreatedName)
+	require.Equal(t, []string{"PROJ-1  First", "PROJ-2  Second  [In Progress]"}, picker.stream.displays)
+}
+
+func TestCreateCmd_FromIssuePickerCancelled(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{}
+	mgr := &stubManager{
+		vcs:     &stubVCSClient{},
+		tracker: &stubTracker{issues: []*pluginv1.Issue{{Key: "PROJ-1", Title: "First"}}},
+		picker:  &stubIssuePicker{cancel: true},
+	}
+
+	cmd := story.NewCreateCmd(store, mgr, "", hookexec.Noop, config.DefaultBranchNameTemplate)
+	cmd.SetArgs([]string{"--from-issue"})
+
+	require.NoError(t, cmd.Execute())
+	require.Empty(t, store.lastCreatedName)
+}
+
+func TestCreateCmd_FromIssueNoOpenIssues(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{}
+	mgr := &stubManager{vcs: &stubVCSClient{}, tracker: &stubTracker{}, picker: &stubIssuePicker{}}
+
+	var out bytes.Buffer
+
+	cmd := story.NewCreateCmd(store, mgr, "", hookexec.Noop, config.DefaultBranchNameTemplate)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--from-issue"})
+
+	require.NoError(t, cmd.Execute())
+	require.Empty(t, store.lastCreatedName)
+	require.Equal(t, "no open issues assigned to you\n", out.String())
+}
+
+func TestCreateCmd_FromIssueWithoutPicker(t *testing.T) {
+	t.Parallel()
+
+	mgr := &stubManager{vcs: &stubVCSClient{}, tracker: &stubTracker{}}
+
+	cmd := story.NewCreateCmd(&stubStore{}, mgr, "", hookexec.Noop, config.DefaultBranchNameTemplate)
+	cmd.SetArgs([]string{"--from-issue"})
+
+	require.ErrorContains(t, cmd.Execute(), "requires a picker plugin")
+}
+
+func TestCreateCmd_FromIssueWithoutTracker(t *testing.T) {
+	t.Parallel()
+
+	cmd := story.NewCreateCmd(&stubStore{}, newCreateManager(), "", hookexec.Noop, config.DefaultBranchNameTemplate)
+	cmd.SetArgs([]string{"--from-issue", testIssueKey})
+
+	require.ErrorContains(t, cmd.Execute(), "loading tracker plugin")
+}
+
+func TestCreateCmd_WithoutFromIssueRequiresName(t *testing.T) {
+	t.Parallel()
+
+	cmd := story.NewCreateCmd(&stubStore{}, newCreateManager(), "", hookexec.Noop, config.DefaultBranchNameTemplate)
+	cmd.SetArgs(nil)
+
+	require.Error(t, cmd.Execute())
+}
+
+// stubTracker embeds the client interface and implements GetIssue and ListMyIssues.
+type stubTracker struct {
+	pluginv1.TrackerClient
+
+	issue   *pluginv1.Issue
+	issues  []*pluginv1.Issue
+	lastKey string
+}
+
+func (s *stubTracker) GetIssue(
+	_ context.Context,
+	req *pluginv1.GetIssueRequest,
+	_ ...grpc.CallOption,
+) (*pluginv1.Issue, error) {
+	s.lastKey = req.GetKey()
+
+	return s.issue, nil
+}
+
+func (s *stubTracker) ListMyIssues(
+	_ context.Context,
+	_ *pluginv1.ListMyIssuesRequest,
+	_ ...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.Issue], error) {
+	return &stubIssueStream{issues: s.issues}, nil
+}
+
+// stubIssueStream embeds the stream interface and only implements Recv.
+type stubIssueStream struct {
+	grpc.ServerStreamingClient[pluginv1.Issue]
+
+	issues []*pluginv1.Issue
+}
+
+func (s *stubIssueStream) Recv() (*pluginv1.Issue, error) {
+	if len(s.issues) == 0 {
+		return nil, io.EOF
+	}
+
+	issue := s.issues[0]
+	s.issues = s.issues[1:]
+
+	return issue, nil
+}
+
+// stubIssuePicker embeds the client interface and only implements Pick.
+type stubIssuePicker struct {
+	pluginv1.PickerClient
+
+	selected string
+	cancel   bool
+	stream   *stubIssuePickStream
+}
+
+func (p *stubIssuePicker) Pick(
+	context.Context, ...grpc.CallOption,
+) (grpc.BidiStreamingClient[pluginv1.PickItem, pluginv1.PickResult], error) {
+	p.stream = &stubIssuePickStream{selected: p.selected, cancel: p.cancel}
+
+	return p.stream, nil
+}
+
+// stubIssuePickStream records the displayed items and returns the configured selection.
+type stubIssuePickStream struct {
+	grpc.BidiStreamingClient[pluginv1.PickItem, pluginv1.PickResult]
+
+	selected string
+	cancel   bool
+	displays []string
+}
+
+func (s *stubIssuePickStream) Send(item *pluginv1.PickItem) error {
+	if item.GetKey() != strconv.Itoa(len(s.displays)) {
+		return status.Error(codes.InvalidArgument, "unexpected key")
+	}
+
+	s.displays = append(s.displays, item.GetDisplay())
+
+	return nil
+}
+
+func (s *stubIssuePickStream) CloseSend() error { return nil }
+
+func (s *stubIssuePickStream) Recv() (*pluginv1.PickResult, error) {
+	if s.cancel {
+		return nil, status.Error(codes.Aborted, "cancelled")
+	}
+
+	return &pluginv1.PickResult{Key: s.selected}, nil
+}
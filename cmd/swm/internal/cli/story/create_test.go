@@ -6,8 +6,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
 	"github.com/kalbasit/swm/cmd/swm/internal/cli/story"
 	"github.com/kalbasit/swm/cmd/swm/internal/config"
@@ -100,11 +103,16 @@ func TestCreateWithHooks_PostHookError(t *testing.T) {
 	require.Equal(t, testStoryName, store.lastCreatedName)
 }
 
+// newCreateManager returns a manager whose VCS accepts every branch name.
+func newCreateManager() *stubManager {
+	return &stubManager{vcs: &stubVCSClient{}}
+}
+
 func TestCreateCmd_BasicCreation(t *testing.T) {
 	t.Parallel()
 
 	store := &stubStore{}
-	cmd := story.NewCreateCmd(store, "", hookexec.Noop, config.DefaultBranchNameTemplate)
+	cmd := story.NewCreateCmd(store, newCreateManager(), "", hookexec.Noop, config.DefaultBranchNameTemplate)
 
 	cmd.SetArgs([]string{testStoryName})
 	require.NoError(t, cmd.Execute())
@@ -116,7 +124,7 @@ func TestCreateCmd_CustomBranch(t *testing.T) {
 	t.Parallel()
 
 	store := &stubStore{}
-	cmd := story.NewCreateCmd(store, "", hookexec.Noop, config.DefaultBranchNameTemplate)
+	cmd := story.NewCreateCmd(store, newCreateManager(), "", hookexec.Noop, config.DefaultBranchNameTemplate)
 
 	cmd.SetArgs([]string{"JIRA-42", "--branch", "fix/JIRA-42-crash"})
 	require.NoError(t, cmd.Execute())
@@ -128,7 +136,7 @@ func TestCreateCmd_TemplateFromConfig(t *testing.T) {
 	t.Parallel()
 
 	store := &stubStore{}
-	cmd := story.NewCreateCmd(store, "", hookexec.Noop, "fix/{{.Name}}")
+	cmd := story.NewCreateCmd(store, newCreateManager(), "", hookexec.Noop, "fix/{{.Name}}")
 
 	cmd.SetArgs([]string{testBugName})
 	require.NoError(t, cmd.Execute())
@@ -140,7 +148,7 @@ func TestCreateCmd_BranchFlagOverridesTemplate(t *testing.T) {
 	t.Parallel()
 
 	store := &stubStore{}
-	cmd := story.NewCreateCmd(store, "", hookexec.Noop, "fix/{{.Name}}")
+	cmd := story.NewCreateCmd(store, newCreateManager(), "", hookexec.Noop, "fix/{{.Name}}")
 
 	cmd.SetArgs([]string{testBugName, "--branch", "custom/branch"})
 	require.NoError(t, cmd.Execute())
@@ -151,7 +159,7 @@ func TestCreateCmd_InvalidTemplateErrors(t *testing.T) {
 	t.Parallel()
 
 	store := &stubStore{}
-	cmd := story.NewCreateCmd(store, "", hookexec.Noop, "{{.Name")
+	cmd := story.NewCreateCmd(store, newCreateManager(), "", hookexec.Noop, "{{.Name")
 
 	cmd.SetArgs([]string{testStoryName})
 	err := cmd.Execute()
@@ -164,7 +172,7 @@ func TestCreateCmd_EmptyTemplateUsesDefault(t *testing.T) {
 	t.Parallel()
 
 	store := &stubStore{}
-	cmd := story.NewCreateCmd(store, "", hookexec.Noop, "")
+	cmd := story.NewCreateCmd(store, newCreateManager(), "", hookexec.Noop, "")
 
 	cmd.SetArgs([]string{testStoryName})
 	require.NoError(t, cmd.Execute())
@@ -184,7 +192,7 @@ func TestCreateCmd_InvalidTemplate_NoHooksRun(t *testing.T) {
 		return nil
 	})
 
-	cmd := story.NewCreateCmd(store, "/code", captureHook, "{{.Name")
+	cmd := story.NewCreateCmd(store, newCreateManager(), "/code", captureHook, "{{.Name")
 	cmd.SetArgs([]string{testStoryName})
 
 	require.Error(t, cmd.Execute())
@@ -195,7 +203,7 @@ func TestCreateCmd_Duplicate(t *testing.T) {
 	t.Parallel()
 
 	store := &stubStore{createErr: coreStory.ErrStoryExists}
-	cmd := story.NewCreateCmd(store, "", hookexec.Noop, config.DefaultBranchNameTemplate)
+	cmd := story.NewCreateCmd(store, newCreateManager(), "", hookexec.Noop, config.DefaultBranchNameTemplate)
 
 	cmd.SetArgs([]string{testStoryName})
 	require.Error(t, cmd.Execute())
@@ -217,7 +225,7 @@ func TestCreateCmd_PreHookAborts(t *testing.T) {
 		return nil
 	})
 
-	cmd := story.NewCreateCmd(store, "/code", captureHook, config.DefaultBranchNameTemplate)
+	cmd := story.NewCreateCmd(store, newCreateManager(), "/code", captureHook, config.DefaultBranchNameTemplate)
 	cmd.SetArgs([]string{testStoryName})
 
 	err := cmd.Execute()
@@ -240,9 +248,69 @@ func TestCreateCmd_HooksCalledInOrder(t *testing.T) {
 		return nil
 	})
 
-	cmd := story.NewCreateCmd(store, "/code", captureHook, config.DefaultBranchNameTemplate)
+	cmd := story.NewCreateCmd(store, newCreateManager(), "/code", captureHook, config.DefaultBranchNameTemplate)
 	cmd.SetArgs([]string{testStoryName})
 
 	require.NoError(t, cmd.Execute())
 	require.Equal(t, []string{eventPreStoryCreate, eventPostStoryCreate}, called)
 }
+
+func TestCreateCmd_TemplateUsesVarsAndVCSIdentity(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{}
+	vcs := &stubVCSClient{identity: &pluginv1.UserIdentity{Name: "Jane Doe", Email: "jane@example.com"}}
+	cmd := story.NewCreateCmd(store, &stubManager{vcs: vcs}, "", hookexec.Noop,
+		"{{.Vars.team}}/{{slug .GitName}}/{{.Name}}")
+
+	cmd.SetArgs([]string{testStoryName, "--var", "team=infra"})
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "infra/jane-doe/"+testStoryName, store.lastCreatedBranch)
+	require.Equal(t, []string{"infra/jane-doe/" + testStoryName}, vcs.validatedBranches)
+}
+
+func TestCreateCmd_InvalidVarErrors(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{}
+	cmd := story.NewCreateCmd(store, newCreateManager(), "", hookexec.Noop, config.DefaultBranchNameTemplate)
+
+	cmd.SetArgs([]string{testStoryName, "--var", "team"})
+	require.ErrorContains(t, cmd.Execute(), "invalid --var")
+	require.Empty(t, store.lastCreatedName)
+}
+
+func TestCreateCmd_InvalidBranchRejectedBeforeStoreWrite(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{}
+	vcs := &stubVCSClient{validateErr: status.Error(codes.InvalidArgument, `"bad..name" is not a valid branch name`)}
+
+	var hooksCalled []string
+
+	captureHook := hookexec.RunnerFunc(func(_ context.Context, cfg hookexec.RunConfig) error {
+		hooksCalled = append(hooksCalled, cfg.Event)
+
+		return nil
+	})
+
+	cmd := story.NewCreateCmd(store, &stubManager{vcs: vcs}, "/code", captureHook, config.DefaultBranchNameTemplate)
+	cmd.SetArgs([]string{testStoryName, "--branch", "bad..name"})
+
+	require.ErrorContains(t, cmd.Execute(), "invalid branch name")
+	require.Equal(t, []string{"bad..name"}, vcs.validatedBranches)
+	require.Empty(t, store.lastCreatedName)
+	require.Empty(t, hooksCalled)
+}
+
+func TestCreateCmd_ValidationUnimplementedIsSkipped(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{}
+	vcs := &stubVCSClient{validateErr: status.Error(codes.Unimplemented, "method ValidateBranchName not implemented")}
+	cmd := story.NewCreateCmd(store, &stubManager{vcs: vcs}, "", hookexec.Noop, config.DefaultBranchNameTemplate)
+
+	cmd.SetArgs([]string{testStoryName})
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "feat/"+testStoryName, store.lastCreatedBranch)
+}
@@ -0,0 +1,242 @@
+package story
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+)
+
+var (
+	errEnvNoStory     = errors.New("story name required: pass --story, set $SWM_STORY or run inside a story")
+	errEnvAssignment  = errors.New("invalid assignment, expected KEY=VALUE")
+	errEnvInvalidKey  = errors.New("invalid environment variable name")
+	errEnvReservedKey = errors.New("environment variables starting with SWM_ are reserved")
+	errEnvNotAttached = errors.New("project not attached to story")
+)
+
+// envKeyRe matches the environment variable names swm accepts.
+var envKeyRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// envOptions are the flags shared by the `swm story env` sub-commands.
+type envOptions struct {
+	storyName string
+	project   string
+}
+
+// NewEnvCmd returns the `swm story env` command group. The variables it
+// manages are stored with the story and set in its workspace, pane groups,
+// hooks, services and `swm exec` commands.
+func NewEnvCmd(store coreStory.Store, resolver *layout.Resolver) *cobra.Command {
+	var opts envOptions
+
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Manage the environment variables of a story",
+		Long: "Manage the environment variables of a story. They are set in the story's " +
+			"workspace, its pane groups and layout panes, its hooks, its services and the " +
+			"commands run by `swm exec`. With --project, a variable only applies to that " +
+			"attached project (host/owner/repo) and overrides the story-wide value.\n\n" +
+			"The story is taken from --story, $SWM_STORY or the working directory. " +
+			"Changes reach an open tmux server the next time the workspace is opened.",
+	}
+
+	cmd.PersistentFlags().StringVarP(&opts.storyName, "story", "s", "", "story name (default: detected)")
+	cmd.PersistentFlags().StringVar(&opts.project, "project", "",
+		"apply to this attached project only (host/owner/repo)")
+
+	//nolint:errcheck,gosec // RegisterFlagCompletionFunc only fails for unknown flags
+	cmd.RegisterFlagCompletionFunc("story", storyNameCompletion(store))
+
+	cmd.AddCommand(newEnvListCmd(store, resolver, &opts))
+	cmd.AddCommand(newEnvSetCmd(store, resolver, &opts))
+	cmd.AddCommand(newEnvUnsetCmd(store, resolver, &opts))
+
+	return cmd
+}
+
+func newEnvListCmd(store coreStory.Store, resolver *layout.Resolver, opts *envOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Print the environment variables of a story",
+		Long: "Print the environment variables of a story as KEY=VALUE lines. With --project, " +
+			"print the variables that apply to that project, its overrides included.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			s, err := loadEnvStory(cmd.Context(), store, resolver, opts)
+			if err != nil {
+				return err
+			}
+
+			for _, kv := range coreStory.Environ(s.Environment(opts.project, "")) {
+				cmd.Println(kv)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newEnvSetCmd(store coreStory.Store, resolver *layout.Resolver, opts *envOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set KEY=VALUE...",
+		Short: "Set environment variables of a story",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vars := make(map[string]string, len(args))
+
+			for _, arg := range args {
+				key, value, ok := strings.Cut(arg, "=")
+				if !ok {
+					return fmt.Errorf("%w: %q", errEnvAssignment, arg)
+				}
+
+				if err := validateEnvKey(key); err != nil {
+					return err
+				}
+
+				vars[key] = value
+			}
+
+			ctx := cmd.Context()
+
+			s, err := loadEnvStory(ctx, store, resolver, opts)
+			if err != nil {
+				return err
+			}
+
+			env := s.Env
+			if opts.project != "" {
+				env = s.ProjectEnv[opts.project]
+			}
+
+			if env == nil {
+				env = make(map[string]string, len(vars))
+			}
+
+			maps.Copy(env, vars)
+
+			if opts.project == "" {
+				s.Env = env
+			} else {
+				if s.ProjectEnv == nil {
+					s.ProjectEnv = make(map[string]map[string]string)
+				}
+
+				s.ProjectEnv[opts.project] = env
+			}
+
+			if err := store.Update(ctx, s); err != nil {
+				return fmt.Errorf("saving story %q: %w", s.Name, err)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newEnvUnsetCmd(store coreStory.Store, resolver *layout.Resolver, opts *envOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset KEY...",
+		Short: "Remove environment variables from a story",
+		Long: "Remove environment variables from a story. Unsetting a variable that is not " +
+			"set succeeds.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			s, err := loadEnvStory(ctx, store, resolver, opts)
+			if err != nil {
+				return err
+			}
+
+			if opts.project == "" {
+				for _, key := range args {
+					delete(s.Env, key)
+				}
+
+				if len(s.Env) == 0 {
+					s.Env = nil
+				}
+			} else {
+				env := s.ProjectEnv[opts.project]
+				for _, key := range args {
+					delete(env, key)
+				}
+
+				if len(env) == 0 {
+					delete(s.ProjectEnv, opts.project)
+				}
+
+				if len(s.ProjectEnv) == 0 {
+					s.ProjectEnv = nil
+				}
+			}
+
+			if err := store.Update(ctx, s); err != nil {
+				return fmt.Errorf("saving story %q: %w", s.Name, err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// loadEnvStory returns the story the env sub-commands operate on: --story,
+// $SWM_STORY or the story of the working directory. With --project, the
+// project must be attached to it.
+func loadEnvStory(
+	ctx context.Context,
+	store coreStory.Store,
+	resolver *layout.Resolver,
+	opts *envOptions,
+) (*coreStory.Story, error) {
+	name := opts.storyName
+	if name == "" {
+		name = os.Getenv("SWM_STORY")
+	}
+
+	if name == "" {
+		if cwd, err := os.Getwd(); err == nil {
+			name = resolver.StoryNameFromPath(cwd)
+		}
+	}
+
+	if name == "" {
+		return nil, errEnvNoStory
+	}
+
+	s, err := store.Get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("loading story %q: %w", name, err)
+	}
+
+	if opts.project != "" && !projectAttached(s, opts.project) {
+		return nil, fmt.Errorf("%w %q: %s", errEnvNotAttached, name, opts.project)
+	}
+
+	return s, nil
+}
+
+// validateEnvKey rejects names that are not valid environment variable names
+// and the SWM_* variables swm sets itself.
+func validateEnvKey(key string) error {
+	if !envKeyRe.MatchString(key) {
+		return fmt.Errorf("%w: %q", errEnvInvalidKey, key)
+	}
+
+	if strings.HasPrefix(key, "SWM_") {
+		return fmt.Errorf("%w: %q", errEnvReservedKey, key)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package story_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/story"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+)
+
+const testEnvProject = testGitHubHost + "/" + testKalbasitOrg + "/" + testSWMRepo
+
+// runEnvCmd runs `swm story env` with args against store and returns its output.
+func runEnvCmd(t *testing.T, store *stubStore, args ...string) (string, error) {
+	t.Helper()
+
+	cmd := story.NewEnvCmd(store, layout.NewResolver(t.TempDir(), defaultStoryName))
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	var out bytes.Buffer
+
+	cmd.SetOut(&out)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+func TestEnvCmd_SetAndUnset(t *testing.T) {
+	t.Parallel()
+
+	st := &coreStory.Story{Name: testStoryName, Env: map[string]string{"KEEP": "1"}}
+	store := &stubStore{getStory: st}
+
+	_, err := runEnvCmd(t, store, "set", "--story", testStoryName, "AWS_PROFILE=dev", "EMPTY=", "URL=a=b")
+	require.NoError(t, err)
+	require.True(t, store.updateCalled)
+	require.Equal(t, testStoryName, store.lastGetName)
+	require.Equal(t, map[string]string{"KEEP": "1", "AWS_PROFILE": "dev", "EMPTY": "", "URL": "a=b"}, st.Env)
+
+	_, err = runEnvCmd(t, store, "unset", "-s", testStoryName, "KEEP", "AWS_PROFILE", "EMPTY", "URL", "NEVER_SET")
+	require.NoError(t, err)
+	require.Nil(t, st.Env)
+}
+
+func TestEnvCmd_ProjectOverrides(t *testing.T) {
+	t.Parallel()
+
+	st := &coreStory.Story{
+		Name:     testStoryName,
+		Env:      map[string]string{"AWS_PROFILE": "dev", "PORT": "3000"},
+		Projects: []coreStory.Project{{Host: testGitHubHost, Segments: []string{testKalbasitOrg, testSWMRepo}}},
+	}
+	store := &stubStore{getStory: st}
+
+	_, err := runEnvCmd(t, store, "set", "-s", testStoryName, "--project", testEnvProject, "PORT=3001")
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]string{testEnvProject: {"PORT": "3001"}}, st.ProjectEnv)
+
+	out, err := runEnvCmd(t, store, "list", "-s", testStoryName)
+	require.NoError(t, err)
+	require.Equal(t, "AWS_PROFILE=dev\nPORT=3000\n", out)
+
+	out, err = runEnvCmd(t, store, "list", "-s", testStoryName, "--project", testEnvProject)
+	require.NoError(t, err)
+	require.Equal(t, "AWS_PROFILE=dev\nPORT=3001\n", out)
+
+	_, err = runEnvCmd(t, store, "unset", "-s", testStoryName, "--project", testEnvProject, "PORT")
+	require.NoError(t, err)
+	require.Nil(t, st.ProjectEnv)
+}
+
+func TestEnvCmd_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"no assignment", []string{"set", "-s", testStoryName, "FOO"}, "expected KEY=VALUE"},
+		{"invalid key", []string{"set", "-s", testStoryName, "1FOO=x"}, "invalid environment variable name"},
+		{"reserved key", []string{"set", "-s", testStoryName, "SWM_STORY=x"}, "reserved"},
+		{"unattached project", []string{"set", "-s", testStoryName, "--project", "github.com/a/b", "X=1"}, "not attached"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &stubStore{getStory: &coreStory.Story{Name: testStoryName}}
+
+			_, err := runEnvCmd(t, store, tt.args...)
+			require.ErrorContains(t, err, tt.wantErr)
+			require.False(t, store.updateCalled)
+		})
+	}
+}
+
+func TestEnvCmd_StoryFromEnv(t *testing.T) {
+	// Cannot be parallel — sets env vars.
+	t.Setenv("SWM_STORY", testBugName)
+
+	store := &stubStore{getStory: &coreStory.Story{Name: testBugName}}
+
+	_, err := runEnvCmd(t, store, "list")
+	require.NoError(t, err)
+	require.Equal(t, testBugName, store.lastGetName)
+}
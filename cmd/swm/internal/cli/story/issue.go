@@ -0,0 +1,161 @@
+package story
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+)
+
+// maxIssueStoryName caps story names derived from issue titles; the title
+// slug is cut at a word boundary to fit.
+const maxIssueStoryName = 60
+
+var (
+	errIssueNeedsPicker = errors.New("--from-issue without an issue key requires a picker plugin")
+	errUnknownIssueKey  = errors.New("picker returned an unknown issue")
+)
+
+// resolveIssue returns the issue named by args[0], or the issue picked from
+// the user's open issues when args is empty. A cancelled picker or an empty
+// issue list returns nil without error.
+func resolveIssue(ctx context.Context, cmd *cobra.Command, mgr pluginManager, args []string) (*pluginv1.Issue, error) {
+	rawTracker, err := mgr.Get(ctx, "tracker")
+	if err != nil {
+		return nil, fmt.Errorf("loading tracker plugin: %w", err)
+	}
+
+	tracker, ok := rawTracker.(pluginv1.TrackerClient)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errUnexpectedPluginType, rawTracker)
+	}
+
+	if len(args) == 1 {
+		issue, err := tracker.GetIssue(ctx, &pluginv1.GetIssueRequest{Key: args[0]})
+		if err != nil {
+			return nil, fmt.Errorf("getting issue %s: %w", args[0], err)
+		}
+
+		return issue, nil
+	}
+
+	rawPicker, err := mgr.Get(ctx, "picker")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errIssueNeedsPicker, err)
+	}
+
+	picker, ok := rawPicker.(pluginv1.PickerClient)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errUnexpectedPluginType, rawPicker)
+	}
+
+	issues, err := listMyIssues(ctx, tracker)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(issues) == 0 {
+		cmd.Println("no open issues assigned to you")
+
+		return nil, nil //nolint:nilnil // nil issue means there is nothing to create
+	}
+
+	return pickIssue(ctx, picker, issues)
+}
+
+// listMyIssues drains the tracker's ListMyIssues stream.
+func listMyIssues(ctx context.Context, tracker pluginv1.TrackerClient) ([]*pluginv1.Issue, error) {
+	stream, err := tracker.ListMyIssues(ctx, &pluginv1.ListMyIssuesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("listing issues: %w", err)
+	}
+
+	var issues []*pluginv1.Issue
+
+	for {
+		issue, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return issues, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("receiving issue: %w", err)
+		}
+
+		issues = append(issues, issue)
+	}
+}
+
+// pickIssue shows issues in the picker and returns the selected one. A
+// cancelled picker returns nil without error.
+func pickIssue(ctx context.Context, picker pluginv1.PickerClient, issues []*pluginv1.Issue) (*pluginv1.Issue, error) {
+	stream, err := picker.Pick(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting picker: %w", err)
+	}
+
+	for i, issue := range issues {
+		display := issue.GetKey() + "  " + issue.GetTitle()
+		if s := issue.GetStatus(); s != "" {
+			display += "  [" + s + "]"
+		}
+
+		if err := stream.Send(&pluginv1.PickItem{Key: strconv.Itoa(i), Display: display}); err != nil {
+			return nil, fmt.Errorf("sending issue to picker: %w", err)
+		}
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return nil, fmt.Errorf("closing picker send: %w", err)
+	}
+
+	result, err := stream.Recv()
+	if err != nil {
+		if status.Code(err) == codes.Aborted || errors.Is(err, io.EOF) {
+			return nil, nil //nolint:nilnil // nil issue means the picker was cancelled
+		}
+
+		return nil, fmt.Errorf("receiving picker result: %w", err)
+	}
+
+	i, err := strconv.Atoi(result.GetKey())
+	if err != nil || i < 0 || i >= len(issues) {
+		return nil, fmt.Errorf("%q: %w", result.GetKey(), errUnknownIssueKey)
+	}
+
+	return issues[i], nil
+}
+
+// issueStoryName names a story after an issue: its key followed by the
+// lower-cased title with runs of other characters collapsed to "-", e.g.
+// "PROJ-123-fix-login-timeout".
+func issueStoryName(issue *pluginv1.Issue) string {
+	name := issue.GetKey()
+
+	for _, word := range strings.Split(slugify(issue.GetTitle()), "-") {
+		if word == "" || len(name)+1+len(word) > maxIssueStoryName {
+			break
+		}
+
+		name += "-" + word
+	}
+
+	return name
+}
+
+// issueMetadata is the value stored under coreStory.MetadataIssue.
+func issueMetadata(issue *pluginv1.Issue) map[string]any {
+	return map[string]any{
+		"key":   issue.GetKey(),
+		"title": issue.GetTitle(),
+		"url":   issue.GetUrl(),
+	}
+}
@@ -0,0 +1,167 @@
+package story
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/ports"
+)
+
+var errPortBlocksOverlap = errors.New("port blocks overlap")
+
+// NewPortsCmd returns the `swm story ports` command. blockSize is the number
+// of ports allocated to each story.
+func NewPortsCmd(store coreStory.Store, resolver *layout.Resolver, blockSize int) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ports [<story-name>]",
+		Short: "List the port blocks allocated to stories",
+		Long: "List the block of ports allocated to each story (or to the named one), the " +
+			"named ports its projects declare in .swm/project.toml, and the ports of the " +
+			"block that are in use on this machine. Fails when the blocks of two stories " +
+			"overlap.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			stories, err := store.List(ctx)
+			if err != nil {
+				return fmt.Errorf("listing stories: %w", err)
+			}
+
+			shown := stories
+			if len(args) == 1 {
+				shown = slices.DeleteFunc(slices.Clone(stories), func(s *coreStory.Story) bool {
+					return s.Name != args[0]
+				})
+
+				if len(shown) == 0 {
+					return fmt.Errorf("%w: %s", coreStory.ErrStoryNotFound, args[0])
+				}
+			}
+
+			if err := printPorts(cmd, resolver, shown, blockSize); err != nil {
+				return err
+			}
+
+			var overlaps []string
+
+			for _, s := range shown {
+				if s.PortBase == 0 {
+					continue
+				}
+
+				for _, other := range ports.Overlapping(stories, s.Name, s.PortBase, blockSize) {
+					if len(args) == 0 && other.Name < s.Name {
+						continue // reported with other
+					}
+
+					overlaps = append(overlaps, s.Name+" and "+other.Name)
+				}
+			}
+
+			if len(overlaps) > 0 {
+				return fmt.Errorf("%w: %s", errPortBlocksOverlap, strings.Join(overlaps, ", "))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.ValidArgsFunction = storyNameCompletion(store)
+
+	return cmd
+}
+
+// printPorts writes a table of the port block, named ports and ports in use
+// of each of stories to the command's output.
+func printPorts(cmd *cobra.Command, resolver *layout.Resolver, stories []*coreStory.Story, blockSize int) error {
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0) //nolint:mnd // column padding
+
+	fmt.Fprintln(tw, "STORY\tPORTS\tNAMED\tIN USE")
+
+	for _, s := range stories {
+		if s.PortBase == 0 {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\n", s.Name)
+
+			continue
+		}
+
+		named, err := ports.Named(resolver, s, "", blockSize)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: story %q: %v\n", s.Name, err)
+		}
+
+		fmt.Fprintf(tw, "%s\t%d-%d\t%s\t%s\n",
+			s.Name, s.PortBase, s.PortBase+blockSize-1, formatNamed(named), formatInUse(s.PortBase, blockSize))
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("writing ports: %w", err)
+	}
+
+	return nil
+}
+
+// formatNamed renders named ports as name=port pairs sorted by port.
+func formatNamed(named map[string]int) string {
+	if len(named) == 0 {
+		return "-"
+	}
+
+	names := slices.SortedFunc(maps.Keys(named), func(a, b string) int {
+		if named[a] != named[b] {
+			return named[a] - named[b]
+		}
+
+		return strings.Compare(a, b)
+	})
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + "=" + strconv.Itoa(named[name])
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// formatInUse renders the ports of the block of size ports starting at first
+// that cannot be bound on this machine.
+func formatInUse(first, size int) string {
+	var used []string
+
+	for port := first; port < first+size; port++ {
+		if portInUse(port) {
+			used = append(used, strconv.Itoa(port))
+		}
+	}
+
+	if len(used) == 0 {
+		return "-"
+	}
+
+	return strings.Join(used, ",")
+}
+
+// portInUse reports whether port cannot be bound on any address, which is
+// the case when a process is listening on it.
+func portInUse(port int) bool {
+	l, err := net.Listen("tcp", ":"+strconv.Itoa(port)) //nolint:gosec // probing every interface is the point
+	if err != nil {
+		return true
+	}
+
+	_ = l.Close() //nolint:errcheck // the probe listener is discarded
+
+	return false
+}
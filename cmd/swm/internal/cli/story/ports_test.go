@@ -0,0 +1,120 @@
+package story_test
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/story"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+)
+
+// runPortsCmd runs `swm story ports` with args against store and returns its
+// output.
+func runPortsCmd(
+	t *testing.T,
+	store *stubStore,
+	resolver *layout.Resolver,
+	blockSize int,
+	args ...string,
+) (string, error) {
+	t.Helper()
+
+	cmd := story.NewPortsCmd(store, resolver, blockSize)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	var out bytes.Buffer
+
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+func TestPortsCmd_ListsBlocksAndNamedPorts(t *testing.T) {
+	t.Parallel()
+
+	codeRoot := t.TempDir()
+	resolver := layout.NewResolver(codeRoot, defaultStoryName)
+
+	wt := filepath.Join(codeRoot, "stories", testStoryName, testGitHubHost, testKalbasitOrg, testSWMRepo)
+	require.NoError(t, os.MkdirAll(filepath.Join(wt, ".swm"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(wt, ".swm", "project.toml"),
+		[]byte("[ports]\nweb = 2\napi = 1\n"), 0o600))
+
+	store := &stubStore{listStories: []*coreStory.Story{
+		{Name: defaultStoryName},
+		{
+			Name:     testStoryName,
+			PortBase: 40100,
+			Projects: []coreStory.Project{{Host: testGitHubHost, Segments: []string{testKalbasitOrg, testSWMRepo}}},
+		},
+	}}
+
+	out, err := runPortsCmd(t, store, resolver, 100)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, []string{"STORY", "PORTS", "NAMED", "IN", "USE"}, strings.Fields(lines[0]))
+	require.Equal(t, []string{defaultStoryName, "-", "-", "-"}, strings.Fields(lines[1]))
+	require.Equal(t, []string{testStoryName, "40100-40199", "api=40101", "web=40102"}, strings.Fields(lines[2])[:4])
+
+	out, err = runPortsCmd(t, store, resolver, 100, testStoryName)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(out), "\n"), 2)
+
+	_, err = runPortsCmd(t, store, resolver, 100, "missing")
+	require.ErrorIs(t, err, coreStory.ErrStoryNotFound)
+}
+
+func TestPortsCmd_ReportsPortsInUse(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", ":0") //nolint:gosec // the port must be taken on every interface
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = l.Close() }) //nolint:errcheck // test cleanup
+
+	port := l.Addr().(*net.TCPAddr).Port //nolint:forcetypeassert // a tcp listener has a TCP address
+	store := &stubStore{listStories: []*coreStory.Story{{Name: testStoryName, PortBase: port}}}
+
+	out, err := runPortsCmd(t, store, layout.NewResolver(t.TempDir(), defaultStoryName), 1)
+	require.NoError(t, err)
+
+	p := strconv.Itoa(port)
+	require.Equal(t, []string{testStoryName, p + "-" + p, "-", p}, strings.Fields(strings.Split(out, "\n")[1]))
+}
+
+func TestPortsCmd_Overlap(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{listStories: []*coreStory.Story{
+		{Name: "alpha", PortBase: 40100},
+		{Name: "beta", PortBase: 40150},
+		{Name: "gamma", PortBase: 40300},
+	}}
+	resolver := layout.NewResolver(t.TempDir(), defaultStoryName)
+
+	_, err := runPortsCmd(t, store, resolver, 100)
+	require.ErrorContains(t, err, "port blocks overlap: alpha and beta")
+	require.NotContains(t, err.Error(), "beta and alpha")
+
+	_, err = runPortsCmd(t, store, resolver, 100, "beta")
+	require.ErrorContains(t, err, "port blocks overlap: beta and alpha")
+
+	_, err = runPortsCmd(t, store, resolver, 100, "gamma")
+	require.NoError(t, err)
+}
@@ -0,0 +1,126 @@
+package story
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/hookexec"
+)
+
+// PrunePluginManager adds forge lookup to pluginManager for pruning review
+// stories.
+type PrunePluginManager interface {
+	pluginManager
+	GetForge(ctx context.Context, hostname string) (pluginv1.ForgeClient, error)
+}
+
+// NewPruneCmd returns the `swm story prune` command.
+func NewPruneCmd(
+	store coreStory.Store,
+	mgr PrunePluginManager,
+	resolver *layout.Resolver,
+	hooks hookexec.Runner,
+) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove ephemeral review stories whose pull request is closed",
+		Long: "Remove ephemeral review stories whose pull request is closed or merged. " +
+			"Stories become ephemeral when opened with `swm workspace open --pr <ref> --ephemeral`, " +
+			"which also prunes the other closed reviews. Run it from a timer or a hook to clean up " +
+			"reviews without opening a new one.",
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			mgr.Warm(cmd.Context(), "vcs", "session") //nolint:errcheck,gosec // Warm always returns nil; errors deferred to Get
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return PruneClosed(cmd.Context(), cmd, store, mgr, resolver, hooks, dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the stories that would be removed")
+
+	return cmd
+}
+
+// PruneClosed removes the ephemeral stories, other than those named in keep,
+// whose pull request is closed or merged, reporting each on cmd. With dryRun
+// it only reports them.
+func PruneClosed(
+	ctx context.Context,
+	cmd *cobra.Command,
+	store coreStory.Store,
+	mgr PrunePluginManager,
+	resolver *layout.Resolver,
+	hooks hookexec.Runner,
+	dryRun bool,
+	keep ...string,
+) error {
+	stories, err := store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing stories: %w", err)
+	}
+
+	var errs []error
+
+	for _, st := range stories {
+		if !st.Ephemeral || st.PullRequest == nil || slices.Contains(keep, st.Name) {
+			continue
+		}
+
+		closed, err := pullRequestClosed(ctx, mgr, st.PullRequest)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("story %q: %w", st.Name, err))
+
+			continue
+		}
+
+		if !closed {
+			continue
+		}
+
+		if dryRun {
+			cmd.Printf("would remove story %q\n", st.Name)
+
+			continue
+		}
+
+		if err := removeStory(ctx, cmd, st.Name, st, mgr, store, resolver, hooks); err != nil {
+			errs = append(errs, fmt.Errorf("story %q: %w", st.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// pullRequestClosed asks the forge whether pr is closed or merged.
+func pullRequestClosed(ctx context.Context, mgr PrunePluginManager, pr *coreStory.PullRequest) (bool, error) {
+	forge, err := mgr.GetForge(ctx, pr.Host)
+	if err != nil {
+		return false, fmt.Errorf("loading forge for %s: %w", pr.Host, err)
+	}
+
+	remote, err := forge.GetPullRequest(ctx, &pluginv1.GetPRRequest{
+		ProjectId: &pluginv1.ProjectID{Host: pr.Host, Segments: pr.Segments},
+		Number:    pr.Number,
+	})
+	if err != nil {
+		return false, fmt.Errorf("getting pull request #%d: %w", pr.Number, err)
+	}
+
+	state := remote.GetState()
+
+	return state == pluginv1.PullRequestState_PULL_REQUEST_STATE_CLOSED ||
+		state == pluginv1.PullRequestState_PULL_REQUEST_STATE_MERGED, nil
+}
@@ -0,0 +1,151 @@
+package story_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/story"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/hookexec"
+)
+
+func TestPruneCmd_RemovesClosedEphemeralStories(t *testing.T) {
+	t.Parallel()
+
+	store, forge := newPruneFixture(t)
+
+	var out bytes.Buffer
+
+	cmd := story.NewPruneCmd(store, &pruneManager{stubManager: &stubManager{}, forge: forge},
+		layout.NewResolver("/code", "_default"), hookexec.Noop)
+	cmd.SetOut(&out)
+	cmd.SetArgs(nil)
+
+	require.NoError(t, cmd.Execute())
+
+	requireStories(t, store, "swm-pr-1", "swm-pr-3", "swm-pr-4")
+	require.ElementsMatch(t, []int64{1, 2, 4}, forge.requested,
+		"only ephemeral stories with a pull request are checked")
+}
+
+func TestPruneCmd_DryRunKeepsStories(t *testing.T) {
+	t.Parallel()
+
+	store, forge := newPruneFixture(t)
+
+	var out bytes.Buffer
+
+	cmd := story.NewPruneCmd(store, &pruneManager{stubManager: &stubManager{}, forge: forge},
+		layout.NewResolver("/code", "_default"), hookexec.Noop)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--dry-run"})
+
+	require.NoError(t, cmd.Execute())
+
+	requireStories(t, store, "swm-pr-1", "swm-pr-2", "swm-pr-3", "swm-pr-4")
+	require.Equal(t, "would remove story \"swm-pr-2\"\n", out.String())
+}
+
+func TestPruneCmd_NoForgeReportsError(t *testing.T) {
+	t.Parallel()
+
+	store, _ := newPruneFixture(t)
+
+	cmd := story.NewPruneCmd(store, &pruneManager{stubManager: &stubManager{}},
+		layout.NewResolver("/code", "_default"), hookexec.Noop)
+	cmd.SetArgs(nil)
+
+	require.ErrorContains(t, cmd.Execute(), "loading forge for github.com")
+	requireStories(t, store, "swm-pr-1", "swm-pr-2", "swm-pr-3", "swm-pr-4")
+}
+
+// newPruneFixture stores four review stories: #1 ephemeral and open, #2
+// ephemeral and merged, #3 closed but not ephemeral, #4 ephemeral and open.
+func newPruneFixture(t *testing.T) (coreStory.Store, *stubPruneForge) {
+	t.Helper()
+
+	store := coreStory.NewJSONStore(t.TempDir())
+	reviews := []struct {
+		number    int64
+		ephemeral bool
+	}{{1, true}, {2, true}, {3, false}, {4, true}}
+
+	for _, r := range reviews {
+		st, err := store.Create(context.Background(), fmt.Sprintf("swm-pr-%d", r.number), "feat")
+		require.NoError(t, err)
+
+		st.Ephemeral = r.ephemeral
+		st.PullRequest = &coreStory.PullRequest{
+			Host:     testGitHubHost,
+			Segments: []string{testKalbasitOrg, testSWMRepo},
+			Number:   r.number,
+		}
+		require.NoError(t, store.Update(context.Background(), st))
+	}
+
+	forge := &stubPruneForge{states: map[int64]pluginv1.PullRequestState{
+		1: pluginv1.PullRequestState_PULL_REQUEST_STATE_OPEN,
+		2: pluginv1.PullRequestState_PULL_REQUEST_STATE_MERGED,
+		3: pluginv1.PullRequestState_PULL_REQUEST_STATE_CLOSED,
+		4: pluginv1.PullRequestState_PULL_REQUEST_STATE_OPEN,
+	}}
+
+	return store, forge
+}
+
+// requireStories asserts the store holds exactly names besides _default,
+// which List always creates.
+func requireStories(t *testing.T, store coreStory.Store, names ...string) {
+	t.Helper()
+
+	stories, err := store.List(context.Background())
+	require.NoError(t, err)
+
+	got := make([]string, 0, len(stories))
+	for _, st := range stories {
+		got = append(got, st.Name)
+	}
+
+	require.ElementsMatch(t, append([]string{"_default"}, names...), got)
+}
+
+// pruneManager adds a single forge to stubManager.
+type pruneManager struct {
+	*stubManager
+
+	forge pluginv1.ForgeClient
+}
+
+func (m *pruneManager) GetForge(context.Context, string) (pluginv1.ForgeClient, error) {
+	if m.forge == nil {
+		return nil, errNotFound
+	}
+
+	return m.forge, nil
+}
+
+// stubPruneForge embeds the client interface and only implements GetPullRequest.
+type stubPruneForge struct {
+	pluginv1.ForgeClient
+
+	states    map[int64]pluginv1.PullRequestState
+	requested []int64
+}
+
+func (f *stubPruneForge) GetPullRequest(
+	_ context.Context,
+	req *pluginv1.GetPRRequest,
+	_ ...grpc.CallOption,
+) (*pluginv1.PullRequest, error) {
+	f.requested = append(f.requested, req.GetNumber())
+
+	return &pluginv1.PullRequest{Number: req.GetNumber(), State: f.states[req.GetNumber()]}, nil
+}
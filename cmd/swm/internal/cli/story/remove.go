@@ -13,6 +13,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	coreServices "github.com/kalbasit/swm/cmd/swm/internal/core/services"
 	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
@@ -23,6 +24,8 @@ import (
 // pluginManager is the subset of the CLI plugin manager used by this command.
 type pluginManager interface {
 	Get(ctx context.Context, capability string) (any, error)
+	GetVCS(ctx context.Context, name string) (pluginv1.VCSClient, error)
+	VCSForPath(ctx context.Context, path string) (string, error)
 	Warm(ctx context.Context, capabilities ...string) error
 }
 
@@ -132,58 +135,59 @@ func removeStory(
 
 	var errs []error
 
-	// Remove all worktrees — best-effort, collect failures.
-	if len(st.Projects) > 0 {
-		raw, err := mgr.Get(ctx, "vcs")
+	// Remove all worktrees through each project's vcs plugin — best-effort,
+	// collect failures.
+	for i := range st.Projects {
+		p := &st.Projects[i]
+		pid := &pluginv1.ProjectID{Host: p.Host, Segments: p.Segments}
+		worktreePath := resolver.WorktreePath(name, pid)
+		repoPath := resolver.CanonicalPath(pid)
+		projectPath := strings.Join(p.Segments, "/")
+
+		vcs, err := mgr.GetVCS(ctx, p.VCS)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("loading vcs plugin: %w", err))
-		} else if vcs, ok := raw.(pluginv1.VCSClient); ok {
-			for i := range st.Projects {
-				p := &st.Projects[i]
-				pid := &pluginv1.ProjectID{Host: p.Host, Segments: p.Segments}
-				worktreePath := resolver.WorktreePath(name, pid)
-				repoPath := resolver.CanonicalPath(pid)
-				projectPath := strings.Join(p.Segments, "/")
-
-				preWT := hookexec.RunConfig{
-					Event:        "pre-worktree-remove",
-					CodeRoot:     codeRoot,
-					StoryName:    name,
-					ProjectHost:  p.Host,
-					ProjectPath:  projectPath,
-					WorktreePath: worktreePath,
-					RepoPath:     repoPath,
-					WorkDir:      worktreePath,
-				}
 
-				if err := hooks.Run(ctx, preWT); err != nil {
-					slog.WarnContext(ctx, "pre-worktree-remove hook failed (continuing)", "err", err)
-				}
+			continue
+		}
 
-				if _, err := vcs.RemoveWorktree(ctx, &pluginv1.RemoveWorktreeRequest{
-					WorktreePath: worktreePath,
-				}); err != nil {
-					if status.Code(err) != codes.NotFound {
-						errs = append(errs, fmt.Errorf("removing worktree %s: %w", worktreePath, err))
-					}
-				}
+		preWT := hookexec.RunConfig{
+			Event:        "pre-worktree-remove",
+			CodeRoot:     codeRoot,
+			StoryName:    name,
+			ProjectHost:  p.Host,
+			ProjectPath:  projectPath,
+			WorktreePath: worktreePath,
+			RepoPath:     repoPath,
+			WorkDir:      worktreePath,
+		}
 
-				postWT := hookexec.RunConfig{
-					Event:        "post-worktree-remove",
-					CodeRoot:     codeRoot,
-					StoryName:    name,
-					ProjectHost:  p.Host,
-					ProjectPath:  projectPath,
-					WorktreePath: worktreePath,
-					RepoPath:     repoPath,
-					WorkDir:      repoPath,
-				}
+		if err := hooks.Run(ctx, preWT); err != nil {
+			slog.WarnContext(ctx, "pre-worktree-remove hook failed (continuing)", "err", err)
+		}
 
-				if err := hooks.Run(ctx, postWT); err != nil {
-					slog.WarnContext(ctx, "post-worktree-remove hook failed (continuing)", "err", err)
-				}
+		if _, err := vcs.RemoveWorktree(ctx, &pluginv1.RemoveWorktreeRequest{
+			WorktreePath: worktreePath,
+		}); err != nil {
+			if status.Code(err) != codes.NotFound {
+				errs = append(errs, fmt.Errorf("removing worktree %s: %w", worktreePath, err))
 			}
 		}
+
+		postWT := hookexec.RunConfig{
+			Event:        "post-worktree-remove",
+			CodeRoot:     codeRoot,
+			StoryName:    name,
+			ProjectHost:  p.Host,
+			ProjectPath:  projectPath,
+			WorktreePath: worktreePath,
+			RepoPath:     repoPath,
+			WorkDir:      repoPath,
+		}
+
+		if err := hooks.Run(ctx, postWT); err != nil {
+			slog.WarnContext(ctx, "post-worktree-remove hook failed (continuing)", "err", err)
+		}
 	}
 
 	// Close workspace — best-effort.
@@ -220,7 +224,8 @@ func removeStory(
 	return nil
 }
 
-// closeStoryWorkspace finds and closes the workspace for the given story (best-effort).
+// closeStoryWorkspace finds the workspace for the given story, stops its services
+// and closes it (best-effort).
 func closeStoryWorkspace(ctx context.Context, sess pluginv1.SessionClient, storyName string) {
 	stream, err := sess.ListWorkspaces(ctx, &pluginv1.Empty{})
 	if err != nil {
@@ -234,6 +239,10 @@ func closeStoryWorkspace(ctx context.Context, sess pluginv1.SessionClient, story
 		}
 
 		if ws.GetStoryName() == storyName {
+			if err := coreServices.StopAll(ctx, sess, ws.GetWorkspaceId()); err != nil {
+				slog.WarnContext(ctx, "stopping services", "story", storyName, "err", err)
+			}
+
 			_, _ = sess.CloseWorkspace(ctx, &pluginv1.CloseWorkspaceRequest{ //nolint:errcheck // best-effort close
 				WorkspaceId: ws.GetWorkspaceId(),
 			})
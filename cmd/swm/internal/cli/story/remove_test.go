@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
 	"github.com/kalbasit/swm/cmd/swm/internal/cli/story"
 	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
@@ -114,6 +115,31 @@ func TestRemoveCmd_Force_WithProjects(t *testing.T) {
 	require.True(t, store.deleted)
 }
 
+func TestRemoveCmd_RoutesEachProjectToItsVCS(t *testing.T) {
+	t.Parallel()
+
+	st := &coreStory.Story{
+		Name: testStoryName,
+		Projects: []coreStory.Project{
+			{Host: testGitHubHost, Segments: []string{testKalbasitOrg, testSWMRepo}},
+			{Host: testGitHubHost, Segments: []string{testKalbasitOrg, "dotfiles"}, VCS: "jj"},
+		},
+	}
+	store := &stubStore{getStory: st}
+	git := &stubVCSClient{}
+	jj := &stubVCSClient{}
+	mgr := &stubManager{vcs: git, namedVCS: map[string]pluginv1.VCSClient{"jj": jj}}
+	resolver := layout.NewResolver("/code", "_default")
+
+	cmd := story.NewRemoveCmd(store, mgr, resolver, hookexec.Noop)
+	cmd.SetArgs([]string{testStoryName, testForceFlag})
+
+	require.NoError(t, cmd.Execute())
+	require.True(t, git.removeWorktreeCalled)
+	require.True(t, jj.removeWorktreeCalled)
+	require.True(t, store.deleted)
+}
+
 func TestRemoveCmd_Confirm_Yes_Accepted(t *testing.T) {
 	t.Parallel()
 
@@ -21,6 +21,8 @@ const (
 	testForceFlag   = "--force"
 	capSession      = "session"
 	capVCS          = "vcs"
+	capTracker      = "tracker"
+	capPicker       = "picker"
 )
 
 var errFakeSession = errors.New("session unavailable")
@@ -96,7 +98,13 @@ var _ coreStory.Store = (*stubStore)(nil)
 type stubManager struct {
 	vcs      pluginv1.VCSClient
 	sess     pluginv1.SessionClient
+	tracker  pluginv1.TrackerClient
+	picker   pluginv1.PickerClient
 	warmErrs map[string]error // optional per-capability warm errors
+	// namedVCS holds the non-default vcs plugins by name; pathVCS is the name
+	// VCSForPath returns.
+	namedVCS map[string]pluginv1.VCSClient
+	pathVCS  string
 }
 
 func (s *stubManager) Get(_ context.Context, capability string) (any, error) {
@@ -109,11 +117,35 @@ func (s *stubManager) Get(_ context.Context, capability string) (any, error) {
 		if s.sess != nil {
 			return s.sess, nil
 		}
+	case capTracker:
+		if s.tracker != nil {
+			return s.tracker, nil
+		}
+	case capPicker:
+		if s.picker != nil {
+			return s.picker, nil
+		}
+	}
+
+	return nil, errNotFound
+}
+
+func (s *stubManager) GetVCS(_ context.Context, name string) (pluginv1.VCSClient, error) {
+	if vcs, ok := s.namedVCS[name]; ok {
+		return vcs, nil
+	}
+
+	if name == "" && s.vcs != nil {
+		return s.vcs, nil
 	}
 
 	return nil, errNotFound
 }
 
+func (s *stubManager) VCSForPath(context.Context, string) (string, error) {
+	return s.pathVCS, nil
+}
+
 func (s *stubManager) Warm(_ context.Context, caps ...string) error {
 	for _, c := range caps {
 		if err, ok := s.warmErrs[c]; ok {
@@ -138,6 +170,10 @@ type stubVCSClient struct {
 	detectPath   string
 	detectPID    *pluginv1.ProjectID
 	detectErr    error
+
+	identity          *pluginv1.UserIdentity
+	validatedBranches []string
+	validateErr       error
 }
 
 func (s *stubVCSClient) Clone(
@@ -212,6 +248,26 @@ func (s *stubVCSClient) DetectProjectAtPath(
 	return &pluginv1.ProjectID{Host: testGitHubHost, Segments: []string{testKalbasitOrg, testSWMRepo}}, nil
 }
 
+func (s *stubVCSClient) GetUserIdentity(
+	context.Context,
+	*pluginv1.UserIdentityRequest,
+	...grpc.CallOption,
+) (*pluginv1.UserIdentity, error) {
+	if s.identity == nil {
+		return &pluginv1.UserIdentity{}, nil
+	}
+
+	return s.identity, nil
+}
+
+func (s *stubVCSClient) GetWorktreeStatus(
+	context.Context,
+	*pluginv1.WorktreeStatusRequest,
+	...grpc.CallOption,
+) (*pluginv1.WorktreeStatus, error) {
+	panic("stub")
+}
+
 func (s *stubVCSClient) Info(
 	context.Context,
 	*pluginv1.Empty,
@@ -250,6 +306,20 @@ func (s *stubVCSClient) RemoveWorktree(
 	return &pluginv1.Empty{}, nil
 }
 
+func (s *stubVCSClient) ValidateBranchName(
+	_ context.Context,
+	req *pluginv1.ValidateBranchNameRequest,
+	_ ...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	s.validatedBranches = append(s.validatedBranches, req.GetBranchName())
+
+	if s.validateErr != nil {
+		return nil, s.validateErr
+	}
+
+	return &pluginv1.Empty{}, nil
+}
+
 var _ pluginv1.VCSClient = (*stubVCSClient)(nil)
 
 // stubSessionClient implements pluginv1.SessionClient for tests.
@@ -257,6 +327,22 @@ type stubSessionClient struct {
 	openWorkspaceFn func(*pluginv1.OpenWorkspaceRequest) (*pluginv1.Workspace, error)
 }
 
+func (s *stubSessionClient) AttachCommand(
+	context.Context,
+	*pluginv1.AttachCommandRequest,
+	...grpc.CallOption,
+) (*pluginv1.AttachCommandResponse, error) {
+	panic("stub")
+}
+
+func (s *stubSessionClient) ClosePaneGroup(
+	context.Context,
+	*pluginv1.ClosePaneGroupRequest,
+	...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	panic("stub")
+}
+
 func (s *stubSessionClient) CloseWorkspace(
 	context.Context,
 	*pluginv1.CloseWorkspaceRequest,
@@ -289,6 +375,22 @@ func (s *stubSessionClient) IsInsideWorkspace(
 	panic("stub")
 }
 
+func (s *stubSessionClient) ListPaneGroups(
+	context.Context,
+	*pluginv1.ListPaneGroupsRequest,
+	...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.PaneGroup], error) {
+	panic("stub")
+}
+
+func (s *stubSessionClient) ListServices(
+	context.Context,
+	*pluginv1.ListServicesRequest,
+	...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.Service], error) {
+	panic("stub")
+}
+
 func (s *stubSessionClient) ListWorkspaces(
 	context.Context,
 	*pluginv1.Empty,
@@ -317,6 +419,38 @@ func (s *stubSessionClient) OpenWorkspace(
 	return &pluginv1.Workspace{WorkspaceId: "sock-" + req.GetStoryName(), StoryName: req.GetStoryName()}, nil
 }
 
+func (s *stubSessionClient) RestoreWorkspace(
+	context.Context,
+	*pluginv1.RestoreWorkspaceRequest,
+	...grpc.CallOption,
+) (*pluginv1.RestoreWorkspaceResponse, error) {
+	panic("stub")
+}
+
+func (s *stubSessionClient) SaveWorkspace(
+	context.Context,
+	*pluginv1.SaveWorkspaceRequest,
+	...grpc.CallOption,
+) (*pluginv1.SaveWorkspaceResponse, error) {
+	panic("stub")
+}
+
+func (s *stubSessionClient) StartService(
+	context.Context,
+	*pluginv1.StartServiceRequest,
+	...grpc.CallOption,
+) (*pluginv1.Service, error) {
+	panic("stub")
+}
+
+func (s *stubSessionClient) StopService(
+	context.Context,
+	*pluginv1.StopServiceRequest,
+	...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	panic("stub")
+}
+
 func (s *stubSessionClient) SwitchTo(
 	context.Context,
 	*pluginv1.SwitchToRequest,
@@ -1,15 +1,21 @@
 package workspace
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	coreServices "github.com/kalbasit/swm/cmd/swm/internal/core/services"
 	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
 )
 
 var (
@@ -18,74 +24,240 @@ var (
 )
 
 // NewCloseCmd returns the `swm workspace close` command.
-func NewCloseCmd(store coreStory.Store, mgr pluginManager) *cobra.Command {
+func NewCloseCmd(cfg *config.Config, store coreStory.Store, mgr pluginManager) *cobra.Command {
+	var projectKey string
+
 	cmd := &cobra.Command{
 		Use:   "close [<name>]",
 		Short: "Close the active workspace for a story without removing the story",
-		Args:  cobra.MaximumNArgs(1),
+		Long: "Close the active workspace for a story without removing the story. " +
+			"With --project, only that project's pane group is closed and the rest " +
+			"of the workspace keeps running.",
+		Args: cobra.MaximumNArgs(1),
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			mgr.Warm(cmd.Context(), "session") //nolint:errcheck,gosec // Warm always returns nil; errors deferred to Get
 
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var name string
-			if len(args) == 1 {
-				name = args[0]
-			} else {
-				name = os.Getenv("SWM_STORY")
-			}
-
-			if name == "" {
-				return errCloseNoStoryName
+			name, err := storyNameFromArgs(args)
+			if err != nil {
+				return err
 			}
 
 			ctx := cmd.Context()
 
-			raw, err := mgr.Get(ctx, "session")
+			sess, err := sessionClient(ctx, mgr)
 			if err != nil {
-				return fmt.Errorf("loading session plugin: %w", err)
-			}
-
-			sess, ok := raw.(pluginv1.SessionClient)
-			if !ok {
-				return fmt.Errorf("%w: expected pluginv1.SessionClient, got %T", errUnexpectedSessionPlugin, raw)
+				return err
 			}
 
-			stream, err := sess.ListWorkspaces(ctx, &pluginv1.Empty{})
+			ws, err := findWorkspace(ctx, sess, name)
 			if err != nil {
-				return fmt.Errorf("listing workspaces: %w", err)
+				return err
 			}
 
-			for {
-				ws, err := stream.Recv()
-				if errors.Is(err, io.EOF) {
-					break
-				}
+			// No active workspace found — succeed (idempotent).
+			if ws == nil {
+				return nil
+			}
 
-				if err != nil {
-					return fmt.Errorf("receiving workspace: %w", err)
-				}
+			if projectKey != "" {
+				return closePaneGroup(cmd, sess, ws, name, projectKey)
+			}
 
-				if ws.GetStoryName() == name {
-					if _, err := sess.CloseWorkspace(ctx, &pluginv1.CloseWorkspaceRequest{
-						WorkspaceId: ws.GetWorkspaceId(),
-					}); err != nil {
-						return fmt.Errorf("closing workspace %q for story %q: %w", ws.GetWorkspaceId(), name, err)
-					}
+			// Stop services first so they exit cleanly and stay out of the snapshot.
+			if err := coreServices.StopAll(ctx, sess, ws.GetWorkspaceId()); err != nil {
+				slog.WarnContext(ctx, "stopping services", "story", name, "err", err)
+			}
 
-					cmd.Printf("closed workspace for story %q\n", name)
+			req := &pluginv1.CloseWorkspaceRequest{WorkspaceId: ws.GetWorkspaceId()}
+			if cfg.Workspace.Autosave {
+				req.SnapshotPath = snapshotPath(cfg, name)
+			}
 
-					return nil
-				}
+			if _, err := sess.CloseWorkspace(ctx, req); err != nil {
+				return fmt.Errorf("closing workspace %q for story %q: %w", ws.GetWorkspaceId(), name, err)
 			}
 
-			// No active workspace found — succeed (idempotent).
+			cmd.Printf("closed workspace for story %q\n", name)
+
 			return nil
 		},
 	}
 
-	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	cmd.Flags().StringVar(&projectKey, "project", "",
+		"close only the pane group of this project (host/seg1/.../segN)")
+
+	cmd.ValidArgsFunction = storyNameCompletion(store)
+
+	//nolint:errcheck,gosec // RegisterFlagCompletionFunc only fails for unknown flags
+	cmd.RegisterFlagCompletionFunc("project", func(
+		cmd *cobra.Command, args []string, _ string,
+	) ([]string, cobra.ShellCompDirective) {
+		name, err := storyNameFromArgs(args)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		st, err := store.Get(cmd.Context(), name)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		keys := make([]string, 0, len(st.Projects))
+		for _, p := range st.Projects {
+			keys = append(keys, p.Host+"/"+strings.Join(p.Segments, "/"))
+		}
+
+		return keys, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// closePaneGroup closes the pane group for projectKey inside ws. A project with
+// no live pane group is treated as already closed.
+func closePaneGroup(
+	cmd *cobra.Command, sess pluginv1.SessionClient, ws *pluginv1.Workspace, storyName, projectKey string,
+) error {
+	ctx := cmd.Context()
+
+	if _, err := projectIDFromKey(projectKey); err != nil {
+		return err
+	}
+
+	groups, err := listPaneGroups(ctx, sess, ws.GetWorkspaceId())
+	if err != nil {
+		return err
+	}
+
+	for _, pg := range groups {
+		if paneGroupKey(pg) != projectKey {
+			continue
+		}
+
+		if _, err := sess.ClosePaneGroup(ctx, &pluginv1.ClosePaneGroupRequest{
+			WorkspaceId: ws.GetWorkspaceId(),
+			PaneGroupId: pg.GetPaneGroupId(),
+		}); err != nil {
+			return fmt.Errorf("closing pane group for project %q in story %q: %w", projectKey, storyName, err)
+		}
+
+		cmd.Printf("closed pane group for project %q in story %q\n", projectKey, storyName)
+
+		return nil
+	}
+
+	return nil
+}
+
+// listPaneGroups collects the live pane groups of a workspace.
+func listPaneGroups(ctx context.Context, sess pluginv1.SessionClient, workspaceID string) ([]*pluginv1.PaneGroup, error) {
+	stream, err := sess.ListPaneGroups(ctx, &pluginv1.ListPaneGroupsRequest{WorkspaceId: workspaceID})
+	if err != nil {
+		return nil, fmt.Errorf("listing pane groups: %w", err)
+	}
+
+	var groups []*pluginv1.PaneGroup
+
+	for {
+		pg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return groups, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("receiving pane group: %w", err)
+		}
+
+		groups = append(groups, pg)
+	}
+}
+
+// paneGroupKey returns the project key (host/seg1/.../segN) of a pane group.
+func paneGroupKey(pg *pluginv1.PaneGroup) string {
+	pid := pg.GetProjectId()
+
+	return pid.GetHost() + "/" + strings.Join(pid.GetSegments(), "/")
+}
+
+// storyNameFromArgs returns the story named by the optional positional
+// argument, falling back to $SWM_STORY.
+func storyNameFromArgs(args []string) (string, error) {
+	var name string
+	if len(args) == 1 {
+		name = args[0]
+	} else {
+		name = os.Getenv("SWM_STORY")
+	}
+
+	if name == "" {
+		return "", errCloseNoStoryName
+	}
+
+	return name, nil
+}
+
+// sessionClient loads the session plugin from mgr.
+func sessionClient(ctx context.Context, mgr pluginManager) (pluginv1.SessionClient, error) {
+	raw, err := mgr.Get(ctx, "session")
+	if err != nil {
+		return nil, fmt.Errorf("loading session plugin: %w", err)
+	}
+
+	sess, ok := raw.(pluginv1.SessionClient)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected pluginv1.SessionClient, got %T", errUnexpectedSessionPlugin, raw)
+	}
+
+	return sess, nil
+}
+
+// findWorkspace returns the live workspace for storyName, or nil when none is running.
+func findWorkspace(ctx context.Context, sess pluginv1.SessionClient, storyName string) (*pluginv1.Workspace, error) {
+	workspaces, err := listWorkspaces(ctx, sess)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ws := range workspaces {
+		if ws.GetStoryName() == storyName {
+			return ws, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// listWorkspaces collects every running workspace reported by the session plugin.
+func listWorkspaces(ctx context.Context, sess pluginv1.SessionClient) ([]*pluginv1.Workspace, error) {
+	stream, err := sess.ListWorkspaces(ctx, &pluginv1.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("listing workspaces: %w", err)
+	}
+
+	var workspaces []*pluginv1.Workspace
+
+	for {
+		ws, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return workspaces, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("receiving workspace: %w", err)
+		}
+
+		workspaces = append(workspaces, ws)
+	}
+}
+
+// storyNameCompletion completes the first positional argument with story names.
+func storyNameCompletion(
+	store coreStory.Store,
+) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
 		if len(args) > 0 {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
@@ -104,6 +276,4 @@ func NewCloseCmd(store coreStory.Store, mgr pluginManager) *cobra.Command {
 
 		return names, cobra.ShellCompDirectiveNoFileComp
 	}
-
-	return cmd
 }
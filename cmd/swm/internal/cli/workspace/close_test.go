@@ -3,6 +3,8 @@ package workspace_test
 import (
 	"context"
 	"io"
+	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 
@@ -15,6 +17,7 @@ import (
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
 	"github.com/kalbasit/swm/cmd/swm/internal/cli/workspace"
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
 )
 
 const (
@@ -29,7 +32,42 @@ type stubCloseSession struct {
 	workspaces       []*pluginv1.Workspace
 	listErr          error
 	closeWorkspaceID string // set by CloseWorkspace call
+	closeSnapshot    string // snapshot_path passed to CloseWorkspace
 	closeErr         error
+
+	lastSaveReq    *pluginv1.SaveWorkspaceRequest
+	saveErr        error
+	lastRestoreReq *pluginv1.RestoreWorkspaceRequest
+	restoreErr     error
+
+	paneGroups        map[string][]*pluginv1.PaneGroup // keyed by workspace ID
+	closePaneGroupReq *pluginv1.ClosePaneGroupRequest
+	closePaneGroupErr error
+
+	lastSwitchReq *pluginv1.SwitchToRequest
+	switchResp    *pluginv1.SwitchToResponse
+
+	services        []*pluginv1.Service
+	stoppedServices []string // service IDs, in StopService order
+	calls           []string // StopService and CloseWorkspace, in call order
+}
+
+func (s *stubCloseSession) AttachCommand(
+	context.Context,
+	*pluginv1.AttachCommandRequest,
+	...grpc.CallOption,
+) (*pluginv1.AttachCommandResponse, error) {
+	panic("stub")
+}
+
+func (s *stubCloseSession) ClosePaneGroup(
+	_ context.Context,
+	req *pluginv1.ClosePaneGroupRequest,
+	_ ...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	s.closePaneGroupReq = req
+
+	return &pluginv1.Empty{}, s.closePaneGroupErr
 }
 
 func (s *stubCloseSession) CloseWorkspace(
@@ -38,6 +76,8 @@ func (s *stubCloseSession) CloseWorkspace(
 	_ ...grpc.CallOption,
 ) (*pluginv1.Empty, error) {
 	s.closeWorkspaceID = req.GetWorkspaceId()
+	s.closeSnapshot = req.GetSnapshotPath()
+	s.calls = append(s.calls, "CloseWorkspace")
 
 	return &pluginv1.Empty{}, s.closeErr
 }
@@ -60,6 +100,34 @@ func (s *stubCloseSession) IsInsideWorkspace(
 	panic("stub")
 }
 
+func (s *stubCloseSession) ListPaneGroups(
+	_ context.Context, req *pluginv1.ListPaneGroupsRequest, _ ...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.PaneGroup], error) {
+	if req.GetWorkspaceId() != "" {
+		return &staticPaneGroupStream{paneGroups: s.paneGroups[req.GetWorkspaceId()]}, nil
+	}
+
+	ids := make([]string, 0, len(s.paneGroups))
+	for id := range s.paneGroups {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	var all []*pluginv1.PaneGroup
+	for _, id := range ids {
+		all = append(all, s.paneGroups[id]...)
+	}
+
+	return &staticPaneGroupStream{paneGroups: all}, nil
+}
+
+func (s *stubCloseSession) ListServices(
+	context.Context, *pluginv1.ListServicesRequest, ...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.Service], error) {
+	return &staticServiceStream{services: s.services}, nil
+}
+
 func (s *stubCloseSession) ListWorkspaces(
 	_ context.Context, _ *pluginv1.Empty, _ ...grpc.CallOption,
 ) (grpc.ServerStreamingClient[pluginv1.Workspace], error) {
@@ -82,10 +150,55 @@ func (s *stubCloseSession) OpenWorkspace(
 	panic("stub")
 }
 
+func (s *stubCloseSession) RestoreWorkspace(
+	_ context.Context, req *pluginv1.RestoreWorkspaceRequest, _ ...grpc.CallOption,
+) (*pluginv1.RestoreWorkspaceResponse, error) {
+	s.lastRestoreReq = req
+	if s.restoreErr != nil {
+		return nil, s.restoreErr
+	}
+
+	return &pluginv1.RestoreWorkspaceResponse{
+		Workspace:    &pluginv1.Workspace{WorkspaceId: testCloseWorkspaceID, StoryName: req.GetStoryName()},
+		PaneGroupIds: []string{"github•com/kalbasit/swm"},
+	}, nil
+}
+
+func (s *stubCloseSession) SaveWorkspace(
+	_ context.Context, req *pluginv1.SaveWorkspaceRequest, _ ...grpc.CallOption,
+) (*pluginv1.SaveWorkspaceResponse, error) {
+	s.lastSaveReq = req
+	if s.saveErr != nil {
+		return nil, s.saveErr
+	}
+
+	return &pluginv1.SaveWorkspaceResponse{SnapshotPath: req.GetSnapshotPath(), PaneGroupCount: 2}, nil
+}
+
+func (s *stubCloseSession) StartService(
+	context.Context, *pluginv1.StartServiceRequest, ...grpc.CallOption,
+) (*pluginv1.Service, error) {
+	panic("stub")
+}
+
+func (s *stubCloseSession) StopService(
+	_ context.Context, req *pluginv1.StopServiceRequest, _ ...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	s.stoppedServices = append(s.stoppedServices, req.GetServiceId())
+	s.calls = append(s.calls, "StopService")
+
+	return &pluginv1.Empty{}, nil
+}
+
 func (s *stubCloseSession) SwitchTo(
-	context.Context, *pluginv1.SwitchToRequest, ...grpc.CallOption,
+	_ context.Context, req *pluginv1.SwitchToRequest, _ ...grpc.CallOption,
 ) (*pluginv1.SwitchToResponse, error) {
-	panic("stub")
+	s.lastSwitchReq = req
+	if s.switchResp != nil {
+		return s.switchResp, nil
+	}
+
+	return &pluginv1.SwitchToResponse{}, nil
 }
 
 var _ pluginv1.SessionClient = (*stubCloseSession)(nil)
@@ -115,6 +228,56 @@ func (s *staticWorkspaceStream) RecvMsg(any) error    { panic("stub") }
 func (s *staticWorkspaceStream) SendMsg(any) error    { panic("stub") }
 func (s *staticWorkspaceStream) Trailer() metadata.MD { panic("stub") }
 
+// staticServiceStream streams a fixed slice of services, then EOF.
+type staticServiceStream struct {
+	services []*pluginv1.Service
+	pos      int
+}
+
+func (s *staticServiceStream) CloseSend() error             { return nil }
+func (s *staticServiceStream) Context() context.Context     { return context.Background() }
+func (s *staticServiceStream) Header() (metadata.MD, error) { panic("stub") }
+
+func (s *staticServiceStream) Recv() (*pluginv1.Service, error) {
+	if s.pos >= len(s.services) {
+		return nil, io.EOF
+	}
+
+	svc := s.services[s.pos]
+	s.pos++
+
+	return svc, nil
+}
+
+func (s *staticServiceStream) RecvMsg(any) error    { panic("stub") }
+func (s *staticServiceStream) SendMsg(any) error    { panic("stub") }
+func (s *staticServiceStream) Trailer() metadata.MD { panic("stub") }
+
+// staticPaneGroupStream streams a fixed slice of pane groups, then EOF.
+type staticPaneGroupStream struct {
+	paneGroups []*pluginv1.PaneGroup
+	pos        int
+}
+
+func (s *staticPaneGroupStream) CloseSend() error             { return nil }
+func (s *staticPaneGroupStream) Context() context.Context     { return context.Background() }
+func (s *staticPaneGroupStream) Header() (metadata.MD, error) { panic("stub") }
+
+func (s *staticPaneGroupStream) Recv() (*pluginv1.PaneGroup, error) {
+	if s.pos >= len(s.paneGroups) {
+		return nil, io.EOF
+	}
+
+	pg := s.paneGroups[s.pos]
+	s.pos++
+
+	return pg, nil
+}
+
+func (s *staticPaneGroupStream) RecvMsg(any) error    { panic("stub") }
+func (s *staticPaneGroupStream) SendMsg(any) error    { panic("stub") }
+func (s *staticPaneGroupStream) Trailer() metadata.MD { panic("stub") }
+
 func TestCloseCmd_ClosesRunningWorkspace(t *testing.T) {
 	t.Parallel()
 
@@ -126,7 +289,7 @@ func TestCloseCmd_ClosesRunningWorkspace(t *testing.T) {
 	mgr := &stubMgr{sess: sess}
 	store := &stubStore{}
 
-	cmd := workspace.NewCloseCmd(store, mgr)
+	cmd := workspace.NewCloseCmd(&config.Config{}, store, mgr)
 	out := &strings.Builder{}
 	cmd.SetOut(out)
 	cmd.SetArgs([]string{testStoryName})
@@ -136,6 +299,28 @@ func TestCloseCmd_ClosesRunningWorkspace(t *testing.T) {
 	require.Contains(t, out.String(), `closed workspace for story "feat-x"`)
 }
 
+func TestCloseCmd_StopsServicesBeforeClosing(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{
+		workspaces: []*pluginv1.Workspace{
+			{WorkspaceId: testCloseWorkspaceID, StoryName: testStoryName},
+		},
+		services: []*pluginv1.Service{
+			{ServiceId: "%1", Name: "github.com/kalbasit/swm:db", Running: true},
+			{ServiceId: "%2", Name: "github.com/kalbasit/swm:web", Running: true},
+		},
+	}
+
+	cmd := workspace.NewCloseCmd(&config.Config{}, &stubStore{}, &stubMgr{sess: sess})
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{testStoryName})
+
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, []string{"%2", "%1"}, sess.stoppedServices, "dependents stop first")
+	require.Equal(t, []string{"StopService", "StopService", "CloseWorkspace"}, sess.calls)
+}
+
 func TestCloseCmd_NoActiveWorkspace_Idempotent(t *testing.T) {
 	t.Parallel()
 
@@ -143,7 +328,7 @@ func TestCloseCmd_NoActiveWorkspace_Idempotent(t *testing.T) {
 	mgr := &stubMgr{sess: sess}
 	store := &stubStore{}
 
-	cmd := workspace.NewCloseCmd(store, mgr)
+	cmd := workspace.NewCloseCmd(&config.Config{}, store, mgr)
 	cmd.SetArgs([]string{testStoryName})
 
 	require.NoError(t, cmd.Execute())
@@ -161,7 +346,7 @@ func TestCloseCmd_SWMStoryFallback(t *testing.T) {
 	mgr := &stubMgr{sess: sess}
 	store := &stubStore{}
 
-	cmd := workspace.NewCloseCmd(store, mgr)
+	cmd := workspace.NewCloseCmd(&config.Config{}, store, mgr)
 	cmd.SetArgs([]string{})
 
 	require.NoError(t, cmd.Execute())
@@ -174,7 +359,7 @@ func TestCloseCmd_NoArg_NoEnv_Error(t *testing.T) {
 	mgr := &stubMgr{}
 	store := &stubStore{}
 
-	cmd := workspace.NewCloseCmd(store, mgr)
+	cmd := workspace.NewCloseCmd(&config.Config{}, store, mgr)
 	cmd.SetArgs([]string{})
 
 	require.Error(t, cmd.Execute())
@@ -191,7 +376,7 @@ func TestCloseCmd_ArgOverridesEnv(t *testing.T) {
 	mgr := &stubMgr{sess: sess}
 	store := &stubStore{}
 
-	cmd := workspace.NewCloseCmd(store, mgr)
+	cmd := workspace.NewCloseCmd(&config.Config{}, store, mgr)
 	cmd.SetArgs([]string{testStoryName})
 
 	require.NoError(t, cmd.Execute())
@@ -204,7 +389,7 @@ func TestCloseCmd_SessionPluginAbsent(t *testing.T) {
 	mgr := &stubMgr{} // no sess → Get("session") returns errNoPlugin
 	store := &stubStore{}
 
-	cmd := workspace.NewCloseCmd(store, mgr)
+	cmd := workspace.NewCloseCmd(&config.Config{}, store, mgr)
 	cmd.SetArgs([]string{testStoryName})
 
 	require.Error(t, cmd.Execute())
@@ -217,7 +402,7 @@ func TestCloseCmd_ListWorkspacesError(t *testing.T) {
 	mgr := &stubMgr{sess: sess}
 	store := &stubStore{}
 
-	cmd := workspace.NewCloseCmd(store, mgr)
+	cmd := workspace.NewCloseCmd(&config.Config{}, store, mgr)
 	cmd.SetArgs([]string{testStoryName})
 
 	err := cmd.Execute()
@@ -236,7 +421,7 @@ func TestCloseCmd_ShellCompletion_ListsStories(t *testing.T) {
 	}
 	mgr := &stubMgr{}
 
-	cmd := workspace.NewCloseCmd(store, mgr)
+	cmd := workspace.NewCloseCmd(&config.Config{}, store, mgr)
 
 	completions, directive := cmd.ValidArgsFunction(cmd, []string{}, "")
 	require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
@@ -249,7 +434,7 @@ func TestCloseCmd_ShellCompletion_NoCompletionAfterFirstArg(t *testing.T) {
 	store := &stubStore{}
 	mgr := &stubMgr{}
 
-	cmd := workspace.NewCloseCmd(store, mgr)
+	cmd := workspace.NewCloseCmd(&config.Config{}, store, mgr)
 
 	_, directive := cmd.ValidArgsFunction(cmd, []string{"already-provided"}, "")
 	require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
@@ -267,7 +452,7 @@ func TestCloseCmd_WrongSessionPluginType_ErrorIncludesType(t *testing.T) {
 	t.Parallel()
 
 	store := &stubStore{}
-	cmd := workspace.NewCloseCmd(store, &badTypeMgr{})
+	cmd := workspace.NewCloseCmd(&config.Config{}, store, &badTypeMgr{})
 	cmd.SetArgs([]string{testStoryName})
 
 	err := cmd.Execute()
@@ -287,7 +472,7 @@ func TestCloseCmd_CloseWorkspaceError_ErrorIncludesContext(t *testing.T) {
 	mgr := &stubMgr{sess: sess}
 	store := &stubStore{}
 
-	cmd := workspace.NewCloseCmd(store, mgr)
+	cmd := workspace.NewCloseCmd(&config.Config{}, store, mgr)
 	cmd.SetArgs([]string{testStoryName})
 
 	err := cmd.Execute()
@@ -309,9 +494,126 @@ func TestCloseCmd_ShellCompletion_NilStoriesSkipped(t *testing.T) {
 	}
 	mgr := &stubMgr{}
 
-	cmd := workspace.NewCloseCmd(store, mgr)
+	cmd := workspace.NewCloseCmd(&config.Config{}, store, mgr)
 
 	completions, directive := cmd.ValidArgsFunction(cmd, []string{}, "")
 	require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
 	require.ElementsMatch(t, []string{testCompletionStoryA, testCompletionStoryB}, completions)
 }
+
+func TestCloseCmd_Autosave_PassesSnapshotPath(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{
+		workspaces: []*pluginv1.Workspace{
+			{WorkspaceId: testCloseWorkspaceID, StoryName: testStoryName},
+		},
+	}
+	dataHome := t.TempDir()
+	cfg := &config.Config{DataHome: dataHome, Workspace: config.Workspace{Autosave: true}}
+
+	cmd := workspace.NewCloseCmd(cfg, &stubStore{}, &stubMgr{sess: sess})
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{testStoryName})
+
+	require.NoError(t, cmd.Execute())
+	require.Equal(t,
+		filepath.Join(dataHome, "swm", "stories", testStoryName, "session-snapshot.json"),
+		sess.closeSnapshot)
+}
+
+func TestCloseCmd_AutosaveDisabled_NoSnapshotPath(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{
+		workspaces: []*pluginv1.Workspace{
+			{WorkspaceId: testCloseWorkspaceID, StoryName: testStoryName},
+		},
+	}
+
+	cmd := workspace.NewCloseCmd(&config.Config{DataHome: t.TempDir()}, &stubStore{}, &stubMgr{sess: sess})
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{testStoryName})
+
+	require.NoError(t, cmd.Execute())
+	require.Empty(t, sess.closeSnapshot)
+}
+
+func TestCloseCmd_Project_ClosesOnlyThatPaneGroup(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{
+		workspaces: []*pluginv1.Workspace{
+			{WorkspaceId: testCloseWorkspaceID, StoryName: testStoryName},
+		},
+		paneGroups: map[string][]*pluginv1.PaneGroup{
+			testCloseWorkspaceID: {
+				{PaneGroupId: "github•com/a/b", ProjectId: &pluginv1.ProjectID{Host: testHost, Segments: []string{"a", "b"}}},
+				{PaneGroupId: "github•com/c/d", ProjectId: &pluginv1.ProjectID{Host: testHost, Segments: []string{"c", "d"}}},
+			},
+		},
+	}
+
+	cmd := workspace.NewCloseCmd(&config.Config{}, &stubStore{}, &stubMgr{sess: sess})
+	out := &strings.Builder{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{testStoryName, "--project", "github.com/c/d"})
+
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "github•com/c/d", sess.closePaneGroupReq.GetPaneGroupId())
+	require.Equal(t, testCloseWorkspaceID, sess.closePaneGroupReq.GetWorkspaceId())
+	require.Empty(t, sess.closeWorkspaceID, "the workspace itself must stay open")
+	require.Contains(t, out.String(), `closed pane group for project "github.com/c/d" in story "feat-x"`)
+}
+
+func TestCloseCmd_Project_NotRunning_Idempotent(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{
+		workspaces: []*pluginv1.Workspace{
+			{WorkspaceId: testCloseWorkspaceID, StoryName: testStoryName},
+		},
+	}
+
+	cmd := workspace.NewCloseCmd(&config.Config{}, &stubStore{}, &stubMgr{sess: sess})
+	cmd.SetArgs([]string{testStoryName, "--project", "github.com/a/b"})
+
+	require.NoError(t, cmd.Execute())
+	require.Nil(t, sess.closePaneGroupReq)
+	require.Empty(t, sess.closeWorkspaceID)
+}
+
+func TestCloseCmd_Project_InvalidKey(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{
+		workspaces: []*pluginv1.Workspace{
+			{WorkspaceId: testCloseWorkspaceID, StoryName: testStoryName},
+		},
+	}
+
+	cmd := workspace.NewCloseCmd(&config.Config{}, &stubStore{}, &stubMgr{sess: sess})
+	cmd.SetArgs([]string{testStoryName, "--project", "github.com"})
+
+	require.Error(t, cmd.Execute())
+	require.Nil(t, sess.closePaneGroupReq)
+}
+
+func TestCloseCmd_Project_ShellCompletion_ListsAttachedProjects(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{getStory: &coreStory.Story{
+		Name: testStoryName,
+		Projects: []coreStory.Project{
+			{Host: testHost, Segments: []string{"a", "b"}},
+		},
+	}}
+
+	cmd := workspace.NewCloseCmd(&config.Config{}, store, &stubMgr{})
+	fn, ok := cmd.GetFlagCompletionFunc("project")
+	require.True(t, ok)
+
+	completions, directive := fn(cmd, []string{testStoryName}, "")
+	require.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	require.Equal(t, []string{"github.com/a/b"}, completions)
+}
@@ -1,4 +1,19 @@
 package workspace
 
+import pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
 // BuildCandidates exposes buildCandidates for testing.
 var BuildCandidates = buildCandidates
+
+// ParsePRRef exposes parsePRRef for testing, flattening the unexported result.
+func ParsePRRef(ref string) (*pluginv1.ProjectID, int64, error) {
+	r, err := parsePRRef(ref)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return r.project, r.number, nil
+}
+
+// ReviewBranchName exposes reviewBranchName for testing.
+var ReviewBranchName = reviewBranchName
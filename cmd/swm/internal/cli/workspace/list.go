@@ -1,6 +1,7 @@
 package workspace
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"sort"
@@ -9,55 +10,141 @@ import (
 	"github.com/spf13/cobra"
 
 	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 )
 
+// liveMarker is appended to stories and projects that have a running
+// workspace or pane group.
+const liveMarker = " (live)"
+
+// untrackedMarker is appended to live pane groups whose project is not
+// attached to the story.
+const untrackedMarker = " (live, untracked)"
+
 // NewListCmd returns the `swm workspace list` command.
-func NewListCmd(store coreStory.Store, defaultStory string) *cobra.Command {
+func NewListCmd(store coreStory.Store, mgr pluginManager, defaultStory string) *cobra.Command {
 	return &cobra.Command{
 		Use:   "list",
 		Short: "List all workspaces and their attached projects",
-		Args:  cobra.NoArgs,
+		Long: "List all workspaces and their attached projects. When a session plugin is " +
+			"available, running workspaces and pane groups are marked as live and pane " +
+			"groups for projects that are not attached to the story are listed as untracked.",
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			mgr.Warm(cmd.Context(), "session") //nolint:errcheck,gosec // best-effort pre-warm; error surfaces on Get
+
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			stories, err := store.List(cmd.Context())
 			if err != nil {
 				return fmt.Errorf("listing workspaces: %w", err)
 			}
 
-			renderWorkspaceTree(cmd.OutOrStdout(), stories, defaultStory)
+			live, err := liveWorkspaces(cmd.Context(), mgr)
+			if err != nil {
+				return err
+			}
+
+			renderWorkspaceTree(cmd.OutOrStdout(), stories, live, defaultStory)
 
 			return nil
 		},
 	}
 }
 
+// liveWorkspaces maps each running story to the project keys of its live pane
+// groups. A missing session plugin is not an error: it yields a nil map and
+// the list falls back to the stored stories only.
+func liveWorkspaces(ctx context.Context, mgr pluginManager) (map[string][]string, error) {
+	raw, err := mgr.Get(ctx, "session")
+	if err != nil {
+		return nil, nil //nolint:nilerr // session plugin is optional for listing
+	}
+
+	sess, ok := raw.(pluginv1.SessionClient)
+	if !ok {
+		return nil, fmt.Errorf("%w: got %T", errUnexpectedSessionPlugin, raw)
+	}
+
+	workspaces, err := listWorkspaces(ctx, sess)
+	if err != nil {
+		return nil, err
+	}
+
+	live := make(map[string][]string, len(workspaces))
+
+	for _, ws := range workspaces {
+		groups, err := listPaneGroups(ctx, sess, ws.GetWorkspaceId())
+		if err != nil {
+			return nil, fmt.Errorf("workspace %q: %w", ws.GetStoryName(), err)
+		}
+
+		keys := make([]string, 0, len(groups))
+		for _, pg := range groups {
+			keys = append(keys, paneGroupKey(pg))
+		}
+
+		live[ws.GetStoryName()] = keys
+	}
+
+	return live, nil
+}
+
 // renderWorkspaceTree writes a two-level tree of workspaces and their projects to w,
 // skipping the default story. Projects are rendered with box-drawing glyphs (├──/└──).
-func renderWorkspaceTree(w io.Writer, stories []*coreStory.Story, defaultStory string) {
+// Stories and projects present in live are marked as running; live pane groups for
+// projects that are not attached to the story are appended as untracked.
+func renderWorkspaceTree(w io.Writer, stories []*coreStory.Story, live map[string][]string, defaultStory string) {
 	for _, s := range stories {
 		if s.Name == defaultStory {
 			continue
 		}
 
-		fmt.Fprintf(w, "%s\n", s.Name) //nolint:errcheck // writing to output; errors are non-actionable
+		liveKeys, running := live[s.Name]
 
-		if len(s.Projects) == 0 {
-			continue
+		name := s.Name
+		if running {
+			name += liveMarker
 		}
 
-		paths := make([]string, 0, len(s.Projects))
+		fmt.Fprintf(w, "%s\n", name) //nolint:errcheck // writing to output; errors are non-actionable
+
+		attached := make(map[string]bool, len(s.Projects))
 		for _, p := range s.Projects {
-			paths = append(paths, p.Host+"/"+strings.Join(p.Segments, "/"))
+			attached[p.Host+"/"+strings.Join(p.Segments, "/")] = true
+		}
+
+		liveSet := make(map[string]bool, len(liveKeys))
+		for _, k := range liveKeys {
+			liveSet[k] = true
+		}
+
+		lines := make([]string, 0, len(attached)+len(liveKeys))
+
+		for path := range attached {
+			if liveSet[path] {
+				path += liveMarker
+			}
+
+			lines = append(lines, path)
+		}
+
+		for _, k := range liveKeys {
+			if !attached[k] {
+				lines = append(lines, k+untrackedMarker)
+			}
 		}
 
-		sort.Strings(paths)
+		sort.Strings(lines)
 
-		for i, path := range paths {
+		for i, line := range lines {
 			glyph := "├── "
-			if i == len(paths)-1 {
+			if i == len(lines)-1 {
 				glyph = "└── "
 			}
 
-			fmt.Fprintf(w, "%s%s\n", glyph, path) //nolint:errcheck // writing to output; errors are non-actionable
+			fmt.Fprintf(w, "%s%s\n", glyph, line) //nolint:errcheck // writing to output; errors are non-actionable
 		}
 	}
 }
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
 	"github.com/kalbasit/swm/cmd/swm/internal/cli/workspace"
 )
@@ -76,7 +78,7 @@ func TestListCmd(t *testing.T) {
 			t.Parallel()
 
 			store := &stubStore{listStories: tc.stories}
-			cmd := workspace.NewListCmd(store, testDefaultStory)
+			cmd := workspace.NewListCmd(store, &stubMgr{}, testDefaultStory)
 
 			var out bytes.Buffer
 			cmd.SetOut(&out)
@@ -91,7 +93,7 @@ func TestListCmd_StoreError(t *testing.T) {
 	t.Parallel()
 
 	store := &stubStore{listErr: errListStore}
-	cmd := workspace.NewListCmd(store, testDefaultStory)
+	cmd := workspace.NewListCmd(store, &stubMgr{}, testDefaultStory)
 
 	require.Error(t, cmd.Execute())
 }
@@ -125,7 +127,7 @@ func TestListCmd_Integration(t *testing.T) {
 	)
 	require.NoError(t, store.Update(ctx, s2))
 
-	cmd := workspace.NewListCmd(store, testDefaultStory)
+	cmd := workspace.NewListCmd(store, &stubMgr{}, testDefaultStory)
 
 	var out bytes.Buffer
 	cmd.SetOut(&out)
@@ -135,3 +137,57 @@ func TestListCmd_Integration(t *testing.T) {
 	want := "story-1\n└── github.com/a/b\nstory-2\n├── github.com/c/d\n└── github.com/e/f\n"
 	require.Equal(t, want, out.String())
 }
+
+func TestListCmd_MarksLiveWorkspacesAndPaneGroups(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{listStories: []*coreStory.Story{
+		{
+			Name: testSortStoryAlpha,
+			Projects: []coreStory.Project{
+				{Host: testHost, Segments: []string{"a", "b"}},
+				{Host: testHost, Segments: []string{"c", "d"}},
+			},
+		},
+		{
+			Name:     testSortStoryBeta,
+			Projects: []coreStory.Project{{Host: testHost, Segments: []string{"e", "f"}}},
+		},
+	}}
+	sess := &stubCloseSession{
+		workspaces: []*pluginv1.Workspace{{WorkspaceId: "sock-alpha", StoryName: testSortStoryAlpha}},
+		paneGroups: map[string][]*pluginv1.PaneGroup{
+			"sock-alpha": {
+				{PaneGroupId: "github•com/a/b", ProjectId: &pluginv1.ProjectID{Host: testHost, Segments: []string{"a", "b"}}},
+				{PaneGroupId: "github•com/x/y", ProjectId: &pluginv1.ProjectID{Host: testHost, Segments: []string{"x", "y"}}},
+			},
+		},
+	}
+
+	cmd := workspace.NewListCmd(store, &stubMgr{sess: sess}, testDefaultStory)
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	require.NoError(t, cmd.Execute())
+
+	want := "alpha (live)\n" +
+		"├── github.com/a/b (live)\n" +
+		"├── github.com/c/d\n" +
+		"└── github.com/x/y (live, untracked)\n" +
+		"beta\n" +
+		"└── github.com/e/f\n"
+	require.Equal(t, want, out.String())
+}
+
+func TestListCmd_ListWorkspacesError(t *testing.T) {
+	t.Parallel()
+
+	store := &stubStore{listStories: []*coreStory.Story{{Name: "feat-x"}}}
+	sess := &stubCloseSession{listErr: errNoPlugin}
+
+	cmd := workspace.NewListCmd(store, &stubMgr{sess: sess}, testDefaultStory)
+	cmd.SetOut(io.Discard)
+
+	require.ErrorIs(t, cmd.Execute(), errNoPlugin)
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"os"
 	"slices"
 	"strings"
@@ -25,6 +26,7 @@ import (
 
 	"github.com/kalbasit/swm/cmd/swm/internal/config"
 	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/ports"
 	"github.com/kalbasit/swm/cmd/swm/internal/hookexec"
 	"github.com/kalbasit/swm/cmd/swm/internal/termwidth"
 )
@@ -36,6 +38,16 @@ type pluginManager interface {
 	Close() error
 }
 
+// openPluginManager adds forge lookup, used by `workspace open --pr`, and vcs
+// routing, used when a project is attached to the story.
+type openPluginManager interface {
+	pluginManager
+	GetForge(ctx context.Context, hostname string) (pluginv1.ForgeClient, error)
+	GetVCS(ctx context.Context, name string) (pluginv1.VCSClient, error)
+	VCSForPath(ctx context.Context, path string) (string, error)
+	VCSForURL(ctx context.Context, url string) (string, error)
+}
+
 // ProjectLister supplies the on-disk project list to the workspace open command.
 // Satisfied by *hostsvc.Server; the interface lives here per consumer-defines-interface convention.
 type ProjectLister interface {
@@ -110,7 +122,7 @@ func WithStdinReader(r io.Reader) OpenOption {
 func NewOpenCmd(
 	cfg *config.Config,
 	store coreStory.Store,
-	mgr pluginManager,
+	mgr openPluginManager,
 	resolver *layout.Resolver,
 	hooks hookexec.Runner,
 	opts ...OpenOption,
@@ -125,14 +137,26 @@ func NewOpenCmd(
 		o(ocfg)
 	}
 
-	var killPane bool
+	var (
+		killPane  bool
+		prRef     string
+		ephemeral bool
+		noAttach  bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "open [story-name]",
 		Short: "Open (or attach to) the workspace for a story",
 		Long: "Open (or attach to) the workspace for a story. " +
 			"If [story-name] is omitted, the command falls back to the $SWM_STORY " +
-			"environment variable, and then to the default story configured in swm.",
+			"environment variable, and then to the default story configured in swm.\n\n" +
+			"With --pr, the story is a review story for a pull request given as a URL or " +
+			"as [host/]owner/repo#number: the repository is cloned if missing, a story named " +
+			"<repo>-pr-<number> is created on the pull request's head branch (fetched through " +
+			"the forge, including forks) and its workspace is opened.\n\n" +
+			"With --no-attach, the workspace and pane groups are prepared without prompting or " +
+			"switching the terminal, and a JSON document with the workspace ID, pane group IDs, " +
+			"worktree paths and the command to attach later is printed to stdout.",
 		Args: cobra.MaximumNArgs(1),
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			// Fire background startup for all three capabilities; errors surface in
@@ -142,8 +166,40 @@ func NewOpenCmd(
 
 			return nil
 		},
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (retErr error) {
 			ctx := cmd.Context()
+			sw := &switchOptions{killPane: killPane, exec: closingExecFunc(ctx, mgr, ocfg.exec)}
+
+			if noAttach {
+				if killPane {
+					return errKillPaneWithNoAttach
+				}
+
+				// Keep stdout for the JSON result; progress messages go to stderr.
+				sw.result = newOpenResult()
+				stdout := cmd.OutOrStdout()
+				cmd.SetOut(cmd.ErrOrStderr())
+
+				defer func() {
+					cmd.SetOut(stdout)
+
+					if retErr == nil && sw.result.WorkspaceID != "" {
+						retErr = sw.result.print(stdout)
+					}
+				}()
+			}
+
+			if prRef != "" {
+				if len(args) > 0 {
+					return errPRWithStoryName
+				}
+
+				return openPullRequest(ctx, cmd, cfg, store, mgr, ocfg.lister, resolver, hooks, prRef, ephemeral, sw)
+			}
+
+			if ephemeral {
+				return errEphemeralWithoutPR
+			}
 
 			var storyName string
 
@@ -157,9 +213,12 @@ func NewOpenCmd(
 
 			// Attempt to load the picker plugin (optional — no error if absent).
 			// Loaded early so it can be used for story selection before project selection.
+			// --no-attach never prompts, so it opens all attached projects instead.
 			var pickerClient pluginv1.PickerClient
 
-			if rawPicker, pickErr := mgr.Get(ctx, "picker"); pickErr == nil {
+			if noAttach {
+				slog.DebugContext(ctx, "picker skipped with --no-attach")
+			} else if rawPicker, pickErr := mgr.Get(ctx, "picker"); pickErr == nil {
 				if pc, ok := rawPicker.(pluginv1.PickerClient); ok {
 					pickerClient = pc
 
@@ -199,7 +258,7 @@ func NewOpenCmd(
 			st, err := store.Get(ctx, storyName)
 			if err != nil {
 				if errors.Is(err, coreStory.ErrStoryNotFound) {
-					if !ocfg.isTTY() {
+					if noAttach || !ocfg.isTTY() {
 						return fmt.Errorf("%w: %s", coreStory.ErrStoryNotFound, storyName)
 					}
 
@@ -217,7 +276,9 @@ func NewOpenCmd(
 						return fmt.Errorf("%w: %s", coreStory.ErrStoryNotFound, storyName)
 					}
 
-					branch, branchErr := clistory.BranchFromTemplate(cfg.Story.BranchNameTemplate, storyName)
+					branch, branchErr := clistory.ResolveBranch(
+						ctx, mgr, cfg.Story.BranchNameTemplate, "", clistory.NewBranchNameData(storyName),
+					)
 					if branchErr != nil {
 						return fmt.Errorf("deriving branch name: %w", branchErr)
 					}
@@ -235,6 +296,10 @@ func NewOpenCmd(
 				}
 			}
 
+			if err := ports.Ensure(ctx, store, st, cfg.Ports.Base, cfg.Ports.BlockSize); err != nil {
+				return fmt.Errorf("allocating ports of story %q: %w", storyName, err)
+			}
+
 			slog.DebugContext(
 				ctx, "workspace open",
 				"story", storyName,
@@ -261,21 +326,11 @@ func NewOpenCmd(
 				return fmt.Errorf("pre-workspace-open hook: %w", err)
 			}
 
-			// Wrap exec so plugins are terminated before the process image is replaced.
-			// Close errors are logged but do not prevent exec.
-			closingExec := ExecFunc(func(argv0 string, argv, envv []string) error {
-				if err := mgr.Close(); err != nil {
-					slog.WarnContext(ctx, "error closing plugins before exec", "err", err)
-				}
-
-				return ocfg.exec(argv0, argv, envv)
-			})
-
 			var openErr error
 			if pickerClient != nil {
 				openErr = openWithPicker(
 					ctx, cmd, cfg, st, store, mgr, sess,
-					pickerClient, ocfg.lister, resolver, hooks, storyName, killPane, closingExec,
+					pickerClient, ocfg.lister, resolver, hooks, storyName, sw,
 				)
 				if openErr != nil {
 					slog.DebugContext(
@@ -286,15 +341,11 @@ func NewOpenCmd(
 
 					if grpcCode(openErr) == codes.FailedPrecondition {
 						slog.DebugContext(ctx, "falling back to openAllAttached (no TTY)")
-						openErr = openAllAttached(
-							ctx, cmd, cfg, st, sess, resolver, hooks, storyName, killPane, closingExec,
-						)
+						openErr = openAllAttached(ctx, cmd, cfg, st, sess, resolver, hooks, storyName, sw)
 					}
 				}
 			} else {
-				openErr = openAllAttached(
-					ctx, cmd, cfg, st, sess, resolver, hooks, storyName, killPane, closingExec,
-				)
+				openErr = openAllAttached(ctx, cmd, cfg, st, sess, resolver, hooks, storyName, sw)
 			}
 
 			return openErr
@@ -303,6 +354,13 @@ func NewOpenCmd(
 
 	cmd.Flags().BoolVar(&killPane, "kill-pane", false,
 		"close the originating multiplexer pane after switching to the new workspace")
+	cmd.Flags().StringVar(&prRef, "pr", "",
+		"open a review story for a pull request URL or [host/]owner/repo#number")
+	cmd.Flags().BoolVar(&ephemeral, "ephemeral", false,
+		"with --pr, remove the review story once the pull request is closed, "+
+			"the next time a review is opened or on `swm story prune`")
+	cmd.Flags().BoolVar(&noAttach, "no-attach", false,
+		"prepare the workspace without switching to it and print the result as JSON")
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
 		if len(args) > 0 {
@@ -325,6 +383,18 @@ func NewOpenCmd(
 	return cmd
 }
 
+// closingExecFunc wraps execFn so plugins are terminated before the process
+// image is replaced. Close errors are logged but do not prevent exec.
+func closingExecFunc(ctx context.Context, mgr pluginManager, execFn ExecFunc) ExecFunc {
+	return func(argv0 string, argv, envv []string) error {
+		if err := mgr.Close(); err != nil {
+			slog.WarnContext(ctx, "error closing plugins before exec", "err", err)
+		}
+
+		return execFn(argv0, argv, envv)
+	}
+}
+
 // buildSwitchToReq constructs a SwitchToRequest, adding origin-pane fields when
 // killPane is true and the caller is identifiably inside a multiplexer session.
 // The origin workspace ID is read directly from the host environment (not from the
@@ -385,15 +455,14 @@ func openWithPicker(
 	cfg *config.Config,
 	st *coreStory.Story,
 	store coreStory.Store,
-	mgr pluginManager,
+	mgr openPluginManager,
 	sess pluginv1.SessionClient,
 	pickerClient pluginv1.PickerClient,
 	lister ProjectLister,
 	resolver *layout.Resolver,
 	hooks hookexec.Runner,
 	storyName string,
-	killPane bool,
-	execFn ExecFunc,
+	sw *switchOptions,
 ) error {
 	// Build a deduplicated candidate set: attached projects + all on-disk repos.
 	candidates := buildCandidates(ctx, lister, st)
@@ -441,6 +510,28 @@ func openWithPicker(
 		return fmt.Errorf("parsing selected project key: %w", err)
 	}
 
+	return openProject(ctx, cmd, cfg, st, store, mgr, sess, resolver, hooks, storyName, pid, "", sw)
+}
+
+// openProject attaches pid to the story (creating its worktree on the story
+// branch, fetched from fetchRef when set) if needed, then opens its pane group
+// in the story's workspace and switches to it (or records it with --no-attach).
+func openProject(
+	ctx context.Context,
+	cmd *cobra.Command,
+	cfg *config.Config,
+	st *coreStory.Story,
+	store coreStory.Store,
+	mgr openPluginManager,
+	sess pluginv1.SessionClient,
+	resolver *layout.Resolver,
+	hooks hookexec.Runner,
+	storyName string,
+	pid *pluginv1.ProjectID,
+	fetchRef string,
+	sw *switchOptions,
+) error {
+	selectedKey := pid.GetHost() + "/" + strings.Join(pid.GetSegments(), "/")
 	worktreePath := resolver.WorktreePath(storyName, pid)
 
 	// Check whether this project is already attached to the story.
@@ -461,23 +552,24 @@ func openWithPicker(
 			return fmt.Errorf("pre-worktree-create hook: %w", err)
 		}
 
+		vcsName, err := mgr.VCSForPath(ctx, repoPath)
+		if err != nil {
+			return fmt.Errorf("routing %s to a vcs plugin: %w", repoPath, err)
+		}
+
 		if storyName != cfg.DefaultStory {
-			rawVCS, err := mgr.Get(ctx, "vcs")
+			vcs, err := mgr.GetVCS(ctx, vcsName)
 			if err != nil {
 				return fmt.Errorf("loading vcs plugin: %w", err)
 			}
 
-			vcs, ok := rawVCS.(pluginv1.VCSClient)
-			if !ok {
-				return fmt.Errorf("%w: %T", errUnexpectedPluginType, rawVCS)
-			}
-
 			if _, err := vcs.CreateWorktree(ctx, &pluginv1.CreateWorktreeRequest{
 				ProjectId:    pid,
 				StoryName:    storyName,
 				BranchName:   st.BranchName,
 				RepoPath:     repoPath,
 				WorktreePath: worktreePath,
+				FetchRef:     fetchRef,
 			}); err != nil {
 				return fmt.Errorf("creating worktree: %w", err)
 			}
@@ -487,6 +579,7 @@ func openWithPicker(
 		st.Projects = append(st.Projects, coreStory.Project{
 			Host:     pid.GetHost(),
 			Segments: pid.GetSegments(),
+			VCS:      vcsName,
 		})
 
 		if err := store.Update(ctx, st); err != nil {
@@ -505,29 +598,39 @@ func openWithPicker(
 		})
 	}
 
+	env, err := ports.StoryEnvironment(resolver, st, "", cfg.HistfilePath(storyName), cfg.Ports.BlockSize)
+	if err != nil {
+		return fmt.Errorf("reading named ports: %w", err)
+	}
+
 	// Ensure the workspace is open.
 	ws, err := sess.OpenWorkspace(ctx, &pluginv1.OpenWorkspaceRequest{
 		StoryName: storyName,
 		WorktreePaths: map[string]string{
 			selectedKey: worktreePath,
 		},
+		RestoreSnapshotPath: restoreSnapshotPath(cfg, storyName),
+		Env:                 env,
 	})
 	if err != nil {
 		return fmt.Errorf("opening workspace: %w", err)
 	}
 
-	pg, err := sess.OpenPaneGroup(ctx, &pluginv1.OpenPaneGroupRequest{
-		WorkspaceId:  ws.GetWorkspaceId(),
-		ProjectId:    pid,
-		WorktreePath: worktreePath,
-	})
+	pgReq, err := newPaneGroupRequest(cfg, resolver, st, storyName, ws.GetWorkspaceId(), pid, worktreePath)
+	if err != nil {
+		return err
+	}
+
+	pg, err := sess.OpenPaneGroup(ctx, pgReq)
 	if err != nil {
 		return fmt.Errorf("opening pane group: %w", err)
 	}
 
-	switchRes, err := sess.SwitchTo(ctx, buildSwitchToReq(ctx, sess, ws.GetWorkspaceId(), pg.GetPaneGroupId(), killPane))
+	sw.record(storyName, ws.GetWorkspaceId(), pg.GetPaneGroupId(), selectedKey, worktreePath)
+
+	argv, err := sw.switchTo(ctx, sess, ws.GetWorkspaceId(), pg.GetPaneGroupId())
 	if err != nil {
-		return fmt.Errorf("switching to pane group: %w", err)
+		return err
 	}
 
 	cmd.Printf("opened pane group %q in workspace %q\n", pg.GetPaneGroupId(), storyName)
@@ -541,16 +644,12 @@ func openWithPicker(
 		WorkDir:   worktreePath,
 	})
 
-	if argv := switchRes.GetExecArgv(); len(argv) > 0 {
-		if err := execFn(argv[0], argv, os.Environ()); err != nil {
-			return fmt.Errorf("exec after switch: %w", err)
-		}
-	}
-
-	return nil
+	return sw.execArgv(argv)
 }
 
-// openAllAttached is the Phase 1 fallback: open a workspace with all attached projects.
+// openAllAttached is the Phase 1 fallback: open a workspace with all attached
+// projects. With --no-attach every attached project gets a pane group, since
+// there is no terminal to open the others from later.
 func openAllAttached(
 	ctx context.Context,
 	cmd *cobra.Command,
@@ -560,8 +659,7 @@ func openAllAttached(
 	resolver *layout.Resolver,
 	hooks hookexec.Runner,
 	storyName string,
-	killPane bool,
-	execFn ExecFunc,
+	sw *switchOptions,
 ) error {
 	worktreePaths := make(map[string]string, len(st.Projects))
 
@@ -572,9 +670,16 @@ func openAllAttached(
 		worktreePaths[key] = resolver.WorktreePath(storyName, pid)
 	}
 
+	env, err := ports.StoryEnvironment(resolver, st, "", cfg.HistfilePath(storyName), cfg.Ports.BlockSize)
+	if err != nil {
+		return fmt.Errorf("reading named ports: %w", err)
+	}
+
 	ws, err := sess.OpenWorkspace(ctx, &pluginv1.OpenWorkspaceRequest{
-		StoryName:     storyName,
-		WorktreePaths: worktreePaths,
+		StoryName:           storyName,
+		WorktreePaths:       worktreePaths,
+		RestoreSnapshotPath: restoreSnapshotPath(cfg, storyName),
+		Env:                 env,
 	})
 	if err != nil {
 		return fmt.Errorf("opening workspace: %w", err)
@@ -582,24 +687,48 @@ func openAllAttached(
 
 	cmd.Printf("workspace opened for story %q\n", storyName)
 
+	sw.record(storyName, ws.GetWorkspaceId(), "", "", "")
+
 	// With no attached projects there is no pane group to switch to.
 	if len(st.Projects) == 0 {
-		return nil
+		if sw.result == nil {
+			return nil
+		}
+
+		_, err := sw.switchTo(ctx, sess, ws.GetWorkspaceId(), "")
+
+		return err
 	}
 
 	// Open a pane group for the first attached project and switch to it so that
 	// exec (tmux attach-session) works consistently with the picker path.
-	first := &st.Projects[0]
-	firstPID := &pluginv1.ProjectID{Host: first.Host, Segments: first.Segments}
-	firstKey := first.Host + "/" + strings.Join(first.Segments, "/")
-
-	pg, err := sess.OpenPaneGroup(ctx, &pluginv1.OpenPaneGroupRequest{
-		WorkspaceId:  ws.GetWorkspaceId(),
-		ProjectId:    firstPID,
-		WorktreePath: worktreePaths[firstKey],
-	})
-	if err != nil {
-		return fmt.Errorf("opening pane group: %w", err)
+	projects := st.Projects[:1]
+	if sw.result != nil {
+		projects = st.Projects
+	}
+
+	var firstKey, firstPG string
+
+	for i := range projects {
+		p := &projects[i]
+		pid := &pluginv1.ProjectID{Host: p.Host, Segments: p.Segments}
+		key := p.Host + "/" + strings.Join(p.Segments, "/")
+
+		pgReq, err := newPaneGroupRequest(cfg, resolver, st, storyName, ws.GetWorkspaceId(), pid, worktreePaths[key])
+		if err != nil {
+			return err
+		}
+
+		pg, err := sess.OpenPaneGroup(ctx, pgReq)
+		if err != nil {
+			return fmt.Errorf("opening pane group: %w", err)
+		}
+
+		sw.record(storyName, ws.GetWorkspaceId(), pg.GetPaneGroupId(), key, worktreePaths[key])
+
+		if i == 0 {
+			firstKey, firstPG = key, pg.GetPaneGroupId()
+		}
 	}
 
 	// Run the post hook before exec so it is not skipped when the host process
@@ -611,18 +740,50 @@ func openAllAttached(
 		WorkDir:   worktreePaths[firstKey],
 	})
 
-	switchRes, err := sess.SwitchTo(ctx, buildSwitchToReq(ctx, sess, ws.GetWorkspaceId(), pg.GetPaneGroupId(), killPane))
+	argv, err := sw.switchTo(ctx, sess, ws.GetWorkspaceId(), firstPG)
 	if err != nil {
-		return fmt.Errorf("switching to pane group: %w", err)
+		return err
+	}
+
+	return sw.execArgv(argv)
+}
+
+// newPaneGroupRequest returns the request opening the pane group of project
+// pid of story st in workspace wsID, with the project's environment and ports.
+func newPaneGroupRequest(
+	cfg *config.Config,
+	resolver *layout.Resolver,
+	st *coreStory.Story,
+	storyName, wsID string,
+	pid *pluginv1.ProjectID,
+	worktreePath string,
+) (*pluginv1.OpenPaneGroupRequest, error) {
+	key := pid.GetHost() + "/" + strings.Join(pid.GetSegments(), "/")
+
+	named, err := ports.Named(resolver, st, key, cfg.Ports.BlockSize)
+	if err != nil {
+		return nil, fmt.Errorf("reading named ports of %s: %w", key, err)
+	}
+
+	env := st.Environment(key, cfg.HistfilePath(storyName))
+	maps.Copy(env, ports.Variables(st.PortBase, named))
+
+	req := &pluginv1.OpenPaneGroupRequest{
+		WorkspaceId:  wsID,
+		ProjectId:    pid,
+		WorktreePath: worktreePath,
+		Env:          env,
+		PortBase:     uint32(st.PortBase), //nolint:gosec // ports are at most 65535
 	}
 
-	if argv := switchRes.GetExecArgv(); len(argv) > 0 {
-		if err := execFn(argv[0], argv, os.Environ()); err != nil {
-			return fmt.Errorf("exec after switch: %w", err)
+	if len(named) > 0 {
+		req.Ports = make(map[string]uint32, len(named))
+		for name, port := range named {
+			req.Ports[name] = uint32(port) //nolint:gosec // ports are at most 65535
 		}
 	}
 
-	return nil
+	return req, nil
 }
 
 // buildCandidates returns a deduplicated list of project key strings,
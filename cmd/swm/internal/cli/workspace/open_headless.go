@@ -0,0 +1,129 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"google.golang.org/grpc/codes"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+)
+
+var errKillPaneWithNoAttach = errors.New("--kill-pane cannot be combined with --no-attach")
+
+// switchOptions controls how `swm workspace open` hands an opened pane group
+// to the terminal.
+type switchOptions struct {
+	killPane bool
+	exec     ExecFunc
+	// result is set by --no-attach: SwitchTo is skipped and the opened
+	// workspace is recorded here instead, to be printed as JSON.
+	result *openResult
+}
+
+// openResult is the JSON document printed by `swm workspace open --no-attach`.
+type openResult struct {
+	Story         string            `json:"story"`
+	WorkspaceID   string            `json:"workspace_id"`
+	PaneGroups    []openedPaneGroup `json:"pane_groups"`
+	WorktreePaths map[string]string `json:"worktree_paths"`
+	// AttachCommand is the argv attaching a terminal to the first pane
+	// group; null when the session plugin cannot provide it.
+	AttachCommand []string `json:"attach_command"`
+}
+
+// openedPaneGroup is a pane group opened by `swm workspace open --no-attach`.
+type openedPaneGroup struct {
+	PaneGroupID  string `json:"pane_group_id"`
+	Project      string `json:"project"`
+	WorktreePath string `json:"worktree_path"`
+}
+
+// newOpenResult returns an empty result that marshals lists and maps as []
+// and {} rather than null.
+func newOpenResult() *openResult {
+	return &openResult{PaneGroups: []openedPaneGroup{}, WorktreePaths: map[string]string{}}
+}
+
+// print writes r to w as indented JSON.
+func (r *openResult) print(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("encoding workspace open result: %w", err)
+	}
+
+	return nil
+}
+
+// record adds the pane group pgID of project, checked out at worktreePath, to
+// the --no-attach result. An empty pgID records only the workspace. It is a
+// no-op without --no-attach.
+func (o *switchOptions) record(storyName, wsID, pgID, project, worktreePath string) {
+	if o.result == nil {
+		return
+	}
+
+	o.result.Story = storyName
+	o.result.WorkspaceID = wsID
+
+	if pgID == "" {
+		return
+	}
+
+	o.result.PaneGroups = append(o.result.PaneGroups, openedPaneGroup{
+		PaneGroupID:  pgID,
+		Project:      project,
+		WorktreePath: worktreePath,
+	})
+	o.result.WorktreePaths[project] = worktreePath
+}
+
+// switchTo brings pane group pgID of workspace wsID into focus and returns the
+// command the host must exec, if any. With --no-attach it only asks the
+// session plugin for the attach command and stores it in the result.
+func (o *switchOptions) switchTo(ctx context.Context, sess pluginv1.SessionClient, wsID, pgID string) ([]string, error) {
+	if o.result != nil {
+		res, err := sess.AttachCommand(ctx, &pluginv1.AttachCommandRequest{WorkspaceId: wsID, PaneGroupId: pgID})
+		if grpcCode(err) == codes.Unimplemented {
+			slog.DebugContext(ctx, "session plugin cannot report an attach command", "err", err)
+
+			return nil, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("getting attach command: %w", err)
+		}
+
+		o.result.AttachCommand = res.GetArgv()
+
+		return nil, nil
+	}
+
+	res, err := sess.SwitchTo(ctx, buildSwitchToReq(ctx, sess, wsID, pgID, o.killPane))
+	if err != nil {
+		return nil, fmt.Errorf("switching to pane group: %w", err)
+	}
+
+	return res.GetExecArgv(), nil
+}
+
+// execArgv replaces the process with argv, as returned by switchTo. An empty
+// argv is a no-op.
+func (o *switchOptions) execArgv(argv []string) error {
+	if len(argv) == 0 {
+		return nil
+	}
+
+	if err := o.exec(argv[0], argv, os.Environ()); err != nil {
+		return fmt.Errorf("exec after switch: %w", err)
+	}
+
+	return nil
+}
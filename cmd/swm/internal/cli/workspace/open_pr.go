@@ -0,0 +1,332 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	clistory "github.com/kalbasit/swm/cmd/swm/internal/cli/story"
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/clone"
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/hookexec"
+)
+
+// defaultPRHost is the forge host assumed for owner/repo#number references
+// that match no cloned repository.
+const defaultPRHost = "github.com"
+
+var (
+	errInvalidPRRef       = errors.New("invalid pull request: want a URL or [host/]owner/repo#number")
+	errPRWithStoryName    = errors.New("--pr cannot be combined with a story name")
+	errEphemeralWithoutPR = errors.New("--ephemeral requires --pr")
+	errNoForgeForPR       = errors.New("no forge plugin for pull request host")
+)
+
+// prRef identifies a pull request on a forge.
+type prRef struct {
+	project *pluginv1.ProjectID
+	number  int64
+}
+
+// parsePRRef parses a pull request URL (".../pull/<n>" or
+// ".../-/merge_requests/<n>") or a "[host/]owner/repo#<n>" reference. The host
+// of a reference without one is left empty.
+func parsePRRef(ref string) (*prRef, error) {
+	if strings.Contains(ref, "://") {
+		return parsePRURL(ref)
+	}
+
+	path, num, ok := strings.Cut(ref, "#")
+	if !ok {
+		return nil, fmt.Errorf("%q: %w", ref, errInvalidPRRef)
+	}
+
+	number, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || number <= 0 {
+		return nil, fmt.Errorf("%q: %w", ref, errInvalidPRRef)
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var host string
+
+	// A leading element with a dot is a hostname: owners and groups cannot
+	// contain dots on the forges swm supports.
+	if strings.Contains(segments[0], ".") {
+		host, segments = segments[0], segments[1:]
+	}
+
+	if len(segments) < 2 || slices.Contains(segments, "") { //nolint:mnd // need owner and repo
+		return nil, fmt.Errorf("%q: %w", ref, errInvalidPRRef)
+	}
+
+	return &prRef{project: &pluginv1.ProjectID{Host: host, Segments: segments}, number: number}, nil
+}
+
+func parsePRURL(raw string) (*prRef, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("%q: %w", raw, errInvalidPRRef)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	for i, part := range parts {
+		if (part != "pull" && part != "merge_requests") || i+1 >= len(parts) {
+			continue
+		}
+
+		number, err := strconv.ParseInt(parts[i+1], 10, 64)
+		if err != nil || number <= 0 {
+			break
+		}
+
+		// GitLab separates the project path from its pages with "/-/".
+		segments := parts[:i]
+		if n := len(segments); n > 0 && segments[n-1] == "-" {
+			segments = segments[:n-1]
+		}
+
+		if len(segments) < 2 { //nolint:mnd // need owner and repo
+			break
+		}
+
+		return &prRef{project: &pluginv1.ProjectID{Host: u.Host, Segments: segments}, number: number}, nil
+	}
+
+	return nil, fmt.Errorf("%q: %w", raw, errInvalidPRRef)
+}
+
+// prRefHost picks the host for a reference without one: the host of the only
+// cloned repository with the same path, or defaultPRHost.
+func prRefHost(ctx context.Context, lister ProjectLister, segments []string) string {
+	projects, err := lister.Projects(ctx)
+	if err != nil {
+		return defaultPRHost
+	}
+
+	var host string
+
+	for _, id := range projects {
+		if !slices.Equal(id.GetSegments(), segments) {
+			continue
+		}
+
+		if host != "" && host != id.GetHost() {
+			return defaultPRHost
+		}
+
+		host = id.GetHost()
+	}
+
+	if host == "" {
+		return defaultPRHost
+	}
+
+	return host
+}
+
+// reviewStoryName names the review story for a pull request: <repo>-pr-<number>.
+func reviewStoryName(ref *prRef) string {
+	segments := ref.project.GetSegments()
+
+	return fmt.Sprintf("%s-pr-%d", segments[len(segments)-1], ref.number)
+}
+
+// reviewBranchName returns the local branch for a pull request. Branches
+// from forks are namespaced so they cannot clash with the base repository's
+// own branches (a fork's "main", for example).
+func reviewBranchName(pr *pluginv1.PullRequest) string {
+	if pr.GetHeadBranch() == "" {
+		return fmt.Sprintf("pr/%d", pr.GetNumber())
+	}
+
+	if pr.GetFromFork() {
+		return fmt.Sprintf("pr/%d/%s", pr.GetNumber(), pr.GetHeadBranch())
+	}
+
+	return pr.GetHeadBranch()
+}
+
+// openPullRequest opens the review story for the pull request ref, cloning
+// the repository and creating the story and its worktree when missing.
+func openPullRequest(
+	ctx context.Context,
+	cmd *cobra.Command,
+	cfg *config.Config,
+	store coreStory.Store,
+	mgr openPluginManager,
+	lister ProjectLister,
+	resolver *layout.Resolver,
+	hooks hookexec.Runner,
+	ref string,
+	ephemeral bool,
+	sw *switchOptions,
+) error {
+	pr, err := parsePRRef(ref)
+	if err != nil {
+		return err
+	}
+
+	if pr.project.GetHost() == "" {
+		pr.project.Host = prRefHost(ctx, lister, pr.project.GetSegments())
+	}
+
+	forge, err := mgr.GetForge(ctx, pr.project.GetHost())
+	if err != nil {
+		return fmt.Errorf("%w %q: %w", errNoForgeForPR, pr.project.GetHost(), err)
+	}
+
+	remote, err := forge.GetPullRequest(ctx, &pluginv1.GetPRRequest{ProjectId: pr.project, Number: pr.number})
+	if err != nil {
+		return fmt.Errorf("getting pull request #%d: %w", pr.number, err)
+	}
+
+	cloneURL := remote.GetCloneUrl()
+	if cloneURL == "" {
+		// Forges that do not report a clone URL get the https one.
+		cloneURL = "https://" + pr.project.GetHost() + "/" + strings.Join(pr.project.GetSegments(), "/") + ".git"
+	}
+
+	vcs, err := pullRequestVCS(ctx, mgr, resolver.CanonicalPath(pr.project), cloneURL)
+	if err != nil {
+		return err
+	}
+
+	cloned, err := clone.Run(ctx, vcs, resolver, hooks, cloneURL, cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	if !cloned.Existing {
+		cmd.Printf("cloned to %s\n", cloned.Path)
+	}
+
+	st, err := reviewStory(ctx, cfg, store, mgr, hooks, pr, remote, ephemeral)
+	if err != nil {
+		return err
+	}
+
+	// Opening a review is when earlier ones are likely done with, so remove
+	// the closed ephemeral reviews, except this one and the one the caller is
+	// working in.
+	if err := clistory.PruneClosed(
+		ctx, cmd, store, mgr, resolver, hooks, false, st.Name, os.Getenv("SWM_STORY"),
+	); err != nil {
+		slog.WarnContext(ctx, "pruning closed review stories", "err", err)
+	}
+
+	sess, err := sessionClient(ctx, mgr)
+	if err != nil {
+		return err
+	}
+
+	if err := hooks.Run(ctx, hookexec.RunConfig{
+		Event:     "pre-workspace-open",
+		CodeRoot:  cfg.CodeRoot,
+		StoryName: st.Name,
+		WorkDir:   cfg.CodeRoot,
+	}); err != nil {
+		return fmt.Errorf("pre-workspace-open hook: %w", err)
+	}
+
+	return openProject(
+		ctx, cmd, cfg, st, store, mgr, sess, resolver, hooks,
+		st.Name, pr.project, remote.GetFetchRef(), sw,
+	)
+}
+
+// pullRequestVCS returns the vcs plugin for a pull request's project: the one
+// owning the clone at canonical when there is one, or the one claiming
+// cloneURL otherwise.
+func pullRequestVCS(
+	ctx context.Context,
+	mgr openPluginManager,
+	canonical, cloneURL string,
+) (pluginv1.VCSClient, error) {
+	var (
+		name string
+		err  error
+	)
+
+	if _, statErr := os.Stat(canonical); statErr == nil {
+		name, err = mgr.VCSForPath(ctx, canonical)
+	} else {
+		name, err = mgr.VCSForURL(ctx, cloneURL)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("routing %q to a vcs plugin: %w", cloneURL, err)
+	}
+
+	vcs, err := mgr.GetVCS(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("loading vcs plugin: %w", err)
+	}
+
+	return vcs, nil
+}
+
+// reviewStory returns the review story for pr, creating it on the pull
+// request's branch, once the VCS plugin accepts it as a branch name, when it
+// does not exist yet.
+func reviewStory(
+	ctx context.Context,
+	cfg *config.Config,
+	store coreStory.Store,
+	mgr clistory.BranchPluginManager,
+	hooks hookexec.Runner,
+	pr *prRef,
+	remote *pluginv1.PullRequest,
+	ephemeral bool,
+) (*coreStory.Story, error) {
+	name := reviewStoryName(pr)
+
+	st, err := store.Get(ctx, name)
+
+	switch {
+	case errors.Is(err, coreStory.ErrStoryNotFound):
+		branch, err := clistory.ResolveBranch(ctx, mgr, "", reviewBranchName(remote), clistory.NewBranchNameData(name))
+		if err != nil {
+			return nil, fmt.Errorf("branch of pull request #%d: %w", pr.number, err)
+		}
+
+		if err := clistory.CreateWithHooks(ctx, store, hooks, cfg.CodeRoot, name, branch); err != nil {
+			return nil, err
+		}
+
+		if st, err = store.Get(ctx, name); err != nil {
+			return nil, fmt.Errorf("loading story %q after creation: %w", name, err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("loading story %q: %w", name, err)
+	case st.PullRequest != nil && (!ephemeral || st.Ephemeral):
+		return st, nil
+	}
+
+	st.PullRequest = &coreStory.PullRequest{
+		Host:     pr.project.GetHost(),
+		Segments: pr.project.GetSegments(),
+		Number:   pr.number,
+		URL:      remote.GetUrl(),
+	}
+	st.Ephemeral = st.Ephemeral || ephemeral
+
+	if err := store.Update(ctx, st); err != nil {
+		return nil, fmt.Errorf("recording pull request on story %q: %w", name, err)
+	}
+
+	return st, nil
+}
@@ -0,0 +1,327 @@
+package workspace_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/workspace"
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/hookexec"
+)
+
+const (
+	testPRURL       = "https://github.com/kalbasit/swm/pull/123"
+	testReviewStory = "swm-pr-123"
+	testPRFetchRef  = "refs/pull/123/head"
+)
+
+func TestParsePRRef(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ref      string
+		host     string
+		segments []string
+		number   int64
+		wantErr  bool
+	}{
+		{name: "github URL", ref: testPRURL, host: testHost, segments: []string{testOwner, testSegment}, number: 123},
+		{
+			name:     "gitlab merge request URL",
+			ref:      "https://gitlab.com/group/sub/repo/-/merge_requests/7",
+			host:     "gitlab.com",
+			segments: []string{"group", "sub", "repo"},
+			number:   7,
+		},
+		{name: "short reference", ref: "kalbasit/swm#123", segments: []string{testOwner, testSegment}, number: 123},
+		{
+			name:     "short reference with host",
+			ref:      "github.example.com/kalbasit/swm#9",
+			host:     "github.example.com",
+			segments: []string{testOwner, testSegment},
+			number:   9,
+		},
+		{name: "missing number", ref: "kalbasit/swm", wantErr: true},
+		{name: "non-numeric number", ref: "kalbasit/swm#abc", wantErr: true},
+		{name: "missing repository", ref: "kalbasit#1", wantErr: true},
+		{name: "URL without pull path", ref: "https://github.com/kalbasit/swm", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			id, number, err := workspace.ParsePRRef(tt.ref)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.host, id.GetHost())
+			require.Equal(t, tt.segments, id.GetSegments())
+			require.Equal(t, tt.number, number)
+		})
+	}
+}
+
+func TestReviewBranchName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "fix/bug", workspace.ReviewBranchName(&pluginv1.PullRequest{Number: 1, HeadBranch: "fix/bug"}))
+	require.Equal(t, "pr/2/main",
+		workspace.ReviewBranchName(&pluginv1.PullRequest{Number: 2, HeadBranch: "main", FromFork: true}))
+	require.Equal(t, "pr/3", workspace.ReviewBranchName(&pluginv1.PullRequest{Number: 3}))
+}
+
+func TestOpenCmd_PR_CreatesReviewStory(t *testing.T) {
+	t.Parallel()
+
+	e := newPREnv(t, &pluginv1.PullRequest{
+		Number:     123,
+		HeadBranch: "feat/thing",
+		Url:        testPRURL,
+		FetchRef:   testPRFetchRef,
+	})
+
+	require.NoError(t, e.run(t, "--pr", testPRURL, "--ephemeral"))
+
+	require.Equal(t, testReviewStory, e.sess.lastOpenReq.GetStoryName())
+	require.Equal(t, testPRFetchRef, e.vcs.lastCreateReq.GetFetchRef())
+	require.Equal(t, "feat/thing", e.vcs.lastCreateReq.GetBranchName())
+	require.Equal(t, int64(123), e.forge.lastReq.GetNumber())
+
+	st, err := e.store.Get(context.Background(), testReviewStory)
+	require.NoError(t, err)
+	require.Equal(t, "feat/thing", st.BranchName)
+	require.True(t, st.Ephemeral)
+	require.Equal(t, &coreStory.PullRequest{
+		Host:     testHost,
+		Segments: []string{testOwner, testSegment},
+		Number:   123,
+		URL:      testPRURL,
+	}, st.PullRequest)
+	require.Len(t, st.Projects, 1)
+}
+
+func TestOpenCmd_PR_ForkUsesNamespacedBranch(t *testing.T) {
+	t.Parallel()
+
+	e := newPREnv(t, &pluginv1.PullRequest{
+		Number:     123,
+		HeadBranch: "main",
+		FromFork:   true,
+		FetchRef:   testPRFetchRef,
+	})
+
+	require.NoError(t, e.run(t, "--pr", "kalbasit/swm#123"))
+
+	require.Equal(t, "pr/123/main", e.vcs.lastCreateReq.GetBranchName())
+
+	st, err := e.store.Get(context.Background(), testReviewStory)
+	require.NoError(t, err)
+	require.False(t, st.Ephemeral)
+	require.NotNil(t, st.PullRequest)
+}
+
+func TestOpenCmd_PR_InvalidHeadBranchFails(t *testing.T) {
+	t.Parallel()
+
+	e := newPREnv(t, &pluginv1.PullRequest{Number: 123, HeadBranch: "feat..x", FetchRef: testPRFetchRef})
+	e.vcs.invalidBranch = "feat..x"
+
+	require.ErrorContains(t, e.run(t, "--pr", testPRURL), "not a valid branch name")
+
+	_, err := e.store.Get(context.Background(), testReviewStory)
+	require.ErrorIs(t, err, coreStory.ErrStoryNotFound)
+	require.False(t, e.vcs.createCalled)
+}
+
+func TestOpenCmd_PR_ClonesFromForgeCloneURL(t *testing.T) {
+	t.Parallel()
+
+	const cloneURL = "ssh://git@github.com/kalbasit/swm.git"
+
+	e := newPREnv(t, &pluginv1.PullRequest{
+		Number:     123,
+		HeadBranch: "feat/thing",
+		FetchRef:   testPRFetchRef,
+		CloneUrl:   cloneURL,
+	})
+	e.mgr.vcs = &stubVCS{} // the default plugin, which must not be used
+	e.mgr.namedVCS = map[string]pluginv1.VCSClient{"git": e.vcs}
+	e.mgr.urlVCS = "git"
+	e.mgr.pathVCS = "git"
+
+	id := &pluginv1.ProjectID{Host: testHost, Segments: []string{testOwner, testSegment}}
+	require.NoError(t, os.RemoveAll(e.resolver.CanonicalPath(id)))
+
+	require.NoError(t, e.run(t, "--pr", testPRURL))
+
+	require.Equal(t, cloneURL, e.mgr.lastURL)
+	require.Equal(t, cloneURL, e.vcs.lastCloneReq.GetUrl())
+	require.Equal(t, "feat/thing", e.vcs.lastCreateReq.GetBranchName())
+}
+
+func TestOpenCmd_PR_ExistingCloneUsesItsVCS(t *testing.T) {
+	t.Parallel()
+
+	e := newPREnv(t, &pluginv1.PullRequest{Number: 123, HeadBranch: "feat/thing", FetchRef: testPRFetchRef})
+	e.mgr.vcs = &stubVCS{} // the default plugin, which must not be used
+	e.mgr.namedVCS = map[string]pluginv1.VCSClient{"hg": e.vcs}
+	e.mgr.pathVCS = "hg"
+
+	require.NoError(t, e.run(t, "--pr", testPRURL))
+
+	require.Empty(t, e.mgr.lastURL)
+	require.Nil(t, e.vcs.lastCloneReq)
+	require.Equal(t, "feat/thing", e.vcs.lastCreateReq.GetBranchName())
+}
+
+func TestOpenCmd_PR_ExistingStoryIsReused(t *testing.T) {
+	t.Parallel()
+
+	e := newPREnv(t, &pluginv1.PullRequest{Number: 123, HeadBranch: "feat/thing", FetchRef: testPRFetchRef})
+
+	require.NoError(t, e.run(t, "--pr", testPRURL))
+	require.NoError(t, e.run(t, "--pr", testPRURL, "--ephemeral"))
+
+	st, err := e.store.Get(context.Background(), testReviewStory)
+	require.NoError(t, err)
+	require.True(t, st.Ephemeral)
+	require.Len(t, st.Projects, 1)
+}
+
+func TestOpenCmd_PR_PrunesClosedReviews(t *testing.T) {
+	t.Parallel()
+
+	e := newPREnv(t, &pluginv1.PullRequest{Number: 123, HeadBranch: "feat/thing", FetchRef: testPRFetchRef})
+	e.forge.others = pluginv1.PullRequestState_PULL_REQUEST_STATE_MERGED
+
+	for _, number := range []int64{7, 8} {
+		st, err := e.store.Create(context.Background(), fmt.Sprintf("swm-pr-%d", number), "feat")
+		require.NoError(t, err)
+
+		st.Ephemeral = number == 7
+		st.PullRequest = &coreStory.PullRequest{
+			Host:     testHost,
+			Segments: []string{testOwner, testSegment},
+			Number:   number,
+		}
+		require.NoError(t, e.store.Update(context.Background(), st))
+	}
+
+	require.NoError(t, e.run(t, "--pr", testPRURL, "--ephemeral"))
+
+	_, err := e.store.Get(context.Background(), "swm-pr-7")
+	require.ErrorIs(t, err, coreStory.ErrStoryNotFound, "the merged ephemeral review is removed")
+
+	_, err = e.store.Get(context.Background(), "swm-pr-8")
+	require.NoError(t, err, "reviews that are not ephemeral are kept")
+
+	_, err = e.store.Get(context.Background(), testReviewStory)
+	require.NoError(t, err, "the review being opened is kept")
+}
+
+func TestOpenCmd_PR_WithStoryNameFails(t *testing.T) {
+	t.Parallel()
+
+	e := newPREnv(t, &pluginv1.PullRequest{Number: 123})
+
+	require.ErrorContains(t, e.run(t, "--pr", testPRURL, testStoryName), "cannot be combined")
+}
+
+func TestOpenCmd_EphemeralWithoutPRFails(t *testing.T) {
+	t.Parallel()
+
+	e := newPREnv(t, &pluginv1.PullRequest{Number: 123})
+
+	require.ErrorContains(t, e.run(t, "--ephemeral", testStoryName), "--ephemeral requires --pr")
+}
+
+func TestOpenCmd_PR_InvalidRefFails(t *testing.T) {
+	t.Parallel()
+
+	e := newPREnv(t, &pluginv1.PullRequest{Number: 123})
+
+	require.ErrorContains(t, e.run(t, "--pr", "not-a-pr"), "invalid pull request")
+}
+
+// prEnv wires `swm workspace open --pr` against a JSON story store and a code
+// root in which kalbasit/swm is already cloned.
+type prEnv struct {
+	cfg      *config.Config
+	store    coreStory.Store
+	resolver *layout.Resolver
+	sess     *stubSess
+	vcs      *stubVCS
+	forge    *stubPRForge
+	mgr      *stubMgr
+}
+
+func newPREnv(t *testing.T, pr *pluginv1.PullRequest) *prEnv {
+	t.Helper()
+
+	codeRoot := t.TempDir()
+	resolver := layout.NewResolver(codeRoot, testDefaultStory)
+	id := &pluginv1.ProjectID{Host: testHost, Segments: []string{testOwner, testSegment}}
+
+	require.NoError(t, os.MkdirAll(filepath.Join(resolver.CanonicalPath(id), ".git"), 0o750))
+
+	e := &prEnv{
+		cfg:      &config.Config{CodeRoot: codeRoot, DefaultStory: testDefaultStory},
+		store:    coreStory.NewJSONStore(t.TempDir()),
+		resolver: resolver,
+		sess:     &stubSess{},
+		vcs:      &stubVCS{parsedID: id},
+		forge:    &stubPRForge{pr: pr},
+	}
+	e.mgr = &stubMgr{sess: e.sess, vcs: e.vcs, forge: e.forge}
+
+	return e
+}
+
+func (e *prEnv) run(t *testing.T, args ...string) error {
+	t.Helper()
+
+	cmd := workspace.NewOpenCmd(e.cfg, e.store, e.mgr, e.resolver, hookexec.Noop)
+	cmd.SetArgs(args)
+
+	return cmd.Execute()
+}
+
+// stubPRForge embeds the client interface and only implements GetPullRequest.
+// Pull requests other than pr are reported in state others.
+type stubPRForge struct {
+	pluginv1.ForgeClient
+
+	pr      *pluginv1.PullRequest
+	others  pluginv1.PullRequestState
+	lastReq *pluginv1.GetPRRequest
+}
+
+func (f *stubPRForge) GetPullRequest(
+	_ context.Context,
+	req *pluginv1.GetPRRequest,
+	_ ...grpc.CallOption,
+) (*pluginv1.PullRequest, error) {
+	if req.GetNumber() != f.pr.GetNumber() {
+		return &pluginv1.PullRequest{Number: req.GetNumber(), State: f.others}, nil
+	}
+
+	f.lastReq = req
+
+	return f.pr, nil
+}
@@ -2,9 +2,13 @@ package workspace_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -66,6 +70,46 @@ func TestOpenCmd_WithPositionalArg(t *testing.T) {
 	require.Equal(t, testStoryName, sess.lastOpenReq.GetStoryName())
 }
 
+func TestOpenCmd_RestoreOnOpen_PassesSnapshotPath(t *testing.T) {
+	t.Parallel()
+
+	dataHome := t.TempDir()
+	cfg := &config.Config{
+		CodeRoot:     testCodeRoot,
+		DefaultStory: testDefaultStory,
+		DataHome:     dataHome,
+		Workspace:    config.Workspace{RestoreOnOpen: true},
+	}
+	store := &stubStore{getStory: &coreStory.Story{Name: testStoryName}}
+	sess := &stubSess{}
+	mgr := &stubMgr{sess: sess}
+	resolver := layout.NewResolver(testCodeRoot, testDefaultStory)
+
+	cmd := workspace.NewOpenCmd(cfg, store, mgr, resolver, hookexec.Noop)
+	cmd.SetArgs([]string{testStoryName})
+
+	require.NoError(t, cmd.Execute())
+	require.Equal(t,
+		filepath.Join(dataHome, "swm", "stories", testStoryName, "session-snapshot.json"),
+		sess.lastOpenReq.GetRestoreSnapshotPath())
+}
+
+func TestOpenCmd_RestoreOnOpenDisabled_NoSnapshotPath(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{CodeRoot: testCodeRoot, DefaultStory: testDefaultStory, DataHome: t.TempDir()}
+	store := &stubStore{getStory: &coreStory.Story{Name: testStoryName}}
+	sess := &stubSess{}
+	mgr := &stubMgr{sess: sess}
+	resolver := layout.NewResolver(testCodeRoot, testDefaultStory)
+
+	cmd := workspace.NewOpenCmd(cfg, store, mgr, resolver, hookexec.Noop)
+	cmd.SetArgs([]string{testStoryName})
+
+	require.NoError(t, cmd.Execute())
+	require.Empty(t, sess.lastOpenReq.GetRestoreSnapshotPath())
+}
+
 func TestOpenCmd_PositionalArgOverridesEnv(t *testing.T) {
 	t.Setenv("SWM_STORY", "env-story")
 
@@ -131,7 +175,7 @@ func TestOpenCmd_StoryNotFound_TTY_Confirms(t *testing.T) {
 		getErrs:    []error{coreStory.ErrStoryNotFound, nil},
 		getStories: []*coreStory.Story{nil, {Name: storyToCreate}},
 	}
-	mgr := &stubMgr{sess: sess}
+	mgr := &stubMgr{sess: sess, vcs: &stubVCS{}}
 	resolver := layout.NewResolver(testCodeRoot, testDefaultStory)
 
 	cmd := workspace.NewOpenCmd(
@@ -151,7 +195,7 @@ func TestOpenCmd_StoryNotFound_TTY_PreHookAborts(t *testing.T) {
 
 	cfg := &config.Config{CodeRoot: testCodeRoot, DefaultStory: testDefaultStory}
 	store := &stubStore{getErr: coreStory.ErrStoryNotFound}
-	mgr := &stubMgr{}
+	mgr := &stubMgr{vcs: &stubVCS{}}
 	resolver := layout.NewResolver(testCodeRoot, testDefaultStory)
 
 	hooks := hookexec.RunnerFunc(func(_ context.Context, rc hookexec.RunConfig) error {
@@ -212,7 +256,7 @@ func TestOpenCmd_StoryNotFound_TTY_UsesConfigTemplate(t *testing.T) {
 		getErrs:    []error{coreStory.ErrStoryNotFound, nil},
 		getStories: []*coreStory.Story{nil, {Name: storyToCreate}},
 	}
-	mgr := &stubMgr{sess: sess}
+	mgr := &stubMgr{sess: sess, vcs: &stubVCS{}}
 	resolver := layout.NewResolver(testCodeRoot, testDefaultStory)
 
 	cmd := workspace.NewOpenCmd(
@@ -227,6 +271,48 @@ func TestOpenCmd_StoryNotFound_TTY_UsesConfigTemplate(t *testing.T) {
 	require.Equal(t, "fix/"+storyToCreate, store.lastCreatedBranch)
 }
 
+func TestOpenCmd_StoryNotFound_TTY_RendersAndValidatesLikeStoryCreate(t *testing.T) {
+	t.Parallel()
+
+	const storyToCreate = testNonexistentStory
+
+	newCmd := func(vcs *stubVCS, store *stubStore) *cobra.Command {
+		cfg := &config.Config{
+			CodeRoot:     testCodeRoot,
+			DefaultStory: testDefaultStory,
+			Story:        config.Story{BranchNameTemplate: "{{.GitName | slug}}/{{.Name}}"},
+		}
+
+		cmd := workspace.NewOpenCmd(
+			cfg, store, &stubMgr{sess: &stubSess{}, vcs: vcs}, layout.NewResolver(testCodeRoot, testDefaultStory),
+			hookexec.Noop,
+			workspace.WithTTYCheck(func() bool { return true }),
+			workspace.WithStdinReader(strings.NewReader("y\n")),
+		)
+		cmd.SetArgs([]string{storyToCreate})
+
+		return cmd
+	}
+
+	// The template sees the VCS identity.
+	store := &stubStore{
+		getErrs:    []error{coreStory.ErrStoryNotFound, nil},
+		getStories: []*coreStory.Story{nil, {Name: storyToCreate}},
+	}
+	vcs := &stubVCS{identity: &pluginv1.UserIdentity{Name: "Jane Doe"}}
+
+	require.NoError(t, newCmd(vcs, store).Execute())
+	require.Equal(t, "jane-doe/"+storyToCreate, store.lastCreatedBranch)
+
+	// The VCS plugin rejects invalid branch names before the story exists.
+	store = &stubStore{getErr: coreStory.ErrStoryNotFound}
+	vcs = &stubVCS{identity: &pluginv1.UserIdentity{Name: "Jane Doe"}, invalidBranch: "jane-doe/" + storyToCreate}
+
+	err := newCmd(vcs, store).Execute()
+	require.ErrorContains(t, err, "not a valid branch name")
+	require.False(t, store.createCalled)
+}
+
 func TestOpenCmd_NoProjects(t *testing.T) {
 	t.Parallel()
 
@@ -306,6 +392,36 @@ func TestOpenCmd_WithPicker_ProjectNotAttached(t *testing.T) {
 	require.NotNil(t, sess.lastPaneGroupReq)
 }
 
+func TestOpenCmd_WithPicker_ProjectNotAttached_RoutesToProjectVCS(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{CodeRoot: testCodeRoot, DefaultStory: testDefaultStory}
+	store := &stubStore{getStory: &coreStory.Story{
+		Name:       testStoryName,
+		BranchName: testBranchName,
+	}}
+	git := &stubVCS{}
+	jj := &stubVCS{}
+	mgr := &stubMgr{
+		sess:     &stubSess{},
+		vcs:      git,
+		picker:   &stubPickerClient{selectedKey: "github.com/kalbasit/dotfiles"},
+		namedVCS: map[string]pluginv1.VCSClient{"jj": jj},
+		pathVCS:  "jj",
+	}
+	resolver := layout.NewResolver(testCodeRoot, testDefaultStory)
+
+	cmd := workspace.NewOpenCmd(cfg, store, mgr, resolver, hookexec.Noop)
+	cmd.SetArgs([]string{testStoryName})
+
+	require.NoError(t, cmd.Execute())
+
+	require.False(t, git.createCalled)
+	require.True(t, jj.createCalled)
+	require.Len(t, store.updatedStory.Projects, 1)
+	require.Equal(t, "jj", store.updatedStory.Projects[0].VCS)
+}
+
 func TestOpenCmd_WithPicker_DefaultStory_ProjectNotAttached_SkipsCreateWorktree(t *testing.T) {
 	t.Parallel()
 
@@ -674,6 +790,85 @@ func TestOpenCmd_NoPicker_FallsBackToPhase1(t *testing.T) {
 	require.Contains(t, sess.lastOpenReq.GetWorktreePaths(), "github.com/kalbasit/swm")
 }
 
+func TestOpenCmd_NoPicker_PassesStoryEnv(t *testing.T) {
+	t.Parallel()
+
+	const key = testHost + "/" + testOwner + "/" + testSegment
+
+	dataHome := t.TempDir()
+	cfg := &config.Config{
+		CodeRoot:     testCodeRoot,
+		DefaultStory: testDefaultStory,
+		DataHome:     dataHome,
+		Story:        config.Story{Histfile: true},
+	}
+	store := &stubStore{getStory: &coreStory.Story{
+		Name:       testStoryName,
+		Projects:   []coreStory.Project{{Host: testHost, Segments: []string{testOwner, testSegment}}},
+		Env:        map[string]string{"AWS_PROFILE": "dev", "PORT": "3000"},
+		ProjectEnv: map[string]map[string]string{key: {"PORT": "3001"}},
+	}}
+	sess := &stubSess{}
+	mgr := &stubMgr{sess: sess}
+	resolver := layout.NewResolver(testCodeRoot, testDefaultStory)
+
+	cmd := workspace.NewOpenCmd(cfg, store, mgr, resolver, hookexec.Noop)
+	cmd.SetArgs([]string{testStoryName})
+
+	require.NoError(t, cmd.Execute())
+
+	histfile := filepath.Join(dataHome, "swm", "stories", testStoryName, "history")
+	require.Equal(t,
+		map[string]string{"AWS_PROFILE": "dev", "PORT": "3000", "HISTFILE": histfile},
+		sess.lastOpenReq.GetEnv())
+	require.Equal(t,
+		map[string]string{"AWS_PROFILE": "dev", "PORT": "3001", "HISTFILE": histfile},
+		sess.lastPaneGroupReq.GetEnv())
+}
+
+func TestOpenCmd_NoPicker_AllocatesAndPassesPorts(t *testing.T) {
+	t.Parallel()
+
+	codeRoot := t.TempDir()
+	wt := filepath.Join(codeRoot, "stories", testStoryName, testHost, testOwner, testSegment)
+	require.NoError(t, os.MkdirAll(filepath.Join(wt, ".swm"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(wt, ".swm", "project.toml"), []byte("[ports]\napi = 1\n"), 0o600))
+
+	cfg := &config.Config{
+		CodeRoot:     codeRoot,
+		DefaultStory: testDefaultStory,
+		Ports:        config.Ports{Base: 20000, BlockSize: 100},
+	}
+	store := &stubStore{getStory: &coreStory.Story{
+		Name:     testStoryName,
+		Projects: []coreStory.Project{{Host: testHost, Segments: []string{testOwner, testSegment}}},
+	}}
+	sess := &stubSess{}
+	mgr := &stubMgr{sess: sess}
+	resolver := layout.NewResolver(codeRoot, testDefaultStory)
+
+	cmd := workspace.NewOpenCmd(cfg, store, mgr, resolver, hookexec.Noop)
+	cmd.SetArgs([]string{testStoryName})
+
+	require.NoError(t, cmd.Execute())
+
+	require.True(t, store.updateCalled, "a story without a port block must get one")
+
+	base := store.updatedStory.PortBase
+	require.NotZero(t, base)
+
+	want := map[string]string{
+		"SWM_PORT_BASE": strconv.Itoa(base),
+		"SWM_PORT_API":  strconv.Itoa(base + 1),
+	}
+	require.Equal(t, want, sess.lastOpenReq.GetEnv())
+	require.Equal(t, want, sess.lastPaneGroupReq.GetEnv())
+
+	port := uint32(base) //nolint:gosec // blocks end below 65536
+	require.Equal(t, port, sess.lastPaneGroupReq.GetPortBase())
+	require.Equal(t, map[string]uint32{"api": port + 1}, sess.lastPaneGroupReq.GetPorts())
+}
+
 func TestOpenCmd_NoPicker_ExecArgvIsExeced(t *testing.T) {
 	t.Parallel()
 
@@ -1056,6 +1251,135 @@ func TestOpenCmd_NoKillPane_OmitsOriginFields(t *testing.T) {
 	require.Empty(t, sess.lastSwitchReq.GetCloseOriginPaneId())
 }
 
+// ─── --no-attach tests ────────────────────────────────────────────────────────
+
+func TestOpenCmd_NoAttach_PrintsJSONWithoutSwitching(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{CodeRoot: testCodeRoot, DefaultStory: testDefaultStory}
+	store := &stubStore{getStory: &coreStory.Story{
+		Name: testStoryName,
+		Projects: []coreStory.Project{
+			{Host: testHost, Segments: []string{testOwner, testSegment}},
+			{Host: testHost, Segments: []string{testOwner, "dotfiles"}},
+		},
+	}}
+	sess := &stubSess{}
+	// An invalid picker selection would fail the command: --no-attach must not pick.
+	picker := &stubPickerClient{selectedKey: "not-a-project"}
+	mgr := &stubMgr{sess: sess, picker: picker}
+	resolver := layout.NewResolver(testCodeRoot, testDefaultStory)
+
+	var stdout, stderr strings.Builder
+
+	cmd := workspace.NewOpenCmd(cfg, store, mgr, resolver, hookexec.Noop,
+		workspace.WithExecFunc(func(string, []string, []string) error {
+			t.Error("exec must not be called with --no-attach")
+
+			return nil
+		}),
+	)
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{testStoryName, "--no-attach"})
+
+	require.NoError(t, cmd.Execute())
+	require.Nil(t, sess.lastSwitchReq, "SwitchTo must not be called with --no-attach")
+	require.Contains(t, stderr.String(), "workspace opened", "progress messages go to stderr")
+
+	var got struct {
+		Story       string `json:"story"`
+		WorkspaceID string `json:"workspace_id"`
+		PaneGroups  []struct {
+			PaneGroupID  string `json:"pane_group_id"`
+			Project      string `json:"project"`
+			WorktreePath string `json:"worktree_path"`
+		} `json:"pane_groups"`
+		WorktreePaths map[string]string `json:"worktree_paths"`
+		AttachCommand []string          `json:"attach_command"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout.String()), &got), stdout.String())
+
+	require.Equal(t, testStoryName, got.Story)
+	require.Equal(t, testTargetWorkspaceID, got.WorkspaceID)
+	require.Len(t, got.PaneGroups, 2, "every attached project gets a pane group")
+	require.Equal(t, "github.com/kalbasit/swm", got.PaneGroups[0].Project)
+	require.Equal(t, testTargetPaneGroupID, got.PaneGroups[0].PaneGroupID)
+	require.Equal(t, "/code/stories/feat-x/github.com/kalbasit/swm", got.PaneGroups[0].WorktreePath)
+	require.Contains(t, got.WorktreePaths, "github.com/kalbasit/dotfiles")
+	require.Equal(t,
+		[]string{"tmux", "-S", testTargetWorkspaceID, testTmuxAttachSession, "-t", testTargetPaneGroupID},
+		got.AttachCommand)
+}
+
+func TestOpenCmd_NoAttach_NoProjects(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{CodeRoot: testCodeRoot, DefaultStory: testDefaultStory}
+	store := &stubStore{getStory: &coreStory.Story{Name: testStoryName}}
+	sess := &stubSess{}
+	mgr := &stubMgr{sess: sess}
+	resolver := layout.NewResolver(testCodeRoot, testDefaultStory)
+
+	var stdout strings.Builder
+
+	cmd := workspace.NewOpenCmd(cfg, store, mgr, resolver, hookexec.Noop)
+	cmd.SetOut(&stdout)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{testStoryName, "--no-attach"})
+
+	require.NoError(t, cmd.Execute())
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal([]byte(stdout.String()), &got), stdout.String())
+	require.Equal(t, testTargetWorkspaceID, got["workspace_id"])
+	require.Empty(t, got["pane_groups"])
+	require.Equal(t,
+		[]any{"tmux", "-S", testTargetWorkspaceID, testTmuxAttachSession, "-t", ""},
+		got["attach_command"])
+}
+
+func TestOpenCmd_NoAttach_StoryNotFound_DoesNotPrompt(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{CodeRoot: testCodeRoot, DefaultStory: testDefaultStory}
+	store := &stubStore{getErr: coreStory.ErrStoryNotFound}
+	mgr := &stubMgr{sess: &stubSess{}}
+	resolver := layout.NewResolver(testCodeRoot, testDefaultStory)
+
+	cmd := workspace.NewOpenCmd(
+		cfg, store, mgr, resolver, hookexec.Noop,
+		workspace.WithTTYCheck(func() bool { return true }),
+		workspace.WithStdinReader(strings.NewReader("y\n")),
+	)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{testNonexistentStory, "--no-attach"})
+
+	err := cmd.Execute()
+	require.ErrorIs(t, err, coreStory.ErrStoryNotFound)
+	require.False(t, store.createCalled)
+}
+
+func TestOpenCmd_NoAttach_RejectsKillPane(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{CodeRoot: testCodeRoot, DefaultStory: testDefaultStory}
+	store := &stubStore{getStory: &coreStory.Story{Name: testStoryName}}
+	sess := &stubSess{}
+	mgr := &stubMgr{sess: sess}
+	resolver := layout.NewResolver(testCodeRoot, testDefaultStory)
+
+	cmd := workspace.NewOpenCmd(cfg, store, mgr, resolver, hookexec.Noop)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{testStoryName, "--no-attach", flagKillPane})
+
+	err := cmd.Execute()
+	require.ErrorContains(t, err, "--kill-pane cannot be combined with --no-attach")
+	require.Nil(t, sess.lastOpenReq)
+}
+
 // ─── stubs ───────────────────────────────────────────────────────────────────
 
 func TestOpenCmd_Completion_ReturnsStoryNames(t *testing.T) {
@@ -1301,6 +1625,7 @@ type stubStore struct {
 	getStory     *coreStory.Story
 	getErr       error
 	updateCalled bool
+	updatedStory *coreStory.Story
 	listStories  []*coreStory.Story
 	listErr      error
 	listCalled   bool
@@ -1375,8 +1700,9 @@ func (s *stubStore) List(context.Context) ([]*coreStory.Story, error) {
 	return s.listStories, s.listErr
 }
 
-func (s *stubStore) Update(_ context.Context, _ *coreStory.Story) error {
+func (s *stubStore) Update(_ context.Context, st *coreStory.Story) error {
 	s.updateCalled = true
+	s.updatedStory = st
 
 	return nil
 }
@@ -1385,9 +1711,14 @@ var _ coreStory.Store = (*stubStore)(nil)
 
 // stubMgr implements pluginManager.
 type stubMgr struct {
-	sess   pluginv1.SessionClient
-	vcs    pluginv1.VCSClient
-	picker pluginv1.PickerClient
+	sess     pluginv1.SessionClient
+	vcs      pluginv1.VCSClient
+	picker   pluginv1.PickerClient
+	forge    pluginv1.ForgeClient
+	namedVCS map[string]pluginv1.VCSClient
+	pathVCS  string // returned by VCSForPath
+	urlVCS   string // returned by VCSForURL
+	lastURL  string // last URL passed to VCSForURL
 }
 
 func (s *stubMgr) Close() error {
@@ -1414,9 +1745,35 @@ func (s *stubMgr) Get(_ context.Context, capability string) (any, error) {
 }
 
 func (s *stubMgr) GetForge(_ context.Context, _ string) (pluginv1.ForgeClient, error) {
+	if s.forge != nil {
+		return s.forge, nil
+	}
+
 	return nil, fmt.Errorf("%w: no forge configured", errNoPlugin)
 }
 
+func (s *stubMgr) GetVCS(_ context.Context, name string) (pluginv1.VCSClient, error) {
+	if vcs, ok := s.namedVCS[name]; ok {
+		return vcs, nil
+	}
+
+	if name == "" && s.vcs != nil {
+		return s.vcs, nil
+	}
+
+	return nil, fmt.Errorf("%w: vcs %q", errNoPlugin, name)
+}
+
+func (s *stubMgr) VCSForPath(context.Context, string) (string, error) {
+	return s.pathVCS, nil
+}
+
+func (s *stubMgr) VCSForURL(_ context.Context, url string) (string, error) {
+	s.lastURL = url
+
+	return s.urlVCS, nil
+}
+
 func (s *stubMgr) Warm(_ context.Context, _ ...string) error {
 	return nil
 }
@@ -1432,6 +1789,24 @@ type stubSess struct {
 	currentContextErr  error
 }
 
+func (s *stubSess) AttachCommand(
+	_ context.Context,
+	req *pluginv1.AttachCommandRequest,
+	_ ...grpc.CallOption,
+) (*pluginv1.AttachCommandResponse, error) {
+	return &pluginv1.AttachCommandResponse{
+		Argv: []string{"tmux", "-S", req.GetWorkspaceId(), "attach-session", "-t", req.GetPaneGroupId()},
+	}, nil
+}
+
+func (s *stubSess) ClosePaneGroup(
+	context.Context,
+	*pluginv1.ClosePaneGroupRequest,
+	...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	panic("stub")
+}
+
 func (s *stubSess) CloseWorkspace(
 	context.Context,
 	*pluginv1.CloseWorkspaceRequest,
@@ -1472,6 +1847,22 @@ func (s *stubSess) IsInsideWorkspace(
 	panic("stub")
 }
 
+func (s *stubSess) ListPaneGroups(
+	context.Context,
+	*pluginv1.ListPaneGroupsRequest,
+	...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.PaneGroup], error) {
+	panic("stub")
+}
+
+func (s *stubSess) ListServices(
+	context.Context,
+	*pluginv1.ListServicesRequest,
+	...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.Service], error) {
+	panic("stub")
+}
+
 func (s *stubSess) ListWorkspaces(
 	context.Context,
 	*pluginv1.Empty,
@@ -1506,6 +1897,38 @@ func (s *stubSess) OpenWorkspace(
 	}, nil
 }
 
+func (s *stubSess) RestoreWorkspace(
+	context.Context,
+	*pluginv1.RestoreWorkspaceRequest,
+	...grpc.CallOption,
+) (*pluginv1.RestoreWorkspaceResponse, error) {
+	panic("stub")
+}
+
+func (s *stubSess) SaveWorkspace(
+	context.Context,
+	*pluginv1.SaveWorkspaceRequest,
+	...grpc.CallOption,
+) (*pluginv1.SaveWorkspaceResponse, error) {
+	panic("stub")
+}
+
+func (s *stubSess) StartService(
+	context.Context,
+	*pluginv1.StartServiceRequest,
+	...grpc.CallOption,
+) (*pluginv1.Service, error) {
+	panic("stub")
+}
+
+func (s *stubSess) StopService(
+	context.Context,
+	*pluginv1.StopServiceRequest,
+	...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	panic("stub")
+}
+
 func (s *stubSess) SwitchTo(
 	_ context.Context,
 	req *pluginv1.SwitchToRequest,
@@ -1520,21 +1943,34 @@ var _ pluginv1.SessionClient = (*stubSess)(nil)
 
 // stubVCS records CreateWorktree calls.
 type stubVCS struct {
-	createCalled bool
+	lastCloneReq  *pluginv1.CloneRequest
+	createCalled  bool
+	lastCreateReq *pluginv1.CreateWorktreeRequest
+	parsedID      *pluginv1.ProjectID // returned from ParseRemoteURL when non-nil
+	identity      *pluginv1.UserIdentity
+	invalidBranch string // rejected by ValidateBranchName
 }
 
+// Clone creates the destination with a .git marker and streams no events.
 func (v *stubVCS) Clone(
-	context.Context, *pluginv1.CloneRequest, ...grpc.CallOption,
+	_ context.Context, req *pluginv1.CloneRequest, _ ...grpc.CallOption,
 ) (grpc.ServerStreamingClient[pluginv1.CloneProgressEvent], error) {
-	panic("stub")
+	v.lastCloneReq = req
+
+	if err := os.MkdirAll(filepath.Join(req.GetDestinationPath(), ".git"), 0o750); err != nil {
+		return nil, err
+	}
+
+	return emptyCloneStream{}, nil
 }
 
 func (v *stubVCS) CreateWorktree(
 	_ context.Context,
-	_ *pluginv1.CreateWorktreeRequest,
+	req *pluginv1.CreateWorktreeRequest,
 	_ ...grpc.CallOption,
 ) (*pluginv1.Empty, error) {
 	v.createCalled = true
+	v.lastCreateReq = req
 
 	return &pluginv1.Empty{}, nil
 }
@@ -1547,10 +1983,26 @@ func (v *stubVCS) DetectProjectAtPath(
 	panic("stub")
 }
 
-func (v *stubVCS) Info(context.Context, *pluginv1.Empty, ...grpc.CallOption) (*pluginv1.VCSInfo, error) {
+func (v *stubVCS) GetUserIdentity(
+	context.Context,
+	*pluginv1.UserIdentityRequest,
+	...grpc.CallOption,
+) (*pluginv1.UserIdentity, error) {
+	return v.identity, nil
+}
+
+func (v *stubVCS) GetWorktreeStatus(
+	context.Context,
+	*pluginv1.WorktreeStatusRequest,
+	...grpc.CallOption,
+) (*pluginv1.WorktreeStatus, error) {
 	panic("stub")
 }
 
+func (v *stubVCS) Info(context.Context, *pluginv1.Empty, ...grpc.CallOption) (*pluginv1.VCSInfo, error) {
+	return &pluginv1.VCSInfo{ProjectMarkers: []string{".git"}}, nil
+}
+
 func (v *stubVCS) ListBranches(
 	context.Context,
 	*pluginv1.ListBranchesRequest,
@@ -1564,6 +2016,10 @@ func (v *stubVCS) ParseRemoteURL(
 	*pluginv1.ParseRemoteURLRequest,
 	...grpc.CallOption,
 ) (*pluginv1.ProjectID, error) {
+	if v.parsedID != nil {
+		return v.parsedID, nil
+	}
+
 	panic("stub")
 }
 
@@ -1575,6 +2031,18 @@ func (v *stubVCS) RemoveWorktree(
 	panic("stub")
 }
 
+func (v *stubVCS) ValidateBranchName(
+	_ context.Context,
+	req *pluginv1.ValidateBranchNameRequest,
+	_ ...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	if req.GetBranchName() == v.invalidBranch {
+		return nil, status.Errorf(codes.InvalidArgument, "%q is not a valid branch name", req.GetBranchName())
+	}
+
+	return &pluginv1.Empty{}, nil
+}
+
 var _ pluginv1.VCSClient = (*stubVCS)(nil)
 
 // stubPickerClient implements pluginv1.PickerClient.
@@ -1687,3 +2155,12 @@ func (e *eofStream) Recv() (*pluginv1.Workspace, error) { return nil, io.EOF }
 func (e *eofStream) RecvMsg(any) error                  { panic("stub") }
 func (e *eofStream) SendMsg(any) error                  { panic("stub") }
 func (e *eofStream) Trailer() metadata.MD               { panic("stub") }
+
+// emptyCloneStream is a clone stream that ends at once.
+type emptyCloneStream struct {
+	grpc.ServerStreamingClient[pluginv1.CloneProgressEvent]
+}
+
+func (emptyCloneStream) Recv() (*pluginv1.CloneProgressEvent, error) {
+	return nil, io.EOF
+}
@@ -0,0 +1,82 @@
+package workspace
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/ports"
+)
+
+// NewRestoreCmd returns the `swm workspace restore` command.
+func NewRestoreCmd(
+	cfg *config.Config,
+	store coreStory.Store,
+	mgr pluginManager,
+	resolver *layout.Resolver,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [<name>]",
+		Short: "Recreate a story's workspace from its last saved snapshot",
+		Long: "Recreate a story's workspace from the snapshot written by " +
+			"\"swm workspace save\" (or by autosave on close). Pane groups that are " +
+			"already running are left untouched. If <name> is omitted, $SWM_STORY is used.",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			mgr.Warm(cmd.Context(), "session") //nolint:errcheck,gosec // Warm always returns nil; errors deferred to Get
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := storyNameFromArgs(args)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+
+			st, err := store.Get(ctx, name)
+			if err != nil {
+				return fmt.Errorf("loading story %q: %w", name, err)
+			}
+
+			if err := ports.Ensure(ctx, store, st, cfg.Ports.Base, cfg.Ports.BlockSize); err != nil {
+				return fmt.Errorf("allocating ports of story %q: %w", name, err)
+			}
+
+			// The workspace may be running already: restoring it with no env
+			// would drop the story's variables from it.
+			env, err := ports.StoryEnvironment(resolver, st, "", cfg.HistfilePath(name), cfg.Ports.BlockSize)
+			if err != nil {
+				return fmt.Errorf("reading named ports: %w", err)
+			}
+
+			sess, err := sessionClient(ctx, mgr)
+			if err != nil {
+				return err
+			}
+
+			resp, err := sess.RestoreWorkspace(ctx, &pluginv1.RestoreWorkspaceRequest{
+				StoryName:    name,
+				SnapshotPath: snapshotPath(cfg, name),
+				Env:          env,
+			})
+			if err != nil {
+				return fmt.Errorf("restoring workspace for story %q: %w", name, err)
+			}
+
+			cmd.Printf("restored %d pane group(s) for story %q\n", len(resp.GetPaneGroupIds()), name)
+
+			return nil
+		},
+	}
+
+	cmd.ValidArgsFunction = storyNameCompletion(store)
+
+	return cmd
+}
@@ -0,0 +1,90 @@
+package workspace_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/workspace"
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+)
+
+func TestRestoreCmd_RestoresFromStoryDataDir(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{}
+	dataHome := t.TempDir()
+
+	cmd := workspace.NewRestoreCmd(
+		&config.Config{DataHome: dataHome}, &stubStore{}, &stubMgr{sess: sess}, testResolver(),
+	)
+	out := &strings.Builder{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{testStoryName})
+
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, testStoryName, sess.lastRestoreReq.GetStoryName())
+	require.Equal(t,
+		filepath.Join(dataHome, "swm", "stories", testStoryName, "session-snapshot.json"),
+		sess.lastRestoreReq.GetSnapshotPath())
+	require.Contains(t, out.String(), `restored 1 pane group(s) for story "feat-x"`)
+}
+
+func TestRestoreCmd_PassesStoryEnv(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{}
+	cfg := &config.Config{DataHome: t.TempDir()}
+	cfg.Story.Histfile = true
+	store := &stubStore{getStory: &coreStory.Story{
+		Name:     testStoryName,
+		Env:      map[string]string{"AWS_PROFILE": "dev"},
+		PortBase: 20000,
+	}}
+
+	cmd := workspace.NewRestoreCmd(cfg, store, &stubMgr{sess: sess}, testResolver())
+	cmd.SetOut(&strings.Builder{})
+	cmd.SetArgs([]string{testStoryName})
+
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, map[string]string{
+		"AWS_PROFILE":   "dev",
+		"HISTFILE":      cfg.HistfilePath(testStoryName),
+		"SWM_PORT_BASE": "20000",
+	}, sess.lastRestoreReq.GetEnv())
+}
+
+func TestRestoreCmd_UnknownStory(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{}
+	store := &stubStore{getErr: coreStory.ErrStoryNotFound}
+
+	cmd := workspace.NewRestoreCmd(&config.Config{DataHome: t.TempDir()}, store, &stubMgr{sess: sess}, testResolver())
+	cmd.SetArgs([]string{testStoryName})
+
+	require.ErrorIs(t, cmd.Execute(), coreStory.ErrStoryNotFound)
+	require.Nil(t, sess.lastRestoreReq)
+}
+
+func TestRestoreCmd_PluginError(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{restoreErr: errFakeClose}
+
+	cmd := workspace.NewRestoreCmd(
+		&config.Config{DataHome: t.TempDir()}, &stubStore{}, &stubMgr{sess: sess}, testResolver(),
+	)
+	cmd.SetArgs([]string{testStoryName})
+
+	require.ErrorIs(t, cmd.Execute(), errFakeClose)
+}
+
+func testResolver() *layout.Resolver {
+	return layout.NewResolver(testCodeRoot, testDefaultStory)
+}
@@ -0,0 +1,71 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+)
+
+var errNoActiveWorkspace = errors.New("no active workspace")
+
+// NewSaveCmd returns the `swm workspace save` command.
+func NewSaveCmd(cfg *config.Config, store coreStory.Store, mgr pluginManager) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save [<name>]",
+		Short: "Save a snapshot of a story's workspace so it can be restored later",
+		Long: "Save a snapshot of a story's workspace (windows, pane geometry, working " +
+			"directories and foreground commands) into the story's data dir. " +
+			"If <name> is omitted, $SWM_STORY is used.",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			mgr.Warm(cmd.Context(), "session") //nolint:errcheck,gosec // Warm always returns nil; errors deferred to Get
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := storyNameFromArgs(args)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+
+			sess, err := sessionClient(ctx, mgr)
+			if err != nil {
+				return err
+			}
+
+			ws, err := findWorkspace(ctx, sess, name)
+			if err != nil {
+				return err
+			}
+
+			if ws == nil {
+				return fmt.Errorf("%w for story %q", errNoActiveWorkspace, name)
+			}
+
+			resp, err := sess.SaveWorkspace(ctx, &pluginv1.SaveWorkspaceRequest{
+				WorkspaceId:  ws.GetWorkspaceId(),
+				SnapshotPath: snapshotPath(cfg, name),
+			})
+			if err != nil {
+				return fmt.Errorf("saving workspace for story %q: %w", name, err)
+			}
+
+			cmd.Printf("saved %d pane group(s) for story %q to %s\n",
+				resp.GetPaneGroupCount(), name, resp.GetSnapshotPath())
+
+			return nil
+		},
+	}
+
+	cmd.ValidArgsFunction = storyNameCompletion(store)
+
+	return cmd
+}
@@ -0,0 +1,85 @@
+package workspace_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/workspace"
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+)
+
+func TestSaveCmd_SavesToStoryDataDir(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{
+		workspaces: []*pluginv1.Workspace{
+			{WorkspaceId: testCloseWorkspaceID, StoryName: testStoryName},
+		},
+	}
+	dataHome := t.TempDir()
+
+	cmd := workspace.NewSaveCmd(&config.Config{DataHome: dataHome}, &stubStore{}, &stubMgr{sess: sess})
+	out := &strings.Builder{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{testStoryName})
+
+	require.NoError(t, cmd.Execute())
+
+	wantPath := filepath.Join(dataHome, "swm", "stories", testStoryName, "session-snapshot.json")
+	require.Equal(t, testCloseWorkspaceID, sess.lastSaveReq.GetWorkspaceId())
+	require.Equal(t, wantPath, sess.lastSaveReq.GetSnapshotPath())
+	require.Contains(t, out.String(), `saved 2 pane group(s) for story "feat-x"`)
+	require.Empty(t, sess.closeWorkspaceID, "save must not close the workspace")
+}
+
+func TestSaveCmd_SWMStoryFallback(t *testing.T) {
+	t.Setenv("SWM_STORY", testStoryName)
+
+	sess := &stubCloseSession{
+		workspaces: []*pluginv1.Workspace{
+			{WorkspaceId: testCloseWorkspaceID, StoryName: testStoryName},
+		},
+	}
+
+	cmd := workspace.NewSaveCmd(&config.Config{DataHome: t.TempDir()}, &stubStore{}, &stubMgr{sess: sess})
+	cmd.SetOut(&strings.Builder{})
+	cmd.SetArgs([]string{})
+
+	require.NoError(t, cmd.Execute())
+	require.NotNil(t, sess.lastSaveReq)
+}
+
+func TestSaveCmd_NoActiveWorkspace_Error(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{}
+
+	cmd := workspace.NewSaveCmd(&config.Config{DataHome: t.TempDir()}, &stubStore{}, &stubMgr{sess: sess})
+	cmd.SetArgs([]string{testStoryName})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no active workspace")
+	require.Nil(t, sess.lastSaveReq)
+}
+
+func TestSaveCmd_PluginError(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{
+		workspaces: []*pluginv1.Workspace{
+			{WorkspaceId: testCloseWorkspaceID, StoryName: testStoryName},
+		},
+		saveErr: errFakeClose,
+	}
+
+	cmd := workspace.NewSaveCmd(&config.Config{DataHome: t.TempDir()}, &stubStore{}, &stubMgr{sess: sess})
+	cmd.SetArgs([]string{testStoryName})
+
+	require.ErrorIs(t, cmd.Execute(), errFakeClose)
+}
@@ -0,0 +1,25 @@
+package workspace
+
+import (
+	"path/filepath"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+)
+
+// snapshotFileName is the name of the session snapshot inside a story's data dir.
+const snapshotFileName = "session-snapshot.json"
+
+// snapshotPath returns where the session snapshot for storyName is stored.
+func snapshotPath(cfg *config.Config, storyName string) string {
+	return filepath.Join(cfg.StoryDataDir(storyName), snapshotFileName)
+}
+
+// restoreSnapshotPath returns the snapshot OpenWorkspace should restore from,
+// or "" when workspace.restore_on_open is disabled.
+func restoreSnapshotPath(cfg *config.Config, storyName string) string {
+	if !cfg.Workspace.RestoreOnOpen {
+		return ""
+	}
+
+	return snapshotPath(cfg, storyName)
+}
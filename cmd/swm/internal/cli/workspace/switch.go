@@ -0,0 +1,197 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/ageformat"
+)
+
+var errSwitchNeedsPicker = errors.New("workspace switch requires a picker plugin")
+
+// NewSwitchCmd returns the `swm workspace switch` command.
+func NewSwitchCmd(mgr pluginManager, opts ...OpenOption) *cobra.Command {
+	ocfg := &openCmdConfig{exec: syscall.Exec}
+	for _, o := range opts {
+		o(ocfg)
+	}
+
+	return &cobra.Command{
+		Use:   "switch",
+		Short: "Pick any live pane group across all stories and switch to it",
+		Long: "Pick any live pane group across all stories and switch to it. " +
+			"Candidates show the story, project, window count and last activity, " +
+			"most recently active first. Switching works across stories, even from " +
+			"inside another story's workspace.",
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			//nolint:errcheck,gosec // Warm always returns nil; errors deferred to Get
+			mgr.Warm(cmd.Context(), "picker", "session")
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			sess, err := sessionClient(ctx, mgr)
+			if err != nil {
+				return err
+			}
+
+			rawPicker, err := mgr.Get(ctx, "picker")
+			if err != nil {
+				return fmt.Errorf("%w: %w", errSwitchNeedsPicker, err)
+			}
+
+			pickerClient, ok := rawPicker.(pluginv1.PickerClient)
+			if !ok {
+				return fmt.Errorf("%w: %T", errUnexpectedPluginType, rawPicker)
+			}
+
+			groups, err := listPaneGroups(ctx, sess, "")
+			if err != nil {
+				return err
+			}
+
+			if len(groups) == 0 {
+				cmd.Println("no live pane groups")
+
+				return nil
+			}
+
+			pg, err := pickPaneGroup(ctx, pickerClient, groups, time.Now())
+			if err != nil {
+				return err
+			}
+
+			if pg == nil {
+				return nil
+			}
+
+			res, err := sess.SwitchTo(ctx, &pluginv1.SwitchToRequest{
+				WorkspaceId: pg.GetWorkspaceId(),
+				PaneGroupId: pg.GetPaneGroupId(),
+			})
+			if err != nil {
+				return fmt.Errorf("switching to pane group %q: %w", pg.GetPaneGroupId(), err)
+			}
+
+			if argv := res.GetExecArgv(); len(argv) > 0 {
+				if err := mgr.Close(); err != nil {
+					slog.WarnContext(ctx, "error closing plugins before exec", "err", err)
+				}
+
+				if err := ocfg.exec(argv[0], argv, os.Environ()); err != nil {
+					return fmt.Errorf("exec after switch: %w", err)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// pickPaneGroup shows groups in the picker, most recently active first, and
+// returns the selected one. A cancelled picker returns nil without error.
+func pickPaneGroup(
+	ctx context.Context, pickerClient pluginv1.PickerClient, groups []*pluginv1.PaneGroup, now time.Time,
+) (*pluginv1.PaneGroup, error) {
+	sorted := SortPaneGroupsForPicker(groups)
+
+	stream, err := pickerClient.Pick(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting picker: %w", err)
+	}
+
+	// Keys are indexes into sorted: pane group IDs may contain characters the
+	// picker uses as delimiters.
+	for i, pg := range sorted {
+		if err := stream.Send(&pluginv1.PickItem{
+			Key:     strconv.Itoa(i),
+			Display: BuildPaneGroupDisplay(pg, now),
+		}); err != nil {
+			return nil, fmt.Errorf("sending pane group to picker: %w", err)
+		}
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return nil, fmt.Errorf("closing picker send: %w", err)
+	}
+
+	result, err := stream.Recv()
+	if err != nil {
+		if status.Code(err) == codes.Aborted || errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("receiving picker result: %w", err)
+	}
+
+	i, err := strconv.Atoi(result.GetKey())
+	if err != nil || i < 0 || i >= len(sorted) {
+		return nil, fmt.Errorf("%q: %w", result.GetKey(), errUnknownPickerKey)
+	}
+
+	return sorted[i], nil
+}
+
+// SortPaneGroupsForPicker returns a copy of groups ordered by last activity,
+// most recent first, then by story name and project.
+func SortPaneGroupsForPicker(groups []*pluginv1.PaneGroup) []*pluginv1.PaneGroup {
+	sorted := make([]*pluginv1.PaneGroup, len(groups))
+	copy(sorted, groups)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ai, aj := sorted[i].GetLastActivity().AsTime(), sorted[j].GetLastActivity().AsTime()
+		if !ai.Equal(aj) {
+			return ai.After(aj)
+		}
+
+		if sorted[i].GetStoryName() != sorted[j].GetStoryName() {
+			return sorted[i].GetStoryName() < sorted[j].GetStoryName()
+		}
+
+		return paneGroupKey(sorted[i]) < paneGroupKey(sorted[j])
+	})
+
+	return sorted
+}
+
+// BuildPaneGroupDisplay returns the picker line for a pane group:
+//
+//	<story> · <project> · <n> window(s) · <last activity>
+//
+// The window count and last activity are omitted when the plugin does not
+// report them.
+func BuildPaneGroupDisplay(pg *pluginv1.PaneGroup, now time.Time) string {
+	parts := []string{pg.GetStoryName(), paneGroupKey(pg)}
+
+	switch n := pg.GetWindowCount(); n {
+	case 0:
+	case 1:
+		parts = append(parts, "1 window")
+	default:
+		parts = append(parts, fmt.Sprintf("%d windows", n))
+	}
+
+	if pg.GetLastActivity() != nil {
+		parts = append(parts, ageformat.FormatAge(pg.GetLastActivity().AsTime(), now))
+	}
+
+	return strings.Join(parts, projectSep)
+}
@@ -0,0 +1,165 @@
+package workspace_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/workspace"
+)
+
+const (
+	testSwitchSockA = "/run/swm/tmux/feat-a.sock"
+	testSwitchSockB = "/run/swm/tmux/feat-b.sock"
+)
+
+// switchPaneGroups returns a live pane group in each of two stories, with
+// feat-b the most recently active.
+func switchPaneGroups() map[string][]*pluginv1.PaneGroup {
+	return map[string][]*pluginv1.PaneGroup{
+		testSwitchSockA: {{
+			PaneGroupId:  "github•com/a/b",
+			WorkspaceId:  testSwitchSockA,
+			StoryName:    testCompletionStoryA,
+			ProjectId:    &pluginv1.ProjectID{Host: testHost, Segments: []string{"a", "b"}},
+			WindowCount:  2,
+			LastActivity: timestamppb.New(time.Unix(1_700_000_000, 0)),
+		}},
+		testSwitchSockB: {{
+			PaneGroupId:  "github•com/c/d",
+			WorkspaceId:  testSwitchSockB,
+			StoryName:    testCompletionStoryB,
+			ProjectId:    &pluginv1.ProjectID{Host: testHost, Segments: []string{"c", "d"}},
+			WindowCount:  1,
+			LastActivity: timestamppb.New(time.Unix(1_700_000_500, 0)),
+		}},
+	}
+}
+
+func TestSwitchCmd_SwitchesToPickedPaneGroup(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{paneGroups: switchPaneGroups()}
+	mgr := &stubMgr{sess: sess, picker: &stubPickerClient{selectedKey: "0"}}
+
+	cmd := workspace.NewSwitchCmd(mgr)
+	cmd.SetArgs([]string{})
+
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, testSwitchSockB, sess.lastSwitchReq.GetWorkspaceId(), "most recent pane group is listed first")
+	require.Equal(t, "github•com/c/d", sess.lastSwitchReq.GetPaneGroupId())
+}
+
+func TestSwitchCmd_ExecsReturnedArgv(t *testing.T) {
+	t.Parallel()
+
+	argv := []string{"tmux", "-S", testSwitchSockA, "attach-session", "-t", "github•com/a/b"}
+	sess := &stubCloseSession{
+		paneGroups: switchPaneGroups(),
+		switchResp: &pluginv1.SwitchToResponse{ExecArgv: argv},
+	}
+	mgr := &stubMgr{sess: sess, picker: &stubPickerClient{selectedKey: "1"}}
+
+	var execArgv []string
+
+	cmd := workspace.NewSwitchCmd(mgr, workspace.WithExecFunc(func(_ string, argv, _ []string) error {
+		execArgv = argv
+
+		return nil
+	}))
+	cmd.SetArgs([]string{})
+
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, argv, execArgv)
+}
+
+func TestSwitchCmd_PickerCancelled_NoSwitch(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{paneGroups: switchPaneGroups()}
+	mgr := &stubMgr{sess: sess, picker: &stubPickerClient{cancelOnRecv: true}}
+
+	cmd := workspace.NewSwitchCmd(mgr)
+	cmd.SetArgs([]string{})
+
+	require.NoError(t, cmd.Execute())
+	require.Nil(t, sess.lastSwitchReq)
+}
+
+func TestSwitchCmd_NoLivePaneGroups(t *testing.T) {
+	t.Parallel()
+
+	sess := &stubCloseSession{}
+	mgr := &stubMgr{sess: sess, picker: &stubPickerClient{selectedKey: "0"}}
+
+	cmd := workspace.NewSwitchCmd(mgr)
+	out := &strings.Builder{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{})
+
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "no live pane groups\n", out.String())
+	require.Nil(t, sess.lastSwitchReq)
+}
+
+func TestSwitchCmd_NoPicker(t *testing.T) {
+	t.Parallel()
+
+	mgr := &stubMgr{sess: &stubCloseSession{paneGroups: switchPaneGroups()}}
+
+	cmd := workspace.NewSwitchCmd(mgr)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{})
+
+	require.ErrorContains(t, cmd.Execute(), "requires a picker plugin")
+}
+
+func TestBuildPaneGroupDisplay(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_003_600, 0)
+	pid := &pluginv1.ProjectID{Host: testHost, Segments: []string{"a", "b"}}
+
+	tests := []struct {
+		name string
+		pg   *pluginv1.PaneGroup
+		want string
+	}{
+		{
+			name: "several windows",
+			pg: &pluginv1.PaneGroup{
+				StoryName: "feat-x", ProjectId: pid, WindowCount: 3,
+				LastActivity: timestamppb.New(time.Unix(1_700_000_000, 0)),
+			},
+			want: "feat-x · github.com/a/b · 3 windows · 1h ago",
+		},
+		{
+			name: "one window",
+			pg: &pluginv1.PaneGroup{
+				StoryName: "feat-x", ProjectId: pid, WindowCount: 1,
+				LastActivity: timestamppb.New(time.Unix(1_700_003_000, 0)),
+			},
+			want: "feat-x · github.com/a/b · 1 window · 10m ago",
+		},
+		{
+			name: "plugin reports no details",
+			pg:   &pluginv1.PaneGroup{StoryName: "feat-x", ProjectId: pid},
+			want: "feat-x · github.com/a/b",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, workspace.BuildPaneGroupDisplay(tc.pg, now))
+		})
+	}
+}
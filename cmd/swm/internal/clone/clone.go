@@ -0,0 +1,114 @@
+// Package clone clones repositories to their canonical path through the VCS
+// plugin, running the clone hooks around it.
+package clone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/hookexec"
+)
+
+// Result describes a cloned (or already present) repository.
+type Result struct {
+	ID   *pluginv1.ProjectID
+	Path string
+	// Existing is true when the repository was already cloned and nothing ran.
+	Existing bool
+}
+
+// Run clones url to its canonical path under the resolver's code root. Clone
+// progress is copied to progress. When the canonical path already holds one of
+// the plugin's project markers, Run returns it with Existing set and runs no
+// hooks.
+func Run(
+	ctx context.Context,
+	vcs pluginv1.VCSClient,
+	resolver *layout.Resolver,
+	hooks hookexec.Runner,
+	url string,
+	progress io.Writer,
+) (*Result, error) {
+	id, err := vcs.ParseRemoteURL(ctx, &pluginv1.ParseRemoteURLRequest{Url: url})
+	if err != nil {
+		return nil, fmt.Errorf("parsing URL %q: %w", url, err)
+	}
+
+	canonical := resolver.CanonicalPath(id)
+
+	info, err := vcs.Info(ctx, &pluginv1.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("calling Info on vcs plugin: %w", err)
+	}
+
+	for _, marker := range info.GetProjectMarkers() {
+		if _, err := os.Stat(filepath.Join(canonical, marker)); err == nil {
+			return &Result{ID: id, Path: canonical, Existing: true}, nil
+		}
+	}
+
+	projectPath := strings.Join(id.GetSegments(), "/")
+	codeRoot := resolver.CodeRoot()
+
+	if err := hooks.Run(ctx, hookexec.RunConfig{
+		Event:       "pre-clone",
+		CodeRoot:    codeRoot,
+		ProjectHost: id.GetHost(),
+		ProjectPath: projectPath,
+		WorkDir:     codeRoot,
+	}); err != nil {
+		return nil, fmt.Errorf("pre-clone hook: %w", err)
+	}
+
+	stream, err := vcs.Clone(ctx, &pluginv1.CloneRequest{
+		Url:             url,
+		DestinationPath: canonical,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cloning %q: %w", url, err)
+	}
+
+	for {
+		evt, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+
+		if recvErr != nil {
+			return nil, fmt.Errorf("cloning %q: %w", url, recvErr)
+		}
+
+		if pid := evt.GetProjectId(); pid != nil {
+			id = pid
+			projectPath = strings.Join(id.GetSegments(), "/")
+
+			continue
+		}
+
+		if line := evt.GetProgressLine(); line != "" {
+			fmt.Fprint(progress, line) //nolint:errcheck // writing progress is best-effort
+		}
+	}
+
+	if err := hooks.Run(ctx, hookexec.RunConfig{
+		Event:       "post-clone",
+		CodeRoot:    codeRoot,
+		ProjectHost: id.GetHost(),
+		ProjectPath: projectPath,
+		RepoPath:    canonical,
+		WorkDir:     canonical,
+	}); err != nil {
+		// post-clone hooks are informational; log the failure but don't abort.
+		fmt.Fprintf(progress, "post-clone hook failed (ignored): %v\n", err) //nolint:errcheck // best-effort
+	}
+
+	return &Result{ID: id, Path: canonical}, nil
+}
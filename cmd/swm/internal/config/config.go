@@ -1,45 +1,287 @@
 // Package config loads and represents the swm host configuration.
 package config
 
+import (
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/core/story"
+)
+
+// histfileName is the name of the shell history file inside a story's data dir.
+const histfileName = "history"
+
 // Plugins contains names and per-plugin config for all capabilities.
 // This maps directly to the [plugins] TOML table.
 type Plugins struct {
-	Session string   `toml:"session,omitempty"`
-	VCS     string   `toml:"vcs,omitempty"`
+	Session string `toml:"session,omitempty"`
+	// VCS lists the vcs plugins, default first. Each project is routed to the
+	// plugin whose project markers it contains, or whose URL scheme its remote
+	// uses.
+	VCS     Names    `toml:"vcs,omitempty"`
 	Picker  string   `toml:"picker,omitempty"`
+	Tracker string   `toml:"tracker,omitempty"`
 	Forges  []string `toml:"forges,omitempty"`
 
+	// ForgeHosts maps code hosts, or globs of them such as "*.example.com",
+	// to the forge plugin handling them, before the hosts the plugins claim;
+	// see ForgeHost.
+	ForgeHosts map[string]ForgeHost `toml:"forge_hosts,omitempty"`
+
 	// Paths contains explicit binary paths keyed by plugin name, e.g. "vcs-git" -> "/usr/bin/swm-plugin-vcs-git".
 	Paths map[string]string `toml:"paths,omitempty"`
 
 	// Config holds per-plugin raw config sections, keyed by plugin name.
 	// Each value is the raw key/value map from [plugins.config.<name>].
 	Config map[string]map[string]any `toml:"config,omitempty"`
+
+	// Timeouts bounds plugin calls, keyed by capability ("vcs") or by
+	// capability and method ("vcs.CreateWorktree"); see Timeout.
+	Timeouts map[string]Duration `toml:"timeouts,omitempty"`
+
+	// Sandbox holds the sandbox settings of plugins, keyed by plugin name,
+	// e.g. "vcs-git". A plugin with an entry runs sandboxed.
+	Sandbox map[string]Sandbox `toml:"sandbox,omitempty"`
+
+	// Checksums pins plugin binaries by their hex-encoded SHA-256, keyed by
+	// plugin name, e.g. "vcs-git". A plugin whose binary does not match is
+	// not launched.
+	Checksums map[string]string `toml:"checksums,omitempty"`
+
+	// Bundled runs the plugins built into swm (vcs-git, session-tmux,
+	// picker-fzf and forge-github) in the swm process when their binaries
+	// are not found.
+	Bundled bool `toml:"bundled,omitempty"`
+}
+
+// Sandbox confines a plugin with Landlock (Linux only): it may read
+// anywhere, but write only below code_root, its own data and runtime dirs,
+// the temp dir, /dev and AllowWrite.
+type Sandbox struct {
+	// AllowWrite lists further directories the plugin may write below.
+	AllowWrite []string `toml:"allow_write,omitempty"`
+}
+
+// ForgeHost is the forge plugin handling a code host, written in TOML as its
+// name ("github") or as a table that may also set how to reach the host.
+type ForgeHost struct {
+	Plugin string `toml:"plugin"`
+	// APIURL is the base URL of the host's API; empty for the plugin's
+	// default for the host.
+	APIURL string `toml:"api_url,omitempty"`
+	// TokenPath is a file holding the API token for the host; empty for the
+	// plugin's own token sources.
+	TokenPath string `toml:"token_path,omitempty"`
+}
+
+// UnmarshalText decodes a single TOML string, the plugin name. Tables are
+// decoded field by field.
+func (h *ForgeHost) UnmarshalText(text []byte) error {
+	*h = ForgeHost{Plugin: string(text)}
+
+	return nil
+}
+
+// Configured returns the names of the configured plugins, e.g. "vcs-git",
+// in the order of the [plugins] table.
+func (p Plugins) Configured() []string {
+	var names []string
+
+	add := func(capability string, plugins ...string) {
+		for _, name := range plugins {
+			if name != "" {
+				names = append(names, capability+"-"+name)
+			}
+		}
+	}
+
+	add("session", p.Session)
+	add("vcs", p.VCS...)
+	add("picker", p.Picker)
+	add("tracker", p.Tracker)
+	add("forge", p.Forges...)
+
+	return names
+}
+
+// ForgeHostFor returns the [plugins.forge_hosts] entry for hostname: the one
+// for hostname itself, else the one of the longest glob matching it.
+func (p Plugins) ForgeHostFor(hostname string) (ForgeHost, bool) {
+	hostname = strings.ToLower(hostname)
+
+	if h, ok := p.ForgeHosts[hostname]; ok {
+		return h, true
+	}
+
+	var (
+		found ForgeHost
+		best  string
+	)
+
+	for pattern, h := range p.ForgeHosts {
+		if ok, _ := path.Match(pattern, hostname); !ok {
+			continue
+		}
+
+		if len(pattern) > len(best) || (len(pattern) == len(best) && pattern < best) {
+			found, best = h, pattern
+		}
+	}
+
+	return found, best != ""
+}
+
+// Timeout returns the deadline of a call to method of the capability's
+// plugin: the timeout configured for "<capability>.<method>", else the one
+// for the capability, else 0, meaning no deadline.
+func (p Plugins) Timeout(capability, method string) time.Duration {
+	if d, ok := p.Timeouts[capability+"."+method]; ok {
+		return time.Duration(d)
+	}
+
+	return time.Duration(p.Timeouts[capability])
+}
+
+// Names is a list of plugin names that may be written in TOML as a single
+// string (vcs = "git") or as an array (vcs = ["git", "jj"]).
+type Names []string
+
+// UnmarshalText decodes a single TOML string into a one-name list. Arrays are
+// decoded as plain string slices.
+func (n *Names) UnmarshalText(text []byte) error {
+	*n = Names{string(text)}
+
+	return nil
+}
+
+// ParseNames parses a comma-separated list of names, dropping empty ones.
+func ParseNames(s string) Names {
+	var names Names
+
+	for name := range strings.SplitSeq(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// Default returns the first name, or "" when there is none.
+func (n Names) Default() string {
+	if len(n) == 0 {
+		return ""
+	}
+
+	return n[0]
+}
+
+// String renders the names comma-separated, as ParseNames reads them.
+func (n Names) String() string {
+	return strings.Join(n, ",")
 }
 
 // DefaultBranchNameTemplate is the branch_name_template used when no value is
 // configured (absent or empty in config.toml).
 const DefaultBranchNameTemplate = "feat/{{.Name}}"
 
+// Default port allocation: stories get blocks of 100 ports starting at 20000.
+const (
+	DefaultPortBase      = 20000
+	DefaultPortBlockSize = 100
+)
+
 // Story contains story-creation settings.
 type Story struct {
-	// BranchNameTemplate is a Go text/template string evaluated with .Name set
-	// to the story name. It controls the default branch name produced by
+	// BranchNameTemplate is a Go text/template string evaluated with the
+	// story's name, issue, user, date and --var values (see
+	// story.BranchNameData). It controls the default branch name produced by
 	// "swm story create". When empty, "feat/{{.Name}}" is used.
 	BranchNameTemplate string `toml:"branch_name_template,omitempty"`
+
+	// Histfile gives every story its own shell history by setting HISTFILE
+	// to a file in the story's data dir, unless the story's env sets it.
+	Histfile bool `toml:"histfile,omitempty"`
+}
+
+// Ports contains the per-story port allocation settings.
+type Ports struct {
+	// Base is the first port handed out to stories.
+	Base int `toml:"base,omitempty"`
+
+	// BlockSize is the number of consecutive ports each story gets. Zero
+	// disables port allocation.
+	BlockSize int `toml:"block_size,omitempty"`
+}
+
+// Workspace contains workspace lifecycle settings.
+type Workspace struct {
+	// Autosave saves a snapshot of the workspace to the story's data dir
+	// before "swm workspace close" tears it down.
+	Autosave bool `toml:"autosave,omitempty"`
+
+	// RestoreOnOpen recreates pane groups from the last snapshot when
+	// "swm workspace open" has to start the workspace from scratch.
+	RestoreOnOpen bool `toml:"restore_on_open,omitempty"`
+}
+
+// DefaultDaemonIdleTimeout is how long "swm daemon run" keeps running
+// without serving a request when daemon.idle_timeout is not configured.
+const DefaultDaemonIdleTimeout = Duration(30 * time.Minute)
+
+// Daemon contains the settings of "swm daemon run".
+type Daemon struct {
+	// IdleTimeout stops the daemon once it has served no request for this
+	// long. Zero keeps it running until "swm daemon stop".
+	IdleTimeout Duration `toml:"idle_timeout,omitempty"`
+}
+
+// Duration is a time.Duration written in TOML as a string such as "30m".
+type Duration time.Duration
+
+// MarshalText renders d as time.Duration.String does.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText parses text with time.ParseDuration.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+
+	return nil
 }
 
 // Config is the parsed representation of $XDG_CONFIG_HOME/swm/config.toml.
 type Config struct {
-	CodeRoot     string  `toml:"code_root,omitempty"`
-	DefaultStory string  `toml:"default_story,omitempty"`
-	Plugins      Plugins `toml:"plugins,omitempty"`
-	Story        Story   `toml:"story,omitempty"`
+	CodeRoot     string    `toml:"code_root,omitempty"`
+	DefaultStory string    `toml:"default_story,omitempty"`
+	Plugins      Plugins   `toml:"plugins,omitempty"`
+	Story        Story     `toml:"story,omitempty"`
+	Ports        Ports     `toml:"ports,omitempty"`
+	Workspace    Workspace `toml:"workspace,omitempty"`
+	Daemon       Daemon    `toml:"daemon,omitempty"`
 
 	// HooksConfigHome overrides the XDG config home used for hook discovery.
 	// When empty, the system XDG config home is used. Set in tests to avoid
 	// writing hooks into the real user config directory.
 	HooksConfigHome string `toml:"-"`
+
+	// DataHome overrides the XDG data home used for per-story state such as
+	// workspace snapshots. When empty, the system XDG data home is used.
+	DataHome string `toml:"-"`
+
+	// CacheHome overrides the XDG cache home used for the status cache read
+	// by "swm status". When empty, the system XDG cache home is used.
+	CacheHome string `toml:"-"`
 }
 
 // Defaults returns a Config populated with default values (no file required).
@@ -50,5 +292,34 @@ func Defaults() *Config {
 		Story: Story{
 			BranchNameTemplate: DefaultBranchNameTemplate,
 		},
+		Ports: Ports{
+			Base:      DefaultPortBase,
+			BlockSize: DefaultPortBlockSize,
+		},
+		Daemon: Daemon{
+			IdleTimeout: DefaultDaemonIdleTimeout,
+		},
+	}
+}
+
+// HistfilePath returns the shell history file of storyName when story.histfile
+// is enabled, or "" otherwise. It lives in the story's data dir, next to its
+// workspace snapshot.
+func (c *Config) HistfilePath(storyName string) string {
+	if !c.Story.Histfile {
+		return ""
 	}
+
+	return filepath.Join(c.StoryDataDir(storyName), histfileName)
+}
+
+// StoryDataDir returns the directory holding the per-story state of
+// storyName, such as its workspace snapshot, service logs and shell history.
+func (c *Config) StoryDataDir(storyName string) string {
+	dataHome := c.DataHome
+	if dataHome == "" {
+		dataHome = xdg.DataHome
+	}
+
+	return story.DataDir(filepath.Join(dataHome, "swm", "stories"), storyName)
 }
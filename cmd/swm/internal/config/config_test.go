@@ -3,7 +3,9 @@ package config_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -83,6 +85,7 @@ default_story = "main"
 session = "tmux"
 vcs = "git"
 picker = "fzf"
+tracker = "jira"
 forges = ["github"]
 
 [plugins.config.vcs-git]
@@ -95,12 +98,168 @@ foo = "bar"
 	require.Equal(t, "/mycode", cfg.CodeRoot)
 	require.Equal(t, "main", cfg.DefaultStory)
 	require.Equal(t, "tmux", cfg.Plugins.Session)
-	require.Equal(t, "git", cfg.Plugins.VCS)
+	require.Equal(t, config.Names{"git"}, cfg.Plugins.VCS)
 	require.Equal(t, "fzf", cfg.Plugins.Picker)
+	require.Equal(t, "jira", cfg.Plugins.Tracker)
 	require.Equal(t, []string{"github"}, cfg.Plugins.Forges)
 	require.Contains(t, cfg.Plugins.Config, "vcs-git")
 }
 
+func TestLoad_VCSList(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[plugins]\nvcs = [\"git\", \"jj\"]\n"), 0o600))
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, config.Names{"git", "jj"}, cfg.Plugins.VCS)
+	require.Equal(t, "git", cfg.Plugins.VCS.Default())
+}
+
+func TestLoad_DaemonIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[daemon]\nidle_timeout = \"5m\"\n"), 0o600))
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, config.Duration(5*time.Minute), cfg.Daemon.IdleTimeout)
+	require.Equal(t, config.DefaultDaemonIdleTimeout, config.Defaults().Daemon.IdleTimeout)
+}
+
+func TestLoad_PluginTimeouts(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`[plugins.timeouts]
+vcs = "30s"
+"vcs.CreateWorktree" = "5m"
+`), 0o600))
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Minute, cfg.Plugins.Timeout("vcs", "CreateWorktree"))
+	require.Equal(t, 30*time.Second, cfg.Plugins.Timeout("vcs", "RemoveWorktree"))
+	require.Zero(t, cfg.Plugins.Timeout("session", "OpenWorkspace"))
+}
+
+func TestLoad_PluginTimeoutsUnknownCapability(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[plugins.timeouts]\n\"vsc.Clone\" = \"5m\"\n"), 0o600))
+
+	_, err := config.Load(path)
+	require.ErrorContains(t, err, `unknown plugin capability "vsc"`)
+}
+
+func TestLoad_PluginSandbox(t *testing.T) {
+	t.Parallel()
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`[plugins.sandbox.vcs-git]
+allow_write = ["~/.cache/git"]
+
+[plugins.sandbox.picker-fzf]
+`), 0o600))
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]config.Sandbox{
+		"vcs-git":    {AllowWrite: []string{filepath.Join(home, ".cache", "git")}},
+		"picker-fzf": {},
+	}, cfg.Plugins.Sandbox)
+}
+
+func TestLoad_PluginChecksums(t *testing.T) {
+	t.Parallel()
+
+	sum := strings.Repeat("AB", 32)
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[plugins.checksums]\n\"vcs-git\" = \""+sum+"\"\n"), 0o600))
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"vcs-git": strings.ToLower(sum)}, cfg.Plugins.Checksums)
+
+	require.NoError(t, os.WriteFile(path, []byte("[plugins.checksums]\n\"vcs-git\" = \"abc\"\n"), 0o600))
+
+	_, err = config.Load(path)
+	require.ErrorContains(t, err, "plugins.checksums: invalid checksum for vcs-git")
+}
+
+func TestLoad_ForgeHosts(t *testing.T) {
+	t.Parallel()
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`[plugins]
+forges = ["github"]
+
+[plugins.forge_hosts]
+"GitHub.example.com" = "github"
+"*.corp.example.com" = { plugin = "github", token_path = "~/.config/swm/ghe_token" }
+"*.example.com" = { plugin = "github", api_url = "https://ghe.example.com/api/v3" }
+`), 0o600))
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]config.ForgeHost{
+		"github.example.com": {Plugin: "github"},
+		"*.corp.example.com": {Plugin: "github", TokenPath: filepath.Join(home, ".config", "swm", "ghe_token")},
+		"*.example.com":      {Plugin: "github", APIURL: "https://ghe.example.com/api/v3"},
+	}, cfg.Plugins.ForgeHosts)
+
+	for host, want := range map[string]string{
+		"github.example.com":      "github.example.com",
+		"git.corp.example.com":    "*.corp.example.com",
+		"git.eu.corp.example.com": "*.corp.example.com",
+		"other.example.com":       "*.example.com",
+		"GITHUB.EXAMPLE.COM":      "github.example.com",
+	} {
+		got, ok := cfg.Plugins.ForgeHostFor(host)
+		require.True(t, ok, host)
+		require.Equal(t, cfg.Plugins.ForgeHosts[want], got, host)
+	}
+
+	_, ok := cfg.Plugins.ForgeHostFor("github.com")
+	require.False(t, ok)
+
+	for body, wantErr := range map[string]string{
+		`"x.example.com" = "gitlab"`:                             `forge "gitlab" is not in plugins.forges`,
+		`"[x.example.com" = "github"`:                            `invalid forge host "[x.example.com"`,
+		`"x.example.com" = { plugin = "github", api_url = "x" }`: `api_url "x" is not an absolute URL`,
+	} {
+		require.NoError(t, os.WriteFile(path, []byte("[plugins]\nforges = [\"github\"]\n\n"+
+			"[plugins.forge_hosts]\n"+body+"\n"), 0o600))
+
+		_, err = config.Load(path)
+		require.ErrorContains(t, err, wantErr)
+	}
+}
+
+func TestPlugins_Configured(t *testing.T) {
+	t.Parallel()
+
+	p := config.Plugins{
+		Session: "tmux",
+		VCS:     config.Names{"git", "jj"},
+		Tracker: "jira",
+		Forges:  []string{"github", "gitlab"},
+	}
+
+	require.Equal(t, []string{"session-tmux", "vcs-git", "vcs-jj", "tracker-jira", "forge-github", "forge-gitlab"},
+		p.Configured())
+}
+
 func TestLoad_MissingOptionalFields(t *testing.T) {
 	t.Parallel()
 
@@ -188,3 +347,22 @@ func TestLoad_BadTOML(t *testing.T) {
 	require.Error(t, err)
 	require.NotErrorIs(t, err, config.ErrConfigNotFound)
 }
+
+func TestHistfilePath(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Defaults()
+	cfg.DataHome = "/data"
+	require.Empty(t, cfg.HistfilePath("feat-x"))
+
+	cfg.Story.Histfile = true
+	require.Equal(t, filepath.Join("/data", "swm", "stories", "feat-x", "history"), cfg.HistfilePath("feat-x"))
+}
+
+func TestStoryDataDir(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Defaults()
+	cfg.DataHome = "/data"
+	require.Equal(t, filepath.Join("/data", "swm", "stories", "feat-x"), cfg.StoryDataDir("feat-x"))
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -192,11 +194,11 @@ func keyRegistry() []KeyDef {
 		},
 		{
 			Path:        "plugins.vcs",
-			Description: "VCS plugin name (e.g. git)",
+			Description: "VCS plugin names, default first (e.g. git or git,jj)",
 			Writable:    true,
-			get:         func(cfg *Config) string { return cfg.Plugins.VCS },
+			get:         func(cfg *Config) string { return cfg.Plugins.VCS.String() },
 			set: func(cfg *Config, v string) error {
-				cfg.Plugins.VCS = v
+				cfg.Plugins.VCS = ParseNames(v)
 
 				return nil
 			},
@@ -212,6 +214,17 @@ func keyRegistry() []KeyDef {
 				return nil
 			},
 		},
+		{
+			Path:        "plugins.tracker",
+			Description: "Tracker plugin name (e.g. jira)",
+			Writable:    true,
+			get:         func(cfg *Config) string { return cfg.Plugins.Tracker },
+			set: func(cfg *Config, v string) error {
+				cfg.Plugins.Tracker = v
+
+				return nil
+			},
+		},
 		{
 			Path:        "plugins.forges",
 			Description: "Forge plugin names (read-only via set; edit config.toml to change)",
@@ -230,6 +243,22 @@ func keyRegistry() []KeyDef {
 			},
 			set: nil,
 		},
+		{
+			Path:        "plugins.bundled",
+			Description: "Run the plugins built into swm when their binaries are not found (default: false)",
+			Writable:    true,
+			get:         func(cfg *Config) string { return strconv.FormatBool(cfg.Plugins.Bundled) },
+			set: func(cfg *Config, v string) error {
+				b, err := strconv.ParseBool(v)
+				if err != nil {
+					return fmt.Errorf("plugins.bundled: %w", err)
+				}
+
+				cfg.Plugins.Bundled = b
+
+				return nil
+			},
+		},
 		{
 			Path:        "story.branch_name_template",
 			Description: `Go template for branch names on story create (default: feat/{{.Name}})`,
@@ -238,6 +267,99 @@ func keyRegistry() []KeyDef {
 			set: func(cfg *Config, v string) error {
 				cfg.Story.BranchNameTemplate = v
 
+				return nil
+			},
+		},
+		{
+			Path:        "story.histfile",
+			Description: "Set HISTFILE to a per-story history file in sessions, hooks and commands (default: false)",
+			Writable:    true,
+			get:         func(cfg *Config) string { return strconv.FormatBool(cfg.Story.Histfile) },
+			set: func(cfg *Config, v string) error {
+				b, err := strconv.ParseBool(v)
+				if err != nil {
+					return fmt.Errorf("story.histfile: %w", err)
+				}
+
+				cfg.Story.Histfile = b
+
+				return nil
+			},
+		},
+		{
+			Path:        "ports.base",
+			Description: "First port handed out to stories (default: 20000)",
+			Writable:    true,
+			get:         func(cfg *Config) string { return strconv.Itoa(cfg.Ports.Base) },
+			set: func(cfg *Config, v string) error {
+				n, err := strconv.Atoi(v)
+				if err != nil {
+					return fmt.Errorf("ports.base: %w", err)
+				}
+
+				cfg.Ports.Base = n
+
+				return nil
+			},
+		},
+		{
+			Path:        "ports.block_size",
+			Description: "Number of ports allocated to each story; 0 disables allocation (default: 100)",
+			Writable:    true,
+			get:         func(cfg *Config) string { return strconv.Itoa(cfg.Ports.BlockSize) },
+			set: func(cfg *Config, v string) error {
+				n, err := strconv.Atoi(v)
+				if err != nil {
+					return fmt.Errorf("ports.block_size: %w", err)
+				}
+
+				cfg.Ports.BlockSize = n
+
+				return nil
+			},
+		},
+		{
+			Path:        "workspace.autosave",
+			Description: "Save a workspace snapshot before swm workspace close (default: false)",
+			Writable:    true,
+			get:         func(cfg *Config) string { return strconv.FormatBool(cfg.Workspace.Autosave) },
+			set: func(cfg *Config, v string) error {
+				b, err := strconv.ParseBool(v)
+				if err != nil {
+					return fmt.Errorf("workspace.autosave: %w", err)
+				}
+
+				cfg.Workspace.Autosave = b
+
+				return nil
+			},
+		},
+		{
+			Path:        "workspace.restore_on_open",
+			Description: "Restore the last workspace snapshot when swm workspace open starts it (default: false)",
+			Writable:    true,
+			get:         func(cfg *Config) string { return strconv.FormatBool(cfg.Workspace.RestoreOnOpen) },
+			set: func(cfg *Config, v string) error {
+				b, err := strconv.ParseBool(v)
+				if err != nil {
+					return fmt.Errorf("workspace.restore_on_open: %w", err)
+				}
+
+				cfg.Workspace.RestoreOnOpen = b
+
+				return nil
+			},
+		},
+		{
+			Path:        "daemon.idle_timeout",
+			Description: "Stop swm daemon after it served no request for this long; 0 never stops it (default: 30m)",
+			Writable:    true,
+			get:         func(cfg *Config) string { return time.Duration(cfg.Daemon.IdleTimeout).String() },
+			set: func(cfg *Config, v string) error {
+				if err := cfg.Daemon.IdleTimeout.UnmarshalText([]byte(v)); err != nil {
+					return fmt.Errorf("daemon.idle_timeout: %w", err)
+				}
+
 				return nil
 			},
 		},
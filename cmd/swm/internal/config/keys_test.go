@@ -19,8 +19,16 @@ func TestLookupKey_StaticKeys(t *testing.T) {
 		"plugins.session",
 		"plugins.vcs",
 		"plugins.picker",
+		"plugins.tracker",
 		"plugins.forges",
+		"plugins.bundled",
 		"story.branch_name_template",
+		"story.histfile",
+		"ports.base",
+		"ports.block_size",
+		"workspace.autosave",
+		"workspace.restore_on_open",
+		"daemon.idle_timeout",
 	}
 
 	for _, path := range paths {
@@ -83,8 +91,15 @@ func TestKeyDef_ScalarRoundTrip(t *testing.T) {
 		{"default_story", "main"},
 		{"plugins.session", testValTmux},
 		{"plugins.vcs", testValGit},
+		{"plugins.vcs", "git,jj"},
 		{"plugins.picker", testValFzf},
+		{"plugins.tracker", "jira"},
+		{"plugins.bundled", "true"},
 		{"story.branch_name_template", "fix/{{.Name}}"},
+		{"story.histfile", "true"},
+		{"workspace.autosave", "true"},
+		{"workspace.restore_on_open", "true"},
+		{"daemon.idle_timeout", "1h0m0s"},
 	}
 
 	for _, tc := range tests {
@@ -101,6 +116,22 @@ func TestKeyDef_ScalarRoundTrip(t *testing.T) {
 	}
 }
 
+func TestKeyDef_BoolRejectsInvalidValue(t *testing.T) {
+	t.Parallel()
+
+	k, ok := config.LookupKey("workspace.autosave")
+	require.True(t, ok)
+	require.Error(t, k.Set(config.Defaults(), "maybe"))
+}
+
+func TestKeyDef_DurationRejectsInvalidValue(t *testing.T) {
+	t.Parallel()
+
+	k, ok := config.LookupKey("daemon.idle_timeout")
+	require.True(t, ok)
+	require.Error(t, k.Set(config.Defaults(), "soon"))
+}
+
 func TestAllKeys_StableOrder(t *testing.T) {
 	t.Parallel()
 
@@ -3,8 +3,12 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/pelletier/go-toml/v2"
@@ -13,6 +17,20 @@ import (
 // ErrConfigNotFound is returned by Load when the config file does not exist.
 var ErrConfigNotFound = errors.New("config file not found")
 
+var (
+	errUnknownCapability = errors.New("unknown plugin capability")
+	errInvalidChecksum   = errors.New("invalid checksum")
+	errInvalidForgeHost  = errors.New("invalid forge host")
+)
+
+// checksumRe matches a hex-encoded SHA-256.
+var checksumRe = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// capabilities returns the plugin capabilities [plugins.timeouts] may name.
+func capabilities() []string {
+	return []string{"session", "vcs", "picker", "tracker", "forge"}
+}
+
 // ResolveConfigPath returns the effective config file path.
 // When swmConfig (the value of $SWM_CONFIG) is non-empty it is returned as-is.
 // Otherwise the XDG default <xdgConfigHome>/swm/config.toml is returned.
@@ -58,9 +76,80 @@ func Load(path string) (*Config, error) {
 		cfg.Plugins.Paths[name] = expanded
 	}
 
+	for name, sb := range cfg.Plugins.Sandbox {
+		for i, p := range sb.AllowWrite {
+			expanded, err := expandTilde(p)
+			if err != nil {
+				return nil, fmt.Errorf("expanding sandbox path for %s: %w", name, err)
+			}
+
+			sb.AllowWrite[i] = expanded
+		}
+	}
+
+	if err := loadForgeHosts(cfg); err != nil {
+		return nil, err
+	}
+
+	for key := range cfg.Plugins.Timeouts {
+		capability, _, _ := strings.Cut(key, ".")
+		if !slices.Contains(capabilities(), capability) {
+			return nil, fmt.Errorf("plugins.timeouts: %w %q", errUnknownCapability, capability)
+		}
+	}
+
+	for name, sum := range cfg.Plugins.Checksums {
+		sum = strings.ToLower(sum)
+		if !checksumRe.MatchString(sum) {
+			return nil, fmt.Errorf("plugins.checksums: %w for %s: want a hex-encoded SHA-256", errInvalidChecksum, name)
+		}
+
+		cfg.Plugins.Checksums[name] = sum
+	}
+
 	return cfg, nil
 }
 
+// loadForgeHosts checks the [plugins.forge_hosts] entries of cfg, lower-cases
+// their hosts and expands their token paths.
+func loadForgeHosts(cfg *Config) error {
+	if len(cfg.Plugins.ForgeHosts) == 0 {
+		return nil
+	}
+
+	hosts := make(map[string]ForgeHost, len(cfg.Plugins.ForgeHosts))
+
+	for pattern, h := range cfg.Plugins.ForgeHosts {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("plugins.forge_hosts: %w %q: %w", errInvalidForgeHost, pattern, err)
+		}
+
+		if !slices.Contains(cfg.Plugins.Forges, h.Plugin) {
+			return fmt.Errorf("plugins.forge_hosts: %w %q: forge %q is not in plugins.forges",
+				errInvalidForgeHost, pattern, h.Plugin)
+		}
+
+		if h.APIURL != "" {
+			if u, err := url.Parse(h.APIURL); err != nil || u.Scheme == "" || u.Host == "" {
+				return fmt.Errorf("plugins.forge_hosts: %w %q: api_url %q is not an absolute URL",
+					errInvalidForgeHost, pattern, h.APIURL)
+			}
+		}
+
+		expanded, err := expandTilde(h.TokenPath)
+		if err != nil {
+			return fmt.Errorf("expanding token path for forge host %s: %w", pattern, err)
+		}
+
+		h.TokenPath = expanded
+		hosts[strings.ToLower(pattern)] = h
+	}
+
+	cfg.Plugins.ForgeHosts = hosts
+
+	return nil
+}
+
 // expandTilde replaces a leading "~/" with the current user's home directory.
 func expandTilde(path string) (string, error) {
 	if path != "~" && !strings.HasPrefix(path, "~/") {
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -61,6 +62,7 @@ func TestSave_OmitsZeroValueFields(t *testing.T) {
 	require.NotContains(t, string(data), "default_story")
 	require.NotContains(t, string(data), "session")
 	require.NotContains(t, string(data), "vcs")
+	require.NotContains(t, string(data), "daemon")
 }
 
 func TestSave_PreservesUnknownFields(t *testing.T) {
@@ -123,6 +125,8 @@ func TestSaveLoadRoundTrip(t *testing.T) {
 	require.NoError(t, err)
 
 	cfg.Plugins.Session = testValTmux
+	cfg.Plugins.VCS = config.Names{"git", "jj"}
+	cfg.Daemon.IdleTimeout = config.Duration(90 * time.Second)
 	cfg.DefaultStory = "main"
 	require.NoError(t, config.Save(path, cfg))
 
@@ -130,6 +134,8 @@ func TestSaveLoadRoundTrip(t *testing.T) {
 	loaded, err := config.Load(path)
 	require.NoError(t, err)
 	require.Equal(t, testValTmux, loaded.Plugins.Session)
+	require.Equal(t, config.Names{"git", "jj"}, loaded.Plugins.VCS)
+	require.Equal(t, config.Duration(90*time.Second), loaded.Daemon.IdleTimeout)
 	require.Equal(t, "main", loaded.DefaultStory)
 	// code_root was not set, so Load's default applies (expanded to absolute path)
 	require.NotEmpty(t, loaded.CodeRoot)
@@ -58,6 +58,33 @@ func (r *Resolver) ProjectIDFromPath(path string) *pluginv1.ProjectID {
 	}
 }
 
+// StoryNameFromPath derives the story a path belongs to from its location under
+// the code root: <code_root>/stories/<story>/... yields <story> and anything
+// under <code_root>/repositories/ yields the default story.
+// Returns "" if the path is not inside a story or the repositories tree.
+func (r *Resolver) StoryNameFromPath(path string) string {
+	if _, ok := relUnder(filepath.Join(r.codeRoot, "repositories"), path); ok {
+		return r.defaultStoryName
+	}
+
+	rel, ok := relUnder(filepath.Join(r.codeRoot, "stories"), path)
+	if !ok || rel == "." {
+		return ""
+	}
+
+	return strings.Split(rel, string(filepath.Separator))[0]
+}
+
+// relUnder returns path relative to dir, and whether path is dir or inside it.
+func relUnder(dir, path string) (string, bool) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return rel, true
+}
+
 // WorktreePath returns the filesystem path for a project within a story.
 // For the default story the project lives at the canonical repositories/ path,
 // not inside a git worktree under stories/.
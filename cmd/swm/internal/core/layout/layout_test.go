@@ -96,3 +96,58 @@ func TestWorktreePath(t *testing.T) {
 		})
 	}
 }
+
+func TestStoryNameFromPath(t *testing.T) {
+	t.Parallel()
+
+	r := layout.NewResolver("/home/user/code", "_default")
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "story worktree",
+			path:     "/home/user/code/stories/feat-x/github.com/kalbasit/swm",
+			expected: "feat-x",
+		},
+		{
+			name:     "nested directory in story worktree",
+			path:     "/home/user/code/stories/feat-x/github.com/kalbasit/swm/cmd/swm",
+			expected: "feat-x",
+		},
+		{
+			name:     "story root",
+			path:     "/home/user/code/stories/feat-x",
+			expected: "feat-x",
+		},
+		{
+			name:     "repositories maps to default story",
+			path:     "/home/user/code/repositories/github.com/kalbasit/swm",
+			expected: "_default",
+		},
+		{
+			name:     "stories dir itself",
+			path:     "/home/user/code/stories",
+			expected: "",
+		},
+		{
+			name:     "outside code root",
+			path:     "/tmp/elsewhere",
+			expected: "",
+		},
+		{
+			name:     "sibling with shared prefix",
+			path:     "/home/user/code/stories-old/feat-x",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, r.StoryNameFromPath(tt.path))
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/sdk/go/trace"
 )
 
 // Projects is an alias for ScanRepos that lets *Resolver satisfy the
@@ -26,6 +28,9 @@ func (r *Resolver) Projects(ctx context.Context) ([]*pluginv1.ProjectID, error)
 // host's children: one goroutine per child, each checking for .git before
 // descending, stopping as soon as a repo root is found.
 func (r *Resolver) ScanRepos(ctx context.Context) ([]*pluginv1.ProjectID, error) {
+	ctx, span := trace.Start(ctx, "scan repos", "code_root", r.codeRoot)
+	defer span.End()
+
 	start := time.Now()
 
 	defer func() {
@@ -0,0 +1,248 @@
+// Package ports allocates a block of TCP ports to every story so that the
+// same service can run in several stories at once, and resolves the named
+// ports projects declare in .swm/project.toml to ports of that block.
+package ports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+)
+
+// FileName is the project file declaring named ports, relative to a worktree.
+const FileName = ".swm/project.toml"
+
+// maxPort is the highest TCP port.
+const maxPort = 65535
+
+var (
+	// ErrNoFreeBlock is returned when every block of the port range is taken.
+	ErrNoFreeBlock = errors.New("no free port block")
+
+	errInvalidRange  = errors.New("invalid port range")
+	errInvalidName   = errors.New("port names must start with a letter and contain only letters, digits and _")
+	errInvalidOffset = errors.New("port offset outside the story's block")
+)
+
+// nameRe matches the port names accepted in .swm/project.toml.
+var nameRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// projectFile is the decoded .swm/project.toml.
+type projectFile struct {
+	// Ports maps a port name to its offset in the story's block.
+	Ports map[string]int `toml:"ports"`
+}
+
+// Allocate returns the first port of a block of size ports, starting at base,
+// for the story name that does not overlap the block of any other of stories.
+// The search starts at a block derived from a hash of name, so a story gets
+// the same block whenever it is free.
+func Allocate(stories []*coreStory.Story, name string, base, size int) (int, error) {
+	if base < 1 || size < 1 || base+size-1 > maxPort {
+		return 0, fmt.Errorf("%w: base %d, block size %d", errInvalidRange, base, size)
+	}
+
+	slots := (maxPort - base + 1) / size
+
+	start := int(crc32.ChecksumIEEE([]byte(name)) % uint32(slots)) //nolint:gosec // 0 < slots <= 65535
+
+	for i := range slots {
+		first := base + (start+i)%slots*size
+		if Overlapping(stories, name, first, size) == nil {
+			return first, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w between %d and %d", ErrNoFreeBlock, base, maxPort)
+}
+
+// Ensure allocates a block to s and saves it when s has none. A size of 0
+// disables allocation.
+func Ensure(ctx context.Context, store coreStory.Store, s *coreStory.Story, base, size int) error {
+	if s.PortBase != 0 || size == 0 {
+		return nil
+	}
+
+	stories, err := store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing stories: %w", err)
+	}
+
+	first, err := Allocate(stories, s.Name, base, size)
+	if err != nil {
+		return err
+	}
+
+	s.PortBase = first
+
+	if err := store.Update(ctx, s); err != nil {
+		return fmt.Errorf("saving port block of story %q: %w", s.Name, err)
+	}
+
+	return nil
+}
+
+// Overlapping returns the stories other than name whose block of size ports
+// overlaps the one starting at first.
+func Overlapping(stories []*coreStory.Story, name string, first, size int) []*coreStory.Story {
+	var out []*coreStory.Story
+
+	for _, s := range stories {
+		if s.Name == name || s.PortBase == 0 {
+			continue
+		}
+
+		if s.PortBase < first+size && first < s.PortBase+size {
+			out = append(out, s)
+		}
+	}
+
+	return out
+}
+
+// Load returns the named ports declared by the project checked out at
+// worktree, as offsets in a block of size ports. A project without a project
+// file declares none.
+func Load(worktree string, size int) (map[string]int, error) {
+	path := filepath.Join(worktree, FileName)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil //nolint:nilnil // no project file declares no ports
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var f projectFile
+	if err := toml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	for name, offset := range f.Ports {
+		if !nameRe.MatchString(name) {
+			return nil, fmt.Errorf("%s: port %q: %w", path, name, errInvalidName)
+		}
+
+		if offset < 0 || offset >= size {
+			return nil, fmt.Errorf("%s: port %q: %w: %d not in [0, %d)", path, name, errInvalidOffset, offset, size)
+		}
+	}
+
+	return f.Ports, nil
+}
+
+// Named returns the named ports of story s for the project key, or of every
+// attached project when key is empty, resolved against the story's block. A
+// name declared by several projects resolves to the first attached one's. A
+// story without a block has no named ports.
+func Named(resolver *layout.Resolver, s *coreStory.Story, key string, size int) (map[string]int, error) {
+	if s.PortBase == 0 {
+		return nil, nil //nolint:nilnil // a story without a block has no named ports
+	}
+
+	named := make(map[string]int)
+
+	for _, p := range s.Projects {
+		id := &pluginv1.ProjectID{Host: p.Host, Segments: p.Segments}
+		if key != "" && key != p.Host+"/"+strings.Join(p.Segments, "/") {
+			continue
+		}
+
+		offsets, err := Load(resolver.WorktreePath(s.Name, id), size)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(offsets)) {
+			if _, ok := named[name]; !ok {
+				named[name] = s.PortBase + offsets[name]
+			}
+		}
+	}
+
+	return named, nil
+}
+
+// StoryEnvironment returns the environment of story s for the project key
+// (see Story.Environment) with the story's SWM_PORT_* variables added: those
+// of the project's named ports, or of every project's when key is empty.
+func StoryEnvironment(
+	resolver *layout.Resolver,
+	s *coreStory.Story,
+	key, histfile string,
+	size int,
+) (map[string]string, error) {
+	env := s.Environment(key, histfile)
+
+	named, err := Named(resolver, s, key, size)
+	if err != nil {
+		return env, err
+	}
+
+	maps.Copy(env, Variables(s.PortBase, named))
+
+	return env, nil
+}
+
+// Variables returns SWM_PORT_BASE and a SWM_PORT_<NAME> variable for each of
+// named, or nil when base is 0.
+func Variables(base int, named map[string]int) map[string]string {
+	if base == 0 {
+		return nil
+	}
+
+	env := map[string]string{"SWM_PORT_BASE": strconv.Itoa(base)}
+
+	for name, port := range named {
+		env["SWM_PORT_"+strings.ToUpper(name)] = strconv.Itoa(port)
+	}
+
+	return env
+}
+
+// Store is a coreStory.Store that allocates a port block to every story it
+// creates.
+type Store struct {
+	coreStory.Store
+
+	base, size int
+}
+
+// NewStore returns a Store creating stories in inner with blocks of size ports
+// starting at base. A size of 0 disables allocation.
+func NewStore(inner coreStory.Store, base, size int) *Store {
+	return &Store{Store: inner, base: base, size: size}
+}
+
+// Create creates the story in the wrapped store and allocates its block. A
+// story whose block cannot be allocated is still created, without ports.
+func (s *Store) Create(ctx context.Context, name, branchName string) (*coreStory.Story, error) {
+	st, err := s.Store.Create(ctx, name, branchName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Ensure(ctx, s.Store, st, s.base, s.size); err != nil {
+		slog.WarnContext(ctx, "ports: cannot allocate a port block", "story", name, "err", err)
+	}
+
+	return st, nil
+}
@@ -0,0 +1,226 @@
+package ports_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	coreStory "github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/ports"
+)
+
+func writeProjectFile(t *testing.T, worktree, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(worktree, ".swm"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ports.FileName), []byte(content), 0o600))
+}
+
+func TestAllocate_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	first, err := ports.Allocate(nil, "feat-x", 20000, 100)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, first, 20000)
+	require.LessOrEqual(t, first+99, 65535)
+	require.Zero(t, (first-20000)%100, "blocks are aligned on the block size")
+
+	again, err := ports.Allocate(nil, "feat-x", 20000, 100)
+	require.NoError(t, err)
+	require.Equal(t, first, again)
+}
+
+func TestAllocate_SkipsTakenBlocks(t *testing.T) {
+	t.Parallel()
+
+	first, err := ports.Allocate(nil, "feat-x", 20000, 100)
+	require.NoError(t, err)
+
+	taken := []*coreStory.Story{{Name: "other", PortBase: first + 50}}
+
+	next, err := ports.Allocate(taken, "feat-x", 20000, 100)
+	require.NoError(t, err)
+	require.NotEqual(t, first, next)
+	require.Empty(t, ports.Overlapping(taken, "feat-x", next, 100))
+
+	// A story's own block never counts as taken.
+	own, err := ports.Allocate([]*coreStory.Story{{Name: "feat-x", PortBase: first}}, "feat-x", 20000, 100)
+	require.NoError(t, err)
+	require.Equal(t, first, own)
+}
+
+func TestAllocate_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := ports.Allocate([]*coreStory.Story{{Name: "other", PortBase: 65000}}, "feat-x", 65000, 500)
+	require.ErrorIs(t, err, ports.ErrNoFreeBlock)
+
+	_, err = ports.Allocate(nil, "feat-x", 65500, 100)
+	require.ErrorContains(t, err, "invalid port range")
+
+	_, err = ports.Allocate(nil, "feat-x", 0, 100)
+	require.ErrorContains(t, err, "invalid port range")
+}
+
+func TestOverlapping(t *testing.T) {
+	t.Parallel()
+
+	stories := []*coreStory.Story{
+		{Name: "a", PortBase: 20000},
+		{Name: "b", PortBase: 20100},
+		{Name: "c"},
+	}
+
+	require.Empty(t, ports.Overlapping(stories, "a", 20000, 100))
+	require.Empty(t, ports.Overlapping(stories, "d", 20200, 100))
+
+	got := ports.Overlapping(stories, "d", 20050, 100)
+	require.Len(t, got, 2)
+	require.Equal(t, "a", got[0].Name)
+	require.Equal(t, "b", got[1].Name)
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	wt := t.TempDir()
+	writeProjectFile(t, wt, "[ports]\napi = 0\nweb = 1\n")
+
+	named, err := ports.Load(wt, 100)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"api": 0, "web": 1}, named)
+
+	named, err = ports.Load(t.TempDir(), 100)
+	require.NoError(t, err)
+	require.Nil(t, named)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"offset past block", "[ports]\napi = 100\n", "outside the story's block"},
+		{"negative offset", "[ports]\napi = -1\n", "outside the story's block"},
+		{"bad name", "[ports]\n\"my-api\" = 1\n", "port names must"},
+		{"bad toml", "[ports\n", "parsing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			wt := t.TempDir()
+			writeProjectFile(t, wt, tt.content)
+
+			_, err := ports.Load(wt, 100)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestStoryEnvironment(t *testing.T) {
+	t.Parallel()
+
+	codeRoot := t.TempDir()
+	resolver := layout.NewResolver(codeRoot, "_default")
+
+	s := &coreStory.Story{
+		Name:     "feat-x",
+		PortBase: 20100,
+		Env:      map[string]string{"FOO": "bar"},
+		Projects: []coreStory.Project{
+			{Host: "github.com", Segments: []string{"org", "api"}},
+			{Host: "github.com", Segments: []string{"org", "web"}},
+		},
+	}
+
+	writeProjectFile(t, resolver.WorktreePath(s.Name, &pluginv1.ProjectID{
+		Host: "github.com", Segments: []string{"org", "api"},
+	}), "[ports]\napi = 1\nshared = 2\n")
+	writeProjectFile(t, resolver.WorktreePath(s.Name, &pluginv1.ProjectID{
+		Host: "github.com", Segments: []string{"org", "web"},
+	}), "[ports]\nweb = 3\nshared = 4\n")
+
+	env, err := ports.StoryEnvironment(resolver, s, "", "", 100)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"FOO":             "bar",
+		"SWM_PORT_BASE":   "20100",
+		"SWM_PORT_API":    "20101",
+		"SWM_PORT_SHARED": "20102",
+		"SWM_PORT_WEB":    "20103",
+	}, env)
+
+	env, err = ports.StoryEnvironment(resolver, s, "github.com/org/web", "", 100)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"FOO":             "bar",
+		"SWM_PORT_BASE":   "20100",
+		"SWM_PORT_SHARED": "20104",
+		"SWM_PORT_WEB":    "20103",
+	}, env)
+
+	s.PortBase = 0
+
+	env, err = ports.StoryEnvironment(resolver, s, "", "", 100)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"FOO": "bar"}, env)
+}
+
+func TestStore_CreateAllocates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := ports.NewStore(coreStory.NewJSONStore(t.TempDir()), 20000, 100)
+
+	a, err := store.Create(ctx, "feat-a", "feat-a")
+	require.NoError(t, err)
+	require.NotZero(t, a.PortBase)
+
+	b, err := store.Create(ctx, "feat-b", "feat-b")
+	require.NoError(t, err)
+	require.NotZero(t, b.PortBase)
+
+	stories, err := store.List(ctx)
+	require.NoError(t, err)
+
+	for _, s := range stories {
+		if s.PortBase != 0 {
+			require.Empty(t, ports.Overlapping(stories, s.Name, s.PortBase, 100))
+		}
+	}
+
+	saved, err := store.Get(ctx, "feat-a")
+	require.NoError(t, err)
+	require.Equal(t, a.PortBase, saved.PortBase, "the block is persisted with the story")
+}
+
+func TestEnsure_Disabled(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := coreStory.NewJSONStore(t.TempDir())
+
+	s, err := store.Create(ctx, "feat-x", "feat-x")
+	require.NoError(t, err)
+
+	require.NoError(t, ports.Ensure(ctx, store, s, 20000, 0))
+	require.Zero(t, s.PortBase)
+
+	require.NoError(t, ports.Ensure(ctx, store, s, 20000, 100))
+	require.NotZero(t, s.PortBase)
+
+	first := s.PortBase
+	require.NoError(t, ports.Ensure(ctx, store, s, 30000, 100))
+	require.Equal(t, first, s.PortBase, "an allocated block is kept")
+}
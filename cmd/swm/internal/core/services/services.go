@@ -0,0 +1,363 @@
+// Package services loads the long-running development processes a project
+// declares in .swm/services.toml and orders them by their dependencies. The
+// processes themselves run in the story's workspace through the session
+// plugin.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+)
+
+// FileName is the services file of a project, relative to its worktree.
+const FileName = ".swm/services.toml"
+
+// Restart policies.
+const (
+	RestartNo        = "no"
+	RestartOnFailure = "on-failure"
+	RestartAlways    = "always"
+)
+
+var (
+	errEmptyCommand      = errors.New("command is required")
+	errInvalidRestart    = errors.New(`restart must be "no", "on-failure" or "always"`)
+	errInvalidCwd        = errors.New("cwd must be a relative path inside the worktree")
+	errUnknownDependency = errors.New("unknown dependency")
+	errDependencyCycle   = errors.New("dependency cycle")
+	errUnknownService    = errors.New("unknown service")
+)
+
+// Definition is one [services.<name>] table of a services file.
+type Definition struct {
+	// Command is run with `sh -c`.
+	Command string            `toml:"command"`
+	Env     map[string]string `toml:"env"`
+	// Cwd is relative to the worktree; empty means the worktree itself.
+	Cwd string `toml:"cwd"`
+	// DependsOn names services started before this one: a bare name refers
+	// to a service of the same project, host/owner/repo:name to another
+	// project's.
+	DependsOn []string `toml:"depends_on"`
+	// Restart is RestartNo (the default), RestartOnFailure or RestartAlways.
+	Restart string `toml:"restart"`
+}
+
+// file is the decoded services file.
+type file struct {
+	Services map[string]Definition `toml:"services"`
+}
+
+// Service is a service declared by a project attached to a story.
+type Service struct {
+	// Name is the service name qualified by its project, host/owner/repo:name.
+	Name string
+	// Project is the project key (host/seg1/.../segN) and Worktree its
+	// worktree in the story.
+	Project  string
+	Worktree string
+	Definition
+}
+
+// Load reads the services file of the project key checked out at worktree and
+// returns its services sorted by name. A project without a services file has
+// no services.
+func Load(key, worktree string) ([]Service, error) {
+	path := filepath.Join(worktree, FileName)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var f file
+	if err := toml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	services := make([]Service, 0, len(f.Services))
+
+	for _, name := range slices.Sorted(maps.Keys(f.Services)) {
+		def := f.Services[name]
+
+		if err := validate(def); err != nil {
+			return nil, fmt.Errorf("%s: service %q: %w", path, name, err)
+		}
+
+		services = append(services, Service{Name: key + ":" + name, Project: key, Worktree: worktree, Definition: def})
+	}
+
+	return services, nil
+}
+
+// Order returns services sorted so that every service comes after its
+// dependencies, keeping the input order otherwise. Dependencies must be among
+// services.
+func Order(services []Service) ([]Service, error) {
+	byName := make(map[string]Service, len(services))
+	for _, svc := range services {
+		byName[svc.Name] = svc
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+
+	var (
+		state   = make(map[string]int, len(services))
+		ordered = make([]Service, 0, len(services))
+		visit   func(svc Service, path []string) error
+	)
+
+	visit = func(svc Service, path []string) error {
+		switch state[svc.Name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s", errDependencyCycle, strings.Join(append(path, svc.Name), " -> "))
+		}
+
+		state[svc.Name] = visiting
+
+		for _, dep := range svc.Dependencies() {
+			d, ok := byName[dep]
+			if !ok {
+				return fmt.Errorf("service %q: %w %q", svc.Name, errUnknownDependency, dep)
+			}
+
+			if err := visit(d, append(path, svc.Name)); err != nil {
+				return err
+			}
+		}
+
+		state[svc.Name] = done
+		ordered = append(ordered, svc)
+
+		return nil
+	}
+
+	for _, svc := range services {
+		if err := visit(svc, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// Match returns the services named by names, each either a qualified name or
+// a bare name matching the services of every project. An empty names matches
+// all services.
+func Match(services []Service, names []string) ([]Service, error) {
+	if len(names) == 0 {
+		return services, nil
+	}
+
+	var matched []Service
+
+	for _, name := range names {
+		found := false
+
+		for _, svc := range services {
+			if svc.Name == name || svc.BareName() == name {
+				found = true
+
+				if !slices.ContainsFunc(matched, func(m Service) bool { return m.Name == svc.Name }) {
+					matched = append(matched, svc)
+				}
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%w %q", errUnknownService, name)
+		}
+	}
+
+	return matched, nil
+}
+
+// Select returns the services named by names (see Match) together with their
+// transitive dependencies, in start order.
+func Select(services []Service, names []string) ([]Service, error) {
+	matched, err := Match(services, names)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]Service, len(services))
+	for _, svc := range services {
+		byName[svc.Name] = svc
+	}
+
+	selected := make(map[string]bool, len(services))
+	queue := matched
+
+	for len(queue) > 0 {
+		svc := queue[0]
+		queue = queue[1:]
+
+		if selected[svc.Name] {
+			continue
+		}
+
+		selected[svc.Name] = true
+
+		for _, dep := range svc.Dependencies() {
+			d, ok := byName[dep]
+			if !ok {
+				return nil, fmt.Errorf("service %q: %w %q", svc.Name, errUnknownDependency, dep)
+			}
+
+			queue = append(queue, d)
+		}
+	}
+
+	var subset []Service
+
+	for _, svc := range services {
+		if selected[svc.Name] {
+			subset = append(subset, svc)
+		}
+	}
+
+	return Order(subset)
+}
+
+// Argv returns the command the session plugin runs for s, wrapped in a shell
+// loop implementing its restart policy.
+func (s Service) Argv() []string {
+	switch s.Restart {
+	case RestartOnFailure:
+		return []string{"sh", "-c", restartLoop("&& exit 0"), "swm-service", s.Command}
+	case RestartAlways:
+		return []string{"sh", "-c", restartLoop(""), "swm-service", s.Command}
+	default:
+		return []string{"sh", "-c", s.Command}
+	}
+}
+
+// BareName returns the name of s within its project's services file.
+func (s Service) BareName() string {
+	return strings.TrimPrefix(s.Name, s.Project+":")
+}
+
+// Dependencies returns the qualified names of the services s depends on.
+func (s Service) Dependencies() []string {
+	deps := make([]string, 0, len(s.DependsOn))
+
+	for _, dep := range s.DependsOn {
+		if !strings.Contains(dep, ":") {
+			dep = s.Project + ":" + dep
+		}
+
+		deps = append(deps, dep)
+	}
+
+	return deps
+}
+
+// LogPath returns the log file of s below dir.
+func (s Service) LogPath(dir string) string {
+	return filepath.Join(dir, filepath.FromSlash(s.Project), s.BareName()+".log")
+}
+
+// WorkDir returns the directory s runs in.
+func (s Service) WorkDir() string {
+	return filepath.Join(s.Worktree, s.Cwd)
+}
+
+// List returns the services running, or exited, in workspace wsID. Session
+// plugins that predate services have none.
+func List(ctx context.Context, sess pluginv1.SessionClient, wsID string) ([]*pluginv1.Service, error) {
+	stream, err := sess.ListServices(ctx, &pluginv1.ListServicesRequest{WorkspaceId: wsID})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("listing services: %w", err)
+	}
+
+	var services []*pluginv1.Service
+
+	for {
+		svc, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return services, nil
+		}
+
+		if status.Code(err) == codes.Unimplemented {
+			return nil, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("listing services: %w", err)
+		}
+
+		services = append(services, svc)
+	}
+}
+
+// StopAll stops every service of workspace wsID, most recently started first
+// so that dependents stop before their dependencies.
+func StopAll(ctx context.Context, sess pluginv1.SessionClient, wsID string) error {
+	running, err := List(ctx, sess, wsID)
+	if err != nil {
+		return err
+	}
+
+	for _, svc := range slices.Backward(running) {
+		if _, err := sess.StopService(ctx, &pluginv1.StopServiceRequest{
+			WorkspaceId: wsID,
+			ServiceId:   svc.GetServiceId(),
+		}); err != nil {
+			return fmt.Errorf("stopping service %q: %w", svc.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// restartLoop returns a shell script running its first argument until it
+// exits; cond is appended to the command to break out of the loop.
+func restartLoop(cond string) string {
+	return `while :; do sh -c "$1" ` + cond +
+		`; echo "swm: service exited with status $?, restarting in 1s" >&2; sleep 1; done`
+}
+
+// validate checks a service definition.
+func validate(def Definition) error {
+	if strings.TrimSpace(def.Command) == "" {
+		return errEmptyCommand
+	}
+
+	switch def.Restart {
+	case "", RestartNo, RestartOnFailure, RestartAlways:
+	default:
+		return fmt.Errorf("%w, got %q", errInvalidRestart, def.Restart)
+	}
+
+	if def.Cwd != "" && !filepath.IsLocal(def.Cwd) {
+		return fmt.Errorf("%w, got %q", errInvalidCwd, def.Cwd)
+	}
+
+	return nil
+}
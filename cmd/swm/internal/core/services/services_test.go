@@ -0,0 +1,173 @@
+package services_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/core/services"
+)
+
+const testProject = "github.com/kalbasit/swm"
+
+func writeServicesFile(t *testing.T, content string) string {
+	t.Helper()
+
+	wt := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(wt, ".swm"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(wt, services.FileName), []byte(content), 0o600))
+
+	return wt
+}
+
+func names(svcs []services.Service) []string {
+	out := make([]string, 0, len(svcs))
+	for _, s := range svcs {
+		out = append(out, s.Name)
+	}
+
+	return out
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	wt := writeServicesFile(t, `
+[services.web]
+command = "npm run dev"
+cwd = "frontend"
+depends_on = ["db"]
+restart = "on-failure"
+env = { PORT = "3000" }
+
+[services.db]
+command = "postgres -D data"
+`)
+
+	svcs, err := services.Load(testProject, wt)
+	require.NoError(t, err)
+	require.Equal(t, []string{testProject + ":db", testProject + ":web"}, names(svcs))
+
+	web := svcs[1]
+	require.Equal(t, "web", web.BareName())
+	require.Equal(t, filepath.Join(wt, "frontend"), web.WorkDir())
+	require.Equal(t, map[string]string{"PORT": "3000"}, web.Env)
+	require.Equal(t, []string{testProject + ":db"}, web.Dependencies())
+	require.Equal(t, filepath.Join("/logs", "github.com", "kalbasit", "swm", "web.log"), web.LogPath("/logs"))
+}
+
+func TestLoad_NoFile(t *testing.T) {
+	t.Parallel()
+
+	svcs, err := services.Load(testProject, t.TempDir())
+	require.NoError(t, err)
+	require.Empty(t, svcs)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"missing command", "[services.web]\ncwd = \"x\"\n", "command is required"},
+		{"bad restart", "[services.web]\ncommand = \"x\"\nrestart = \"sometimes\"\n", "restart must be"},
+		{"absolute cwd", "[services.web]\ncommand = \"x\"\ncwd = \"/tmp\"\n", "cwd must be a relative path"},
+		{"escaping cwd", "[services.web]\ncommand = \"x\"\ncwd = \"../x\"\n", "cwd must be a relative path"},
+		{"bad toml", "[services.web\n", "parsing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := services.Load(testProject, writeServicesFile(t, tt.content))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestOrder(t *testing.T) {
+	t.Parallel()
+
+	svcs := []services.Service{
+		{Name: "h/a/app:web", Project: "h/a/app", Definition: services.Definition{DependsOn: []string{"api"}}},
+		{Name: "h/a/app:api", Project: "h/a/app", Definition: services.Definition{DependsOn: []string{"h/a/db:pg"}}},
+		{Name: "h/a/db:pg", Project: "h/a/db"},
+		{Name: "h/a/app:docs", Project: "h/a/app"},
+	}
+
+	ordered, err := services.Order(svcs)
+	require.NoError(t, err)
+	require.Equal(t, []string{"h/a/db:pg", "h/a/app:api", "h/a/app:web", "h/a/app:docs"}, names(ordered))
+}
+
+func TestOrder_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := services.Order([]services.Service{
+		{Name: "p:a", Project: "p", Definition: services.Definition{DependsOn: []string{"b"}}},
+		{Name: "p:b", Project: "p", Definition: services.Definition{DependsOn: []string{"a"}}},
+	})
+	require.ErrorContains(t, err, "dependency cycle: p:a -> p:b -> p:a")
+
+	_, err = services.Order([]services.Service{
+		{Name: "p:a", Project: "p", Definition: services.Definition{DependsOn: []string{"missing"}}},
+	})
+	require.ErrorContains(t, err, `unknown dependency "p:missing"`)
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	svcs := []services.Service{
+		{Name: "p:db", Project: "p"},
+		{Name: "p:api", Project: "p", Definition: services.Definition{DependsOn: []string{"db"}}},
+		{Name: "p:web", Project: "p", Definition: services.Definition{DependsOn: []string{"api"}}},
+		{Name: "p:docs", Project: "p"},
+	}
+
+	selected, err := services.Select(svcs, []string{"web"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"p:db", "p:api", "p:web"}, names(selected))
+
+	matched, err := services.Match(svcs, []string{"p:web"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"p:web"}, names(matched))
+
+	all, err := services.Select(svcs, nil)
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+
+	_, err = services.Select(svcs, []string{"nope"})
+	require.ErrorContains(t, err, `unknown service "nope"`)
+}
+
+func TestArgv_RestartPolicies(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "runs")
+
+	// The command fails twice, then succeeds.
+	cmd := `echo x >> ` + counter + `; [ "$(wc -l < ` + counter + `)" -ge 3 ]`
+
+	svc := services.Service{Definition: services.Definition{Command: cmd, Restart: services.RestartOnFailure}}
+	argv := svc.Argv()
+
+	out, err := exec.Command(argv[0], argv[1:]...).CombinedOutput() //nolint:gosec // test command
+	require.NoError(t, err, string(out))
+	require.Equal(t, 2, strings.Count(string(out), "restarting in 1s"))
+
+	svc.Restart = services.RestartNo
+	require.Equal(t, []string{"sh", "-c", cmd}, svc.Argv())
+
+	svc.Restart = services.RestartAlways
+	require.NotContains(t, svc.Argv()[2], "exit 0")
+}
@@ -0,0 +1,147 @@
+// Package statuscache persists the per-project VCS and forge state shown by
+// `swm status`. Commands that already talk to plugins write to the cache so
+// prompt segments can render it without launching any plugin.
+package statuscache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/gofrs/flock"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+)
+
+// PR is the cached state of the pull request opened for a project's story branch.
+type PR struct {
+	Number int64  `json:"number"`
+	State  string `json:"state"`
+	URL    string `json:"url,omitempty"`
+	Checks string `json:"checks,omitempty"`
+}
+
+// Entry is the cached state of one project within a story.
+type Entry struct {
+	Branch    string    `json:"branch,omitempty"`
+	Dirty     bool      `json:"dirty,omitempty"`
+	Ahead     int32     `json:"ahead,omitempty"`
+	Behind    int32     `json:"behind,omitempty"`
+	PR        *PR       `json:"pr,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Entries maps a project key (host/seg1/.../segN) to its cached state.
+type Entries map[string]*Entry
+
+// Cache reads and writes one JSON file per story under a directory.
+type Cache struct {
+	dir string
+}
+
+// New returns a Cache rooted at <cacheHome>/swm/status. When cacheHome is
+// empty the system XDG cache home is used.
+func New(cacheHome string) *Cache {
+	if cacheHome == "" {
+		cacheHome = xdg.CacheHome
+	}
+
+	return &Cache{dir: filepath.Join(cacheHome, "swm", "status")}
+}
+
+// Load returns the cached entries for storyName. A story that has never been
+// cached yields an empty, non-nil map.
+func (c *Cache) Load(storyName string) (Entries, error) {
+	data, err := os.ReadFile(c.path(storyName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Entries{}, nil
+		}
+
+		return nil, fmt.Errorf("reading status cache: %w", err)
+	}
+
+	entries := Entries{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing status cache: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Update applies fn to the cached entries for storyName and writes the result
+// back atomically. Concurrent updaters are serialized with a lock file.
+func (c *Cache) Update(storyName string, fn func(Entries)) error {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return fmt.Errorf("creating status cache directory: %w", err)
+	}
+
+	p := c.path(storyName)
+
+	fl := flock.New(p + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+
+	defer fl.Unlock() //nolint:errcheck // lock release errors are non-actionable
+
+	entries, err := c.Load(storyName)
+	if err != nil {
+		return err
+	}
+
+	fn(entries)
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling status cache: %w", err)
+	}
+
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing status cache: %w", err)
+	}
+	defer os.Remove(tmp) //nolint:errcheck // best-effort cleanup if Rename fails
+
+	if err := os.Rename(tmp, p); err != nil {
+		return fmt.Errorf("finalizing status cache: %w", err)
+	}
+
+	return nil
+}
+
+// ProjectKey returns the cache key for a project: host/seg1/.../segN.
+func ProjectKey(host string, segments []string) string {
+	return host + "/" + strings.Join(segments, "/")
+}
+
+// PRFromProto converts a forge pull request into its cached form. States and
+// check results are stored as lower-case words ("open", "merged", "pending").
+func PRFromProto(pr *pluginv1.PullRequest) *PR {
+	return &PR{
+		Number: pr.GetNumber(),
+		State:  enumWord(pr.GetState().String(), "PULL_REQUEST_STATE_"),
+		URL:    pr.GetUrl(),
+		Checks: enumWord(pr.GetCheckState().String(), "CHECK_STATE_"),
+	}
+}
+
+// enumWord turns a proto enum name such as CHECK_STATE_PENDING into "pending",
+// mapping the UNSPECIFIED value to "".
+func enumWord(name, prefix string) string {
+	word := strings.ToLower(strings.TrimPrefix(name, prefix))
+	if word == "unspecified" {
+		return ""
+	}
+
+	return word
+}
+
+func (c *Cache) path(storyName string) string {
+	return filepath.Join(c.dir, storyName+".json")
+}
@@ -0,0 +1,83 @@
+package statuscache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/core/statuscache"
+)
+
+func TestLoad_MissingStoryIsEmpty(t *testing.T) {
+	t.Parallel()
+
+	entries, err := statuscache.New(t.TempDir()).Load("nope")
+	require.NoError(t, err)
+	require.NotNil(t, entries)
+	require.Empty(t, entries)
+}
+
+func TestUpdate_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	cacheHome := t.TempDir()
+	c := statuscache.New(cacheHome)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, c.Update("my-story", func(e statuscache.Entries) {
+		e["github.com/kalbasit/swm"] = &statuscache.Entry{Branch: "feat/my-story", Dirty: true, UpdatedAt: now}
+	}))
+
+	require.NoError(t, c.Update("my-story", func(e statuscache.Entries) {
+		e["github.com/kalbasit/swm"].PR = &statuscache.PR{Number: 7, State: "open", Checks: "pending"}
+	}))
+
+	entries, err := c.Load("my-story")
+	require.NoError(t, err)
+	require.Equal(t, statuscache.Entries{
+		"github.com/kalbasit/swm": {
+			Branch:    "feat/my-story",
+			Dirty:     true,
+			PR:        &statuscache.PR{Number: 7, State: "open", Checks: "pending"},
+			UpdatedAt: now,
+		},
+	}, entries)
+
+	require.FileExists(t, filepath.Join(cacheHome, "swm", "status", "my-story.json"))
+}
+
+func TestLoad_CorruptFile(t *testing.T) {
+	t.Parallel()
+
+	cacheHome := t.TempDir()
+	dir := filepath.Join(cacheHome, "swm", "status")
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0o600))
+
+	_, err := statuscache.New(cacheHome).Load("bad")
+	require.Error(t, err)
+}
+
+func TestPRFromProto(t *testing.T) {
+	t.Parallel()
+
+	got := statuscache.PRFromProto(&pluginv1.PullRequest{
+		Number:     12,
+		State:      pluginv1.PullRequestState_PULL_REQUEST_STATE_MERGED,
+		Url:        "https://github.com/kalbasit/swm/pull/12",
+		CheckState: pluginv1.CheckState_CHECK_STATE_FAILURE,
+	})
+	require.Equal(t, &statuscache.PR{
+		Number: 12,
+		State:  "merged",
+		URL:    "https://github.com/kalbasit/swm/pull/12",
+		Checks: "failure",
+	}, got)
+
+	require.Empty(t, statuscache.PRFromProto(&pluginv1.PullRequest{Number: 1}).Checks)
+}
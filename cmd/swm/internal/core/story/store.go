@@ -26,6 +26,12 @@ type Store interface {
 	Update(ctx context.Context, s *Story) error
 }
 
+// DataDir returns the directory holding per-story state (such as workspace
+// snapshots) for the story name, alongside the story JSON files in storiesDir.
+func DataDir(storiesDir, name string) string {
+	return filepath.Join(storiesDir, name)
+}
+
 // JSONStore implements Store using JSON files in a directory.
 type JSONStore struct {
 	dir string
@@ -77,7 +83,8 @@ func (s *JSONStore) Delete(_ context.Context, name string) error {
 		return fmt.Errorf("deleting story file: %w", err)
 	}
 
-	_ = os.Remove(p + ".lock") //nolint:errcheck // best-effort lock file cleanup
+	_ = os.Remove(p + ".lock")             //nolint:errcheck // best-effort lock file cleanup
+	_ = os.RemoveAll(DataDir(s.dir, name)) //nolint:errcheck // best-effort per-story state cleanup
 
 	return nil
 }
@@ -147,6 +147,29 @@ func TestDelete_RemovesFile(t *testing.T) {
 	require.ErrorIs(t, err, story.ErrStoryNotFound)
 }
 
+func TestDelete_RemovesDataDir(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "stories")
+	store := story.NewJSONStore(dir)
+	_, err := store.Create(context.Background(), "feat-x", "feat/feat-x")
+	require.NoError(t, err)
+
+	dataDir := story.DataDir(dir, "feat-x")
+	require.NoError(t, os.MkdirAll(dataDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "state.json"), []byte("{}"), 0o600))
+
+	// The data dir must not be mistaken for a story by List.
+	list, err := store.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	require.NoError(t, store.Delete(context.Background(), "feat-x"))
+
+	_, err = os.Stat(dataDir)
+	require.True(t, os.IsNotExist(err))
+}
+
 func TestUpdate_AttachesProject(t *testing.T) {
 	t.Parallel()
 
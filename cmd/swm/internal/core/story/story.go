@@ -3,6 +3,8 @@ package story
 
 import (
 	"errors"
+	"maps"
+	"slices"
 	"time"
 )
 
@@ -21,12 +23,67 @@ type Project struct {
 	AttachedAt time.Time `json:"attached_at"`
 }
 
+// PullRequest records the forge pull request a review story was opened from.
+type PullRequest struct {
+	Host     string   `json:"host"`
+	Segments []string `json:"segments"`
+	Number   int64    `json:"number"`
+	URL      string   `json:"url,omitempty"`
+}
+
+// MetadataIssue is the Metadata key holding the tracker issue a story was
+// created from: an object with "key", "title" and "url".
+const MetadataIssue = "issue"
+
 // Story is the domain object representing a unit of work.
 type Story struct {
-	Name       string         `json:"name"`
-	BranchName string         `json:"branch_name"`
-	CreatedAt  time.Time      `json:"created_at"`
-	VCS        string         `json:"vcs,omitempty"`
-	Projects   []Project      `json:"projects"`
-	Metadata   map[string]any `json:"metadata"`
+	Name        string         `json:"name"`
+	BranchName  string         `json:"branch_name"`
+	CreatedAt   time.Time      `json:"created_at"`
+	VCS         string         `json:"vcs,omitempty"`
+	Projects    []Project      `json:"projects"`
+	Metadata    map[string]any `json:"metadata"`
+	PullRequest *PullRequest   `json:"pull_request,omitempty"`
+	// Ephemeral stories are removed by "swm story prune" once PullRequest is
+	// closed or merged.
+	Ephemeral bool `json:"ephemeral,omitempty"`
+	// Env holds the environment variables set in the story's workspace, hooks
+	// and commands. ProjectEnv overrides them per project key
+	// (host/seg1/.../segN).
+	Env        map[string]string            `json:"env,omitempty"`
+	ProjectEnv map[string]map[string]string `json:"project_env,omitempty"`
+	// PortBase is the first port of the block allocated to the story, or 0
+	// when it has none. The block is freed with the story.
+	PortBase int `json:"port_base,omitempty"`
+}
+
+// Environment returns the story's environment for the project key, Env
+// overridden by ProjectEnv[key]. An empty key returns Env alone. histfile,
+// when not empty, is set as HISTFILE unless the story sets it explicitly.
+func (s *Story) Environment(key, histfile string) map[string]string {
+	env := make(map[string]string, len(s.Env)+1)
+
+	if histfile != "" {
+		env["HISTFILE"] = histfile
+	}
+
+	maps.Copy(env, s.Env)
+
+	if key != "" {
+		maps.Copy(env, s.ProjectEnv[key])
+	}
+
+	return env
+}
+
+// Environ returns env as KEY=VALUE entries sorted by key, ready to be
+// appended to an exec.Cmd environment.
+func Environ(env map[string]string) []string {
+	out := make([]string, 0, len(env))
+
+	for _, k := range slices.Sorted(maps.Keys(env)) {
+		out = append(out, k+"="+env[k])
+	}
+
+	return out
 }
@@ -0,0 +1,38 @@
+package story_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/core/story"
+)
+
+func TestEnvironment(t *testing.T) {
+	t.Parallel()
+
+	key := testHost + "/" + testOwner + "/" + testProject
+
+	s := &story.Story{
+		Name: "feat-x",
+		Env:  map[string]string{"AWS_PROFILE": "dev", "PORT": "3000"},
+		ProjectEnv: map[string]map[string]string{
+			key: {"PORT": "3001"},
+		},
+	}
+
+	require.Equal(t, map[string]string{"AWS_PROFILE": "dev", "PORT": "3000"}, s.Environment("", ""))
+	require.Equal(t,
+		[]string{"AWS_PROFILE=dev", "HISTFILE=/data/history", "PORT=3001"},
+		story.Environ(s.Environment(key, "/data/history")),
+	)
+
+	// An explicit HISTFILE wins over the per-story history file.
+	s.Env["HISTFILE"] = "/home/me/.history"
+	require.Equal(t, "/home/me/.history", s.Environment("", "/data/history")["HISTFILE"])
+
+	// The result is a copy.
+	s.Environment(key, "")["PORT"] = "1"
+	require.Equal(t, "3001", s.ProjectEnv[key]["PORT"])
+	require.Empty(t, story.Environ((&story.Story{}).Environment("", "")))
+}
@@ -0,0 +1,198 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	daemonv1 "github.com/kalbasit/swm/proto/swm/daemon/v1"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/sdk/go/trace"
+)
+
+// dialTimeout bounds how long the CLI waits for a daemon to answer before it
+// runs the plugins itself.
+const dialTimeout = time.Second
+
+var (
+	// ErrNotRunning is returned by Dial when no daemon answers on the socket.
+	ErrNotRunning = errors.New("swm daemon is not running")
+
+	errMismatch = errors.New("swm daemon serves another swm")
+)
+
+// Client is a connection to a running swm daemon. It implements
+// pluginmgr.Remote so a plugin manager forwards forge, tracker and vcs calls
+// to the daemon, and workspace.ProjectLister from the daemon's scan cache.
+type Client struct {
+	conn     *grpc.ClientConn
+	hostConn *grpc.ClientConn
+	daemon   daemonv1.DaemonClient
+	status   *daemonv1.StatusResponse
+}
+
+// Dial connects to the daemon listening on socketPath. It fails with
+// ErrNotRunning when there is none.
+func Dial(ctx context.Context, socketPath string) (*Client, error) {
+	if _, err := os.Stat(socketPath); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotRunning, err)
+	}
+
+	// Plugin calls forwarded to the daemon are traced here; the daemon passes
+	// the trace context on to the plugin and its spans back.
+	conn, err := grpc.NewClient("unix://"+socketPath,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(trace.UnaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(trace.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("dialing %s: %w", socketPath, err)
+	}
+
+	c := &Client{conn: conn, daemon: daemonv1.NewDaemonClient(conn)}
+
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	c.status, err = c.daemon.Status(ctx, &daemonv1.StatusRequest{})
+	if err != nil {
+		conn.Close() //nolint:errcheck,gosec // best-effort close of an unusable connection
+
+		return nil, fmt.Errorf("%w: %w", ErrNotRunning, err)
+	}
+
+	c.hostConn, err = grpc.NewClient(c.status.GetHostSocket(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		conn.Close() //nolint:errcheck,gosec // best-effort close of an unusable connection
+
+		return nil, fmt.Errorf("dialing host service: %w", err)
+	}
+
+	return c, nil
+}
+
+// Connect dials the daemon like Dial and checks that it runs the given swm
+// version with the config file at cfgPath, so the CLI never forwards to a
+// daemon that would behave differently than running the plugins itself.
+func Connect(ctx context.Context, socketPath, cfgPath, version string) (*Client, error) {
+	c, err := Dial(ctx, socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.status.GetVersion() != version || c.status.GetConfigPath() != cfgPath {
+		c.Close() //nolint:errcheck,gosec // best-effort close of an unusable connection
+
+		return nil, fmt.Errorf("%w: version %s with config %s",
+			errMismatch, c.status.GetVersion(), c.status.GetConfigPath())
+	}
+
+	return c, nil
+}
+
+// Close closes the connection to the daemon. The daemon keeps running.
+func (c *Client) Close() error {
+	return errors.Join(c.hostConn.Close(), c.conn.Close())
+}
+
+// Dial implements pluginmgr.Remote. It reports false for the capabilities the
+// daemon does not run.
+func (c *Client) Dial(ctx context.Context, key string) (grpc.ClientConnInterface, bool, error) {
+	capability, _, _ := strings.Cut(key, ":")
+	if !Forwards(capability) {
+		return nil, false, nil
+	}
+
+	if _, err := c.daemon.Acquire(ctx, &daemonv1.AcquireRequest{Key: key}); err != nil {
+		return nil, false, fmt.Errorf("launching plugin %s in swm daemon: %w", key, err)
+	}
+
+	return pluginConn{cc: c.conn, key: key}, true, nil
+}
+
+// HostSocket returns the dial address of the daemon's Host service.
+func (c *Client) HostSocket() string {
+	return c.status.GetHostSocket()
+}
+
+// IssueCallerToken implements pluginmgr.Remote. The daemon only issues tokens
+// for the capabilities it does not run.
+func (c *Client) IssueCallerToken(ctx context.Context, caller string) (string, error) {
+	resp, err := c.daemon.IssueCallerToken(ctx, &daemonv1.IssueCallerTokenRequest{Caller: caller})
+	if err != nil {
+		return "", fmt.Errorf("asking swm daemon: %w", err)
+	}
+
+	return resp.GetToken(), nil
+}
+
+// Projects lists the repositories under the code root from the daemon's scan
+// cache.
+func (c *Client) Projects(ctx context.Context) ([]*pluginv1.ProjectID, error) {
+	stream, err := pluginv1.NewHostClient(c.hostConn).ListProjects(ctx, &pluginv1.ListProjectsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("listing projects: %w", err)
+	}
+
+	var projects []*pluginv1.ProjectID
+
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return projects, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("listing projects: %w", err)
+		}
+
+		projects = append(projects, &pluginv1.ProjectID{Host: resp.GetHost(), Segments: resp.GetSegments()})
+	}
+}
+
+// Status asks the daemon to describe itself.
+func (c *Client) Status(ctx context.Context) (*daemonv1.StatusResponse, error) {
+	resp, err := c.daemon.Status(ctx, &daemonv1.StatusRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("querying swm daemon: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Stop asks the daemon to shut down.
+func (c *Client) Stop(ctx context.Context) error {
+	if _, err := c.daemon.Stop(ctx, &daemonv1.StopRequest{}); err != nil {
+		return fmt.Errorf("stopping swm daemon: %w", err)
+	}
+
+	return nil
+}
+
+// pluginConn sends calls to the daemon, tagged with the key of the plugin to
+// forward them to.
+type pluginConn struct {
+	cc  grpc.ClientConnInterface
+	key string
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (p pluginConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	return p.cc.Invoke(metadata.AppendToOutgoingContext(ctx, pluginMetadataKey, p.key), method, args, reply, opts...)
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (p pluginConn) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return p.cc.NewStream(metadata.AppendToOutgoingContext(ctx, pluginMetadataKey, p.key), desc, method, opts...)
+}
@@ -0,0 +1,473 @@
+// Package daemon implements swm daemon: a long-lived process that owns the
+// plugin processes, the Host service and its scan cache, so that consecutive
+// swm invocations skip launching plugins and rescanning the code root. The
+// CLI forwards plugin calls to it over a Unix socket when it is running.
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/gofrs/flock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	daemonv1 "github.com/kalbasit/swm/proto/swm/daemon/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	"github.com/kalbasit/swm/cmd/swm/internal/hostsvc"
+	"github.com/kalbasit/swm/cmd/swm/internal/pluginmgr"
+)
+
+const (
+	// pollInterval is how often the daemon checks its config file for
+	// changes and itself for idleness.
+	pollInterval = time.Second
+
+	// scanTTL bounds how stale the Host service's project scan may get when
+	// repositories are created outside of the daemon.
+	scanTTL = time.Minute
+
+	// stopTimeout bounds how long shutdown waits for in-flight calls.
+	stopTimeout = 5 * time.Second
+)
+
+// ErrAlreadyRunning is returned by New when another daemon holds the socket.
+var ErrAlreadyRunning = errors.New("swm daemon is already running")
+
+// SocketPath returns the path of the daemon socket under XDG_RUNTIME_DIR.
+func SocketPath() string {
+	return filepath.Join(xdg.RuntimeDir, "swm", "daemon.sock")
+}
+
+// Server is a running swm daemon.
+type Server struct {
+	daemonv1.UnimplementedDaemonServer
+
+	cfgPath    string
+	version    string
+	socketPath string
+	store      story.Store
+	startedAt  time.Time
+
+	lock    *flock.Flock
+	grpcSrv *grpc.Server
+	lis     net.Listener
+
+	// mu guards the config and everything built from it. Forwarded calls
+	// hold it for reading only while they look up their plugin, so a reload
+	// stops the plugins of calls still streaming.
+	mu       sync.RWMutex
+	cfg      *config.Config
+	cfgStamp fileStamp
+	mgr      *pluginmgr.Manager
+	host     *hostsvc.Server
+
+	active     atomic.Int64
+	lastActive atomic.Int64
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithSocketPath makes the daemon listen on path instead of SocketPath().
+func WithSocketPath(path string) Option {
+	return func(s *Server) { s.socketPath = path }
+}
+
+// New loads the config at cfgPath, starts the Host service and listens on
+// the daemon socket. Call Run to serve. It fails with ErrAlreadyRunning when
+// another daemon owns the socket.
+func New(cfgPath, version string, store story.Store, opts ...Option) (*Server, error) {
+	s := &Server{
+		cfgPath:    cfgPath,
+		version:    version,
+		socketPath: SocketPath(),
+		store:      store,
+		startedAt:  time.Now(),
+		done:       make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.lastActive.Store(s.startedAt.UnixNano())
+
+	if err := os.MkdirAll(filepath.Dir(s.socketPath), 0o700); err != nil {
+		return nil, fmt.Errorf("creating socket dir: %w", err)
+	}
+
+	s.lock = flock.New(s.socketPath + ".lock")
+
+	locked, err := s.lock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("locking %s: %w", s.lock.Path(), err)
+	}
+
+	if !locked {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyRunning, s.socketPath)
+	}
+
+	if err := s.start(); err != nil {
+		s.lock.Unlock() //nolint:errcheck,gosec // best-effort unlock on a failed start
+
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Acquire launches the plugin under the request key unless it is running.
+func (s *Server) Acquire(ctx context.Context, req *daemonv1.AcquireRequest) (*daemonv1.AcquireResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.conn(ctx, req.GetKey()); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return &daemonv1.AcquireResponse{}, nil
+}
+
+// IssueCallerToken issues a caller token to the plugin a CLI launches itself
+// under the request caller, for its calls to the daemon's Host service. The
+// plugins of the capabilities the daemon forwards get theirs when the daemon
+// launches them, so a CLI cannot ask for one of those.
+func (s *Server) IssueCallerToken(
+	_ context.Context, req *daemonv1.IssueCallerTokenRequest,
+) (*daemonv1.IssueCallerTokenResponse, error) {
+	capability, name, _ := strings.Cut(req.GetCaller(), "-")
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "caller %q is not <capability>-<name>", req.GetCaller())
+	}
+
+	if Forwards(capability) {
+		return nil, status.Errorf(codes.PermissionDenied, "the swm daemon launches the %s plugins itself", capability)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return &daemonv1.IssueCallerTokenResponse{Token: s.mgr.IssueCallerToken(req.GetCaller())}, nil
+}
+
+// Run serves until ctx is done, Stop is called, or the daemon served no
+// call for the configured idle timeout. It reloads the config when its file
+// changes.
+func (s *Server) Run(ctx context.Context) error {
+	serveErr := make(chan error, 1)
+
+	go func() { serveErr <- s.grpcSrv.Serve(s.lis) }()
+
+	slog.InfoContext(ctx, "swm daemon started", "socket", s.socketPath, "pid", os.Getpid())
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.shutdown()
+
+			return nil
+		case <-s.done:
+			return nil
+		case err := <-serveErr:
+			s.shutdown()
+
+			if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				return fmt.Errorf("serving %s: %w", s.socketPath, err)
+			}
+
+			return nil
+		case <-ticker.C:
+			s.reload(ctx)
+
+			if s.idle() {
+				slog.InfoContext(ctx, "swm daemon idle, stopping")
+				s.shutdown()
+
+				return nil
+			}
+		}
+	}
+}
+
+// Status describes the daemon and the plugins it runs.
+func (s *Server) Status(_ context.Context, _ *daemonv1.StatusRequest) (*daemonv1.StatusResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var plugins []*daemonv1.Plugin
+
+	for capability, infos := range s.mgr.Plugins() {
+		for _, info := range infos {
+			plugins = append(plugins, &daemonv1.Plugin{
+				Capability: capability,
+				Name:       info.GetName(),
+				Version:    info.GetVersion(),
+			})
+		}
+	}
+
+	slices.SortFunc(plugins, func(a, b *daemonv1.Plugin) int {
+		if c := strings.Compare(a.GetCapability(), b.GetCapability()); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+
+	return &daemonv1.StatusResponse{
+		Pid:          int64(os.Getpid()),
+		Version:      s.version,
+		ConfigPath:   s.cfgPath,
+		HostSocket:   s.host.SocketPath(),
+		StartedAt:    timestamppb.New(s.startedAt),
+		LastActiveAt: timestamppb.New(time.Unix(0, s.lastActive.Load())),
+		IdleTimeout:  durationpb.New(time.Duration(s.cfg.Daemon.IdleTimeout)),
+		Plugins:      plugins,
+	}, nil
+}
+
+// Stop shuts the daemon down once the call returns.
+func (s *Server) Stop(_ context.Context, _ *daemonv1.StopRequest) (*daemonv1.StopResponse, error) {
+	go s.shutdown()
+
+	return &daemonv1.StopResponse{}, nil
+}
+
+// build creates the Host service and plugin manager for cfg.
+func (s *Server) build(cfg *config.Config) (*hostsvc.Server, *pluginmgr.Manager, error) {
+	resolver := layout.NewResolver(cfg.CodeRoot, cfg.DefaultStory)
+
+	host, err := hostsvc.NewServer(cfg, resolver, s.store, hostsvc.WithScanTTL(scanTTL))
+	if err != nil {
+		return nil, nil, fmt.Errorf("starting host service: %w", err)
+	}
+
+	mgr := pluginmgr.New(cfg, host.SocketPath(), pluginmgr.WithHostVersion(s.version))
+	host.SetPlugins(mgr)
+
+	return host, mgr, nil
+}
+
+// conn returns the connection to the plugin under key, launching it. Must be
+// called with s.mu held. The plugin outlives the call that launched it, so
+// ctx's cancellation is not passed on.
+func (s *Server) conn(ctx context.Context, key string) (*grpc.ClientConn, error) {
+	capability, _, _ := strings.Cut(key, ":")
+	if !Forwards(capability) {
+		return nil, fmt.Errorf("%w: %s", errNotForwarded, capability)
+	}
+
+	return s.mgr.Conn(context.WithoutCancel(ctx), key)
+}
+
+// idle reports whether the daemon served no call for its idle timeout.
+func (s *Server) idle() bool {
+	s.mu.RLock()
+	timeout := time.Duration(s.cfg.Daemon.IdleTimeout)
+	s.mu.RUnlock()
+
+	if timeout <= 0 || s.active.Load() > 0 {
+		return false
+	}
+
+	return time.Since(time.Unix(0, s.lastActive.Load())) > timeout
+}
+
+// reload rebuilds the Host service and plugin manager when the config file
+// changed. Running plugins are stopped; the next call launches them anew. A
+// config that fails to load is logged and the current one kept.
+func (s *Server) reload(ctx context.Context) {
+	stamp := statFile(s.cfgPath)
+
+	s.mu.RLock()
+	unchanged := stamp == s.cfgStamp
+	s.mu.RUnlock()
+
+	if unchanged {
+		return
+	}
+
+	cfg, err := loadConfig(s.cfgPath)
+	if err != nil {
+		slog.WarnContext(ctx, "keeping current config", "err", err)
+
+		s.mu.Lock()
+		s.cfgStamp = stamp
+		s.mu.Unlock()
+
+		return
+	}
+
+	host, mgr, err := s.build(cfg)
+	if err != nil {
+		slog.WarnContext(ctx, "keeping current config", "err", err)
+
+		return
+	}
+
+	s.mu.Lock()
+	oldHost, oldMgr := s.host, s.mgr
+	s.cfg, s.cfgStamp, s.host, s.mgr = cfg, stamp, host, mgr
+	s.mu.Unlock()
+
+	oldMgr.Close() //nolint:errcheck,gosec // the old plugins are no longer reachable
+	oldHost.Stop()
+
+	slog.InfoContext(ctx, "swm daemon reloaded config", "path", s.cfgPath)
+}
+
+// shutdown stops serving, the plugins and the Host service, and releases the
+// socket. It is safe to call more than once.
+func (s *Server) shutdown() {
+	s.stopOnce.Do(func() {
+		stopped := make(chan struct{})
+
+		go func() {
+			s.grpcSrv.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(stopTimeout):
+			s.grpcSrv.Stop()
+		}
+
+		s.mu.Lock()
+		s.mgr.Close() //nolint:errcheck,gosec // best-effort close on shutdown
+		s.host.Stop()
+		s.mu.Unlock()
+
+		os.Remove(s.socketPath) //nolint:errcheck,gosec // best-effort cleanup; the socket may already be gone
+		s.lock.Unlock()         //nolint:errcheck,gosec // best-effort unlock on shutdown
+
+		close(s.done)
+	})
+}
+
+// start loads the config, builds the Host service and plugin manager, and
+// listens on the socket. Must be called with the lock held.
+func (s *Server) start() error {
+	s.cfgStamp = statFile(s.cfgPath)
+
+	cfg, err := loadConfig(s.cfgPath)
+	if err != nil {
+		return err
+	}
+
+	host, mgr, err := s.build(cfg)
+	if err != nil {
+		return err
+	}
+
+	s.cfg, s.host, s.mgr = cfg, host, mgr
+
+	// A socket left behind by a daemon that did not shut down cleanly is
+	// stale: its owner no longer holds the lock.
+	if err := os.Remove(s.socketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		s.closeBuilt()
+
+		return fmt.Errorf("removing stale socket: %w", err)
+	}
+
+	s.lis, err = net.Listen("unix", s.socketPath)
+	if err != nil {
+		s.closeBuilt()
+
+		return fmt.Errorf("listening on %s: %w", s.socketPath, err)
+	}
+
+	s.grpcSrv = grpc.NewServer(
+		grpc.ForceServerCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(s.forward),
+		grpc.UnaryInterceptor(func(
+			ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+		) (any, error) {
+			defer s.track()()
+
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(
+			srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler,
+		) error {
+			defer s.track()()
+
+			return handler(srv, ss)
+		}),
+	)
+
+	daemonv1.RegisterDaemonServer(s.grpcSrv, s)
+
+	return nil
+}
+
+// closeBuilt stops the Host service and plugin manager after a failed start.
+func (s *Server) closeBuilt() {
+	s.mgr.Close() //nolint:errcheck,gosec // nothing was launched yet
+	s.host.Stop()
+}
+
+// track marks a call as in flight; the returned func marks it done.
+func (s *Server) track() func() {
+	s.active.Add(1)
+
+	return func() {
+		s.lastActive.Store(time.Now().UnixNano())
+		s.active.Add(-1)
+	}
+}
+
+// fileStamp identifies a version of a file by its modification time and size.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// statFile returns the stamp of path, or the zero stamp when it is missing.
+func statFile(path string) fileStamp {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+
+	return fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+}
+
+// loadConfig loads the config at path, falling back to the defaults when the
+// file does not exist, as the CLI does.
+func loadConfig(path string) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if errors.Is(err, config.ErrConfigNotFound) {
+		return config.Defaults(), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,241 @@
+package daemon_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	"github.com/kalbasit/swm/cmd/swm/internal/daemon"
+	"github.com/kalbasit/swm/sdk/go/hostcall"
+)
+
+const testVersion = "v2.0.0-test"
+
+// startDaemon writes config to a temp file and runs a daemon on it. It
+// returns the config path, the socket path and Run's result.
+func startDaemon(t *testing.T, config string) (string, string, <-chan error) {
+	t.Helper()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.toml")
+	socketPath := filepath.Join(dir, "daemon.sock")
+
+	require.NoError(t, os.WriteFile(cfgPath, []byte(config), 0o600))
+
+	srv, err := daemon.New(cfgPath, testVersion, story.NewJSONStore(t.TempDir()), daemon.WithSocketPath(socketPath))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	exited := make(chan struct{})
+
+	go func() {
+		done <- srv.Run(ctx)
+
+		close(exited)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-exited
+	})
+
+	return cfgPath, socketPath, done
+}
+
+func dial(t *testing.T, socketPath string) *daemon.Client {
+	t.Helper()
+
+	client, err := daemon.Dial(t.Context(), socketPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	return client
+}
+
+func TestServer_StatusAndStop(t *testing.T) {
+	t.Parallel()
+
+	cfgPath, socketPath, done := startDaemon(t, "")
+	client := dial(t, socketPath)
+
+	st, err := client.Status(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, int64(os.Getpid()), st.GetPid())
+	require.Equal(t, testVersion, st.GetVersion())
+	require.Equal(t, cfgPath, st.GetConfigPath())
+	require.Equal(t, 30*time.Minute, st.GetIdleTimeout().AsDuration())
+	require.Empty(t, st.GetPlugins())
+	require.NotEmpty(t, client.HostSocket())
+
+	require.NoError(t, client.Stop(t.Context()))
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "daemon did not stop")
+	}
+
+	require.NoFileExists(t, socketPath)
+
+	_, err = daemon.Dial(t.Context(), socketPath)
+	require.ErrorIs(t, err, daemon.ErrNotRunning)
+}
+
+func TestNew_AlreadyRunning(t *testing.T) {
+	t.Parallel()
+
+	cfgPath, socketPath, _ := startDaemon(t, "")
+
+	_, err := daemon.New(cfgPath, testVersion, story.NewJSONStore(t.TempDir()), daemon.WithSocketPath(socketPath))
+	require.ErrorIs(t, err, daemon.ErrAlreadyRunning)
+
+	// The running daemon keeps its socket.
+	dial(t, socketPath)
+}
+
+func TestServer_StopsWhenIdle(t *testing.T) {
+	t.Parallel()
+
+	_, socketPath, done := startDaemon(t, "[daemon]\nidle_timeout = \"100ms\"\n")
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "daemon did not stop when idle")
+	}
+
+	require.NoFileExists(t, socketPath)
+}
+
+func TestServer_ReloadsConfig(t *testing.T) {
+	t.Parallel()
+
+	cfgPath, socketPath, _ := startDaemon(t, "[daemon]\nidle_timeout = \"1h\"\n")
+	client := dial(t, socketPath)
+
+	require.NoError(t, os.WriteFile(cfgPath, []byte("[daemon]\nidle_timeout = \"90m\"\n"), 0o600))
+
+	require.Eventually(t, func() bool {
+		st, err := client.Status(t.Context())
+
+		return err == nil && st.GetIdleTimeout().AsDuration() == 90*time.Minute
+	}, 10*time.Second, 50*time.Millisecond)
+}
+
+func TestConnect_ChecksVersionAndConfig(t *testing.T) {
+	t.Parallel()
+
+	cfgPath, socketPath, _ := startDaemon(t, "")
+
+	_, err := daemon.Connect(t.Context(), socketPath, cfgPath, "v1.0.0")
+	require.Error(t, err)
+
+	_, err = daemon.Connect(t.Context(), socketPath, "/elsewhere/config.toml", testVersion)
+	require.Error(t, err)
+
+	client, err := daemon.Connect(t.Context(), socketPath, cfgPath, testVersion)
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+}
+
+func TestClient_Dial(t *testing.T) {
+	t.Parallel()
+
+	_, socketPath, _ := startDaemon(t, "")
+	client := dial(t, socketPath)
+
+	// Session and picker plugins stay in the CLI process.
+	for _, key := range []string{"session:tmux", "picker:fzf"} {
+		conn, ok, err := client.Dial(t.Context(), key)
+		require.NoError(t, err)
+		require.False(t, ok, key)
+		require.Nil(t, conn)
+	}
+
+	// The daemon fails to launch a plugin that is not configured.
+	_, _, err := client.Dial(t.Context(), "vcs:hg")
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestClient_IssueCallerToken(t *testing.T) {
+	t.Parallel()
+
+	_, socketPath, _ := startDaemon(t, "")
+	client := dial(t, socketPath)
+
+	conn, err := grpc.NewClient(client.HostSocket(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	call := func(token string) error {
+		ctx := metadata.AppendToOutgoingContext(t.Context(), hostcall.CallerTokenKey, token)
+		_, err := pluginv1.NewHostClient(conn).CallCapability(ctx, &pluginv1.CallCapabilityRequest{
+			Capability: pluginv1.CapabilityType_CAPABILITY_TYPE_VCS,
+			Method:     "GetUserIdentity",
+		})
+
+		return err
+	}
+
+	// The daemon's Host service knows the plugin a token was issued to.
+	token, err := client.IssueCallerToken(t.Context(), "session-tmux")
+	require.NoError(t, err)
+	require.ErrorContains(t, call(token), "session-tmux")
+
+	other, err := client.IssueCallerToken(t.Context(), "session-tmux")
+	require.NoError(t, err)
+	require.NotEqual(t, token, other)
+
+	err = call("session-tmux")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.ErrorContains(t, err, "caller token")
+
+	// The plugins the daemon launches get their tokens from it directly.
+	_, err = client.IssueCallerToken(t.Context(), "forge-github")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.IssueCallerToken(t.Context(), "session")
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDial_NotRunning(t *testing.T) {
+	t.Parallel()
+
+	_, err := daemon.Dial(t.Context(), filepath.Join(t.TempDir(), "daemon.sock"))
+	require.ErrorIs(t, err, daemon.ErrNotRunning)
+
+	// A socket nobody listens on is stale.
+	stale := filepath.Join(t.TempDir(), "daemon.sock")
+	require.NoError(t, os.WriteFile(stale, nil, 0o600))
+
+	_, err = daemon.Dial(t.Context(), stale)
+	require.ErrorIs(t, err, daemon.ErrNotRunning)
+}
+
+func TestForwards(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []string{"vcs", "forge", "tracker"} {
+		require.True(t, daemon.Forwards(c), c)
+	}
+
+	for _, c := range []string{"session", "picker"} {
+		require.False(t, daemon.Forwards(c), c)
+	}
+}
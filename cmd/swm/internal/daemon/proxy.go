@@ -0,0 +1,189 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/sdk/go/trace"
+)
+
+// pluginMetadataKey carries the "<capability>:<name>" key of the plugin a
+// forwarded call is meant for.
+const pluginMetadataKey = "swm-plugin"
+
+var (
+	errNotForwarded = errors.New("plugin capability is not run by the daemon")
+	errNotProto     = errors.New("not a protobuf message")
+)
+
+// Forwards reports whether the daemon runs plugins of capability. Session and
+// picker plugins act on the caller's terminal and environment, so they always
+// run in the CLI process.
+func Forwards(capability string) bool {
+	switch capability {
+	case "forge", "tracker", "vcs":
+		return true
+	default:
+		return false
+	}
+}
+
+// forward relays a call to a plugin service, unknown to the daemon's own
+// server, to the plugin named in the call's metadata. Messages pass through
+// undecoded, so every plugin RPC is forwarded without the daemon knowing it.
+// The lock is held only to look the plugin up, so a long stream does not hold
+// off a reload.
+func (s *Server) forward(_ any, in grpc.ServerStream) error {
+	ctx := in.Context()
+
+	method, ok := grpc.MethodFromServerStream(in)
+	if !ok {
+		return status.Error(codes.Internal, "no method in stream")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	keys := md.Get(pluginMetadataKey)
+	if len(keys) != 1 {
+		return status.Errorf(codes.InvalidArgument, "missing %s metadata", pluginMetadataKey)
+	}
+
+	s.mu.RLock()
+	conn, err := s.conn(ctx, keys[0])
+	host := s.host
+	s.mu.RUnlock()
+
+	if err != nil {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Pass the caller's trace context on; the plugin's spans come back in the
+	// trailer.
+	if ids := md.Get(trace.MetadataKey); len(ids) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, trace.MetadataKey, ids[0])
+	}
+
+	out, err := conn.NewStream(
+		ctx,
+		&grpc.StreamDesc{ServerStreams: true, ClientStreams: true},
+		method,
+		grpc.ForceCodec(rawCodec{}),
+	)
+	if err != nil {
+		return err
+	}
+
+	pumped := make(chan struct{})
+
+	go func() {
+		defer close(pumped)
+
+		for {
+			f := &frame{}
+			if err := in.RecvMsg(f); err != nil {
+				if errors.Is(err, io.EOF) {
+					out.CloseSend() //nolint:errcheck,gosec // a failure surfaces on RecvMsg
+				} else {
+					cancel()
+				}
+
+				return
+			}
+
+			if err := out.SendMsg(f); err != nil {
+				return
+			}
+		}
+	}()
+
+	// in must not be read once the handler returned. Callers of the forwarded
+	// capabilities send their one request and close, so the pump is done or
+	// about to be.
+	defer func() {
+		cancel()
+		<-pumped
+	}()
+
+	for {
+		f := &frame{}
+		if err := out.RecvMsg(f); err != nil {
+			in.SetTrailer(out.Trailer())
+
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+
+			break
+		}
+
+		if err := in.SendMsg(f); err != nil {
+			return err
+		}
+	}
+
+	// A clone adds a project the cached scan does not know about yet.
+	if method == pluginv1.VCS_Clone_FullMethodName {
+		host.InvalidateProjects()
+	}
+
+	return nil
+}
+
+// frame is a message relayed without being decoded.
+type frame struct {
+	payload []byte
+}
+
+// rawCodec passes frames through untouched and marshals anything else, the
+// daemon's own messages, as protobuf.
+type rawCodec struct{}
+
+// Marshal implements encoding.Codec.
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	if f, ok := v.(*frame); ok {
+		return f.payload, nil
+	}
+
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errNotProto, v)
+	}
+
+	return proto.Marshal(m)
+}
+
+// Name implements encoding.Codec.
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// Unmarshal implements encoding.Codec.
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	if f, ok := v.(*frame); ok {
+		// grpc may reuse data once Unmarshal returns.
+		f.payload = slices.Clone(data)
+
+		return nil
+	}
+
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%w: %T", errNotProto, v)
+	}
+
+	return proto.Unmarshal(data, m)
+}
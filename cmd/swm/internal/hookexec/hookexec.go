@@ -17,6 +17,8 @@ import (
 	"strings"
 
 	"github.com/adrg/xdg"
+
+	"github.com/kalbasit/swm/sdk/go/trace"
 )
 
 // RunConfig holds all the context needed to run hooks for a lifecycle event.
@@ -46,6 +48,10 @@ type RunConfig struct {
 	// processes inherit the working directory of the swm process.
 	WorkDir string
 
+	// Env holds extra KEY=VALUE entries, such as the story's environment,
+	// added to the inherited environment before the SWM_* variables.
+	Env []string
+
 	// ConfigHome overrides the XDG config home used for global and per-story
 	// hook tiers. When empty, xdg.ConfigHome is used. Inject in tests to avoid
 	// relying on the xdg package's cached (init-time) value.
@@ -171,15 +177,18 @@ func findHooks(dir string) ([]string, error) {
 
 // runHook executes a single hook binary with the appropriate env and stdin.
 func runHook(ctx context.Context, hookPath string, cfg RunConfig) error {
+	ctx, span := trace.Start(ctx, "hook "+cfg.Event, "path", hookPath, "story", cfg.StoryName)
+	defer span.End()
+
 	cmd := exec.CommandContext(ctx, hookPath)
 
 	if cfg.WorkDir != "" {
 		cmd.Dir = cfg.WorkDir
 	}
 
-	// Inherit the current environment and add the SWM_* vars.
+	// Inherit the current environment and add the story's and the SWM_* vars.
 	cmd.Env = append(
-		os.Environ(),
+		append(os.Environ(), cfg.Env...),
 		"SWM_HOOK="+cfg.Event,
 		"SWM_STORY="+cfg.StoryName,
 		"SWM_PROJECT_HOST="+cfg.ProjectHost,
@@ -227,6 +227,27 @@ func TestRun_EnvVarsSet(t *testing.T) {
 	require.Contains(t, log, "SWM_PROJECT_PATH=kalbasit/swm")
 }
 
+func TestRun_StoryEnvSet(t *testing.T) {
+	configHome := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "env.log")
+
+	globalDir := filepath.Join(configHome, "swm", "hooks")
+	installScript(t, globalDir, eventPostStory, "00-env",
+		`printf 'AWS_PROFILE=%s\nSWM_STORY=%s\n' "$AWS_PROFILE" "$SWM_STORY" > `+logFile+"\n")
+
+	require.NoError(t, hookexec.Run(context.Background(), hookexec.RunConfig{
+		Event:      eventPostStory,
+		StoryName:  testStoryName,
+		ConfigHome: configHome,
+		// A story variable cannot shadow the SWM_* variables.
+		Env: []string{"AWS_PROFILE=dev", "SWM_STORY=other"},
+	}))
+
+	logBytes, err := os.ReadFile(logFile) //nolint:gosec // G304: test-controlled path
+	require.NoError(t, err)
+	require.Equal(t, "AWS_PROFILE=dev\nSWM_STORY=feat-x\n", string(logBytes))
+}
+
 func TestRun_StdinJSON(t *testing.T) {
 	configHome := t.TempDir()
 
@@ -0,0 +1,184 @@
+package hostsvc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/pluginmgr"
+	"github.com/kalbasit/swm/sdk/go/hostcall"
+)
+
+// Plugins runs the plugins CallCapability calls, as a pluginmgr.Manager does.
+type Plugins interface {
+	// Caller returns the plugin, "<capability>-<name>", a caller token was
+	// issued to, or false for a token that was not issued.
+	Caller(token string) (string, bool)
+	// CapabilityConn returns a connection to the plugin providing capability,
+	// e.g. "vcs"; for forge, the one handling hostname.
+	CapabilityConn(ctx context.Context, capability, hostname string) (grpc.ClientConnInterface, error)
+	// Declared returns the capabilities the plugin caller,
+	// "<capability>-<name>", requires or optionally uses.
+	Declared(ctx context.Context, caller string) ([]string, error)
+}
+
+// callerToken returns the caller token of the call ctx carries, from its
+// hostcall.CallerTokenKey metadata.
+func callerToken(ctx context.Context) string {
+	values := metadata.ValueFromIncomingContext(ctx, hostcall.CallerTokenKey)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[len(values)-1]
+}
+
+// lookupMethod returns the named method of the gRPC service of capability t.
+func lookupMethod(t pluginv1.CapabilityType, name string) (protoreflect.MethodDescriptor, error) {
+	var service string
+
+	switch t {
+	case pluginv1.CapabilityType_CAPABILITY_TYPE_FORGE:
+		service = pluginv1.Forge_ServiceDesc.ServiceName
+	case pluginv1.CapabilityType_CAPABILITY_TYPE_PICKER:
+		service = pluginv1.Picker_ServiceDesc.ServiceName
+	case pluginv1.CapabilityType_CAPABILITY_TYPE_SESSION:
+		service = pluginv1.Session_ServiceDesc.ServiceName
+	case pluginv1.CapabilityType_CAPABILITY_TYPE_TRACKER:
+		service = pluginv1.Tracker_ServiceDesc.ServiceName
+	case pluginv1.CapabilityType_CAPABILITY_TYPE_VCS:
+		service = pluginv1.VCS_ServiceDesc.ServiceName
+	case pluginv1.CapabilityType_CAPABILITY_TYPE_UNSPECIFIED, pluginv1.CapabilityType_CAPABILITY_TYPE_HOOK:
+		return nil, status.Errorf(codes.InvalidArgument,
+			"capability %s has no plugin service", pluginmgr.CapabilityName(t))
+	}
+
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"capability %s has no plugin service", pluginmgr.CapabilityName(t))
+	}
+
+	sd, _ := desc.(protoreflect.ServiceDescriptor)
+
+	method := sd.Methods().ByName(protoreflect.Name(name))
+	if method == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s has no method %q", service, name)
+	}
+
+	if method.IsStreamingClient() {
+		return nil, status.Errorf(codes.Unimplemented,
+			"%s.%s streams requests, which the host cannot relay", service, name)
+	}
+
+	return method, nil
+}
+
+// newMessage returns an empty message of type desc.
+func newMessage(desc protoreflect.MessageDescriptor) (proto.Message, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "message type %s: %v", desc.FullName(), err)
+	}
+
+	return mt.New().Interface(), nil
+}
+
+// projectHost returns the host of the project_id of the request req, or ""
+// when it has none. It routes forge calls.
+func projectHost(req proto.Message) string {
+	field := req.ProtoReflect().Descriptor().Fields().ByName("project_id")
+	if field == nil || field.Message() == nil {
+		return ""
+	}
+
+	id, _ := req.ProtoReflect().Get(field).Message().Interface().(*pluginv1.ProjectID)
+
+	return id.GetHost()
+}
+
+// invoke calls method over conn with the request in and returns the JSON of
+// its response, or of the array of its responses for a server stream.
+func invoke(
+	ctx context.Context, conn grpc.ClientConnInterface, method protoreflect.MethodDescriptor, in proto.Message,
+) (string, error) {
+	fullMethod := fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())
+
+	if !method.IsStreamingServer() {
+		out, err := newMessage(method.Output())
+		if err != nil {
+			return "", err
+		}
+
+		if err := conn.Invoke(ctx, fullMethod, in, out); err != nil {
+			return "", err
+		}
+
+		return marshal(out)
+	}
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, fullMethod)
+	if err != nil {
+		return "", err
+	}
+
+	if err := stream.SendMsg(in); err != nil {
+		return "", err
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return "", err
+	}
+
+	results := []json.RawMessage{}
+
+	for {
+		out, err := newMessage(method.Output())
+		if err != nil {
+			return "", err
+		}
+
+		if err := stream.RecvMsg(out); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return "", err
+		}
+
+		data, err := marshal(out)
+		if err != nil {
+			return "", err
+		}
+
+		results = append(results, json.RawMessage(data))
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "encoding %s responses: %v", fullMethod, err)
+	}
+
+	return string(data), nil
+}
+
+// marshal returns the JSON of the response msg.
+func marshal(msg proto.Message) (string, error) {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "encoding %T: %v", msg, err)
+	}
+
+	return string(data), nil
+}
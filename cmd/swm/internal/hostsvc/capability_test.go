@@ -0,0 +1,189 @@
+package hostsvc_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	"github.com/kalbasit/swm/cmd/swm/internal/hostsvc"
+	"github.com/kalbasit/swm/sdk/go/hostcall"
+)
+
+// fakeVCS serves a few calls of the VCS service.
+type fakeVCS struct {
+	pluginv1.UnimplementedVCSServer
+}
+
+func (fakeVCS) GetUserIdentity(
+	_ context.Context, req *pluginv1.UserIdentityRequest,
+) (*pluginv1.UserIdentity, error) {
+	if req.GetRepoPath() == "/missing" {
+		return nil, status.Error(codes.NotFound, "no repository at /missing")
+	}
+
+	return &pluginv1.UserIdentity{Name: "Fake", Email: req.GetRepoPath()}, nil
+}
+
+func (fakeVCS) ListBranches(_ *pluginv1.ListBranchesRequest, stream pluginv1.VCS_ListBranchesServer) error {
+	for _, name := range []string{"main", "feat"} {
+		if err := stream.Send(&pluginv1.Branch{Name: name}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fakePlugins routes every capability to one connection.
+type fakePlugins struct {
+	conn     grpc.ClientConnInterface
+	tokens   map[string]string
+	declared map[string][]string
+
+	mu        sync.Mutex
+	hostnames []string
+}
+
+func (p *fakePlugins) Caller(token string) (string, bool) {
+	caller, ok := p.tokens[token]
+
+	return caller, ok
+}
+
+func (p *fakePlugins) CapabilityConn(_ context.Context, _, hostname string) (grpc.ClientConnInterface, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.hostnames = append(p.hostnames, hostname)
+
+	return p.conn, nil
+}
+
+func (p *fakePlugins) Declared(_ context.Context, caller string) ([]string, error) {
+	return p.declared[caller], nil
+}
+
+// dialFakeVCS returns a connection to a fakeVCS.
+func dialFakeVCS(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+
+	lis, err := net.Listen("unix", filepath.Join(t.TempDir(), "plugin.sock"))
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	pluginv1.RegisterVCSServer(srv, fakeVCS{})
+
+	go srv.Serve(lis) //nolint:errcheck // stopped below
+
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("unix://"+lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	return conn
+}
+
+func TestCallCapability(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{CodeRoot: t.TempDir(), DefaultStory: "_default"}
+
+	resolver := layout.NewResolver(cfg.CodeRoot, cfg.DefaultStory)
+
+	srv, err := hostsvc.NewServer(cfg, resolver, story.NewJSONStore(t.TempDir()))
+	require.NoError(t, err)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(srv.SocketPath(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	host := pluginv1.NewHostClient(conn)
+
+	call := func(ctx context.Context, capability pluginv1.CapabilityType, method, args string) (string, error) {
+		resp, err := host.CallCapability(ctx, &pluginv1.CallCapabilityRequest{
+			Capability: capability,
+			Method:     method,
+			ArgsJson:   args,
+		})
+
+		return resp.GetResultJson(), err
+	}
+
+	vcs := pluginv1.CapabilityType_CAPABILITY_TYPE_VCS
+	session := metadata.AppendToOutgoingContext(t.Context(), hostcall.CallerTokenKey, "session-token")
+
+	// A host without plugins has nothing to call.
+	_, err = call(session, vcs, "GetUserIdentity", "")
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	plugins := &fakePlugins{
+		conn:     dialFakeVCS(t),
+		tokens:   map[string]string{"session-token": "session-tmux", "picker-token": "picker-fzf"},
+		declared: map[string][]string{"session-tmux": {"vcs", "forge"}},
+	}
+	srv.SetPlugins(plugins)
+
+	result, err := call(session, vcs, "GetUserIdentity", `{"repoPath":"/repo"}`)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"Fake","email":"/repo"}`, result)
+
+	// A server stream's responses become an array.
+	result, err = call(session, vcs, "ListBranches", `{}`)
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"name":"main"},{"name":"feat"}]`, result)
+
+	// The plugin's errors keep their status.
+	_, err = call(session, vcs, "GetUserIdentity", `{"repo_path":"/missing"}`)
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = call(session, vcs, "Frobnicate", "")
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = call(session, vcs, "GetUserIdentity", `{"repoPath":`)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Forge calls are routed by the host of their project.
+	_, err = call(session, pluginv1.CapabilityType_CAPABILITY_TYPE_FORGE, "GetPullRequest",
+		`{"projectId":{"host":"github.com","segments":["org","repo"]},"number":"1"}`)
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+
+	plugins.mu.Lock()
+	require.Contains(t, plugins.hostnames, "github.com")
+	plugins.mu.Unlock()
+
+	// Plugins may only call the capabilities they declare.
+	_, err = call(session, pluginv1.CapabilityType_CAPABILITY_TYPE_TRACKER, "GetIssue", "")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.ErrorContains(t, err, "plugin session-tmux does not declare tracker")
+
+	_, err = call(metadata.AppendToOutgoingContext(t.Context(), hostcall.CallerTokenKey, "picker-token"),
+		vcs, "GetUserIdentity", "")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.ErrorContains(t, err, "plugin picker-fzf does not declare vcs")
+
+	// Only the tokens the host issued identify a plugin: naming one is not
+	// enough.
+	_, err = call(metadata.AppendToOutgoingContext(t.Context(), hostcall.CallerTokenKey, "session-tmux"),
+		vcs, "GetUserIdentity", "")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.ErrorContains(t, err, "caller token")
+
+	_, err = call(t.Context(), vcs, "GetUserIdentity", "")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -9,19 +9,24 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/adrg/xdg"
 	"github.com/pelletier/go-toml/v2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
 
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
 	"github.com/kalbasit/swm/cmd/swm/internal/config"
 	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
 	"github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	"github.com/kalbasit/swm/cmd/swm/internal/pluginmgr"
+	"github.com/kalbasit/swm/sdk/go/hostcall"
 )
 
 // Server implements pluginv1.HostServer and manages its own gRPC listener.
@@ -36,14 +41,30 @@ type Server struct {
 	socketPath string
 	fsPath     string
 
-	// scan cache — populated once per process lifetime via sync.Once.
-	scanOnce      sync.Once
+	pluginsMu sync.Mutex
+	plugins   Plugins // nil until SetPlugins
+
+	// scan cache — populated on first use and kept for scanTTL, or for the
+	// lifetime of the server when scanTTL is zero.
+	scanMu        sync.Mutex
+	scanTTL       time.Duration
+	scannedAt     time.Time
 	cachedRepos   []*pluginv1.ProjectID
 	cachedScanErr error
 }
 
+// Option configures a Server.
+type Option func(*Server)
+
+// WithScanTTL makes Projects rescan the code root once its cached result is
+// older than ttl. Long-lived servers such as the daemon's use it to pick up
+// repositories created by other processes.
+func WithScanTTL(ttl time.Duration) Option {
+	return func(s *Server) { s.scanTTL = ttl }
+}
+
 // NewServer starts a Host gRPC server on a Unix socket under XDG_RUNTIME_DIR.
-func NewServer(cfg *config.Config, resolver *layout.Resolver, store story.Store) (*Server, error) {
+func NewServer(cfg *config.Config, resolver *layout.Resolver, store story.Store, opts ...Option) (*Server, error) {
 	base := filepath.Join(xdg.RuntimeDir, "swm")
 	if err := os.MkdirAll(base, 0o700); err != nil {
 		return nil, fmt.Errorf("creating socket base dir: %w", err)
@@ -74,6 +95,10 @@ func NewServer(cfg *config.Config, resolver *layout.Resolver, store story.Store)
 		fsPath:     sockDir,
 	}
 
+	for _, opt := range opts {
+		opt(srv)
+	}
+
 	pluginv1.RegisterHostServer(srv.grpcSrv, srv)
 
 	go func() {
@@ -85,6 +110,34 @@ func NewServer(cfg *config.Config, resolver *layout.Resolver, store story.Store)
 	return srv, nil
 }
 
+// CallCapability calls a method of the plugin providing a capability on
+// behalf of the calling plugin, identified by the caller token it was
+// launched with in the hostcall.CallerTokenKey metadata, which must declare
+// the capability in the requires or optional of its Info(). The request and
+// the result are mapped to and from JSON through the method's protobuf types;
+// a server stream's responses become a JSON array. Every call is logged.
+func (s *Server) CallCapability(
+	ctx context.Context, req *pluginv1.CallCapabilityRequest,
+) (*pluginv1.CallCapabilityResponse, error) {
+	start := time.Now()
+
+	caller, result, err := s.callCapability(ctx, req)
+
+	level := slog.LevelInfo
+	if status.Code(err) == codes.PermissionDenied {
+		level = slog.LevelWarn
+	}
+
+	slog.Log(ctx, level, "plugin call", "caller", caller, "capability", pluginmgr.CapabilityName(req.GetCapability()),
+		"method", req.GetMethod(), "duration", time.Since(start), "err", err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &pluginv1.CallCapabilityResponse{ResultJson: result}, nil
+}
+
 // GetCodeRoot returns the configured code root directory.
 func (s *Server) GetCodeRoot(_ context.Context, _ *pluginv1.Empty) (*pluginv1.PathResponse, error) {
 	return &pluginv1.PathResponse{Path: s.cfg.CodeRoot}, nil
@@ -163,18 +216,40 @@ func (s *Server) Log(ctx context.Context, req *pluginv1.LogRequest) (*pluginv1.E
 	return &pluginv1.Empty{}, nil
 }
 
+// InvalidateProjects drops the scan cache so the next Projects call rescans
+// the code root.
+func (s *Server) InvalidateProjects() {
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+
+	s.scannedAt = time.Time{}
+}
+
 // Projects returns all on-disk repositories under the configured code root.
-// The result is scanned once and cached for the lifetime of the server process.
-// Callers such as the workspace open command can use this directly to avoid
-// a second filesystem scan.
+// The result is scanned once and cached for the lifetime of the server process,
+// or for the TTL set with WithScanTTL. Callers such as the workspace open
+// command can use this directly to avoid a second filesystem scan.
 func (s *Server) Projects(ctx context.Context) ([]*pluginv1.ProjectID, error) {
-	s.scanOnce.Do(func() {
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+
+	if s.scannedAt.IsZero() || (s.scanTTL > 0 && time.Since(s.scannedAt) > s.scanTTL) {
 		s.cachedRepos, s.cachedScanErr = s.resolver.ScanRepos(ctx)
-	})
+		s.scannedAt = time.Now()
+	}
 
 	return s.cachedRepos, s.cachedScanErr
 }
 
+// SetPlugins makes CallCapability call the plugins p runs. The Manager
+// running them needs the server's socket first, hence no Option.
+func (s *Server) SetPlugins(p Plugins) {
+	s.pluginsMu.Lock()
+	defer s.pluginsMu.Unlock()
+
+	s.plugins = p
+}
+
 // SocketPath returns the gRPC dial address for this server.
 func (s *Server) SocketPath() string {
 	return s.socketPath
@@ -186,6 +261,64 @@ func (s *Server) Stop() {
 	os.RemoveAll(s.fsPath) //nolint:errcheck,gosec // best-effort cleanup; dir may already be gone
 }
 
+// callCapability checks and makes the call req, returning the calling plugin
+// and the JSON of the call's result.
+func (s *Server) callCapability(
+	ctx context.Context, req *pluginv1.CallCapabilityRequest,
+) (string, string, error) {
+	s.pluginsMu.Lock()
+	plugins := s.plugins
+	s.pluginsMu.Unlock()
+
+	name := pluginmgr.CapabilityName(req.GetCapability())
+
+	if plugins == nil {
+		return "", "", status.Errorf(codes.Unavailable, "no %s plugin to call: this host runs no plugins", name)
+	}
+
+	caller, ok := plugins.Caller(callerToken(ctx))
+	if !ok {
+		return "", "", status.Errorf(codes.PermissionDenied,
+			"call to %s does not carry a caller token this host issued in the %s metadata",
+			name, hostcall.CallerTokenKey)
+	}
+
+	declared, err := plugins.Declared(ctx, caller)
+	if err != nil {
+		return caller, "", status.Errorf(codes.PermissionDenied, "checking plugin %s: %v", caller, err)
+	}
+
+	if !slices.Contains(declared, name) {
+		return caller, "", status.Errorf(codes.PermissionDenied,
+			"plugin %s does not declare %s in the requires or optional of its Info()", caller, name)
+	}
+
+	method, err := lookupMethod(req.GetCapability(), req.GetMethod())
+	if err != nil {
+		return caller, "", err
+	}
+
+	in, err := newMessage(method.Input())
+	if err != nil {
+		return caller, "", err
+	}
+
+	if req.GetArgsJson() != "" {
+		if err := protojson.Unmarshal([]byte(req.GetArgsJson()), in); err != nil {
+			return caller, "", status.Errorf(codes.InvalidArgument, "decoding %s: %v", method.Input().FullName(), err)
+		}
+	}
+
+	conn, err := plugins.CapabilityConn(ctx, name, projectHost(in))
+	if err != nil {
+		return caller, "", status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+
+	result, err := invoke(ctx, conn, method, in)
+
+	return caller, result, err
+}
+
 func storyToProto(s *story.Story) *pluginv1.Story {
 	projects := make([]*pluginv1.Project, len(s.Projects))
 
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/adrg/xdg"
 	"github.com/stretchr/testify/require"
@@ -123,6 +124,38 @@ func TestServer_ProjectsCachesResult(t *testing.T) {
 	require.Equal(t, r1, r2)
 }
 
+func TestServer_InvalidateProjectsRescans(t *testing.T) {
+	t.Parallel()
+
+	codeRoot := t.TempDir()
+	cfg := &config.Config{CodeRoot: codeRoot, DefaultStory: "_default"}
+	store := story.NewJSONStore(t.TempDir())
+	resolver := layout.NewResolver(codeRoot, cfg.DefaultStory)
+
+	srv, err := hostsvc.NewServer(cfg, resolver, store, hostsvc.WithScanTTL(time.Hour))
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.Stop() })
+
+	r1, err := srv.Projects(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, r1)
+
+	require.NoError(t, os.MkdirAll(
+		filepath.Join(codeRoot, "repositories", "github.com", "user", "repo", ".git"), 0o750,
+	))
+
+	// Within the TTL the cached scan is served.
+	r2, err := srv.Projects(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, r2)
+
+	srv.InvalidateProjects()
+
+	r3, err := srv.Projects(context.Background())
+	require.NoError(t, err)
+	require.Len(t, r3, 1)
+}
+
 func TestListProjects_SkipsSubRepositories(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,594 @@
+// Package plugininstall installs, upgrades and removes plugins built from
+// source into $XDG_DATA_HOME/swm/plugins, where plugin discovery finds them.
+package plugininstall
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+const (
+	// ManifestName is the manifest file at the root of a plugin's source.
+	ManifestName = "swm-plugin.toml"
+
+	// recordName is the file, in an installed plugin's directory, recording
+	// where the plugin was installed from.
+	recordName = ".swm-install.json"
+
+	// stagingPrefix prefixes the directories plugins are fetched and built in
+	// before they are moved into place.
+	stagingPrefix = ".staging-"
+)
+
+var (
+	// ErrAlreadyInstalled is returned when installing a plugin that is installed.
+	ErrAlreadyInstalled = errors.New("plugin already installed")
+
+	// ErrNotInstalled is returned for a plugin that is not installed.
+	ErrNotInstalled = errors.New("plugin not installed")
+
+	errInvalidManifest  = errors.New("invalid " + ManifestName)
+	errBinaryNotBuilt   = errors.New("build did not produce the plugin binary")
+	errCapabilityChange = errors.New("upgrade changes the plugin's capability")
+	errNameChange       = errors.New("upgrade changes the plugin's name")
+)
+
+// nameRe matches plugin names.
+var nameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// capabilities lists the capabilities a plugin may provide.
+var capabilities = []string{"forge", "picker", "session", "tracker", "vcs"} //nolint:gochecknoglobals // read-only list
+
+// Manifest is a decoded swm-plugin.toml.
+type Manifest struct {
+	Plugin ManifestPlugin `toml:"plugin"`
+	Build  ManifestBuild  `toml:"build"`
+}
+
+// ManifestPlugin is the [plugin] table of a manifest.
+type ManifestPlugin struct {
+	// Name is the plugin name, with or without its capability prefix
+	// ("zellij" or "session-zellij").
+	Name       string `toml:"name"`
+	Capability string `toml:"capability"`
+	Version    string `toml:"version"`
+}
+
+// ManifestBuild is the [build] table of a manifest.
+type ManifestBuild struct {
+	// Command builds the plugin binary in the plugin root. It defaults to
+	// `go build -o <binary> .`.
+	Command []string `toml:"command"`
+}
+
+// Binary returns the name of the plugin binary.
+func (m *Manifest) Binary() string {
+	return "swm-plugin-" + m.Plugin.Capability + "-" + m.ShortName()
+}
+
+// BuildCommand returns the command building the plugin binary.
+func (m *Manifest) BuildCommand() []string {
+	if len(m.Build.Command) > 0 {
+		return m.Build.Command
+	}
+
+	return []string{"go", "build", "-o", m.Binary(), "."}
+}
+
+// ShortName returns the plugin name without its capability prefix, as used
+// in config.toml (e.g. "zellij" for the session plugin "session-zellij").
+func (m *Manifest) ShortName() string {
+	return strings.TrimPrefix(m.Plugin.Name, m.Plugin.Capability+"-")
+}
+
+// Checksum returns the hex-encoded SHA-256 of the plugin binary at path, as
+// [plugins.checksums] pins it.
+func Checksum(path string) (string, error) {
+	f, err := os.Open(path) //nolint:gosec // path is a discovered plugin binary
+	if err != nil {
+		return "", fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close() //nolint:errcheck // read-only
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// LoadManifest reads and validates the manifest at the root of dir.
+func LoadManifest(dir string) (*Manifest, error) {
+	path := filepath.Join(dir, ManifestName)
+
+	data, err := os.ReadFile(path) //nolint:gosec // path is under a plugin directory
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var m Manifest
+	if err := toml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	if !slices.Contains(capabilities, m.Plugin.Capability) {
+		return nil, fmt.Errorf("%w: capability must be one of %s, got %q",
+			errInvalidManifest, strings.Join(capabilities, ", "), m.Plugin.Capability)
+	}
+
+	if !nameRe.MatchString(m.ShortName()) {
+		return nil, fmt.Errorf("%w: name must contain only lowercase letters, digits and -, got %q",
+			errInvalidManifest, m.Plugin.Name)
+	}
+
+	return &m, nil
+}
+
+// Plugin is an installed plugin.
+type Plugin struct {
+	Manifest
+
+	// Dir is the directory the plugin is installed in.
+	Dir string
+	// Source is the git URL or local path the plugin was installed from.
+	Source string
+	// InstalledAt is when the installed build was made.
+	InstalledAt time.Time
+}
+
+// BinaryPath returns the path of the plugin binary.
+func (p *Plugin) BinaryPath() string {
+	return filepath.Join(p.Dir, p.Binary())
+}
+
+// record is the content of an installed plugin's install record.
+type record struct {
+	Source      string    `json:"source"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// Staged is a plugin fetched into a staging directory, ready to be built.
+type Staged struct {
+	Manifest
+
+	// Dir is the staging directory holding the plugin's source.
+	Dir string
+	// Source is the git URL or absolute local path the plugin was fetched from.
+	Source string
+}
+
+// Cleanup removes the staging directory. It is a no-op after Install.
+func (s *Staged) Cleanup() error {
+	if err := os.RemoveAll(s.Dir); err != nil {
+		return fmt.Errorf("removing %s: %w", s.Dir, err)
+	}
+
+	return nil
+}
+
+// Installer manages the plugins installed under a data directory.
+type Installer struct {
+	pluginsDir string
+	binDir     string
+	stdout     io.Writer
+	stderr     io.Writer
+}
+
+// New returns an Installer keeping plugins in dataHome/swm/plugins and
+// linking their binaries into dataHome/swm/bin. Build output is written to
+// stdout and stderr.
+func New(dataHome string, stdout, stderr io.Writer) *Installer {
+	return &Installer{
+		pluginsDir: filepath.Join(dataHome, "swm", "plugins"),
+		binDir:     filepath.Join(dataHome, "swm", "bin"),
+		stdout:     stdout,
+		stderr:     stderr,
+	}
+}
+
+// Fetch copies the plugin at source into a staging directory and reads its
+// manifest. source is a local directory, which is copied (without its .git),
+// a local bare repository or a git URL, which are cloned. A URL without a
+// scheme, such as github.com/foo/bar, is cloned over https. The caller must
+// Install or Cleanup the result.
+func (i *Installer) Fetch(ctx context.Context, source string) (*Staged, error) {
+	if err := os.MkdirAll(i.pluginsDir, 0o750); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", i.pluginsDir, err)
+	}
+
+	dir, err := os.MkdirTemp(i.pluginsDir, stagingPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("creating staging directory: %w", err)
+	}
+
+	staged := &Staged{Dir: dir, Source: source}
+
+	if err := staged.fetch(ctx, i.stderr); err != nil {
+		_ = staged.Cleanup() //nolint:errcheck // the fetch error is more useful
+
+		return nil, err
+	}
+
+	m, err := LoadManifest(dir)
+	if err != nil {
+		_ = staged.Cleanup() //nolint:errcheck // the manifest error is more useful
+
+		return nil, err
+	}
+
+	staged.Manifest = *m
+
+	return staged, nil
+}
+
+// Get returns the installed plugin named name, with or without its
+// capability prefix.
+func (i *Installer) Get(name string) (*Plugin, error) {
+	plugins, err := i.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range plugins {
+		if name == p.ShortName() || name == p.Plugin.Capability+"-"+p.ShortName() {
+			return p, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNotInstalled, name)
+}
+
+// Install builds staged and moves it into place, linking its binary into the
+// bin directory. With a non-nil replace, staged must be the same plugin and
+// replaces it; otherwise nothing may be installed under the plugin's name.
+// staged's directory is removed whatever the outcome.
+func (i *Installer) Install(ctx context.Context, staged *Staged, replace *Plugin) (*Plugin, error) {
+	defer staged.Cleanup() //nolint:errcheck // best-effort; the directory is gone after a successful move
+
+	target := filepath.Join(i.pluginsDir, staged.ShortName())
+
+	switch {
+	case replace == nil:
+		if _, err := os.Stat(target); err == nil {
+			return nil, fmt.Errorf("%w: %s (use swm plugin upgrade)", ErrAlreadyInstalled, staged.ShortName())
+		}
+	case staged.ShortName() != replace.ShortName():
+		return nil, fmt.Errorf("%w: %s to %s", errNameChange, replace.ShortName(), staged.ShortName())
+	case staged.Plugin.Capability != replace.Plugin.Capability:
+		return nil, fmt.Errorf("%w: %s to %s", errCapabilityChange, replace.Plugin.Capability, staged.Plugin.Capability)
+	}
+
+	if err := i.build(ctx, staged); err != nil {
+		return nil, err
+	}
+
+	p := &Plugin{
+		Manifest:    staged.Manifest,
+		Dir:         target,
+		Source:      staged.Source,
+		InstalledAt: time.Now().UTC(),
+	}
+
+	if err := writeRecord(staged.Dir, p); err != nil {
+		return nil, err
+	}
+
+	if err := replaceDir(staged.Dir, target); err != nil {
+		return nil, err
+	}
+
+	if err := i.link(p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// List returns the installed plugins sorted by name.
+func (i *Installer) List() ([]*Plugin, error) {
+	entries, err := os.ReadDir(i.pluginsDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("listing %s: %w", i.pluginsDir, err)
+	}
+
+	var plugins []*Plugin
+
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		dir := filepath.Join(i.pluginsDir, e.Name())
+
+		m, err := LoadManifest(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // a plugin directory populated by hand
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		p := &Plugin{Manifest: *m, Dir: dir}
+
+		if rec, err := readRecord(dir); err == nil {
+			p.Source = rec.Source
+			p.InstalledAt = rec.InstalledAt
+		}
+
+		plugins = append(plugins, p)
+	}
+
+	return plugins, nil
+}
+
+// Remove uninstalls p: it deletes its directory and the link to its binary.
+func (i *Installer) Remove(p *Plugin) error {
+	link := filepath.Join(i.binDir, p.Binary())
+	if dest, err := os.Readlink(link); err == nil && dest == p.BinaryPath() {
+		if err := os.Remove(link); err != nil {
+			return fmt.Errorf("removing %s: %w", link, err)
+		}
+	}
+
+	if err := os.RemoveAll(p.Dir); err != nil {
+		return fmt.Errorf("removing %s: %w", p.Dir, err)
+	}
+
+	return nil
+}
+
+// build runs the build command of staged in its directory and checks that it
+// produced the plugin binary.
+func (i *Installer) build(ctx context.Context, staged *Staged) error {
+	argv := staged.BuildCommand()
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...) //nolint:gosec // the user confirmed the build command
+	cmd.Dir = staged.Dir
+	cmd.Stdout = i.stdout
+	cmd.Stderr = i.stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("building plugin %s: %w", staged.ShortName(), err)
+	}
+
+	info, err := os.Stat(filepath.Join(staged.Dir, staged.Binary()))
+	if err != nil || !info.Mode().IsRegular() {
+		return fmt.Errorf("%w: %s", errBinaryNotBuilt, staged.Binary())
+	}
+
+	return nil
+}
+
+// link points the plugin's entry in the bin directory at its binary.
+func (i *Installer) link(p *Plugin) error {
+	if err := os.MkdirAll(i.binDir, 0o750); err != nil {
+		return fmt.Errorf("creating %s: %w", i.binDir, err)
+	}
+
+	link := filepath.Join(i.binDir, p.Binary())
+
+	if err := os.Remove(link); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("replacing %s: %w", link, err)
+	}
+
+	if err := os.Symlink(p.BinaryPath(), link); err != nil {
+		return fmt.Errorf("linking %s: %w", link, err)
+	}
+
+	return nil
+}
+
+// fetch populates the staging directory from the staged source and makes a
+// local source absolute.
+func (s *Staged) fetch(ctx context.Context, stderr io.Writer) error {
+	if info, err := os.Stat(s.Source); err == nil && info.IsDir() {
+		abs, err := filepath.Abs(s.Source)
+		if err != nil {
+			return fmt.Errorf("resolving %s: %w", s.Source, err)
+		}
+
+		s.Source = abs
+
+		if !isBareRepo(abs) {
+			return copyDir(abs, s.Dir)
+		}
+	}
+
+	return gitClone(ctx, cloneURL(s.Source), s.Dir, stderr)
+}
+
+// cloneURL returns the URL git clones source from: source itself when it is
+// a URL, an scp-like address or a local path, https://source otherwise.
+func cloneURL(source string) string {
+	if strings.Contains(source, "://") || strings.Contains(source, "@") || filepath.IsAbs(source) {
+		return source
+	}
+
+	return "https://" + source
+}
+
+// copyDir copies the tree at src into the existing directory dst, skipping
+// .git directories.
+func copyDir(src, dst string) error {
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		if d.Name() == ".git" {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.Mkdir(target, info.Mode().Perm())
+		case info.Mode()&fs.ModeSymlink != 0:
+			dest, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			return os.Symlink(dest, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return nil // sockets, pipes and devices are not part of a plugin's source
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("copying %s: %w", src, err)
+	}
+
+	return nil
+}
+
+// copyFile copies the regular file src to dst with the given permissions.
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src) //nolint:gosec // src is under the user-chosen plugin source
+	if err != nil {
+		return err
+	}
+	defer in.Close() //nolint:errcheck // read-only
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm) //nolint:gosec // dst is under the staging dir
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close() //nolint:errcheck // the copy error is more useful
+
+		return err
+	}
+
+	return out.Close()
+}
+
+// gitClone clones url into the existing empty directory dst.
+func gitClone(ctx context.Context, url, dst string, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, "git", "clone", "--quiet", url, dst)
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("cloning %s: %w", url, err)
+	}
+
+	return nil
+}
+
+// isBareRepo reports whether dir is a bare git repository.
+func isBareRepo(dir string) bool {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// readRecord reads the install record of the plugin installed in dir.
+func readRecord(dir string) (*record, error) {
+	data, err := os.ReadFile(filepath.Join(dir, recordName)) //nolint:gosec // dir is a plugin directory
+	if err != nil {
+		return nil, err
+	}
+
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Join(dir, recordName), err)
+	}
+
+	return &rec, nil
+}
+
+// replaceDir moves src to dst, replacing dst if it exists.
+func replaceDir(src, dst string) error {
+	backup := filepath.Join(filepath.Dir(dst), "."+filepath.Base(dst)+".old")
+
+	if err := os.RemoveAll(backup); err != nil {
+		return fmt.Errorf("removing %s: %w", backup, err)
+	}
+
+	hadOld := true
+	if err := os.Rename(dst, backup); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("moving %s aside: %w", dst, err)
+		}
+
+		hadOld = false
+	}
+
+	if err := os.Rename(src, dst); err != nil {
+		if hadOld {
+			_ = os.Rename(backup, dst) //nolint:errcheck // best-effort restore of the previous install
+		}
+
+		return fmt.Errorf("moving plugin into %s: %w", dst, err)
+	}
+
+	if hadOld {
+		if err := os.RemoveAll(backup); err != nil {
+			return fmt.Errorf("removing %s: %w", backup, err)
+		}
+	}
+
+	return nil
+}
+
+// writeRecord writes the install record of p into dir.
+func writeRecord(dir string, p *Plugin) error {
+	data, err := json.MarshalIndent(record{Source: p.Source, InstalledAt: p.InstalledAt}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding install record: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, recordName), data, 0o600); err != nil {
+		return fmt.Errorf("writing install record: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+package plugininstall_test
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/plugininstall"
+)
+
+const testBinary = "swm-plugin-session-demo"
+
+// writePluginSource writes a plugin source tree whose build command writes a
+// script printing version into the plugin binary.
+func writePluginSource(t *testing.T, dir, version string) {
+	t.Helper()
+
+	manifest := `[plugin]
+name = "session-demo"
+capability = "session"
+version = "` + version + `"
+
+[build]
+command = ["sh", "-c", "printf '#!/bin/sh\\necho ` + version + `\\n' > ` + testBinary +
+		` && chmod +x ` + testBinary + `"]
+`
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/main\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, plugininstall.ManifestName), []byte(manifest), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o600))
+}
+
+// runBinary runs the installed binary and returns its output.
+func runBinary(t *testing.T, path string) string {
+	t.Helper()
+
+	out, err := exec.Command(path).Output() //nolint:gosec // test binary
+	require.NoError(t, err)
+
+	return string(out)
+}
+
+func TestInstall_LocalDirectory(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	writePluginSource(t, src, "0.1.0")
+
+	dataHome := t.TempDir()
+	installer := plugininstall.New(dataHome, io.Discard, io.Discard)
+
+	staged, err := installer.Fetch(t.Context(), src)
+	require.NoError(t, err)
+	require.Equal(t, "demo", staged.ShortName())
+	require.Equal(t, testBinary, staged.Binary())
+	require.Equal(t, src, staged.Source)
+
+	p, err := installer.Install(t.Context(), staged, nil)
+	require.NoError(t, err)
+
+	pluginDir := filepath.Join(dataHome, "swm", "plugins", "demo")
+	require.Equal(t, pluginDir, p.Dir)
+	require.Equal(t, "0.1.0\n", runBinary(t, filepath.Join(pluginDir, testBinary)))
+	require.NoDirExists(t, filepath.Join(pluginDir, ".git"), "a local directory is copied without .git")
+	require.NoDirExists(t, staged.Dir)
+
+	link, err := os.Readlink(filepath.Join(dataHome, "swm", "bin", testBinary))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(pluginDir, testBinary), link)
+
+	plugins, err := installer.List()
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	require.Equal(t, src, plugins[0].Source)
+	require.False(t, plugins[0].InstalledAt.IsZero())
+
+	staged, err = installer.Fetch(t.Context(), src)
+	require.NoError(t, err)
+
+	_, err = installer.Install(t.Context(), staged, nil)
+	require.ErrorIs(t, err, plugininstall.ErrAlreadyInstalled)
+}
+
+func TestInstall_LocalBareRepo(t *testing.T) {
+	t.Parallel()
+
+	work := t.TempDir()
+	writePluginSource(t, work, "0.2.0")
+	require.NoError(t, os.RemoveAll(filepath.Join(work, ".git")))
+
+	bare := filepath.Join(t.TempDir(), "demo.git")
+
+	for _, argv := range [][]string{
+		{"git", "-C", work, "init", "--quiet"},
+		{"git", "-C", work, "add", "."},
+		{"git", "-C", work, "-c", "user.name=t", "-c", "user.email=t@t", "commit", "--quiet", "-m", "init"},
+		{"git", "clone", "--quiet", "--bare", work, bare},
+	} {
+		out, err := exec.Command(argv[0], argv[1:]...).CombinedOutput() //nolint:gosec // test command
+		require.NoError(t, err, string(out))
+	}
+
+	dataHome := t.TempDir()
+	installer := plugininstall.New(dataHome, io.Discard, io.Discard)
+
+	staged, err := installer.Fetch(t.Context(), bare)
+	require.NoError(t, err)
+
+	p, err := installer.Install(t.Context(), staged, nil)
+	require.NoError(t, err)
+	require.Equal(t, "0.2.0\n", runBinary(t, p.BinaryPath()))
+	require.DirExists(t, filepath.Join(p.Dir, ".git"), "a repository is cloned")
+}
+
+func TestInstall_Upgrade(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	writePluginSource(t, src, "0.1.0")
+
+	installer := plugininstall.New(t.TempDir(), io.Discard, io.Discard)
+
+	staged, err := installer.Fetch(t.Context(), src)
+	require.NoError(t, err)
+
+	installed, err := installer.Install(t.Context(), staged, nil)
+	require.NoError(t, err)
+
+	// A failing build leaves the installed plugin untouched.
+	require.NoError(t, os.WriteFile(filepath.Join(src, plugininstall.ManifestName), []byte(`[plugin]
+name = "demo"
+capability = "session"
+
+[build]
+command = ["false"]
+`), 0o600))
+
+	staged, err = installer.Fetch(t.Context(), installed.Source)
+	require.NoError(t, err)
+
+	_, err = installer.Install(t.Context(), staged, installed)
+	require.ErrorContains(t, err, "building plugin demo")
+	require.Equal(t, "0.1.0\n", runBinary(t, installed.BinaryPath()))
+
+	writePluginSource(t, src, "0.2.0")
+
+	staged, err = installer.Fetch(t.Context(), installed.Source)
+	require.NoError(t, err)
+
+	upgraded, err := installer.Install(t.Context(), staged, installed)
+	require.NoError(t, err)
+	require.Equal(t, "0.2.0\n", runBinary(t, upgraded.BinaryPath()))
+
+	plugins, err := installer.List()
+	require.NoError(t, err)
+	require.Len(t, plugins, 1, "no staging or backup directory is left behind")
+	require.Equal(t, "0.2.0", plugins[0].Plugin.Version)
+}
+
+func TestInstall_UpgradeMustKeepNameAndCapability(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	writePluginSource(t, src, "0.1.0")
+
+	installer := plugininstall.New(t.TempDir(), io.Discard, io.Discard)
+
+	staged, err := installer.Fetch(t.Context(), src)
+	require.NoError(t, err)
+
+	installed, err := installer.Install(t.Context(), staged, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(src, plugininstall.ManifestName),
+		[]byte("[plugin]\nname = \"other\"\ncapability = \"session\"\n"), 0o600))
+
+	staged, err = installer.Fetch(t.Context(), src)
+	require.NoError(t, err)
+
+	_, err = installer.Install(t.Context(), staged, installed)
+	require.ErrorContains(t, err, "upgrade changes the plugin's name: demo to other")
+
+	require.NoError(t, os.WriteFile(filepath.Join(src, plugininstall.ManifestName),
+		[]byte("[plugin]\nname = \"demo\"\ncapability = \"vcs\"\n"), 0o600))
+
+	staged, err = installer.Fetch(t.Context(), src)
+	require.NoError(t, err)
+
+	_, err = installer.Install(t.Context(), staged, installed)
+	require.ErrorContains(t, err, "upgrade changes the plugin's capability: session to vcs")
+}
+
+func TestGetAndRemove(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	writePluginSource(t, src, "0.1.0")
+
+	dataHome := t.TempDir()
+	installer := plugininstall.New(dataHome, io.Discard, io.Discard)
+
+	staged, err := installer.Fetch(t.Context(), src)
+	require.NoError(t, err)
+
+	_, err = installer.Install(t.Context(), staged, nil)
+	require.NoError(t, err)
+
+	p, err := installer.Get("session-demo")
+	require.NoError(t, err)
+	require.Equal(t, "demo", p.ShortName())
+
+	require.NoError(t, installer.Remove(p))
+	require.NoDirExists(t, p.Dir)
+	require.NoFileExists(t, filepath.Join(dataHome, "swm", "bin", testBinary))
+
+	_, err = installer.Get("demo")
+	require.ErrorIs(t, err, plugininstall.ErrNotInstalled)
+}
+
+func TestLoadManifest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown capability", "[plugin]\nname = \"x\"\ncapability = \"editor\"\n", "capability must be one of"},
+		{"bad name", "[plugin]\nname = \"My Plugin\"\ncapability = \"vcs\"\n", "name must contain only"},
+		{"empty name", "[plugin]\nname = \"vcs-\"\ncapability = \"vcs\"\n", "name must contain only"},
+		{"bad toml", "[plugin\n", "parsing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, plugininstall.ManifestName), []byte(tt.content), 0o600))
+
+			_, err := plugininstall.LoadManifest(dir)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, plugininstall.ManifestName),
+		[]byte("[plugin]\nname = \"zellij\"\ncapability = \"session\"\n"), 0o600))
+
+	m, err := plugininstall.LoadManifest(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"go", "build", "-o", "swm-plugin-session-zellij", "."}, m.BuildCommand())
+}
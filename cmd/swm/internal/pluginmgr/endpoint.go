@@ -0,0 +1,86 @@
+package pluginmgr
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+)
+
+// endpointConn is the connection to a forge plugin for a code host with an
+// API URL or token path in [plugins.forge_hosts]. It passes them with every
+// request that takes an endpoint and does not name one of its own.
+type endpointConn struct {
+	grpc.ClientConnInterface
+
+	endpoint *pluginv1.ForgeEndpoint
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (c *endpointConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	return c.ClientConnInterface.Invoke(ctx, method, c.withEndpoint(args), reply, opts...)
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (c *endpointConn) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	stream, err := c.ClientConnInterface.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &endpointStream{ClientStream: stream, conn: c}, nil
+}
+
+// withEndpoint returns a copy of req naming c's endpoint, or req itself when
+// it takes no endpoint or names one.
+func (c *endpointConn) withEndpoint(req any) any {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return req
+	}
+
+	field := msg.ProtoReflect().Descriptor().Fields().ByName("endpoint")
+	if field == nil || field.Message() == nil ||
+		field.Message().FullName() != c.endpoint.ProtoReflect().Descriptor().FullName() ||
+		msg.ProtoReflect().Has(field) {
+		return req
+	}
+
+	msg = proto.Clone(msg)
+	msg.ProtoReflect().Set(field, protoreflect.ValueOfMessage(c.endpoint.ProtoReflect()))
+
+	return msg
+}
+
+// endpointStream passes the endpoint of its connection with the requests it
+// sends.
+type endpointStream struct {
+	grpc.ClientStream
+
+	conn *endpointConn
+}
+
+// SendMsg implements grpc.ClientStream.
+func (s *endpointStream) SendMsg(m any) error {
+	return s.ClientStream.SendMsg(s.conn.withEndpoint(m))
+}
+
+// withEndpoint returns conn passing the endpoint host configures, or conn
+// itself when host configures none.
+func withEndpoint(conn grpc.ClientConnInterface, host config.ForgeHost) grpc.ClientConnInterface {
+	if host.APIURL == "" && host.TokenPath == "" {
+		return conn
+	}
+
+	return &endpointConn{
+		ClientConnInterface: conn,
+		endpoint:            &pluginv1.ForgeEndpoint{ApiUrl: host.APIURL, TokenPath: host.TokenPath},
+	}
+}
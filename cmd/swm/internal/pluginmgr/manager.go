@@ -3,6 +3,7 @@ package pluginmgr
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
@@ -11,10 +12,12 @@ import (
 	"os/exec"
 	"path/filepath"
 	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 
 	"github.com/adrg/xdg"
+	"google.golang.org/grpc"
 
 	hclog "github.com/hashicorp/go-hclog"
 	goplugin "github.com/hashicorp/go-plugin"
@@ -22,10 +25,18 @@ import (
 	sdkforge "github.com/kalbasit/swm/sdk/go/forge"
 	sdkpicker "github.com/kalbasit/swm/sdk/go/picker"
 	sdksession "github.com/kalbasit/swm/sdk/go/session"
+	sdktracker "github.com/kalbasit/swm/sdk/go/tracker"
 	sdkvcs "github.com/kalbasit/swm/sdk/go/vcs"
 
+	"github.com/kalbasit/swm/cmd/swm/internal/bundled"
 	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/plugininstall"
+	"github.com/kalbasit/swm/cmd/swm/internal/pluginrec"
+	"github.com/kalbasit/swm/cmd/swm/internal/sandbox"
+	"github.com/kalbasit/swm/cmd/swm/internal/semver"
 	"github.com/kalbasit/swm/sdk/go/handshake"
+	"github.com/kalbasit/swm/sdk/go/hostcall"
+	"github.com/kalbasit/swm/sdk/go/trace"
 )
 
 // VCSClient wraps pluginv1.VCSClient as a named type for type assertions.
@@ -38,44 +49,91 @@ const (
 	capabilityForge   = "forge"
 	capabilityPicker  = "picker"
 	capabilitySession = "session"
+	capabilityTracker = "tracker"
 	capabilityVCS     = "vcs"
 )
 
+// Discovery tiers, in the order Discover searches them.
+const (
+	tierEnv    = "SWM_PLUGIN_PATH"
+	tierConfig = "config"
+	tierXDG    = "xdg"
+	tierPATH   = "PATH"
+	// tierBundled is the plugin built into swm, with no binary.
+	tierBundled = "bundled"
+)
+
 // Sentinel errors for plugin capability configuration.
 var (
-	errInvalidForgePlugin = errors.New("forge plugin did not return a ForgeClient")
-	errNoForgePlugin      = errors.New("no forge plugin configured for hostname")
-	errNoPickerPlugin     = errors.New("no picker plugin configured")
-	errNoSessionPlugin    = errors.New("no session plugin configured")
-	errNoVCSPlugin        = errors.New("no vcs plugin configured")
-	errPluginNotFound     = errors.New("plugin binary not found")
-	errPluginMissingDep   = errors.New("plugin missing required capability")
-	errUnknownCapability  = errors.New("unknown capability")
-	errUnsupported        = errors.New("unsupported capability")
+	errChecksumMismatch    = errors.New("plugin checksum mismatch")
+	errHostTooOld          = errors.New("swm version too old for plugin")
+	errInvalidVCSPlugin    = errors.New("vcs plugin did not return a VCSClient")
+	errNoForgePlugin       = errors.New("no forge plugin configured for hostname")
+	errNoPickerPlugin      = errors.New("no picker plugin configured")
+	errNoSessionPlugin     = errors.New("no session plugin configured")
+	errNoTrackerPlugin     = errors.New("no tracker plugin configured")
+	errNoVCSPlugin         = errors.New("no vcs plugin configured")
+	errNotGRPC             = errors.New("plugin does not speak gRPC")
+	errNotLaunched         = errors.New("plugin is not launched by this process")
+	errPluginNotFound      = errors.New("plugin binary not found")
+	errPluginMissingDep    = errors.New("plugin missing required capability")
+	errPluginNotConfigured = errors.New("plugin not configured")
+	errPluginTooOld        = errors.New("plugin version too old")
+	errUnknownCapability   = errors.New("unknown capability")
+	errUnsupported         = errors.New("unsupported capability")
 )
 
 // launchOnce holds the result of a single plugin launch attempt.
 // once.Do ensures the launch runs exactly once; the result is cached permanently
-// (including errors — a failed launch is not retried).
+// (including errors — a failed launch is not retried). A plugin that launched
+// and later crashes is relaunched by its process.
 // done is set to true after once.Do's function returns; Close checks this instead of
 // calling once.Do(func(){}) (which would steal the Once if launch hasn't started yet).
 type launchOnce struct {
 	once   sync.Once
 	done   atomic.Bool
-	client *goplugin.Client
+	client *process
+	conn   grpc.ClientConnInterface
 	raw    any
 	err    error
 }
 
+// run launches the plugin under key, a launched-map key, unless it was
+// launched already.
+func (lo *launchOnce) run(ctx context.Context, m *Manager, key string) {
+	lo.once.Do(func() {
+		lo.client, lo.conn, lo.err = m.launch(ctx, key)
+		if lo.err == nil {
+			capability, _, _ := strings.Cut(key, ":")
+			lo.raw = typedClient(capability, lo.conn)
+		}
+
+		lo.done.Store(true)
+	})
+}
+
 type forgeEntry struct {
-	client    *goplugin.Client
-	forge     pluginv1.ForgeClient
+	name      string
+	client    *process // nil when the plugin runs in the Remote
+	conn      grpc.ClientConnInterface
 	hostnames []string
 }
 
 // Option configures a Manager.
 type Option func(*Manager)
 
+// Remote runs plugins on behalf of a Manager, as the swm daemon does.
+type Remote interface {
+	// Dial returns a connection to the running plugin under key,
+	// "<capability>:<name>", launching it if needed. It returns false when
+	// the Manager should launch the plugin itself.
+	Dial(ctx context.Context, key string) (grpc.ClientConnInterface, bool, error)
+	// IssueCallerToken returns a token naming caller, "<capability>-<name>",
+	// to the Host service the Manager's plugins call, for a plugin the
+	// Manager launches itself.
+	IssueCallerToken(ctx context.Context, caller string) (string, error)
+}
+
 // WithStderr sets the writer that receives the raw stderr output of plugin processes.
 // The provided writer must be thread-safe as it may be shared by multiple concurrent plugins.
 // Defaults to os.Stderr when not specified.
@@ -85,11 +143,47 @@ func WithStderr(w io.Writer) Option {
 	}
 }
 
+// WithHostVersion sets the swm version checked against the min_host_version
+// plugins declare. The check is skipped when the version is not a semantic
+// version, as in tests.
+func WithHostVersion(version string) Option {
+	return func(m *Manager) {
+		m.hostVersion = version
+	}
+}
+
+// WithRemote makes the Manager use the plugins remote runs instead of
+// launching its own, for the capabilities remote accepts.
+func WithRemote(remote Remote) Option {
+	return func(m *Manager) {
+		m.remote = remote
+	}
+}
+
+// WithRecorder records the calls to plugins with r, as SWM_RECORD does.
+func WithRecorder(r *pluginrec.Recorder) Option {
+	return func(m *Manager) {
+		m.recorder = r
+	}
+}
+
+// WithReplay makes the Manager answer the calls to plugins from the recording
+// r instead of launching them, as SWM_REPLAY does.
+func WithReplay(r *pluginrec.Replay) Option {
+	return func(m *Manager) {
+		m.replay = r
+	}
+}
+
 // Manager discovers, launches, and provides typed access to swm plugins.
 type Manager struct {
-	cfg        *config.Config
-	hostSocket string
-	stderr     io.Writer
+	cfg         *config.Config
+	hostSocket  string
+	hostVersion string
+	stderr      io.Writer
+	remote      Remote
+	recorder    *pluginrec.Recorder
+	replay      *pluginrec.Replay
 
 	// launched stores *launchOnce per capability, enabling per-capability locking
 	// so concurrent Get/Warm calls for different capabilities do not serialize.
@@ -98,6 +192,17 @@ type Manager struct {
 	mu           sync.Mutex // guards forge state only
 	forgeClients []*forgeEntry
 	forgesLoaded bool
+
+	infoMu sync.Mutex // guards infos, callers and tokens
+	// infos holds the Info() of every launched plugin by capability; forge
+	// may have several.
+	infos map[string][]*pluginv1.PluginInfo
+	// callers holds the Info() of the plugins calling CallCapability by full
+	// plugin name, e.g. "session-tmux".
+	callers map[string]*pluginv1.PluginInfo
+	// tokens maps the caller tokens this Manager issued to the plugin they
+	// name, e.g. "session-tmux".
+	tokens map[string]string
 }
 
 // New returns a Manager. Plugins are not launched until Get is called.
@@ -106,6 +211,9 @@ func New(cfg *config.Config, hostSocket string, opts ...Option) *Manager {
 		cfg:        cfg,
 		hostSocket: hostSocket,
 		stderr:     os.Stderr,
+		infos:      make(map[string][]*pluginv1.PluginInfo),
+		callers:    make(map[string]*pluginv1.PluginInfo),
+		tokens:     make(map[string]string),
 	}
 
 	for _, o := range opts {
@@ -115,6 +223,45 @@ func New(cfg *config.Config, hostSocket string, opts ...Option) *Manager {
 	return m
 }
 
+// CapabilityName returns the capability name of t, e.g. "vcs".
+func CapabilityName(t pluginv1.CapabilityType) string {
+	return strings.ToLower(strings.TrimPrefix(t.String(), "CAPABILITY_TYPE_"))
+}
+
+// Caller returns the plugin, "<capability>-<name>", token was issued to by
+// IssueCallerToken. It reports false for a token this Manager did not issue.
+func (m *Manager) Caller(token string) (string, bool) {
+	m.infoMu.Lock()
+	defer m.infoMu.Unlock()
+
+	caller, ok := m.tokens[token]
+
+	return caller, ok
+}
+
+// CapabilityConn returns a connection to the plugin providing capability,
+// launching it like Get: the configured plugin, the default one for vcs, or
+// for forge the one GetForge returns for hostname. Host.CallCapability calls
+// plugins over it.
+func (m *Manager) CapabilityConn(ctx context.Context, capability, hostname string) (grpc.ClientConnInterface, error) {
+	if capability == capabilityForge {
+		return m.forgeConn(ctx, hostname)
+	}
+
+	if _, err := m.Get(ctx, capability); err != nil {
+		return nil, err
+	}
+
+	stored, _ := m.launched.Load(capability)
+
+	lo, ok := stored.(*launchOnce)
+	if !ok || lo.conn == nil {
+		return nil, fmt.Errorf("%w: %s", errNotLaunched, capability)
+	}
+
+	return lo.conn, nil
+}
+
 // Close terminates all launched plugin processes.
 func (m *Manager) Close() error {
 	m.launched.Range(func(k, v any) bool {
@@ -126,8 +273,8 @@ func (m *Manager) Close() error {
 		// Only kill if launch has fully completed; done is set after once.Do's function
 		// returns, ensuring client is visible without a data race.
 		// A launch that is in-progress or never started is skipped — the OS cleans up.
-		if lo.done.Load() && lo.client != nil {
-			lo.client.Kill()
+		if lo.done.Load() {
+			kill(lo.client)
 		}
 
 		m.launched.Delete(k)
@@ -139,16 +286,135 @@ func (m *Manager) Close() error {
 	defer m.mu.Unlock()
 
 	for _, fe := range m.forgeClients {
-		fe.client.Kill()
+		kill(fe.client)
 	}
 
 	m.forgeClients = nil
 	m.forgesLoaded = false
 
+	m.infoMu.Lock()
+	defer m.infoMu.Unlock()
+
+	clear(m.infos)
+	clear(m.callers)
+	clear(m.tokens)
+
 	return nil
 }
 
+// Conn returns the gRPC connection to the plugin under key,
+// "<capability>:<name>", launching it like Get and GetForge do. The swm
+// daemon forwards calls over it.
+func (m *Manager) Conn(ctx context.Context, key string) (*grpc.ClientConn, error) {
+	capability, name, _ := strings.Cut(key, ":")
+
+	proc, err := m.pluginClient(ctx, capability, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return proc.Conn(ctx)
+}
+
+// Declared returns the capabilities the plugin caller, "<capability>-<name>",
+// lists in the requires and optional of its Info(). A plugin this Manager
+// did not launch, such as a session plugin of a CLI calling the daemon's
+// Host service, is inspected once.
+func (m *Manager) Declared(ctx context.Context, caller string) ([]string, error) {
+	m.infoMu.Lock()
+	info, ok := m.callers[caller]
+	m.infoMu.Unlock()
+
+	if !ok {
+		capability, name, _ := strings.Cut(caller, "-")
+
+		if capability == capabilityForge && !slices.Contains(m.cfg.Plugins.Forges, name) {
+			return nil, fmt.Errorf("%w: %s %q", errPluginNotConfigured, capability, name)
+		}
+
+		if capability != capabilityForge {
+			if _, err := m.launchKey(capability, name); err != nil {
+				return nil, err
+			}
+		}
+
+		binary, _, err := m.Discover(capability, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if info, err = m.Inspect(ctx, capability, name, binary); err != nil {
+			return nil, err
+		}
+
+		m.infoMu.Lock()
+		m.callers[caller] = info
+		m.infoMu.Unlock()
+	}
+
+	declared := slices.Clone(info.GetOptional())
+	for _, dep := range info.GetRequires() {
+		declared = append(declared, CapabilityName(dep.GetCapability()))
+	}
+
+	return declared, nil
+}
+
+// Discover finds the binary of the plugin providing capability with the
+// given name, and returns it with the discovery tier it was found in:
+// (0) SWM_PLUGIN_PATH dirs, (1) explicit config path, (2) XDG plugins dir,
+// (3) PATH, (4) the plugin built into swm when plugins.bundled is set, for
+// which the binary is empty. A sandboxed or pinned plugin needs a binary.
+func (m *Manager) Discover(capability, name string) (string, string, error) {
+	binary := "swm-plugin-" + capability + "-" + name
+
+	// 0. SWM_PLUGIN_PATH: platform-specific path list, searched left-to-right.
+	// Non-existent or non-directory entries are silently skipped.
+	for _, dir := range filepath.SplitList(os.Getenv("SWM_PLUGIN_PATH")) {
+		candidate := filepath.Join(dir, binary)
+		if _, err := os.Stat(candidate); err == nil { //nolint:gosec // SWM_PLUGIN_PATH is user-owned
+			return candidate, tierEnv, nil
+		}
+	}
+
+	// 1. Explicit config path.
+	if explicit, ok := m.cfg.Plugins.Paths[name]; ok {
+		if _, err := os.Stat(explicit); err == nil {
+			return explicit, tierConfig, nil
+		}
+	}
+
+	// 2. XDG data dir: $XDG_DATA_HOME/swm/plugins/<name>/<binary>, where
+	// `swm plugin install` puts plugins.
+	xdgPath := filepath.Join(m.dataHome(), "swm", "plugins", name, binary)
+	if _, err := os.Stat(xdgPath); err == nil {
+		return xdgPath, tierXDG, nil
+	}
+
+	// 3. PATH lookup.
+	if path, err := exec.LookPath(binary); err == nil {
+		return path, tierPATH, nil
+	}
+
+	// 4. Built into swm.
+	fullName := capability + "-" + name
+	_, sandboxed := m.cfg.Plugins.Sandbox[fullName]
+	_, pinned := m.cfg.Plugins.Checksums[fullName]
+
+	if bundled.Has(capability, name) && !sandboxed && !pinned {
+		if m.cfg.Plugins.Bundled {
+			return "", tierBundled, nil
+		}
+
+		return "", "", fmt.Errorf("%w: %q not in config paths, %s, or PATH; "+
+			"set plugins.bundled = true to run the one built into swm", errPluginNotFound, binary, xdgPath)
+	}
+
+	return "", "", fmt.Errorf("%w: %q not in config paths, %s, or PATH", errPluginNotFound, binary, xdgPath)
+}
+
 // Get returns the client for the configured plugin of the given capability.
+// For vcs it is the default vcs plugin; see GetVCS for the others.
 // The plugin is lazily launched on the first call and cached for subsequent calls.
 // A failed launch is also cached — the same error is returned on every subsequent call.
 func (m *Manager) Get(ctx context.Context, capability string) (any, error) {
@@ -159,46 +425,231 @@ func (m *Manager) Get(ctx context.Context, capability string) (any, error) {
 		return nil, fmt.Errorf("%w: launched map contains unexpected type %T", errUnknownCapability, stored)
 	}
 
-	entry.once.Do(func() {
-		entry.client, entry.raw, entry.err = m.launch(ctx, capability)
-		entry.done.Store(true)
-	})
+	entry.run(ctx, m, capability)
 
 	return entry.raw, entry.err
 }
 
-// GetForge returns the ForgeClient for the plugin claiming the given hostname.
+// GetForge returns the ForgeClient for the plugin handling the given
+// hostname: the one [plugins.forge_hosts] maps it to, passing the endpoint
+// configured there, else the one claiming it.
 // All configured forge plugins are lazily launched on the first call.
 func (m *Manager) GetForge(ctx context.Context, hostname string) (pluginv1.ForgeClient, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	conn, err := m.forgeConn(ctx, hostname)
+	if err != nil {
+		return nil, err
+	}
 
-	if !m.forgesLoaded {
-		if err := m.loadForges(ctx); err != nil {
-			return nil, err
+	return pluginv1.NewForgeClient(conn), nil
+}
+
+// GetVCS returns the client of the named vcs plugin, launching it on first
+// use like Get. An empty name selects the default vcs plugin, the first one
+// configured.
+func (m *Manager) GetVCS(ctx context.Context, name string) (pluginv1.VCSClient, error) {
+	key, err := m.launchKey(capabilityVCS, name)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := m.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	vcs, ok := raw.(pluginv1.VCSClient)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errInvalidVCSPlugin, raw)
+	}
+
+	return vcs, nil
+}
+
+// HasFeature reports whether a launched plugin of the given capability lists
+// feature in its Info() response. Callers use it to skip RPCs an older plugin
+// does not implement; it is false until the capability has been launched.
+func (m *Manager) HasFeature(capability, feature string) bool {
+	m.infoMu.Lock()
+	defer m.infoMu.Unlock()
+
+	for _, info := range m.infos[capability] {
+		if slices.Contains(info.GetFeatures(), feature) {
+			return true
 		}
+	}
+
+	return false
+}
 
-		m.forgesLoaded = true
+// Inspect launches binary, the named plugin providing capability, returns
+// its Info() and stops it. An empty binary, as Discover returns for a bundled
+// plugin, serves the plugin built into swm instead. It does not check the
+// plugin's dependencies. The plugin gets no caller token: it is only asked
+// for its Info().
+func (m *Manager) Inspect(ctx context.Context, capability, name, binary string) (*pluginv1.PluginInfo, error) {
+	set := pluginSet(capability)
+	if len(set) == 0 {
+		return nil, fmt.Errorf("%w: %s", errUnsupported, capability)
 	}
 
-	for _, fe := range m.forgeClients {
-		if slices.Contains(fe.hostnames, hostname) {
-			return fe.forge, nil
+	ctx, span := trace.Start(ctx, "inspect "+capability+"-"+name, "binary", binary)
+	defer span.End()
+
+	if binary == "" {
+		return m.inspectBundled(ctx, capability, name)
+	}
+
+	pluginCmd, err := m.pluginCommand(capability, name, binary, "")
+	if err != nil {
+		return nil, err
+	}
+
+	client := goplugin.NewClient(m.buildClientConfig(ctx, pluginCmd, set))
+	defer client.Kill()
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to plugin %s: %w", binary, err)
+	}
+
+	raw, err := rpcClient.Dispense(capability)
+	if err != nil {
+		return nil, fmt.Errorf("dispensing capability %s: %w", capability, err)
+	}
+
+	info, err := pluginInfo(ctx, capability, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if info == nil {
+		return nil, fmt.Errorf("%w: %s", errUnsupported, capability)
+	}
+
+	return info, nil
+}
+
+// IssueCallerToken returns a new token naming caller, "<capability>-<name>",
+// which Caller resolves. The plugin launched as caller presents it in its
+// Host.CallCapability calls; the swm daemon also issues them for the session
+// and picker plugins a CLI launches itself.
+func (m *Manager) IssueCallerToken(caller string) string {
+	token := rand.Text()
+
+	m.infoMu.Lock()
+	defer m.infoMu.Unlock()
+
+	m.tokens[token] = caller
+
+	return token
+}
+
+// Plugins returns the Info() of every launched plugin by capability.
+func (m *Manager) Plugins() map[string][]*pluginv1.PluginInfo {
+	m.infoMu.Lock()
+	defer m.infoMu.Unlock()
+
+	plugins := make(map[string][]*pluginv1.PluginInfo, len(m.infos))
+	for capability, infos := range m.infos {
+		plugins[capability] = slices.Clone(infos)
+	}
+
+	return plugins
+}
+
+// VCSForPath returns the name of the vcs plugin owning the project at path.
+// Walking up from path, the first directory holding one of the project
+// markers a vcs plugin reports decides; plugins are tried in configuration
+// order. It returns the default vcs plugin when no marker is found, and
+// launches nothing when a single vcs plugin is configured.
+func (m *Manager) VCSForPath(ctx context.Context, path string) (string, error) {
+	names := m.cfg.Plugins.VCS
+	if len(names) < 2 { //nolint:mnd // routing needs two plugins
+		return names.Default(), nil
+	}
+
+	markers := make([][]string, len(names))
+
+	for i, name := range names {
+		info, err := m.vcsInfo(ctx, name)
+		if err != nil {
+			return "", err
 		}
+
+		markers[i] = info.GetProjectMarkers()
 	}
 
-	return nil, fmt.Errorf("%w: %q", errNoForgePlugin, hostname)
+	dir := filepath.Clean(path)
+
+	for {
+		for i, name := range names {
+			for _, marker := range markers[i] {
+				if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+					return name, nil
+				}
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return names.Default(), nil
+		}
+
+		dir = parent
+	}
+}
+
+// VCSForURL returns the name of the vcs plugin claiming the scheme of the
+// remote url in its url_schemes. A scheme "x+y" also matches a plugin claiming
+// "x". URLs without a claimed scheme, including scp-like ones, go to the
+// default vcs plugin.
+func (m *Manager) VCSForURL(ctx context.Context, url string) (string, error) {
+	names := m.cfg.Plugins.VCS
+
+	scheme, _, ok := strings.Cut(url, "://")
+	if len(names) < 2 || !ok { //nolint:mnd // routing needs two plugins
+		return names.Default(), nil
+	}
+
+	scheme = strings.ToLower(scheme)
+	base, _, _ := strings.Cut(scheme, "+")
+
+	for _, name := range names {
+		info, err := m.vcsInfo(ctx, name)
+		if err != nil {
+			return "", err
+		}
+
+		if slices.Contains(info.GetUrlSchemes(), scheme) || slices.Contains(info.GetUrlSchemes(), base) {
+			return name, nil
+		}
+	}
+
+	return names.Default(), nil
 }
 
 // Warm pre-starts the listed capabilities in background goroutines and returns
 // immediately. Errors from plugin startup are not returned here; they are
 // surfaced by the first Get call for the failing capability.
-// Capabilities already running are reused without relaunching.
+// Capabilities already running are reused without relaunching. Warming vcs
+// starts every configured vcs plugin, since routing a project asks them all.
 func (m *Manager) Warm(ctx context.Context, capabilities ...string) error {
 	// Strip cancellation so goroutines outlive the caller's context (e.g. PreRunE).
 	bgCtx := context.WithoutCancel(ctx)
 
+	var keys []string
+
 	for _, c := range capabilities {
+		keys = append(keys, c)
+
+		if c == capabilityVCS && len(m.cfg.Plugins.VCS) > 1 {
+			for _, name := range m.cfg.Plugins.VCS[1:] {
+				keys = append(keys, vcsKey(name))
+			}
+		}
+	}
+
+	for _, c := range keys {
 		stored, loaded := m.launched.LoadOrStore(c, &launchOnce{})
 		if loaded {
 			continue
@@ -209,12 +660,7 @@ func (m *Manager) Warm(ctx context.Context, capabilities ...string) error {
 			continue
 		}
 
-		go func(capability string) {
-			lo.once.Do(func() {
-				lo.client, lo.raw, lo.err = m.launch(bgCtx, capability)
-				lo.done.Store(true)
-			})
-		}(c)
+		go lo.run(bgCtx, m, c)
 	}
 
 	return nil
@@ -249,10 +695,10 @@ func (m *Manager) buildClientConfig(
 	pluginCmd.Env = append(pluginCmd.Env, "SWM_LOG_LEVEL="+level.String())
 
 	return &goplugin.ClientConfig{
-		HandshakeConfig: handshake.Config,
-		Plugins:         set,
-		Cmd:             pluginCmd,
-		Stderr:          newLevelFilterWriter(m.stderr, level),
+		HandshakeConfig:  handshake.Config,
+		VersionedPlugins: handshake.VersionedPlugins(set),
+		Cmd:              pluginCmd,
+		Stderr:           newLevelFilterWriter(m.stderr, level),
 		Logger: hclog.New(&hclog.LoggerOptions{
 			Level:  level,
 			Output: m.stderr,
@@ -260,9 +706,33 @@ func (m *Manager) buildClientConfig(
 		AllowedProtocols: []goplugin.Protocol{
 			goplugin.ProtocolGRPC,
 		},
+		GRPCDialOptions: dialOptions(),
 	}
 }
 
+// callerToken returns the caller token to launch the named plugin of
+// capability with: one this Manager issues or, with a Remote, one the swm
+// daemon whose Host service the plugin calls issues. It is empty when the
+// plugins have no Host service.
+func (m *Manager) callerToken(ctx context.Context, capability, name string) (string, error) {
+	if m.hostSocket == "" {
+		return "", nil
+	}
+
+	caller := capability + "-" + name
+
+	if m.remote == nil {
+		return m.IssueCallerToken(caller), nil
+	}
+
+	token, err := m.remote.IssueCallerToken(ctx, caller)
+	if err != nil {
+		return "", fmt.Errorf("issuing caller token for plugin %s: %w", caller, err)
+	}
+
+	return token, nil
+}
+
 // capabilityName returns the plugin name from config for the given capability.
 func (m *Manager) capabilityName(capability string) (string, error) {
 	switch capability {
@@ -273,68 +743,167 @@ func (m *Manager) capabilityName(capability string) (string, error) {
 
 		return m.cfg.Plugins.Session, nil
 	case capabilityVCS:
-		if m.cfg.Plugins.VCS == "" {
+		if len(m.cfg.Plugins.VCS) == 0 {
 			return "", errNoVCSPlugin
 		}
 
-		return m.cfg.Plugins.VCS, nil
+		return m.cfg.Plugins.VCS.Default(), nil
 	case capabilityPicker:
 		if m.cfg.Plugins.Picker == "" {
 			return "", errNoPickerPlugin
 		}
 
 		return m.cfg.Plugins.Picker, nil
+	case capabilityTracker:
+		if m.cfg.Plugins.Tracker == "" {
+			return "", errNoTrackerPlugin
+		}
+
+		return m.cfg.Plugins.Tracker, nil
 	default:
 		return "", fmt.Errorf("%w: %s", errUnknownCapability, capability)
 	}
 }
 
-// discover finds the binary for the plugin providing the given capability with the given name.
-// Search order: (0) SWM_PLUGIN_PATH dirs, (1) explicit config path, (2) XDG plugins dir, (3) PATH.
-func (m *Manager) discover(capability, name string) (string, error) {
-	binary := "swm-plugin-" + capability + "-" + name
+// checkHostVersion checks that the host is at least the min_host_version
+// info declares.
+func (m *Manager) checkHostVersion(info *pluginv1.PluginInfo) error {
+	if info.GetMinHostVersion() == "" {
+		return nil
+	}
 
-	// 0. SWM_PLUGIN_PATH: platform-specific path list, searched left-to-right.
-	// Non-existent or non-directory entries are silently skipped.
-	for _, dir := range filepath.SplitList(os.Getenv("SWM_PLUGIN_PATH")) {
-		candidate := filepath.Join(dir, binary)
-		if _, err := os.Stat(candidate); err == nil { //nolint:gosec // SWM_PLUGIN_PATH is user-owned
-			return candidate, nil
+	minimum, err := semver.Parse(info.GetMinHostVersion())
+	if err != nil {
+		return fmt.Errorf("plugin %q min_host_version: %w", info.GetName(), err)
+	}
+
+	host, err := semver.Parse(m.hostVersion)
+	if err != nil {
+		return nil //nolint:nilerr // the host version is unknown; nothing to compare against
+	}
+
+	if host.Compare(minimum) < 0 {
+		return fmt.Errorf("%w: %q requires swm >= %s, but this is %s",
+			errHostTooOld, info.GetName(), minimum, m.hostVersion)
+	}
+
+	return nil
+}
+
+// configured reports whether a plugin is configured for capability.
+func (m *Manager) configured(capability string) bool {
+	if capability == capabilityForge {
+		return len(m.cfg.Plugins.Forges) > 0
+	}
+
+	_, err := m.capabilityName(capability)
+
+	return err == nil
+}
+
+// dataHome returns the XDG data home, or its override in the config.
+func (m *Manager) dataHome() string {
+	if m.cfg.DataHome != "" {
+		return m.cfg.DataHome
+	}
+
+	return xdg.DataHome
+}
+
+// dialRemote returns a connection to the named plugin running in the Remote.
+// It returns false when there is no Remote or it leaves the plugin to m.
+func (m *Manager) dialRemote(ctx context.Context, capability, name string) (grpc.ClientConnInterface, bool, error) {
+	if m.remote == nil {
+		return nil, false, nil
+	}
+
+	conn, ok, err := m.remote.Dial(ctx, capability+":"+name)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return m.withTimeouts(capability, m.record(capability, name, conn)), true, nil
+}
+
+// ensureForges launches the configured forge plugins once. Must be called
+// with m.mu held.
+func (m *Manager) ensureForges(ctx context.Context) error {
+	if m.forgesLoaded {
+		return nil
+	}
+
+	if err := m.loadForges(ctx); err != nil {
+		return err
+	}
+
+	m.forgesLoaded = true
+
+	return nil
+}
+
+// forgeConn returns a connection to the forge plugin handling hostname: the
+// one [plugins.forge_hosts] maps it to, passing the endpoint configured
+// there, else the one claiming it.
+func (m *Manager) forgeConn(ctx context.Context, hostname string) (grpc.ClientConnInterface, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.ensureForges(ctx); err != nil {
+		return nil, err
+	}
+
+	if host, ok := m.cfg.Plugins.ForgeHostFor(hostname); ok {
+		for _, fe := range m.forgeClients {
+			if fe.name == host.Plugin {
+				return withEndpoint(fe.conn, host), nil
+			}
 		}
 	}
 
-	// 1. Explicit config path.
-	if explicit, ok := m.cfg.Plugins.Paths[name]; ok {
-		if _, err := os.Stat(explicit); err == nil {
-			return explicit, nil
+	for _, fe := range m.forgeClients {
+		if slices.Contains(fe.hostnames, hostname) {
+			return fe.conn, nil
 		}
 	}
 
-	// 2. XDG data dir: $XDG_DATA_HOME/swm/plugins/<name>/<binary>.
-	xdgPath := filepath.Join(xdg.DataHome, "swm", "plugins", name, binary)
-	if _, err := os.Stat(xdgPath); err == nil {
-		return xdgPath, nil
+	return nil, fmt.Errorf("%w: %q", errNoForgePlugin, hostname)
+}
+
+// inspectBundled serves the plugin name of capability built into swm,
+// returns its Info() and stops it.
+func (m *Manager) inspectBundled(ctx context.Context, capability, name string) (*pluginv1.PluginInfo, error) {
+	conn, stop, err := bundled.Serve(capability, name, m.hostSocket, "", dialOptions()...)
+	if err != nil {
+		return nil, err
 	}
+	defer stop()
 
-	// 3. PATH lookup.
-	if path, err := exec.LookPath(binary); err == nil {
-		return path, nil
+	info, err := pluginInfo(ctx, capability, typedClient(capability, conn))
+	if err != nil {
+		return nil, err
+	}
+
+	if info == nil {
+		return nil, fmt.Errorf("%w: %s", errUnsupported, capability)
 	}
 
-	return "", fmt.Errorf("%w: %q not in config paths, %s, or PATH", errPluginNotFound, binary, xdgPath)
+	return info, nil
 }
 
 // launch performs the actual plugin binary discovery, exec, and gRPC handshake.
+// key is a capability, launching its configured plugin, or "<capability>:<name>"
+// (see vcsKey), launching the named plugin.
+// With a replay, the recording answers in place of the plugin.
 // It is called inside launchOnce.once.Do and must not hold any Manager-level locks.
-func (m *Manager) launch(ctx context.Context, capability string) (*goplugin.Client, any, error) {
-	name, err := m.capabilityName(capability)
-	if err != nil {
-		return nil, nil, err
-	}
+func (m *Manager) launch(ctx context.Context, key string) (*process, grpc.ClientConnInterface, error) {
+	capability, name, named := strings.Cut(key, ":")
+	if !named {
+		var err error
 
-	binary, err := m.discover(capability, name)
-	if err != nil {
-		return nil, nil, err
+		name, err = m.capabilityName(capability)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	set := pluginSet(capability)
@@ -342,149 +911,421 @@ func (m *Manager) launch(ctx context.Context, capability string) (*goplugin.Clie
 		return nil, nil, fmt.Errorf("%w: %s", errUnsupported, capability)
 	}
 
-	// Pre-populate Cmd.Env with the host socket address; go-plugin will append
-	// os.Environ() (since SkipHostEnv defaults to false), so this var stays first.
-	pluginCmd := exec.Command(binary) //nolint:gosec // binary is discovered from trusted sources
-	if m.hostSocket != "" {
-		pluginCmd.Env = []string{"SWM_HOST_SOCKET=" + m.hostSocket}
+	if m.replay != nil {
+		conn := m.withTimeouts(capability, m.replay.Conn(capability+"-"+name))
+
+		return nil, conn, m.validateDeps(ctx, capability, name, conn)
 	}
 
-	client := goplugin.NewClient(m.buildClientConfig(ctx, pluginCmd, set))
+	if conn, ok, err := m.dialRemote(ctx, capability, name); err != nil || ok {
+		if err == nil {
+			err = m.validateDeps(ctx, capability, name, conn)
+		}
 
-	rpcClient, err := client.Client()
-	if err != nil {
-		client.Kill()
+		return nil, conn, err
+	}
 
-		return nil, nil, fmt.Errorf("connecting to plugin %s: %w", binary, err)
+	binary, _, err := m.Discover(capability, name)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	raw, err := rpcClient.Dispense(capability)
+	proc, err := startProcess(ctx, m, capability, name, binary)
 	if err != nil {
-		client.Kill()
+		return nil, nil, err
+	}
+
+	conn := m.withTimeouts(capability, m.record(capability, name, proc))
+
+	if err := m.validateDeps(ctx, capability, name, conn); err != nil {
+		proc.kill()
 
-		return nil, nil, fmt.Errorf("dispensing capability %s: %w", capability, err)
+		return nil, nil, err
 	}
 
-	if err := m.validateDeps(ctx, capability, raw); err != nil {
-		client.Kill()
+	return proc, conn, nil
+}
+
+// launchForge launches the named forge plugin, or dials it through the Remote,
+// in which case the returned process is nil.
+func (m *Manager) launchForge(ctx context.Context, name string) (*process, grpc.ClientConnInterface, error) {
+	if m.replay != nil {
+		return nil, m.withTimeouts(capabilityForge, m.replay.Conn(capabilityForge+"-"+name)), nil
+	}
 
+	if conn, ok, err := m.dialRemote(ctx, capabilityForge, name); err != nil || ok {
+		return nil, conn, err
+	}
+
+	binary, _, err := m.Discover(capabilityForge, name)
+	if err != nil {
 		return nil, nil, err
 	}
 
-	return client, raw, nil
+	proc, err := startProcess(ctx, m, capabilityForge, name, binary)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return proc, m.withTimeouts(capabilityForge, m.record(capabilityForge, name, proc)), nil
+}
+
+// launchKey returns the launched-map key of the named plugin of capability.
+// An empty name selects the configured plugin, the default one for vcs.
+func (m *Manager) launchKey(capability, name string) (string, error) {
+	configured, err := m.capabilityName(capability)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case name == "" || name == configured:
+		return capability, nil
+	case capability == capabilityVCS && slices.Contains(m.cfg.Plugins.VCS, name):
+		return vcsKey(name), nil
+	default:
+		return "", fmt.Errorf("%w: %s %q", errPluginNotConfigured, capability, name)
+	}
 }
 
 // loadForges launches all configured forge plugins and populates m.forgeClients.
 // Must be called with m.mu held.
 func (m *Manager) loadForges(ctx context.Context) error {
 	for _, name := range m.cfg.Plugins.Forges {
-		binary, err := m.discover(capabilityForge, name)
+		client, conn, err := m.launchForge(ctx, name)
 		if err != nil {
 			return err
 		}
 
-		pluginCmd := exec.Command(binary) //nolint:gosec // binary is discovered from trusted sources
-		if m.hostSocket != "" {
-			pluginCmd.Env = []string{"SWM_HOST_SOCKET=" + m.hostSocket}
+		info, err := pluginv1.NewForgeClient(conn).Info(ctx, &pluginv1.Empty{})
+		if err != nil {
+			kill(client)
+
+			return fmt.Errorf("calling Info on forge plugin %s: %w", name, err)
 		}
 
-		set := goplugin.PluginSet{capabilityForge: &sdkforge.GRPCPlugin{}}
+		if err := m.register(ctx, capabilityForge, name, info.GetPluginInfo()); err != nil {
+			kill(client)
 
-		client := goplugin.NewClient(m.buildClientConfig(ctx, pluginCmd, set))
+			return err
+		}
 
-		rpcClient, err := client.Client()
-		if err != nil {
-			client.Kill()
+		m.forgeClients = append(m.forgeClients, &forgeEntry{
+			name:      name,
+			client:    client,
+			conn:      conn,
+			hostnames: info.GetClaimedHosts(),
+		})
+	}
+
+	return nil
+}
+
+// pluginClient returns the process of the named plugin of capability,
+// launching it if needed.
+func (m *Manager) pluginClient(ctx context.Context, capability, name string) (*process, error) {
+	if capability == capabilityForge {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if err := m.ensureForges(ctx); err != nil {
+			return nil, err
+		}
+
+		for _, fe := range m.forgeClients {
+			if fe.name == name && fe.client != nil {
+				return fe.client, nil
+			}
+		}
+
+		return nil, fmt.Errorf("%w: %s %q", errPluginNotConfigured, capability, name)
+	}
+
+	key, err := m.launchKey(capability, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := m.Get(ctx, key); err != nil {
+		return nil, err
+	}
+
+	stored, _ := m.launched.Load(key)
+
+	lo, ok := stored.(*launchOnce)
+	if !ok || lo.client == nil {
+		return nil, fmt.Errorf("%w: %s", errNotLaunched, key)
+	}
 
-			return fmt.Errorf("connecting to forge plugin %s: %w", binary, err)
+	return lo.client, nil
+}
+
+// pluginCommand returns the command that runs binary, the named plugin of
+// capability, with the caller token token, if any, sandboxed when
+// [plugins.sandbox] has an entry for it. It fails when binary does not match
+// the checksum [plugins.checksums] pins.
+func (m *Manager) pluginCommand(capability, name, binary, token string) (*exec.Cmd, error) {
+	if err := m.verifyChecksum(capability+"-"+name, binary); err != nil {
+		return nil, err
+	}
+
+	pluginCmd := exec.Command(binary) //nolint:gosec // binary is discovered from trusted sources
+
+	if sb, ok := m.cfg.Plugins.Sandbox[capability+"-"+name]; ok {
+		writable, err := m.sandboxWritable(name, sb)
+		if err == nil {
+			pluginCmd, err = sandbox.Command(binary, writable)
 		}
 
-		raw, err := rpcClient.Dispense(capabilityForge)
 		if err != nil {
-			client.Kill()
+			return nil, fmt.Errorf("sandboxing plugin %s-%s: %w", capability, name, err)
+		}
+	}
+
+	// Pre-populate Cmd.Env with the host socket address and the caller
+	// token, which identifies the plugin's Host.CallCapability calls;
+	// go-plugin will append os.Environ() (since SkipHostEnv defaults to false).
+	if m.hostSocket != "" {
+		pluginCmd.Env = append(pluginCmd.Env, "SWM_HOST_SOCKET="+m.hostSocket)
+	}
+
+	if token != "" {
+		pluginCmd.Env = append(pluginCmd.Env, hostcall.CallerTokenEnv+"="+token)
+	}
+
+	return pluginCmd, nil
+}
 
-			return fmt.Errorf("dispensing forge capability from %s: %w", binary, err)
+// record returns conn, a connection to the named plugin of capability,
+// recording the calls made through it when the Manager records them.
+func (m *Manager) record(capability, name string, conn grpc.ClientConnInterface) grpc.ClientConnInterface {
+	if m.recorder == nil {
+		return conn
+	}
+
+	return m.recorder.Conn(capability+"-"+name, conn)
+}
+
+// register checks a launched plugin's info against the host version, the
+// configured capabilities, and the versions of the plugins launched so far in
+// both directions, then records it, as the named plugin of capability, for
+// HasFeature, CallCapability and later checks.
+func (m *Manager) register(ctx context.Context, capability, name string, info *pluginv1.PluginInfo) error {
+	if err := m.checkHostVersion(info); err != nil {
+		return err
+	}
+
+	m.infoMu.Lock()
+	defer m.infoMu.Unlock()
+
+	for _, dep := range info.GetRequires() {
+		depCap := CapabilityName(dep.GetCapability())
+		if !m.configured(depCap) {
+			return fmt.Errorf("%w: %q requires %q", errPluginMissingDep, info.GetName(), depCap)
 		}
 
-		fc, ok := raw.(pluginv1.ForgeClient)
-		if !ok {
-			client.Kill()
+		for _, provider := range m.infos[depCap] {
+			if err := checkDepVersion(info, dep, provider); err != nil {
+				return err
+			}
+		}
+	}
 
-			return fmt.Errorf("%w: %s", errInvalidForgePlugin, binary)
+	for _, opt := range info.GetOptional() {
+		if !m.configured(opt) {
+			slog.DebugContext(ctx, "optional capability not configured",
+				"plugin", info.GetName(), "capability", opt)
 		}
+	}
 
-		info, err := fc.Info(ctx, &pluginv1.Empty{})
-		if err != nil {
-			client.Kill()
+	for _, launched := range m.infos {
+		for _, dependant := range launched {
+			for _, dep := range dependant.GetRequires() {
+				if CapabilityName(dep.GetCapability()) != capability {
+					continue
+				}
 
-			return fmt.Errorf("calling Info on forge plugin %s: %w", binary, err)
+				if err := checkDepVersion(dependant, dep, info); err != nil {
+					return err
+				}
+			}
 		}
+	}
 
-		m.forgeClients = append(m.forgeClients, &forgeEntry{
-			client:    client,
-			forge:     fc,
-			hostnames: info.GetClaimedHosts(),
-		})
+	m.infos[capability] = append(m.infos[capability], info)
+	m.callers[capability+"-"+name] = info
+
+	return nil
+}
+
+// sandboxWritable returns the directories the named plugin may write below
+// when sandboxed, creating its own data and runtime dirs.
+func (m *Manager) sandboxWritable(name string, sb config.Sandbox) ([]string, error) {
+	own := []string{
+		filepath.Join(m.dataHome(), "swm", "plugins", name),
+		filepath.Join(xdg.RuntimeDir, "swm", name),
+	}
+
+	for _, dir := range own {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("creating %s: %w", dir, err)
+		}
+	}
+
+	return slices.Concat([]string{m.cfg.CodeRoot}, own, []string{os.TempDir(), "/tmp", "/dev"}, sb.AllowWrite), nil
+}
+
+// validateDeps calls Info() on the named plugin of capability over conn and
+// registers it.
+func (m *Manager) validateDeps(ctx context.Context, capability, name string, conn grpc.ClientConnInterface) error {
+	info, err := pluginInfo(ctx, capability, typedClient(capability, conn))
+	if err != nil {
+		return err
+	}
+
+	if info == nil {
+		return nil
+	}
+
+	return m.register(ctx, capability, name, info)
+}
+
+// vcsInfo returns the Info() of the named vcs plugin.
+func (m *Manager) vcsInfo(ctx context.Context, name string) (*pluginv1.VCSInfo, error) {
+	vcs, err := m.GetVCS(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := vcs.Info(ctx, &pluginv1.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("calling Info on vcs plugin %s: %w", name, err)
+	}
+
+	return info, nil
+}
+
+// verifyChecksum checks binary against the checksum [plugins.checksums]
+// pins for the plugin fullName, if any.
+func (m *Manager) verifyChecksum(fullName, binary string) error {
+	want, ok := m.cfg.Plugins.Checksums[fullName]
+	if !ok {
+		return nil
+	}
+
+	got, err := plugininstall.Checksum(binary)
+	if err != nil {
+		return fmt.Errorf("verifying plugin %s: %w", fullName, err)
+	}
+
+	if got != want {
+		return fmt.Errorf("%w: plugin %s: %s has sha256 %s, but %s is pinned; "+
+			"run `swm plugin pin %s` if it was updated on purpose", errChecksumMismatch, fullName, binary, got, want, fullName)
 	}
 
 	return nil
 }
 
-// validateDeps calls Info() on the plugin and checks required capability deps.
-func (m *Manager) validateDeps(ctx context.Context, capability string, raw any) error {
-	var info *pluginv1.PluginInfo
+// withTimeouts returns conn bounding the calls to capability's plugin by
+// [plugins.timeouts], or conn itself when none are configured.
+func (m *Manager) withTimeouts(capability string, conn grpc.ClientConnInterface) grpc.ClientConnInterface {
+	if len(m.cfg.Plugins.Timeouts) == 0 {
+		return conn
+	}
 
+	return &timeoutConn{ClientConnInterface: conn, capability: capability, plugins: m.cfg.Plugins}
+}
+
+// checkDepVersion checks that provider is at least the minimum version the
+// plugin dependant requires through dep.
+func checkDepVersion(dependant *pluginv1.PluginInfo, dep *pluginv1.CapabilityDep, provider *pluginv1.PluginInfo) error {
+	if dep.GetMinVersion() == "" {
+		return nil
+	}
+
+	depCap := CapabilityName(dep.GetCapability())
+
+	ok, err := semver.AtLeast(provider.GetVersion(), dep.GetMinVersion())
+	if err != nil {
+		return fmt.Errorf("checking %q's requirement on %s %q: %w", dependant.GetName(), depCap, provider.GetName(), err)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %q requires %s >= %s, but %q is %s", errPluginTooOld,
+			dependant.GetName(), depCap, dep.GetMinVersion(), provider.GetName(), provider.GetVersion())
+	}
+
+	return nil
+}
+
+// pluginInfo calls Info() on the plugin client raw of the given capability.
+// It returns nil when raw is not a client of that capability.
+func pluginInfo(ctx context.Context, capability string, raw any) (*pluginv1.PluginInfo, error) {
 	switch capability {
 	case capabilityVCS:
 		if c, ok := raw.(pluginv1.VCSClient); ok {
 			resp, err := c.Info(ctx, &pluginv1.Empty{})
 			if err != nil {
-				return fmt.Errorf("calling Info on vcs plugin: %w", err)
+				return nil, fmt.Errorf("calling Info on vcs plugin: %w", err)
 			}
 
-			info = resp.GetPluginInfo()
+			return resp.GetPluginInfo(), nil
 		}
 	case capabilitySession:
 		if c, ok := raw.(pluginv1.SessionClient); ok {
 			resp, err := c.Info(ctx, &pluginv1.Empty{})
 			if err != nil {
-				return fmt.Errorf("calling Info on session plugin: %w", err)
+				return nil, fmt.Errorf("calling Info on session plugin: %w", err)
 			}
 
-			info = resp.GetPluginInfo()
+			return resp.GetPluginInfo(), nil
 		}
 	case capabilityPicker:
 		if c, ok := raw.(pluginv1.PickerClient); ok {
 			resp, err := c.Info(ctx, &pluginv1.Empty{})
 			if err != nil {
-				return fmt.Errorf("calling Info on picker plugin: %w", err)
+				return nil, fmt.Errorf("calling Info on picker plugin: %w", err)
 			}
 
-			info = resp.GetPluginInfo()
+			return resp.GetPluginInfo(), nil
 		}
 	case capabilityForge:
 		if c, ok := raw.(pluginv1.ForgeClient); ok {
 			resp, err := c.Info(ctx, &pluginv1.Empty{})
 			if err != nil {
-				return fmt.Errorf("calling Info on forge plugin: %w", err)
+				return nil, fmt.Errorf("calling Info on forge plugin: %w", err)
 			}
 
-			info = resp.GetPluginInfo()
+			return resp.GetPluginInfo(), nil
 		}
-	}
+	case capabilityTracker:
+		if c, ok := raw.(pluginv1.TrackerClient); ok {
+			resp, err := c.Info(ctx, &pluginv1.Empty{})
+			if err != nil {
+				return nil, fmt.Errorf("calling Info on tracker plugin: %w", err)
+			}
 
-	if info == nil {
-		return nil
+			return resp.GetPluginInfo(), nil
+		}
 	}
 
-	for _, dep := range info.GetRequires() {
-		depCap := dep.GetCapability().String()
-		if _, configured := m.capabilityName(depCap); configured != nil {
-			return fmt.Errorf("%w: %q requires %q", errPluginMissingDep, info.GetName(), depCap)
-		}
+	return nil, nil //nolint:nilnil // raw does not implement the capability's client
+}
+
+// dialOptions returns the options swm dials plugins with.
+func dialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(trace.UnaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(trace.StreamClientInterceptor),
 	}
+}
 
-	return nil
+// kill terminates the plugin process proc, which is nil for plugins running
+// in a Remote.
+func kill(proc *process) {
+	if proc != nil {
+		proc.kill()
+	}
 }
 
 // pluginSet returns the go-plugin PluginSet for the given capability.
@@ -496,9 +1337,34 @@ func pluginSet(capability string) goplugin.PluginSet {
 		return goplugin.PluginSet{capabilityPicker: &sdkpicker.GRPCPlugin{}}
 	case capabilitySession:
 		return goplugin.PluginSet{capabilitySession: &sdksession.GRPCPlugin{}}
+	case capabilityTracker:
+		return goplugin.PluginSet{capabilityTracker: &sdktracker.GRPCPlugin{}}
 	case capabilityVCS:
 		return goplugin.PluginSet{capabilityVCS: &sdkvcs.GRPCPlugin{}}
 	default:
 		return goplugin.PluginSet{}
 	}
 }
+
+// typedClient returns the client of capability over conn.
+func typedClient(capability string, conn grpc.ClientConnInterface) any {
+	switch capability {
+	case capabilityForge:
+		return pluginv1.NewForgeClient(conn)
+	case capabilityPicker:
+		return pluginv1.NewPickerClient(conn)
+	case capabilitySession:
+		return pluginv1.NewSessionClient(conn)
+	case capabilityTracker:
+		return pluginv1.NewTrackerClient(conn)
+	case capabilityVCS:
+		return pluginv1.NewVCSClient(conn)
+	default:
+		return nil
+	}
+}
+
+// vcsKey returns the launched-map key of the named, non-default vcs plugin.
+func vcsKey(name string) string {
+	return capabilityVCS + ":" + name
+}
@@ -18,7 +18,10 @@ import (
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
 	"github.com/kalbasit/swm/cmd/swm/internal/config"
+	"github.com/kalbasit/swm/cmd/swm/internal/plugininstall"
 	"github.com/kalbasit/swm/cmd/swm/internal/pluginmgr"
+	"github.com/kalbasit/swm/cmd/swm/internal/pluginrec"
+	"github.com/kalbasit/swm/cmd/swm/internal/sandbox"
 )
 
 const (
@@ -54,6 +57,9 @@ func (s *syncBuffer) Write(p []byte) (int, error) {
 }
 
 func TestMain(m *testing.M) {
+	// Sandboxed plugins are started through the test binary.
+	sandbox.Init()
+
 	dir, err := os.MkdirTemp("", "pluginmgr-test-*")
 	if err != nil {
 		panic("creating temp dir: " + err.Error())
@@ -102,7 +108,7 @@ func newCfg(vcs string) *config.Config {
 		CodeRoot:     testCodeRoot,
 		DefaultStory: testDefaultStory,
 		Plugins: config.Plugins{
-			VCS: vcs,
+			VCS: config.Names{vcs},
 		},
 	}
 }
@@ -134,7 +140,7 @@ func TestGet_LazyLaunch(t *testing.T) {
 		CodeRoot:     testCodeRoot,
 		DefaultStory: testDefaultStory,
 		Plugins: config.Plugins{
-			VCS: fakePluginName,
+			VCS: config.Names{fakePluginName},
 			Paths: map[string]string{
 				fakePluginName: fakeVCSBin,
 			},
@@ -160,7 +166,7 @@ func TestGet_ClientIsVCSClient(t *testing.T) {
 
 	cfg := &config.Config{
 		Plugins: config.Plugins{
-			VCS: fakePluginName,
+			VCS: config.Names{fakePluginName},
 			Paths: map[string]string{
 				fakePluginName: fakeVCSBin,
 			},
@@ -181,6 +187,254 @@ func TestGet_ClientIsVCSClient(t *testing.T) {
 	require.Equal(t, fakePluginName, info.GetPluginInfo().GetName())
 }
 
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	mgr := pluginmgr.New(&config.Config{}, "")
+	defer mgr.Close() //nolint:errcheck // best-effort cleanup in test teardown
+
+	info, err := mgr.Inspect(context.Background(), "vcs", fakePluginName, fakeVCSBin)
+	require.NoError(t, err)
+	require.Equal(t, fakePluginName, info.GetName())
+	require.Equal(t, "0.0.1", info.GetVersion())
+
+	_, err = mgr.Inspect(context.Background(), "editor", fakePluginName, fakeVCSBin)
+	require.ErrorContains(t, err, "unsupported capability")
+}
+
+// linkFakePlugins installs the fake vcs and picker plugins, named fake, into
+// a data home as symlinks, each reporting the info in the given protojson,
+// and returns a config using them.
+func linkFakePlugins(t *testing.T, vcsInfo, pickerInfo string) *config.Config {
+	t.Helper()
+
+	dataHome := t.TempDir()
+	dir := filepath.Join(dataHome, "swm", "plugins", fakePluginName)
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+
+	for bin, info := range map[string]string{fakeVCSBin: vcsInfo, fakePickerBin: pickerInfo} {
+		link := filepath.Join(dir, filepath.Base(bin))
+		require.NoError(t, os.Symlink(bin, link))
+		require.NoError(t, os.WriteFile(link+".json", []byte(info), 0o600))
+	}
+
+	return &config.Config{
+		DataHome: dataHome,
+		Plugins: config.Plugins{
+			VCS:    config.Names{fakePluginName},
+			Picker: fakePluginName,
+		},
+	}
+}
+
+func TestGet_DependencyVersion(t *testing.T) {
+	t.Parallel()
+
+	picker := `{"name": "fakepicker", "version": "1.0.0",
+		"requires": [{"capability": "CAPABILITY_TYPE_VCS", "min_version": "1.2.0"}]}`
+
+	t.Run("provider launched first", func(t *testing.T) {
+		t.Parallel()
+
+		mgr := pluginmgr.New(linkFakePlugins(t, `{"name": "fakevcs", "version": "1.1.9"}`, picker), "")
+		defer mgr.Close() //nolint:errcheck // best-effort cleanup in test teardown
+
+		_, err := mgr.Get(context.Background(), "vcs")
+		require.NoError(t, err)
+
+		_, err = mgr.Get(context.Background(), "picker")
+		require.ErrorContains(t, err, `plugin version too old: "fakepicker" requires vcs >= 1.2.0, but "fakevcs" is 1.1.9`)
+	})
+
+	t.Run("dependant launched first", func(t *testing.T) {
+		t.Parallel()
+
+		mgr := pluginmgr.New(linkFakePlugins(t, `{"name": "fakevcs", "version": "1.1.9"}`, picker), "")
+		defer mgr.Close() //nolint:errcheck // best-effort cleanup in test teardown
+
+		_, err := mgr.Get(context.Background(), "picker")
+		require.NoError(t, err)
+
+		_, err = mgr.Get(context.Background(), "vcs")
+		require.ErrorContains(t, err, `plugin version too old: "fakepicker" requires vcs >= 1.2.0, but "fakevcs" is 1.1.9`)
+	})
+
+	t.Run("satisfied", func(t *testing.T) {
+		t.Parallel()
+
+		mgr := pluginmgr.New(linkFakePlugins(t, `{"name": "fakevcs", "version": "v1.2.0"}`, picker), "")
+		defer mgr.Close() //nolint:errcheck // best-effort cleanup in test teardown
+
+		_, err := mgr.Get(context.Background(), "vcs")
+		require.NoError(t, err)
+
+		_, err = mgr.Get(context.Background(), "picker")
+		require.NoError(t, err)
+	})
+}
+
+func TestGet_MissingAndOptionalDependencies(t *testing.T) {
+	t.Parallel()
+
+	mgr := pluginmgr.New(linkFakePlugins(t, `{"name": "fakevcs", "version": "1.0.0", "optional": ["forge"]}`,
+		`{"name": "fakepicker", "version": "1.0.0", "requires": [{"capability": "CAPABILITY_TYPE_TRACKER"}]}`), "")
+	defer mgr.Close() //nolint:errcheck // best-effort cleanup in test teardown
+
+	_, err := mgr.Get(context.Background(), "vcs")
+	require.NoError(t, err, "a missing optional capability is not an error")
+
+	_, err = mgr.Get(context.Background(), "picker")
+	require.ErrorContains(t, err, `plugin missing required capability: "fakepicker" requires "tracker"`)
+}
+
+func TestGet_HostVersion(t *testing.T) {
+	t.Parallel()
+
+	cfg := linkFakePlugins(t, `{"name": "fakevcs", "version": "1.0.0", "min_host_version": "2.1.0"}`,
+		`{"name": "fakepicker", "version": "1.0.0", "min_host_version": "v2.0.0"}`)
+
+	mgr := pluginmgr.New(cfg, "", pluginmgr.WithHostVersion("v2.0.0"))
+	defer mgr.Close() //nolint:errcheck // best-effort cleanup in test teardown
+
+	_, err := mgr.Get(context.Background(), "vcs")
+	require.ErrorContains(t, err, `swm version too old for plugin: "fakevcs" requires swm >= 2.1.0, but this is v2.0.0`)
+
+	_, err = mgr.Get(context.Background(), "picker")
+	require.NoError(t, err)
+
+	// Without a known host version the requirement is not checked.
+	unversioned := pluginmgr.New(cfg, "")
+	defer unversioned.Close() //nolint:errcheck // best-effort cleanup in test teardown
+
+	_, err = unversioned.Get(context.Background(), "vcs")
+	require.NoError(t, err)
+}
+
+func TestHasFeature(t *testing.T) {
+	t.Parallel()
+
+	mgr := pluginmgr.New(linkFakePlugins(t, `{"name": "fakevcs", "version": "1.0.0", "features": ["list-branches"]}`,
+		`{"name": "fakepicker", "version": "1.0.0"}`), "")
+	defer mgr.Close() //nolint:errcheck // best-effort cleanup in test teardown
+
+	require.False(t, mgr.HasFeature("vcs", "list-branches"), "the plugin is not launched yet")
+
+	_, err := mgr.Get(context.Background(), "vcs")
+	require.NoError(t, err)
+
+	require.True(t, mgr.HasFeature("vcs", "list-branches"))
+	require.False(t, mgr.HasFeature("vcs", "stash"))
+	require.False(t, mgr.HasFeature("picker", "list-branches"))
+}
+
+// linkFakeVCS installs the fake vcs plugin under the given name into cfg's data
+// home, with vcsInfo (protojson VCSInfo) replacing its project markers and URL
+// schemes.
+func linkFakeVCS(t *testing.T, cfg *config.Config, name, vcsInfo string) {
+	t.Helper()
+
+	dir := filepath.Join(cfg.DataHome, "swm", "plugins", name)
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+
+	link := filepath.Join(dir, "swm-plugin-vcs-"+name)
+	require.NoError(t, os.Symlink(fakeVCSBin, link))
+	require.NoError(t, os.WriteFile(link+".vcs.json", []byte(vcsInfo), 0o600))
+
+	cfg.Plugins.VCS = append(cfg.Plugins.VCS, name)
+}
+
+func TestVCSRouting(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{DataHome: t.TempDir()}
+	linkFakeVCS(t, cfg, "git", `{"project_markers": [".git"], "url_schemes": ["https", "ssh", "git"]}`)
+	linkFakeVCS(t, cfg, "jj", `{"project_markers": [".jj"], "url_schemes": ["jj"]}`)
+
+	mgr := pluginmgr.New(cfg, "")
+	t.Cleanup(func() { mgr.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	ctx := context.Background()
+
+	t.Run("GetVCS", func(t *testing.T) {
+		t.Parallel()
+
+		vcs, err := mgr.GetVCS(ctx, "jj")
+		require.NoError(t, err)
+		require.NotNil(t, vcs)
+
+		_, err = mgr.GetVCS(ctx, "hg")
+		require.ErrorContains(t, err, `plugin not configured: vcs "hg"`)
+	})
+
+	t.Run("VCSForPath", func(t *testing.T) {
+		t.Parallel()
+
+		root := t.TempDir()
+		for _, dir := range []string{"git/.git", "jj/.jj/repo", "jj/sub", "plain"} {
+			require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o750))
+		}
+
+		for path, want := range map[string]string{"git": "git", "jj": "jj", "jj/sub": "jj", "plain": "git"} {
+			got, err := mgr.VCSForPath(ctx, filepath.Join(root, path))
+			require.NoError(t, err)
+			require.Equal(t, want, got, path)
+		}
+	})
+
+	t.Run("VCSForURL", func(t *testing.T) {
+		t.Parallel()
+
+		for url, want := range map[string]string{
+			"https://github.com/kalbasit/swm":     "git",
+			"jj+https://github.com/kalbasit/swm":  "jj",
+			"JJ://example.com/repo":               "jj",
+			"git@github.com:kalbasit/swm.git":     "git",
+			"hg+https://example.com/unclaimed/hg": "git",
+		} {
+			got, err := mgr.VCSForURL(ctx, url)
+			require.NoError(t, err)
+			require.Equal(t, want, got, url)
+		}
+	})
+}
+
+func TestVCSRouting_SinglePluginLaunchesNothing(t *testing.T) {
+	t.Parallel()
+
+	mgr := pluginmgr.New(newCfg("missing"), "")
+	defer mgr.Close() //nolint:errcheck // best-effort cleanup in test teardown
+
+	name, err := mgr.VCSForPath(context.Background(), t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, "missing", name)
+
+	name, err = mgr.VCSForURL(context.Background(), "jj+https://example.com/repo")
+	require.NoError(t, err)
+	require.Equal(t, "missing", name)
+}
+
+//nolint:paralleltest // writes an executable that forks of parallel tests could hold open (ETXTBSY)
+func TestDiscover_InstalledPlugin(t *testing.T) {
+	dataHome := t.TempDir()
+	dir := filepath.Join(dataHome, "swm", "plugins", "installed")
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+
+	data, err := os.ReadFile(fakeVCSBin) //nolint:gosec // reading trusted test binary
+	require.NoError(t, err)
+	binary := filepath.Join(dir, "swm-plugin-vcs-installed")
+	require.NoError(t, os.WriteFile(binary, data, 0o755)) //nolint:gosec // binary must be executable
+
+	cfg := newCfg("installed")
+	cfg.DataHome = dataHome
+
+	mgr := pluginmgr.New(cfg, "")
+	defer mgr.Close() //nolint:errcheck // best-effort cleanup in test teardown
+
+	raw, err := mgr.Get(context.Background(), "vcs")
+	require.NoError(t, err)
+	require.NotNil(t, raw)
+}
+
 func TestGet_MissingPlugin(t *testing.T) {
 	t.Parallel()
 
@@ -201,12 +455,22 @@ func TestGet_UnconfiguredCapability(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestGet_UnconfiguredTracker(t *testing.T) {
+	t.Parallel()
+
+	mgr := pluginmgr.New(newCfg(""), "")
+	defer mgr.Close() //nolint:errcheck // best-effort cleanup in test teardown
+
+	_, err := mgr.Get(context.Background(), "tracker")
+	require.ErrorContains(t, err, "no tracker plugin configured")
+}
+
 func TestClose_Cleanup(t *testing.T) {
 	t.Parallel()
 
 	cfg := &config.Config{
 		Plugins: config.Plugins{
-			VCS: fakePluginName,
+			VCS: config.Names{fakePluginName},
 			Paths: map[string]string{
 				fakePluginName: fakeVCSBin,
 			},
@@ -232,7 +496,7 @@ func TestGet_PluginStderrForwarded(t *testing.T) {
 
 	cfg := &config.Config{
 		Plugins: config.Plugins{
-			VCS: "fakestderr",
+			VCS: config.Names{"fakestderr"},
 			Paths: map[string]string{
 				"fakestderr": fakeStderrBin,
 			},
@@ -260,7 +524,7 @@ func TestGet_NoDebugLogs_AtWarnLevel(t *testing.T) { //nolint:paralleltest // mu
 
 	cfg := &config.Config{
 		Plugins: config.Plugins{
-			VCS: fakePluginName,
+			VCS: config.Names{fakePluginName},
 			Paths: map[string]string{
 				fakePluginName: fakeVCSBin,
 			},
@@ -308,6 +572,11 @@ func TestDiscover_SWMPluginPath(t *testing.T) {
 		raw, err := mgr.Get(context.Background(), "vcs")
 		require.NoError(t, err)
 		require.NotNil(t, raw)
+
+		binary, tier, err := mgr.Discover("vcs", fakePluginName)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dir, "swm-plugin-vcs-fake"), binary)
+		require.Equal(t, "SWM_PLUGIN_PATH", tier)
 	})
 
 	t.Run("takes precedence over PATH", func(t *testing.T) {
@@ -343,7 +612,7 @@ func TestDiscover_SWMPluginPath(t *testing.T) {
 
 		cfg := &config.Config{
 			Plugins: config.Plugins{
-				VCS:   fakePluginName,
+				VCS:   config.Names{fakePluginName},
 				Paths: map[string]string{fakePluginName: dummyPath},
 			},
 		}
@@ -408,7 +677,7 @@ func TestWarm_StartsBothConcurrently(t *testing.T) {
 		CodeRoot:     testCodeRoot,
 		DefaultStory: testDefaultStory,
 		Plugins: config.Plugins{
-			VCS:    fakePluginName,
+			VCS:    config.Names{fakePluginName},
 			Picker: fakePluginName,
 			Paths: map[string]string{
 				fakePluginName: fakeVCSBin,
@@ -421,7 +690,7 @@ func TestWarm_StartsBothConcurrently(t *testing.T) {
 		"fake-vcs":    fakeVCSBin,
 		"fake-picker": fakePickerBin,
 	}
-	cfg.Plugins.VCS = "fake-vcs"
+	cfg.Plugins.VCS = config.Names{"fake-vcs"}
 	cfg.Plugins.Picker = "fake-picker"
 
 	mgr := pluginmgr.New(cfg, "")
@@ -447,7 +716,7 @@ func TestWarm_ReturnsNilImmediately(t *testing.T) {
 		CodeRoot:     testCodeRoot,
 		DefaultStory: testDefaultStory,
 		Plugins: config.Plugins{
-			VCS:    fakePluginName,
+			VCS:    config.Names{fakePluginName},
 			Picker: "nonexistent",
 			Paths: map[string]string{
 				fakePluginName: fakeVCSBin,
@@ -469,7 +738,7 @@ func TestWarm_ErrorSurfacedByGet(t *testing.T) {
 		CodeRoot:     testCodeRoot,
 		DefaultStory: testDefaultStory,
 		Plugins: config.Plugins{
-			VCS:    fakePluginName,
+			VCS:    config.Names{fakePluginName},
 			Picker: "nonexistent",
 			Paths: map[string]string{
 				fakePluginName: fakeVCSBin,
@@ -493,7 +762,7 @@ func TestWarm_AlreadyLaunchedNotRelaunched(t *testing.T) {
 		CodeRoot:     testCodeRoot,
 		DefaultStory: testDefaultStory,
 		Plugins: config.Plugins{
-			VCS: fakePluginName,
+			VCS: config.Names{fakePluginName},
 			Paths: map[string]string{
 				fakePluginName: fakeVCSBin,
 			},
@@ -523,7 +792,7 @@ func TestWarm_CalledTwiceDoesNotRelaunch(t *testing.T) {
 		CodeRoot:     testCodeRoot,
 		DefaultStory: testDefaultStory,
 		Plugins: config.Plugins{
-			VCS: fakePluginName,
+			VCS: config.Names{fakePluginName},
 			Paths: map[string]string{
 				fakePluginName: fakeVCSBin,
 			},
@@ -555,7 +824,7 @@ func TestGet_FailedLaunchIsCached(t *testing.T) {
 		CodeRoot:     testCodeRoot,
 		DefaultStory: testDefaultStory,
 		Plugins: config.Plugins{
-			VCS: "missing",
+			VCS: config.Names{"missing"},
 			Paths: map[string]string{
 				"missing": missingBin,
 			},
@@ -587,7 +856,7 @@ func TestGet_DebugLogs_AtDebugLevel(t *testing.T) { //nolint:paralleltest // mut
 
 	cfg := &config.Config{
 		Plugins: config.Plugins{
-			VCS: fakePluginName,
+			VCS: config.Names{fakePluginName},
 			Paths: map[string]string{
 				fakePluginName: fakeVCSBin,
 			},
@@ -606,3 +875,382 @@ func TestGet_DebugLogs_AtDebugLevel(t *testing.T) { //nolint:paralleltest // mut
 		return bytes.Contains([]byte(sink.String()), []byte("[DEBUG]"))
 	}, 5*time.Second, 10*time.Millisecond)
 }
+
+// newCrashingVCS returns a Manager for a copy of the fake vcs plugin that
+// crashes as crash says (see testdata/fakevcs).
+func newCrashingVCS(t *testing.T, crash string) (*pluginmgr.Manager, pluginv1.VCSClient) {
+	t.Helper()
+
+	bin := filepath.Join(t.TempDir(), "swm-plugin-vcs-"+fakePluginName)
+	copyBinary(t, fakeVCSBin, bin)
+
+	cfg := newCfg(fakePluginName)
+	cfg.Plugins.Paths = map[string]string{fakePluginName: bin}
+
+	mgr := pluginmgr.New(cfg, "")
+	t.Cleanup(func() { mgr.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	vcs, err := mgr.GetVCS(t.Context(), fakePluginName)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(bin+".crash", []byte(crash), 0o600))
+
+	return mgr, vcs
+}
+
+func TestProcess_RetriesIdempotentCall(t *testing.T) {
+	t.Parallel()
+
+	_, vcs := newCrashingVCS(t, "1")
+
+	id, err := vcs.GetUserIdentity(t.Context(), &pluginv1.UserIdentityRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "Fake", id.GetName())
+}
+
+func TestProcess_DoesNotRetryMutatingCall(t *testing.T) {
+	t.Parallel()
+
+	_, vcs := newCrashingVCS(t, "1")
+
+	_, err := vcs.CreateWorktree(t.Context(), &pluginv1.CreateWorktreeRequest{})
+	require.Error(t, err)
+
+	// The plugin was relaunched for the next call.
+	_, err = vcs.CreateWorktree(t.Context(), &pluginv1.CreateWorktreeRequest{})
+	require.NoError(t, err)
+}
+
+func TestProcess_ConcurrentCallsShareRelaunch(t *testing.T) {
+	t.Parallel()
+
+	_, vcs := newCrashingVCS(t, "1")
+
+	var wg sync.WaitGroup
+
+	errs := make([]error, 8)
+
+	for i := range errs {
+		wg.Go(func() {
+			_, errs[i] = vcs.GetUserIdentity(t.Context(), &pluginv1.UserIdentityRequest{})
+		})
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err, "calls that find the plugin relaunching wait for it and retry")
+	}
+}
+
+func TestProcess_CrashLoop(t *testing.T) {
+	t.Parallel()
+
+	mgr, vcs := newCrashingVCS(t, "always")
+
+	var err error
+
+	for range 3 {
+		if _, err = vcs.GetUserIdentity(t.Context(), &pluginv1.UserIdentityRequest{}); err != nil &&
+			strings.Contains(err.Error(), "crashed") {
+			break
+		}
+	}
+
+	require.ErrorContains(t, err, "plugin vcs-fake crashed 3 times")
+	require.ErrorContains(t, err, "fakevcs: crashing")
+
+	// The plugin is not relaunched again.
+	_, err = mgr.Conn(t.Context(), "vcs:"+fakePluginName)
+	require.ErrorContains(t, err, "crashed 3 times")
+}
+
+func TestTimeouts(t *testing.T) {
+	t.Parallel()
+
+	cfg := newCfg(fakePluginName)
+	cfg.Plugins.Paths = map[string]string{fakePluginName: fakeVCSBin}
+	cfg.Plugins.Timeouts = map[string]config.Duration{
+		"vcs.ValidateBranchName": config.Duration(100 * time.Millisecond),
+	}
+
+	mgr := pluginmgr.New(cfg, "")
+	t.Cleanup(func() { mgr.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	vcs, err := mgr.GetVCS(t.Context(), fakePluginName)
+	require.NoError(t, err)
+
+	_, err = vcs.ValidateBranchName(t.Context(), &pluginv1.ValidateBranchNameRequest{BranchName: "main"})
+	require.NoError(t, err)
+
+	_, err = vcs.ValidateBranchName(t.Context(), &pluginv1.ValidateBranchNameRequest{BranchName: "hang"})
+	require.EqualError(t, err, "timed out after 100ms calling vcs.ValidateBranchName")
+
+	// The caller's own cancellation is not reported as a timeout.
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = vcs.ValidateBranchName(ctx, &pluginv1.ValidateBranchNameRequest{BranchName: "hang"})
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "timed out after")
+
+	// Other calls have no deadline.
+	_, err = vcs.GetUserIdentity(t.Context(), &pluginv1.UserIdentityRequest{})
+	require.NoError(t, err)
+}
+
+func TestSandbox(t *testing.T) {
+	t.Parallel()
+
+	cfg := newCfg(fakePluginName)
+	cfg.DataHome = t.TempDir()
+	cfg.Plugins.Paths = map[string]string{fakePluginName: fakeVCSBin}
+	cfg.Plugins.Sandbox = map[string]config.Sandbox{"vcs-" + fakePluginName: {}}
+
+	mgr := pluginmgr.New(cfg, "")
+	t.Cleanup(func() { mgr.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	vcs, err := mgr.GetVCS(t.Context(), fakePluginName)
+	if sandbox.Available() != nil {
+		require.ErrorIs(t, err, sandbox.ErrUnsupported)
+
+		return
+	}
+
+	require.NoError(t, err)
+
+	id, err := vcs.GetUserIdentity(t.Context(), &pluginv1.UserIdentityRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "Fake", id.GetName())
+
+	// The plugin's own data dir was created for it.
+	require.DirExists(t, filepath.Join(cfg.DataHome, "swm", "plugins", fakePluginName))
+}
+
+func TestChecksums(t *testing.T) {
+	t.Parallel()
+
+	sum, err := plugininstall.Checksum(fakeVCSBin)
+	require.NoError(t, err)
+
+	cfg := newCfg(fakePluginName)
+	cfg.Plugins.Paths = map[string]string{fakePluginName: fakeVCSBin}
+	cfg.Plugins.Checksums = map[string]string{"vcs-" + fakePluginName: sum}
+
+	mgr := pluginmgr.New(cfg, "")
+	t.Cleanup(func() { mgr.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	_, err = mgr.Get(t.Context(), "vcs")
+	require.NoError(t, err)
+
+	// A binary that does not match its pin is not launched.
+	cfg = newCfg(fakePluginName)
+	cfg.Plugins.Paths = map[string]string{fakePluginName: fakeVCSBin}
+	cfg.Plugins.Checksums = map[string]string{"vcs-" + fakePluginName: strings.Repeat("0", 64)}
+
+	mgr = pluginmgr.New(cfg, "")
+	t.Cleanup(func() { mgr.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	_, err = mgr.Get(t.Context(), "vcs")
+	require.ErrorContains(t, err, "plugin checksum mismatch: plugin vcs-fake: "+fakeVCSBin+" has sha256 "+sum)
+}
+
+func TestRecordReplay(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+
+	rec, err := pluginrec.Create(path)
+	require.NoError(t, err)
+
+	cfg := newCfg(fakePluginName)
+	cfg.Plugins.Paths = map[string]string{fakePluginName: fakeVCSBin}
+
+	mgr := pluginmgr.New(cfg, "", pluginmgr.WithRecorder(rec))
+	t.Cleanup(func() { mgr.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	vcs, err := mgr.GetVCS(t.Context(), fakePluginName)
+	require.NoError(t, err)
+
+	_, err = vcs.GetUserIdentity(t.Context(), &pluginv1.UserIdentityRequest{})
+	require.NoError(t, err)
+	require.NoError(t, rec.Close())
+
+	replay, err := pluginrec.Load(path)
+	require.NoError(t, err)
+
+	// The recording answers, Info included, without a plugin binary.
+	cfg = newCfg(fakePluginName)
+	cfg.Plugins.Paths = map[string]string{fakePluginName: filepath.Join(t.TempDir(), "missing")}
+
+	mgr = pluginmgr.New(cfg, "", pluginmgr.WithReplay(replay))
+	t.Cleanup(func() { mgr.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	vcs, err = mgr.GetVCS(t.Context(), fakePluginName)
+	require.NoError(t, err)
+	require.Equal(t, fakePluginName, mgr.Plugins()["vcs"][0].GetName())
+
+	id, err := vcs.GetUserIdentity(t.Context(), &pluginv1.UserIdentityRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "fake@example.com", id.GetEmail())
+
+	// Calls beyond the recording fail.
+	_, err = vcs.GetUserIdentity(t.Context(), &pluginv1.UserIdentityRequest{})
+	require.ErrorContains(t, err, "call not in the recording")
+}
+
+func TestBundled(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not on PATH")
+	}
+
+	if _, err := exec.LookPath("swm-plugin-vcs-git"); err == nil {
+		t.Skip("swm-plugin-vcs-git is installed and takes precedence")
+	}
+
+	cfg := newCfg("git")
+	cfg.DataHome = t.TempDir()
+
+	mgr := pluginmgr.New(cfg, "")
+	t.Cleanup(func() { mgr.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	// Bundled plugins are off by default.
+	_, err := mgr.Get(t.Context(), "vcs")
+	require.ErrorContains(t, err, "set plugins.bundled = true")
+
+	cfg.Plugins.Bundled = true
+
+	mgr = pluginmgr.New(cfg, "")
+	t.Cleanup(func() { mgr.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	binary, tier, err := mgr.Discover("vcs", "git")
+	require.NoError(t, err)
+	require.Empty(t, binary)
+	require.Equal(t, "bundled", tier)
+
+	vcs, err := mgr.GetVCS(t.Context(), "")
+	require.NoError(t, err)
+	require.Equal(t, "git", mgr.Plugins()["vcs"][0].GetName())
+
+	vcsInfo, err := vcs.Info(t.Context(), &pluginv1.Empty{})
+	require.NoError(t, err)
+	require.Contains(t, vcsInfo.GetProjectMarkers(), ".git")
+
+	info, err := mgr.Inspect(t.Context(), "vcs", "git", "")
+	require.NoError(t, err)
+	require.Equal(t, "git", info.GetName())
+
+	// A pinned plugin needs its binary.
+	cfg.Plugins.Checksums = map[string]string{"vcs-git": strings.Repeat("0", 64)}
+
+	_, _, err = mgr.Discover("vcs", "git")
+	require.ErrorContains(t, err, "plugin binary not found")
+}
+
+func TestGetForge_ForgeHosts(t *testing.T) {
+	t.Parallel()
+
+	// A recorded forge plugin claiming github.com, answering GetPullRequest
+	// by the endpoint it is passed.
+	recording := `{"plugin":"forge-github","method":"/swm.plugin.v1.Forge/Info",` +
+		`"responses":[{"pluginInfo":{"name":"github"},"claimedHosts":["github.com"]}]}` + "\n" +
+		`{"plugin":"forge-github","method":"/swm.plugin.v1.Forge/GetPullRequest",` +
+		`"requests":[{"number":"1"}],"responses":[{"title":"public"}]}` + "\n" +
+		`{"plugin":"forge-github","method":"/swm.plugin.v1.Forge/GetPullRequest",` +
+		`"requests":[{"number":"1","endpoint":{"apiUrl":"https://ghe.example.com/api/v3/",` +
+		`"tokenPath":"/tokens/ghe"}}],"responses":[{"title":"enterprise"}]}` + "\n"
+
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(recording), 0o600))
+
+	replay, err := pluginrec.Load(path)
+	require.NoError(t, err)
+
+	cfg := newCfg(fakePluginName)
+	cfg.Plugins.Forges = config.Names{"github"}
+	cfg.Plugins.ForgeHosts = map[string]config.ForgeHost{
+		"*.example.com": {Plugin: "github", APIURL: "https://ghe.example.com/api/v3/", TokenPath: "/tokens/ghe"},
+		"git.corp":      {Plugin: "github"},
+	}
+
+	mgr := pluginmgr.New(cfg, "", pluginmgr.WithReplay(replay))
+	t.Cleanup(func() { mgr.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	// A host matching a glob gets its endpoint with every call.
+	forge, err := mgr.GetForge(t.Context(), "ghe.example.com")
+	require.NoError(t, err)
+
+	pr, err := forge.GetPullRequest(t.Context(), &pluginv1.GetPRRequest{Number: 1})
+	require.NoError(t, err)
+	require.Equal(t, "enterprise", pr.GetTitle())
+
+	// A host mapped to a plugin alone gets no endpoint.
+	forge, err = mgr.GetForge(t.Context(), "git.corp")
+	require.NoError(t, err)
+
+	pr, err = forge.GetPullRequest(t.Context(), &pluginv1.GetPRRequest{Number: 1})
+	require.NoError(t, err)
+	require.Equal(t, "public", pr.GetTitle())
+
+	// Claimed hosts still route to their plugin.
+	_, err = mgr.GetForge(t.Context(), "github.com")
+	require.NoError(t, err)
+
+	_, err = mgr.GetForge(t.Context(), "gitlab.com")
+	require.ErrorContains(t, err, `"gitlab.com"`)
+}
+
+func TestCallCapabilitySupport(t *testing.T) {
+	t.Parallel()
+
+	recording := `{"plugin":"session-fake","method":"/swm.plugin.v1.Session/Info",` +
+		`"responses":[{"pluginInfo":{"name":"fake","requires":[{"capability":"CAPABILITY_TYPE_VCS"}],` +
+		`"optional":["forge"]}}]}` + "\n" +
+		`{"plugin":"vcs-fake","method":"/swm.plugin.v1.VCS/Info","responses":[{"pluginInfo":{"name":"fake"}}]}` + "\n" +
+		`{"plugin":"vcs-fake","method":"/swm.plugin.v1.VCS/GetUserIdentity",` +
+		`"responses":[{"email":"fake@example.com"}]}` + "\n"
+
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(recording), 0o600))
+
+	replay, err := pluginrec.Load(path)
+	require.NoError(t, err)
+
+	cfg := newCfg(fakePluginName)
+	cfg.Plugins.Session = fakePluginName
+
+	mgr := pluginmgr.New(cfg, "", pluginmgr.WithReplay(replay))
+	t.Cleanup(func() { mgr.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	_, err = mgr.Get(t.Context(), "session")
+	require.NoError(t, err)
+
+	// A launched plugin declares what its Info() requires and optionally uses.
+	declared, err := mgr.Declared(t.Context(), "session-fake")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"vcs", "forge"}, declared)
+
+	// Plugins that are not configured cannot call.
+	_, err = mgr.Declared(t.Context(), "tracker-jira")
+	require.ErrorContains(t, err, "no tracker plugin configured")
+
+	// Calls are identified by the tokens the Manager issues, one per launch.
+	token := mgr.IssueCallerToken("session-fake")
+	require.NotEqual(t, token, mgr.IssueCallerToken("session-fake"))
+
+	caller, ok := mgr.Caller(token)
+	require.True(t, ok)
+	require.Equal(t, "session-fake", caller)
+
+	_, ok = mgr.Caller("session-fake")
+	require.False(t, ok)
+
+	conn, err := mgr.CapabilityConn(t.Context(), "vcs", "")
+	require.NoError(t, err)
+
+	id, err := pluginv1.NewVCSClient(conn).GetUserIdentity(t.Context(), &pluginv1.UserIdentityRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "fake@example.com", id.GetEmail())
+}
@@ -0,0 +1,420 @@
+package pluginmgr
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	hclog "github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/bundled"
+	"github.com/kalbasit/swm/sdk/go/trace"
+)
+
+const (
+	// maxCrashes is how many times in a row a plugin may crash before the
+	// Manager stops relaunching it.
+	maxCrashes = 3
+
+	// stableAfter is how long a plugin must run for its crash count to reset.
+	stableAfter = time.Minute
+
+	// restartBackoff is the delay before the first relaunch; it doubles with
+	// every further crash up to maxRestartBackoff.
+	restartBackoff    = 100 * time.Millisecond
+	maxRestartBackoff = 2 * time.Second
+
+	// stderrTailLines is how many lines of a plugin's stderr are kept for
+	// reporting a crash.
+	stderrTailLines = 20
+
+	// healthCheckTimeout bounds the health check of a plugin a call failed on.
+	healthCheckTimeout = 2 * time.Second
+)
+
+var (
+	errClosed    = errors.New("plugin stopped by swm")
+	errCrashLoop = errors.New("plugin keeps crashing")
+)
+
+// process is a plugin process the Manager launched. Plugin clients call
+// through it rather than straight to the plugin's connection, so a plugin
+// that crashed is relaunched underneath the clients callers already hold.
+// Calls that only read are retried once on the relaunched plugin.
+// A bundled plugin, with an empty binary, is served in the swm process and
+// never crashes on its own.
+type process struct {
+	m          *Manager
+	capability string
+	name       string
+	binary     string
+
+	mu        sync.Mutex
+	client    *goplugin.Client // nil for a bundled plugin
+	stop      func()           // stops a bundled plugin
+	conn      *grpc.ClientConn
+	stderr    *tailWriter
+	startedAt time.Time
+	crashes   int
+	closed    bool
+	err       error // set once the plugin crashed maxCrashes times
+
+	// relaunching is closed once the relaunch waiting out its backoff, with
+	// p.mu released, has started the plugin again; nil when none is pending.
+	relaunching chan struct{}
+}
+
+// startProcess launches binary as the plugin name of capability, or serves
+// the bundled plugin when binary is empty.
+func startProcess(ctx context.Context, m *Manager, capability, name, binary string) (*process, error) {
+	p := &process{m: m, capability: capability, name: name, binary: binary}
+
+	if err := p.start(ctx); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Conn returns the connection to the running plugin, relaunching it first
+// when it exited.
+func (p *process) Conn(ctx context.Context) (*grpc.ClientConn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil && p.client.Exited() {
+		if err := p.restart(ctx, "exited"); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.conn, nil
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (p *process) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	conn, err := p.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = conn.Invoke(ctx, method, args, reply, opts...)
+	if err == nil {
+		return nil
+	}
+
+	if rerr := p.recover(ctx, conn, err); rerr != nil || !idempotent(method) {
+		return cmp.Or(rerr, err)
+	}
+
+	slog.DebugContext(ctx, "retrying plugin call", "plugin", p.fullName(), "method", method)
+
+	if conn, err = p.Conn(ctx); err != nil {
+		return err
+	}
+
+	if err = conn.Invoke(ctx, method, args, reply, opts...); err != nil {
+		return cmp.Or(p.recover(ctx, conn, err), err)
+	}
+
+	return nil
+}
+
+// NewStream implements grpc.ClientConnInterface. Opening the stream is
+// retried like Invoke; a stream that breaks later is not.
+func (p *process) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	conn, err := p.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := conn.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		if rerr := p.recover(ctx, conn, err); rerr != nil || !idempotent(method) {
+			return nil, cmp.Or(rerr, err)
+		}
+
+		if conn, err = p.Conn(ctx); err != nil {
+			return nil, err
+		}
+
+		if stream, err = conn.NewStream(ctx, desc, method, opts...); err != nil {
+			return nil, cmp.Or(p.recover(ctx, conn, err), err)
+		}
+	}
+
+	return &watchedStream{ClientStream: stream, ctx: ctx, p: p, conn: conn}, nil
+}
+
+// awaitRelaunch waits, with p.mu released, until the pending relaunch done
+// belongs to has started the plugin. Must be called with p.mu held.
+func (p *process) awaitRelaunch(ctx context.Context, done chan struct{}) error {
+	p.mu.Unlock()
+
+	var waitErr error
+
+	select {
+	case <-ctx.Done():
+		waitErr = fmt.Errorf("relaunching plugin %s: %w", p.fullName(), ctx.Err())
+	case <-done:
+	}
+
+	p.mu.Lock()
+
+	if waitErr != nil {
+		return waitErr
+	}
+
+	if p.closed {
+		return fmt.Errorf("%w: %s", errClosed, p.fullName())
+	}
+
+	return p.err
+}
+
+// fullName returns the plugin's "<capability>-<name>", as in its binary name.
+func (p *process) fullName() string {
+	return p.capability + "-" + p.name
+}
+
+// healthy reports whether the plugin answers the health check go-plugin
+// serves next to every plugin. Must be called with p.mu held.
+func (p *process) healthy(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	resp, err := healthpb.NewHealthClient(p.conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: goplugin.GRPCServiceName,
+	})
+
+	return err == nil && resp.GetStatus() == healthpb.HealthCheckResponse_SERVING
+}
+
+// kill terminates the plugin process for good.
+func (p *process) kill() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+
+	if p.client == nil {
+		p.stop()
+
+		return
+	}
+
+	p.client.Kill()
+}
+
+// recover checks whether err from a call over conn means the plugin is gone:
+// it exited, or it no longer answers health checks. It then relaunches the
+// plugin unless that already happened. It returns nil when a relaunched
+// plugin is ready for a retry, err when the plugin is fine and the call itself
+// failed, and the error relaunching the plugin otherwise.
+func (p *process) recover(ctx context.Context, conn *grpc.ClientConn, err error) error {
+	if status.Code(err) != codes.Unavailable {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client == nil {
+		return err
+	}
+
+	if p.conn != conn {
+		// Another call relaunched the plugin meanwhile.
+		return p.err
+	}
+
+	reason := "exited"
+
+	if !p.client.Exited() {
+		if p.healthy(ctx) {
+			return err
+		}
+
+		reason = "failed health check"
+	}
+
+	return p.restart(ctx, reason)
+}
+
+// restart relaunches the plugin after it crashed, waiting longer after each
+// crash in a row. Must be called with p.mu held; it is released while waiting,
+// so other calls, and kill, are not held up by the backoff. A call that finds
+// a relaunch pending waits for it instead of starting another.
+func (p *process) restart(ctx context.Context, reason string) error {
+	if p.closed {
+		return fmt.Errorf("%w: %s", errClosed, p.fullName())
+	}
+
+	if p.err != nil {
+		return p.err
+	}
+
+	if p.relaunching != nil {
+		return p.awaitRelaunch(ctx, p.relaunching)
+	}
+
+	if time.Since(p.startedAt) >= stableAfter {
+		p.crashes = 0
+	}
+
+	p.crashes++
+
+	// Kill reaps the process and waits until its stderr has been read.
+	p.client.Kill()
+
+	output := p.stderr.String()
+
+	slog.WarnContext(ctx, "plugin crashed",
+		"plugin", p.fullName(), "reason", reason, "crashes", p.crashes, "stderr", output)
+
+	if p.crashes >= maxCrashes {
+		p.err = fmt.Errorf("%w: plugin %s crashed %d times", errCrashLoop, p.fullName(), p.crashes)
+		if output != "" {
+			p.err = fmt.Errorf("%w; last output:\n%s", p.err, output)
+		}
+
+		return p.err
+	}
+
+	backoff := min(restartBackoff<<(p.crashes-1), maxRestartBackoff)
+
+	done := make(chan struct{})
+	p.relaunching = done
+
+	p.mu.Unlock()
+
+	var waitErr error
+
+	select {
+	case <-ctx.Done():
+		waitErr = fmt.Errorf("relaunching plugin %s: %w", p.fullName(), ctx.Err())
+	case <-time.After(backoff):
+	}
+
+	p.mu.Lock()
+
+	// Waiters block on p.mu until the plugin has started.
+	p.relaunching = nil
+	close(done)
+
+	if waitErr != nil {
+		return waitErr
+	}
+
+	if p.closed {
+		return fmt.Errorf("%w: %s", errClosed, p.fullName())
+	}
+
+	return p.start(ctx)
+}
+
+// start launches the plugin process and connects to it. Must be called with
+// p.mu held, or before p is shared.
+func (p *process) start(ctx context.Context) error {
+	ctx, span := trace.Start(ctx, "launch "+p.fullName(), "binary", p.binary)
+	defer span.End()
+
+	token, err := p.m.callerToken(ctx, p.capability, p.name)
+	if err != nil {
+		return err
+	}
+
+	if p.binary == "" {
+		conn, stop, err := bundled.Serve(p.capability, p.name, p.m.hostSocket, token, dialOptions()...)
+		if err != nil {
+			return err
+		}
+
+		slog.DebugContext(ctx, "bundled plugin started", "capability", p.capability, "name", p.name)
+
+		p.conn, p.stop, p.startedAt = conn, stop, time.Now()
+
+		return nil
+	}
+
+	pluginCmd, err := p.m.pluginCommand(p.capability, p.name, p.binary, token)
+	if err != nil {
+		return err
+	}
+
+	p.stderr = newTailWriter(stderrTailLines)
+
+	cfg := p.m.buildClientConfig(ctx, pluginCmd, pluginSet(p.capability))
+	cfg.Stderr = io.MultiWriter(cfg.Stderr, newLevelFilterWriter(p.stderr, hclog.Info))
+
+	client := goplugin.NewClient(cfg)
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+
+		return fmt.Errorf("connecting to plugin %s: %w", p.binary, err)
+	}
+
+	gc, ok := rpcClient.(*goplugin.GRPCClient)
+	if !ok {
+		client.Kill()
+
+		return fmt.Errorf("%w: %s", errNotGRPC, p.binary)
+	}
+
+	slog.DebugContext(ctx, "plugin connected",
+		"capability", p.capability, "binary", p.binary, "protocol", client.NegotiatedVersion())
+
+	p.client, p.conn, p.startedAt = client, gc.Conn, time.Now()
+
+	return nil
+}
+
+// watchedStream checks the plugin's health when a stream breaks, so the
+// next call finds it relaunched.
+type watchedStream struct {
+	grpc.ClientStream
+
+	ctx  context.Context
+	p    *process
+	conn *grpc.ClientConn
+}
+
+// RecvMsg implements grpc.ClientStream.
+func (s *watchedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return cmp.Or(s.p.recover(s.ctx, s.conn, err), err)
+	}
+
+	return err
+}
+
+// idempotent reports whether method only reads, so that retrying it on a
+// relaunched plugin cannot repeat a side effect.
+func idempotent(method string) bool {
+	name := methodName(method)
+
+	for _, prefix := range []string{"Current", "Detect", "Get", "Info", "Is", "List", "Parse", "Validate"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
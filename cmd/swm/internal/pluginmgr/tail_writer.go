@@ -0,0 +1,61 @@
+package pluginmgr
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// tailWriter keeps the last lines written to it, so that the output of a
+// plugin that crashed can be reported after the fact.
+type tailWriter struct {
+	mu      sync.Mutex
+	max     int
+	lines   []string
+	partial []byte
+}
+
+func newTailWriter(maxLines int) *tailWriter {
+	return &tailWriter{max: maxLines}
+}
+
+// String returns the kept lines, oldest first, with a trailing unterminated
+// line if there is one.
+func (t *tailWriter) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lines := t.lines
+	if len(t.partial) > 0 {
+		lines = append(lines[:len(lines):len(lines)], string(t.partial))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (t *tailWriter) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	data := append(t.partial, p...)
+
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+
+		t.lines = append(t.lines, string(data[:i]))
+		data = data[i+1:]
+	}
+
+	if len(t.lines) > t.max {
+		// Clone so the dropped lines can be collected.
+		t.lines = slices.Clone(t.lines[len(t.lines)-t.max:])
+	}
+
+	t.partial = bytes.Clone(data)
+
+	return len(p), nil
+}
@@ -0,0 +1,52 @@
+//nolint:testpackage // white-box test for unexported tailWriter
+package pluginmgr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailWriter_KeepsLastLines(t *testing.T) {
+	t.Parallel()
+
+	tw := newTailWriter(2)
+
+	for _, chunk := range []string{"one\ntw", "o\nthree\n", "fo"} {
+		n, err := tw.Write([]byte(chunk))
+		require.NoError(t, err)
+		require.Equal(t, len(chunk), n)
+	}
+
+	require.Equal(t, "two\nthree\nfo", tw.String())
+
+	_, err := tw.Write([]byte("ur\n"))
+	require.NoError(t, err)
+	require.Equal(t, "three\nfour", tw.String())
+}
+
+func TestTailWriter_Empty(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, newTailWriter(2).String())
+}
+
+func TestIdempotent(t *testing.T) {
+	t.Parallel()
+
+	for _, method := range []string{
+		"/swm.plugin.v1.VCS/GetUserIdentity",
+		"/swm.plugin.v1.VCS/ListBranches",
+		"/swm.plugin.v1.Forge/ParseRemoteURL",
+	} {
+		require.True(t, idempotent(method), method)
+	}
+
+	for _, method := range []string{
+		"/swm.plugin.v1.VCS/CreateWorktree",
+		"/swm.plugin.v1.VCS/Clone",
+		"/swm.plugin.v1.Forge/CreatePullRequest",
+	} {
+		require.False(t, idempotent(method), method)
+	}
+}
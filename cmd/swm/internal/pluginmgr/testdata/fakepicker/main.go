@@ -3,11 +3,15 @@ package main
 
 import (
 	"context"
+	"errors"
+	"io/fs"
+	"os"
 
 	goplugin "github.com/hashicorp/go-plugin"
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 	"github.com/kalbasit/swm/sdk/go/handshake"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 type fakePicker struct {
@@ -15,14 +19,40 @@ type fakePicker struct {
 }
 
 func (f *fakePicker) Info(_ context.Context, _ *pluginv1.Empty) (*pluginv1.PickerInfo, error) {
+	info, err := pluginInfo()
+	if err != nil {
+		return nil, err
+	}
+
 	return &pluginv1.PickerInfo{
-		PluginInfo: &pluginv1.PluginInfo{
-			Name:    "fake",
-			Version: "0.0.1",
-		},
+		PluginInfo: info,
 	}, nil
 }
 
+// pluginInfo returns the plugin's info, replaced by the protojson in
+// <argv0>.json when that exists so that tests can vary it without a rebuild.
+func pluginInfo() (*pluginv1.PluginInfo, error) {
+	info := &pluginv1.PluginInfo{
+		Name:    "fake",
+		Version: "0.0.1",
+	}
+
+	data, err := os.ReadFile(os.Args[0] + ".json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return info, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := protojson.Unmarshal(data, info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
 type grpcPlugin struct {
 	goplugin.NetRPCUnsupportedPlugin
 }
@@ -3,11 +3,17 @@ package main
 
 import (
 	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
 
 	goplugin "github.com/hashicorp/go-plugin"
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 	"github.com/kalbasit/swm/sdk/go/handshake"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 type fakeVCS struct {
@@ -15,13 +21,108 @@ type fakeVCS struct {
 }
 
 func (f *fakeVCS) Info(_ context.Context, _ *pluginv1.Empty) (*pluginv1.VCSInfo, error) {
-	return &pluginv1.VCSInfo{
-		PluginInfo: &pluginv1.PluginInfo{
-			Name:    "fake",
-			Version: "0.0.1",
-		},
-		ProjectMarkers: []string{".git"},
-	}, nil
+	info, err := pluginInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	vcsInfo := &pluginv1.VCSInfo{ProjectMarkers: []string{".git"}}
+
+	// <argv0>.vcs.json replaces the vcs-specific fields.
+	data, err := os.ReadFile(os.Args[0] + ".vcs.json")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	if err == nil {
+		if err := protojson.Unmarshal(data, vcsInfo); err != nil {
+			return nil, err
+		}
+	}
+
+	vcsInfo.PluginInfo = info
+
+	return vcsInfo, nil
+}
+
+// GetUserIdentity is a call that only reads; it crashes the plugin on demand.
+func (f *fakeVCS) GetUserIdentity(
+	_ context.Context, _ *pluginv1.UserIdentityRequest,
+) (*pluginv1.UserIdentity, error) {
+	maybeCrash()
+
+	return &pluginv1.UserIdentity{Name: "Fake", Email: "fake@example.com"}, nil
+}
+
+// CreateWorktree is a call with a side effect; it crashes the plugin on demand.
+func (f *fakeVCS) CreateWorktree(_ context.Context, _ *pluginv1.CreateWorktreeRequest) (*pluginv1.Empty, error) {
+	maybeCrash()
+
+	return &pluginv1.Empty{}, nil
+}
+
+// ValidateBranchName hangs until the call is cancelled for the branch name
+// "hang", so that tests can run into a timeout.
+func (f *fakeVCS) ValidateBranchName(
+	ctx context.Context, req *pluginv1.ValidateBranchNameRequest,
+) (*pluginv1.Empty, error) {
+	if req.GetBranchName() == "hang" {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
+	return &pluginv1.Empty{}, nil
+}
+
+// maybeCrash crashes the plugin when <argv0>.crash says so: "always" crashes
+// every call, a count N crashes the next N calls.
+func maybeCrash() {
+	path := os.Args[0] + ".crash"
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	if want := strings.TrimSpace(string(data)); want != "always" {
+		n, err := strconv.Atoi(want)
+		if err != nil || n <= 0 {
+			return
+		}
+
+		if err := os.WriteFile(path, []byte(strconv.Itoa(n-1)), 0o600); err != nil {
+			return
+		}
+	}
+
+	// A panic in a handler takes the whole plugin down, and its trace goes
+	// to the process's stderr rather than the one go-plugin forwards.
+	panic("fakevcs: crashing")
+}
+
+// pluginInfo returns the plugin's info, replaced by the protojson in
+// <argv0>.json when that exists so that tests can vary it without a rebuild.
+func pluginInfo() (*pluginv1.PluginInfo, error) {
+	info := &pluginv1.PluginInfo{
+		Name:    "fake",
+		Version: "0.0.1",
+	}
+
+	data, err := os.ReadFile(os.Args[0] + ".json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return info, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := protojson.Unmarshal(data, info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
 }
 
 type grpcPlugin struct {
@@ -41,9 +142,9 @@ func (g *grpcPlugin) GRPCClient(_ context.Context, _ *goplugin.GRPCBroker, conn
 func main() {
 	goplugin.Serve(&goplugin.ServeConfig{
 		HandshakeConfig: handshake.Config,
-		Plugins: goplugin.PluginSet{
+		VersionedPlugins: handshake.VersionedPlugins(goplugin.PluginSet{
 			"vcs": &grpcPlugin{},
-		},
+		}),
 		GRPCServer: goplugin.DefaultGRPCServer,
 	})
 }
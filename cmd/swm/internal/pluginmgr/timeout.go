@@ -0,0 +1,115 @@
+package pluginmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+)
+
+var errTimeout = errors.New("timed out")
+
+// timeoutConn bounds the calls to one capability's plugin by the deadlines
+// configured in [plugins.timeouts].
+type timeoutConn struct {
+	grpc.ClientConnInterface
+
+	capability string
+	plugins    config.Plugins
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (c *timeoutConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	name := methodName(method)
+
+	d := c.plugins.Timeout(c.capability, name)
+	if d <= 0 {
+		return c.ClientConnInterface.Invoke(ctx, method, args, reply, opts...)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	err := c.ClientConnInterface.Invoke(callCtx, method, args, reply, opts...)
+
+	return c.timedOut(ctx, callCtx, err, d, name)
+}
+
+// NewStream implements grpc.ClientConnInterface. The deadline covers the
+// whole stream, not only opening it.
+func (c *timeoutConn) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	name := methodName(method)
+
+	d := c.plugins.Timeout(c.capability, name)
+	if d <= 0 {
+		return c.ClientConnInterface.NewStream(ctx, desc, method, opts...)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, d)
+
+	stream, err := c.ClientConnInterface.NewStream(callCtx, desc, method, opts...)
+	if err != nil {
+		cancel()
+
+		return nil, c.timedOut(ctx, callCtx, err, d, name)
+	}
+
+	// RecvMsg is not always called until it fails: a client-streaming call
+	// reads its single response and stops. Release the deadline once the
+	// stream itself has ended too, rather than when it expires.
+	context.AfterFunc(stream.Context(), cancel)
+
+	return &timeoutStream{
+		ClientStream: stream,
+		timedOut: func(err error) error {
+			cancel()
+
+			if errors.Is(err, io.EOF) {
+				return err
+			}
+
+			return c.timedOut(ctx, callCtx, err, d, name)
+		},
+	}, nil
+}
+
+// timedOut replaces err, from a call to method bounded by callCtx, with a
+// timeout error when callCtx ran past its deadline while ctx, the caller's
+// context, did not end.
+func (c *timeoutConn) timedOut(ctx, callCtx context.Context, err error, d time.Duration, method string) error {
+	if err == nil || ctx.Err() != nil || !errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+
+	return fmt.Errorf("%w after %s calling %s.%s", errTimeout, d, c.capability, method)
+}
+
+// timeoutStream ends the deadline of a stream when the stream ends.
+type timeoutStream struct {
+	grpc.ClientStream
+
+	timedOut func(err error) error
+}
+
+// RecvMsg implements grpc.ClientStream.
+func (s *timeoutStream) RecvMsg(m any) error {
+	if err := s.ClientStream.RecvMsg(m); err != nil {
+		return s.timedOut(err)
+	}
+
+	return nil
+}
+
+// methodName returns the method of a full gRPC method name such as
+// "/swm.plugin.v1.VCS/CreateWorktree".
+func methodName(method string) string {
+	return method[strings.LastIndex(method, "/")+1:]
+}
@@ -0,0 +1,66 @@
+//nolint:testpackage // white-box test for unexported timeoutConn
+package pluginmgr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/config"
+)
+
+// endingConn opens streams that end when their end func is called, recording
+// the context each was opened with.
+type endingConn struct {
+	grpc.ClientConnInterface
+
+	ctx context.Context //nolint:containedctx // the context NewStream was called with
+	end context.CancelFunc
+}
+
+func (c *endingConn) NewStream(ctx context.Context, _ *grpc.StreamDesc, _ string, _ ...grpc.CallOption) (
+	grpc.ClientStream, error,
+) {
+	c.ctx = ctx
+
+	streamCtx, end := context.WithCancel(ctx)
+	c.end = end
+
+	return &endingStream{ctx: streamCtx}, nil
+}
+
+// endingStream is a stream whose context ends when the stream does, as with
+// gRPC's streams.
+type endingStream struct {
+	grpc.ClientStream
+
+	ctx context.Context //nolint:containedctx // the stream's own context
+}
+
+func (s *endingStream) Context() context.Context { return s.ctx }
+
+func TestTimeoutConn_StreamReleasesDeadlineWhenItEnds(t *testing.T) {
+	t.Parallel()
+
+	conn := &endingConn{}
+	tc := &timeoutConn{
+		ClientConnInterface: conn,
+		capability:          "picker",
+		plugins: config.Plugins{Timeouts: map[string]config.Duration{
+			"picker.Pick": config.Duration(time.Hour),
+		}},
+	}
+
+	_, err := tc.NewStream(t.Context(), &grpc.StreamDesc{ClientStreams: true}, "/swm.plugin.v1.Picker/Pick")
+	require.NoError(t, err)
+	require.NoError(t, conn.ctx.Err())
+
+	// The stream ends without RecvMsg returning an error.
+	conn.end()
+
+	require.Eventually(t, func() bool { return conn.ctx.Err() != nil }, time.Second, time.Millisecond,
+		"the deadline must be released with the stream, not an hour later")
+}
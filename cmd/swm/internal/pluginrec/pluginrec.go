@@ -0,0 +1,246 @@
+// Package pluginrec records the gRPC calls swm makes to its plugins, replays
+// such a recording in place of the plugins, and redacts recordings for bug
+// reports.
+//
+// A recording is a JSON Lines file holding one Call per line, in the order
+// the calls ended.
+package pluginrec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Call is a recorded call to a plugin: its requests and responses in
+// protojson, one each for unary calls, and the status it ended with.
+type Call struct {
+	// Plugin is the plugin's "<capability>-<name>", e.g. "vcs-git".
+	Plugin string `json:"plugin"`
+	// Method is the full gRPC method, e.g. "/swm.plugin.v1.VCS/CreateWorktree".
+	Method    string            `json:"method"`
+	Requests  []json.RawMessage `json:"requests,omitempty"`
+	Responses []json.RawMessage `json:"responses,omitempty"`
+	// Code is the gRPC status code the call failed with, e.g. "NotFound";
+	// empty when it succeeded.
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// err returns the error the call ended with.
+func (c *Call) err() error {
+	if c.Code == "" {
+		return nil
+	}
+
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		if code.String() == c.Code {
+			return status.Error(code, c.Message)
+		}
+	}
+
+	return status.Error(codes.Unknown, c.Message)
+}
+
+// Recorder writes the calls made through its connections to a recording.
+type Recorder struct {
+	mu      sync.Mutex
+	f       *os.File
+	enc     *json.Encoder
+	pending []*Call // streams that have not ended, in the order they started
+	err     error   // the first error writing the recording
+}
+
+// Create creates the recording at path, replacing any file there.
+func Create(path string) (*Recorder, error) {
+	f, err := os.Create(path) //nolint:gosec // the user chose the path
+	if err != nil {
+		return nil, fmt.Errorf("creating recording: %w", err)
+	}
+
+	return &Recorder{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Close writes the streams that have not ended yet, as they are so far, and
+// closes the recording. It returns the first error writing it.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		return r.err
+	}
+
+	for _, c := range r.pending {
+		r.write(c)
+	}
+
+	r.pending = nil
+
+	if err := r.f.Close(); err != nil && r.err == nil {
+		r.err = fmt.Errorf("closing recording: %w", err)
+	}
+
+	r.f = nil
+
+	return r.err
+}
+
+// Conn returns conn, a connection to plugin, recording the calls made
+// through it.
+func (r *Recorder) Conn(plugin string, conn grpc.ClientConnInterface) grpc.ClientConnInterface {
+	return &recordingConn{ClientConnInterface: conn, r: r, plugin: plugin}
+}
+
+// add appends the protojson of m to msgs.
+func (r *Recorder) add(msgs *[]json.RawMessage, m any) {
+	data := encode(m)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	*msgs = append(*msgs, data)
+}
+
+// end records that c ended with err.
+func (r *Recorder) end(c *Call, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i := slices.Index(r.pending, c); i >= 0 {
+		r.pending = slices.Delete(r.pending, i, i+1)
+	}
+
+	if err != nil {
+		s := status.Convert(err)
+		c.Code, c.Message = s.Code().String(), s.Message()
+	}
+
+	r.write(c)
+}
+
+// start records that the stream c started.
+func (r *Recorder) start(c *Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending = append(r.pending, c)
+}
+
+// write writes c to the recording. Must be called with r.mu held.
+func (r *Recorder) write(c *Call) {
+	if r.f == nil {
+		return // the stream outlived the recording
+	}
+
+	if err := r.enc.Encode(c); err != nil && r.err == nil {
+		r.err = fmt.Errorf("writing recording: %w", err)
+	}
+}
+
+// recordingConn records the calls to one plugin.
+type recordingConn struct {
+	grpc.ClientConnInterface
+
+	r      *Recorder
+	plugin string
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (c *recordingConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	err := c.ClientConnInterface.Invoke(ctx, method, args, reply, opts...)
+
+	call := &Call{Plugin: c.plugin, Method: method, Requests: []json.RawMessage{encode(args)}}
+	if err == nil {
+		call.Responses = []json.RawMessage{encode(reply)}
+	}
+
+	c.r.end(call, err)
+
+	return err
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (c *recordingConn) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	call := &Call{Plugin: c.plugin, Method: method}
+
+	stream, err := c.ClientConnInterface.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		c.r.end(call, err)
+
+		return nil, err
+	}
+
+	c.r.start(call)
+
+	return &recordingStream{ClientStream: stream, r: c.r, call: call, serverStreams: desc.ServerStreams}, nil
+}
+
+// recordingStream records the messages of a stream, and the stream once it
+// ends: on an error or io.EOF, or on its only response.
+type recordingStream struct {
+	grpc.ClientStream
+
+	r             *Recorder
+	call          *Call
+	serverStreams bool
+	once          sync.Once
+}
+
+// RecvMsg implements grpc.ClientStream.
+func (s *recordingStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	switch {
+	case err == nil:
+		s.r.add(&s.call.Responses, m)
+
+		if !s.serverStreams {
+			s.once.Do(func() { s.r.end(s.call, nil) })
+		}
+	case errors.Is(err, io.EOF):
+		s.once.Do(func() { s.r.end(s.call, nil) })
+	default:
+		s.once.Do(func() { s.r.end(s.call, err) })
+	}
+
+	return err
+}
+
+// SendMsg implements grpc.ClientStream.
+func (s *recordingStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.r.add(&s.call.Requests, m)
+	}
+
+	return err
+}
+
+// encode returns the protojson of m, or null when m is not a message.
+func encode(m any) json.RawMessage {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return json.RawMessage("null")
+	}
+
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+
+	return data
+}
@@ -0,0 +1,172 @@
+package pluginrec
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// redacted replaces the secrets in a redacted recording.
+const redacted = "REDACTED"
+
+var (
+	// secretKeyRe matches the field and map keys whose values are secrets,
+	// such as "apiToken" or an environment variable "GITHUB_TOKEN".
+	secretKeyRe = regexp.MustCompile(`(?i)token|secret|passw(or)?d|credential|api_?key|authorization`)
+
+	// tokenRe matches well-known token formats wherever they appear.
+	tokenRe = regexp.MustCompile(`\b(gh[opsur]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,}|` +
+		`glpat-[A-Za-z0-9_-]{20,}|xox[abpr]-[A-Za-z0-9-]{10,}|` +
+		`eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,})`)
+
+	// authHeaderRe matches the credentials of an HTTP Authorization header.
+	authHeaderRe = regexp.MustCompile(`(?i)\b(bearer|basic|token) [A-Za-z0-9._~+/=-]{8,}`)
+
+	// urlPasswordRe matches the password in the userinfo of a URL.
+	urlPasswordRe = regexp.MustCompile(`(://[^/@\s:]*):[^/@\s]+@`)
+)
+
+// Redact copies the recording in to out, replacing the paths below the
+// directories in paths with their placeholder, e.g. "/home/me" with
+// "$HOME", and secrets with REDACTED: the values of fields and map entries
+// named like secrets, well-known token formats, Authorization headers and
+// the passwords of URLs.
+func Redact(in io.Reader, out io.Writer, paths map[string]string) error {
+	calls, err := readCalls(in)
+	if err != nil {
+		return err
+	}
+
+	r := newRedactor(paths)
+	enc := json.NewEncoder(out)
+
+	for _, c := range calls {
+		if err := r.call(c); err != nil {
+			return err
+		}
+
+		if err := enc.Encode(c); err != nil {
+			return fmt.Errorf("writing redacted recording: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// pathRule replaces a directory, and the paths below it, with a placeholder.
+type pathRule struct {
+	dir         string
+	re          *regexp.Regexp
+	replacement string
+}
+
+// redactor redacts the calls of a recording.
+type redactor struct {
+	paths []pathRule // longest directory first, so that nested ones win
+}
+
+// newRedactor returns a redactor replacing the directories in paths.
+func newRedactor(paths map[string]string) *redactor {
+	r := &redactor{}
+
+	for dir, placeholder := range paths {
+		if dir = strings.TrimSuffix(dir, "/"); dir == "" {
+			continue
+		}
+
+		r.paths = append(r.paths, pathRule{
+			dir: dir,
+			// The directory itself, or followed by a path or anything but a
+			// file name character, which is kept.
+			re:          regexp.MustCompile(regexp.QuoteMeta(dir) + `(/|$|[^\w.-])`),
+			replacement: strings.ReplaceAll(placeholder, "$", "$$") + "${1}",
+		})
+	}
+
+	slices.SortFunc(r.paths, func(a, b pathRule) int {
+		return cmp.Or(len(b.dir)-len(a.dir), strings.Compare(a.dir, b.dir))
+	})
+
+	return r
+}
+
+// call redacts the messages and the error of c.
+func (r *redactor) call(c *Call) error {
+	for _, msgs := range [][]json.RawMessage{c.Requests, c.Responses} {
+		for i, msg := range msgs {
+			redactedMsg, err := r.message(msg)
+			if err != nil {
+				return fmt.Errorf("redacting %s %s: %w", c.Plugin, c.Method, err)
+			}
+
+			msgs[i] = redactedMsg
+		}
+	}
+
+	c.Message = r.string(c.Message)
+
+	return nil
+}
+
+// message redacts the protojson msg.
+func (r *redactor) message(msg json.RawMessage) (json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decoding message: %w", err)
+	}
+
+	data, err := json.Marshal(r.value(v))
+	if err != nil {
+		return nil, fmt.Errorf("encoding message: %w", err)
+	}
+
+	return data, nil
+}
+
+// string redacts the paths and the secrets in s.
+func (r *redactor) string(s string) string {
+	for _, rule := range r.paths {
+		s = rule.re.ReplaceAllString(s, rule.replacement)
+	}
+
+	s = tokenRe.ReplaceAllString(s, redacted)
+	s = authHeaderRe.ReplaceAllString(s, "$1 "+redacted)
+
+	return urlPasswordRe.ReplaceAllString(s, "$1:"+redacted+"@")
+}
+
+// value redacts the decoded JSON v.
+func (r *redactor) value(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, val := range v {
+			if s, ok := val.(string); ok && s != "" && secretKeyRe.MatchString(key) {
+				v[key] = redacted
+
+				continue
+			}
+
+			v[key] = r.value(val)
+		}
+
+		return v
+	case []any:
+		for i, val := range v {
+			v[i] = r.value(val)
+		}
+
+		return v
+	case string:
+		return r.string(v)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,240 @@
+package pluginrec
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxLine bounds a line of a recording, a call with all its messages.
+const maxLine = 64 << 20
+
+var errNotRecorded = errors.New("call not in the recording")
+
+// Replay answers calls to plugins from a recording, as a fake plugin running
+// in the host process.
+type Replay struct {
+	mu    sync.Mutex
+	calls []*Call // the calls not replayed yet, in recorded order
+}
+
+// Load reads the recording at path.
+func Load(path string) (*Replay, error) {
+	f, err := os.Open(path) //nolint:gosec // the user chose the path
+	if err != nil {
+		return nil, fmt.Errorf("opening recording: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // read-only
+
+	calls, err := readCalls(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading recording %s: %w", path, err)
+	}
+
+	return &Replay{calls: calls}, nil
+}
+
+// Conn returns a connection to plugin, "<capability>-<name>", that answers
+// calls with the recorded responses.
+func (r *Replay) Conn(plugin string) grpc.ClientConnInterface {
+	return &replayConn{r: r, plugin: plugin}
+}
+
+// take returns the next recorded call of plugin to method and removes it.
+// Of those, the first one recorded with the request req, if given, is
+// preferred; calls made concurrently may have been recorded in any order.
+func (r *Replay) take(plugin, method string, req proto.Message) (*Call, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := -1
+
+	for i, c := range r.calls {
+		if c.Plugin != plugin || c.Method != method {
+			continue
+		}
+
+		if found < 0 {
+			found = i
+		}
+
+		if req != nil && len(c.Requests) > 0 && decodesTo(c.Requests[0], req) {
+			found = i
+
+			break
+		}
+	}
+
+	if found < 0 {
+		return nil, fmt.Errorf("%w: %s %s", errNotRecorded, plugin, method)
+	}
+
+	c := r.calls[found]
+	r.calls = append(r.calls[:found], r.calls[found+1:]...)
+
+	return c, nil
+}
+
+// replayConn answers the calls to one plugin.
+type replayConn struct {
+	r      *Replay
+	plugin string
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (c *replayConn) Invoke(_ context.Context, method string, args, reply any, _ ...grpc.CallOption) error {
+	req, _ := args.(proto.Message)
+
+	call, err := c.r.take(c.plugin, method, req)
+	if err != nil {
+		return err
+	}
+
+	if err := call.err(); err != nil {
+		return err
+	}
+
+	if len(call.Responses) == 0 {
+		return fmt.Errorf("%w: %s %s has no response", errNotRecorded, c.plugin, method)
+	}
+
+	return decode(call.Responses[0], reply)
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (c *replayConn) NewStream(
+	ctx context.Context, _ *grpc.StreamDesc, method string, _ ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return &replayStream{ctx: ctx, conn: c, method: method}, nil
+}
+
+// replayStream answers a stream with its recorded responses. The recorded
+// call is chosen on the first RecvMsg, after the request of a server stream
+// was sent.
+type replayStream struct {
+	ctx    context.Context
+	conn   *replayConn
+	method string
+
+	mu    sync.Mutex
+	first proto.Message // the first request sent
+	call  *Call
+	next  int // the next response to return
+}
+
+// CloseSend implements grpc.ClientStream.
+func (s *replayStream) CloseSend() error {
+	return nil
+}
+
+// Context implements grpc.ClientStream.
+func (s *replayStream) Context() context.Context {
+	return s.ctx
+}
+
+// Header implements grpc.ClientStream.
+func (s *replayStream) Header() (metadata.MD, error) {
+	return metadata.MD{}, nil
+}
+
+// RecvMsg implements grpc.ClientStream.
+func (s *replayStream) RecvMsg(m any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.call == nil {
+		call, err := s.conn.r.take(s.conn.plugin, s.method, s.first)
+		if err != nil {
+			return err
+		}
+
+		s.call = call
+	}
+
+	if s.next < len(s.call.Responses) {
+		s.next++
+
+		return decode(s.call.Responses[s.next-1], m)
+	}
+
+	if err := s.call.err(); err != nil {
+		return err
+	}
+
+	return io.EOF
+}
+
+// SendMsg implements grpc.ClientStream.
+func (s *replayStream) SendMsg(m any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if msg, ok := m.(proto.Message); ok && s.first == nil {
+		s.first = msg
+	}
+
+	return nil
+}
+
+// Trailer implements grpc.ClientStream.
+func (s *replayStream) Trailer() metadata.MD {
+	return metadata.MD{}
+}
+
+// readCalls reads the calls of a recording.
+func readCalls(r io.Reader) ([]*Call, error) {
+	var calls []*Call
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLine)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var c Call
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return nil, fmt.Errorf("call %d: %w", len(calls)+1, err)
+		}
+
+		calls = append(calls, &c)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading calls: %w", err)
+	}
+
+	return calls, nil
+}
+
+// decode decodes the recorded message data into m.
+func decode(data json.RawMessage, m any) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%w: cannot decode into %T", errNotRecorded, m)
+	}
+
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("decoding recorded %T: %w", m, err)
+	}
+
+	return nil
+}
+
+// decodesTo reports whether the recorded message data equals msg.
+func decodesTo(data json.RawMessage, msg proto.Message) bool {
+	recorded := msg.ProtoReflect().New().Interface()
+
+	return decode(data, recorded) == nil && proto.Equal(recorded, msg)
+}
@@ -0,0 +1,65 @@
+// Package sandbox confines plugin processes with Landlock on Linux. A
+// sandboxed plugin may read anywhere but write only below the directories it
+// is given.
+//
+// swm cannot restrict another process, so Command runs swm itself, which
+// restricts itself in Init and then executes the plugin in its place.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// envWritable carries the writable directories to the swm process that
+// applies the sandbox, separated by os.PathListSeparator.
+const envWritable = "SWM_SANDBOX_WRITABLE"
+
+// ErrUnsupported is returned when this system cannot sandbox plugins.
+var ErrUnsupported = errors.New("plugin sandbox unavailable")
+
+// Command returns the command that runs binary sandboxed, able to write only
+// below writable. It fails with ErrUnsupported when the sandbox is not
+// available rather than run the plugin unconfined.
+func Command(binary string, writable []string) (*exec.Cmd, error) {
+	if err := Available(); err != nil {
+		return nil, err
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("finding the swm executable: %w", err)
+	}
+
+	cmd := exec.Command(self, binary) //nolint:gosec // self is swm, binary a discovered plugin
+	cmd.Env = []string{envWritable + "=" + strings.Join(writable, string(os.PathListSeparator))}
+
+	return cmd, nil
+}
+
+// Init sandboxes this process and executes the plugin when it was started by
+// Command, and returns at once otherwise. It must be called first in main,
+// before swm starts any goroutine.
+func Init() {
+	writable, ok := os.LookupEnv(envWritable)
+	if !ok || len(os.Args) < 2 { //nolint:mnd // swm and the plugin binary
+		return
+	}
+
+	binary := os.Args[1]
+
+	env := make([]string, 0, len(os.Environ()))
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, envWritable+"=") {
+			env = append(env, kv)
+		}
+	}
+
+	err := restrictAndExec(binary, strings.Split(writable, string(os.PathListSeparator)), env)
+
+	fmt.Fprintf(os.Stderr, "swm: sandboxing plugin %s: %v\n", binary, err)
+	os.Exit(1)
+}
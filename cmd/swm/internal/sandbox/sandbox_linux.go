@@ -0,0 +1,150 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// writeAccess is the write access of the first Landlock ABI; the sandbox
+// denies it outside the writable directories.
+const writeAccess = unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+	unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+	unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+	unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+	unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+	unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+	unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+	unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+
+// fileAccess is the part of the access that applies to a file rather than
+// a directory.
+const fileAccess = unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_TRUNCATE
+
+// Available reports why plugins cannot be sandboxed, or nil when they can.
+func Available() error {
+	_, err := abiVersion()
+
+	return err
+}
+
+// abiVersion returns the Landlock ABI version of the kernel.
+func abiVersion() (int, error) {
+	v, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+
+	switch {
+	case errno == unix.ENOSYS:
+		return 0, fmt.Errorf("%w: the kernel was built without Landlock (Linux 5.13 or later with "+
+			"CONFIG_SECURITY_LANDLOCK is needed)", ErrUnsupported)
+	case errno == unix.EOPNOTSUPP:
+		return 0, fmt.Errorf("%w: Landlock is disabled; add landlock to the kernel's lsm= boot "+
+			"parameter", ErrUnsupported)
+	case errno != 0:
+		return 0, fmt.Errorf("%w: checking Landlock: %w", ErrUnsupported, errno)
+	}
+
+	return int(v), nil
+}
+
+// restrictAndExec denies this thread writes outside writable, and executes
+// binary in its place, which keeps the restriction.
+func restrictAndExec(binary string, writable, env []string) error {
+	// Landlock restricts the calling thread, which then has to be the one
+	// executing the plugin.
+	runtime.LockOSThread()
+
+	if err := restrict(writable); err != nil {
+		return err
+	}
+
+	if err := unix.Exec(binary, []string{binary}, env); err != nil {
+		return fmt.Errorf("executing: %w", err)
+	}
+
+	return nil
+}
+
+// restrict denies the calling thread writes outside writable. Paths that do
+// not exist are skipped.
+func restrict(writable []string) error {
+	abi, err := abiVersion()
+	if err != nil {
+		return err
+	}
+
+	access := uint64(writeAccess)
+	if abi >= 2 { //nolint:mnd // ABI 2 added REFER
+		access |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+
+	if abi >= 3 { //nolint:mnd // ABI 3 added TRUNCATE
+		access |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+
+	attr := unix.LandlockRulesetAttr{Access_fs: access}
+
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("creating Landlock ruleset: %w", errno)
+	}
+	defer unix.Close(int(fd)) //nolint:errcheck // closing the ruleset cannot fail meaningfully
+
+	for _, path := range writable {
+		if path == "" {
+			continue
+		}
+
+		if err := allow(int(fd), path, access); err != nil {
+			return err
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("setting no_new_privs: %w", err)
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, fd, 0, 0); errno != 0 {
+		return fmt.Errorf("enforcing Landlock ruleset: %w", errno)
+	}
+
+	return nil
+}
+
+// allow adds a rule granting access below path to the ruleset.
+func allow(ruleset int, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.ENOENT) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer unix.Close(fd) //nolint:errcheck // closing an O_PATH descriptor cannot fail meaningfully
+
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return fmt.Errorf("stat %s: %w", path, err)
+	}
+
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= fileAccess
+	}
+
+	rule := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)} //nolint:gosec // fds fit
+
+	if _, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(ruleset),
+		unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&rule)), 0, 0, 0); errno != 0 {
+		return fmt.Errorf("allowing writes below %s: %w", path, errno)
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package sandbox
+
+import "fmt"
+
+// Available reports why plugins cannot be sandboxed: Landlock is Linux-only.
+func Available() error {
+	return fmt.Errorf("%w: Landlock is only available on Linux", ErrUnsupported)
+}
+
+// restrictAndExec is never reached: Command fails before starting swm.
+func restrictAndExec(_ string, _, _ []string) error {
+	return Available()
+}
@@ -0,0 +1,87 @@
+package sandbox_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/sandbox"
+)
+
+// envTestWrite makes the test binary, run as a sandboxed plugin, write the
+// file it names and exit.
+const envTestWrite = "SANDBOX_TEST_WRITE"
+
+// exitDenied is the exit code of the test binary when the write was denied.
+const exitDenied = 3
+
+func TestMain(m *testing.M) {
+	sandbox.Init()
+
+	if path := os.Getenv(envTestWrite); path != "" {
+		if err := os.WriteFile(path, []byte("x"), 0o600); err != nil {
+			if errors.Is(err, os.ErrPermission) {
+				os.Exit(exitDenied)
+			}
+
+			os.Exit(1)
+		}
+
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// runSandboxed runs the test binary sandboxed to writable, writing path, and
+// returns its exit code.
+func runSandboxed(t *testing.T, writable []string, path string) int {
+	t.Helper()
+
+	cmd, err := sandbox.Command(os.Args[0], writable)
+	require.NoError(t, err)
+
+	cmd.Env = append(cmd.Env, envTestWrite+"="+path)
+
+	out, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	require.NoError(t, err, string(out))
+
+	return 0
+}
+
+func TestCommand_RestrictsWrites(t *testing.T) {
+	t.Parallel()
+
+	if err := sandbox.Available(); err != nil {
+		t.Skip(err)
+	}
+
+	allowed, denied := t.TempDir(), t.TempDir()
+
+	require.Equal(t, 0, runSandboxed(t, []string{allowed}, filepath.Join(allowed, "file")))
+	require.FileExists(t, filepath.Join(allowed, "file"))
+
+	require.Equal(t, exitDenied, runSandboxed(t, []string{allowed}, filepath.Join(denied, "file")))
+	require.NoFileExists(t, filepath.Join(denied, "file"))
+}
+
+func TestCommand_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	if sandbox.Available() == nil {
+		t.Skip("the sandbox is available")
+	}
+
+	_, err := sandbox.Command(os.Args[0], nil)
+	require.ErrorIs(t, err, sandbox.ErrUnsupported)
+}
@@ -0,0 +1,197 @@
+// Package semver parses and compares the semantic versions plugins report in
+// their Info response and declare as requirements on the host and on each
+// other.
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalid is returned when a string is not a semantic version.
+var ErrInvalid = errors.New("invalid semantic version")
+
+// Version is a parsed semantic version. Build metadata is dropped since it
+// does not take part in precedence.
+type Version struct {
+	Major, Minor, Patch uint64
+	Pre                 []string
+}
+
+// Parse parses s as a semantic version. A leading "v" is accepted, as are the
+// shorthands "1" and "1.2" for "1.0.0" and "1.2.0".
+func Parse(s string) (Version, error) {
+	var v Version
+
+	rest := strings.TrimPrefix(s, "v")
+
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		if !validIdentifiers(rest[i+1:], false) {
+			return Version{}, fmt.Errorf("%w: %q", ErrInvalid, s)
+		}
+
+		rest = rest[:i]
+	}
+
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		if !validIdentifiers(rest[i+1:], true) {
+			return Version{}, fmt.Errorf("%w: %q", ErrInvalid, s)
+		}
+
+		v.Pre = strings.Split(rest[i+1:], ".")
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) > 3 { //nolint:mnd // major.minor.patch
+		return Version{}, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+
+	nums := []*uint64{&v.Major, &v.Minor, &v.Patch}
+
+	for i, p := range parts {
+		n, ok := number(p)
+		if !ok {
+			return Version{}, fmt.Errorf("%w: %q", ErrInvalid, s)
+		}
+
+		*nums[i] = n
+	}
+
+	return v, nil
+}
+
+// AtLeast reports whether version is at least minimum. Both must be semantic
+// versions.
+func AtLeast(version, minimum string) (bool, error) {
+	v, err := Parse(version)
+	if err != nil {
+		return false, err
+	}
+
+	lo, err := Parse(minimum)
+	if err != nil {
+		return false, err
+	}
+
+	return v.Compare(lo) >= 0, nil
+}
+
+// Compare returns -1, 0 or 1 when v precedes, equals or follows o. A
+// pre-release precedes the release it leads up to: 2.0.0-rc.1 < 2.0.0.
+func (v Version) Compare(o Version) int {
+	for _, c := range [][2]uint64{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if c[0] != c[1] {
+			if c[0] < c[1] {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	switch {
+	case len(v.Pre) == 0 && len(o.Pre) == 0:
+		return 0
+	case len(v.Pre) == 0:
+		return 1
+	case len(o.Pre) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.Pre) && i < len(o.Pre); i++ {
+		if c := comparePre(v.Pre[i], o.Pre[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(v.Pre) < len(o.Pre):
+		return -1
+	case len(v.Pre) > len(o.Pre):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// String renders v without a leading "v".
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Pre) > 0 {
+		s += "-" + strings.Join(v.Pre, ".")
+	}
+
+	return s
+}
+
+// comparePre compares two pre-release identifiers: numeric identifiers
+// compare numerically and precede alphanumeric ones, which compare in ASCII
+// order.
+func comparePre(a, b string) int {
+	an, aNum := number(a)
+	bn, bNum := number(b)
+
+	switch {
+	case aNum && bNum:
+		switch {
+		case an < bn:
+			return -1
+		case an > bn:
+			return 1
+		default:
+			return 0
+		}
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// number parses s as a numeric identifier: digits without a leading zero.
+func number(s string) (uint64, bool) {
+	if s == "" || (len(s) > 1 && s[0] == '0') {
+		return 0, false
+	}
+
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// validIdentifiers reports whether s is a dot-separated list of non-empty
+// identifiers of [0-9A-Za-z-]. Numeric pre-release identifiers must not have
+// leading zeros.
+func validIdentifiers(s string, pre bool) bool {
+	for id := range strings.SplitSeq(s, ".") {
+		if id == "" {
+			return false
+		}
+
+		digits := true
+
+		for _, r := range id {
+			switch {
+			case r >= '0' && r <= '9':
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '-':
+				digits = false
+			default:
+				return false
+			}
+		}
+
+		if pre && digits && len(id) > 1 && id[0] == '0' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,87 @@
+package semver_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalbasit/swm/cmd/swm/internal/semver"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"1.2.3", "1.2.3"},
+		{"v1.2.3", "1.2.3"},
+		{"v2", "2.0.0"},
+		{"1.4", "1.4.0"},
+		{"v2.0.0-dev", "2.0.0-dev"},
+		{"1.0.0-rc.1+build.5", "1.0.0-rc.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			v, err := semver.Parse(tt.in)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, v.String())
+		})
+	}
+
+	for _, in := range []string{"", "v", "1.2.3.4", "01.2.3", "1.x.3", "1.2.3-", "1.2.3-01", "1.2.3-a..b", "1.2.3+"} {
+		_, err := semver.Parse(in)
+		require.ErrorIs(t, err, semver.ErrInvalid, in)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	// Each version precedes the next.
+	ordered := []string{
+		"0.9.9",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+
+	for i := range len(ordered) - 1 {
+		a, err := semver.Parse(ordered[i])
+		require.NoError(t, err)
+
+		b, err := semver.Parse(ordered[i+1])
+		require.NoError(t, err)
+
+		require.Equal(t, -1, a.Compare(b), "%s < %s", a, b)
+		require.Equal(t, 1, b.Compare(a), "%s > %s", b, a)
+		require.Equal(t, 0, a.Compare(a))
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	t.Parallel()
+
+	ok, err := semver.AtLeast("v1.3.0", "1.2")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = semver.AtLeast("v2.0.0-dev", "2.0.0")
+	require.NoError(t, err)
+	require.False(t, ok, "a pre-release precedes its release")
+
+	_, err = semver.AtLeast("dev", "1.0.0")
+	require.ErrorIs(t, err, semver.ErrInvalid)
+}
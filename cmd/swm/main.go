@@ -2,10 +2,13 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 
 	"github.com/adrg/xdg"
 
@@ -13,20 +16,58 @@ import (
 	"github.com/kalbasit/swm/cmd/swm/internal/cli/workspace"
 	"github.com/kalbasit/swm/cmd/swm/internal/config"
 	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
+	"github.com/kalbasit/swm/cmd/swm/internal/core/ports"
 	"github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	"github.com/kalbasit/swm/cmd/swm/internal/daemon"
 	"github.com/kalbasit/swm/cmd/swm/internal/hostsvc"
 	"github.com/kalbasit/swm/cmd/swm/internal/pluginmgr"
+	"github.com/kalbasit/swm/cmd/swm/internal/pluginrec"
+	"github.com/kalbasit/swm/cmd/swm/internal/sandbox"
+	"github.com/kalbasit/swm/sdk/go/trace"
 )
 
 var version = "v2.0.0-dev"
 
 func main() {
+	// A sandboxed plugin is started through swm, which sandboxes itself here
+	// and executes the plugin.
+	sandbox.Init()
+
+	os.Exit(run())
+}
+
+// run runs swm and returns its exit code. It returns rather than exits so
+// that the plugins are stopped on the way out.
+func run() int {
+	// Ctrl-C and SIGTERM cancel the plugin calls in flight, and the plugins
+	// stop the commands they run for them.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// With --trace or $SWM_TRACE, record the run and write the trace once
+	// everything else has stopped.
+	if path := cli.TracePath(os.Args[1:]); path != "" {
+		rec := trace.NewRecorder("swm")
+		ctx = trace.WithRecorder(ctx, rec)
+
+		defer func() {
+			if err := rec.WriteFile(path); err != nil {
+				fmt.Fprintf(os.Stderr, "swm: %v\n", err)
+			}
+		}()
+	}
+
 	cfgPath := config.ResolveConfigPath(os.Getenv("SWM_CONFIG"), xdg.ConfigHome)
 
+	_, span := trace.Start(ctx, "load config", "path", cfgPath)
 	cfg, err := config.Load(cfgPath)
+
+	span.End()
+
 	if err != nil && !errors.Is(err, config.ErrConfigNotFound) {
 		fmt.Fprintf(os.Stderr, "swm: loading config: %v\n", err)
-		os.Exit(1)
+
+		return 1
 	}
 
 	if cfg == nil {
@@ -34,24 +75,107 @@ func main() {
 	}
 
 	storiesDir := filepath.Join(xdg.DataHome, "swm", "stories")
-	store := story.NewJSONStore(storiesDir)
+	store := ports.NewStore(story.NewJSONStore(storiesDir), cfg.Ports.Base, cfg.Ports.BlockSize)
 	resolver := layout.NewResolver(cfg.CodeRoot, cfg.DefaultStory)
 
-	hostSrv, err := hostsvc.NewServer(cfg, resolver, store)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "swm: starting host service: %v\n", err)
-		os.Exit(1)
+	var (
+		mgr    *pluginmgr.Manager
+		lister workspace.ProjectLister
+		client *daemon.Client
+	)
+
+	mgrOpts := []pluginmgr.Option{pluginmgr.WithHostVersion(version)}
+
+	// $SWM_RECORD records the calls to plugins to a file, and $SWM_REPLAY
+	// answers them from such a recording instead of running the plugins.
+	if path := os.Getenv("SWM_RECORD"); path != "" {
+		rec, err := pluginrec.Create(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "swm: %v\n", err)
+
+			return 1
+		}
+
+		defer func() {
+			if err := rec.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "swm: %v\n", err)
+			}
+		}()
+
+		mgrOpts = append(mgrOpts, pluginmgr.WithRecorder(rec))
+	}
+
+	replay := os.Getenv("SWM_REPLAY")
+	if replay != "" {
+		rp, err := pluginrec.Load(replay)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "swm: %v\n", err)
+
+			return 1
+		}
+
+		mgrOpts = append(mgrOpts, pluginmgr.WithReplay(rp))
 	}
-	defer hostSrv.Stop()
 
-	mgr := pluginmgr.New(cfg, hostSrv.SocketPath())
-	defer mgr.Close() //nolint:errcheck // best-effort close on exit
+	// Forward to a running swm daemon of this version and config; without
+	// one, run the Host service and the plugins in this process. A replay
+	// stands in for the plugins, daemon or not.
+	err = daemon.ErrNotRunning
 
-	root := cli.NewRootCmd(cfgPath, cfg, mgr, store, resolver, workspace.WithProjectLister(hostSrv))
+	if replay == "" {
+		connectCtx, span := trace.Start(ctx, "connect daemon")
+		client, err = daemon.Connect(connectCtx, daemon.SocketPath(), cfgPath, version)
+
+		span.End()
+	}
+
+	if err == nil {
+		defer client.Close() //nolint:errcheck // best-effort close on exit
+
+		mgr = pluginmgr.New(cfg, client.HostSocket(), append(mgrOpts, pluginmgr.WithRemote(client))...)
+		lister = client
+	} else {
+		_, span := trace.Start(ctx, "start host service")
+		hostSrv, err := hostsvc.NewServer(cfg, resolver, store)
+
+		span.End()
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "swm: starting host service: %v\n", err)
+
+			return 1
+		}
+		defer hostSrv.Stop()
+
+		mgr = pluginmgr.New(cfg, hostSrv.SocketPath(), mgrOpts...)
+		hostSrv.SetPlugins(mgr)
+
+		lister = hostSrv
+	}
+
+	defer func() {
+		_, span := trace.Start(ctx, "stop plugins")
+		defer span.End()
+
+		mgr.Close() //nolint:errcheck,gosec // best-effort close on exit
+	}()
+
+	root := cli.NewRootCmd(cfgPath, cfg, mgr, store, resolver, workspace.WithProjectLister(lister))
 	root.Version = version
 
-	if err := root.Execute(); err != nil {
+	name := root.Name()
+	if cmd, _, err := root.Find(os.Args[1:]); err == nil {
+		name = cmd.CommandPath()
+	}
+
+	runCtx, span := trace.Start(ctx, name)
+	defer span.End()
+
+	if err := root.ExecuteContext(runCtx); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
+
+		return 1
 	}
+
+	return 0
 }
@@ -2,6 +2,7 @@ package integration_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -17,15 +18,20 @@ import (
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
 	"github.com/kalbasit/swm/cmd/swm/internal/cli"
+	"github.com/kalbasit/swm/cmd/swm/internal/cli/workspace"
 	"github.com/kalbasit/swm/cmd/swm/internal/config"
 	"github.com/kalbasit/swm/cmd/swm/internal/core/layout"
 	"github.com/kalbasit/swm/cmd/swm/internal/core/story"
+	"github.com/kalbasit/swm/cmd/swm/internal/daemon"
 	"github.com/kalbasit/swm/cmd/swm/internal/hostsvc"
 	"github.com/kalbasit/swm/cmd/swm/internal/pluginmgr"
 )
 
 const pickerPluginName = "fzf"
 
+// daemonVersion is the swm version the daemon tests run and connect as.
+const daemonVersion = "v2.0.0-dev"
+
 const (
 	vcsPluginName     = "git"
 	sessionPluginName = "tmux"
@@ -54,7 +60,7 @@ func setupEnv(t *testing.T) (*config.Config, *layout.Resolver, story.Store, *plu
 		CodeRoot:     codeRoot,
 		DefaultStory: testDefaultStory,
 		Plugins: config.Plugins{
-			VCS:     vcsPluginName,
+			VCS:     config.Names{vcsPluginName},
 			Session: sessionPluginName,
 			Paths: map[string]string{
 				vcsPluginName:     vcsGitBin,
@@ -283,7 +289,7 @@ func TestWorkspaceOpenWithPicker(t *testing.T) {
 		CodeRoot:     codeRoot,
 		DefaultStory: testDefaultStory,
 		Plugins: config.Plugins{
-			VCS:     vcsPluginName,
+			VCS:     config.Names{vcsPluginName},
 			Session: sessionPluginName,
 			Picker:  pickerPluginName,
 			Paths: map[string]string{
@@ -355,7 +361,7 @@ func setupPickerEnv(t *testing.T) (*config.Config, *layout.Resolver, story.Store
 		CodeRoot:     codeRoot,
 		DefaultStory: testDefaultStory,
 		Plugins: config.Plugins{
-			VCS:     vcsPluginName,
+			VCS:     config.Names{vcsPluginName},
 			Session: sessionPluginName,
 			Picker:  pickerPluginName,
 			Paths: map[string]string{
@@ -615,7 +621,7 @@ func TestPRListAndCreate(t *testing.T) {
 		CodeRoot:     codeRoot,
 		DefaultStory: testDefaultStory,
 		Plugins: config.Plugins{
-			VCS:    vcsPluginName,
+			VCS:    config.Names{vcsPluginName},
 			Forges: []string{forgePluginName},
 			Paths: map[string]string{
 				vcsPluginName:   vcsGitBin,
@@ -698,3 +704,87 @@ func TestHooksRunOnStoryCreate(t *testing.T) {
 
 	require.FileExists(t, sentinelFile, "expected pre-story-create hook to create sentinel file")
 }
+
+func TestDaemonForwardsVCS(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.toml")
+	socketPath := filepath.Join(dir, "daemon.sock")
+
+	cfg := &config.Config{
+		CodeRoot:     t.TempDir(),
+		DefaultStory: testDefaultStory,
+		Plugins: config.Plugins{
+			VCS:   config.Names{vcsPluginName},
+			Paths: map[string]string{vcsPluginName: vcsGitBin},
+		},
+	}
+	require.NoError(t, config.Save(cfgPath, cfg))
+
+	store := story.NewJSONStore(t.TempDir())
+	resolver := layout.NewResolver(cfg.CodeRoot, testDefaultStory)
+
+	srv, err := daemon.New(cfgPath, daemonVersion, store, daemon.WithSocketPath(socketPath))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	exited := make(chan struct{})
+
+	go func() {
+		srv.Run(ctx) //nolint:errcheck,gosec // stopped by the test cleanup
+
+		close(exited)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-exited
+	})
+
+	// Each invocation connects anew and closes its manager, as the CLI does;
+	// the daemon keeps the plugin running in between.
+	invoke := func(args ...string) {
+		client, err := daemon.Connect(t.Context(), socketPath, cfgPath, daemonVersion)
+		require.NoError(t, err)
+
+		defer client.Close() //nolint:errcheck // best-effort close in test
+
+		mgr := pluginmgr.New(cfg, client.HostSocket(),
+			pluginmgr.WithHostVersion(daemonVersion), pluginmgr.WithRemote(client))
+		defer mgr.Close() //nolint:errcheck // best-effort close in test
+
+		root := cli.NewRootCmd(cfgPath, cfg, mgr, store, resolver, workspace.WithProjectLister(client))
+		root.SetArgs(args)
+		require.NoError(t, root.Execute())
+	}
+
+	fileURL := "file://" + initLocalRepo(t)
+	invoke(cmdClone, fileURL)
+
+	pid := fileURLtoProjectID(fileURL)
+	require.DirExists(t, filepath.Join(resolver.CanonicalPath(pid), ".git"))
+
+	client, err := daemon.Connect(t.Context(), socketPath, cfgPath, daemonVersion)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	// The clone invalidated the daemon's scan cache.
+	projects, err := client.Projects(t.Context())
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	require.Equal(t, pid.GetSegments(), projects[0].GetSegments())
+
+	// A second invocation reuses the plugin the first one launched.
+	invoke(cmdClone, "file://"+initLocalRepo(t))
+
+	st, err := client.Status(t.Context())
+	require.NoError(t, err)
+	require.Len(t, st.GetPlugins(), 1)
+	require.Equal(t, "vcs", st.GetPlugins()[0].GetCapability())
+	require.Equal(t, vcsPluginName, st.GetPlugins()[0].GetName())
+
+	projects, err = client.Projects(t.Context())
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+}
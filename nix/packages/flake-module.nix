@@ -5,6 +5,7 @@
     ./swm-plugin-forge-github
     ./swm-plugin-picker-fzf
     ./swm-plugin-session-tmux
+    ./swm-plugin-tracker-jira
     ./swm-plugin-vcs-git
     ./swm-full
     ./swm-test-faketmux
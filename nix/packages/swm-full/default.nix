@@ -9,6 +9,7 @@ _: {
           config.packages.swm-plugin-forge-github
           config.packages.swm-plugin-picker-fzf
           config.packages.swm-plugin-session-tmux
+          config.packages.swm-plugin-tracker-jira
           config.packages.swm-plugin-vcs-git
         ];
         meta = {
@@ -12,7 +12,7 @@
             in
             if tag != "" then tag else rev;
 
-          vendorHash = "sha256-8XiPF+Ruaeoiqxv8wSNEor8r+TO2wuqFym5WqRDGnFg=";
+          vendorHash = "sha256-ERksWrrvnfFof10uVGA1/aYpDUcyOgaScIH3tWDJEJs=";
         in
         pkgs.buildGoModule {
           inherit version vendorHash;
@@ -12,7 +12,7 @@
             in
             if tag != "" then tag else rev;
 
-          vendorHash = "sha256-cWFlVehqRE7YC6AGdAlblk6ggms6yX7ek9toGoapMqA=";
+          vendorHash = "sha256-3nQpGrsz7QcfICPaMK+SACO+xIzDdwnKQqPN9DO6uBo=";
         in
         pkgs.buildGoModule {
           inherit version vendorHash;
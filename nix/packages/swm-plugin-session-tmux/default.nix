@@ -12,7 +12,7 @@
             in
             if tag != "" then tag else rev;
 
-          vendorHash = "sha256-lFbZ0rzin/MhejU0f2gGkGD73XhakTBoM0NByZS58/c=";
+          vendorHash = "sha256-tMPqtyRQpVkITt7tCJy6VQx9iv9QzC0eSlv00k0GHfo=";
         in
         pkgs.buildGoModule {
           inherit version vendorHash;
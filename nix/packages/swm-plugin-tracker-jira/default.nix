@@ -0,0 +1,47 @@
+{ self, ... }:
+{
+  perSystem =
+    { lib, pkgs, ... }:
+    {
+      packages.swm-plugin-tracker-jira =
+        let
+          version =
+            let
+              rev = self.rev or self.dirtyRev;
+              tag = lib.trim (builtins.readFile ./version.txt);
+            in
+            if tag != "" then tag else rev;
+
+          vendorHash = "sha256-kos4p6lsty7b6j5K2eX9oFJuBDQ/A2RR6M8BGTWy7sY=";
+        in
+        pkgs.buildGoModule {
+          inherit version vendorHash;
+
+          pname = "swm-plugin-tracker-jira";
+          modRoot = "plugins/tracker-jira";
+
+          src = lib.fileset.toSource {
+            root = ../../..;
+            fileset = lib.fileset.unions [
+              ../../../plugins/tracker-jira
+              ../../../proto
+              ../../../sdk/go
+            ];
+          };
+
+          doCheck = true;
+
+          postInstall = ''
+            mv "$out/bin/tracker-jira" "$out/bin/swm-plugin-tracker-jira"
+          '';
+
+          meta = {
+            description = "swm Jira issue tracker plugin";
+            homepage = "https://github.com/kalbasit/swm";
+            license = lib.licenses.mit;
+            mainProgram = "swm-plugin-tracker-jira";
+            maintainers = [ lib.maintainers.kalbasit ];
+          };
+        };
+    };
+}
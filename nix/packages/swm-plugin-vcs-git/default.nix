@@ -12,7 +12,7 @@
             in
             if tag != "" then tag else rev;
 
-          vendorHash = "sha256-KxQxkZDUvJq7dSbmkWhkTj1T0lXTMLUq1lTdFQHLaYY=";
+          vendorHash = "sha256-KO4RUAbR0DcwLJ2JOmF+aZEaKI1pNawBewL4gEkVNK4=";
         in
         pkgs.buildGoModule {
           inherit version vendorHash;
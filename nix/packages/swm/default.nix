@@ -12,7 +12,7 @@
             in
             if tag != "" then tag else rev;
 
-          vendorHash = "sha256-Fiy7NJ6cWcV4arcJKXZ4UTsDPHTiFxtKsa9jiub96ho=";
+          vendorHash = "sha256-dyjB9pAYRAnjxkWzcy2uWv+ELoyAIJeQll4imXlmTIY=";
         in
         pkgs.buildGoModule {
           inherit version vendorHash;
@@ -28,6 +28,11 @@
               (lib.fileset.difference ../../../cmd/swm ../../../cmd/swm/tests/integration)
               ../../../proto
               ../../../sdk/go
+              # The plugins swm runs in-process when plugins.bundled is set.
+              ../../../plugins/forge-github
+              ../../../plugins/picker-fzf
+              ../../../plugins/session-tmux
+              ../../../plugins/vcs-git
             ];
           };
 
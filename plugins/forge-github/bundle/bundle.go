@@ -0,0 +1,15 @@
+// Package bundle lets swm run the GitHub forge plugin in its own process
+// when swm-plugin-forge-github is not installed.
+package bundle
+
+import (
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/plugins/forge-github/internal/forge"
+)
+
+// New returns the plugin as its binary serves it, calling the Host service
+// through host.
+func New(host pluginv1.HostClient) pluginv1.ForgeServer {
+	return forge.New(host)
+}
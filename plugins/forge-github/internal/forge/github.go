@@ -1,4 +1,5 @@
-// Package forge implements the swm Forge capability for github.com.
+// Package forge implements the swm Forge capability for github.com and
+// GitHub Enterprise Server.
 package forge
 
 import (
@@ -7,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -19,6 +21,8 @@ import (
 	"google.golang.org/grpc/status"
 
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/sdk/go/command"
 )
 
 // buildVersion is set via -ldflags at build time.
@@ -26,6 +30,9 @@ var buildVersion = "dev" //nolint:gochecknoglobals // set via ldflags at link ti
 
 var errGHAuthTokenEmpty = errors.New("gh auth token: empty output")
 
+// publicHost is the host of the public GitHub, served by the default API URL.
+const publicHost = "github.com"
+
 type githubConfig struct {
 	TokenPath string `toml:"token_path"`
 }
@@ -33,8 +40,9 @@ type githubConfig struct {
 // Option configures a GitHub forge server.
 type Option func(*GitHub)
 
-// WithGHTokenFn overrides the function used to obtain a token via the gh CLI.
-func WithGHTokenFn(fn func(ctx context.Context) (string, error)) Option {
+// WithGHTokenFn overrides the function used to obtain a token for a host via
+// the gh CLI.
+func WithGHTokenFn(fn func(ctx context.Context, host string) (string, error)) Option {
 	return func(g *GitHub) {
 		g.ghTokenFn = fn
 	}
@@ -47,11 +55,13 @@ func WithUserHomeDirFn(fn func() (string, error)) Option {
 	}
 }
 
-// GitHub implements pluginv1.ForgeServer for github.com.
+// GitHub implements pluginv1.ForgeServer for github.com and GitHub
+// Enterprise Server. Requests for a host other than github.com go to its
+// /api/v3 endpoint, or to the API URL of the request's endpoint.
 type GitHub struct {
 	pluginv1.UnimplementedForgeServer
 	hostClient    pluginv1.HostClient
-	ghTokenFn     func(ctx context.Context) (string, error)
+	ghTokenFn     func(ctx context.Context, host string) (string, error)
 	userHomeDirFn func() (string, error)
 	// baseURL, when non-empty, overrides the GitHub API base URL (for tests).
 	baseURL string
@@ -97,7 +107,7 @@ func ownerRepo(id *pluginv1.ProjectID) (owner, repo string, err error) {
 
 // CreatePullRequest opens a new pull request and returns the created PR.
 func (g *GitHub) CreatePullRequest(ctx context.Context, req *pluginv1.CreatePRRequest) (*pluginv1.PullRequest, error) {
-	client, err := g.newGitHubClient(ctx)
+	client, err := g.newGitHubClient(ctx, req.GetProjectId().GetHost(), req.GetEndpoint())
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +139,7 @@ func (g *GitHub) CreatePullRequest(ctx context.Context, req *pluginv1.CreatePRRe
 
 // GetPullRequest fetches a single pull request by number.
 func (g *GitHub) GetPullRequest(ctx context.Context, req *pluginv1.GetPRRequest) (*pluginv1.PullRequest, error) {
-	client, err := g.newGitHubClient(ctx)
+	client, err := g.newGitHubClient(ctx, req.GetProjectId().GetHost(), req.GetEndpoint())
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +168,7 @@ func (g *GitHub) Info(_ context.Context, _ *pluginv1.Empty) (*pluginv1.ForgeInfo
 			Name:    "github",
 			Version: buildVersion,
 		},
-		ClaimedHosts: []string{"github.com"},
+		ClaimedHosts: []string{publicHost},
 	}, nil
 }
 
@@ -166,7 +176,7 @@ func (g *GitHub) Info(_ context.Context, _ *pluginv1.Empty) (*pluginv1.ForgeInfo
 func (g *GitHub) ListPullRequests(req *pluginv1.ListPRsRequest, stream pluginv1.Forge_ListPullRequestsServer) error {
 	ctx := stream.Context()
 
-	client, err := g.newGitHubClient(ctx)
+	client, err := g.newGitHubClient(ctx, req.GetProjectId().GetHost(), req.GetEndpoint())
 	if err != nil {
 		return err
 	}
@@ -187,13 +197,27 @@ func (g *GitHub) ListPullRequests(req *pluginv1.ListPRsRequest, stream pluginv1.
 		state = "open"
 	}
 
-	prs, _, err := client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{State: state})
+	opts := &github.PullRequestListOptions{State: state}
+	if req.GetHeadBranch() != "" {
+		opts.Head = owner + ":" + req.GetHeadBranch()
+	}
+
+	prs, _, err := client.PullRequests.List(ctx, owner, repo, opts)
 	if err != nil {
 		return fmt.Errorf("listing pull requests: %w", err)
 	}
 
 	for _, pr := range prs {
-		if err := stream.Send(ghPRToProto(pr)); err != nil {
+		msg := ghPRToProto(pr)
+
+		if req.GetIncludeChecks() {
+			msg.CheckState, err = checkState(ctx, client, owner, repo, pr.GetHead().GetSHA())
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := stream.Send(msg); err != nil {
 			return fmt.Errorf("sending pull request: %w", err)
 		}
 	}
@@ -201,6 +225,64 @@ func (g *GitHub) ListPullRequests(req *pluginv1.ListPRsRequest, stream pluginv1.
 	return nil
 }
 
+// checkState combines the check runs and commit statuses reported for ref
+// into a single CheckState: any failure wins, then anything still pending,
+// then success. A ref with no checks at all is UNSPECIFIED.
+func checkState(ctx context.Context, client *github.Client, owner, repo, ref string) (pluginv1.CheckState, error) {
+	if ref == "" {
+		return pluginv1.CheckState_CHECK_STATE_UNSPECIFIED, nil
+	}
+
+	var pending, success bool
+
+	runs, _, err := client.Checks.ListCheckRunsForRef(ctx, owner, repo, ref, nil)
+	if err != nil {
+		return pluginv1.CheckState_CHECK_STATE_UNSPECIFIED, fmt.Errorf("listing check runs for %s: %w", ref, err)
+	}
+
+	for _, run := range runs.CheckRuns {
+		if run.GetStatus() != "completed" {
+			pending = true
+
+			continue
+		}
+
+		switch run.GetConclusion() {
+		case "success", "neutral", "skipped":
+			success = true
+		default:
+			return pluginv1.CheckState_CHECK_STATE_FAILURE, nil
+		}
+	}
+
+	combined, _, err := client.Repositories.GetCombinedStatus(ctx, owner, repo, ref, nil)
+	if err != nil {
+		return pluginv1.CheckState_CHECK_STATE_UNSPECIFIED, fmt.Errorf("getting commit status for %s: %w", ref, err)
+	}
+
+	// GitHub reports "pending" for a commit without any statuses, so only
+	// trust the combined state when at least one status exists.
+	if combined.GetTotalCount() > 0 {
+		switch combined.GetState() {
+		case "failure", "error":
+			return pluginv1.CheckState_CHECK_STATE_FAILURE, nil
+		case "pending":
+			pending = true
+		case "success":
+			success = true
+		}
+	}
+
+	switch {
+	case pending:
+		return pluginv1.CheckState_CHECK_STATE_PENDING, nil
+	case success:
+		return pluginv1.CheckState_CHECK_STATE_SUCCESS, nil
+	default:
+		return pluginv1.CheckState_CHECK_STATE_UNSPECIFIED, nil
+	}
+}
+
 // expandPath expands a leading ~/ using userHomeDirFn.
 func (g *GitHub) expandPath(path string) (string, error) {
 	if strings.HasPrefix(path, "~/") {
@@ -215,9 +297,12 @@ func (g *GitHub) expandPath(path string) (string, error) {
 	return path, nil
 }
 
-// newGitHubClient returns a GitHub API client authenticated with the user's token.
-func (g *GitHub) newGitHubClient(ctx context.Context) (*github.Client, error) {
-	token, err := g.tokenFromConfig(ctx)
+// newGitHubClient returns a GitHub API client for host, authenticated with
+// the user's token for it.
+func (g *GitHub) newGitHubClient(
+	ctx context.Context, host string, endpoint *pluginv1.ForgeEndpoint,
+) (*github.Client, error) {
+	token, err := g.tokenFromConfig(ctx, host, endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -230,10 +315,25 @@ func (g *GitHub) newGitHubClient(ctx context.Context) (*github.Client, error) {
 		baseURL = os.Getenv("FORGE_GITHUB_API_URL")
 	}
 
-	if baseURL != "" {
+	switch {
+	case endpoint.GetApiUrl() != "":
+		apiURL, uploadURL, err := enterpriseURLs(endpoint.GetApiUrl())
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, github.WithURLs(&apiURL, &uploadURL))
+	case baseURL != "":
 		// WithURLs sets the base URL verbatim (no /api/v3/ enterprise munging),
 		// matching the behavior tests and integration runs rely on.
 		opts = append(opts, github.WithURLs(&baseURL, nil))
+	case host != "" && host != publicHost:
+		apiURL, uploadURL, err := enterpriseURLs("https://" + host)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, github.WithURLs(&apiURL, &uploadURL))
 	}
 
 	client, err := github.NewClient(opts...)
@@ -244,15 +344,45 @@ func (g *GitHub) newGitHubClient(ctx context.Context) (*github.Client, error) {
 	return client, nil
 }
 
-// ghAuthToken retrieves a GitHub token via the gh CLI.
-func ghAuthToken(ctx context.Context) (string, error) {
+// enterpriseURLs returns the API and upload URLs of the GitHub Enterprise
+// Server at base, its web root or its API URL: base with /api/v3/ and
+// /api/uploads/ added unless it already ends in /api/v3 or is api.github.com.
+// A server whose own name starts with "api." is still given /api/v3/.
+func enterpriseURLs(base string) (string, string, error) {
+	u, err := url.Parse(base)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", "", status.Errorf(codes.InvalidArgument, "invalid GitHub API URL %q", base)
+	}
+
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+
+	if u.Hostname() == "api."+publicHost || strings.HasSuffix(u.Path, "/api/v3/") {
+		return u.String(), u.String(), nil
+	}
+
+	upload := *u
+	u.Path += "api/v3/"
+	upload.Path += "api/uploads/"
+
+	return u.String(), upload.String(), nil
+}
+
+// ghAuthToken retrieves a GitHub token for host via the gh CLI.
+func ghAuthToken(ctx context.Context, host string) (string, error) {
 	if _, err := exec.LookPath("gh"); err != nil {
 		return "", err
 	}
 
 	var stderr bytes.Buffer
 
-	cmd := exec.CommandContext(ctx, "gh", "auth", "token")
+	args := []string{"auth", "token"}
+	if host != "" && host != publicHost {
+		args = append(args, "--hostname", host)
+	}
+
+	cmd := command.New(ctx, "gh", args...)
 	cmd.Stderr = &stderr
 
 	out, err := cmd.Output()
@@ -287,13 +417,24 @@ func tokenFromFile(absPath string) (string, error) {
 	return token, nil
 }
 
-// tokenFromConfig resolves the GitHub token using the following priority order:
-//  1. Explicit token_path in config — reads file and returns; never falls through on error.
-//  2. gh auth token subprocess — default when token_path is absent.
-//  3. ~/.github_token file — last-resort fallback.
+// tokenFromConfig resolves the token for host using the following priority order:
+//  0. token_path of the request's endpoint — reads file and returns; never falls through on error.
+//  1. Explicit token_path in config, which holds a github.com token — for github.com only;
+//     reads file and returns; never falls through on error.
+//  2. gh auth token subprocess for host — default when token_path is absent.
+//  3. ~/.github_token file — last-resort fallback, for github.com only.
 //
 // Returns FailedPrecondition if all sources fail.
-func (g *GitHub) tokenFromConfig(ctx context.Context) (string, error) {
+func (g *GitHub) tokenFromConfig(ctx context.Context, host string, endpoint *pluginv1.ForgeEndpoint) (string, error) {
+	if endpoint.GetTokenPath() != "" {
+		expanded, err := g.expandPath(endpoint.GetTokenPath())
+		if err != nil {
+			return "", err
+		}
+
+		return tokenFromFile(expanded)
+	}
+
 	if g.hostClient == nil {
 		return "", status.Error(codes.FailedPrecondition, "no host client: GitHub token unavailable")
 	}
@@ -310,8 +451,11 @@ func (g *GitHub) tokenFromConfig(ctx context.Context) (string, error) {
 		}
 	}
 
+	public := host == "" || host == publicHost
+
 	// Step 1: explicit token_path — takes priority; never falls through on error.
-	if cfg.TokenPath != "" {
+	// Other hosts configure theirs in [plugins.forge_hosts], step 0.
+	if cfg.TokenPath != "" && public {
 		expanded, err := g.expandPath(cfg.TokenPath)
 		if err != nil {
 			return "", err
@@ -322,11 +466,16 @@ func (g *GitHub) tokenFromConfig(ctx context.Context) (string, error) {
 
 	// Step 2: gh auth token. Resolved at call time (not cached) for consistency
 	// with the file-read path and to avoid stale tokens after `gh auth refresh`.
-	if token, err := g.ghTokenFn(ctx); err == nil {
+	if token, err := g.ghTokenFn(ctx, host); err == nil {
 		return token, nil
 	}
 
-	// Step 3: ~/.github_token fallback.
+	if !public {
+		return "", status.Errorf(codes.FailedPrecondition, "no GitHub token found for %s: run `gh auth login "+
+			"--hostname %s` to authenticate, or set its token_path in [plugins.forge_hosts]", host, host)
+	}
+
+	// Step 3: ~/.github_token fallback, which holds a github.com token.
 	if home, err := g.userHomeDirFn(); err == nil {
 		if token, err := tokenFromFile(filepath.Join(home, ".github_token")); err == nil {
 			return token, nil
@@ -356,6 +505,9 @@ func ghPRToProto(pr *github.PullRequest) *pluginv1.PullRequest {
 		HeadBranch: pr.GetHead().GetRef(),
 		BaseBranch: pr.GetBase().GetRef(),
 		Draft:      pr.GetDraft(),
+		FetchRef:   fmt.Sprintf("refs/pull/%d/head", pr.GetNumber()),
+		FromFork:   pr.GetHead().GetRepo().GetFullName() != pr.GetBase().GetRepo().GetFullName(),
+		CloneUrl:   pr.GetBase().GetRepo().GetCloneURL(),
 	}
 }
 
@@ -0,0 +1,49 @@
+//nolint:testpackage // white-box test for unexported enterpriseURLs
+package forge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnterpriseURLs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		base, wantAPI, wantUpload string
+	}{
+		{
+			base:       "https://github.example.com",
+			wantAPI:    "https://github.example.com/api/v3/",
+			wantUpload: "https://github.example.com/api/uploads/",
+		},
+		{
+			base:       "https://github.example.com/api/v3",
+			wantAPI:    "https://github.example.com/api/v3/",
+			wantUpload: "https://github.example.com/api/v3/",
+		},
+		{
+			// A server named api.* is still an Enterprise Server web root.
+			base:       "https://api.corp.example",
+			wantAPI:    "https://api.corp.example/api/v3/",
+			wantUpload: "https://api.corp.example/api/uploads/",
+		},
+		{
+			base:       "https://api.github.com",
+			wantAPI:    "https://api.github.com/",
+			wantUpload: "https://api.github.com/",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.base, func(t *testing.T) {
+			t.Parallel()
+
+			api, upload, err := enterpriseURLs(tc.base)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantAPI, api)
+			require.Equal(t, tc.wantUpload, upload)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -202,6 +203,101 @@ func TestGitHub_ListPullRequests_NoPRs(t *testing.T) {
 	require.Empty(t, stream.prs)
 }
 
+func TestGitHub_ListPullRequests_HeadBranchAndChecks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		runs     string
+		combined string
+		want     pluginv1.CheckState
+	}{
+		{
+			name:     "no checks",
+			runs:     `{"total_count":0,"check_runs":[]}`,
+			combined: `{"state":"pending","total_count":0}`,
+			want:     pluginv1.CheckState_CHECK_STATE_UNSPECIFIED,
+		},
+		{
+			name:     "all succeeded",
+			runs:     `{"total_count":1,"check_runs":[{"status":"completed","conclusion":"success"}]}`,
+			combined: `{"state":"success","total_count":1}`,
+			want:     pluginv1.CheckState_CHECK_STATE_SUCCESS,
+		},
+		{
+			name:     "run in progress",
+			runs:     `{"total_count":1,"check_runs":[{"status":"in_progress"}]}`,
+			combined: `{"state":"success","total_count":1}`,
+			want:     pluginv1.CheckState_CHECK_STATE_PENDING,
+		},
+		{
+			name: "failed run wins over pending",
+			runs: `{"total_count":2,"check_runs":[{"status":"queued"},` +
+				`{"status":"completed","conclusion":"failure"}]}`,
+			combined: `{"state":"success","total_count":0}`,
+			want:     pluginv1.CheckState_CHECK_STATE_FAILURE,
+		},
+		{
+			name:     "failed commit status",
+			runs:     `{"total_count":0,"check_runs":[]}`,
+			combined: `{"state":"failure","total_count":1}`,
+			want:     pluginv1.CheckState_CHECK_STATE_FAILURE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			var gotHead string
+
+			mux.HandleFunc("/repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+				gotHead = r.URL.Query().Get("head")
+
+				pr := prJSON(3, "Story work", "open", "https://github.com/owner/repo/pull/3", "my-story", false)
+				pr["head"] = map[string]any{"ref": "my-story", "sha": "abc123"}
+
+				w.Header().Set("Content-Type", "application/json")
+
+				//nolint:errcheck // test mock, response write failure is non-critical
+				_ = json.NewEncoder(w).Encode([]map[string]any{pr})
+			})
+			mux.HandleFunc("/repos/owner/repo/commits/abc123/check-runs", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				//nolint:errcheck // test mock, response write failure is non-critical
+				fmt.Fprintln(w, tt.runs)
+			})
+			mux.HandleFunc("/repos/owner/repo/commits/abc123/status", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				//nolint:errcheck // test mock, response write failure is non-critical
+				fmt.Fprintln(w, tt.combined)
+			})
+
+			tokenFile := writeTokenFile(t)
+			hc := &fakeHostClient{toml: fmt.Appendf(nil, "token_path = %q", tokenFile)}
+			g := forge.NewWithBaseURL(hc, server.URL+"/")
+
+			stream := &fakeListStream{ctx: context.Background()}
+			err := g.ListPullRequests(&pluginv1.ListPRsRequest{
+				ProjectId:     &pluginv1.ProjectID{Host: testGitHubHost, Segments: []string{testOwner, testRepo}},
+				HeadBranch:    "my-story",
+				IncludeChecks: true,
+			}, stream)
+
+			require.NoError(t, err)
+			require.Equal(t, "owner:my-story", gotHead)
+			require.Len(t, stream.prs, 1)
+			require.Equal(t, tt.want, stream.prs[0].GetCheckState())
+		})
+	}
+}
+
 func TestGitHub_CreatePullRequest_Success(t *testing.T) {
 	t.Parallel()
 
@@ -310,6 +406,45 @@ func TestGitHub_GetPullRequest_Success(t *testing.T) {
 	require.Equal(t, int64(5), pr.GetNumber())
 	require.Equal(t, "PR five", pr.GetTitle())
 	require.Equal(t, pluginv1.PullRequestState_PULL_REQUEST_STATE_CLOSED, pr.GetState())
+	require.False(t, pr.GetFromFork())
+	require.Equal(t, "refs/pull/5/head", pr.GetFetchRef())
+}
+
+func TestGitHub_GetPullRequest_FromFork(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/repos/owner/repo/pulls/8", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		pr := prJSON(8, "Fork PR", "open", "https://github.com/owner/repo/pull/8", "main", false)
+		pr["head"] = map[string]any{"ref": "main", "repo": map[string]any{"full_name": "someone/repo"}}
+		pr["base"] = map[string]any{"ref": testBaseBranch, "repo": map[string]any{
+			"full_name": "owner/repo",
+			"clone_url": "https://github.com/owner/repo.git",
+		}}
+
+		//nolint:errcheck // test mock, response write failure is non-critical
+		_ = json.NewEncoder(w).Encode(pr)
+	})
+
+	tokenFile := writeTokenFile(t)
+	hc := &fakeHostClient{toml: fmt.Appendf(nil, "token_path = %q", tokenFile)}
+	g := forge.NewWithBaseURL(hc, server.URL+"/")
+
+	pr, err := g.GetPullRequest(context.Background(), &pluginv1.GetPRRequest{
+		ProjectId: &pluginv1.ProjectID{Host: testGitHubHost, Segments: []string{testOwner, testRepo}},
+		Number:    8,
+	})
+
+	require.NoError(t, err)
+	require.True(t, pr.GetFromFork())
+	require.Equal(t, "main", pr.GetHeadBranch())
+	require.Equal(t, "refs/pull/8/head", pr.GetFetchRef())
+	require.Equal(t, "https://github.com/owner/repo.git", pr.GetCloneUrl())
 }
 
 func TestGitHub_GetPullRequest_NotFound(t *testing.T) {
@@ -390,13 +525,13 @@ func TestGitHub_TokenMissing_FailedPrecondition(t *testing.T) {
 func TestGitHub_TokenResolution(t *testing.T) {
 	t.Parallel()
 
-	successFn := func(_ context.Context) (string, error) { return testToken, nil }
-	failFn := func(_ context.Context) (string, error) { return "", errGHNotAvailable }
+	successFn := func(_ context.Context, _ string) (string, error) { return testToken, nil }
+	failFn := func(_ context.Context, _ string) (string, error) { return "", errGHNotAvailable }
 
 	tests := []struct {
 		name            string
 		configTOML      string
-		ghTokenFn       func(context.Context) (string, error)
+		ghTokenFn       func(context.Context, string) (string, error)
 		homeToken       string     // if non-empty, write to <tmpHome>/.github_token
 		wantErrCode     codes.Code // 0 means success
 		wantErrContains string
@@ -478,3 +613,151 @@ func TestGitHub_TokenResolution(t *testing.T) {
 		})
 	}
 }
+
+func TestGitHub_EnterpriseServer(t *testing.T) {
+	t.Parallel()
+
+	const enterpriseHost = "github.example.com"
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/api/v3/repos/owner/repo/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testToken {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		pr := prJSON(7, "Enterprise PR", "open", "https://"+enterpriseHost+"/owner/repo/pull/7", "feat", false)
+
+		//nolint:errcheck // test mock, response write failure is non-critical
+		_ = json.NewEncoder(w).Encode(pr)
+	})
+
+	tests := []struct {
+		name     string
+		endpoint *pluginv1.ForgeEndpoint
+		wantHost string // the host gh auth token is asked for; empty when not asked
+	}{
+		{
+			name:     "web root",
+			endpoint: &pluginv1.ForgeEndpoint{ApiUrl: server.URL, TokenPath: writeTokenFile(t)},
+		},
+		{
+			name:     "API URL",
+			endpoint: &pluginv1.ForgeEndpoint{ApiUrl: server.URL + "/api/v3", TokenPath: writeTokenFile(t)},
+		},
+		{
+			name:     "token from gh for the host",
+			endpoint: &pluginv1.ForgeEndpoint{ApiUrl: server.URL + "/api/v3/"},
+			wantHost: enterpriseHost,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var ghHost string
+
+			// The plugin's own token_path would fail; the endpoint's wins.
+			hc := &fakeHostClient{}
+			if tc.endpoint.GetTokenPath() != "" {
+				hc.toml = []byte(`token_path = "/nonexistent/token"`)
+			}
+
+			g := forge.New(hc, forge.WithGHTokenFn(func(_ context.Context, host string) (string, error) {
+				ghHost = host
+
+				return testToken, nil
+			}))
+
+			pr, err := g.GetPullRequest(context.Background(), &pluginv1.GetPRRequest{
+				ProjectId: &pluginv1.ProjectID{Host: enterpriseHost, Segments: []string{testOwner, testRepo}},
+				Number:    7,
+				Endpoint:  tc.endpoint,
+			})
+			require.NoError(t, err)
+			require.Equal(t, "Enterprise PR", pr.GetTitle())
+			require.Equal(t, tc.wantHost, ghHost)
+		})
+	}
+}
+
+func TestGitHub_EnterpriseServer_NotSentPublicTokenPath(t *testing.T) {
+	t.Parallel()
+
+	const enterpriseHost = "github.example.com"
+
+	publicToken := filepath.Join(t.TempDir(), "github.com-token")
+	require.NoError(t, os.WriteFile(publicToken, []byte("ghp_public\n"), 0o600))
+
+	var (
+		mu    sync.Mutex
+		auths []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		auths = append(auths, r.Header.Get("Authorization"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		pr := prJSON(7, "Enterprise PR", "open", "https://"+enterpriseHost+"/owner/repo/pull/7", "feat", false)
+
+		//nolint:errcheck // test mock, response write failure is non-critical
+		_ = json.NewEncoder(w).Encode(pr)
+	}))
+	t.Cleanup(server.Close)
+
+	get := func(ghTokenFn func(context.Context, string) (string, error)) error {
+		// The plugin's token_path holds the github.com token.
+		g := forge.New(&fakeHostClient{toml: []byte(`token_path = "` + publicToken + `"`)},
+			forge.WithGHTokenFn(ghTokenFn))
+
+		_, err := g.GetPullRequest(context.Background(), &pluginv1.GetPRRequest{
+			ProjectId: &pluginv1.ProjectID{Host: enterpriseHost, Segments: []string{testOwner, testRepo}},
+			Number:    7,
+			Endpoint:  &pluginv1.ForgeEndpoint{ApiUrl: server.URL},
+		})
+
+		return err
+	}
+
+	// gh's token for the enterprise host is used instead.
+	require.NoError(t, get(func(_ context.Context, _ string) (string, error) { return testToken, nil }))
+
+	// Without one, the call fails before reaching the server.
+	err := get(func(_ context.Context, _ string) (string, error) { return "", errGHNotAvailable })
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Equal(t, []string{"Bearer " + testToken}, auths, "the github.com token must never reach the server")
+}
+
+func TestGitHub_EnterpriseServer_NoToken(t *testing.T) {
+	t.Parallel()
+
+	// ~/.github_token holds a github.com token, never sent to other hosts.
+	home := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".github_token"), []byte("ghp_public\n"), 0o600))
+
+	g := forge.New(&fakeHostClient{},
+		forge.WithGHTokenFn(func(_ context.Context, _ string) (string, error) { return "", errGHNotAvailable }),
+		forge.WithUserHomeDirFn(func() (string, error) { return home, nil }),
+	)
+
+	_, err := g.GetPullRequest(context.Background(), &pluginv1.GetPRRequest{
+		ProjectId: &pluginv1.ProjectID{Host: "github.example.com", Segments: []string{testOwner, testRepo}},
+		Number:    7,
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.ErrorContains(t, err, "gh auth login --hostname github.example.com")
+}
@@ -0,0 +1,14 @@
+// Package bundle lets swm run the fzf picker plugin in its own process when
+// swm-plugin-picker-fzf is not installed.
+package bundle
+
+import (
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/plugins/picker-fzf/internal/picker"
+)
+
+// New returns the plugin as its binary serves it.
+func New() (pluginv1.PickerServer, error) {
+	return picker.New()
+}
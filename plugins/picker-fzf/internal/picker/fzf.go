@@ -14,6 +14,8 @@ import (
 	"google.golang.org/grpc/status"
 
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/sdk/go/command"
 )
 
 // buildVersion is set via -ldflags at build time.
@@ -89,12 +91,16 @@ func (f *Fzf) Pick(stream grpc.BidiStreamingServer[pluginv1.PickItem, pluginv1.P
 
 	var outBuf bytes.Buffer
 
-	cmd := exec.Command(f.fzfBin, "--with-nth=2", "--delimiter=\t") //nolint:gosec // fzfBin from LookPath
+	cmd := command.NewForeground(stream.Context(), f.fzfBin, "--with-nth=2", "--delimiter=\t")
 	cmd.Stdin = &input
 	cmd.Stdout = &outBuf
 	cmd.Stderr = tty // fzf renders its TUI on stderr (attached to /dev/tty)
 
 	if err := cmd.Run(); err != nil {
+		if ctxErr := stream.Context().Err(); ctxErr != nil {
+			return status.FromContextError(ctxErr).Err()
+		}
+
 		// fzf exits 1 when the user presses Escape or Ctrl-C.
 		return status.Errorf(codes.Aborted, "picker cancelled")
 	}
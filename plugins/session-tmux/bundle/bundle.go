@@ -0,0 +1,15 @@
+// Package bundle lets swm run the tmux session plugin in its own process
+// when swm-plugin-session-tmux is not installed.
+package bundle
+
+import (
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/plugins/session-tmux/internal/session"
+)
+
+// New returns the plugin as its binary serves it, calling the Host service
+// through host.
+func New(host pluginv1.HostClient) (pluginv1.SessionServer, error) {
+	return session.NewWithClient(host)
+}
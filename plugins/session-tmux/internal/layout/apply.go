@@ -252,15 +252,15 @@ func cmdString(c Command) string {
 
 	quoted := make([]string, len(c.Args))
 	for i, arg := range c.Args {
-		quoted[i] = shellQuote(arg)
+		quoted[i] = ShellQuote(arg)
 	}
 
 	return c.Command + " " + strings.Join(quoted, " ")
 }
 
-// shellQuote wraps arg in single quotes if it contains shell metacharacters.
+// ShellQuote wraps arg in single quotes if it contains shell metacharacters.
 // Single quotes inside the arg are escaped using the ”\” idiom.
-func shellQuote(arg string) string {
+func ShellQuote(arg string) string {
 	if arg == "" {
 		return "''"
 	}
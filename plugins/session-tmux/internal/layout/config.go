@@ -29,6 +29,10 @@ type TemplateVars struct {
 	StoryName    string
 	ProjectID    string
 	TmuxSocket   string
+	// PortBase is the first port of the story's port block, 0 when it has none.
+	PortBase int
+	// Ports maps the project's named ports to their port in the story's block.
+	Ports map[string]int
 }
 
 // Command is an executable with optional arguments (used for startup commands).
@@ -112,6 +112,27 @@ func TestLoadConfig_TemplateSubstitution(t *testing.T) {
 	require.Equal(t, "echo feat-x /run/user/1000/swm/tmux/feat-x.sock", cfg.Windows[0].Panes[0].Commands[0])
 }
 
+func TestLoadConfig_PortTemplateSubstitution(t *testing.T) {
+	t.Parallel()
+
+	wt := t.TempDir()
+	xdg := t.TempDir()
+	writeConfig(t, filepath.Join(wt, ".swm"), `
+[[windows]]
+name = "server"
+
+  [[windows.panes]]
+  commands = ["serve --port {{.Ports.api}}"]
+  env = { SWM_PORT_BASE = "{{.PortBase}}" }
+`)
+
+	cfg, err := layout.LoadConfig(wt, xdg, layout.TemplateVars{PortBase: 20100, Ports: map[string]int{"api": 20101}})
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	require.Equal(t, "serve --port 20101", cfg.Windows[0].Panes[0].Commands[0])
+	require.Equal(t, "20100", cfg.Windows[0].Panes[0].Env["SWM_PORT_BASE"])
+}
+
 func TestLoadConfig_ValidationNoWindows(t *testing.T) {
 	t.Parallel()
 
@@ -1,13 +1,17 @@
 package session
 
 import (
+	"context"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 )
 
 // pluginInternalVars lists the SWM_* variables that are only meaningful inside a
 // plugin subprocess and must not appear in user-facing processes (tmux sessions, hooks).
 var pluginInternalVars = map[string]bool{ //nolint:gochecknoglobals // package-level constant set
+	"SWM_CALLER_TOKEN":        true,
 	"SWM_HOST_SOCKET":         true,
 	"SWM_LOG_LEVEL":           true,
 	"SWM_PLUGIN_MAGIC_COOKIE": true,
@@ -27,3 +31,60 @@ func filteredEnv() []string {
 
 	return out
 }
+
+// storyEnvOption is the server user option listing the variables set from the
+// story's env, so that a later OpenWorkspace can drop the ones removed since.
+const storyEnvOption = "@swm_env"
+
+// setStoryEnv makes env the story environment of the server at sock: every
+// variable is set in the global environment, inherited by sessions and panes
+// created from now on, and variables set by a previous call that are no
+// longer in env are removed.
+func (t *Tmux) setStoryEnv(ctx context.Context, sock string, env map[string]string) error {
+	out, err := t.run(ctx, "-S", sock, "show-options", "-gqv", storyEnvOption)
+	if err != nil {
+		return err
+	}
+
+	prev := strings.Fields(out)
+	if len(prev) == 0 && len(env) == 0 {
+		return nil
+	}
+
+	for _, k := range prev {
+		if _, ok := env[k]; ok {
+			continue
+		}
+
+		if _, err := t.run(ctx, "-S", sock, "set-environment", "-gu", k); err != nil {
+			return err
+		}
+	}
+
+	keys := slices.Sorted(maps.Keys(env))
+
+	for _, k := range keys {
+		if _, err := t.run(ctx, "-S", sock, "set-environment", "-g", k, env[k]); err != nil {
+			return err
+		}
+	}
+
+	if len(keys) == 0 {
+		_, err = t.run(ctx, "-S", sock, "set-option", "-gu", storyEnvOption)
+	} else {
+		_, err = t.run(ctx, "-S", sock, "set-option", "-g", storyEnvOption, strings.Join(keys, " "))
+	}
+
+	return err
+}
+
+// envFlags returns env as tmux -e KEY=VALUE flags, sorted by key.
+func envFlags(env map[string]string) []string {
+	flags := make([]string, 0, 2*len(env)) //nolint:mnd // two arguments per variable
+
+	for _, k := range slices.Sorted(maps.Keys(env)) {
+		flags = append(flags, "-e", k+"="+env[k])
+	}
+
+	return flags
+}
@@ -0,0 +1,80 @@
+package session_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+)
+
+func TestOpenWorkspace_SetsStoryEnv(t *testing.T) {
+	// Cannot be parallel — sets env vars.
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+
+	tmux, _ := newTmux(t)
+	_, err := tmux.OpenWorkspace(context.Background(), &pluginv1.OpenWorkspaceRequest{
+		StoryName: "feat-env",
+		Env:       map[string]string{"KUBECONFIG": "/tmp/kube", "AWS_PROFILE": "dev"},
+	})
+	require.NoError(t, err)
+
+	log := readTmuxLog(t, logFile)
+	require.Contains(t, log, "new-session -d -s feat-env -e SWM_STORY=feat-env -e AWS_PROFILE=dev -e KUBECONFIG=/tmp/kube")
+	require.Contains(t, log, "set-environment -g AWS_PROFILE dev\n")
+	require.Contains(t, log, "set-environment -g KUBECONFIG /tmp/kube\n")
+	require.Contains(t, log, "set-option -g @swm_env AWS_PROFILE KUBECONFIG\n")
+}
+
+func TestOpenWorkspace_DropsRemovedStoryEnv(t *testing.T) {
+	// Cannot be parallel — sets env vars.
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+	t.Setenv("FAKETMUX_SHOW_OPTIONS", "AWS_PROFILE KUBECONFIG\n")
+
+	tmux, _ := newTmux(t)
+	_, err := tmux.OpenWorkspace(context.Background(), &pluginv1.OpenWorkspaceRequest{
+		StoryName: "feat-env",
+		Env:       map[string]string{"AWS_PROFILE": "prod"},
+	})
+	require.NoError(t, err)
+
+	log := readTmuxLog(t, logFile)
+	require.Contains(t, log, "set-environment -gu KUBECONFIG\n")
+	require.NotContains(t, log, "set-environment -gu AWS_PROFILE")
+	require.Contains(t, log, "set-environment -g AWS_PROFILE prod\n")
+	require.Contains(t, log, "set-option -g @swm_env AWS_PROFILE\n")
+
+	// Removing the last variable clears the option.
+	t.Setenv("FAKETMUX_SHOW_OPTIONS", "AWS_PROFILE")
+
+	_, err = tmux.OpenWorkspace(context.Background(), &pluginv1.OpenWorkspaceRequest{StoryName: "feat-env"})
+	require.NoError(t, err)
+
+	log = readTmuxLog(t, logFile)
+	require.Contains(t, log, "set-environment -gu AWS_PROFILE\n")
+	require.Contains(t, log, "set-option -gu @swm_env\n")
+}
+
+func TestOpenPaneGroup_SetsProjectEnv(t *testing.T) {
+	// Cannot be parallel — sets env vars.
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+
+	worktree := t.TempDir()
+	tmux, socketDir := newTmuxWithConfigHome(t, t.TempDir())
+
+	_, err := tmux.OpenPaneGroup(context.Background(), &pluginv1.OpenPaneGroupRequest{
+		WorkspaceId:  filepath.Join(socketDir, "feat-env.sock"),
+		ProjectId:    &pluginv1.ProjectID{Host: testHost, Segments: []string{testOrg, testRepo}},
+		WorktreePath: worktree,
+		Env:          map[string]string{"PORT": "3001", "HISTFILE": "/data/history"},
+	})
+	require.NoError(t, err)
+
+	require.Contains(t, readTmuxLog(t, logFile),
+		"new-session -d -s "+testPaneGroupFull+" -c "+worktree+" -e HISTFILE=/data/history -e PORT=3001")
+}
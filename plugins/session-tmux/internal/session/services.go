@@ -0,0 +1,226 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/plugins/session-tmux/internal/layout"
+)
+
+// servicesWindow is the window of the bootstrap session holding one pane per
+// service.
+const servicesWindow = "swm-services"
+
+// serviceOption is the pane user option naming the service a pane runs. Pane
+// titles are not used because programs can change them.
+const serviceOption = "@swm_service"
+
+// servicePaneFormat is the list-panes format used by ListServices.
+const servicePaneFormat = "#{pane_id}\t#{" + serviceOption + "}\t#{pane_pid}\t#{pane_dead}\t#{pane_dead_status}"
+
+// servicePaneFields is the number of tab-separated fields in servicePaneFormat.
+// The last one is empty for a live pane and may be trimmed from the output.
+const servicePaneFields = 5
+
+// ListServices streams the services of a workspace in start order, including
+// exited ones (the services window keeps their panes with remain-on-exit).
+func (t *Tmux) ListServices(req *pluginv1.ListServicesRequest, stream pluginv1.Session_ListServicesServer) error {
+	services, err := t.services(stream.Context(), req.GetWorkspaceId())
+	if err != nil {
+		return err
+	}
+
+	for _, svc := range services {
+		if err := stream.Send(svc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StartService runs argv in a new pane of the services window of the
+// workspace's bootstrap session, creating the window on first use. A running
+// service with the same name is returned as is; an exited one is replaced.
+//
+// The pane is created with a placeholder that waits for input, configured, and
+// then respawned with argv, so that no early output escapes the log and a
+// service exiting immediately still leaves its pane behind.
+func (t *Tmux) StartService(ctx context.Context, req *pluginv1.StartServiceRequest) (*pluginv1.Service, error) {
+	sock := req.GetWorkspaceId()
+
+	if req.GetName() == "" || len(req.GetArgv()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "name and argv are required")
+	}
+
+	boot := sessionName(storyNameFromSocket(sock))
+	if _, err := t.run(ctx, "-S", sock, "has-session", "-t", boot); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "workspace %s is not running", sock)
+	}
+
+	existing, err := t.services(ctx, sock)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, svc := range existing {
+		if svc.GetName() != req.GetName() {
+			continue
+		}
+
+		if svc.GetRunning() {
+			return svc, nil
+		}
+
+		if _, err := t.run(ctx, "-S", sock, "kill-pane", "-t", svc.GetServiceId()); err != nil {
+			return nil, err
+		}
+	}
+
+	window := boot + ":" + servicesWindow
+
+	args := []string{"-S", sock, "split-window", "-d", "-t", window}
+	if !hasServicesWindow(existing, req.GetName()) {
+		args = []string{"-S", sock, "new-window", "-d", "-t", boot + ":", "-n", servicesWindow}
+	}
+
+	paneID, err := t.run(ctx, append(args, "-P", "-F", "#{pane_id}", "-c", req.GetWorkDir(), "cat")...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.setupServicePane(ctx, sock, window, paneID, req); err != nil {
+		return nil, err
+	}
+
+	respawn := []string{"-S", sock, "respawn-pane", "-k", "-t", paneID, "-c", req.GetWorkDir()}
+	respawn = append(respawn, envFlags(req.GetEnv())...)
+
+	if _, err := t.run(ctx, append(respawn, req.GetArgv()...)...); err != nil {
+		return nil, err
+	}
+
+	out, err := t.run(ctx, "-S", sock, "display-message", "-p", "-t", paneID, "#{pane_pid}")
+	if err != nil {
+		return nil, err
+	}
+
+	pid, _ := strconv.ParseInt(out, 10, 64) //nolint:errcheck // informational; zero when unparsable
+
+	return &pluginv1.Service{ServiceId: paneID, Name: req.GetName(), Pid: pid, Running: true}, nil
+}
+
+// StopService kills the pane running a service. A service that is already
+// gone, or a workspace that is not running, is treated as stopped.
+func (t *Tmux) StopService(ctx context.Context, req *pluginv1.StopServiceRequest) (*pluginv1.Empty, error) {
+	sock := req.GetWorkspaceId()
+
+	if req.GetServiceId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "service_id is required")
+	}
+
+	services, err := t.services(ctx, sock)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, svc := range services {
+		if svc.GetServiceId() != req.GetServiceId() {
+			continue
+		}
+
+		if _, err := t.run(ctx, "-S", sock, "kill-pane", "-t", svc.GetServiceId()); err != nil {
+			return nil, err
+		}
+	}
+
+	return &pluginv1.Empty{}, nil
+}
+
+// services lists the panes of the services window on the server at sock. A
+// server or window that does not exist has no services.
+func (t *Tmux) services(ctx context.Context, sock string) ([]*pluginv1.Service, error) {
+	if _, err := os.Stat(sock); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	window := sessionName(storyNameFromSocket(sock)) + ":" + servicesWindow
+
+	out, err := t.run(ctx, "-S", sock, "list-panes", "-t", window, "-F", servicePaneFormat)
+	if err != nil || out == "" {
+		return nil, nil //nolint:nilerr // no services window yet
+	}
+
+	var services []*pluginv1.Service
+
+	for line := range strings.SplitSeq(out, "\n") {
+		f := strings.Split(line, "\t")
+		if len(f) < servicePaneFields-1 {
+			return nil, status.Errorf(codes.Internal, "%v: %q", errMalformedPaneLine, line)
+		}
+
+		var exitCode int
+		if len(f) == servicePaneFields {
+			exitCode, _ = strconv.Atoi(f[4]) //nolint:errcheck // empty while the pane is alive
+		}
+
+		pid, _ := strconv.ParseInt(f[2], 10, 64) //nolint:errcheck // informational; zero when unparsable
+		services = append(services, &pluginv1.Service{
+			ServiceId: f[0],
+			Name:      f[1],
+			Pid:       pid,
+			Running:   f[3] != "1",
+			ExitCode:  int32(exitCode), //nolint:gosec // exit codes fit in int32
+		})
+	}
+
+	return services, nil
+}
+
+// setupServicePane names a new service pane, keeps exited panes visible, tiles
+// the services window and pipes the pane's output to the log file.
+func (t *Tmux) setupServicePane(
+	ctx context.Context, sock, window, paneID string, req *pluginv1.StartServiceRequest,
+) error {
+	cmds := [][]string{
+		{"set-option", "-p", "-t", paneID, serviceOption, req.GetName()},
+		{"set-option", "-w", "-t", window, "remain-on-exit", "on"},
+		{"select-layout", "-t", window, "tiled"},
+	}
+
+	if logPath := req.GetLogPath(); logPath != "" {
+		if err := os.MkdirAll(filepath.Dir(logPath), 0o700); err != nil {
+			return status.Errorf(codes.Internal, "creating log dir: %v", err)
+		}
+
+		cmds = append(cmds, []string{"pipe-pane", "-o", "-t", paneID, "cat >> " + layout.ShellQuote(logPath)})
+	}
+
+	for _, c := range cmds {
+		if _, err := t.run(ctx, append([]string{"-S", sock}, c...)...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasServicesWindow reports whether any pane other than one named name keeps
+// the services window alive.
+func hasServicesWindow(services []*pluginv1.Service, name string) bool {
+	for _, svc := range services {
+		if svc.GetName() != name {
+			return true
+		}
+	}
+
+	return false
+}
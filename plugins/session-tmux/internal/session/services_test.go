@@ -0,0 +1,208 @@
+package session_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/plugins/session-tmux/internal/session"
+)
+
+const testServiceName = testProject + ":web"
+
+// newServiceWorkspace returns a Tmux whose fake server for story feat-x is
+// running, and the path of the faketmux invocation log.
+func newServiceWorkspace(t *testing.T) (*session.Tmux, string, string) {
+	t.Helper()
+
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+	t.Setenv("FAKETMUX_HAS_SESSION", "0")
+
+	tmux, socketDir := newTmux(t)
+	sock := filepath.Join(socketDir, "feat-x.sock")
+	require.NoError(t, os.WriteFile(sock, nil, 0o600))
+
+	return tmux, sock, logFile
+}
+
+func readTmuxLog(t *testing.T, logFile string) string {
+	t.Helper()
+
+	b, err := os.ReadFile(logFile) //nolint:gosec // G304: test-controlled path
+	require.NoError(t, err)
+
+	return string(b)
+}
+
+func TestStartService_CreatesServicesWindow(t *testing.T) {
+	// Cannot be parallel — sets env vars.
+	tmux, sock, logFile := newServiceWorkspace(t)
+	t.Setenv("FAKETMUX_NEW_PANE", "%7")
+	t.Setenv("FAKETMUX_SESSION", "4242") // display-message output: the pane PID
+
+	logPath := filepath.Join(t.TempDir(), "logs", "web.log")
+
+	svc, err := tmux.StartService(context.Background(), &pluginv1.StartServiceRequest{
+		WorkspaceId: sock,
+		Name:        testServiceName,
+		Argv:        []string{"sh", "-c", "npm run dev"},
+		Env:         map[string]string{"PORT": "3000"},
+		WorkDir:     testWorktree,
+		LogPath:     logPath,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "%7", svc.GetServiceId())
+	require.Equal(t, int64(4242), svc.GetPid())
+	require.True(t, svc.GetRunning())
+	require.DirExists(t, filepath.Dir(logPath))
+
+	log := readTmuxLog(t, logFile)
+	require.Contains(t, log, "new-window -d -t feat-x: -n swm-services -P -F #{pane_id} -c /tmp/wt cat\n")
+	require.Contains(t, log, "respawn-pane -k -t %7 -c /tmp/wt -e PORT=3000 sh -c npm run dev\n")
+	require.Contains(t, log, "set-option -p -t %7 @swm_service "+testServiceName+"\n")
+	require.Contains(t, log, "set-option -w -t feat-x:swm-services remain-on-exit on\n")
+	require.Contains(t, log, "pipe-pane -o -t %7 cat >> "+logPath+"\n")
+	require.Less(t, strings.Index(log, "pipe-pane"), strings.Index(log, "respawn-pane"),
+		"output must be piped before the service starts")
+}
+
+func TestStartService_SplitsExistingWindow(t *testing.T) {
+	// Cannot be parallel — sets env vars.
+	tmux, sock, logFile := newServiceWorkspace(t)
+	t.Setenv("FAKETMUX_LIST_PANES", "%3\t"+testProject+":db\t41\t0\t\n")
+
+	_, err := tmux.StartService(context.Background(), &pluginv1.StartServiceRequest{
+		WorkspaceId: sock,
+		Name:        testServiceName,
+		Argv:        []string{"sh", "-c", "npm run dev"},
+		WorkDir:     testWorktree,
+	})
+	require.NoError(t, err)
+
+	log := readTmuxLog(t, logFile)
+	require.Contains(t, log, "split-window -d -t feat-x:swm-services -P")
+	require.Contains(t, log, "select-layout -t feat-x:swm-services tiled\n")
+	require.NotContains(t, log, "new-window")
+	require.NotContains(t, log, "pipe-pane", "no log_path, no pipe")
+}
+
+func TestStartService_AlreadyRunning(t *testing.T) {
+	// Cannot be parallel — sets env vars.
+	tmux, sock, logFile := newServiceWorkspace(t)
+	t.Setenv("FAKETMUX_LIST_PANES", "%3\t"+testServiceName+"\t41\t0\t\n")
+
+	svc, err := tmux.StartService(context.Background(), &pluginv1.StartServiceRequest{
+		WorkspaceId: sock,
+		Name:        testServiceName,
+		Argv:        []string{"sh", "-c", "npm run dev"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "%3", svc.GetServiceId())
+	require.Equal(t, int64(41), svc.GetPid())
+
+	log := readTmuxLog(t, logFile)
+	require.NotContains(t, log, "new-window")
+	require.NotContains(t, log, "split-window")
+}
+
+func TestStartService_ReplacesExitedService(t *testing.T) {
+	// Cannot be parallel — sets env vars.
+	tmux, sock, logFile := newServiceWorkspace(t)
+	t.Setenv("FAKETMUX_LIST_PANES", "%3\t"+testServiceName+"\t41\t1\t2\n")
+
+	_, err := tmux.StartService(context.Background(), &pluginv1.StartServiceRequest{
+		WorkspaceId: sock,
+		Name:        testServiceName,
+		Argv:        []string{"sh", "-c", "npm run dev"},
+	})
+	require.NoError(t, err)
+
+	log := readTmuxLog(t, logFile)
+	require.Contains(t, log, "kill-pane -t %3\n")
+	require.Contains(t, log, "new-window -d -t feat-x: -n swm-services")
+}
+
+func TestStartService_WorkspaceNotRunning(t *testing.T) {
+	t.Parallel()
+
+	tmux, socketDir := newTmux(t)
+
+	_, err := tmux.StartService(context.Background(), &pluginv1.StartServiceRequest{
+		WorkspaceId: filepath.Join(socketDir, "feat-x.sock"),
+		Name:        testServiceName,
+		Argv:        []string{"true"},
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = tmux.StartService(context.Background(), &pluginv1.StartServiceRequest{Name: testServiceName})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListServices(t *testing.T) {
+	// Cannot be parallel — sets env vars.
+	tmux, sock, _ := newServiceWorkspace(t)
+	t.Setenv("FAKETMUX_LIST_PANES", "%3\t"+testProject+":db\t41\t0\t\n%4\t"+testServiceName+"\t42\t1\t3")
+
+	stream := &collectServiceStream{ctx: context.Background()}
+	require.NoError(t, tmux.ListServices(&pluginv1.ListServicesRequest{WorkspaceId: sock}, stream))
+
+	require.Len(t, stream.items, 2)
+	require.Equal(t, testProject+":db", stream.items[0].GetName())
+	require.True(t, stream.items[0].GetRunning())
+	require.Equal(t, "%4", stream.items[1].GetServiceId())
+	require.False(t, stream.items[1].GetRunning())
+	require.Equal(t, int32(3), stream.items[1].GetExitCode())
+}
+
+func TestListServices_NoWorkspace(t *testing.T) {
+	t.Parallel()
+
+	tmux, socketDir := newTmux(t)
+
+	stream := &collectServiceStream{ctx: context.Background()}
+	require.NoError(t, tmux.ListServices(&pluginv1.ListServicesRequest{
+		WorkspaceId: filepath.Join(socketDir, "gone.sock"),
+	}, stream))
+	require.Empty(t, stream.items)
+}
+
+func TestStopService(t *testing.T) {
+	// Cannot be parallel — sets env vars.
+	tmux, sock, logFile := newServiceWorkspace(t)
+	t.Setenv("FAKETMUX_LIST_PANES", "%3\t"+testServiceName+"\t41\t0\t\n")
+
+	_, err := tmux.StopService(context.Background(), &pluginv1.StopServiceRequest{WorkspaceId: sock, ServiceId: "%9"})
+	require.NoError(t, err, "stopping an unknown service succeeds")
+	require.NotContains(t, readTmuxLog(t, logFile), "kill-pane")
+
+	_, err = tmux.StopService(context.Background(), &pluginv1.StopServiceRequest{WorkspaceId: sock, ServiceId: "%3"})
+	require.NoError(t, err)
+	require.Contains(t, readTmuxLog(t, logFile), "kill-pane -t %3\n")
+
+	_, err = tmux.StopService(context.Background(), &pluginv1.StopServiceRequest{WorkspaceId: sock})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// collectServiceStream implements pluginv1.Session_ListServicesServer for tests.
+type collectServiceStream struct {
+	pluginv1.Session_ListServicesServer
+	ctx   context.Context
+	items []*pluginv1.Service
+}
+
+func (s *collectServiceStream) Context() context.Context { return s.ctx }
+
+func (s *collectServiceStream) Send(svc *pluginv1.Service) error {
+	s.items = append(s.items, svc)
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package session
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestSessionName(t *testing.T) {
 	t.Parallel()
@@ -48,3 +51,54 @@ func TestSessionName(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectIDFromSessionName(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		session  string
+		wantHost string
+		wantSegs []string
+	}{
+		{
+			name:     "round-trips sessionName",
+			session:  sessionName("github.com/kalbasit/swm"),
+			wantHost: "github.com",
+			wantSegs: []string{"kalbasit", "swm"},
+		},
+		{
+			name:     "host with port",
+			session:  sessionName("host:8080/org/repo"),
+			wantHost: "host:8080",
+			wantSegs: []string{"org", "repo"},
+		},
+		{
+			name:    "bootstrap session is not a project",
+			session: "feat-x",
+		},
+		{
+			name:    "empty name",
+			session: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := projectIDFromSessionName(tc.session)
+			if tc.wantHost == "" {
+				if got != nil {
+					t.Errorf("projectIDFromSessionName(%q) = %v; want nil", tc.session, got)
+				}
+
+				return
+			}
+
+			if got.GetHost() != tc.wantHost || strings.Join(got.GetSegments(), "/") != strings.Join(tc.wantSegs, "/") {
+				t.Errorf("projectIDFromSessionName(%q) = %v; want %s/%v", tc.session, got, tc.wantHost, tc.wantSegs)
+			}
+		})
+	}
+}
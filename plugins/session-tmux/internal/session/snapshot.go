@@ -0,0 +1,391 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+)
+
+// snapshotVersion is the on-disk format version written by SaveWorkspace.
+const snapshotVersion = 1
+
+// snapshotPaneFormat is the list-panes format used to capture a workspace.
+// Fields are tab-separated so that paths and window names may contain spaces.
+const snapshotPaneFormat = "#{session_name}\t#{window_index}\t#{window_name}\t#{window_layout}\t#{window_active}\t" +
+	"#{pane_index}\t#{pane_current_path}\t#{pane_current_command}\t#{pane_width}\t#{pane_height}\t#{pane_active}"
+
+// snapshotPaneFields is the number of tab-separated fields in snapshotPaneFormat.
+const snapshotPaneFields = 11
+
+// defaultRestoreCommands lists foreground commands that are safe to restart
+// when restoring a snapshot. Anything else (including shells) is left alone.
+var defaultRestoreCommands = []string{ //nolint:gochecknoglobals // read-only default allowlist
+	"emacs", "htop", "less", "man", "nvim", "top", "vi", "vim",
+}
+
+var errMalformedPaneLine = errors.New("malformed list-panes line")
+
+// snapshot is the serialized form of a tmux workspace.
+type snapshot struct {
+	Version    int                 `json:"version"`
+	StoryName  string              `json:"story_name"`
+	SavedAt    time.Time           `json:"saved_at"`
+	PaneGroups []snapshotPaneGroup `json:"pane_groups"`
+}
+
+// snapshotPaneGroup is one tmux session inside the workspace.
+type snapshotPaneGroup struct {
+	Name    string           `json:"name"`
+	Windows []snapshotWindow `json:"windows"`
+}
+
+// snapshotWindow is one tmux window; Layout is tmux's own layout string and
+// carries the pane geometry.
+type snapshotWindow struct {
+	Index  int            `json:"index"`
+	Name   string         `json:"name"`
+	Layout string         `json:"layout"`
+	Active bool           `json:"active"`
+	Panes  []snapshotPane `json:"panes"`
+}
+
+// snapshotPane is one tmux pane.
+type snapshotPane struct {
+	Index   int    `json:"index"`
+	Cwd     string `json:"cwd"`
+	Command string `json:"command"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Active  bool   `json:"active"`
+}
+
+// RestoreWorkspace recreates a workspace from a snapshot written by SaveWorkspace.
+// Pane groups that already exist on the server are left untouched. The story
+// environment is set as OpenWorkspace sets it.
+func (t *Tmux) RestoreWorkspace(
+	ctx context.Context,
+	req *pluginv1.RestoreWorkspaceRequest,
+) (*pluginv1.RestoreWorkspaceResponse, error) {
+	if req.GetSnapshotPath() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "snapshot_path is required")
+	}
+
+	snap, err := readSnapshot(req.GetSnapshotPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, status.Errorf(codes.NotFound, "no snapshot at %s", req.GetSnapshotPath())
+		}
+
+		return nil, status.Errorf(codes.Internal, "reading snapshot: %v", err)
+	}
+
+	ws, err := t.OpenWorkspace(ctx, &pluginv1.OpenWorkspaceRequest{StoryName: req.GetStoryName(), Env: req.GetEnv()})
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := t.restoreSnapshot(ctx, ws.GetWorkspaceId(), snap)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pluginv1.RestoreWorkspaceResponse{Workspace: ws, PaneGroupIds: ids}, nil
+}
+
+// SaveWorkspace serializes every pane group of a workspace to snapshot_path.
+func (t *Tmux) SaveWorkspace(
+	ctx context.Context,
+	req *pluginv1.SaveWorkspaceRequest,
+) (*pluginv1.SaveWorkspaceResponse, error) {
+	if req.GetSnapshotPath() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "snapshot_path is required")
+	}
+
+	sock := req.GetWorkspaceId()
+
+	if _, err := os.Stat(sock); os.IsNotExist(err) {
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %s", sock)
+	}
+
+	snap, err := t.captureSnapshot(ctx, sock)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeSnapshot(req.GetSnapshotPath(), snap); err != nil {
+		return nil, status.Errorf(codes.Internal, "writing snapshot: %v", err)
+	}
+
+	return &pluginv1.SaveWorkspaceResponse{
+		SnapshotPath:   req.GetSnapshotPath(),
+		PaneGroupCount: int32(len(snap.PaneGroups)), //nolint:gosec // pane group count never approaches int32 max
+	}, nil
+}
+
+// captureSnapshot lists every pane on the server and groups them by session and window.
+func (t *Tmux) captureSnapshot(ctx context.Context, sock string) (*snapshot, error) {
+	out, err := t.run(ctx, "-S", sock, "list-panes", "-a", "-F", snapshotPaneFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &snapshot{
+		Version:   snapshotVersion,
+		StoryName: strings.TrimSuffix(filepath.Base(sock), ".sock"),
+		SavedAt:   time.Now().UTC(),
+	}
+
+	for line := range strings.SplitSeq(out, "\n") {
+		if line == "" {
+			continue
+		}
+
+		if err := snap.addPaneLine(line); err != nil {
+			return nil, status.Errorf(codes.Internal, "parsing tmux output: %v", err)
+		}
+	}
+
+	return snap, nil
+}
+
+// addPaneLine parses one snapshotPaneFormat line into the snapshot, appending
+// a new pane group or window whenever the session or window changes.
+func (s *snapshot) addPaneLine(line string) error {
+	f := strings.Split(line, "\t")
+	if len(f) != snapshotPaneFields {
+		return fmt.Errorf("%w: %q", errMalformedPaneLine, line)
+	}
+
+	winIndex, err := strconv.Atoi(f[1])
+	if err != nil {
+		return fmt.Errorf("%w: window index %q", errMalformedPaneLine, f[1])
+	}
+
+	paneIndex, err := strconv.Atoi(f[5])
+	if err != nil {
+		return fmt.Errorf("%w: pane index %q", errMalformedPaneLine, f[5])
+	}
+
+	width, _ := strconv.Atoi(f[8])  //nolint:errcheck // geometry is informational; layout string is authoritative
+	height, _ := strconv.Atoi(f[9]) //nolint:errcheck // geometry is informational; layout string is authoritative
+
+	if n := len(s.PaneGroups); n == 0 || s.PaneGroups[n-1].Name != f[0] {
+		s.PaneGroups = append(s.PaneGroups, snapshotPaneGroup{Name: f[0]})
+	}
+
+	pg := &s.PaneGroups[len(s.PaneGroups)-1]
+
+	if n := len(pg.Windows); n == 0 || pg.Windows[n-1].Index != winIndex {
+		pg.Windows = append(pg.Windows, snapshotWindow{
+			Index:  winIndex,
+			Name:   f[2],
+			Layout: f[3],
+			Active: f[4] == "1",
+		})
+	}
+
+	w := &pg.Windows[len(pg.Windows)-1]
+	w.Panes = append(w.Panes, snapshotPane{
+		Index:   paneIndex,
+		Cwd:     f[6],
+		Command: f[7],
+		Width:   width,
+		Height:  height,
+		Active:  f[10] == "1",
+	})
+
+	return nil
+}
+
+// restoreSnapshot recreates each pane group of snap on the server at sock and
+// returns the IDs of the pane groups it created.
+func (t *Tmux) restoreSnapshot(ctx context.Context, sock string, snap *snapshot) ([]string, error) {
+	allowed := t.restoreCommands(ctx)
+
+	var created []string
+
+	for _, pg := range snap.PaneGroups {
+		if len(pg.Windows) == 0 {
+			continue
+		}
+
+		if _, err := t.run(ctx, "-S", sock, "has-session", "-t", "="+pg.Name); err == nil {
+			continue
+		}
+
+		if err := t.restorePaneGroup(ctx, sock, pg, allowed); err != nil {
+			return nil, fmt.Errorf("restoring pane group %q: %w", pg.Name, err)
+		}
+
+		created = append(created, pg.Name)
+	}
+
+	return created, nil
+}
+
+// restorePaneGroup creates one tmux session with the windows, splits, layouts
+// and allowlisted commands recorded in pg. Windows keep their saved indexes;
+// the session is created with the first window that has panes.
+func (t *Tmux) restorePaneGroup(ctx context.Context, sock string, pg snapshotPaneGroup, allowed []string) error {
+	var (
+		activeWindow string
+		created      bool
+	)
+
+	for _, w := range pg.Windows {
+		if len(w.Panes) == 0 {
+			continue
+		}
+
+		target := fmt.Sprintf("=%s:%d", pg.Name, w.Index)
+
+		var (
+			firstID string
+			err     error
+		)
+
+		if created {
+			firstID, err = t.run(ctx, "-S", sock, "new-window", "-d", "-t", target,
+				"-n", w.Name, "-c", w.Panes[0].Cwd, "-P", "-F", "#{pane_id}")
+		} else {
+			firstID, err = t.newRestoredSession(ctx, sock, pg.Name, w, target)
+			created = true
+		}
+
+		if err != nil {
+			return err
+		}
+
+		paneIDs := []string{firstID}
+
+		for _, p := range w.Panes[1:] {
+			id, err := t.run(ctx, "-S", sock, "split-window", "-d", "-t", firstID, "-c", p.Cwd, "-P", "-F", "#{pane_id}")
+			if err != nil {
+				return err
+			}
+
+			paneIDs = append(paneIDs, id)
+		}
+
+		if w.Layout != "" {
+			if _, err := t.run(ctx, "-S", sock, "select-layout", "-t", firstID, w.Layout); err != nil {
+				return err
+			}
+		}
+
+		for j, p := range w.Panes {
+			if slices.Contains(allowed, p.Command) {
+				if _, err := t.run(ctx, "-S", sock, "send-keys", "-t", paneIDs[j], p.Command, "Enter"); err != nil {
+					return err
+				}
+			}
+
+			if p.Active {
+				if _, err := t.run(ctx, "-S", sock, "select-pane", "-t", paneIDs[j]); err != nil {
+					return err
+				}
+			}
+		}
+
+		if w.Active {
+			activeWindow = firstID
+		}
+	}
+
+	if activeWindow != "" {
+		if _, err := t.run(ctx, "-S", sock, "select-window", "-t", activeWindow); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newRestoredSession creates the session name with the saved window w as its
+// first window, moves that window to target when tmux placed it at another
+// index, and returns the window's first pane ID.
+func (t *Tmux) newRestoredSession(
+	ctx context.Context, sock, name string, w snapshotWindow, target string,
+) (string, error) {
+	out, err := t.run(ctx, "-S", sock, "new-session", "-d", "-s", name,
+		"-n", w.Name, "-c", w.Panes[0].Cwd, "-P", "-F", "#{window_index} #{pane_id}")
+	if err != nil {
+		return "", err
+	}
+
+	index, paneID, ok := strings.Cut(out, " ")
+	if !ok {
+		return "", status.Errorf(codes.Internal, "tmux new-session printed %q, want a window index and pane ID", out)
+	}
+
+	if index != strconv.Itoa(w.Index) {
+		if _, err := t.run(ctx, "-S", sock, "move-window", "-s", paneID, "-t", target); err != nil {
+			return "", err
+		}
+	}
+
+	return paneID, nil
+}
+
+// restoreCommands returns the configured restore_commands allowlist, falling
+// back to defaultRestoreCommands when unset.
+func (t *Tmux) restoreCommands(ctx context.Context) []string {
+	cfg := t.loadConfig(ctx)
+	if cfg.RestoreCommands != nil {
+		return cfg.RestoreCommands
+	}
+
+	return defaultRestoreCommands
+}
+
+// readSnapshot loads and decodes a snapshot file.
+func readSnapshot(path string) (*snapshot, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path supplied by the host
+	if err != nil {
+		return nil, err
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+
+	return &snap, nil
+}
+
+// writeSnapshot atomically writes snap to path, creating parent directories.
+func writeSnapshot(path string, snap *snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating snapshot dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding snapshot: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp) //nolint:errcheck // best-effort temp cleanup
+
+		return fmt.Errorf("renaming snapshot: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,373 @@
+package session_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/plugins/session-tmux/internal/session"
+)
+
+// testListPanes is a list-panes listing with two pane groups: the first has an
+// editor window split in two and a shell window, the second a single pane.
+const testListPanes = testPaneGroupFull + "\t0\teditor\tb25f,80x24,0,0{40x24,0,0,1,39x24,41,0,2}\t1\t0\t/tmp/wt\tvim\t40\t24\t1\n" +
+	testPaneGroupFull + "\t0\teditor\tb25f,80x24,0,0{40x24,0,0,1,39x24,41,0,2}\t1\t1\t/tmp/wt/sub\tzsh\t39\t24\t0\n" +
+	testPaneGroupFull + "\t1\tshell\tc1a2,80x24,0,0,3\t0\t0\t/tmp/wt\tzsh\t80\t24\t1\n" +
+	"github•com/kalbasit/other\t0\tmain\td3e4,80x24,0,0,4\t1\t0\t/tmp/other\thtop\t80\t24\t1\n"
+
+// testSnapshot mirrors the JSON written by SaveWorkspace.
+type testSnapshot struct {
+	Version    int    `json:"version"`
+	StoryName  string `json:"story_name"`
+	PaneGroups []struct {
+		Name    string `json:"name"`
+		Windows []struct {
+			Name   string `json:"name"`
+			Layout string `json:"layout"`
+			Active bool   `json:"active"`
+			Panes  []struct {
+				Cwd     string `json:"cwd"`
+				Command string `json:"command"`
+				Width   int    `json:"width"`
+				Active  bool   `json:"active"`
+			} `json:"panes"`
+		} `json:"windows"`
+	} `json:"pane_groups"`
+}
+
+func readTestSnapshot(t *testing.T, path string) testSnapshot {
+	t.Helper()
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: test-controlled path
+	require.NoError(t, err)
+
+	var snap testSnapshot
+	require.NoError(t, json.Unmarshal(data, &snap))
+
+	return snap
+}
+
+func writeTestSnapshot(t *testing.T, path string) {
+	t.Helper()
+
+	snap := `{
+  "version": 1,
+  "story_name": "feat-x",
+  "pane_groups": [
+    {
+      "name": "` + testPaneGroupFull + `",
+      "windows": [
+        {"index": 0, "name": "editor", "layout": "b25f,80x24", "active": false, "panes": [
+          {"index": 0, "cwd": "/tmp/wt", "command": "vim", "active": true},
+          {"index": 1, "cwd": "/tmp/wt/sub", "command": "zsh"}
+        ]},
+        {"index": 1, "name": "shell", "layout": "c1a2,80x24", "active": true, "panes": [
+          {"index": 0, "cwd": "/tmp/wt", "command": "htop", "active": true}
+        ]}
+      ]
+    }
+  ]
+}`
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(snap), 0o600))
+}
+
+func TestSaveWorkspace_WritesSnapshot(t *testing.T) {
+	// Cannot be parallel — uses t.Setenv.
+	t.Setenv("FAKETMUX_LIST_PANES", testListPanes)
+
+	tmux, socketDir := newTmux(t)
+	sock := filepath.Join(socketDir, "feat-x.sock")
+	require.NoError(t, os.WriteFile(sock, nil, 0o600))
+
+	snapPath := filepath.Join(t.TempDir(), "feat-x", "session-snapshot.json")
+
+	resp, err := tmux.SaveWorkspace(context.Background(), &pluginv1.SaveWorkspaceRequest{
+		WorkspaceId:  sock,
+		SnapshotPath: snapPath,
+	})
+	require.NoError(t, err)
+	require.Equal(t, snapPath, resp.GetSnapshotPath())
+	require.EqualValues(t, 2, resp.GetPaneGroupCount())
+
+	snap := readTestSnapshot(t, snapPath)
+	require.Equal(t, 1, snap.Version)
+	require.Equal(t, "feat-x", snap.StoryName)
+	require.Len(t, snap.PaneGroups, 2)
+
+	pg := snap.PaneGroups[0]
+	require.Equal(t, testPaneGroupFull, pg.Name)
+	require.Len(t, pg.Windows, 2)
+	require.Equal(t, "editor", pg.Windows[0].Name)
+	require.Equal(t, "b25f,80x24,0,0{40x24,0,0,1,39x24,41,0,2}", pg.Windows[0].Layout)
+	require.True(t, pg.Windows[0].Active)
+	require.Len(t, pg.Windows[0].Panes, 2)
+	require.Equal(t, "/tmp/wt/sub", pg.Windows[0].Panes[1].Cwd)
+	require.Equal(t, "vim", pg.Windows[0].Panes[0].Command)
+	require.Equal(t, 40, pg.Windows[0].Panes[0].Width)
+	require.Equal(t, "shell", pg.Windows[1].Name)
+	require.Equal(t, "htop", snap.PaneGroups[1].Windows[0].Panes[0].Command)
+}
+
+func TestSaveWorkspace_WorkspaceNotFound(t *testing.T) {
+	t.Parallel()
+
+	tmux, socketDir := newTmux(t)
+
+	_, err := tmux.SaveWorkspace(context.Background(), &pluginv1.SaveWorkspaceRequest{
+		WorkspaceId:  filepath.Join(socketDir, "missing.sock"),
+		SnapshotPath: filepath.Join(t.TempDir(), "snap.json"),
+	})
+	require.Error(t, err)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestSaveWorkspace_SnapshotPathRequired(t *testing.T) {
+	t.Parallel()
+
+	tmux, _ := newTmux(t)
+
+	_, err := tmux.SaveWorkspace(context.Background(), &pluginv1.SaveWorkspaceRequest{WorkspaceId: "/tmp/x.sock"})
+	require.Error(t, err)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRestoreWorkspace_RecreatesPaneGroups(t *testing.T) {
+	// Cannot be parallel — uses t.Setenv.
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+
+	snapPath := filepath.Join(t.TempDir(), "session-snapshot.json")
+	writeTestSnapshot(t, snapPath)
+
+	tmux, socketDir := newTmux(t)
+
+	resp, err := tmux.RestoreWorkspace(context.Background(), &pluginv1.RestoreWorkspaceRequest{
+		StoryName:    "feat-x",
+		SnapshotPath: snapPath,
+	})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(socketDir, "feat-x.sock"), resp.GetWorkspace().GetWorkspaceId())
+	require.Equal(t, []string{testPaneGroupFull}, resp.GetPaneGroupIds())
+
+	logBytes, err := os.ReadFile(logFile) //nolint:gosec // G304: test-controlled path
+	require.NoError(t, err)
+
+	log := string(logBytes)
+	require.Contains(t, log, "new-session -d -s "+testPaneGroupFull+" -n editor -c /tmp/wt")
+	require.Contains(t, log, "split-window -d -t %0 -c /tmp/wt/sub")
+	require.Contains(t, log, "select-layout -t %0 b25f,80x24")
+	require.Contains(t, log, "has-session -t ="+testPaneGroupFull)
+	require.Contains(t, log, "new-window -d -t ="+testPaneGroupFull+":1 -n shell -c /tmp/wt")
+	require.NotContains(t, log, "move-window", "the first window already sits at its saved index")
+	require.Contains(t, log, "select-layout -t %0 c1a2,80x24")
+	require.Contains(t, log, "send-keys -t %0 vim Enter", "allowlisted vim must be restarted")
+	require.Contains(t, log, "send-keys -t %0 htop Enter", "allowlisted htop must be restarted")
+	require.NotContains(t, log, "zsh Enter", "shells must not be restarted")
+	require.Contains(t, log, "select-window -t %0")
+}
+
+func TestRestoreWorkspace_FirstWindowWithoutPanes(t *testing.T) {
+	// Cannot be parallel — uses t.Setenv.
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+
+	snapPath := filepath.Join(t.TempDir(), "session-snapshot.json")
+	require.NoError(t, os.WriteFile(snapPath, []byte(`{
+  "version": 1,
+  "story_name": "feat-x",
+  "pane_groups": [
+    {
+      "name": "`+testPaneGroupFull+`",
+      "windows": [
+        {"index": 0, "name": "empty", "panes": []},
+        {"index": 2, "name": "editor", "panes": [{"index": 0, "cwd": "/tmp/wt", "command": "zsh"}]},
+        {"index": 5, "name": "shell", "panes": [{"index": 0, "cwd": "/tmp/wt", "command": "zsh"}]}
+      ]
+    }
+  ]
+}`), 0o600))
+
+	tmux, _ := newTmux(t)
+
+	resp, err := tmux.RestoreWorkspace(context.Background(), &pluginv1.RestoreWorkspaceRequest{
+		StoryName:    "feat-x",
+		SnapshotPath: snapPath,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{testPaneGroupFull}, resp.GetPaneGroupIds())
+
+	logBytes, err := os.ReadFile(logFile) //nolint:gosec // G304: test-controlled path
+	require.NoError(t, err)
+
+	log := string(logBytes)
+	require.Contains(t, log, "new-session -d -s "+testPaneGroupFull+" -n editor -c /tmp/wt")
+	require.Contains(t, log, "move-window -s %0 -t ="+testPaneGroupFull+":2", "the window keeps its saved index")
+	require.Contains(t, log, "new-window -d -t ="+testPaneGroupFull+":5 -n shell -c /tmp/wt")
+	require.NotContains(t, log, "-n empty")
+	require.Less(t, strings.Index(log, "new-session"), strings.Index(log, "new-window"),
+		"the session must exist before windows are added to it")
+}
+
+func TestRestoreWorkspace_RestoreCommandsFromConfig(t *testing.T) {
+	// Cannot be parallel — uses t.Setenv.
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+
+	snapPath := filepath.Join(t.TempDir(), "session-snapshot.json")
+	writeTestSnapshot(t, snapPath)
+
+	client := &fakeHostClient{toml: []byte(`restore_commands = ["htop"]`)}
+	tmux := session.NewWithBinAndClient(faketmuxBin, t.TempDir(), client)
+
+	_, err := tmux.RestoreWorkspace(context.Background(), &pluginv1.RestoreWorkspaceRequest{
+		StoryName:    "feat-x",
+		SnapshotPath: snapPath,
+	})
+	require.NoError(t, err)
+
+	logBytes, err := os.ReadFile(logFile) //nolint:gosec // G304: test-controlled path
+	require.NoError(t, err)
+
+	log := string(logBytes)
+	require.Contains(t, log, "htop Enter")
+	require.NotContains(t, log, "vim Enter", "vim is not in the configured allowlist")
+}
+
+func TestRestoreWorkspace_SkipsExistingPaneGroups(t *testing.T) {
+	// Cannot be parallel — uses t.Setenv.
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+	t.Setenv("FAKETMUX_HAS_SESSION", "0")
+
+	snapPath := filepath.Join(t.TempDir(), "session-snapshot.json")
+	writeTestSnapshot(t, snapPath)
+
+	tmux, _ := newTmux(t)
+
+	resp, err := tmux.RestoreWorkspace(context.Background(), &pluginv1.RestoreWorkspaceRequest{
+		StoryName:    "feat-x",
+		SnapshotPath: snapPath,
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.GetPaneGroupIds())
+
+	logBytes, err := os.ReadFile(logFile) //nolint:gosec // G304: test-controlled path
+	require.NoError(t, err)
+	require.NotContains(t, string(logBytes), "-s "+testPaneGroupFull)
+}
+
+func TestRestoreWorkspace_KeepsStoryEnvOnRunningServer(t *testing.T) {
+	// Cannot be parallel — uses t.Setenv.
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+	t.Setenv("FAKETMUX_HAS_SESSION", "0")
+	t.Setenv("FAKETMUX_SHOW_OPTIONS", "AWS_PROFILE HISTFILE SWM_PORT_BASE\n")
+
+	snapPath := filepath.Join(t.TempDir(), "session-snapshot.json")
+	writeTestSnapshot(t, snapPath)
+
+	tmux, socketDir := newTmux(t)
+	require.NoError(t, os.WriteFile(filepath.Join(socketDir, "feat-x.sock"), nil, 0o600))
+
+	_, err := tmux.RestoreWorkspace(context.Background(), &pluginv1.RestoreWorkspaceRequest{
+		StoryName:    "feat-x",
+		SnapshotPath: snapPath,
+		Env: map[string]string{
+			"AWS_PROFILE":   "dev",
+			"HISTFILE":      "/tmp/hist",
+			"SWM_PORT_BASE": "20000",
+		},
+	})
+	require.NoError(t, err)
+
+	logBytes, err := os.ReadFile(logFile) //nolint:gosec // G304: test-controlled path
+	require.NoError(t, err)
+
+	log := string(logBytes)
+	require.NotContains(t, log, "set-environment -gu", "restoring must not drop the story env")
+	require.Contains(t, log, "set-environment -g AWS_PROFILE dev\n")
+	require.Contains(t, log, "set-environment -g HISTFILE /tmp/hist\n")
+	require.Contains(t, log, "set-environment -g SWM_PORT_BASE 20000\n")
+	require.Contains(t, log, "set-option -g @swm_env AWS_PROFILE HISTFILE SWM_PORT_BASE\n")
+}
+
+func TestRestoreWorkspace_MissingSnapshot(t *testing.T) {
+	t.Parallel()
+
+	tmux, _ := newTmux(t)
+
+	_, err := tmux.RestoreWorkspace(context.Background(), &pluginv1.RestoreWorkspaceRequest{
+		StoryName:    "feat-x",
+		SnapshotPath: filepath.Join(t.TempDir(), "missing.json"),
+	})
+	require.Error(t, err)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCloseWorkspace_Autosave(t *testing.T) {
+	// Cannot be parallel — uses t.Setenv.
+	t.Setenv("FAKETMUX_LIST_PANES", testListPanes)
+
+	tmux, socketDir := newTmux(t)
+	ws, err := tmux.OpenWorkspace(context.Background(), &pluginv1.OpenWorkspaceRequest{StoryName: "feat-x"})
+	require.NoError(t, err)
+
+	snapPath := filepath.Join(t.TempDir(), "session-snapshot.json")
+
+	_, err = tmux.CloseWorkspace(context.Background(), &pluginv1.CloseWorkspaceRequest{
+		WorkspaceId:  ws.GetWorkspaceId(),
+		SnapshotPath: snapPath,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, readTestSnapshot(t, snapPath).PaneGroups, 2)
+
+	_, err = os.Stat(filepath.Join(socketDir, "feat-x.sock"))
+	require.True(t, os.IsNotExist(err), "workspace must still be torn down after autosave")
+}
+
+func TestOpenWorkspace_RestoresSnapshotOnStart(t *testing.T) {
+	// Cannot be parallel — uses t.Setenv.
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+
+	snapPath := filepath.Join(t.TempDir(), "session-snapshot.json")
+	writeTestSnapshot(t, snapPath)
+
+	tmux, _ := newTmux(t)
+	req := &pluginv1.OpenWorkspaceRequest{StoryName: "feat-x", RestoreSnapshotPath: snapPath}
+
+	_, err := tmux.OpenWorkspace(context.Background(), req)
+	require.NoError(t, err)
+
+	// A second open finds the server running and must not restore again.
+	_, err = tmux.OpenWorkspace(context.Background(), req)
+	require.NoError(t, err)
+
+	logBytes, err := os.ReadFile(logFile) //nolint:gosec // G304: test-controlled path
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(logBytes), "-s "+testPaneGroupFull+" -n editor"))
+}
+
+func TestOpenWorkspace_MissingRestoreSnapshotIgnored(t *testing.T) {
+	t.Parallel()
+
+	tmux, _ := newTmux(t)
+
+	_, err := tmux.OpenWorkspace(context.Background(), &pluginv1.OpenWorkspaceRequest{
+		StoryName:           "feat-x",
+		RestoreSnapshotPath: filepath.Join(t.TempDir(), "missing.json"),
+	})
+	require.NoError(t, err)
+}
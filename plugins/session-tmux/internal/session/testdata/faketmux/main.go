@@ -35,10 +35,30 @@ func main() {
 	socket, cmd := parseArgs(args)
 
 	switch cmd {
-	case "new-session":
-		if socket != "" {
+	case "new-session", "new-window":
+		if cmd == "new-session" && socket != "" {
 			os.WriteFile(socket, nil, 0o600) //nolint:errcheck // fake socket creation
 		}
+		// -P prints the new pane ID, as used when restoring snapshots, or
+		// FAKETMUX_NEW_PANE verbatim when set. A format asking for the window
+		// index gets FAKETMUX_WINDOW_INDEX, or 0, first.
+		if hasFlag(args, "-P") {
+			pane := os.Getenv("FAKETMUX_NEW_PANE")
+			if pane == "" {
+				pane = "%0"
+			}
+			if strings.Contains(strings.Join(args, " "), "#{window_index}") {
+				index := os.Getenv("FAKETMUX_WINDOW_INDEX")
+				if index == "" {
+					index = "0"
+				}
+				pane = index + " " + pane
+			}
+			fmt.Println(pane)
+		}
+	case "list-panes":
+		// FAKETMUX_LIST_PANES supplies the formatted pane listing verbatim.
+		fmt.Print(os.Getenv("FAKETMUX_LIST_PANES"))
 	case "kill-server":
 		if socket != "" {
 			os.Remove(socket) //nolint:errcheck // fake socket removal
@@ -49,6 +69,13 @@ func main() {
 				os.Exit(1)
 			}
 		}
+		// <socket>.sessions, when present, supplies a per-server listing;
+		// otherwise FAKETMUX_LIST_SESSIONS supplies it verbatim.
+		if out, err := os.ReadFile(socket + ".sessions"); err == nil {
+			fmt.Print(string(out))
+		} else {
+			fmt.Print(os.Getenv("FAKETMUX_LIST_SESSIONS"))
+		}
 	case "has-session":
 		// Default: session not found so the caller creates it.
 		// Set FAKETMUX_HAS_SESSION=0 to simulate an existing session.
@@ -61,6 +88,9 @@ func main() {
 			fmt.Fprintln(os.Stderr, "no such pane")
 			os.Exit(1)
 		}
+	case "show-options":
+		// FAKETMUX_SHOW_OPTIONS supplies the option value verbatim.
+		fmt.Print(os.Getenv("FAKETMUX_SHOW_OPTIONS"))
 	case "display-message":
 		name := os.Getenv("FAKETMUX_SESSION")
 		if name == "" {
@@ -69,8 +99,21 @@ func main() {
 		fmt.Println(name)
 	case "split-window":
 		// Return a fake pane ID so layout.Apply can reference the new pane.
-		fmt.Println("%1")
+		if pane := os.Getenv("FAKETMUX_NEW_PANE"); pane != "" {
+			fmt.Println(pane)
+		} else {
+			fmt.Println("%1")
+		}
+	}
+}
+
+func hasFlag(args []string, flag string) bool {
+	for _, a := range args {
+		if a == flag {
+			return true
+		}
 	}
+	return false
 }
 
 func parseArgs(args []string) (socket, cmd string) {
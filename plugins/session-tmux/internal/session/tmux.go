@@ -9,8 +9,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/adrg/xdg"
 	"github.com/pelletier/go-toml/v2"
@@ -18,10 +20,13 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
 	"github.com/kalbasit/swm/plugins/session-tmux/internal/layout"
+	"github.com/kalbasit/swm/sdk/go/command"
+	"github.com/kalbasit/swm/sdk/go/trace"
 )
 
 // buildVersion is set via -ldflags at build time.
@@ -30,9 +35,16 @@ var buildVersion = "dev" //nolint:gochecknoglobals // set via ldflags at link ti
 // sessionNameReplacer substitutes characters that are unsafe in tmux session names.
 var sessionNameReplacer = strings.NewReplacer(".", "•", ":", "：") //nolint:gochecknoglobals // package-level replacer
 
+// sessionNameReverser undoes sessionNameReplacer to recover a project key.
+var sessionNameReverser = strings.NewReplacer("•", ".", "：", ":") //nolint:gochecknoglobals // package-level replacer
+
 // tmuxConfig holds the plugin-specific config read from the host.
 type tmuxConfig struct {
 	PaneGroupCommand string `toml:"pane_group_command"`
+
+	// RestoreCommands is the allowlist of foreground commands restarted when a
+	// snapshot is restored. When unset, defaultRestoreCommands is used.
+	RestoreCommands []string `toml:"restore_commands"`
 }
 
 // Tmux implements pluginv1.SessionServer by shelling out to the system tmux.
@@ -47,15 +59,9 @@ type Tmux struct {
 // New returns a Tmux instance using the system tmux binary.
 // It connects to SWM_HOST_SOCKET if set, enabling host config lookups.
 func New() (*Tmux, error) {
-	bin, err := exec.LookPath("tmux")
+	t, err := NewWithClient(nil)
 	if err != nil {
-		return nil, fmt.Errorf("tmux binary not found in PATH: %w", err)
-	}
-
-	t := &Tmux{
-		tmuxBin:    bin,
-		socketDir:  filepath.Join(xdg.RuntimeDir, "swm", "tmux"),
-		configHome: xdg.ConfigHome,
+		return nil, err
 	}
 
 	if sock := os.Getenv("SWM_HOST_SOCKET"); sock != "" {
@@ -74,6 +80,23 @@ func New() (*Tmux, error) {
 	return t, nil
 }
 
+// NewWithClient returns a Tmux instance using the system tmux binary and the
+// given host client, which may be nil, as when swm runs the plugin in its own
+// process.
+func NewWithClient(client pluginv1.HostClient) (*Tmux, error) {
+	bin, err := exec.LookPath("tmux")
+	if err != nil {
+		return nil, fmt.Errorf("tmux binary not found in PATH: %w", err)
+	}
+
+	return &Tmux{
+		tmuxBin:    bin,
+		socketDir:  filepath.Join(xdg.RuntimeDir, "swm", "tmux"),
+		configHome: xdg.ConfigHome,
+		hostClient: client,
+	}, nil
+}
+
 // NewWithBin returns a Tmux instance with an injected binary path and socket dir (for tests).
 func NewWithBin(tmuxBin, socketDir string) *Tmux {
 	return &Tmux{tmuxBin: tmuxBin, socketDir: socketDir, configHome: xdg.ConfigHome}
@@ -97,6 +120,22 @@ func NewWithBinClientAndConfigHome(tmuxBin, socketDir, configHome string, client
 	return &Tmux{tmuxBin: tmuxBin, socketDir: socketDir, configHome: configHome, hostClient: client}
 }
 
+// AttachCommand returns the tmux attach-session command for a pane group
+// without running it, so a workspace prepared with `swm workspace open
+// --no-attach` can be attached to later.
+func (t *Tmux) AttachCommand(
+	_ context.Context,
+	req *pluginv1.AttachCommandRequest,
+) (*pluginv1.AttachCommandResponse, error) {
+	if req.GetWorkspaceId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "workspace_id is required")
+	}
+
+	return &pluginv1.AttachCommandResponse{
+		Argv: t.attachArgv(req.GetWorkspaceId(), req.GetPaneGroupId()),
+	}, nil
+}
+
 // Close releases the gRPC connection to the host service.
 func (t *Tmux) Close() error {
 	if t.grpcConn != nil {
@@ -106,10 +145,50 @@ func (t *Tmux) Close() error {
 	return nil
 }
 
+// ClosePaneGroup kills a single pane group's tmux session, leaving the rest of
+// the workspace running. Closing a pane group that no longer exists succeeds.
+func (t *Tmux) ClosePaneGroup(ctx context.Context, req *pluginv1.ClosePaneGroupRequest) (*pluginv1.Empty, error) {
+	sock := req.GetWorkspaceId()
+	name := req.GetPaneGroupId()
+
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "pane_group_id is required")
+	}
+
+	if _, err := os.Stat(sock); os.IsNotExist(err) {
+		return &pluginv1.Empty{}, nil
+	}
+
+	// "=" makes tmux match the name exactly instead of as a prefix, so a gone
+	// pane group never resolves to another whose name extends it.
+	if _, err := t.run(ctx, "-S", sock, "has-session", "-t", "="+name); err != nil {
+		return &pluginv1.Empty{}, nil //nolint:nilerr // pane group already gone; close is idempotent
+	}
+
+	if _, err := t.run(ctx, "-S", sock, "kill-session", "-t", "="+name); err != nil {
+		return nil, err
+	}
+
+	return &pluginv1.Empty{}, nil
+}
+
 // CloseWorkspace tears down the tmux server for the given workspace.
+// When snapshot_path is set, the workspace is saved first; a failed save
+// leaves the server running so no state is lost.
 func (t *Tmux) CloseWorkspace(ctx context.Context, req *pluginv1.CloseWorkspaceRequest) (*pluginv1.Empty, error) {
 	sock := req.GetWorkspaceId()
 
+	if req.GetSnapshotPath() != "" {
+		if _, err := os.Stat(sock); err == nil {
+			if _, err := t.SaveWorkspace(ctx, &pluginv1.SaveWorkspaceRequest{
+				WorkspaceId:  sock,
+				SnapshotPath: req.GetSnapshotPath(),
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Kill the tmux server; ignore errors — socket may already be gone.
 	_, _ = t.run(ctx, "-S", sock, "kill-server") //nolint:errcheck // best-effort kill server
 	_ = os.Remove(sock)                          //nolint:errcheck // best-effort socket cleanup
@@ -164,33 +243,52 @@ func (t *Tmux) IsInsideWorkspace(_ context.Context, _ *pluginv1.Empty) (*pluginv
 	return &pluginv1.BoolValue{Value: inside}, nil
 }
 
-// ListWorkspaces streams all live swm tmux workspaces.
-func (t *Tmux) ListWorkspaces(_ *pluginv1.Empty, stream pluginv1.Session_ListWorkspacesServer) error {
-	entries, err := os.ReadDir(t.socketDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
+// paneGroupFormat is the list-sessions format used by ListPaneGroups.
+const paneGroupFormat = "#{session_name}\t#{session_path}\t#{session_windows}\t#{session_activity}"
 
-		return status.Errorf(codes.Internal, "reading socket dir: %v", err)
-	}
+// ListPaneGroups streams the live project sessions of a workspace, or of every
+// live workspace when workspace_id is empty. The bootstrap session that keeps
+// the server alive is not a pane group and is skipped. A workspace that is not
+// running has no pane groups.
+func (t *Tmux) ListPaneGroups(req *pluginv1.ListPaneGroupsRequest, stream pluginv1.Session_ListPaneGroupsServer) error {
+	socks := []string{req.GetWorkspaceId()}
 
-	for _, e := range entries {
-		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sock") {
-			continue
+	if req.GetWorkspaceId() == "" {
+		var err error
+
+		socks, err = t.liveSockets(stream.Context())
+		if err != nil {
+			return err
 		}
+	}
 
-		sock := filepath.Join(t.socketDir, e.Name())
+	for _, sock := range socks {
+		groups, err := t.paneGroups(stream.Context(), sock)
+		if err != nil {
+			return err
+		}
 
-		// Probe liveness — skip dead sockets.
-		if _, err := t.run(stream.Context(), "-S", sock, "list-sessions"); err != nil {
-			continue
+		for _, pg := range groups {
+			if err := stream.Send(pg); err != nil {
+				return err
+			}
 		}
+	}
+
+	return nil
+}
+
+// ListWorkspaces streams all live swm tmux workspaces.
+func (t *Tmux) ListWorkspaces(_ *pluginv1.Empty, stream pluginv1.Session_ListWorkspacesServer) error {
+	socks, err := t.liveSockets(stream.Context())
+	if err != nil {
+		return err
+	}
 
-		storyName := strings.TrimSuffix(e.Name(), ".sock")
+	for _, sock := range socks {
 		if err := stream.Send(&pluginv1.Workspace{
 			WorkspaceId: sock,
-			StoryName:   storyName,
+			StoryName:   storyNameFromSocket(sock),
 		}); err != nil {
 			return err
 		}
@@ -225,6 +323,8 @@ func (t *Tmux) OpenPaneGroup(ctx context.Context, req *pluginv1.OpenPaneGroupReq
 	// Create session if it doesn't exist yet.
 	if _, err := t.run(ctx, "-S", sock, "has-session", "-t", name); err != nil {
 		args := []string{"-S", sock, "new-session", "-d", "-s", name, "-c", req.GetWorktreePath()}
+		args = append(args, envFlags(req.GetEnv())...)
+
 		if initialCmd != "" {
 			args = append(args, initialCmd)
 		}
@@ -254,6 +354,10 @@ func (t *Tmux) OpenPaneGroup(ctx context.Context, req *pluginv1.OpenPaneGroupReq
 // A single bootstrap session (named after the story) is created to keep the
 // server alive; project sessions are created lazily by OpenPaneGroup so that
 // pane_group_command is applied to each one individually.
+//
+// When restore_snapshot_path is set and the server had to be started, the
+// pane groups recorded in that snapshot are recreated. A missing snapshot
+// file is ignored.
 func (t *Tmux) OpenWorkspace(ctx context.Context, req *pluginv1.OpenWorkspaceRequest) (*pluginv1.Workspace, error) {
 	sock := t.socketPath(req.GetStoryName())
 
@@ -265,13 +369,17 @@ func (t *Tmux) OpenWorkspace(ctx context.Context, req *pluginv1.OpenWorkspaceReq
 	// story keeps the server alive (tmux exits with exit-empty=on when there are
 	// no sessions). Project sessions use "host/org/repo" names and never collide
 	// with the short story name used here.
+	started := false
+
 	if _, err := t.run(ctx, "-S", sock, "list-sessions"); err != nil {
 		bootstrapName := sessionName(req.GetStoryName())
 
 		args := []string{"-S", sock, "new-session", "-d", "-s", bootstrapName, "-e", "SWM_STORY=" + req.GetStoryName()}
-		if _, err := t.run(ctx, args...); err != nil {
+		if _, err := t.run(ctx, append(args, envFlags(req.GetEnv())...)...); err != nil {
 			return nil, err
 		}
+
+		started = true
 	}
 
 	// Propagate the story name so shells inside the workspace can run
@@ -280,6 +388,25 @@ func (t *Tmux) OpenWorkspace(ctx context.Context, req *pluginv1.OpenWorkspaceReq
 		return nil, err
 	}
 
+	if err := t.setStoryEnv(ctx, sock, req.GetEnv()); err != nil {
+		return nil, err
+	}
+
+	if started && req.GetRestoreSnapshotPath() != "" {
+		snap, err := readSnapshot(req.GetRestoreSnapshotPath())
+
+		switch {
+		case os.IsNotExist(err):
+			// Nothing saved yet; start with an empty workspace.
+		case err != nil:
+			return nil, status.Errorf(codes.Internal, "reading snapshot: %v", err)
+		default:
+			if _, err := t.restoreSnapshot(ctx, sock, snap); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return &pluginv1.Workspace{
 		WorkspaceId: sock,
 		StoryName:   req.GetStoryName(),
@@ -287,9 +414,11 @@ func (t *Tmux) OpenWorkspace(ctx context.Context, req *pluginv1.OpenWorkspaceReq
 }
 
 // SwitchTo brings the given pane group into focus.
-// When the caller is already inside a tmux session, it calls switch-client directly.
-// When not inside tmux, it returns exec_argv so the host can exec tmux attach-session
-// with the terminal it holds — the plugin subprocess has no TTY.
+// When the caller is already attached to the target server, it calls switch-client
+// directly. When the caller is attached to a different tmux server, the client is
+// detached with detach-client -E so that it re-attaches to the target server in
+// place. When not inside tmux, it returns exec_argv so the host can exec tmux
+// attach-session with the terminal it holds — the plugin subprocess has no TTY.
 //
 // When close_origin_pane_id is set, the originating pane is killed inside this
 // handler before the response is returned, so that the kill runs even when the
@@ -298,18 +427,33 @@ func (t *Tmux) SwitchTo(ctx context.Context, req *pluginv1.SwitchToRequest) (*pl
 	sock := req.GetWorkspaceId()
 	target := req.GetPaneGroupId()
 
+	// $TMUX is "<socket-path>,<pid>,<session-id>"
+	current := strings.SplitN(os.Getenv("TMUX"), ",", 2)[0] //nolint:mnd // socket path is the first field
+
 	var resp *pluginv1.SwitchToResponse
 
-	if os.Getenv("TMUX") != "" {
+	switch current {
+	case "":
+		resp = &pluginv1.SwitchToResponse{ExecArgv: t.attachArgv(sock, target)}
+	case sock:
 		if _, err := t.run(ctx, "-S", sock, "switch-client", "-t", target); err != nil {
 			return nil, err
 		}
 
 		resp = &pluginv1.SwitchToResponse{}
-	} else {
-		resp = &pluginv1.SwitchToResponse{
-			ExecArgv: []string{t.tmuxBin, "-S", sock, "attach-session", "-t", target},
+	default:
+		// switch-client cannot cross servers; replace the attached client with
+		// one attached to the target server instead.
+		attach := strings.Join([]string{
+			"exec", layout.ShellQuote(t.tmuxBin), "-S", layout.ShellQuote(sock),
+			"attach-session", "-t", layout.ShellQuote(target),
+		}, " ")
+
+		if _, err := t.run(ctx, "-S", current, "detach-client", "-E", attach); err != nil {
+			return nil, err
 		}
+
+		resp = &pluginv1.SwitchToResponse{}
 	}
 
 	if err := t.killOriginPane(ctx, req.GetCloseOriginWorkspaceId(), req.GetCloseOriginPaneId()); err != nil {
@@ -319,19 +463,21 @@ func (t *Tmux) SwitchTo(ctx context.Context, req *pluginv1.SwitchToRequest) (*pl
 	return resp, nil
 }
 
+// attachArgv is the command attaching a terminal to target on the tmux server
+// at sock, or to its most recent session when target is empty.
+func (t *Tmux) attachArgv(sock, target string) []string {
+	argv := []string{t.tmuxBin, "-S", sock, "attach-session"}
+	if target != "" {
+		argv = append(argv, "-t", target)
+	}
+
+	return argv
+}
+
 // applyLayout resolves and applies the session-tmux layout for a newly created pane group.
 // Falls back to the built-in default layout (editor + shell) when no config file exists.
 func (t *Tmux) applyLayout(ctx context.Context, sock, sessionName string, req *pluginv1.OpenPaneGroupRequest) error {
-	storyName := strings.TrimSuffix(filepath.Base(req.GetWorkspaceId()), ".sock")
-	pid := req.GetProjectId()
-	vars := layout.TemplateVars{
-		WorktreePath: req.GetWorktreePath(),
-		StoryName:    storyName,
-		ProjectID:    pid.GetHost() + "/" + strings.Join(pid.GetSegments(), "/"),
-		TmuxSocket:   req.GetWorkspaceId(),
-	}
-
-	cfg, err := layout.LoadConfig(req.GetWorktreePath(), t.configHome, vars)
+	cfg, err := layout.LoadConfig(req.GetWorktreePath(), t.configHome, templateVars(req))
 	if err != nil {
 		return err
 	}
@@ -401,45 +547,44 @@ func (t *Tmux) layoutConfigExists(worktreePath string) bool {
 // no command is configured. Returns a non-nil error when the configured command's
 // template is invalid or references an unknown variable.
 func (t *Tmux) paneGroupCommand(ctx context.Context, req *pluginv1.OpenPaneGroupRequest) (string, error) {
-	if t.hostClient == nil {
+	cfg := t.loadConfig(ctx)
+	if cfg.PaneGroupCommand == "" {
 		return "", nil
 	}
 
-	resp, err := t.hostClient.GetConfig(ctx, &pluginv1.GetConfigRequest{PluginName: "session-tmux"})
+	tmpl, err := template.New("cmd").Option("missingkey=error").Parse(cfg.PaneGroupCommand)
 	if err != nil {
-		return "", nil //nolint:nilerr // host RPC failure means no config available; not a user-facing error
+		return "", status.Errorf(codes.InvalidArgument, "pane_group_command template parse error: %v", err)
 	}
 
-	var cfg tmuxConfig
-	if err := toml.Unmarshal(resp.GetToml(), &cfg); err != nil {
-		return "", nil //nolint:nilerr // malformed TOML treated as unconfigured; host validates config
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateVars(req)); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "pane_group_command template execute error: %v", err)
 	}
 
-	if cfg.PaneGroupCommand == "" {
-		return "", nil
-	}
+	return buf.String(), nil
+}
 
-	storyName := strings.TrimSuffix(filepath.Base(req.GetWorkspaceId()), ".sock")
+// loadConfig fetches the session-tmux section of the host config. Any failure
+// (no host connection, RPC error, malformed TOML) yields the zero config.
+func (t *Tmux) loadConfig(ctx context.Context) tmuxConfig {
+	var cfg tmuxConfig
 
-	pid := req.GetProjectId()
-	vars := layout.TemplateVars{
-		WorktreePath: req.GetWorktreePath(),
-		StoryName:    storyName,
-		ProjectID:    pid.GetHost() + "/" + strings.Join(pid.GetSegments(), "/"),
-		TmuxSocket:   req.GetWorkspaceId(),
+	if t.hostClient == nil {
+		return cfg
 	}
 
-	tmpl, err := template.New("cmd").Option("missingkey=error").Parse(cfg.PaneGroupCommand)
+	resp, err := t.hostClient.GetConfig(ctx, &pluginv1.GetConfigRequest{PluginName: "session-tmux"})
 	if err != nil {
-		return "", status.Errorf(codes.InvalidArgument, "pane_group_command template parse error: %v", err)
+		return cfg
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, vars); err != nil {
-		return "", status.Errorf(codes.InvalidArgument, "pane_group_command template execute error: %v", err)
+	if err := toml.Unmarshal(resp.GetToml(), &cfg); err != nil {
+		// Malformed TOML is treated as unconfigured; the host validates config.
+		return tmuxConfig{}
 	}
 
-	return buf.String(), nil
+	return cfg
 }
 
 // validateCommandBinary checks that the first token of cmd resolves to an
@@ -477,14 +622,21 @@ func defaultLayout() *layout.Config {
 }
 
 func (t *Tmux) run(ctx context.Context, args ...string) (string, error) {
+	ctx, span := trace.Start(ctx, "tmux "+args[0], "args", strings.Join(args, " "))
+	defer span.End()
+
 	var stderr bytes.Buffer
 
-	cmd := exec.CommandContext(ctx, t.tmuxBin, args...) //nolint:gosec // tmuxBin from LookPath, args are controlled
+	cmd := command.New(ctx, t.tmuxBin, args...)
 	cmd.Env = filteredEnv()
 	cmd.Stderr = &stderr
 
 	out, err := cmd.Output()
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", status.FromContextError(ctx.Err()).Err()
+		}
+
 		return "", status.Errorf(codes.Internal, "tmux %s: %s", strings.Join(args, " "), stderr.String())
 	}
 
@@ -496,8 +648,127 @@ func (t *Tmux) socketPath(storyName string) string {
 	return filepath.Join(t.socketDir, storyName+".sock")
 }
 
+// liveSockets returns the sockets in socketDir whose tmux server answers.
+func (t *Tmux) liveSockets(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(t.socketDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, status.Errorf(codes.Internal, "reading socket dir: %v", err)
+	}
+
+	var socks []string
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sock") {
+			continue
+		}
+
+		sock := filepath.Join(t.socketDir, e.Name())
+
+		// Probe liveness — skip dead sockets.
+		if _, err := t.run(ctx, "-S", sock, "list-sessions"); err != nil {
+			continue
+		}
+
+		socks = append(socks, sock)
+	}
+
+	return socks, nil
+}
+
+// paneGroups lists the project sessions on the server at sock.
+func (t *Tmux) paneGroups(ctx context.Context, sock string) ([]*pluginv1.PaneGroup, error) {
+	if _, err := os.Stat(sock); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	out, err := t.run(ctx, "-S", sock, "list-sessions", "-F", paneGroupFormat)
+	if err != nil {
+		return nil, nil //nolint:nilerr // dead server behind a stale socket has no pane groups
+	}
+
+	storyName := storyNameFromSocket(sock)
+
+	var groups []*pluginv1.PaneGroup
+
+	for line := range strings.SplitSeq(out, "\n") {
+		f := strings.Split(line, "\t")
+
+		pid := projectIDFromSessionName(f[0])
+		if pid == nil {
+			continue
+		}
+
+		pg := &pluginv1.PaneGroup{
+			PaneGroupId: f[0],
+			WorkspaceId: sock,
+			ProjectId:   pid,
+			StoryName:   storyName,
+		}
+
+		if len(f) > 1 {
+			pg.WorktreePath = f[1]
+		}
+
+		if len(f) > 2 { //nolint:mnd // session_windows field
+			n, _ := strconv.Atoi(f[2]) //nolint:errcheck // informational; zero when unparsable
+			pg.WindowCount = int32(n)  //nolint:gosec // window count never approaches int32 max
+		}
+
+		if len(f) > 3 { //nolint:mnd // session_activity field
+			if sec, err := strconv.ParseInt(f[3], 10, 64); err == nil {
+				pg.LastActivity = timestamppb.New(time.Unix(sec, 0))
+			}
+		}
+
+		groups = append(groups, pg)
+	}
+
+	return groups, nil
+}
+
+// storyNameFromSocket derives the story name from a workspace socket path.
+func storyNameFromSocket(sock string) string {
+	return strings.TrimSuffix(filepath.Base(sock), ".sock")
+}
+
+// projectIDFromSessionName reverses sessionName for a pane group session.
+// It returns nil for sessions that do not name a project (such as the
+// bootstrap session, which is named after the story).
+func projectIDFromSessionName(name string) *pluginv1.ProjectID {
+	parts := strings.Split(sessionNameReverser.Replace(name), "/")
+	if len(parts) < 2 || parts[0] == "" {
+		return nil
+	}
+
+	return &pluginv1.ProjectID{Host: parts[0], Segments: parts[1:]}
+}
+
 // sessionName derives a tmux-safe session name from a worktree map key (host/seg/.../last).
 // Dots and colons are replaced with tmux-safe Unicode equivalents; slashes are preserved.
 func sessionName(key string) string {
 	return sessionNameReplacer.Replace(key)
 }
+
+// templateVars returns the layout template variables of the pane group req
+// opens.
+func templateVars(req *pluginv1.OpenPaneGroupRequest) layout.TemplateVars {
+	pid := req.GetProjectId()
+
+	ports := make(map[string]int, len(req.GetPorts()))
+	for name, port := range req.GetPorts() {
+		ports[name] = int(port)
+	}
+
+	return layout.TemplateVars{
+		WorktreePath: req.GetWorktreePath(),
+		StoryName:    storyNameFromSocket(req.GetWorkspaceId()),
+		ProjectID:    pid.GetHost() + "/" + strings.Join(pid.GetSegments(), "/"),
+		TmuxSocket:   req.GetWorkspaceId(),
+		PortBase:     int(req.GetPortBase()),
+		Ports:        ports,
+	}
+}
@@ -240,6 +240,27 @@ func TestSwitchTo_OutsideTmux_ReturnsExecArgv(t *testing.T) {
 	}
 }
 
+func TestAttachCommand(t *testing.T) {
+	t.Parallel()
+
+	tmux, socketDir := newTmux(t)
+	sock := filepath.Join(socketDir, "feat-x.sock")
+
+	resp, err := tmux.AttachCommand(context.Background(), &pluginv1.AttachCommandRequest{
+		WorkspaceId: sock,
+		PaneGroupId: testPaneGroup,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{faketmuxBin, "-S", sock, "attach-session", "-t", testPaneGroup}, resp.GetArgv())
+
+	resp, err = tmux.AttachCommand(context.Background(), &pluginv1.AttachCommandRequest{WorkspaceId: sock})
+	require.NoError(t, err)
+	require.Equal(t, []string{faketmuxBin, "-S", sock, "attach-session"}, resp.GetArgv())
+
+	_, err = tmux.AttachCommand(context.Background(), &pluginv1.AttachCommandRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 func TestSwitchTo_InsideTmux_CallsSwitchClient(t *testing.T) {
 	// Cannot be parallel — sets env vars.
 	logFile := filepath.Join(t.TempDir(), "tmux.log")
@@ -264,6 +285,30 @@ func TestSwitchTo_InsideTmux_CallsSwitchClient(t *testing.T) {
 	require.Contains(t, string(logBytes), "switch-client", "faketmux must be called with switch-client")
 }
 
+func TestSwitchTo_OtherServer_DetachesAndReattaches(t *testing.T) {
+	// Cannot be parallel — sets env vars.
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+
+	tmux, socketDir := newTmux(t)
+	current := filepath.Join(socketDir, "feat-a.sock")
+	target := filepath.Join(socketDir, "feat-b.sock")
+	t.Setenv("TMUX", current+",12345,0")
+
+	resp, err := tmux.SwitchTo(context.Background(), &pluginv1.SwitchToRequest{
+		WorkspaceId: target,
+		PaneGroupId: testPaneGroupFull,
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.GetExecArgv(), "the attached client is replaced in place")
+
+	logBytes, err := os.ReadFile(logFile) //nolint:gosec // G304: test-controlled path
+	require.NoError(t, err)
+	require.Contains(t, string(logBytes),
+		"-S "+current+" detach-client -E exec "+faketmuxBin+" -S "+target+" attach-session -t "+testPaneGroupFull)
+	require.NotContains(t, string(logBytes), "switch-client")
+}
+
 func TestOpenWorkspace_SetsSWMStory(t *testing.T) {
 	// Cannot be parallel — uses FAKETMUX_LOG env var.
 	logFile := filepath.Join(t.TempDir(), "tmux.log")
@@ -439,6 +484,39 @@ func TestOpenPaneGroup_WithPaneGroupCommand_SocketSubstitution(t *testing.T) {
 		"{{.TmuxSocket}} must be substituted with the workspace socket path")
 }
 
+func TestOpenPaneGroup_WithPaneGroupCommand_PortSubstitution(t *testing.T) {
+	// Cannot be parallel — uses t.Setenv.
+	socketDir := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+
+	sockPath := filepath.Join(socketDir, "feat-ports.sock")
+
+	toml := `pane_group_command = "` + faketmuxBin +
+		` --ports {{.PortBase}} {{.Ports.api}}"`
+
+	tmux := session.NewWithBinAndClient(faketmuxBin, socketDir, &fakeHostClient{toml: []byte(toml)})
+
+	if err := os.WriteFile(sockPath, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := tmux.OpenPaneGroup(context.Background(), &pluginv1.OpenPaneGroupRequest{
+		WorkspaceId:  sockPath,
+		ProjectId:    &pluginv1.ProjectID{Host: testHost, Segments: []string{testOrg, testRepo}},
+		WorktreePath: "/tmp/stories/feat-ports/github.com/kalbasit/swm",
+		PortBase:     20100,
+		Ports:        map[string]uint32{"api": 20101},
+	})
+	require.NoError(t, err)
+
+	logBytes, err := os.ReadFile(logFile) //nolint:gosec // G304: test-controlled path
+	require.NoError(t, err)
+
+	require.Contains(t, string(logBytes), "--ports 20100 20101",
+		"{{.PortBase}} and {{.Ports.<name>}} must be substituted with the story's ports")
+}
+
 func TestOpenPaneGroup_PaneGroupCommandWhitespaceOnly(t *testing.T) {
 	// Cannot be parallel — uses t.Setenv.
 	socketDir := t.TempDir()
@@ -594,6 +672,7 @@ func TestOpenWorkspace_EnvIsolation_PluginInternalVarsAbsent(t *testing.T) {
 	envFile := filepath.Join(t.TempDir(), "env.log")
 	t.Setenv("FAKETMUX_ENV_LOG", envFile)
 
+	t.Setenv("SWM_CALLER_TOKEN", "token")
 	t.Setenv("SWM_HOST_SOCKET", "unix:///run/user/1000/swm/test.sock")
 	t.Setenv("SWM_LOG_LEVEL", "debug")
 	t.Setenv("SWM_PLUGIN_MAGIC_COOKIE", "swm-plugin-v1")
@@ -609,6 +688,7 @@ func TestOpenWorkspace_EnvIsolation_PluginInternalVarsAbsent(t *testing.T) {
 	require.NoError(t, err)
 
 	envContents := string(envBytes)
+	require.NotContains(t, envContents, "SWM_CALLER_TOKEN=")
 	require.NotContains(t, envContents, "SWM_HOST_SOCKET=")
 	require.NotContains(t, envContents, "SWM_LOG_LEVEL=")
 	require.NotContains(t, envContents, "SWM_PLUGIN_MAGIC_COOKIE=")
@@ -1066,3 +1146,138 @@ func (s *collectWorkspaceStream) Send(ws *pluginv1.Workspace) error {
 
 	return nil
 }
+
+func TestListPaneGroups(t *testing.T) {
+	// Cannot be parallel — uses t.Setenv.
+	t.Setenv("FAKETMUX_LIST_SESSIONS",
+		"feat-x\t/home/me\t1\t1700000000\n"+
+			testPaneGroupFull+"\t/tmp/wt\t3\t1700000123\n"+
+			"gitlab•example•com：8443/group/sub/repo\t/tmp/other\t1\t1700000000\n")
+
+	tmux, socketDir := newTmux(t)
+	sock := filepath.Join(socketDir, "feat-x.sock")
+	require.NoError(t, os.WriteFile(sock, nil, 0o600))
+
+	stream := &collectPaneGroupStream{ctx: context.Background()}
+	require.NoError(t, tmux.ListPaneGroups(&pluginv1.ListPaneGroupsRequest{WorkspaceId: sock}, stream))
+
+	require.Len(t, stream.items, 2, "bootstrap session must not be reported as a pane group")
+
+	require.Equal(t, testPaneGroupFull, stream.items[0].GetPaneGroupId())
+	require.Equal(t, sock, stream.items[0].GetWorkspaceId())
+	require.Equal(t, testHost, stream.items[0].GetProjectId().GetHost())
+	require.Equal(t, []string{testOrg, testRepo}, stream.items[0].GetProjectId().GetSegments())
+	require.Equal(t, "/tmp/wt", stream.items[0].GetWorktreePath())
+	require.Equal(t, "feat-x", stream.items[0].GetStoryName())
+	require.Equal(t, int32(3), stream.items[0].GetWindowCount())
+	require.Equal(t, int64(1700000123), stream.items[0].GetLastActivity().GetSeconds())
+
+	require.Equal(t, "gitlab.example.com:8443", stream.items[1].GetProjectId().GetHost())
+	require.Equal(t, []string{"group", "sub", "repo"}, stream.items[1].GetProjectId().GetSegments())
+}
+
+func TestListPaneGroups_WorkspaceNotRunning(t *testing.T) {
+	t.Parallel()
+
+	tmux, socketDir := newTmux(t)
+
+	stream := &collectPaneGroupStream{ctx: context.Background()}
+	require.NoError(t, tmux.ListPaneGroups(&pluginv1.ListPaneGroupsRequest{
+		WorkspaceId: filepath.Join(socketDir, "missing.sock"),
+	}, stream))
+	require.Empty(t, stream.items)
+}
+
+func TestListPaneGroups_AllWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	tmux, socketDir := newTmux(t)
+
+	sockA := filepath.Join(socketDir, "feat-a.sock")
+	sockB := filepath.Join(socketDir, "feat-b.sock")
+	require.NoError(t, os.WriteFile(sockA, nil, 0o600))
+	require.NoError(t, os.WriteFile(sockB, nil, 0o600))
+	require.NoError(t, os.WriteFile(sockA+".sessions", []byte("feat-a\t/home/me\t1\t0\n"+testPaneGroupFull+"\t/tmp/a\t2\t0\n"), 0o600))
+	require.NoError(t, os.WriteFile(sockB+".sessions", []byte("github•com/a/b\t/tmp/b\t1\t0\n"), 0o600))
+
+	stream := &collectPaneGroupStream{ctx: context.Background()}
+	require.NoError(t, tmux.ListPaneGroups(&pluginv1.ListPaneGroupsRequest{}, stream))
+
+	require.Len(t, stream.items, 2)
+	require.Equal(t, "feat-a", stream.items[0].GetStoryName())
+	require.Equal(t, sockA, stream.items[0].GetWorkspaceId())
+	require.Equal(t, testPaneGroupFull, stream.items[0].GetPaneGroupId())
+	require.Equal(t, "feat-b", stream.items[1].GetStoryName())
+	require.Equal(t, sockB, stream.items[1].GetWorkspaceId())
+	require.Equal(t, "github•com/a/b", stream.items[1].GetPaneGroupId())
+}
+
+func TestClosePaneGroup_KillsSession(t *testing.T) {
+	// Cannot be parallel — uses t.Setenv.
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+	t.Setenv("FAKETMUX_HAS_SESSION", "0")
+
+	tmux, socketDir := newTmux(t)
+	sock := filepath.Join(socketDir, "feat-x.sock")
+	require.NoError(t, os.WriteFile(sock, nil, 0o600))
+
+	_, err := tmux.ClosePaneGroup(context.Background(), &pluginv1.ClosePaneGroupRequest{
+		WorkspaceId: sock,
+		PaneGroupId: testPaneGroupFull,
+	})
+	require.NoError(t, err)
+
+	logBytes, err := os.ReadFile(logFile) //nolint:gosec // G304: test-controlled path
+	require.NoError(t, err)
+	require.Contains(t, string(logBytes), "has-session -t ="+testPaneGroupFull, "targets must match the name exactly")
+	require.Contains(t, string(logBytes), "kill-session -t ="+testPaneGroupFull, "targets must match the name exactly")
+	require.NotContains(t, string(logBytes), "kill-server", "closing a pane group must not kill the workspace")
+
+	_, err = os.Stat(sock)
+	require.NoError(t, err, "workspace socket must survive")
+}
+
+func TestClosePaneGroup_AlreadyGone(t *testing.T) {
+	// Cannot be parallel — uses t.Setenv.
+	logFile := filepath.Join(t.TempDir(), "tmux.log")
+	t.Setenv("FAKETMUX_LOG", logFile)
+
+	tmux, socketDir := newTmux(t)
+	sock := filepath.Join(socketDir, "feat-x.sock")
+	require.NoError(t, os.WriteFile(sock, nil, 0o600))
+
+	_, err := tmux.ClosePaneGroup(context.Background(), &pluginv1.ClosePaneGroupRequest{
+		WorkspaceId: sock,
+		PaneGroupId: testPaneGroupFull,
+	})
+	require.NoError(t, err)
+
+	logBytes, err := os.ReadFile(logFile) //nolint:gosec // G304: test-controlled path
+	require.NoError(t, err)
+	require.NotContains(t, string(logBytes), "kill-session")
+}
+
+func TestClosePaneGroup_RequiresPaneGroupID(t *testing.T) {
+	t.Parallel()
+
+	tmux, _ := newTmux(t)
+
+	_, err := tmux.ClosePaneGroup(context.Background(), &pluginv1.ClosePaneGroupRequest{WorkspaceId: "/tmp/x.sock"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// collectPaneGroupStream implements pluginv1.Session_ListPaneGroupsServer for tests.
+type collectPaneGroupStream struct {
+	pluginv1.Session_ListPaneGroupsServer
+	ctx   context.Context
+	items []*pluginv1.PaneGroup
+}
+
+func (s *collectPaneGroupStream) Context() context.Context { return s.ctx }
+
+func (s *collectPaneGroupStream) Send(pg *pluginv1.PaneGroup) error {
+	s.items = append(s.items, pg)
+
+	return nil
+}
@@ -0,0 +1,490 @@
+// Package tracker implements the swm Tracker capability for Jira.
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+)
+
+// buildVersion is set via -ldflags at build time.
+var buildVersion = "dev" //nolint:gochecknoglobals // set via ldflags at link time
+
+const (
+	// defaultJQL selects the open issues assigned to the authenticated user.
+	defaultJQL = "assignee = currentUser() AND statusCategory != Done ORDER BY updated DESC"
+
+	// issueFields are the fields requested for every issue.
+	issueFields = "summary,description,status,issuetype,assignee"
+
+	// searchPageSize is the number of issues requested per search page.
+	searchPageSize = 100
+)
+
+var errNoTransitions = errors.New("no matching transition")
+
+type jiraConfig struct {
+	BaseURL   string `toml:"base_url"`
+	Email     string `toml:"email"`
+	TokenPath string `toml:"token_path"`
+	JQL       string `toml:"jql"`
+}
+
+// Option configures a Jira tracker server.
+type Option func(*Jira)
+
+// WithHTTPClient overrides the HTTP client used to call the Jira REST API.
+func WithHTTPClient(c *http.Client) Option {
+	return func(j *Jira) {
+		j.httpClient = c
+	}
+}
+
+// WithGetenv overrides the function used to read environment variables.
+func WithGetenv(fn func(string) string) Option {
+	return func(j *Jira) {
+		j.getenv = fn
+	}
+}
+
+// Jira implements pluginv1.TrackerServer for Jira Cloud and Jira Data Center.
+type Jira struct {
+	pluginv1.UnimplementedTrackerServer
+	hostClient pluginv1.HostClient
+	httpClient *http.Client
+	getenv     func(string) string
+}
+
+// New returns a Jira tracker server backed by the given host client.
+func New(hostClient pluginv1.HostClient, opts ...Option) *Jira {
+	j := &Jira{
+		hostClient: hostClient,
+		httpClient: http.DefaultClient,
+		getenv:     os.Getenv,
+	}
+	for _, o := range opts {
+		o(j)
+	}
+
+	return j
+}
+
+// Info returns plugin metadata.
+func (j *Jira) Info(_ context.Context, _ *pluginv1.Empty) (*pluginv1.TrackerInfo, error) {
+	return &pluginv1.TrackerInfo{
+		PluginInfo: &pluginv1.PluginInfo{
+			Name:    "jira",
+			Version: buildVersion,
+		},
+	}, nil
+}
+
+// GetIssue fetches a single issue by key.
+func (j *Jira) GetIssue(ctx context.Context, req *pluginv1.GetIssueRequest) (*pluginv1.Issue, error) {
+	if req.GetKey() == "" {
+		return nil, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	c, err := j.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getIssue(ctx, req.GetKey())
+}
+
+// ListMyIssues streams the open issues assigned to the authenticated user,
+// most recently updated first. The query can be replaced with the jql config key.
+func (j *Jira) ListMyIssues(_ *pluginv1.ListMyIssuesRequest, stream pluginv1.Tracker_ListMyIssuesServer) error {
+	ctx := stream.Context()
+
+	c, err := j.newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	send := func(issues []jiraIssue) error {
+		for i := range issues {
+			if err := stream.Send(c.toProto(&issues[i])); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	err = c.searchJQL(ctx, send)
+	if status.Code(err) == codes.NotFound {
+		// Jira Data Center has no /search/jql; use the offset-paginated search.
+		err = c.searchLegacy(ctx, send)
+	}
+
+	return err
+}
+
+// TransitionIssue moves an issue through the first transition whose name or
+// target status matches req.status, and returns the updated issue.
+func (j *Jira) TransitionIssue(ctx context.Context, req *pluginv1.TransitionIssueRequest) (*pluginv1.Issue, error) {
+	if req.GetKey() == "" || req.GetStatus() == "" {
+		return nil, status.Error(codes.InvalidArgument, "key and status are required")
+	}
+
+	c, err := j.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+
+	path := "/rest/api/2/issue/" + url.PathEscape(req.GetKey()) + "/transitions"
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(resp.Transitions))
+
+	for _, t := range resp.Transitions {
+		if !strings.EqualFold(t.Name, req.GetStatus()) && !strings.EqualFold(t.To.Name, req.GetStatus()) {
+			names = append(names, t.To.Name)
+
+			continue
+		}
+
+		body := map[string]any{"transition": map[string]string{"id": t.ID}}
+		if err := c.do(ctx, http.MethodPost, path, nil, body, nil); err != nil {
+			return nil, err
+		}
+
+		return c.getIssue(ctx, req.GetKey())
+	}
+
+	return nil, status.Errorf(codes.FailedPrecondition, "issue %s: %v to %q (available: %s)",
+		req.GetKey(), errNoTransitions, req.GetStatus(), strings.Join(names, ", "))
+}
+
+// AddComment adds a plain-text comment to an issue.
+func (j *Jira) AddComment(ctx context.Context, req *pluginv1.AddCommentRequest) (*pluginv1.Empty, error) {
+	if req.GetKey() == "" || req.GetBody() == "" {
+		return nil, status.Error(codes.InvalidArgument, "key and body are required")
+	}
+
+	c, err := j.newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/rest/api/2/issue/" + url.PathEscape(req.GetKey()) + "/comment"
+	if err := c.do(ctx, http.MethodPost, path, nil, map[string]string{"body": req.GetBody()}, nil); err != nil {
+		return nil, err
+	}
+
+	return &pluginv1.Empty{}, nil
+}
+
+// jiraIssue is the subset of the Jira REST v2 issue representation swm uses.
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+		Status      struct {
+			Name string `json:"name"`
+		} `json:"status"`
+		IssueType struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+		Assignee *struct {
+			DisplayName string `json:"displayName"`
+		} `json:"assignee"`
+	} `json:"fields"`
+}
+
+// client is a Jira REST API client for one resolved configuration.
+type client struct {
+	http    *http.Client
+	baseURL string
+	auth    string
+	jql     string
+}
+
+// newClient loads the plugin config from the host and resolves the API token.
+func (j *Jira) newClient(ctx context.Context) (*client, error) {
+	if j.hostClient == nil {
+		return nil, status.Error(codes.FailedPrecondition, "no host client: Jira config unavailable")
+	}
+
+	resp, err := j.hostClient.GetConfig(ctx, &pluginv1.GetConfigRequest{PluginName: "tracker-jira"})
+	if err != nil {
+		return nil, fmt.Errorf("getting tracker-jira config: %w", err)
+	}
+
+	var cfg jiraConfig
+	if len(resp.GetToml()) > 0 {
+		if err := toml.Unmarshal(resp.GetToml(), &cfg); err != nil {
+			return nil, fmt.Errorf("parsing tracker-jira config: %w", err)
+		}
+	}
+
+	if cfg.BaseURL == "" {
+		return nil, status.Error(codes.FailedPrecondition, "base_url is not set in the tracker-jira plugin config")
+	}
+
+	token, err := j.token(cfg.TokenPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Jira Cloud authenticates API tokens with basic auth against the account
+	// email; Data Center personal access tokens are bearer tokens.
+	auth := "Bearer " + token
+	if cfg.Email != "" {
+		auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(cfg.Email+":"+token))
+	}
+
+	jql := cfg.JQL
+	if jql == "" {
+		jql = defaultJQL
+	}
+
+	return &client{
+		http:    j.httpClient,
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		auth:    auth,
+		jql:     jql,
+	}, nil
+}
+
+// token reads the API token from tokenPath, falling back to $JIRA_API_TOKEN.
+func (j *Jira) token(tokenPath string) (string, error) {
+	if tokenPath == "" {
+		if token := strings.TrimSpace(j.getenv("JIRA_API_TOKEN")); token != "" {
+			return token, nil
+		}
+
+		return "", status.Error(codes.FailedPrecondition,
+			"no Jira token found: set token_path in the tracker-jira plugin config or export JIRA_API_TOKEN")
+	}
+
+	if rest, ok := strings.CutPrefix(tokenPath, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("resolving home directory: %w", err)
+		}
+
+		tokenPath = filepath.Join(home, rest)
+	}
+
+	raw, err := os.ReadFile(tokenPath) //nolint:gosec // G304: path comes from trusted plugin config
+	if err != nil {
+		return "", status.Errorf(codes.FailedPrecondition, "reading Jira token from %q: %v", tokenPath, err)
+	}
+
+	token := strings.TrimSpace(string(raw))
+	if token == "" {
+		return "", status.Errorf(codes.FailedPrecondition, "Jira token file %q is empty", tokenPath)
+	}
+
+	return token, nil
+}
+
+func (c *client) getIssue(ctx context.Context, key string) (*pluginv1.Issue, error) {
+	var issue jiraIssue
+
+	query := url.Values{"fields": {issueFields}}
+	if err := c.do(ctx, http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(key), query, nil, &issue); err != nil {
+		return nil, err
+	}
+
+	return c.toProto(&issue), nil
+}
+
+// searchJQL pages through /rest/api/2/search/jql (Jira Cloud).
+func (c *client) searchJQL(ctx context.Context, send func([]jiraIssue) error) error {
+	query := url.Values{
+		"jql":        {c.jql},
+		"fields":     {issueFields},
+		"maxResults": {strconv.Itoa(searchPageSize)},
+	}
+
+	for {
+		var page struct {
+			Issues        []jiraIssue `json:"issues"`
+			NextPageToken string      `json:"nextPageToken"`
+			IsLast        bool        `json:"isLast"`
+		}
+
+		if err := c.do(ctx, http.MethodGet, "/rest/api/2/search/jql", query, nil, &page); err != nil {
+			return err
+		}
+
+		if err := send(page.Issues); err != nil {
+			return err
+		}
+
+		if page.IsLast || page.NextPageToken == "" {
+			return nil
+		}
+
+		query.Set("nextPageToken", page.NextPageToken)
+	}
+}
+
+// searchLegacy pages through /rest/api/2/search (Jira Data Center).
+func (c *client) searchLegacy(ctx context.Context, send func([]jiraIssue) error) error {
+	query := url.Values{
+		"jql":        {c.jql},
+		"fields":     {issueFields},
+		"maxResults": {strconv.Itoa(searchPageSize)},
+	}
+
+	for startAt := 0; ; {
+		var page struct {
+			Issues []jiraIssue `json:"issues"`
+			Total  int         `json:"total"`
+		}
+
+		query.Set("startAt", strconv.Itoa(startAt))
+
+		if err := c.do(ctx, http.MethodGet, "/rest/api/2/search", query, nil, &page); err != nil {
+			return err
+		}
+
+		if err := send(page.Issues); err != nil {
+			return err
+		}
+
+		startAt += len(page.Issues)
+		if len(page.Issues) == 0 || startAt >= page.Total {
+			return nil
+		}
+	}
+}
+
+// do sends a request to the Jira REST API, encoding body as JSON when non-nil
+// and decoding the response into out when non-nil. Error responses are mapped
+// to gRPC status errors.
+func (c *client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request body: %w", err)
+		}
+
+		reqBody = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", c.auth)
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "jira: %s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // best-effort close of response body
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return responseError(resp, method, path)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("jira: decoding %s response: %w", path, err)
+	}
+
+	return nil
+}
+
+// responseError converts a Jira error response into a gRPC status error,
+// including Jira's own error messages when present.
+func responseError(resp *http.Response, method, path string) error {
+	var body struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+
+	_ = json.NewDecoder(resp.Body).Decode(&body) //nolint:errcheck // error bodies are best-effort
+
+	msgs := body.ErrorMessages
+	for field, msg := range body.Errors {
+		msgs = append(msgs, field+": "+msg)
+	}
+
+	msg := fmt.Sprintf("jira: %s %s: HTTP %d", method, path, resp.StatusCode)
+	if len(msgs) > 0 {
+		msg += ": " + strings.Join(msgs, "; ")
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadRequest:
+		return status.Error(codes.InvalidArgument, msg)
+	case http.StatusUnauthorized:
+		return status.Error(codes.Unauthenticated, msg)
+	case http.StatusForbidden:
+		return status.Error(codes.PermissionDenied, msg)
+	case http.StatusNotFound:
+		return status.Error(codes.NotFound, msg)
+	default:
+		return status.Error(codes.Unavailable, msg)
+	}
+}
+
+// toProto converts a Jira issue to a proto Issue, linking it to the web UI.
+func (c *client) toProto(issue *jiraIssue) *pluginv1.Issue {
+	out := &pluginv1.Issue{
+		Key:         issue.Key,
+		Title:       issue.Fields.Summary,
+		Description: issue.Fields.Description,
+		Status:      issue.Fields.Status.Name,
+		Url:         c.baseURL + "/browse/" + issue.Key,
+		Type:        issue.Fields.IssueType.Name,
+	}
+
+	if issue.Fields.Assignee != nil {
+		out.Assignee = issue.Fields.Assignee.DisplayName
+	}
+
+	return out
+}
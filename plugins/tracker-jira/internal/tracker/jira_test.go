@@ -0,0 +1,404 @@
+package tracker_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/plugins/tracker-jira/internal/tracker"
+)
+
+const (
+	testToken    = "jira_test_token" //nolint:gosec // G101: test placeholder, not a real credential
+	testEmail    = "me@example.com"
+	testIssueKey = "PROJ-123"
+)
+
+// fakeHostClient implements pluginv1.HostClient for tests.
+type fakeHostClient struct {
+	toml []byte
+}
+
+func (c *fakeHostClient) CallCapability(
+	_ context.Context,
+	_ *pluginv1.CallCapabilityRequest,
+	_ ...grpc.CallOption,
+) (*pluginv1.CallCapabilityResponse, error) {
+	panic("stub")
+}
+
+func (c *fakeHostClient) GetCodeRoot(
+	_ context.Context,
+	_ *pluginv1.Empty,
+	_ ...grpc.CallOption,
+) (*pluginv1.PathResponse, error) {
+	panic("stub")
+}
+
+func (c *fakeHostClient) GetConfig(
+	_ context.Context,
+	_ *pluginv1.GetConfigRequest,
+	_ ...grpc.CallOption,
+) (*pluginv1.Config, error) {
+	return &pluginv1.Config{Toml: c.toml}, nil
+}
+
+func (c *fakeHostClient) GetCurrentStory(
+	_ context.Context,
+	_ *pluginv1.Empty,
+	_ ...grpc.CallOption,
+) (*pluginv1.Story, error) {
+	panic("stub")
+}
+
+func (c *fakeHostClient) ListProjects(
+	_ context.Context,
+	_ *pluginv1.ListProjectsRequest,
+	_ ...grpc.CallOption,
+) (grpc.ServerStreamingClient[pluginv1.Project], error) {
+	panic("stub")
+}
+
+func (c *fakeHostClient) Log(
+	_ context.Context,
+	_ *pluginv1.LogRequest,
+	_ ...grpc.CallOption,
+) (*pluginv1.Empty, error) {
+	panic("stub")
+}
+
+// fakeListStream captures issues sent via ListMyIssues.
+type fakeListStream struct {
+	ctx    context.Context
+	issues []*pluginv1.Issue
+}
+
+func (s *fakeListStream) Context() context.Context { return s.ctx }
+func (s *fakeListStream) RecvMsg(any) error        { return nil }
+func (s *fakeListStream) Send(issue *pluginv1.Issue) error {
+	s.issues = append(s.issues, issue)
+
+	return nil
+}
+
+func (s *fakeListStream) SendHeader(metadata.MD) error { return nil }
+func (s *fakeListStream) SendMsg(any) error            { return nil }
+func (s *fakeListStream) SetHeader(metadata.MD) error  { return nil }
+func (s *fakeListStream) SetTrailer(metadata.MD)       {}
+
+// newJira starts an httptest stand-in for Jira serving mux and returns a
+// tracker configured against it with the given extra config lines.
+func newJira(t *testing.T, mux *http.ServeMux, extraConfig string) *tracker.Jira {
+	t.Helper()
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte(testToken+"\n"), 0o600))
+
+	hc := &fakeHostClient{toml: fmt.Appendf(nil, "base_url = %q\ntoken_path = %q\n%s", server.URL+"/", tokenPath, extraConfig)}
+
+	return tracker.New(hc, tracker.WithHTTPClient(server.Client()))
+}
+
+// issueJSON returns a minimal Jira REST v2 issue.
+func issueJSON(key, summary, statusName string) map[string]any {
+	return map[string]any{
+		"key": key,
+		"fields": map[string]any{
+			"summary":     summary,
+			"description": "description of " + summary,
+			"status":      map[string]any{"name": statusName},
+			"issuetype":   map[string]any{"name": "Bug"},
+			"assignee":    map[string]any{"displayName": "Me"},
+		},
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	//nolint:errcheck // test mock, response write failure is non-critical
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestJira_Info(t *testing.T) {
+	t.Parallel()
+
+	info, err := tracker.New(nil).Info(context.Background(), &pluginv1.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, "jira", info.GetPluginInfo().GetName())
+}
+
+func TestJira_GetIssue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		config   string
+		wantAuth string
+	}{
+		{name: "bearer token", wantAuth: "Bearer " + testToken},
+		{
+			name:     "cloud basic auth",
+			config:   fmt.Sprintf("email = %q", testEmail),
+			wantAuth: "Basic " + base64.StdEncoding.EncodeToString([]byte(testEmail+":"+testToken)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /rest/api/2/issue/PROJ-123", func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != tt.wantAuth {
+					w.WriteHeader(http.StatusUnauthorized)
+
+					return
+				}
+
+				writeJSON(w, issueJSON(testIssueKey, "Fix login", "To Do"))
+			})
+
+			j := newJira(t, mux, tt.config)
+
+			issue, err := j.GetIssue(context.Background(), &pluginv1.GetIssueRequest{Key: testIssueKey})
+			require.NoError(t, err)
+			require.Equal(t, testIssueKey, issue.GetKey())
+			require.Equal(t, "Fix login", issue.GetTitle())
+			require.Equal(t, "To Do", issue.GetStatus())
+			require.Equal(t, "Bug", issue.GetType())
+			require.Equal(t, "Me", issue.GetAssignee())
+			require.Regexp(t, `^http://127\.0\.0\.1:\d+/browse/PROJ-123$`, issue.GetUrl())
+		})
+	}
+}
+
+func TestJira_GetIssue_NotFound(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rest/api/2/issue/PROJ-404", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		writeJSON(w, map[string]any{"errorMessages": []string{"Issue does not exist"}})
+	})
+
+	j := newJira(t, mux, "")
+
+	_, err := j.GetIssue(context.Background(), &pluginv1.GetIssueRequest{Key: "PROJ-404"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.ErrorContains(t, err, "Issue does not exist")
+}
+
+func TestJira_GetIssue_MissingConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		toml    string
+		getenv  func(string) string
+		wantMsg string
+	}{
+		{name: "no base_url", toml: "", wantMsg: "base_url is not set"},
+		{
+			name:    "no token",
+			toml:    `base_url = "https://example.atlassian.net"`,
+			getenv:  func(string) string { return "" },
+			wantMsg: "no Jira token found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := []tracker.Option{}
+			if tt.getenv != nil {
+				opts = append(opts, tracker.WithGetenv(tt.getenv))
+			}
+
+			j := tracker.New(&fakeHostClient{toml: []byte(tt.toml)}, opts...)
+
+			_, err := j.GetIssue(context.Background(), &pluginv1.GetIssueRequest{Key: testIssueKey})
+			require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			require.ErrorContains(t, err, tt.wantMsg)
+		})
+	}
+}
+
+func TestJira_GetIssue_TokenFromEnv(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rest/api/2/issue/PROJ-123", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer env-token", r.Header.Get("Authorization"))
+		writeJSON(w, issueJSON(testIssueKey, "Fix login", "To Do"))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	getenv := func(key string) string {
+		if key == "JIRA_API_TOKEN" {
+			return "env-token"
+		}
+
+		return ""
+	}
+
+	j := tracker.New(&fakeHostClient{toml: fmt.Appendf(nil, "base_url = %q", server.URL)},
+		tracker.WithHTTPClient(server.Client()), tracker.WithGetenv(getenv))
+
+	_, err := j.GetIssue(context.Background(), &pluginv1.GetIssueRequest{Key: testIssueKey})
+	require.NoError(t, err)
+}
+
+func TestJira_ListMyIssues_Paginates(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rest/api/2/search/jql", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "assignee = currentUser() AND statusCategory != Done ORDER BY updated DESC",
+			r.URL.Query().Get("jql"))
+
+		if r.URL.Query().Get("nextPageToken") == "" {
+			writeJSON(w, map[string]any{
+				"issues":        []any{issueJSON("PROJ-1", "One", "To Do")},
+				"nextPageToken": "page-2",
+			})
+
+			return
+		}
+
+		writeJSON(w, map[string]any{
+			"issues": []any{issueJSON("PROJ-2", "Two", "In Progress")},
+			"isLast": true,
+		})
+	})
+
+	j := newJira(t, mux, "")
+
+	stream := &fakeListStream{ctx: context.Background()}
+	require.NoError(t, j.ListMyIssues(&pluginv1.ListMyIssuesRequest{}, stream))
+	require.Len(t, stream.issues, 2)
+	require.Equal(t, "PROJ-1", stream.issues[0].GetKey())
+	require.Equal(t, "PROJ-2", stream.issues[1].GetKey())
+}
+
+func TestJira_ListMyIssues_LegacySearchFallback(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "project = OPS", r.URL.Query().Get("jql"))
+
+		if r.URL.Query().Get("startAt") == "0" {
+			writeJSON(w, map[string]any{"issues": []any{issueJSON("OPS-1", "One", "To Do")}, "total": 2})
+
+			return
+		}
+
+		writeJSON(w, map[string]any{"issues": []any{issueJSON("OPS-2", "Two", "To Do")}, "total": 2})
+	})
+
+	j := newJira(t, mux, `jql = "project = OPS"`)
+
+	stream := &fakeListStream{ctx: context.Background()}
+	require.NoError(t, j.ListMyIssues(&pluginv1.ListMyIssuesRequest{}, stream))
+	require.Len(t, stream.issues, 2)
+	require.Equal(t, "OPS-2", stream.issues[1].GetKey())
+}
+
+func TestJira_TransitionIssue(t *testing.T) {
+	t.Parallel()
+
+	var transitioned string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rest/api/2/issue/PROJ-123/transitions", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"transitions": []any{
+			map[string]any{"id": "11", "name": "Start", "to": map[string]any{"name": "In Progress"}},
+			map[string]any{"id": "21", "name": "Finish", "to": map[string]any{"name": "Done"}},
+		}})
+	})
+	mux.HandleFunc("POST /rest/api/2/issue/PROJ-123/transitions", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Transition struct {
+				ID string `json:"id"`
+			} `json:"transition"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		transitioned = body.Transition.ID
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /rest/api/2/issue/PROJ-123", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, issueJSON(testIssueKey, "Fix login", "In Progress"))
+	})
+
+	j := newJira(t, mux, "")
+
+	issue, err := j.TransitionIssue(context.Background(),
+		&pluginv1.TransitionIssueRequest{Key: testIssueKey, Status: "in progress"})
+	require.NoError(t, err)
+	require.Equal(t, "11", transitioned)
+	require.Equal(t, "In Progress", issue.GetStatus())
+
+	_, err = j.TransitionIssue(context.Background(),
+		&pluginv1.TransitionIssueRequest{Key: testIssueKey, Status: "Blocked"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.ErrorContains(t, err, "available: In Progress, Done")
+}
+
+func TestJira_AddComment(t *testing.T) {
+	t.Parallel()
+
+	var comment string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /rest/api/2/issue/PROJ-123/comment", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Body string `json:"body"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		comment = body.Body
+
+		w.WriteHeader(http.StatusCreated)
+		writeJSON(w, map[string]any{"id": "1"})
+	})
+
+	j := newJira(t, mux, "")
+
+	_, err := j.AddComment(context.Background(), &pluginv1.AddCommentRequest{Key: testIssueKey, Body: "Started work"})
+	require.NoError(t, err)
+	require.Equal(t, "Started work", comment)
+
+	_, err = j.AddComment(context.Background(), &pluginv1.AddCommentRequest{Key: testIssueKey})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
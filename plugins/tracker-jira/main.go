@@ -0,0 +1,39 @@
+// swm-plugin-tracker-jira is the Jira issue tracker plugin for swm.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+	sdktracker "github.com/kalbasit/swm/sdk/go/tracker"
+
+	"github.com/kalbasit/swm/plugins/tracker-jira/internal/tracker"
+)
+
+func main() {
+	var hostClient pluginv1.HostClient
+
+	if sock := os.Getenv("SWM_HOST_SOCKET"); sock != "" {
+		conn, err := grpc.NewClient(
+			sock,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "swm-plugin-tracker-jira: connecting to host socket: %v\n", err)
+			os.Exit(1)
+		}
+
+		defer conn.Close() //nolint:errcheck // best-effort close on exit
+
+		hostClient = pluginv1.NewHostClient(conn)
+	}
+
+	if err := sdktracker.Serve(tracker.New(hostClient)); err != nil {
+		fmt.Fprintf(os.Stderr, "swm-plugin-tracker-jira: serve: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,14 @@
+// Package bundle lets swm run the git VCS plugin in its own process when
+// swm-plugin-vcs-git is not installed.
+package bundle
+
+import (
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/plugins/vcs-git/internal/vcs"
+)
+
+// New returns the plugin as its binary serves it.
+func New() (pluginv1.VCSServer, error) {
+	return vcs.New()
+}
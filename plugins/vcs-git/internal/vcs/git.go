@@ -17,6 +17,9 @@ import (
 	"google.golang.org/grpc/status"
 
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/sdk/go/command"
+	"github.com/kalbasit/swm/sdk/go/trace"
 )
 
 // buildVersion is set via -ldflags at build time.
@@ -48,7 +51,10 @@ func (g *Git) Clone(req *pluginv1.CloneRequest, stream pluginv1.VCS_CloneServer)
 	}
 
 	//nolint:gosec // gitBin from exec.LookPath; args are controlled
-	cmd := exec.CommandContext(stream.Context(), g.gitBin, "clone", "--progress", req.GetUrl(), req.GetDestinationPath())
+	_, span := trace.Start(stream.Context(), "git clone", "url", req.GetUrl())
+	defer span.End()
+
+	cmd := command.New(stream.Context(), g.gitBin, "clone", "--progress", req.GetUrl(), req.GetDestinationPath())
 
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
@@ -92,6 +98,10 @@ func (g *Git) Clone(req *pluginv1.CloneRequest, stream pluginv1.VCS_CloneServer)
 	}
 
 	if err := cmd.Wait(); err != nil {
+		if ctxErr := stream.Context().Err(); ctxErr != nil {
+			return status.FromContextError(ctxErr).Err()
+		}
+
 		return status.Errorf(codes.Internal, "git clone %s: %s", req.GetUrl(), stderrBuf.String())
 	}
 
@@ -128,6 +138,14 @@ func (g *Git) CreateWorktree(ctx context.Context, req *pluginv1.CreateWorktreeRe
 		return nil, status.Errorf(codes.Internal, "creating worktree parent: %v", err)
 	}
 
+	// Fetch the requested ref into the branch, creating or fast-forwarding it.
+	if ref := req.GetFetchRef(); ref != "" {
+		refspec := ref + ":refs/heads/" + req.GetBranchName()
+		if _, err := g.run(ctx, "-C", req.GetRepoPath(), "fetch", "origin", refspec); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check if branch exists.
 	_, branchErr := g.run(ctx, "-C", req.GetRepoPath(), "rev-parse", "--verify", req.GetBranchName())
 
@@ -159,6 +177,47 @@ func (g *Git) DetectProjectAtPath(
 	return parseURL(originURL)
 }
 
+// GetUserIdentity returns git's user.name and user.email, read from the
+// repository at repo_path when set and from the global configuration
+// otherwise. Unset values are returned empty.
+func (g *Git) GetUserIdentity(ctx context.Context, req *pluginv1.UserIdentityRequest) (*pluginv1.UserIdentity, error) {
+	var args []string
+	if p := req.GetRepoPath(); p != "" {
+		args = append(args, "-C", p)
+	}
+
+	name, err := g.run(ctx, append(args, "config", "--default", "", "--get", "user.name")...)
+	if err != nil {
+		return nil, err
+	}
+
+	email, err := g.run(ctx, append(args, "config", "--default", "", "--get", "user.email")...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pluginv1.UserIdentity{Name: name, Email: email}, nil
+}
+
+// GetWorktreeStatus reports the checked-out branch of a worktree, whether it
+// has uncommitted or untracked changes, and how far it is ahead of and behind
+// its upstream.
+func (g *Git) GetWorktreeStatus(
+	ctx context.Context,
+	req *pluginv1.WorktreeStatusRequest,
+) (*pluginv1.WorktreeStatus, error) {
+	if _, err := os.Stat(req.GetWorktreePath()); err != nil {
+		return nil, status.Errorf(codes.NotFound, "worktree not found at %s", req.GetWorktreePath())
+	}
+
+	out, err := g.run(ctx, "-C", req.GetWorktreePath(), "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseStatus(out), nil
+}
+
 // Info returns metadata about this VCS plugin.
 func (g *Git) Info(_ context.Context, _ *pluginv1.Empty) (*pluginv1.VCSInfo, error) {
 	return &pluginv1.VCSInfo{
@@ -167,6 +226,7 @@ func (g *Git) Info(_ context.Context, _ *pluginv1.Empty) (*pluginv1.VCSInfo, err
 			Version: buildVersion,
 		},
 		ProjectMarkers: []string{".git"},
+		UrlSchemes:     []string{"https", "http", "ssh", "git", "file"},
 	}, nil
 }
 
@@ -211,6 +271,22 @@ func (g *Git) RemoveWorktree(ctx context.Context, req *pluginv1.RemoveWorktreeRe
 	return &pluginv1.Empty{}, nil
 }
 
+// ValidateBranchName checks the name with git check-ref-format --branch.
+// Names git would expand (e.g. "@{-1}") are rejected as well.
+func (g *Git) ValidateBranchName(
+	ctx context.Context,
+	req *pluginv1.ValidateBranchNameRequest,
+) (*pluginv1.Empty, error) {
+	name := req.GetBranchName()
+
+	out, err := g.run(ctx, "check-ref-format", "--branch", name)
+	if err != nil || out != name {
+		return nil, status.Errorf(codes.InvalidArgument, "%q is not a valid branch name", name)
+	}
+
+	return &pluginv1.Empty{}, nil
+}
+
 // mainRepoPath resolves the main repository root from any path within a worktree.
 func (g *Git) mainRepoPath(ctx context.Context, worktreePath string) (string, error) {
 	gitCommonDir, err := g.run(ctx, "-C", worktreePath, "rev-parse", "--git-common-dir")
@@ -226,19 +302,51 @@ func (g *Git) mainRepoPath(ctx context.Context, worktreePath string) (string, er
 }
 
 func (g *Git) run(ctx context.Context, args ...string) (string, error) {
+	ctx, span := trace.Start(ctx, "git "+args[0], "args", strings.Join(args, " "))
+	defer span.End()
+
 	var stderr bytes.Buffer
 
-	cmd := exec.CommandContext(ctx, g.gitBin, args...) //nolint:gosec // gitBin is from exec.LookPath, args are controlled
+	cmd := command.New(ctx, g.gitBin, args...)
 	cmd.Stderr = &stderr
 
 	out, err := cmd.Output()
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", status.FromContextError(ctx.Err()).Err()
+		}
+
 		return "", status.Errorf(codes.Internal, "git %s: %s", strings.Join(args, " "), stderr.String())
 	}
 
 	return strings.TrimSpace(string(out)), nil
 }
 
+// parseStatus parses the output of git status --porcelain=v2 --branch.
+func parseStatus(out string) *pluginv1.WorktreeStatus {
+	st := &pluginv1.WorktreeStatus{}
+
+	for line := range strings.SplitSeq(out, "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "# branch.head "):
+			if head := strings.TrimPrefix(line, "# branch.head "); head != "(detached)" {
+				st.Branch = head
+			}
+		case strings.HasPrefix(line, "# branch.ab "):
+			var ahead, behind int32
+			if _, err := fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &ahead, &behind); err == nil {
+				st.Ahead, st.Behind = ahead, behind
+			}
+		case strings.HasPrefix(line, "#"):
+		default:
+			st.Dirty = true
+		}
+	}
+
+	return st
+}
+
 func parseURL(raw string) (*pluginv1.ProjectID, error) {
 	// SSH format: git@github.com:owner/repo.git
 	if m := sshURLRe.FindStringSubmatch(raw); m != nil {
@@ -2,12 +2,15 @@ package vcs_test
 
 import (
 	"context"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
 
@@ -198,6 +201,46 @@ func TestCreateAndRemoveWorktree(t *testing.T) {
 	require.NoDirExists(t, worktreeDir)
 }
 
+func TestCreateWorktree_FetchRef(t *testing.T) {
+	t.Parallel()
+
+	// The upstream has a pull request head that is not on any branch.
+	upstream := initRepo(t)
+	for _, c := range [][]string{
+		{gitBin, "-C", upstream, "commit", "--allow-empty", "-m", "pr head"},
+		{gitBin, "-C", upstream, "update-ref", "refs/pull/7/head", "HEAD"},
+		{gitBin, "-C", upstream, "reset", "--hard", "HEAD~1"},
+	} {
+		out, err := exec.Command(c[0], c[1:]...).CombinedOutput() //nolint:gosec // trusted test commands
+		require.NoError(t, err, "cmd %v: %s", c, out)
+	}
+
+	want, err := exec.Command(gitBin, "-C", upstream, "rev-parse", "refs/pull/7/head").Output()
+	require.NoError(t, err)
+
+	canonical := filepath.Join(t.TempDir(), "canonical")
+	out, err := exec.Command(gitBin, "clone", upstream, canonical).CombinedOutput()
+	require.NoError(t, err, "clone: %s", out)
+
+	worktreeDir := filepath.Join(t.TempDir(), "stories", "swm-pr-7", "github.com", "kalbasit", "swm")
+
+	_, err = newGit(t).CreateWorktree(context.Background(), &pluginv1.CreateWorktreeRequest{
+		RepoPath:     canonical,
+		WorktreePath: worktreeDir,
+		BranchName:   "fix-thing",
+		FetchRef:     "refs/pull/7/head",
+	})
+	require.NoError(t, err)
+
+	got, err := exec.Command(gitBin, "-C", worktreeDir, "rev-parse", "HEAD").Output() //nolint:gosec // trusted test command
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got))
+
+	branch, err := exec.Command(gitBin, "-C", worktreeDir, "branch", "--show-current").Output() //nolint:gosec // trusted test command
+	require.NoError(t, err)
+	require.Equal(t, "fix-thing\n", string(branch))
+}
+
 func TestDetectProjectAtPath(t *testing.T) {
 	t.Parallel()
 
@@ -222,6 +265,50 @@ func TestDetectProjectAtPath_NotGitRepo(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestGetWorktreeStatus_Clean(t *testing.T) {
+	t.Parallel()
+
+	canonical := initRepo(t)
+	worktreeDir := filepath.Join(t.TempDir(), "feat-x")
+
+	g := newGit(t)
+
+	_, err := g.CreateWorktree(context.Background(), &pluginv1.CreateWorktreeRequest{
+		RepoPath:     canonical,
+		WorktreePath: worktreeDir,
+		BranchName:   "feat/feat-x",
+	})
+	require.NoError(t, err)
+
+	st, err := g.GetWorktreeStatus(context.Background(), &pluginv1.WorktreeStatusRequest{WorktreePath: worktreeDir})
+	require.NoError(t, err)
+	require.Equal(t, "feat/feat-x", st.GetBranch())
+	require.False(t, st.GetDirty())
+}
+
+func TestGetWorktreeStatus_Dirty(t *testing.T) {
+	t.Parallel()
+
+	dir := initRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("x"), 0o600))
+
+	g := newGit(t)
+
+	st, err := g.GetWorktreeStatus(context.Background(), &pluginv1.WorktreeStatusRequest{WorktreePath: dir})
+	require.NoError(t, err)
+	require.True(t, st.GetDirty())
+}
+
+func TestGetWorktreeStatus_NotFound(t *testing.T) {
+	t.Parallel()
+
+	g := newGit(t)
+	_, err := g.GetWorktreeStatus(context.Background(), &pluginv1.WorktreeStatusRequest{
+		WorktreePath: filepath.Join(t.TempDir(), "missing"),
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
 func TestInfo(t *testing.T) {
 	t.Parallel()
 
@@ -230,6 +317,7 @@ func TestInfo(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "git", info.GetPluginInfo().GetName())
 	require.Contains(t, info.GetProjectMarkers(), ".git")
+	require.Contains(t, info.GetUrlSchemes(), "ssh")
 }
 
 func TestListBranches(t *testing.T) {
@@ -240,3 +328,44 @@ func TestListBranches(t *testing.T) {
 	g := newGit(t)
 	require.NotNil(t, g)
 }
+
+func TestGetUserIdentity(t *testing.T) {
+	t.Parallel()
+
+	dir := initRepo(t)
+	g := newGit(t)
+
+	id, err := g.GetUserIdentity(context.Background(), &pluginv1.UserIdentityRequest{RepoPath: dir})
+	require.NoError(t, err)
+	require.Equal(t, "Test", id.GetName())
+	require.Equal(t, "test@test.com", id.GetEmail())
+}
+
+func TestValidateBranchName(t *testing.T) {
+	t.Parallel()
+
+	g := newGit(t)
+
+	for _, name := range []string{"feat/x", "users/jdoe/PROJ-1-fix"} {
+		_, err := g.ValidateBranchName(context.Background(), &pluginv1.ValidateBranchNameRequest{BranchName: name})
+		require.NoError(t, err, name)
+	}
+
+	for _, name := range []string{"", "bad..name", "-x", "feat/x.lock", "with space", "@{-1}"} {
+		_, err := g.ValidateBranchName(context.Background(), &pluginv1.ValidateBranchNameRequest{BranchName: name})
+		require.Equal(t, codes.InvalidArgument, status.Code(err), name)
+	}
+}
+
+func TestCancelledCall(t *testing.T) {
+	t.Parallel()
+
+	dir := initRepo(t)
+	g := newGit(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := g.GetUserIdentity(ctx, &pluginv1.UserIdentityRequest{RepoPath: dir})
+	require.Equal(t, codes.Canceled, status.Code(err))
+}
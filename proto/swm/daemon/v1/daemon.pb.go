@@ -0,0 +1,592 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: swm/daemon/v1/daemon.proto
+
+package daemonv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// StatusRequest asks the daemon to describe itself.
+type StatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusRequest) Reset() {
+	*x = StatusRequest{}
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusRequest) ProtoMessage() {}
+
+func (x *StatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
+func (*StatusRequest) Descriptor() ([]byte, []int) {
+	return file_swm_daemon_v1_daemon_proto_rawDescGZIP(), []int{0}
+}
+
+// Plugin describes a plugin process the daemon keeps running.
+type Plugin struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// capability is the capability the plugin serves, e.g. "vcs".
+	Capability string `protobuf:"bytes,1,opt,name=capability,proto3" json:"capability,omitempty"`
+	// name and version are what the plugin reports in Info().
+	Name          string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version       string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Plugin) Reset() {
+	*x = Plugin{}
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Plugin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Plugin) ProtoMessage() {}
+
+func (x *Plugin) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Plugin.ProtoReflect.Descriptor instead.
+func (*Plugin) Descriptor() ([]byte, []int) {
+	return file_swm_daemon_v1_daemon_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Plugin) GetCapability() string {
+	if x != nil {
+		return x.Capability
+	}
+	return ""
+}
+
+func (x *Plugin) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Plugin) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+// StatusResponse describes a running daemon.
+type StatusResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Pid   int64                  `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	// version is the swm version of the daemon. The CLI only forwards to a
+	// daemon of its own version.
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// config_path is the config file the daemon loaded and reloads on change.
+	// The CLI only forwards to a daemon using its own config file.
+	ConfigPath string `protobuf:"bytes,3,opt,name=config_path,json=configPath,proto3" json:"config_path,omitempty"`
+	// host_socket is the dial address of the daemon's Host service.
+	HostSocket   string                 `protobuf:"bytes,4,opt,name=host_socket,json=hostSocket,proto3" json:"host_socket,omitempty"`
+	StartedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	LastActiveAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_active_at,json=lastActiveAt,proto3" json:"last_active_at,omitempty"`
+	// idle_timeout is zero when idle shutdown is disabled.
+	IdleTimeout   *durationpb.Duration `protobuf:"bytes,7,opt,name=idle_timeout,json=idleTimeout,proto3" json:"idle_timeout,omitempty"`
+	Plugins       []*Plugin            `protobuf:"bytes,8,rep,name=plugins,proto3" json:"plugins,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponse) ProtoMessage() {}
+
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
+	return file_swm_daemon_v1_daemon_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StatusResponse) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *StatusResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *StatusResponse) GetConfigPath() string {
+	if x != nil {
+		return x.ConfigPath
+	}
+	return ""
+}
+
+func (x *StatusResponse) GetHostSocket() string {
+	if x != nil {
+		return x.HostSocket
+	}
+	return ""
+}
+
+func (x *StatusResponse) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *StatusResponse) GetLastActiveAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastActiveAt
+	}
+	return nil
+}
+
+func (x *StatusResponse) GetIdleTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.IdleTimeout
+	}
+	return nil
+}
+
+func (x *StatusResponse) GetPlugins() []*Plugin {
+	if x != nil {
+		return x.Plugins
+	}
+	return nil
+}
+
+// AcquireRequest asks the daemon to launch a plugin, if it is not running yet.
+type AcquireRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key selects the plugin as "<capability>:<name>", e.g. "vcs:git" or
+	// "forge:github".
+	Key           string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcquireRequest) Reset() {
+	*x = AcquireRequest{}
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcquireRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcquireRequest) ProtoMessage() {}
+
+func (x *AcquireRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcquireRequest.ProtoReflect.Descriptor instead.
+func (*AcquireRequest) Descriptor() ([]byte, []int) {
+	return file_swm_daemon_v1_daemon_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AcquireRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+// AcquireResponse is returned once the plugin is running.
+type AcquireResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcquireResponse) Reset() {
+	*x = AcquireResponse{}
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcquireResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcquireResponse) ProtoMessage() {}
+
+func (x *AcquireResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcquireResponse.ProtoReflect.Descriptor instead.
+func (*AcquireResponse) Descriptor() ([]byte, []int) {
+	return file_swm_daemon_v1_daemon_proto_rawDescGZIP(), []int{4}
+}
+
+// IssueCallerTokenRequest asks the daemon's Host service for a caller token
+// for a plugin the CLI launches itself, which calls that Host service.
+type IssueCallerTokenRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// caller names the plugin as "<capability>-<name>", e.g. "session-tmux".
+	// The daemon refuses the capabilities it launches the plugins of.
+	Caller        string `protobuf:"bytes,1,opt,name=caller,proto3" json:"caller,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueCallerTokenRequest) Reset() {
+	*x = IssueCallerTokenRequest{}
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueCallerTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueCallerTokenRequest) ProtoMessage() {}
+
+func (x *IssueCallerTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueCallerTokenRequest.ProtoReflect.Descriptor instead.
+func (*IssueCallerTokenRequest) Descriptor() ([]byte, []int) {
+	return file_swm_daemon_v1_daemon_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *IssueCallerTokenRequest) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+// IssueCallerTokenResponse carries the token the plugin presents in its
+// Host.CallCapability calls.
+type IssueCallerTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueCallerTokenResponse) Reset() {
+	*x = IssueCallerTokenResponse{}
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueCallerTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueCallerTokenResponse) ProtoMessage() {}
+
+func (x *IssueCallerTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueCallerTokenResponse.ProtoReflect.Descriptor instead.
+func (*IssueCallerTokenResponse) Descriptor() ([]byte, []int) {
+	return file_swm_daemon_v1_daemon_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *IssueCallerTokenResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// StopRequest asks the daemon to shut down.
+type StopRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopRequest) Reset() {
+	*x = StopRequest{}
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopRequest) ProtoMessage() {}
+
+func (x *StopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopRequest.ProtoReflect.Descriptor instead.
+func (*StopRequest) Descriptor() ([]byte, []int) {
+	return file_swm_daemon_v1_daemon_proto_rawDescGZIP(), []int{7}
+}
+
+// StopResponse is returned before the daemon shuts down.
+type StopResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopResponse) Reset() {
+	*x = StopResponse{}
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopResponse) ProtoMessage() {}
+
+func (x *StopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_daemon_v1_daemon_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopResponse.ProtoReflect.Descriptor instead.
+func (*StopResponse) Descriptor() ([]byte, []int) {
+	return file_swm_daemon_v1_daemon_proto_rawDescGZIP(), []int{8}
+}
+
+var File_swm_daemon_v1_daemon_proto protoreflect.FileDescriptor
+
+const file_swm_daemon_v1_daemon_proto_rawDesc = "" +
+	"\n" +
+	"\x1aswm/daemon/v1/daemon.proto\x12\rswm.daemon.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x0f\n" +
+	"\rStatusRequest\"V\n" +
+	"\x06Plugin\x12\x1e\n" +
+	"\n" +
+	"capability\x18\x01 \x01(\tR\n" +
+	"capability\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\"\xea\x02\n" +
+	"\x0eStatusResponse\x12\x10\n" +
+	"\x03pid\x18\x01 \x01(\x03R\x03pid\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x1f\n" +
+	"\vconfig_path\x18\x03 \x01(\tR\n" +
+	"configPath\x12\x1f\n" +
+	"\vhost_socket\x18\x04 \x01(\tR\n" +
+	"hostSocket\x129\n" +
+	"\n" +
+	"started_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12@\n" +
+	"\x0elast_active_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\flastActiveAt\x12<\n" +
+	"\fidle_timeout\x18\a \x01(\v2\x19.google.protobuf.DurationR\vidleTimeout\x12/\n" +
+	"\aplugins\x18\b \x03(\v2\x15.swm.daemon.v1.PluginR\aplugins\"\"\n" +
+	"\x0eAcquireRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"\x11\n" +
+	"\x0fAcquireResponse\"1\n" +
+	"\x17IssueCallerTokenRequest\x12\x16\n" +
+	"\x06caller\x18\x01 \x01(\tR\x06caller\"0\n" +
+	"\x18IssueCallerTokenResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"\r\n" +
+	"\vStopRequest\"\x0e\n" +
+	"\fStopResponse2\xbf\x02\n" +
+	"\x06Daemon\x12E\n" +
+	"\x06Status\x12\x1c.swm.daemon.v1.StatusRequest\x1a\x1d.swm.daemon.v1.StatusResponse\x12H\n" +
+	"\aAcquire\x12\x1d.swm.daemon.v1.AcquireRequest\x1a\x1e.swm.daemon.v1.AcquireResponse\x12c\n" +
+	"\x10IssueCallerToken\x12&.swm.daemon.v1.IssueCallerTokenRequest\x1a'.swm.daemon.v1.IssueCallerTokenResponse\x12?\n" +
+	"\x04Stop\x12\x1a.swm.daemon.v1.StopRequest\x1a\x1b.swm.daemon.v1.StopResponseB6Z4github.com/kalbasit/swm/proto/swm/daemon/v1;daemonv1b\x06proto3"
+
+var (
+	file_swm_daemon_v1_daemon_proto_rawDescOnce sync.Once
+	file_swm_daemon_v1_daemon_proto_rawDescData []byte
+)
+
+func file_swm_daemon_v1_daemon_proto_rawDescGZIP() []byte {
+	file_swm_daemon_v1_daemon_proto_rawDescOnce.Do(func() {
+		file_swm_daemon_v1_daemon_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_swm_daemon_v1_daemon_proto_rawDesc), len(file_swm_daemon_v1_daemon_proto_rawDesc)))
+	})
+	return file_swm_daemon_v1_daemon_proto_rawDescData
+}
+
+var file_swm_daemon_v1_daemon_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_swm_daemon_v1_daemon_proto_goTypes = []any{
+	(*StatusRequest)(nil),            // 0: swm.daemon.v1.StatusRequest
+	(*Plugin)(nil),                   // 1: swm.daemon.v1.Plugin
+	(*StatusResponse)(nil),           // 2: swm.daemon.v1.StatusResponse
+	(*AcquireRequest)(nil),           // 3: swm.daemon.v1.AcquireRequest
+	(*AcquireResponse)(nil),          // 4: swm.daemon.v1.AcquireResponse
+	(*IssueCallerTokenRequest)(nil),  // 5: swm.daemon.v1.IssueCallerTokenRequest
+	(*IssueCallerTokenResponse)(nil), // 6: swm.daemon.v1.IssueCallerTokenResponse
+	(*StopRequest)(nil),              // 7: swm.daemon.v1.StopRequest
+	(*StopResponse)(nil),             // 8: swm.daemon.v1.StopResponse
+	(*timestamppb.Timestamp)(nil),    // 9: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),      // 10: google.protobuf.Duration
+}
+var file_swm_daemon_v1_daemon_proto_depIdxs = []int32{
+	9,  // 0: swm.daemon.v1.StatusResponse.started_at:type_name -> google.protobuf.Timestamp
+	9,  // 1: swm.daemon.v1.StatusResponse.last_active_at:type_name -> google.protobuf.Timestamp
+	10, // 2: swm.daemon.v1.StatusResponse.idle_timeout:type_name -> google.protobuf.Duration
+	1,  // 3: swm.daemon.v1.StatusResponse.plugins:type_name -> swm.daemon.v1.Plugin
+	0,  // 4: swm.daemon.v1.Daemon.Status:input_type -> swm.daemon.v1.StatusRequest
+	3,  // 5: swm.daemon.v1.Daemon.Acquire:input_type -> swm.daemon.v1.AcquireRequest
+	5,  // 6: swm.daemon.v1.Daemon.IssueCallerToken:input_type -> swm.daemon.v1.IssueCallerTokenRequest
+	7,  // 7: swm.daemon.v1.Daemon.Stop:input_type -> swm.daemon.v1.StopRequest
+	2,  // 8: swm.daemon.v1.Daemon.Status:output_type -> swm.daemon.v1.StatusResponse
+	4,  // 9: swm.daemon.v1.Daemon.Acquire:output_type -> swm.daemon.v1.AcquireResponse
+	6,  // 10: swm.daemon.v1.Daemon.IssueCallerToken:output_type -> swm.daemon.v1.IssueCallerTokenResponse
+	8,  // 11: swm.daemon.v1.Daemon.Stop:output_type -> swm.daemon.v1.StopResponse
+	8,  // [8:12] is the sub-list for method output_type
+	4,  // [4:8] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_swm_daemon_v1_daemon_proto_init() }
+func file_swm_daemon_v1_daemon_proto_init() {
+	if File_swm_daemon_v1_daemon_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_swm_daemon_v1_daemon_proto_rawDesc), len(file_swm_daemon_v1_daemon_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_swm_daemon_v1_daemon_proto_goTypes,
+		DependencyIndexes: file_swm_daemon_v1_daemon_proto_depIdxs,
+		MessageInfos:      file_swm_daemon_v1_daemon_proto_msgTypes,
+	}.Build()
+	File_swm_daemon_v1_daemon_proto = out.File
+	file_swm_daemon_v1_daemon_proto_goTypes = nil
+	file_swm_daemon_v1_daemon_proto_depIdxs = nil
+}
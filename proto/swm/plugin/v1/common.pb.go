@@ -32,6 +32,7 @@ const (
 	CapabilityType_CAPABILITY_TYPE_FORGE       CapabilityType = 3
 	CapabilityType_CAPABILITY_TYPE_PICKER      CapabilityType = 4
 	CapabilityType_CAPABILITY_TYPE_HOOK        CapabilityType = 5
+	CapabilityType_CAPABILITY_TYPE_TRACKER     CapabilityType = 6
 )
 
 // Enum value maps for CapabilityType.
@@ -43,6 +44,7 @@ var (
 		3: "CAPABILITY_TYPE_FORGE",
 		4: "CAPABILITY_TYPE_PICKER",
 		5: "CAPABILITY_TYPE_HOOK",
+		6: "CAPABILITY_TYPE_TRACKER",
 	}
 	CapabilityType_value = map[string]int32{
 		"CAPABILITY_TYPE_UNSPECIFIED": 0,
@@ -51,6 +53,7 @@ var (
 		"CAPABILITY_TYPE_FORGE":       3,
 		"CAPABILITY_TYPE_PICKER":      4,
 		"CAPABILITY_TYPE_HOOK":        5,
+		"CAPABILITY_TYPE_TRACKER":     6,
 	}
 )
 
@@ -362,14 +365,21 @@ func (x *CapabilityDep) GetMinVersion() string {
 
 // PluginInfo is returned by every plugin's Info RPC and describes itself.
 type PluginInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	Provides      []*Capability          `protobuf:"bytes,3,rep,name=provides,proto3" json:"provides,omitempty"`
-	Requires      []*CapabilityDep       `protobuf:"bytes,4,rep,name=requires,proto3" json:"requires,omitempty"`
-	Optional      []string               `protobuf:"bytes,5,rep,name=optional,proto3" json:"optional,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Name     string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version  string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Provides []*Capability          `protobuf:"bytes,3,rep,name=provides,proto3" json:"provides,omitempty"`
+	Requires []*CapabilityDep       `protobuf:"bytes,4,rep,name=requires,proto3" json:"requires,omitempty"`
+	Optional []string               `protobuf:"bytes,5,rep,name=optional,proto3" json:"optional,omitempty"`
+	// features names the optional RPCs and behaviours the plugin implements
+	// beyond its capability's baseline, so the host can degrade gracefully when
+	// a plugin built against an older SDK lacks a newer RPC.
+	Features []string `protobuf:"bytes,6,rep,name=features,proto3" json:"features,omitempty"`
+	// min_host_version is the oldest swm host version (semver) the plugin
+	// works with. Empty means any.
+	MinHostVersion string `protobuf:"bytes,7,opt,name=min_host_version,json=minHostVersion,proto3" json:"min_host_version,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *PluginInfo) Reset() {
@@ -437,6 +447,20 @@ func (x *PluginInfo) GetOptional() []string {
 	return nil
 }
 
+func (x *PluginInfo) GetFeatures() []string {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+func (x *PluginInfo) GetMinHostVersion() string {
+	if x != nil {
+		return x.MinHostVersion
+	}
+	return ""
+}
+
 // Project records a source repository attached to a story.
 type Project struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -612,14 +636,16 @@ const file_swm_plugin_v1_common_proto_rawDesc = "" +
 	"capability\x18\x01 \x01(\x0e2\x1d.swm.plugin.v1.CapabilityTypeR\n" +
 	"capability\x12\x1f\n" +
 	"\vmin_version\x18\x02 \x01(\tR\n" +
-	"minVersion\"\xc7\x01\n" +
+	"minVersion\"\x8d\x02\n" +
 	"\n" +
 	"PluginInfo\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
 	"\aversion\x18\x02 \x01(\tR\aversion\x125\n" +
 	"\bprovides\x18\x03 \x03(\v2\x19.swm.plugin.v1.CapabilityR\bprovides\x128\n" +
 	"\brequires\x18\x04 \x03(\v2\x1c.swm.plugin.v1.CapabilityDepR\brequires\x12\x1a\n" +
-	"\boptional\x18\x05 \x03(\tR\boptional\"\x88\x01\n" +
+	"\boptional\x18\x05 \x03(\tR\boptional\x12\x1a\n" +
+	"\bfeatures\x18\x06 \x03(\tR\bfeatures\x12(\n" +
+	"\x10min_host_version\x18\a \x01(\tR\x0eminHostVersion\"\x88\x01\n" +
 	"\aProject\x12\x12\n" +
 	"\x04host\x18\x01 \x01(\tR\x04host\x12\x1a\n" +
 	"\bsegments\x18\x02 \x03(\tR\bsegments\x12\x10\n" +
@@ -637,14 +663,15 @@ const file_swm_plugin_v1_common_proto_rawDesc = "" +
 	"\bmetadata\x18\x06 \x03(\v2\".swm.plugin.v1.Story.MetadataEntryR\bmetadata\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\fR\x05value:\x028\x01*\xb8\x01\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value:\x028\x01*\xd5\x01\n" +
 	"\x0eCapabilityType\x12\x1f\n" +
 	"\x1bCAPABILITY_TYPE_UNSPECIFIED\x10\x00\x12\x1b\n" +
 	"\x17CAPABILITY_TYPE_SESSION\x10\x01\x12\x17\n" +
 	"\x13CAPABILITY_TYPE_VCS\x10\x02\x12\x19\n" +
 	"\x15CAPABILITY_TYPE_FORGE\x10\x03\x12\x1a\n" +
 	"\x16CAPABILITY_TYPE_PICKER\x10\x04\x12\x18\n" +
-	"\x14CAPABILITY_TYPE_HOOK\x10\x05B6Z4github.com/kalbasit/swm/proto/swm/plugin/v1;pluginv1b\x06proto3"
+	"\x14CAPABILITY_TYPE_HOOK\x10\x05\x12\x1b\n" +
+	"\x17CAPABILITY_TYPE_TRACKER\x10\x06B6Z4github.com/kalbasit/swm/proto/swm/plugin/v1;pluginv1b\x06proto3"
 
 var (
 	file_swm_plugin_v1_common_proto_rawDescOnce sync.Once
@@ -74,6 +74,59 @@ func (PullRequestState) EnumDescriptor() ([]byte, []int) {
 	return file_swm_plugin_v1_forge_proto_rawDescGZIP(), []int{0}
 }
 
+// CheckState summarizes the CI checks reported for a pull request's head commit.
+type CheckState int32
+
+const (
+	CheckState_CHECK_STATE_UNSPECIFIED CheckState = 0
+	CheckState_CHECK_STATE_PENDING     CheckState = 1
+	CheckState_CHECK_STATE_SUCCESS     CheckState = 2
+	CheckState_CHECK_STATE_FAILURE     CheckState = 3
+)
+
+// Enum value maps for CheckState.
+var (
+	CheckState_name = map[int32]string{
+		0: "CHECK_STATE_UNSPECIFIED",
+		1: "CHECK_STATE_PENDING",
+		2: "CHECK_STATE_SUCCESS",
+		3: "CHECK_STATE_FAILURE",
+	}
+	CheckState_value = map[string]int32{
+		"CHECK_STATE_UNSPECIFIED": 0,
+		"CHECK_STATE_PENDING":     1,
+		"CHECK_STATE_SUCCESS":     2,
+		"CHECK_STATE_FAILURE":     3,
+	}
+)
+
+func (x CheckState) Enum() *CheckState {
+	p := new(CheckState)
+	*p = x
+	return p
+}
+
+func (x CheckState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CheckState) Descriptor() protoreflect.EnumDescriptor {
+	return file_swm_plugin_v1_forge_proto_enumTypes[1].Descriptor()
+}
+
+func (CheckState) Type() protoreflect.EnumType {
+	return &file_swm_plugin_v1_forge_proto_enumTypes[1]
+}
+
+func (x CheckState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CheckState.Descriptor instead.
+func (CheckState) EnumDescriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_forge_proto_rawDescGZIP(), []int{1}
+}
+
 // PullRequestFilter enumerates the valid filter values for listing pull requests.
 type PullRequestFilter int32
 
@@ -111,11 +164,11 @@ func (x PullRequestFilter) String() string {
 }
 
 func (PullRequestFilter) Descriptor() protoreflect.EnumDescriptor {
-	return file_swm_plugin_v1_forge_proto_enumTypes[1].Descriptor()
+	return file_swm_plugin_v1_forge_proto_enumTypes[2].Descriptor()
 }
 
 func (PullRequestFilter) Type() protoreflect.EnumType {
-	return &file_swm_plugin_v1_forge_proto_enumTypes[1]
+	return &file_swm_plugin_v1_forge_proto_enumTypes[2]
 }
 
 func (x PullRequestFilter) Number() protoreflect.EnumNumber {
@@ -124,7 +177,7 @@ func (x PullRequestFilter) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PullRequestFilter.Descriptor instead.
 func (PullRequestFilter) EnumDescriptor() ([]byte, []int) {
-	return file_swm_plugin_v1_forge_proto_rawDescGZIP(), []int{1}
+	return file_swm_plugin_v1_forge_proto_rawDescGZIP(), []int{2}
 }
 
 // ForgeInfo is returned by Forge.Info.
@@ -183,16 +236,27 @@ func (x *ForgeInfo) GetClaimedHosts() []string {
 
 // PullRequest describes a pull/merge request on a forge.
 type PullRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Number        int64                  `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
-	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Body          string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
-	State         PullRequestState       `protobuf:"varint,5,opt,name=state,proto3,enum=swm.plugin.v1.PullRequestState" json:"state,omitempty"`
-	Url           string                 `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`
-	HeadBranch    string                 `protobuf:"bytes,7,opt,name=head_branch,json=headBranch,proto3" json:"head_branch,omitempty"`
-	BaseBranch    string                 `protobuf:"bytes,8,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
-	Draft         bool                   `protobuf:"varint,9,opt,name=draft,proto3" json:"draft,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Number     int64                  `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	Title      string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Body       string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	State      PullRequestState       `protobuf:"varint,5,opt,name=state,proto3,enum=swm.plugin.v1.PullRequestState" json:"state,omitempty"`
+	Url        string                 `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`
+	HeadBranch string                 `protobuf:"bytes,7,opt,name=head_branch,json=headBranch,proto3" json:"head_branch,omitempty"`
+	BaseBranch string                 `protobuf:"bytes,8,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
+	Draft      bool                   `protobuf:"varint,9,opt,name=draft,proto3" json:"draft,omitempty"`
+	// Only populated when requested via ListPRsRequest.include_checks.
+	CheckState CheckState `protobuf:"varint,10,opt,name=check_state,json=checkState,proto3,enum=swm.plugin.v1.CheckState" json:"check_state,omitempty"`
+	// Ref on the base repository that points at the pull request head, usable
+	// even when the head lives in a fork (e.g. "refs/pull/123/head").
+	FetchRef string `protobuf:"bytes,11,opt,name=fetch_ref,json=fetchRef,proto3" json:"fetch_ref,omitempty"`
+	// True when the head branch lives in a different repository than the base.
+	FromFork bool `protobuf:"varint,12,opt,name=from_fork,json=fromFork,proto3" json:"from_fork,omitempty"`
+	// URL to clone the base repository from (e.g.
+	// "https://github.com/org/repo.git"). Empty when the forge does not report
+	// it.
+	CloneUrl      string `protobuf:"bytes,13,opt,name=clone_url,json=cloneUrl,proto3" json:"clone_url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -290,18 +354,110 @@ func (x *PullRequest) GetDraft() bool {
 	return false
 }
 
+func (x *PullRequest) GetCheckState() CheckState {
+	if x != nil {
+		return x.CheckState
+	}
+	return CheckState_CHECK_STATE_UNSPECIFIED
+}
+
+func (x *PullRequest) GetFetchRef() string {
+	if x != nil {
+		return x.FetchRef
+	}
+	return ""
+}
+
+func (x *PullRequest) GetFromFork() bool {
+	if x != nil {
+		return x.FromFork
+	}
+	return false
+}
+
+func (x *PullRequest) GetCloneUrl() string {
+	if x != nil {
+		return x.CloneUrl
+	}
+	return ""
+}
+
+// ForgeEndpoint is how to reach the code host of a project, as configured
+// for it in the host's [plugins.forge_hosts]. Empty fields leave the plugin's
+// own defaults for the host.
+type ForgeEndpoint struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// api_url is the base URL of the host's API, e.g.
+	// "https://github.example.com/api/v3/".
+	ApiUrl string `protobuf:"bytes,1,opt,name=api_url,json=apiUrl,proto3" json:"api_url,omitempty"`
+	// token_path is a file holding the token to authenticate to the API with.
+	TokenPath     string `protobuf:"bytes,2,opt,name=token_path,json=tokenPath,proto3" json:"token_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForgeEndpoint) Reset() {
+	*x = ForgeEndpoint{}
+	mi := &file_swm_plugin_v1_forge_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForgeEndpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForgeEndpoint) ProtoMessage() {}
+
+func (x *ForgeEndpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_forge_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForgeEndpoint.ProtoReflect.Descriptor instead.
+func (*ForgeEndpoint) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_forge_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ForgeEndpoint) GetApiUrl() string {
+	if x != nil {
+		return x.ApiUrl
+	}
+	return ""
+}
+
+func (x *ForgeEndpoint) GetTokenPath() string {
+	if x != nil {
+		return x.TokenPath
+	}
+	return ""
+}
+
 // ListPRsRequest asks for pull requests on a project.
 type ListPRsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProjectId     *ProjectID             `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
-	State         PullRequestFilter      `protobuf:"varint,2,opt,name=state,proto3,enum=swm.plugin.v1.PullRequestFilter" json:"state,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId *ProjectID             `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	State     PullRequestFilter      `protobuf:"varint,2,opt,name=state,proto3,enum=swm.plugin.v1.PullRequestFilter" json:"state,omitempty"`
+	// Optional: only return pull requests whose head is this branch.
+	HeadBranch string `protobuf:"bytes,3,opt,name=head_branch,json=headBranch,proto3" json:"head_branch,omitempty"`
+	// When true, check_state is populated on each returned pull request.
+	IncludeChecks bool `protobuf:"varint,4,opt,name=include_checks,json=includeChecks,proto3" json:"include_checks,omitempty"`
+	// Optional: how to reach project_id.host.
+	Endpoint      *ForgeEndpoint `protobuf:"bytes,5,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListPRsRequest) Reset() {
 	*x = ListPRsRequest{}
-	mi := &file_swm_plugin_v1_forge_proto_msgTypes[2]
+	mi := &file_swm_plugin_v1_forge_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -313,7 +469,7 @@ func (x *ListPRsRequest) String() string {
 func (*ListPRsRequest) ProtoMessage() {}
 
 func (x *ListPRsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_swm_plugin_v1_forge_proto_msgTypes[2]
+	mi := &file_swm_plugin_v1_forge_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -326,7 +482,7 @@ func (x *ListPRsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListPRsRequest.ProtoReflect.Descriptor instead.
 func (*ListPRsRequest) Descriptor() ([]byte, []int) {
-	return file_swm_plugin_v1_forge_proto_rawDescGZIP(), []int{2}
+	return file_swm_plugin_v1_forge_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ListPRsRequest) GetProjectId() *ProjectID {
@@ -343,22 +499,45 @@ func (x *ListPRsRequest) GetState() PullRequestFilter {
 	return PullRequestFilter_PULL_REQUEST_FILTER_UNSPECIFIED
 }
 
+func (x *ListPRsRequest) GetHeadBranch() string {
+	if x != nil {
+		return x.HeadBranch
+	}
+	return ""
+}
+
+func (x *ListPRsRequest) GetIncludeChecks() bool {
+	if x != nil {
+		return x.IncludeChecks
+	}
+	return false
+}
+
+func (x *ListPRsRequest) GetEndpoint() *ForgeEndpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
 // CreatePRRequest asks the plugin to open a new pull request.
 type CreatePRRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProjectId     *ProjectID             `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Body          string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
-	HeadBranch    string                 `protobuf:"bytes,4,opt,name=head_branch,json=headBranch,proto3" json:"head_branch,omitempty"`
-	BaseBranch    string                 `protobuf:"bytes,5,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
-	Draft         bool                   `protobuf:"varint,6,opt,name=draft,proto3" json:"draft,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId  *ProjectID             `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Title      string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Body       string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	HeadBranch string                 `protobuf:"bytes,4,opt,name=head_branch,json=headBranch,proto3" json:"head_branch,omitempty"`
+	BaseBranch string                 `protobuf:"bytes,5,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
+	Draft      bool                   `protobuf:"varint,6,opt,name=draft,proto3" json:"draft,omitempty"`
+	// Optional: how to reach project_id.host.
+	Endpoint      *ForgeEndpoint `protobuf:"bytes,7,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreatePRRequest) Reset() {
 	*x = CreatePRRequest{}
-	mi := &file_swm_plugin_v1_forge_proto_msgTypes[3]
+	mi := &file_swm_plugin_v1_forge_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -370,7 +549,7 @@ func (x *CreatePRRequest) String() string {
 func (*CreatePRRequest) ProtoMessage() {}
 
 func (x *CreatePRRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_swm_plugin_v1_forge_proto_msgTypes[3]
+	mi := &file_swm_plugin_v1_forge_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -383,7 +562,7 @@ func (x *CreatePRRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreatePRRequest.ProtoReflect.Descriptor instead.
 func (*CreatePRRequest) Descriptor() ([]byte, []int) {
-	return file_swm_plugin_v1_forge_proto_rawDescGZIP(), []int{3}
+	return file_swm_plugin_v1_forge_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreatePRRequest) GetProjectId() *ProjectID {
@@ -428,18 +607,27 @@ func (x *CreatePRRequest) GetDraft() bool {
 	return false
 }
 
+func (x *CreatePRRequest) GetEndpoint() *ForgeEndpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
 // GetPRRequest asks the plugin to fetch a single pull request.
 type GetPRRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProjectId     *ProjectID             `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
-	Number        int64                  `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId *ProjectID             `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Number    int64                  `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	// Optional: how to reach project_id.host.
+	Endpoint      *ForgeEndpoint `protobuf:"bytes,3,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetPRRequest) Reset() {
 	*x = GetPRRequest{}
-	mi := &file_swm_plugin_v1_forge_proto_msgTypes[4]
+	mi := &file_swm_plugin_v1_forge_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -451,7 +639,7 @@ func (x *GetPRRequest) String() string {
 func (*GetPRRequest) ProtoMessage() {}
 
 func (x *GetPRRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_swm_plugin_v1_forge_proto_msgTypes[4]
+	mi := &file_swm_plugin_v1_forge_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -464,7 +652,7 @@ func (x *GetPRRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPRRequest.ProtoReflect.Descriptor instead.
 func (*GetPRRequest) Descriptor() ([]byte, []int) {
-	return file_swm_plugin_v1_forge_proto_rawDescGZIP(), []int{4}
+	return file_swm_plugin_v1_forge_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetPRRequest) GetProjectId() *ProjectID {
@@ -481,6 +669,13 @@ func (x *GetPRRequest) GetNumber() int64 {
 	return 0
 }
 
+func (x *GetPRRequest) GetEndpoint() *ForgeEndpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
 var File_swm_plugin_v1_forge_proto protoreflect.FileDescriptor
 
 const file_swm_plugin_v1_forge_proto_rawDesc = "" +
@@ -489,7 +684,7 @@ const file_swm_plugin_v1_forge_proto_rawDesc = "" +
 	"\tForgeInfo\x12:\n" +
 	"\vplugin_info\x18\x01 \x01(\v2\x19.swm.plugin.v1.PluginInfoR\n" +
 	"pluginInfo\x12#\n" +
-	"\rclaimed_hosts\x18\x02 \x03(\tR\fclaimedHosts\"\x80\x02\n" +
+	"\rclaimed_hosts\x18\x02 \x03(\tR\fclaimedHosts\"\x93\x03\n" +
 	"\vPullRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
 	"\x06number\x18\x02 \x01(\x03R\x06number\x12\x14\n" +
@@ -501,11 +696,25 @@ const file_swm_plugin_v1_forge_proto_rawDesc = "" +
 	"headBranch\x12\x1f\n" +
 	"\vbase_branch\x18\b \x01(\tR\n" +
 	"baseBranch\x12\x14\n" +
-	"\x05draft\x18\t \x01(\bR\x05draft\"\x81\x01\n" +
+	"\x05draft\x18\t \x01(\bR\x05draft\x12:\n" +
+	"\vcheck_state\x18\n" +
+	" \x01(\x0e2\x19.swm.plugin.v1.CheckStateR\n" +
+	"checkState\x12\x1b\n" +
+	"\tfetch_ref\x18\v \x01(\tR\bfetchRef\x12\x1b\n" +
+	"\tfrom_fork\x18\f \x01(\bR\bfromFork\x12\x1b\n" +
+	"\tclone_url\x18\r \x01(\tR\bcloneUrl\"G\n" +
+	"\rForgeEndpoint\x12\x17\n" +
+	"\aapi_url\x18\x01 \x01(\tR\x06apiUrl\x12\x1d\n" +
+	"\n" +
+	"token_path\x18\x02 \x01(\tR\ttokenPath\"\x83\x02\n" +
 	"\x0eListPRsRequest\x127\n" +
 	"\n" +
 	"project_id\x18\x01 \x01(\v2\x18.swm.plugin.v1.ProjectIDR\tprojectId\x126\n" +
-	"\x05state\x18\x02 \x01(\x0e2 .swm.plugin.v1.PullRequestFilterR\x05state\"\xcc\x01\n" +
+	"\x05state\x18\x02 \x01(\x0e2 .swm.plugin.v1.PullRequestFilterR\x05state\x12\x1f\n" +
+	"\vhead_branch\x18\x03 \x01(\tR\n" +
+	"headBranch\x12%\n" +
+	"\x0einclude_checks\x18\x04 \x01(\bR\rincludeChecks\x128\n" +
+	"\bendpoint\x18\x05 \x01(\v2\x1c.swm.plugin.v1.ForgeEndpointR\bendpoint\"\x86\x02\n" +
 	"\x0fCreatePRRequest\x127\n" +
 	"\n" +
 	"project_id\x18\x01 \x01(\v2\x18.swm.plugin.v1.ProjectIDR\tprojectId\x12\x14\n" +
@@ -515,16 +724,24 @@ const file_swm_plugin_v1_forge_proto_rawDesc = "" +
 	"headBranch\x12\x1f\n" +
 	"\vbase_branch\x18\x05 \x01(\tR\n" +
 	"baseBranch\x12\x14\n" +
-	"\x05draft\x18\x06 \x01(\bR\x05draft\"_\n" +
+	"\x05draft\x18\x06 \x01(\bR\x05draft\x128\n" +
+	"\bendpoint\x18\a \x01(\v2\x1c.swm.plugin.v1.ForgeEndpointR\bendpoint\"\x99\x01\n" +
 	"\fGetPRRequest\x127\n" +
 	"\n" +
 	"project_id\x18\x01 \x01(\v2\x18.swm.plugin.v1.ProjectIDR\tprojectId\x12\x16\n" +
-	"\x06number\x18\x02 \x01(\x03R\x06number*\x91\x01\n" +
+	"\x06number\x18\x02 \x01(\x03R\x06number\x128\n" +
+	"\bendpoint\x18\x03 \x01(\v2\x1c.swm.plugin.v1.ForgeEndpointR\bendpoint*\x91\x01\n" +
 	"\x10PullRequestState\x12\"\n" +
 	"\x1ePULL_REQUEST_STATE_UNSPECIFIED\x10\x00\x12\x1b\n" +
 	"\x17PULL_REQUEST_STATE_OPEN\x10\x01\x12\x1d\n" +
 	"\x19PULL_REQUEST_STATE_CLOSED\x10\x02\x12\x1d\n" +
-	"\x19PULL_REQUEST_STATE_MERGED\x10\x03*\x93\x01\n" +
+	"\x19PULL_REQUEST_STATE_MERGED\x10\x03*t\n" +
+	"\n" +
+	"CheckState\x12\x1b\n" +
+	"\x17CHECK_STATE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13CHECK_STATE_PENDING\x10\x01\x12\x17\n" +
+	"\x13CHECK_STATE_SUCCESS\x10\x02\x12\x17\n" +
+	"\x13CHECK_STATE_FAILURE\x10\x03*\x93\x01\n" +
 	"\x11PullRequestFilter\x12#\n" +
 	"\x1fPULL_REQUEST_FILTER_UNSPECIFIED\x10\x00\x12\x1c\n" +
 	"\x18PULL_REQUEST_FILTER_OPEN\x10\x01\x12\x1e\n" +
@@ -548,40 +765,46 @@ func file_swm_plugin_v1_forge_proto_rawDescGZIP() []byte {
 	return file_swm_plugin_v1_forge_proto_rawDescData
 }
 
-var file_swm_plugin_v1_forge_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_swm_plugin_v1_forge_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_swm_plugin_v1_forge_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_swm_plugin_v1_forge_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_swm_plugin_v1_forge_proto_goTypes = []any{
 	(PullRequestState)(0),   // 0: swm.plugin.v1.PullRequestState
-	(PullRequestFilter)(0),  // 1: swm.plugin.v1.PullRequestFilter
-	(*ForgeInfo)(nil),       // 2: swm.plugin.v1.ForgeInfo
-	(*PullRequest)(nil),     // 3: swm.plugin.v1.PullRequest
-	(*ListPRsRequest)(nil),  // 4: swm.plugin.v1.ListPRsRequest
-	(*CreatePRRequest)(nil), // 5: swm.plugin.v1.CreatePRRequest
-	(*GetPRRequest)(nil),    // 6: swm.plugin.v1.GetPRRequest
-	(*PluginInfo)(nil),      // 7: swm.plugin.v1.PluginInfo
-	(*ProjectID)(nil),       // 8: swm.plugin.v1.ProjectID
-	(*Empty)(nil),           // 9: swm.plugin.v1.Empty
+	(CheckState)(0),         // 1: swm.plugin.v1.CheckState
+	(PullRequestFilter)(0),  // 2: swm.plugin.v1.PullRequestFilter
+	(*ForgeInfo)(nil),       // 3: swm.plugin.v1.ForgeInfo
+	(*PullRequest)(nil),     // 4: swm.plugin.v1.PullRequest
+	(*ForgeEndpoint)(nil),   // 5: swm.plugin.v1.ForgeEndpoint
+	(*ListPRsRequest)(nil),  // 6: swm.plugin.v1.ListPRsRequest
+	(*CreatePRRequest)(nil), // 7: swm.plugin.v1.CreatePRRequest
+	(*GetPRRequest)(nil),    // 8: swm.plugin.v1.GetPRRequest
+	(*PluginInfo)(nil),      // 9: swm.plugin.v1.PluginInfo
+	(*ProjectID)(nil),       // 10: swm.plugin.v1.ProjectID
+	(*Empty)(nil),           // 11: swm.plugin.v1.Empty
 }
 var file_swm_plugin_v1_forge_proto_depIdxs = []int32{
-	7,  // 0: swm.plugin.v1.ForgeInfo.plugin_info:type_name -> swm.plugin.v1.PluginInfo
+	9,  // 0: swm.plugin.v1.ForgeInfo.plugin_info:type_name -> swm.plugin.v1.PluginInfo
 	0,  // 1: swm.plugin.v1.PullRequest.state:type_name -> swm.plugin.v1.PullRequestState
-	8,  // 2: swm.plugin.v1.ListPRsRequest.project_id:type_name -> swm.plugin.v1.ProjectID
-	1,  // 3: swm.plugin.v1.ListPRsRequest.state:type_name -> swm.plugin.v1.PullRequestFilter
-	8,  // 4: swm.plugin.v1.CreatePRRequest.project_id:type_name -> swm.plugin.v1.ProjectID
-	8,  // 5: swm.plugin.v1.GetPRRequest.project_id:type_name -> swm.plugin.v1.ProjectID
-	9,  // 6: swm.plugin.v1.Forge.Info:input_type -> swm.plugin.v1.Empty
-	4,  // 7: swm.plugin.v1.Forge.ListPullRequests:input_type -> swm.plugin.v1.ListPRsRequest
-	5,  // 8: swm.plugin.v1.Forge.CreatePullRequest:input_type -> swm.plugin.v1.CreatePRRequest
-	6,  // 9: swm.plugin.v1.Forge.GetPullRequest:input_type -> swm.plugin.v1.GetPRRequest
-	2,  // 10: swm.plugin.v1.Forge.Info:output_type -> swm.plugin.v1.ForgeInfo
-	3,  // 11: swm.plugin.v1.Forge.ListPullRequests:output_type -> swm.plugin.v1.PullRequest
-	3,  // 12: swm.plugin.v1.Forge.CreatePullRequest:output_type -> swm.plugin.v1.PullRequest
-	3,  // 13: swm.plugin.v1.Forge.GetPullRequest:output_type -> swm.plugin.v1.PullRequest
-	10, // [10:14] is the sub-list for method output_type
-	6,  // [6:10] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	1,  // 2: swm.plugin.v1.PullRequest.check_state:type_name -> swm.plugin.v1.CheckState
+	10, // 3: swm.plugin.v1.ListPRsRequest.project_id:type_name -> swm.plugin.v1.ProjectID
+	2,  // 4: swm.plugin.v1.ListPRsRequest.state:type_name -> swm.plugin.v1.PullRequestFilter
+	5,  // 5: swm.plugin.v1.ListPRsRequest.endpoint:type_name -> swm.plugin.v1.ForgeEndpoint
+	10, // 6: swm.plugin.v1.CreatePRRequest.project_id:type_name -> swm.plugin.v1.ProjectID
+	5,  // 7: swm.plugin.v1.CreatePRRequest.endpoint:type_name -> swm.plugin.v1.ForgeEndpoint
+	10, // 8: swm.plugin.v1.GetPRRequest.project_id:type_name -> swm.plugin.v1.ProjectID
+	5,  // 9: swm.plugin.v1.GetPRRequest.endpoint:type_name -> swm.plugin.v1.ForgeEndpoint
+	11, // 10: swm.plugin.v1.Forge.Info:input_type -> swm.plugin.v1.Empty
+	6,  // 11: swm.plugin.v1.Forge.ListPullRequests:input_type -> swm.plugin.v1.ListPRsRequest
+	7,  // 12: swm.plugin.v1.Forge.CreatePullRequest:input_type -> swm.plugin.v1.CreatePRRequest
+	8,  // 13: swm.plugin.v1.Forge.GetPullRequest:input_type -> swm.plugin.v1.GetPRRequest
+	3,  // 14: swm.plugin.v1.Forge.Info:output_type -> swm.plugin.v1.ForgeInfo
+	4,  // 15: swm.plugin.v1.Forge.ListPullRequests:output_type -> swm.plugin.v1.PullRequest
+	4,  // 16: swm.plugin.v1.Forge.CreatePullRequest:output_type -> swm.plugin.v1.PullRequest
+	4,  // 17: swm.plugin.v1.Forge.GetPullRequest:output_type -> swm.plugin.v1.PullRequest
+	14, // [14:18] is the sub-list for method output_type
+	10, // [10:14] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_swm_plugin_v1_forge_proto_init() }
@@ -595,8 +818,8 @@ func file_swm_plugin_v1_forge_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_swm_plugin_v1_forge_proto_rawDesc), len(file_swm_plugin_v1_forge_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   5,
+			NumEnums:      3,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
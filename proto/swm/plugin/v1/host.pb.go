@@ -266,13 +266,17 @@ func (x *LogRequest) GetFields() map[string]string {
 	return nil
 }
 
-// CallCapabilityRequest routes a cross-plugin call through the host.
+// CallCapabilityRequest routes a cross-plugin call through the host. The
+// calling plugin is identified by the token the host issued it at launch, in
+// SWM_CALLER_TOKEN, which it sends in the "swm-caller-token" metadata, and
+// must declare capability in the requires or optional of its PluginInfo.
 type CallCapabilityRequest struct {
 	state      protoimpl.MessageState `protogen:"open.v1"`
 	Capability CapabilityType         `protobuf:"varint,1,opt,name=capability,proto3,enum=swm.plugin.v1.CapabilityType" json:"capability,omitempty"`
-	// method is the RPC name (e.g. "CreateWorktree").
+	// method is the RPC name (e.g. "CreateWorktree"). Client-streaming RPCs
+	// cannot be called.
 	Method string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
-	// args_json is the request serialised as JSON.
+	// args_json is the request serialised as JSON (protobuf JSON mapping).
 	ArgsJson      string `protobuf:"bytes,3,opt,name=args_json,json=argsJson,proto3" json:"args_json,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -332,7 +336,8 @@ func (x *CallCapabilityRequest) GetArgsJson() string {
 // CallCapabilityResponse carries the cross-plugin call's result.
 type CallCapabilityResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// result_json is the response serialised as JSON.
+	// result_json is the response serialised as JSON, or a JSON array of the
+	// responses of a server-streaming RPC.
 	ResultJson    string `protobuf:"bytes,1,opt,name=result_json,json=resultJson,proto3" json:"result_json,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -9,6 +9,7 @@ package pluginv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -129,11 +130,15 @@ func (x *Workspace) GetActive() bool {
 
 // PaneGroup represents a project's working environment inside a workspace.
 type PaneGroup struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	PaneGroupId   string                 `protobuf:"bytes,1,opt,name=pane_group_id,json=paneGroupId,proto3" json:"pane_group_id,omitempty"`
-	WorkspaceId   string                 `protobuf:"bytes,2,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
-	ProjectId     *ProjectID             `protobuf:"bytes,3,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
-	WorktreePath  string                 `protobuf:"bytes,4,opt,name=worktree_path,json=worktreePath,proto3" json:"worktree_path,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	PaneGroupId  string                 `protobuf:"bytes,1,opt,name=pane_group_id,json=paneGroupId,proto3" json:"pane_group_id,omitempty"`
+	WorkspaceId  string                 `protobuf:"bytes,2,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	ProjectId    *ProjectID             `protobuf:"bytes,3,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	WorktreePath string                 `protobuf:"bytes,4,opt,name=worktree_path,json=worktreePath,proto3" json:"worktree_path,omitempty"`
+	// Populated by ListPaneGroups; zero when the plugin cannot report them.
+	WindowCount   int32                  `protobuf:"varint,5,opt,name=window_count,json=windowCount,proto3" json:"window_count,omitempty"`
+	LastActivity  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_activity,json=lastActivity,proto3" json:"last_activity,omitempty"`
+	StoryName     string                 `protobuf:"bytes,7,opt,name=story_name,json=storyName,proto3" json:"story_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -196,6 +201,27 @@ func (x *PaneGroup) GetWorktreePath() string {
 	return ""
 }
 
+func (x *PaneGroup) GetWindowCount() int32 {
+	if x != nil {
+		return x.WindowCount
+	}
+	return 0
+}
+
+func (x *PaneGroup) GetLastActivity() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastActivity
+	}
+	return nil
+}
+
+func (x *PaneGroup) GetStoryName() string {
+	if x != nil {
+		return x.StoryName
+	}
+	return ""
+}
+
 // CurrentContextResponse describes the session the caller is currently inside.
 type CurrentContextResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -271,6 +297,14 @@ type OpenWorkspaceRequest struct {
 	StoryName string                 `protobuf:"bytes,1,opt,name=story_name,json=storyName,proto3" json:"story_name,omitempty"`
 	// worktree_paths maps project key (host/seg1/.../segN) to absolute path.
 	WorktreePaths map[string]string `protobuf:"bytes,2,rep,name=worktree_paths,json=worktreePaths,proto3" json:"worktree_paths,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Optional: when non-empty and the workspace is not already running, the
+	// plugin SHALL recreate its pane groups from the snapshot at this path.
+	// A missing snapshot file is not an error.
+	RestoreSnapshotPath string `protobuf:"bytes,3,opt,name=restore_snapshot_path,json=restoreSnapshotPath,proto3" json:"restore_snapshot_path,omitempty"`
+	// env holds the story's environment variables. The plugin SHALL make them
+	// available to every process it starts in the workspace from now on, and
+	// SHALL drop variables it set on a previous call that are no longer present.
+	Env           map[string]string `protobuf:"bytes,4,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -319,10 +353,27 @@ func (x *OpenWorkspaceRequest) GetWorktreePaths() map[string]string {
 	return nil
 }
 
+func (x *OpenWorkspaceRequest) GetRestoreSnapshotPath() string {
+	if x != nil {
+		return x.RestoreSnapshotPath
+	}
+	return ""
+}
+
+func (x *OpenWorkspaceRequest) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
 // CloseWorkspaceRequest asks the plugin to tear down a workspace.
 type CloseWorkspaceRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	// Optional: when non-empty, the plugin SHALL save a snapshot of the
+	// workspace to this path before tearing it down (autosave).
+	SnapshotPath  string `protobuf:"bytes,2,opt,name=snapshot_path,json=snapshotPath,proto3" json:"snapshot_path,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -364,30 +415,37 @@ func (x *CloseWorkspaceRequest) GetWorkspaceId() string {
 	return ""
 }
 
-// OpenPaneGroupRequest asks the plugin to open a project pane inside a workspace.
-type OpenPaneGroupRequest struct {
+func (x *CloseWorkspaceRequest) GetSnapshotPath() string {
+	if x != nil {
+		return x.SnapshotPath
+	}
+	return ""
+}
+
+// SaveWorkspaceRequest asks the plugin to serialize a workspace to disk.
+// The snapshot format is plugin-specific; the host only chooses where it lives.
+type SaveWorkspaceRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
-	ProjectId     *ProjectID             `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
-	WorktreePath  string                 `protobuf:"bytes,3,opt,name=worktree_path,json=worktreePath,proto3" json:"worktree_path,omitempty"`
+	SnapshotPath  string                 `protobuf:"bytes,2,opt,name=snapshot_path,json=snapshotPath,proto3" json:"snapshot_path,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OpenPaneGroupRequest) Reset() {
-	*x = OpenPaneGroupRequest{}
+func (x *SaveWorkspaceRequest) Reset() {
+	*x = SaveWorkspaceRequest{}
 	mi := &file_swm_plugin_v1_session_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OpenPaneGroupRequest) String() string {
+func (x *SaveWorkspaceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OpenPaneGroupRequest) ProtoMessage() {}
+func (*SaveWorkspaceRequest) ProtoMessage() {}
 
-func (x *OpenPaneGroupRequest) ProtoReflect() protoreflect.Message {
+func (x *SaveWorkspaceRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_swm_plugin_v1_session_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -399,62 +457,107 @@ func (x *OpenPaneGroupRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OpenPaneGroupRequest.ProtoReflect.Descriptor instead.
-func (*OpenPaneGroupRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SaveWorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*SaveWorkspaceRequest) Descriptor() ([]byte, []int) {
 	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *OpenPaneGroupRequest) GetWorkspaceId() string {
+func (x *SaveWorkspaceRequest) GetWorkspaceId() string {
 	if x != nil {
 		return x.WorkspaceId
 	}
 	return ""
 }
 
-func (x *OpenPaneGroupRequest) GetProjectId() *ProjectID {
+func (x *SaveWorkspaceRequest) GetSnapshotPath() string {
 	if x != nil {
-		return x.ProjectId
+		return x.SnapshotPath
 	}
-	return nil
+	return ""
 }
 
-func (x *OpenPaneGroupRequest) GetWorktreePath() string {
+// SaveWorkspaceResponse is returned by Session.SaveWorkspace.
+type SaveWorkspaceResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SnapshotPath   string                 `protobuf:"bytes,1,opt,name=snapshot_path,json=snapshotPath,proto3" json:"snapshot_path,omitempty"`
+	PaneGroupCount int32                  `protobuf:"varint,2,opt,name=pane_group_count,json=paneGroupCount,proto3" json:"pane_group_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SaveWorkspaceResponse) Reset() {
+	*x = SaveWorkspaceResponse{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveWorkspaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveWorkspaceResponse) ProtoMessage() {}
+
+func (x *SaveWorkspaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[7]
 	if x != nil {
-		return x.WorktreePath
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveWorkspaceResponse.ProtoReflect.Descriptor instead.
+func (*SaveWorkspaceResponse) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SaveWorkspaceResponse) GetSnapshotPath() string {
+	if x != nil {
+		return x.SnapshotPath
 	}
 	return ""
 }
 
-// SwitchToRequest asks the plugin to bring a pane group into focus.
-type SwitchToRequest struct {
-	state       protoimpl.MessageState `protogen:"open.v1"`
-	WorkspaceId string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
-	PaneGroupId string                 `protobuf:"bytes,2,opt,name=pane_group_id,json=paneGroupId,proto3" json:"pane_group_id,omitempty"`
-	// Optional: if non-empty, the plugin SHALL close the originating pane after
-	// switching. close_origin_workspace_id identifies the source workspace and
-	// close_origin_pane_id is the multiplexer-specific pane reference (e.g.
-	// $TMUX_PANE for session-tmux).
-	CloseOriginWorkspaceId string `protobuf:"bytes,3,opt,name=close_origin_workspace_id,json=closeOriginWorkspaceId,proto3" json:"close_origin_workspace_id,omitempty"`
-	CloseOriginPaneId      string `protobuf:"bytes,4,opt,name=close_origin_pane_id,json=closeOriginPaneId,proto3" json:"close_origin_pane_id,omitempty"`
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+func (x *SaveWorkspaceResponse) GetPaneGroupCount() int32 {
+	if x != nil {
+		return x.PaneGroupCount
+	}
+	return 0
+}
+
+// RestoreWorkspaceRequest asks the plugin to recreate a workspace from a
+// snapshot previously written by SaveWorkspace.
+type RestoreWorkspaceRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	StoryName    string                 `protobuf:"bytes,1,opt,name=story_name,json=storyName,proto3" json:"story_name,omitempty"`
+	SnapshotPath string                 `protobuf:"bytes,2,opt,name=snapshot_path,json=snapshotPath,proto3" json:"snapshot_path,omitempty"`
+	// env holds the story's environment variables, SWM_PORT_BASE and
+	// SWM_PORT_<NAME> included, as in OpenWorkspaceRequest.env. The plugin SHALL
+	// apply it to the workspace the same way OpenWorkspace does.
+	Env           map[string]string `protobuf:"bytes,3,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SwitchToRequest) Reset() {
-	*x = SwitchToRequest{}
-	mi := &file_swm_plugin_v1_session_proto_msgTypes[7]
+func (x *RestoreWorkspaceRequest) Reset() {
+	*x = RestoreWorkspaceRequest{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SwitchToRequest) String() string {
+func (x *RestoreWorkspaceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SwitchToRequest) ProtoMessage() {}
+func (*RestoreWorkspaceRequest) ProtoMessage() {}
 
-func (x *SwitchToRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_swm_plugin_v1_session_proto_msgTypes[7]
+func (x *RestoreWorkspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -465,64 +568,201 @@ func (x *SwitchToRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SwitchToRequest.ProtoReflect.Descriptor instead.
-func (*SwitchToRequest) Descriptor() ([]byte, []int) {
-	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use RestoreWorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*RestoreWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *SwitchToRequest) GetWorkspaceId() string {
+func (x *RestoreWorkspaceRequest) GetStoryName() string {
 	if x != nil {
-		return x.WorkspaceId
+		return x.StoryName
 	}
 	return ""
 }
 
-func (x *SwitchToRequest) GetPaneGroupId() string {
+func (x *RestoreWorkspaceRequest) GetSnapshotPath() string {
 	if x != nil {
-		return x.PaneGroupId
+		return x.SnapshotPath
 	}
 	return ""
 }
 
-func (x *SwitchToRequest) GetCloseOriginWorkspaceId() string {
+func (x *RestoreWorkspaceRequest) GetEnv() map[string]string {
 	if x != nil {
-		return x.CloseOriginWorkspaceId
+		return x.Env
+	}
+	return nil
+}
+
+// RestoreWorkspaceResponse is returned by Session.RestoreWorkspace.
+type RestoreWorkspaceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workspace     *Workspace             `protobuf:"bytes,1,opt,name=workspace,proto3" json:"workspace,omitempty"`
+	PaneGroupIds  []string               `protobuf:"bytes,2,rep,name=pane_group_ids,json=paneGroupIds,proto3" json:"pane_group_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreWorkspaceResponse) Reset() {
+	*x = RestoreWorkspaceResponse{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreWorkspaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreWorkspaceResponse) ProtoMessage() {}
+
+func (x *RestoreWorkspaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreWorkspaceResponse.ProtoReflect.Descriptor instead.
+func (*RestoreWorkspaceResponse) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RestoreWorkspaceResponse) GetWorkspace() *Workspace {
+	if x != nil {
+		return x.Workspace
+	}
+	return nil
+}
+
+func (x *RestoreWorkspaceResponse) GetPaneGroupIds() []string {
+	if x != nil {
+		return x.PaneGroupIds
+	}
+	return nil
+}
+
+// OpenPaneGroupRequest asks the plugin to open a project pane inside a workspace.
+type OpenPaneGroupRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId  string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	ProjectId    *ProjectID             `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	WorktreePath string                 `protobuf:"bytes,3,opt,name=worktree_path,json=worktreePath,proto3" json:"worktree_path,omitempty"`
+	// env holds the environment of the project: the story's variables with
+	// the project's overrides. The plugin SHALL set it for every pane of a pane
+	// group it creates.
+	Env map[string]string `protobuf:"bytes,4,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// port_base is the first port of the block allocated to the story, or 0
+	// when it has none. ports maps the names the project declares to ports of
+	// that block. Both are also in env as SWM_PORT_BASE and SWM_PORT_<NAME>;
+	// plugins expose them to their own configuration (e.g. layout templates).
+	PortBase      uint32            `protobuf:"varint,5,opt,name=port_base,json=portBase,proto3" json:"port_base,omitempty"`
+	Ports         map[string]uint32 `protobuf:"bytes,6,rep,name=ports,proto3" json:"ports,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenPaneGroupRequest) Reset() {
+	*x = OpenPaneGroupRequest{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenPaneGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenPaneGroupRequest) ProtoMessage() {}
+
+func (x *OpenPaneGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenPaneGroupRequest.ProtoReflect.Descriptor instead.
+func (*OpenPaneGroupRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *OpenPaneGroupRequest) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
 	}
 	return ""
 }
 
-func (x *SwitchToRequest) GetCloseOriginPaneId() string {
+func (x *OpenPaneGroupRequest) GetProjectId() *ProjectID {
 	if x != nil {
-		return x.CloseOriginPaneId
+		return x.ProjectId
+	}
+	return nil
+}
+
+func (x *OpenPaneGroupRequest) GetWorktreePath() string {
+	if x != nil {
+		return x.WorktreePath
 	}
 	return ""
 }
 
-// SwitchToResponse is returned by Session.SwitchTo.
-// When exec_argv is non-empty the host MUST exec the command to hand the
-// terminal over (e.g. tmux attach-session when not already inside tmux).
-type SwitchToResponse struct {
+func (x *OpenPaneGroupRequest) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *OpenPaneGroupRequest) GetPortBase() uint32 {
+	if x != nil {
+		return x.PortBase
+	}
+	return 0
+}
+
+func (x *OpenPaneGroupRequest) GetPorts() map[string]uint32 {
+	if x != nil {
+		return x.Ports
+	}
+	return nil
+}
+
+// ListPaneGroupsRequest asks the plugin for the live pane groups in a workspace.
+// An empty workspace_id lists the pane groups of every live workspace.
+type ListPaneGroupsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ExecArgv      []string               `protobuf:"bytes,1,rep,name=exec_argv,json=execArgv,proto3" json:"exec_argv,omitempty"`
+	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SwitchToResponse) Reset() {
-	*x = SwitchToResponse{}
-	mi := &file_swm_plugin_v1_session_proto_msgTypes[8]
+func (x *ListPaneGroupsRequest) Reset() {
+	*x = ListPaneGroupsRequest{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SwitchToResponse) String() string {
+func (x *ListPaneGroupsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SwitchToResponse) ProtoMessage() {}
+func (*ListPaneGroupsRequest) ProtoMessage() {}
 
-func (x *SwitchToResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_swm_plugin_v1_session_proto_msgTypes[8]
+func (x *ListPaneGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -533,74 +773,692 @@ func (x *SwitchToResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SwitchToResponse.ProtoReflect.Descriptor instead.
-func (*SwitchToResponse) Descriptor() ([]byte, []int) {
-	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ListPaneGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListPaneGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *SwitchToResponse) GetExecArgv() []string {
+func (x *ListPaneGroupsRequest) GetWorkspaceId() string {
 	if x != nil {
-		return x.ExecArgv
+		return x.WorkspaceId
 	}
-	return nil
+	return ""
 }
 
-var File_swm_plugin_v1_session_proto protoreflect.FileDescriptor
+// ClosePaneGroupRequest asks the plugin to tear down a single pane group,
+// leaving the rest of the workspace running.
+type ClosePaneGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	PaneGroupId   string                 `protobuf:"bytes,2,opt,name=pane_group_id,json=paneGroupId,proto3" json:"pane_group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_swm_plugin_v1_session_proto_rawDesc = "" +
-	"\n" +
-	"\x1bswm/plugin/v1/session.proto\x12\rswm.plugin.v1\x1a\x1aswm/plugin/v1/common.proto\"I\n" +
-	"\vSessionInfo\x12:\n" +
-	"\vplugin_info\x18\x01 \x01(\v2\x19.swm.plugin.v1.PluginInfoR\n" +
-	"pluginInfo\"e\n" +
-	"\tWorkspace\x12!\n" +
-	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\x1d\n" +
-	"\n" +
-	"story_name\x18\x02 \x01(\tR\tstoryName\x12\x16\n" +
-	"\x06active\x18\x03 \x01(\bR\x06active\"\xb0\x01\n" +
-	"\tPaneGroup\x12\"\n" +
-	"\rpane_group_id\x18\x01 \x01(\tR\vpaneGroupId\x12!\n" +
-	"\fworkspace_id\x18\x02 \x01(\tR\vworkspaceId\x127\n" +
-	"\n" +
-	"project_id\x18\x03 \x01(\v2\x18.swm.plugin.v1.ProjectIDR\tprojectId\x12#\n" +
-	"\rworktree_path\x18\x04 \x01(\tR\fworktreePath\"\xb7\x01\n" +
-	"\x16CurrentContextResponse\x12!\n" +
-	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\x1d\n" +
+func (x *ClosePaneGroupRequest) Reset() {
+	*x = ClosePaneGroupRequest{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClosePaneGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClosePaneGroupRequest) ProtoMessage() {}
+
+func (x *ClosePaneGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClosePaneGroupRequest.ProtoReflect.Descriptor instead.
+func (*ClosePaneGroupRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ClosePaneGroupRequest) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *ClosePaneGroupRequest) GetPaneGroupId() string {
+	if x != nil {
+		return x.PaneGroupId
+	}
+	return ""
+}
+
+// SwitchToRequest asks the plugin to bring a pane group into focus.
+type SwitchToRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	PaneGroupId string                 `protobuf:"bytes,2,opt,name=pane_group_id,json=paneGroupId,proto3" json:"pane_group_id,omitempty"`
+	// Optional: if non-empty, the plugin SHALL close the originating pane after
+	// switching. close_origin_workspace_id identifies the source workspace and
+	// close_origin_pane_id is the multiplexer-specific pane reference (e.g.
+	// $TMUX_PANE for session-tmux).
+	CloseOriginWorkspaceId string `protobuf:"bytes,3,opt,name=close_origin_workspace_id,json=closeOriginWorkspaceId,proto3" json:"close_origin_workspace_id,omitempty"`
+	CloseOriginPaneId      string `protobuf:"bytes,4,opt,name=close_origin_pane_id,json=closeOriginPaneId,proto3" json:"close_origin_pane_id,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *SwitchToRequest) Reset() {
+	*x = SwitchToRequest{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwitchToRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwitchToRequest) ProtoMessage() {}
+
+func (x *SwitchToRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwitchToRequest.ProtoReflect.Descriptor instead.
+func (*SwitchToRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SwitchToRequest) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *SwitchToRequest) GetPaneGroupId() string {
+	if x != nil {
+		return x.PaneGroupId
+	}
+	return ""
+}
+
+func (x *SwitchToRequest) GetCloseOriginWorkspaceId() string {
+	if x != nil {
+		return x.CloseOriginWorkspaceId
+	}
+	return ""
+}
+
+func (x *SwitchToRequest) GetCloseOriginPaneId() string {
+	if x != nil {
+		return x.CloseOriginPaneId
+	}
+	return ""
+}
+
+// SwitchToResponse is returned by Session.SwitchTo.
+// When exec_argv is non-empty the host MUST exec the command to hand the
+// terminal over (e.g. tmux attach-session when not already inside tmux).
+type SwitchToResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExecArgv      []string               `protobuf:"bytes,1,rep,name=exec_argv,json=execArgv,proto3" json:"exec_argv,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SwitchToResponse) Reset() {
+	*x = SwitchToResponse{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwitchToResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwitchToResponse) ProtoMessage() {}
+
+func (x *SwitchToResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwitchToResponse.ProtoReflect.Descriptor instead.
+func (*SwitchToResponse) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SwitchToResponse) GetExecArgv() []string {
+	if x != nil {
+		return x.ExecArgv
+	}
+	return nil
+}
+
+// AttachCommandRequest asks for the command that attaches a terminal to a
+// pane group. An empty pane_group_id attaches to the workspace.
+type AttachCommandRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	PaneGroupId   string                 `protobuf:"bytes,2,opt,name=pane_group_id,json=paneGroupId,proto3" json:"pane_group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachCommandRequest) Reset() {
+	*x = AttachCommandRequest{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachCommandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachCommandRequest) ProtoMessage() {}
+
+func (x *AttachCommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachCommandRequest.ProtoReflect.Descriptor instead.
+func (*AttachCommandRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AttachCommandRequest) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *AttachCommandRequest) GetPaneGroupId() string {
+	if x != nil {
+		return x.PaneGroupId
+	}
+	return ""
+}
+
+// AttachCommandResponse carries the attach command as an argv.
+type AttachCommandResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Argv          []string               `protobuf:"bytes,1,rep,name=argv,proto3" json:"argv,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachCommandResponse) Reset() {
+	*x = AttachCommandResponse{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachCommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachCommandResponse) ProtoMessage() {}
+
+func (x *AttachCommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachCommandResponse.ProtoReflect.Descriptor instead.
+func (*AttachCommandResponse) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AttachCommandResponse) GetArgv() []string {
+	if x != nil {
+		return x.Argv
+	}
+	return nil
+}
+
+// StartServiceRequest starts a long-running service process in a workspace.
+type StartServiceRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	// name identifies the service within the workspace, e.g.
+	// "github.com/kalbasit/swm:web". Starting a name that is already running
+	// returns the running service.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// argv is executed directly, without a shell.
+	Argv []string `protobuf:"bytes,3,rep,name=argv,proto3" json:"argv,omitempty"`
+	// env is added to the service's environment.
+	Env     map[string]string `protobuf:"bytes,4,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	WorkDir string            `protobuf:"bytes,5,opt,name=work_dir,json=workDir,proto3" json:"work_dir,omitempty"`
+	// Optional: when set, everything the service prints is appended to this file.
+	LogPath       string `protobuf:"bytes,6,opt,name=log_path,json=logPath,proto3" json:"log_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartServiceRequest) Reset() {
+	*x = StartServiceRequest{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartServiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartServiceRequest) ProtoMessage() {}
+
+func (x *StartServiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartServiceRequest.ProtoReflect.Descriptor instead.
+func (*StartServiceRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *StartServiceRequest) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *StartServiceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StartServiceRequest) GetArgv() []string {
+	if x != nil {
+		return x.Argv
+	}
+	return nil
+}
+
+func (x *StartServiceRequest) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *StartServiceRequest) GetWorkDir() string {
+	if x != nil {
+		return x.WorkDir
+	}
+	return ""
+}
+
+func (x *StartServiceRequest) GetLogPath() string {
+	if x != nil {
+		return x.LogPath
+	}
+	return ""
+}
+
+// Service is a service process started by StartService.
+type Service struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ServiceId string                 `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Pid       int64                  `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
+	Running   bool                   `protobuf:"varint,4,opt,name=running,proto3" json:"running,omitempty"`
+	// exit_code is set when running is false.
+	ExitCode      int32 `protobuf:"varint,5,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Service) Reset() {
+	*x = Service{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Service) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Service) ProtoMessage() {}
+
+func (x *Service) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Service.ProtoReflect.Descriptor instead.
+func (*Service) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Service) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *Service) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Service) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *Service) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *Service) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+// ListServicesRequest lists the services of a workspace.
+type ListServicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServicesRequest) Reset() {
+	*x = ListServicesRequest{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServicesRequest) ProtoMessage() {}
+
+func (x *ListServicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServicesRequest.ProtoReflect.Descriptor instead.
+func (*ListServicesRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListServicesRequest) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+// StopServiceRequest stops a service. Stopping a service that is gone succeeds.
+type StopServiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	ServiceId     string                 `protobuf:"bytes,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopServiceRequest) Reset() {
+	*x = StopServiceRequest{}
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopServiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopServiceRequest) ProtoMessage() {}
+
+func (x *StopServiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_session_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopServiceRequest.ProtoReflect.Descriptor instead.
+func (*StopServiceRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_session_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *StopServiceRequest) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *StopServiceRequest) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+var File_swm_plugin_v1_session_proto protoreflect.FileDescriptor
+
+const file_swm_plugin_v1_session_proto_rawDesc = "" +
+	"\n" +
+	"\x1bswm/plugin/v1/session.proto\x12\rswm.plugin.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1aswm/plugin/v1/common.proto\"I\n" +
+	"\vSessionInfo\x12:\n" +
+	"\vplugin_info\x18\x01 \x01(\v2\x19.swm.plugin.v1.PluginInfoR\n" +
+	"pluginInfo\"e\n" +
+	"\tWorkspace\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\x1d\n" +
+	"\n" +
+	"story_name\x18\x02 \x01(\tR\tstoryName\x12\x16\n" +
+	"\x06active\x18\x03 \x01(\bR\x06active\"\xb3\x02\n" +
+	"\tPaneGroup\x12\"\n" +
+	"\rpane_group_id\x18\x01 \x01(\tR\vpaneGroupId\x12!\n" +
+	"\fworkspace_id\x18\x02 \x01(\tR\vworkspaceId\x127\n" +
+	"\n" +
+	"project_id\x18\x03 \x01(\v2\x18.swm.plugin.v1.ProjectIDR\tprojectId\x12#\n" +
+	"\rworktree_path\x18\x04 \x01(\tR\fworktreePath\x12!\n" +
+	"\fwindow_count\x18\x05 \x01(\x05R\vwindowCount\x12?\n" +
+	"\rlast_activity\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\flastActivity\x12\x1d\n" +
+	"\n" +
+	"story_name\x18\a \x01(\tR\tstoryName\"\xb7\x01\n" +
+	"\x16CurrentContextResponse\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\x1d\n" +
 	"\n" +
 	"story_name\x18\x02 \x01(\tR\tstoryName\x12\"\n" +
 	"\rpane_group_id\x18\x03 \x01(\tR\vpaneGroupId\x127\n" +
 	"\n" +
-	"project_id\x18\x04 \x01(\v2\x18.swm.plugin.v1.ProjectIDR\tprojectId\"\xd6\x01\n" +
+	"project_id\x18\x04 \x01(\v2\x18.swm.plugin.v1.ProjectIDR\tprojectId\"\x82\x03\n" +
 	"\x14OpenWorkspaceRequest\x12\x1d\n" +
 	"\n" +
 	"story_name\x18\x01 \x01(\tR\tstoryName\x12]\n" +
-	"\x0eworktree_paths\x18\x02 \x03(\v26.swm.plugin.v1.OpenWorkspaceRequest.WorktreePathsEntryR\rworktreePaths\x1a@\n" +
+	"\x0eworktree_paths\x18\x02 \x03(\v26.swm.plugin.v1.OpenWorkspaceRequest.WorktreePathsEntryR\rworktreePaths\x122\n" +
+	"\x15restore_snapshot_path\x18\x03 \x01(\tR\x13restoreSnapshotPath\x12>\n" +
+	"\x03env\x18\x04 \x03(\v2,.swm.plugin.v1.OpenWorkspaceRequest.EnvEntryR\x03env\x1a@\n" +
 	"\x12WorktreePathsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\":\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"_\n" +
 	"\x15CloseWorkspaceRequest\x12!\n" +
-	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\"\x97\x01\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12#\n" +
+	"\rsnapshot_path\x18\x02 \x01(\tR\fsnapshotPath\"^\n" +
+	"\x14SaveWorkspaceRequest\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12#\n" +
+	"\rsnapshot_path\x18\x02 \x01(\tR\fsnapshotPath\"f\n" +
+	"\x15SaveWorkspaceResponse\x12#\n" +
+	"\rsnapshot_path\x18\x01 \x01(\tR\fsnapshotPath\x12(\n" +
+	"\x10pane_group_count\x18\x02 \x01(\x05R\x0epaneGroupCount\"\xd8\x01\n" +
+	"\x17RestoreWorkspaceRequest\x12\x1d\n" +
+	"\n" +
+	"story_name\x18\x01 \x01(\tR\tstoryName\x12#\n" +
+	"\rsnapshot_path\x18\x02 \x01(\tR\fsnapshotPath\x12A\n" +
+	"\x03env\x18\x03 \x03(\v2/.swm.plugin.v1.RestoreWorkspaceRequest.EnvEntryR\x03env\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"x\n" +
+	"\x18RestoreWorkspaceResponse\x126\n" +
+	"\tworkspace\x18\x01 \x01(\v2\x18.swm.plugin.v1.WorkspaceR\tworkspace\x12$\n" +
+	"\x0epane_group_ids\x18\x02 \x03(\tR\fpaneGroupIds\"\xac\x03\n" +
 	"\x14OpenPaneGroupRequest\x12!\n" +
 	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x127\n" +
 	"\n" +
 	"project_id\x18\x02 \x01(\v2\x18.swm.plugin.v1.ProjectIDR\tprojectId\x12#\n" +
-	"\rworktree_path\x18\x03 \x01(\tR\fworktreePath\"\xc4\x01\n" +
+	"\rworktree_path\x18\x03 \x01(\tR\fworktreePath\x12>\n" +
+	"\x03env\x18\x04 \x03(\v2,.swm.plugin.v1.OpenPaneGroupRequest.EnvEntryR\x03env\x12\x1b\n" +
+	"\tport_base\x18\x05 \x01(\rR\bportBase\x12D\n" +
+	"\x05ports\x18\x06 \x03(\v2..swm.plugin.v1.OpenPaneGroupRequest.PortsEntryR\x05ports\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a8\n" +
+	"\n" +
+	"PortsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\rR\x05value:\x028\x01\":\n" +
+	"\x15ListPaneGroupsRequest\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\"^\n" +
+	"\x15ClosePaneGroupRequest\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\"\n" +
+	"\rpane_group_id\x18\x02 \x01(\tR\vpaneGroupId\"\xe0\x01\n" +
 	"\x0fSwitchToRequest\x12!\n" +
 	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\"\n" +
 	"\rpane_group_id\x18\x02 \x01(\tR\vpaneGroupId\x129\n" +
 	"\x19close_origin_workspace_id\x18\x03 \x01(\tR\x16closeOriginWorkspaceId\x12/\n" +
-	"\x14close_origin_pane_id\x18\x04 \x01(\tR\x11closeOriginPaneId\"/\n" +
+	"\x14close_origin_pane_id\x18\x04 \x01(\tR\x11closeOriginPaneIdJ\x04\b\x05\x10\x06R\x14current_workspace_id\"/\n" +
 	"\x10SwitchToResponse\x12\x1b\n" +
-	"\texec_argv\x18\x01 \x03(\tR\bexecArgv2\xd6\x04\n" +
+	"\texec_argv\x18\x01 \x03(\tR\bexecArgv\"]\n" +
+	"\x14AttachCommandRequest\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\"\n" +
+	"\rpane_group_id\x18\x02 \x01(\tR\vpaneGroupId\"+\n" +
+	"\x15AttachCommandResponse\x12\x12\n" +
+	"\x04argv\x18\x01 \x03(\tR\x04argv\"\x8d\x02\n" +
+	"\x13StartServiceRequest\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04argv\x18\x03 \x03(\tR\x04argv\x12=\n" +
+	"\x03env\x18\x04 \x03(\v2+.swm.plugin.v1.StartServiceRequest.EnvEntryR\x03env\x12\x19\n" +
+	"\bwork_dir\x18\x05 \x01(\tR\aworkDir\x12\x19\n" +
+	"\blog_path\x18\x06 \x01(\tR\alogPath\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x85\x01\n" +
+	"\aService\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x01 \x01(\tR\tserviceId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x10\n" +
+	"\x03pid\x18\x03 \x01(\x03R\x03pid\x12\x18\n" +
+	"\arunning\x18\x04 \x01(\bR\arunning\x12\x1b\n" +
+	"\texit_code\x18\x05 \x01(\x05R\bexitCode\"8\n" +
+	"\x13ListServicesRequest\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\"V\n" +
+	"\x12StopServiceRequest\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x02 \x01(\tR\tserviceId2\xf7\t\n" +
 	"\aSession\x128\n" +
 	"\x04Info\x12\x14.swm.plugin.v1.Empty\x1a\x1a.swm.plugin.v1.SessionInfo\x12N\n" +
 	"\rOpenWorkspace\x12#.swm.plugin.v1.OpenWorkspaceRequest\x1a\x18.swm.plugin.v1.Workspace\x12L\n" +
 	"\x0eCloseWorkspace\x12$.swm.plugin.v1.CloseWorkspaceRequest\x1a\x14.swm.plugin.v1.Empty\x12B\n" +
 	"\x0eListWorkspaces\x12\x14.swm.plugin.v1.Empty\x1a\x18.swm.plugin.v1.Workspace0\x01\x12N\n" +
-	"\rOpenPaneGroup\x12#.swm.plugin.v1.OpenPaneGroupRequest\x1a\x18.swm.plugin.v1.PaneGroup\x12K\n" +
+	"\rOpenPaneGroup\x12#.swm.plugin.v1.OpenPaneGroupRequest\x1a\x18.swm.plugin.v1.PaneGroup\x12R\n" +
+	"\x0eListPaneGroups\x12$.swm.plugin.v1.ListPaneGroupsRequest\x1a\x18.swm.plugin.v1.PaneGroup0\x01\x12L\n" +
+	"\x0eClosePaneGroup\x12$.swm.plugin.v1.ClosePaneGroupRequest\x1a\x14.swm.plugin.v1.Empty\x12K\n" +
 	"\bSwitchTo\x12\x1e.swm.plugin.v1.SwitchToRequest\x1a\x1f.swm.plugin.v1.SwitchToResponse\x12C\n" +
 	"\x11IsInsideWorkspace\x12\x14.swm.plugin.v1.Empty\x1a\x18.swm.plugin.v1.BoolValue\x12M\n" +
-	"\x0eCurrentContext\x12\x14.swm.plugin.v1.Empty\x1a%.swm.plugin.v1.CurrentContextResponseB6Z4github.com/kalbasit/swm/proto/swm/plugin/v1;pluginv1b\x06proto3"
+	"\x0eCurrentContext\x12\x14.swm.plugin.v1.Empty\x1a%.swm.plugin.v1.CurrentContextResponse\x12Z\n" +
+	"\rSaveWorkspace\x12#.swm.plugin.v1.SaveWorkspaceRequest\x1a$.swm.plugin.v1.SaveWorkspaceResponse\x12c\n" +
+	"\x10RestoreWorkspace\x12&.swm.plugin.v1.RestoreWorkspaceRequest\x1a'.swm.plugin.v1.RestoreWorkspaceResponse\x12Z\n" +
+	"\rAttachCommand\x12#.swm.plugin.v1.AttachCommandRequest\x1a$.swm.plugin.v1.AttachCommandResponse\x12J\n" +
+	"\fStartService\x12\".swm.plugin.v1.StartServiceRequest\x1a\x16.swm.plugin.v1.Service\x12L\n" +
+	"\fListServices\x12\".swm.plugin.v1.ListServicesRequest\x1a\x16.swm.plugin.v1.Service0\x01\x12F\n" +
+	"\vStopService\x12!.swm.plugin.v1.StopServiceRequest\x1a\x14.swm.plugin.v1.EmptyB6Z4github.com/kalbasit/swm/proto/swm/plugin/v1;pluginv1b\x06proto3"
 
 var (
 	file_swm_plugin_v1_session_proto_rawDescOnce sync.Once
@@ -614,50 +1472,91 @@ func file_swm_plugin_v1_session_proto_rawDescGZIP() []byte {
 	return file_swm_plugin_v1_session_proto_rawDescData
 }
 
-var file_swm_plugin_v1_session_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_swm_plugin_v1_session_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_swm_plugin_v1_session_proto_goTypes = []any{
-	(*SessionInfo)(nil),            // 0: swm.plugin.v1.SessionInfo
-	(*Workspace)(nil),              // 1: swm.plugin.v1.Workspace
-	(*PaneGroup)(nil),              // 2: swm.plugin.v1.PaneGroup
-	(*CurrentContextResponse)(nil), // 3: swm.plugin.v1.CurrentContextResponse
-	(*OpenWorkspaceRequest)(nil),   // 4: swm.plugin.v1.OpenWorkspaceRequest
-	(*CloseWorkspaceRequest)(nil),  // 5: swm.plugin.v1.CloseWorkspaceRequest
-	(*OpenPaneGroupRequest)(nil),   // 6: swm.plugin.v1.OpenPaneGroupRequest
-	(*SwitchToRequest)(nil),        // 7: swm.plugin.v1.SwitchToRequest
-	(*SwitchToResponse)(nil),       // 8: swm.plugin.v1.SwitchToResponse
-	nil,                            // 9: swm.plugin.v1.OpenWorkspaceRequest.WorktreePathsEntry
-	(*PluginInfo)(nil),             // 10: swm.plugin.v1.PluginInfo
-	(*ProjectID)(nil),              // 11: swm.plugin.v1.ProjectID
-	(*Empty)(nil),                  // 12: swm.plugin.v1.Empty
-	(*BoolValue)(nil),              // 13: swm.plugin.v1.BoolValue
+	(*SessionInfo)(nil),              // 0: swm.plugin.v1.SessionInfo
+	(*Workspace)(nil),                // 1: swm.plugin.v1.Workspace
+	(*PaneGroup)(nil),                // 2: swm.plugin.v1.PaneGroup
+	(*CurrentContextResponse)(nil),   // 3: swm.plugin.v1.CurrentContextResponse
+	(*OpenWorkspaceRequest)(nil),     // 4: swm.plugin.v1.OpenWorkspaceRequest
+	(*CloseWorkspaceRequest)(nil),    // 5: swm.plugin.v1.CloseWorkspaceRequest
+	(*SaveWorkspaceRequest)(nil),     // 6: swm.plugin.v1.SaveWorkspaceRequest
+	(*SaveWorkspaceResponse)(nil),    // 7: swm.plugin.v1.SaveWorkspaceResponse
+	(*RestoreWorkspaceRequest)(nil),  // 8: swm.plugin.v1.RestoreWorkspaceRequest
+	(*RestoreWorkspaceResponse)(nil), // 9: swm.plugin.v1.RestoreWorkspaceResponse
+	(*OpenPaneGroupRequest)(nil),     // 10: swm.plugin.v1.OpenPaneGroupRequest
+	(*ListPaneGroupsRequest)(nil),    // 11: swm.plugin.v1.ListPaneGroupsRequest
+	(*ClosePaneGroupRequest)(nil),    // 12: swm.plugin.v1.ClosePaneGroupRequest
+	(*SwitchToRequest)(nil),          // 13: swm.plugin.v1.SwitchToRequest
+	(*SwitchToResponse)(nil),         // 14: swm.plugin.v1.SwitchToResponse
+	(*AttachCommandRequest)(nil),     // 15: swm.plugin.v1.AttachCommandRequest
+	(*AttachCommandResponse)(nil),    // 16: swm.plugin.v1.AttachCommandResponse
+	(*StartServiceRequest)(nil),      // 17: swm.plugin.v1.StartServiceRequest
+	(*Service)(nil),                  // 18: swm.plugin.v1.Service
+	(*ListServicesRequest)(nil),      // 19: swm.plugin.v1.ListServicesRequest
+	(*StopServiceRequest)(nil),       // 20: swm.plugin.v1.StopServiceRequest
+	nil,                              // 21: swm.plugin.v1.OpenWorkspaceRequest.WorktreePathsEntry
+	nil,                              // 22: swm.plugin.v1.OpenWorkspaceRequest.EnvEntry
+	nil,                              // 23: swm.plugin.v1.RestoreWorkspaceRequest.EnvEntry
+	nil,                              // 24: swm.plugin.v1.OpenPaneGroupRequest.EnvEntry
+	nil,                              // 25: swm.plugin.v1.OpenPaneGroupRequest.PortsEntry
+	nil,                              // 26: swm.plugin.v1.StartServiceRequest.EnvEntry
+	(*PluginInfo)(nil),               // 27: swm.plugin.v1.PluginInfo
+	(*ProjectID)(nil),                // 28: swm.plugin.v1.ProjectID
+	(*timestamppb.Timestamp)(nil),    // 29: google.protobuf.Timestamp
+	(*Empty)(nil),                    // 30: swm.plugin.v1.Empty
+	(*BoolValue)(nil),                // 31: swm.plugin.v1.BoolValue
 }
 var file_swm_plugin_v1_session_proto_depIdxs = []int32{
-	10, // 0: swm.plugin.v1.SessionInfo.plugin_info:type_name -> swm.plugin.v1.PluginInfo
-	11, // 1: swm.plugin.v1.PaneGroup.project_id:type_name -> swm.plugin.v1.ProjectID
-	11, // 2: swm.plugin.v1.CurrentContextResponse.project_id:type_name -> swm.plugin.v1.ProjectID
-	9,  // 3: swm.plugin.v1.OpenWorkspaceRequest.worktree_paths:type_name -> swm.plugin.v1.OpenWorkspaceRequest.WorktreePathsEntry
-	11, // 4: swm.plugin.v1.OpenPaneGroupRequest.project_id:type_name -> swm.plugin.v1.ProjectID
-	12, // 5: swm.plugin.v1.Session.Info:input_type -> swm.plugin.v1.Empty
-	4,  // 6: swm.plugin.v1.Session.OpenWorkspace:input_type -> swm.plugin.v1.OpenWorkspaceRequest
-	5,  // 7: swm.plugin.v1.Session.CloseWorkspace:input_type -> swm.plugin.v1.CloseWorkspaceRequest
-	12, // 8: swm.plugin.v1.Session.ListWorkspaces:input_type -> swm.plugin.v1.Empty
-	6,  // 9: swm.plugin.v1.Session.OpenPaneGroup:input_type -> swm.plugin.v1.OpenPaneGroupRequest
-	7,  // 10: swm.plugin.v1.Session.SwitchTo:input_type -> swm.plugin.v1.SwitchToRequest
-	12, // 11: swm.plugin.v1.Session.IsInsideWorkspace:input_type -> swm.plugin.v1.Empty
-	12, // 12: swm.plugin.v1.Session.CurrentContext:input_type -> swm.plugin.v1.Empty
-	0,  // 13: swm.plugin.v1.Session.Info:output_type -> swm.plugin.v1.SessionInfo
-	1,  // 14: swm.plugin.v1.Session.OpenWorkspace:output_type -> swm.plugin.v1.Workspace
-	12, // 15: swm.plugin.v1.Session.CloseWorkspace:output_type -> swm.plugin.v1.Empty
-	1,  // 16: swm.plugin.v1.Session.ListWorkspaces:output_type -> swm.plugin.v1.Workspace
-	2,  // 17: swm.plugin.v1.Session.OpenPaneGroup:output_type -> swm.plugin.v1.PaneGroup
-	8,  // 18: swm.plugin.v1.Session.SwitchTo:output_type -> swm.plugin.v1.SwitchToResponse
-	13, // 19: swm.plugin.v1.Session.IsInsideWorkspace:output_type -> swm.plugin.v1.BoolValue
-	3,  // 20: swm.plugin.v1.Session.CurrentContext:output_type -> swm.plugin.v1.CurrentContextResponse
-	13, // [13:21] is the sub-list for method output_type
-	5,  // [5:13] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	27, // 0: swm.plugin.v1.SessionInfo.plugin_info:type_name -> swm.plugin.v1.PluginInfo
+	28, // 1: swm.plugin.v1.PaneGroup.project_id:type_name -> swm.plugin.v1.ProjectID
+	29, // 2: swm.plugin.v1.PaneGroup.last_activity:type_name -> google.protobuf.Timestamp
+	28, // 3: swm.plugin.v1.CurrentContextResponse.project_id:type_name -> swm.plugin.v1.ProjectID
+	21, // 4: swm.plugin.v1.OpenWorkspaceRequest.worktree_paths:type_name -> swm.plugin.v1.OpenWorkspaceRequest.WorktreePathsEntry
+	22, // 5: swm.plugin.v1.OpenWorkspaceRequest.env:type_name -> swm.plugin.v1.OpenWorkspaceRequest.EnvEntry
+	23, // 6: swm.plugin.v1.RestoreWorkspaceRequest.env:type_name -> swm.plugin.v1.RestoreWorkspaceRequest.EnvEntry
+	1,  // 7: swm.plugin.v1.RestoreWorkspaceResponse.workspace:type_name -> swm.plugin.v1.Workspace
+	28, // 8: swm.plugin.v1.OpenPaneGroupRequest.project_id:type_name -> swm.plugin.v1.ProjectID
+	24, // 9: swm.plugin.v1.OpenPaneGroupRequest.env:type_name -> swm.plugin.v1.OpenPaneGroupRequest.EnvEntry
+	25, // 10: swm.plugin.v1.OpenPaneGroupRequest.ports:type_name -> swm.plugin.v1.OpenPaneGroupRequest.PortsEntry
+	26, // 11: swm.plugin.v1.StartServiceRequest.env:type_name -> swm.plugin.v1.StartServiceRequest.EnvEntry
+	30, // 12: swm.plugin.v1.Session.Info:input_type -> swm.plugin.v1.Empty
+	4,  // 13: swm.plugin.v1.Session.OpenWorkspace:input_type -> swm.plugin.v1.OpenWorkspaceRequest
+	5,  // 14: swm.plugin.v1.Session.CloseWorkspace:input_type -> swm.plugin.v1.CloseWorkspaceRequest
+	30, // 15: swm.plugin.v1.Session.ListWorkspaces:input_type -> swm.plugin.v1.Empty
+	10, // 16: swm.plugin.v1.Session.OpenPaneGroup:input_type -> swm.plugin.v1.OpenPaneGroupRequest
+	11, // 17: swm.plugin.v1.Session.ListPaneGroups:input_type -> swm.plugin.v1.ListPaneGroupsRequest
+	12, // 18: swm.plugin.v1.Session.ClosePaneGroup:input_type -> swm.plugin.v1.ClosePaneGroupRequest
+	13, // 19: swm.plugin.v1.Session.SwitchTo:input_type -> swm.plugin.v1.SwitchToRequest
+	30, // 20: swm.plugin.v1.Session.IsInsideWorkspace:input_type -> swm.plugin.v1.Empty
+	30, // 21: swm.plugin.v1.Session.CurrentContext:input_type -> swm.plugin.v1.Empty
+	6,  // 22: swm.plugin.v1.Session.SaveWorkspace:input_type -> swm.plugin.v1.SaveWorkspaceRequest
+	8,  // 23: swm.plugin.v1.Session.RestoreWorkspace:input_type -> swm.plugin.v1.RestoreWorkspaceRequest
+	15, // 24: swm.plugin.v1.Session.AttachCommand:input_type -> swm.plugin.v1.AttachCommandRequest
+	17, // 25: swm.plugin.v1.Session.StartService:input_type -> swm.plugin.v1.StartServiceRequest
+	19, // 26: swm.plugin.v1.Session.ListServices:input_type -> swm.plugin.v1.ListServicesRequest
+	20, // 27: swm.plugin.v1.Session.StopService:input_type -> swm.plugin.v1.StopServiceRequest
+	0,  // 28: swm.plugin.v1.Session.Info:output_type -> swm.plugin.v1.SessionInfo
+	1,  // 29: swm.plugin.v1.Session.OpenWorkspace:output_type -> swm.plugin.v1.Workspace
+	30, // 30: swm.plugin.v1.Session.CloseWorkspace:output_type -> swm.plugin.v1.Empty
+	1,  // 31: swm.plugin.v1.Session.ListWorkspaces:output_type -> swm.plugin.v1.Workspace
+	2,  // 32: swm.plugin.v1.Session.OpenPaneGroup:output_type -> swm.plugin.v1.PaneGroup
+	2,  // 33: swm.plugin.v1.Session.ListPaneGroups:output_type -> swm.plugin.v1.PaneGroup
+	30, // 34: swm.plugin.v1.Session.ClosePaneGroup:output_type -> swm.plugin.v1.Empty
+	14, // 35: swm.plugin.v1.Session.SwitchTo:output_type -> swm.plugin.v1.SwitchToResponse
+	31, // 36: swm.plugin.v1.Session.IsInsideWorkspace:output_type -> swm.plugin.v1.BoolValue
+	3,  // 37: swm.plugin.v1.Session.CurrentContext:output_type -> swm.plugin.v1.CurrentContextResponse
+	7,  // 38: swm.plugin.v1.Session.SaveWorkspace:output_type -> swm.plugin.v1.SaveWorkspaceResponse
+	9,  // 39: swm.plugin.v1.Session.RestoreWorkspace:output_type -> swm.plugin.v1.RestoreWorkspaceResponse
+	16, // 40: swm.plugin.v1.Session.AttachCommand:output_type -> swm.plugin.v1.AttachCommandResponse
+	18, // 41: swm.plugin.v1.Session.StartService:output_type -> swm.plugin.v1.Service
+	18, // 42: swm.plugin.v1.Session.ListServices:output_type -> swm.plugin.v1.Service
+	30, // 43: swm.plugin.v1.Session.StopService:output_type -> swm.plugin.v1.Empty
+	28, // [28:44] is the sub-list for method output_type
+	12, // [12:28] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_swm_plugin_v1_session_proto_init() }
@@ -672,7 +1571,7 @@ func file_swm_plugin_v1_session_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_swm_plugin_v1_session_proto_rawDesc), len(file_swm_plugin_v1_session_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
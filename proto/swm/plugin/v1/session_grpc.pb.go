@@ -24,9 +24,17 @@ const (
 	Session_CloseWorkspace_FullMethodName    = "/swm.plugin.v1.Session/CloseWorkspace"
 	Session_ListWorkspaces_FullMethodName    = "/swm.plugin.v1.Session/ListWorkspaces"
 	Session_OpenPaneGroup_FullMethodName     = "/swm.plugin.v1.Session/OpenPaneGroup"
+	Session_ListPaneGroups_FullMethodName    = "/swm.plugin.v1.Session/ListPaneGroups"
+	Session_ClosePaneGroup_FullMethodName    = "/swm.plugin.v1.Session/ClosePaneGroup"
 	Session_SwitchTo_FullMethodName          = "/swm.plugin.v1.Session/SwitchTo"
 	Session_IsInsideWorkspace_FullMethodName = "/swm.plugin.v1.Session/IsInsideWorkspace"
 	Session_CurrentContext_FullMethodName    = "/swm.plugin.v1.Session/CurrentContext"
+	Session_SaveWorkspace_FullMethodName     = "/swm.plugin.v1.Session/SaveWorkspace"
+	Session_RestoreWorkspace_FullMethodName  = "/swm.plugin.v1.Session/RestoreWorkspace"
+	Session_AttachCommand_FullMethodName     = "/swm.plugin.v1.Session/AttachCommand"
+	Session_StartService_FullMethodName      = "/swm.plugin.v1.Session/StartService"
+	Session_ListServices_FullMethodName      = "/swm.plugin.v1.Session/ListServices"
+	Session_StopService_FullMethodName       = "/swm.plugin.v1.Session/StopService"
 )
 
 // SessionClient is the client API for Session service.
@@ -40,9 +48,22 @@ type SessionClient interface {
 	CloseWorkspace(ctx context.Context, in *CloseWorkspaceRequest, opts ...grpc.CallOption) (*Empty, error)
 	ListWorkspaces(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Workspace], error)
 	OpenPaneGroup(ctx context.Context, in *OpenPaneGroupRequest, opts ...grpc.CallOption) (*PaneGroup, error)
+	ListPaneGroups(ctx context.Context, in *ListPaneGroupsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PaneGroup], error)
+	ClosePaneGroup(ctx context.Context, in *ClosePaneGroupRequest, opts ...grpc.CallOption) (*Empty, error)
 	SwitchTo(ctx context.Context, in *SwitchToRequest, opts ...grpc.CallOption) (*SwitchToResponse, error)
 	IsInsideWorkspace(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BoolValue, error)
 	CurrentContext(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CurrentContextResponse, error)
+	SaveWorkspace(ctx context.Context, in *SaveWorkspaceRequest, opts ...grpc.CallOption) (*SaveWorkspaceResponse, error)
+	RestoreWorkspace(ctx context.Context, in *RestoreWorkspaceRequest, opts ...grpc.CallOption) (*RestoreWorkspaceResponse, error)
+	// AttachCommand returns the command to attach to a pane group later,
+	// without switching to it (used by `swm workspace open --no-attach`).
+	AttachCommand(ctx context.Context, in *AttachCommandRequest, opts ...grpc.CallOption) (*AttachCommandResponse, error)
+	// StartService, ListServices and StopService manage per-story service
+	// processes (used by `swm services`). ListServices streams them in start
+	// order, including services that exited.
+	StartService(ctx context.Context, in *StartServiceRequest, opts ...grpc.CallOption) (*Service, error)
+	ListServices(ctx context.Context, in *ListServicesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Service], error)
+	StopService(ctx context.Context, in *StopServiceRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type sessionClient struct {
@@ -112,6 +133,35 @@ func (c *sessionClient) OpenPaneGroup(ctx context.Context, in *OpenPaneGroupRequ
 	return out, nil
 }
 
+func (c *sessionClient) ListPaneGroups(ctx context.Context, in *ListPaneGroupsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PaneGroup], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Session_ServiceDesc.Streams[1], Session_ListPaneGroups_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListPaneGroupsRequest, PaneGroup]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Session_ListPaneGroupsClient = grpc.ServerStreamingClient[PaneGroup]
+
+func (c *sessionClient) ClosePaneGroup(ctx context.Context, in *ClosePaneGroupRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Session_ClosePaneGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *sessionClient) SwitchTo(ctx context.Context, in *SwitchToRequest, opts ...grpc.CallOption) (*SwitchToResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SwitchToResponse)
@@ -142,6 +192,75 @@ func (c *sessionClient) CurrentContext(ctx context.Context, in *Empty, opts ...g
 	return out, nil
 }
 
+func (c *sessionClient) SaveWorkspace(ctx context.Context, in *SaveWorkspaceRequest, opts ...grpc.CallOption) (*SaveWorkspaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SaveWorkspaceResponse)
+	err := c.cc.Invoke(ctx, Session_SaveWorkspace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionClient) RestoreWorkspace(ctx context.Context, in *RestoreWorkspaceRequest, opts ...grpc.CallOption) (*RestoreWorkspaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreWorkspaceResponse)
+	err := c.cc.Invoke(ctx, Session_RestoreWorkspace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionClient) AttachCommand(ctx context.Context, in *AttachCommandRequest, opts ...grpc.CallOption) (*AttachCommandResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AttachCommandResponse)
+	err := c.cc.Invoke(ctx, Session_AttachCommand_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionClient) StartService(ctx context.Context, in *StartServiceRequest, opts ...grpc.CallOption) (*Service, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Service)
+	err := c.cc.Invoke(ctx, Session_StartService_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionClient) ListServices(ctx context.Context, in *ListServicesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Service], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Session_ServiceDesc.Streams[2], Session_ListServices_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListServicesRequest, Service]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Session_ListServicesClient = grpc.ServerStreamingClient[Service]
+
+func (c *sessionClient) StopService(ctx context.Context, in *StopServiceRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Session_StopService_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SessionServer is the server API for Session service.
 // All implementations should embed UnimplementedSessionServer
 // for forward compatibility.
@@ -153,9 +272,22 @@ type SessionServer interface {
 	CloseWorkspace(context.Context, *CloseWorkspaceRequest) (*Empty, error)
 	ListWorkspaces(*Empty, grpc.ServerStreamingServer[Workspace]) error
 	OpenPaneGroup(context.Context, *OpenPaneGroupRequest) (*PaneGroup, error)
+	ListPaneGroups(*ListPaneGroupsRequest, grpc.ServerStreamingServer[PaneGroup]) error
+	ClosePaneGroup(context.Context, *ClosePaneGroupRequest) (*Empty, error)
 	SwitchTo(context.Context, *SwitchToRequest) (*SwitchToResponse, error)
 	IsInsideWorkspace(context.Context, *Empty) (*BoolValue, error)
 	CurrentContext(context.Context, *Empty) (*CurrentContextResponse, error)
+	SaveWorkspace(context.Context, *SaveWorkspaceRequest) (*SaveWorkspaceResponse, error)
+	RestoreWorkspace(context.Context, *RestoreWorkspaceRequest) (*RestoreWorkspaceResponse, error)
+	// AttachCommand returns the command to attach to a pane group later,
+	// without switching to it (used by `swm workspace open --no-attach`).
+	AttachCommand(context.Context, *AttachCommandRequest) (*AttachCommandResponse, error)
+	// StartService, ListServices and StopService manage per-story service
+	// processes (used by `swm services`). ListServices streams them in start
+	// order, including services that exited.
+	StartService(context.Context, *StartServiceRequest) (*Service, error)
+	ListServices(*ListServicesRequest, grpc.ServerStreamingServer[Service]) error
+	StopService(context.Context, *StopServiceRequest) (*Empty, error)
 }
 
 // UnimplementedSessionServer should be embedded to have
@@ -180,6 +312,12 @@ func (UnimplementedSessionServer) ListWorkspaces(*Empty, grpc.ServerStreamingSer
 func (UnimplementedSessionServer) OpenPaneGroup(context.Context, *OpenPaneGroupRequest) (*PaneGroup, error) {
 	return nil, status.Error(codes.Unimplemented, "method OpenPaneGroup not implemented")
 }
+func (UnimplementedSessionServer) ListPaneGroups(*ListPaneGroupsRequest, grpc.ServerStreamingServer[PaneGroup]) error {
+	return status.Error(codes.Unimplemented, "method ListPaneGroups not implemented")
+}
+func (UnimplementedSessionServer) ClosePaneGroup(context.Context, *ClosePaneGroupRequest) (*Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method ClosePaneGroup not implemented")
+}
 func (UnimplementedSessionServer) SwitchTo(context.Context, *SwitchToRequest) (*SwitchToResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SwitchTo not implemented")
 }
@@ -189,6 +327,24 @@ func (UnimplementedSessionServer) IsInsideWorkspace(context.Context, *Empty) (*B
 func (UnimplementedSessionServer) CurrentContext(context.Context, *Empty) (*CurrentContextResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CurrentContext not implemented")
 }
+func (UnimplementedSessionServer) SaveWorkspace(context.Context, *SaveWorkspaceRequest) (*SaveWorkspaceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SaveWorkspace not implemented")
+}
+func (UnimplementedSessionServer) RestoreWorkspace(context.Context, *RestoreWorkspaceRequest) (*RestoreWorkspaceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RestoreWorkspace not implemented")
+}
+func (UnimplementedSessionServer) AttachCommand(context.Context, *AttachCommandRequest) (*AttachCommandResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AttachCommand not implemented")
+}
+func (UnimplementedSessionServer) StartService(context.Context, *StartServiceRequest) (*Service, error) {
+	return nil, status.Error(codes.Unimplemented, "method StartService not implemented")
+}
+func (UnimplementedSessionServer) ListServices(*ListServicesRequest, grpc.ServerStreamingServer[Service]) error {
+	return status.Error(codes.Unimplemented, "method ListServices not implemented")
+}
+func (UnimplementedSessionServer) StopService(context.Context, *StopServiceRequest) (*Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method StopService not implemented")
+}
 func (UnimplementedSessionServer) testEmbeddedByValue() {}
 
 // UnsafeSessionServer may be embedded to opt out of forward compatibility for this service.
@@ -292,6 +448,35 @@ func _Session_OpenPaneGroup_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Session_ListPaneGroups_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPaneGroupsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SessionServer).ListPaneGroups(m, &grpc.GenericServerStream[ListPaneGroupsRequest, PaneGroup]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Session_ListPaneGroupsServer = grpc.ServerStreamingServer[PaneGroup]
+
+func _Session_ClosePaneGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClosePaneGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).ClosePaneGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Session_ClosePaneGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).ClosePaneGroup(ctx, req.(*ClosePaneGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Session_SwitchTo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SwitchToRequest)
 	if err := dec(in); err != nil {
@@ -346,6 +531,107 @@ func _Session_CurrentContext_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Session_SaveWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveWorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).SaveWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Session_SaveWorkspace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).SaveWorkspace(ctx, req.(*SaveWorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Session_RestoreWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreWorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).RestoreWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Session_RestoreWorkspace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).RestoreWorkspace(ctx, req.(*RestoreWorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Session_AttachCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).AttachCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Session_AttachCommand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).AttachCommand(ctx, req.(*AttachCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Session_StartService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartServiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).StartService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Session_StartService_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).StartService(ctx, req.(*StartServiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Session_ListServices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListServicesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SessionServer).ListServices(m, &grpc.GenericServerStream[ListServicesRequest, Service]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Session_ListServicesServer = grpc.ServerStreamingServer[Service]
+
+func _Session_StopService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopServiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).StopService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Session_StopService_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).StopService(ctx, req.(*StopServiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Session_ServiceDesc is the grpc.ServiceDesc for Session service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -369,6 +655,10 @@ var Session_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "OpenPaneGroup",
 			Handler:    _Session_OpenPaneGroup_Handler,
 		},
+		{
+			MethodName: "ClosePaneGroup",
+			Handler:    _Session_ClosePaneGroup_Handler,
+		},
 		{
 			MethodName: "SwitchTo",
 			Handler:    _Session_SwitchTo_Handler,
@@ -381,6 +671,26 @@ var Session_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CurrentContext",
 			Handler:    _Session_CurrentContext_Handler,
 		},
+		{
+			MethodName: "SaveWorkspace",
+			Handler:    _Session_SaveWorkspace_Handler,
+		},
+		{
+			MethodName: "RestoreWorkspace",
+			Handler:    _Session_RestoreWorkspace_Handler,
+		},
+		{
+			MethodName: "AttachCommand",
+			Handler:    _Session_AttachCommand_Handler,
+		},
+		{
+			MethodName: "StartService",
+			Handler:    _Session_StartService_Handler,
+		},
+		{
+			MethodName: "StopService",
+			Handler:    _Session_StopService_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -388,6 +698,16 @@ var Session_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Session_ListWorkspaces_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ListPaneGroups",
+			Handler:       _Session_ListPaneGroups_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListServices",
+			Handler:       _Session_ListServices_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "swm/plugin/v1/session.proto",
 }
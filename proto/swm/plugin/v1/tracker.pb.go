@@ -0,0 +1,452 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: swm/plugin/v1/tracker.proto
+
+package pluginv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TrackerInfo is returned by Tracker.Info.
+type TrackerInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PluginInfo    *PluginInfo            `protobuf:"bytes,1,opt,name=plugin_info,json=pluginInfo,proto3" json:"plugin_info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackerInfo) Reset() {
+	*x = TrackerInfo{}
+	mi := &file_swm_plugin_v1_tracker_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackerInfo) ProtoMessage() {}
+
+func (x *TrackerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_tracker_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackerInfo.ProtoReflect.Descriptor instead.
+func (*TrackerInfo) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_tracker_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TrackerInfo) GetPluginInfo() *PluginInfo {
+	if x != nil {
+		return x.PluginInfo
+	}
+	return nil
+}
+
+// Issue describes a ticket in an issue tracker.
+type Issue struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key is the tracker's human-readable identifier (e.g. "PROJ-123").
+	Key         string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Title       string `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// status is the tracker's workflow status name (e.g. "In Progress").
+	Status string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Url    string `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`
+	// type is the issue type name (e.g. "Bug", "Story").
+	Type          string `protobuf:"bytes,6,opt,name=type,proto3" json:"type,omitempty"`
+	Assignee      string `protobuf:"bytes,7,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Issue) Reset() {
+	*x = Issue{}
+	mi := &file_swm_plugin_v1_tracker_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Issue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Issue) ProtoMessage() {}
+
+func (x *Issue) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_tracker_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Issue.ProtoReflect.Descriptor instead.
+func (*Issue) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_tracker_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Issue) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Issue) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Issue) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Issue) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Issue) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Issue) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Issue) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+// GetIssueRequest asks the plugin to fetch a single issue.
+type GetIssueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIssueRequest) Reset() {
+	*x = GetIssueRequest{}
+	mi := &file_swm_plugin_v1_tracker_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIssueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIssueRequest) ProtoMessage() {}
+
+func (x *GetIssueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_tracker_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIssueRequest.ProtoReflect.Descriptor instead.
+func (*GetIssueRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_tracker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetIssueRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+// ListMyIssuesRequest asks for the open issues assigned to the authenticated user.
+type ListMyIssuesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMyIssuesRequest) Reset() {
+	*x = ListMyIssuesRequest{}
+	mi := &file_swm_plugin_v1_tracker_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMyIssuesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMyIssuesRequest) ProtoMessage() {}
+
+func (x *ListMyIssuesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_tracker_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMyIssuesRequest.ProtoReflect.Descriptor instead.
+func (*ListMyIssuesRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_tracker_proto_rawDescGZIP(), []int{3}
+}
+
+// TransitionIssueRequest moves an issue to another workflow status.
+type TransitionIssueRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// status is the target status or transition name, matched case-insensitively.
+	Status        string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitionIssueRequest) Reset() {
+	*x = TransitionIssueRequest{}
+	mi := &file_swm_plugin_v1_tracker_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitionIssueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitionIssueRequest) ProtoMessage() {}
+
+func (x *TransitionIssueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_tracker_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitionIssueRequest.ProtoReflect.Descriptor instead.
+func (*TransitionIssueRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_tracker_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TransitionIssueRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *TransitionIssueRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// AddCommentRequest adds a comment to an issue.
+type AddCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Body          string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddCommentRequest) Reset() {
+	*x = AddCommentRequest{}
+	mi := &file_swm_plugin_v1_tracker_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCommentRequest) ProtoMessage() {}
+
+func (x *AddCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_tracker_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCommentRequest.ProtoReflect.Descriptor instead.
+func (*AddCommentRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_tracker_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AddCommentRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *AddCommentRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+var File_swm_plugin_v1_tracker_proto protoreflect.FileDescriptor
+
+const file_swm_plugin_v1_tracker_proto_rawDesc = "" +
+	"\n" +
+	"\x1bswm/plugin/v1/tracker.proto\x12\rswm.plugin.v1\x1a\x1aswm/plugin/v1/common.proto\"I\n" +
+	"\vTrackerInfo\x12:\n" +
+	"\vplugin_info\x18\x01 \x01(\v2\x19.swm.plugin.v1.PluginInfoR\n" +
+	"pluginInfo\"\xab\x01\n" +
+	"\x05Issue\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x10\n" +
+	"\x03url\x18\x05 \x01(\tR\x03url\x12\x12\n" +
+	"\x04type\x18\x06 \x01(\tR\x04type\x12\x1a\n" +
+	"\bassignee\x18\a \x01(\tR\bassignee\"#\n" +
+	"\x0fGetIssueRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"\x15\n" +
+	"\x13ListMyIssuesRequest\"B\n" +
+	"\x16TransitionIssueRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"9\n" +
+	"\x11AddCommentRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\tR\x04body2\xe7\x02\n" +
+	"\aTracker\x128\n" +
+	"\x04Info\x12\x14.swm.plugin.v1.Empty\x1a\x1a.swm.plugin.v1.TrackerInfo\x12@\n" +
+	"\bGetIssue\x12\x1e.swm.plugin.v1.GetIssueRequest\x1a\x14.swm.plugin.v1.Issue\x12J\n" +
+	"\fListMyIssues\x12\".swm.plugin.v1.ListMyIssuesRequest\x1a\x14.swm.plugin.v1.Issue0\x01\x12N\n" +
+	"\x0fTransitionIssue\x12%.swm.plugin.v1.TransitionIssueRequest\x1a\x14.swm.plugin.v1.Issue\x12D\n" +
+	"\n" +
+	"AddComment\x12 .swm.plugin.v1.AddCommentRequest\x1a\x14.swm.plugin.v1.EmptyB6Z4github.com/kalbasit/swm/proto/swm/plugin/v1;pluginv1b\x06proto3"
+
+var (
+	file_swm_plugin_v1_tracker_proto_rawDescOnce sync.Once
+	file_swm_plugin_v1_tracker_proto_rawDescData []byte
+)
+
+func file_swm_plugin_v1_tracker_proto_rawDescGZIP() []byte {
+	file_swm_plugin_v1_tracker_proto_rawDescOnce.Do(func() {
+		file_swm_plugin_v1_tracker_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_swm_plugin_v1_tracker_proto_rawDesc), len(file_swm_plugin_v1_tracker_proto_rawDesc)))
+	})
+	return file_swm_plugin_v1_tracker_proto_rawDescData
+}
+
+var file_swm_plugin_v1_tracker_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_swm_plugin_v1_tracker_proto_goTypes = []any{
+	(*TrackerInfo)(nil),            // 0: swm.plugin.v1.TrackerInfo
+	(*Issue)(nil),                  // 1: swm.plugin.v1.Issue
+	(*GetIssueRequest)(nil),        // 2: swm.plugin.v1.GetIssueRequest
+	(*ListMyIssuesRequest)(nil),    // 3: swm.plugin.v1.ListMyIssuesRequest
+	(*TransitionIssueRequest)(nil), // 4: swm.plugin.v1.TransitionIssueRequest
+	(*AddCommentRequest)(nil),      // 5: swm.plugin.v1.AddCommentRequest
+	(*PluginInfo)(nil),             // 6: swm.plugin.v1.PluginInfo
+	(*Empty)(nil),                  // 7: swm.plugin.v1.Empty
+}
+var file_swm_plugin_v1_tracker_proto_depIdxs = []int32{
+	6, // 0: swm.plugin.v1.TrackerInfo.plugin_info:type_name -> swm.plugin.v1.PluginInfo
+	7, // 1: swm.plugin.v1.Tracker.Info:input_type -> swm.plugin.v1.Empty
+	2, // 2: swm.plugin.v1.Tracker.GetIssue:input_type -> swm.plugin.v1.GetIssueRequest
+	3, // 3: swm.plugin.v1.Tracker.ListMyIssues:input_type -> swm.plugin.v1.ListMyIssuesRequest
+	4, // 4: swm.plugin.v1.Tracker.TransitionIssue:input_type -> swm.plugin.v1.TransitionIssueRequest
+	5, // 5: swm.plugin.v1.Tracker.AddComment:input_type -> swm.plugin.v1.AddCommentRequest
+	0, // 6: swm.plugin.v1.Tracker.Info:output_type -> swm.plugin.v1.TrackerInfo
+	1, // 7: swm.plugin.v1.Tracker.GetIssue:output_type -> swm.plugin.v1.Issue
+	1, // 8: swm.plugin.v1.Tracker.ListMyIssues:output_type -> swm.plugin.v1.Issue
+	1, // 9: swm.plugin.v1.Tracker.TransitionIssue:output_type -> swm.plugin.v1.Issue
+	7, // 10: swm.plugin.v1.Tracker.AddComment:output_type -> swm.plugin.v1.Empty
+	6, // [6:11] is the sub-list for method output_type
+	1, // [1:6] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_swm_plugin_v1_tracker_proto_init() }
+func file_swm_plugin_v1_tracker_proto_init() {
+	if File_swm_plugin_v1_tracker_proto != nil {
+		return
+	}
+	file_swm_plugin_v1_common_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_swm_plugin_v1_tracker_proto_rawDesc), len(file_swm_plugin_v1_tracker_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_swm_plugin_v1_tracker_proto_goTypes,
+		DependencyIndexes: file_swm_plugin_v1_tracker_proto_depIdxs,
+		MessageInfos:      file_swm_plugin_v1_tracker_proto_msgTypes,
+	}.Build()
+	File_swm_plugin_v1_tracker_proto = out.File
+	file_swm_plugin_v1_tracker_proto_goTypes = nil
+	file_swm_plugin_v1_tracker_proto_depIdxs = nil
+}
@@ -28,8 +28,12 @@ type VCSInfo struct {
 	// project_markers are filenames whose presence marks a directory as a project
 	// root (e.g. [".git"] for git, [".jj"] for jujutsu).
 	ProjectMarkers []string `protobuf:"bytes,2,rep,name=project_markers,json=projectMarkers,proto3" json:"project_markers,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// url_schemes are the remote URL schemes routed to this plugin when several
+	// vcs plugins are configured (e.g. ["hg", "hg+ssh"]). A scheme "x+y" also
+	// matches a plugin claiming "x". Unclaimed URLs go to the default plugin.
+	UrlSchemes    []string `protobuf:"bytes,3,rep,name=url_schemes,json=urlSchemes,proto3" json:"url_schemes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *VCSInfo) Reset() {
@@ -76,6 +80,13 @@ func (x *VCSInfo) GetProjectMarkers() []string {
 	return nil
 }
 
+func (x *VCSInfo) GetUrlSchemes() []string {
+	if x != nil {
+		return x.UrlSchemes
+	}
+	return nil
+}
+
 // CloneRequest asks the plugin to clone a remote URL.
 type CloneRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
@@ -260,12 +271,15 @@ func (x *ParseRemoteURLRequest) GetUrl() string {
 
 // CreateWorktreeRequest asks the plugin to create a per-story worktree.
 type CreateWorktreeRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProjectId     *ProjectID             `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
-	StoryName     string                 `protobuf:"bytes,2,opt,name=story_name,json=storyName,proto3" json:"story_name,omitempty"`
-	BranchName    string                 `protobuf:"bytes,3,opt,name=branch_name,json=branchName,proto3" json:"branch_name,omitempty"`
-	RepoPath      string                 `protobuf:"bytes,4,opt,name=repo_path,json=repoPath,proto3" json:"repo_path,omitempty"`
-	WorktreePath  string                 `protobuf:"bytes,5,opt,name=worktree_path,json=worktreePath,proto3" json:"worktree_path,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId    *ProjectID             `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	StoryName    string                 `protobuf:"bytes,2,opt,name=story_name,json=storyName,proto3" json:"story_name,omitempty"`
+	BranchName   string                 `protobuf:"bytes,3,opt,name=branch_name,json=branchName,proto3" json:"branch_name,omitempty"`
+	RepoPath     string                 `protobuf:"bytes,4,opt,name=repo_path,json=repoPath,proto3" json:"repo_path,omitempty"`
+	WorktreePath string                 `protobuf:"bytes,5,opt,name=worktree_path,json=worktreePath,proto3" json:"worktree_path,omitempty"`
+	// When set, the plugin fetches this ref from the project's origin into
+	// branch_name before creating the worktree (e.g. a forge's pull request ref).
+	FetchRef      string `protobuf:"bytes,6,opt,name=fetch_ref,json=fetchRef,proto3" json:"fetch_ref,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -335,6 +349,13 @@ func (x *CreateWorktreeRequest) GetWorktreePath() string {
 	return ""
 }
 
+func (x *CreateWorktreeRequest) GetFetchRef() string {
+	if x != nil {
+		return x.FetchRef
+	}
+	return ""
+}
+
 // RemoveWorktreeRequest asks the plugin to remove a story's worktree.
 type RemoveWorktreeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -555,15 +576,277 @@ func (x *Branch) GetIsCurrent() bool {
 	return false
 }
 
+// WorktreeStatusRequest asks for the state of a checked-out worktree.
+type WorktreeStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorktreePath  string                 `protobuf:"bytes,1,opt,name=worktree_path,json=worktreePath,proto3" json:"worktree_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorktreeStatusRequest) Reset() {
+	*x = WorktreeStatusRequest{}
+	mi := &file_swm_plugin_v1_vcs_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorktreeStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorktreeStatusRequest) ProtoMessage() {}
+
+func (x *WorktreeStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_vcs_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorktreeStatusRequest.ProtoReflect.Descriptor instead.
+func (*WorktreeStatusRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_vcs_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WorktreeStatusRequest) GetWorktreePath() string {
+	if x != nil {
+		return x.WorktreePath
+	}
+	return ""
+}
+
+// WorktreeStatus describes the checked-out branch of a worktree and whether
+// it has uncommitted changes. ahead/behind are relative to the upstream branch
+// and zero when there is none.
+type WorktreeStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Branch        string                 `protobuf:"bytes,1,opt,name=branch,proto3" json:"branch,omitempty"`
+	Dirty         bool                   `protobuf:"varint,2,opt,name=dirty,proto3" json:"dirty,omitempty"`
+	Ahead         int32                  `protobuf:"varint,3,opt,name=ahead,proto3" json:"ahead,omitempty"`
+	Behind        int32                  `protobuf:"varint,4,opt,name=behind,proto3" json:"behind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorktreeStatus) Reset() {
+	*x = WorktreeStatus{}
+	mi := &file_swm_plugin_v1_vcs_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorktreeStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorktreeStatus) ProtoMessage() {}
+
+func (x *WorktreeStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_vcs_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorktreeStatus.ProtoReflect.Descriptor instead.
+func (*WorktreeStatus) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_vcs_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WorktreeStatus) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *WorktreeStatus) GetDirty() bool {
+	if x != nil {
+		return x.Dirty
+	}
+	return false
+}
+
+func (x *WorktreeStatus) GetAhead() int32 {
+	if x != nil {
+		return x.Ahead
+	}
+	return 0
+}
+
+func (x *WorktreeStatus) GetBehind() int32 {
+	if x != nil {
+		return x.Behind
+	}
+	return 0
+}
+
+// ValidateBranchNameRequest asks whether branch_name is a valid branch name.
+type ValidateBranchNameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BranchName    string                 `protobuf:"bytes,1,opt,name=branch_name,json=branchName,proto3" json:"branch_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateBranchNameRequest) Reset() {
+	*x = ValidateBranchNameRequest{}
+	mi := &file_swm_plugin_v1_vcs_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateBranchNameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateBranchNameRequest) ProtoMessage() {}
+
+func (x *ValidateBranchNameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_vcs_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateBranchNameRequest.ProtoReflect.Descriptor instead.
+func (*ValidateBranchNameRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_vcs_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ValidateBranchNameRequest) GetBranchName() string {
+	if x != nil {
+		return x.BranchName
+	}
+	return ""
+}
+
+// UserIdentityRequest asks for the identity the VCS records on commits.
+// repo_path is optional; when empty the user's global configuration is used.
+type UserIdentityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RepoPath      string                 `protobuf:"bytes,1,opt,name=repo_path,json=repoPath,proto3" json:"repo_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserIdentityRequest) Reset() {
+	*x = UserIdentityRequest{}
+	mi := &file_swm_plugin_v1_vcs_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserIdentityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserIdentityRequest) ProtoMessage() {}
+
+func (x *UserIdentityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_vcs_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserIdentityRequest.ProtoReflect.Descriptor instead.
+func (*UserIdentityRequest) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_vcs_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UserIdentityRequest) GetRepoPath() string {
+	if x != nil {
+		return x.RepoPath
+	}
+	return ""
+}
+
+// UserIdentity is the configured VCS user. Fields are empty when unset.
+type UserIdentity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserIdentity) Reset() {
+	*x = UserIdentity{}
+	mi := &file_swm_plugin_v1_vcs_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserIdentity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserIdentity) ProtoMessage() {}
+
+func (x *UserIdentity) ProtoReflect() protoreflect.Message {
+	mi := &file_swm_plugin_v1_vcs_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserIdentity.ProtoReflect.Descriptor instead.
+func (*UserIdentity) Descriptor() ([]byte, []int) {
+	return file_swm_plugin_v1_vcs_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UserIdentity) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UserIdentity) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
 var File_swm_plugin_v1_vcs_proto protoreflect.FileDescriptor
 
 const file_swm_plugin_v1_vcs_proto_rawDesc = "" +
 	"\n" +
-	"\x17swm/plugin/v1/vcs.proto\x12\rswm.plugin.v1\x1a\x1aswm/plugin/v1/common.proto\"n\n" +
+	"\x17swm/plugin/v1/vcs.proto\x12\rswm.plugin.v1\x1a\x1aswm/plugin/v1/common.proto\"\x8f\x01\n" +
 	"\aVCSInfo\x12:\n" +
 	"\vplugin_info\x18\x01 \x01(\v2\x19.swm.plugin.v1.PluginInfoR\n" +
 	"pluginInfo\x12'\n" +
-	"\x0fproject_markers\x18\x02 \x03(\tR\x0eprojectMarkers\"K\n" +
+	"\x0fproject_markers\x18\x02 \x03(\tR\x0eprojectMarkers\x12\x1f\n" +
+	"\vurl_schemes\x18\x03 \x03(\tR\n" +
+	"urlSchemes\"K\n" +
 	"\fCloneRequest\x12\x10\n" +
 	"\x03url\x18\x01 \x01(\tR\x03url\x12)\n" +
 	"\x10destination_path\x18\x02 \x01(\tR\x0fdestinationPath\"\x7f\n" +
@@ -573,7 +856,7 @@ const file_swm_plugin_v1_vcs_proto_rawDesc = "" +
 	"project_id\x18\x02 \x01(\v2\x18.swm.plugin.v1.ProjectIDH\x00R\tprojectIdB\a\n" +
 	"\x05event\")\n" +
 	"\x15ParseRemoteURLRequest\x12\x10\n" +
-	"\x03url\x18\x01 \x01(\tR\x03url\"\xd2\x01\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\"\xef\x01\n" +
 	"\x15CreateWorktreeRequest\x127\n" +
 	"\n" +
 	"project_id\x18\x01 \x01(\v2\x18.swm.plugin.v1.ProjectIDR\tprojectId\x12\x1d\n" +
@@ -582,7 +865,8 @@ const file_swm_plugin_v1_vcs_proto_rawDesc = "" +
 	"\vbranch_name\x18\x03 \x01(\tR\n" +
 	"branchName\x12\x1b\n" +
 	"\trepo_path\x18\x04 \x01(\tR\brepoPath\x12#\n" +
-	"\rworktree_path\x18\x05 \x01(\tR\fworktreePath\"\x94\x01\n" +
+	"\rworktree_path\x18\x05 \x01(\tR\fworktreePath\x12\x1b\n" +
+	"\tfetch_ref\x18\x06 \x01(\tR\bfetchRef\"\x94\x01\n" +
 	"\x15RemoveWorktreeRequest\x127\n" +
 	"\n" +
 	"project_id\x18\x01 \x01(\v2\x18.swm.plugin.v1.ProjectIDR\tprojectId\x12\x1d\n" +
@@ -599,7 +883,22 @@ const file_swm_plugin_v1_vcs_proto_rawDesc = "" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
 	"\tis_remote\x18\x02 \x01(\bR\bisRemote\x12\x1d\n" +
 	"\n" +
-	"is_current\x18\x03 \x01(\bR\tisCurrent2\x96\x04\n" +
+	"is_current\x18\x03 \x01(\bR\tisCurrent\"<\n" +
+	"\x15WorktreeStatusRequest\x12#\n" +
+	"\rworktree_path\x18\x01 \x01(\tR\fworktreePath\"l\n" +
+	"\x0eWorktreeStatus\x12\x16\n" +
+	"\x06branch\x18\x01 \x01(\tR\x06branch\x12\x14\n" +
+	"\x05dirty\x18\x02 \x01(\bR\x05dirty\x12\x14\n" +
+	"\x05ahead\x18\x03 \x01(\x05R\x05ahead\x12\x16\n" +
+	"\x06behind\x18\x04 \x01(\x05R\x06behind\"<\n" +
+	"\x19ValidateBranchNameRequest\x12\x1f\n" +
+	"\vbranch_name\x18\x01 \x01(\tR\n" +
+	"branchName\"2\n" +
+	"\x13UserIdentityRequest\x12\x1b\n" +
+	"\trepo_path\x18\x01 \x01(\tR\brepoPath\"8\n" +
+	"\fUserIdentity\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email2\x9a\x06\n" +
 	"\x03VCS\x124\n" +
 	"\x04Info\x12\x14.swm.plugin.v1.Empty\x1a\x16.swm.plugin.v1.VCSInfo\x12I\n" +
 	"\x05Clone\x12\x1b.swm.plugin.v1.CloneRequest\x1a!.swm.plugin.v1.CloneProgressEvent0\x01\x12P\n" +
@@ -607,7 +906,10 @@ const file_swm_plugin_v1_vcs_proto_rawDesc = "" +
 	"\x0eCreateWorktree\x12$.swm.plugin.v1.CreateWorktreeRequest\x1a\x14.swm.plugin.v1.Empty\x12L\n" +
 	"\x0eRemoveWorktree\x12$.swm.plugin.v1.RemoveWorktreeRequest\x1a\x14.swm.plugin.v1.Empty\x12S\n" +
 	"\x13DetectProjectAtPath\x12\".swm.plugin.v1.DetectAtPathRequest\x1a\x18.swm.plugin.v1.ProjectID\x12K\n" +
-	"\fListBranches\x12\".swm.plugin.v1.ListBranchesRequest\x1a\x15.swm.plugin.v1.Branch0\x01B6Z4github.com/kalbasit/swm/proto/swm/plugin/v1;pluginv1b\x06proto3"
+	"\fListBranches\x12\".swm.plugin.v1.ListBranchesRequest\x1a\x15.swm.plugin.v1.Branch0\x01\x12X\n" +
+	"\x11GetWorktreeStatus\x12$.swm.plugin.v1.WorktreeStatusRequest\x1a\x1d.swm.plugin.v1.WorktreeStatus\x12T\n" +
+	"\x12ValidateBranchName\x12(.swm.plugin.v1.ValidateBranchNameRequest\x1a\x14.swm.plugin.v1.Empty\x12R\n" +
+	"\x0fGetUserIdentity\x12\".swm.plugin.v1.UserIdentityRequest\x1a\x1b.swm.plugin.v1.UserIdentityB6Z4github.com/kalbasit/swm/proto/swm/plugin/v1;pluginv1b\x06proto3"
 
 var (
 	file_swm_plugin_v1_vcs_proto_rawDescOnce sync.Once
@@ -621,43 +923,54 @@ func file_swm_plugin_v1_vcs_proto_rawDescGZIP() []byte {
 	return file_swm_plugin_v1_vcs_proto_rawDescData
 }
 
-var file_swm_plugin_v1_vcs_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_swm_plugin_v1_vcs_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_swm_plugin_v1_vcs_proto_goTypes = []any{
-	(*VCSInfo)(nil),               // 0: swm.plugin.v1.VCSInfo
-	(*CloneRequest)(nil),          // 1: swm.plugin.v1.CloneRequest
-	(*CloneProgressEvent)(nil),    // 2: swm.plugin.v1.CloneProgressEvent
-	(*ParseRemoteURLRequest)(nil), // 3: swm.plugin.v1.ParseRemoteURLRequest
-	(*CreateWorktreeRequest)(nil), // 4: swm.plugin.v1.CreateWorktreeRequest
-	(*RemoveWorktreeRequest)(nil), // 5: swm.plugin.v1.RemoveWorktreeRequest
-	(*DetectAtPathRequest)(nil),   // 6: swm.plugin.v1.DetectAtPathRequest
-	(*ListBranchesRequest)(nil),   // 7: swm.plugin.v1.ListBranchesRequest
-	(*Branch)(nil),                // 8: swm.plugin.v1.Branch
-	(*PluginInfo)(nil),            // 9: swm.plugin.v1.PluginInfo
-	(*ProjectID)(nil),             // 10: swm.plugin.v1.ProjectID
-	(*Empty)(nil),                 // 11: swm.plugin.v1.Empty
+	(*VCSInfo)(nil),                   // 0: swm.plugin.v1.VCSInfo
+	(*CloneRequest)(nil),              // 1: swm.plugin.v1.CloneRequest
+	(*CloneProgressEvent)(nil),        // 2: swm.plugin.v1.CloneProgressEvent
+	(*ParseRemoteURLRequest)(nil),     // 3: swm.plugin.v1.ParseRemoteURLRequest
+	(*CreateWorktreeRequest)(nil),     // 4: swm.plugin.v1.CreateWorktreeRequest
+	(*RemoveWorktreeRequest)(nil),     // 5: swm.plugin.v1.RemoveWorktreeRequest
+	(*DetectAtPathRequest)(nil),       // 6: swm.plugin.v1.DetectAtPathRequest
+	(*ListBranchesRequest)(nil),       // 7: swm.plugin.v1.ListBranchesRequest
+	(*Branch)(nil),                    // 8: swm.plugin.v1.Branch
+	(*WorktreeStatusRequest)(nil),     // 9: swm.plugin.v1.WorktreeStatusRequest
+	(*WorktreeStatus)(nil),            // 10: swm.plugin.v1.WorktreeStatus
+	(*ValidateBranchNameRequest)(nil), // 11: swm.plugin.v1.ValidateBranchNameRequest
+	(*UserIdentityRequest)(nil),       // 12: swm.plugin.v1.UserIdentityRequest
+	(*UserIdentity)(nil),              // 13: swm.plugin.v1.UserIdentity
+	(*PluginInfo)(nil),                // 14: swm.plugin.v1.PluginInfo
+	(*ProjectID)(nil),                 // 15: swm.plugin.v1.ProjectID
+	(*Empty)(nil),                     // 16: swm.plugin.v1.Empty
 }
 var file_swm_plugin_v1_vcs_proto_depIdxs = []int32{
-	9,  // 0: swm.plugin.v1.VCSInfo.plugin_info:type_name -> swm.plugin.v1.PluginInfo
-	10, // 1: swm.plugin.v1.CloneProgressEvent.project_id:type_name -> swm.plugin.v1.ProjectID
-	10, // 2: swm.plugin.v1.CreateWorktreeRequest.project_id:type_name -> swm.plugin.v1.ProjectID
-	10, // 3: swm.plugin.v1.RemoveWorktreeRequest.project_id:type_name -> swm.plugin.v1.ProjectID
-	10, // 4: swm.plugin.v1.ListBranchesRequest.project_id:type_name -> swm.plugin.v1.ProjectID
-	11, // 5: swm.plugin.v1.VCS.Info:input_type -> swm.plugin.v1.Empty
+	14, // 0: swm.plugin.v1.VCSInfo.plugin_info:type_name -> swm.plugin.v1.PluginInfo
+	15, // 1: swm.plugin.v1.CloneProgressEvent.project_id:type_name -> swm.plugin.v1.ProjectID
+	15, // 2: swm.plugin.v1.CreateWorktreeRequest.project_id:type_name -> swm.plugin.v1.ProjectID
+	15, // 3: swm.plugin.v1.RemoveWorktreeRequest.project_id:type_name -> swm.plugin.v1.ProjectID
+	15, // 4: swm.plugin.v1.ListBranchesRequest.project_id:type_name -> swm.plugin.v1.ProjectID
+	16, // 5: swm.plugin.v1.VCS.Info:input_type -> swm.plugin.v1.Empty
 	1,  // 6: swm.plugin.v1.VCS.Clone:input_type -> swm.plugin.v1.CloneRequest
 	3,  // 7: swm.plugin.v1.VCS.ParseRemoteURL:input_type -> swm.plugin.v1.ParseRemoteURLRequest
 	4,  // 8: swm.plugin.v1.VCS.CreateWorktree:input_type -> swm.plugin.v1.CreateWorktreeRequest
 	5,  // 9: swm.plugin.v1.VCS.RemoveWorktree:input_type -> swm.plugin.v1.RemoveWorktreeRequest
 	6,  // 10: swm.plugin.v1.VCS.DetectProjectAtPath:input_type -> swm.plugin.v1.DetectAtPathRequest
 	7,  // 11: swm.plugin.v1.VCS.ListBranches:input_type -> swm.plugin.v1.ListBranchesRequest
-	0,  // 12: swm.plugin.v1.VCS.Info:output_type -> swm.plugin.v1.VCSInfo
-	2,  // 13: swm.plugin.v1.VCS.Clone:output_type -> swm.plugin.v1.CloneProgressEvent
-	10, // 14: swm.plugin.v1.VCS.ParseRemoteURL:output_type -> swm.plugin.v1.ProjectID
-	11, // 15: swm.plugin.v1.VCS.CreateWorktree:output_type -> swm.plugin.v1.Empty
-	11, // 16: swm.plugin.v1.VCS.RemoveWorktree:output_type -> swm.plugin.v1.Empty
-	10, // 17: swm.plugin.v1.VCS.DetectProjectAtPath:output_type -> swm.plugin.v1.ProjectID
-	8,  // 18: swm.plugin.v1.VCS.ListBranches:output_type -> swm.plugin.v1.Branch
-	12, // [12:19] is the sub-list for method output_type
-	5,  // [5:12] is the sub-list for method input_type
+	9,  // 12: swm.plugin.v1.VCS.GetWorktreeStatus:input_type -> swm.plugin.v1.WorktreeStatusRequest
+	11, // 13: swm.plugin.v1.VCS.ValidateBranchName:input_type -> swm.plugin.v1.ValidateBranchNameRequest
+	12, // 14: swm.plugin.v1.VCS.GetUserIdentity:input_type -> swm.plugin.v1.UserIdentityRequest
+	0,  // 15: swm.plugin.v1.VCS.Info:output_type -> swm.plugin.v1.VCSInfo
+	2,  // 16: swm.plugin.v1.VCS.Clone:output_type -> swm.plugin.v1.CloneProgressEvent
+	15, // 17: swm.plugin.v1.VCS.ParseRemoteURL:output_type -> swm.plugin.v1.ProjectID
+	16, // 18: swm.plugin.v1.VCS.CreateWorktree:output_type -> swm.plugin.v1.Empty
+	16, // 19: swm.plugin.v1.VCS.RemoveWorktree:output_type -> swm.plugin.v1.Empty
+	15, // 20: swm.plugin.v1.VCS.DetectProjectAtPath:output_type -> swm.plugin.v1.ProjectID
+	8,  // 21: swm.plugin.v1.VCS.ListBranches:output_type -> swm.plugin.v1.Branch
+	10, // 22: swm.plugin.v1.VCS.GetWorktreeStatus:output_type -> swm.plugin.v1.WorktreeStatus
+	16, // 23: swm.plugin.v1.VCS.ValidateBranchName:output_type -> swm.plugin.v1.Empty
+	13, // 24: swm.plugin.v1.VCS.GetUserIdentity:output_type -> swm.plugin.v1.UserIdentity
+	15, // [15:25] is the sub-list for method output_type
+	5,  // [5:15] is the sub-list for method input_type
 	5,  // [5:5] is the sub-list for extension type_name
 	5,  // [5:5] is the sub-list for extension extendee
 	0,  // [0:5] is the sub-list for field type_name
@@ -679,7 +992,7 @@ func file_swm_plugin_v1_vcs_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_swm_plugin_v1_vcs_proto_rawDesc), len(file_swm_plugin_v1_vcs_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
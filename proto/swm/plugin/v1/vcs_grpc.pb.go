@@ -26,6 +26,9 @@ const (
 	VCS_RemoveWorktree_FullMethodName      = "/swm.plugin.v1.VCS/RemoveWorktree"
 	VCS_DetectProjectAtPath_FullMethodName = "/swm.plugin.v1.VCS/DetectProjectAtPath"
 	VCS_ListBranches_FullMethodName        = "/swm.plugin.v1.VCS/ListBranches"
+	VCS_GetWorktreeStatus_FullMethodName   = "/swm.plugin.v1.VCS/GetWorktreeStatus"
+	VCS_ValidateBranchName_FullMethodName  = "/swm.plugin.v1.VCS/ValidateBranchName"
+	VCS_GetUserIdentity_FullMethodName     = "/swm.plugin.v1.VCS/GetUserIdentity"
 )
 
 // VCSClient is the client API for VCS service.
@@ -41,6 +44,11 @@ type VCSClient interface {
 	RemoveWorktree(ctx context.Context, in *RemoveWorktreeRequest, opts ...grpc.CallOption) (*Empty, error)
 	DetectProjectAtPath(ctx context.Context, in *DetectAtPathRequest, opts ...grpc.CallOption) (*ProjectID, error)
 	ListBranches(ctx context.Context, in *ListBranchesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Branch], error)
+	GetWorktreeStatus(ctx context.Context, in *WorktreeStatusRequest, opts ...grpc.CallOption) (*WorktreeStatus, error)
+	// ValidateBranchName returns INVALID_ARGUMENT when branch_name is not a
+	// valid branch name.
+	ValidateBranchName(ctx context.Context, in *ValidateBranchNameRequest, opts ...grpc.CallOption) (*Empty, error)
+	GetUserIdentity(ctx context.Context, in *UserIdentityRequest, opts ...grpc.CallOption) (*UserIdentity, error)
 }
 
 type vCSClient struct {
@@ -139,6 +147,36 @@ func (c *vCSClient) ListBranches(ctx context.Context, in *ListBranchesRequest, o
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type VCS_ListBranchesClient = grpc.ServerStreamingClient[Branch]
 
+func (c *vCSClient) GetWorktreeStatus(ctx context.Context, in *WorktreeStatusRequest, opts ...grpc.CallOption) (*WorktreeStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorktreeStatus)
+	err := c.cc.Invoke(ctx, VCS_GetWorktreeStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vCSClient) ValidateBranchName(ctx context.Context, in *ValidateBranchNameRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, VCS_ValidateBranchName_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vCSClient) GetUserIdentity(ctx context.Context, in *UserIdentityRequest, opts ...grpc.CallOption) (*UserIdentity, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserIdentity)
+	err := c.cc.Invoke(ctx, VCS_GetUserIdentity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // VCSServer is the server API for VCS service.
 // All implementations should embed UnimplementedVCSServer
 // for forward compatibility.
@@ -152,6 +190,11 @@ type VCSServer interface {
 	RemoveWorktree(context.Context, *RemoveWorktreeRequest) (*Empty, error)
 	DetectProjectAtPath(context.Context, *DetectAtPathRequest) (*ProjectID, error)
 	ListBranches(*ListBranchesRequest, grpc.ServerStreamingServer[Branch]) error
+	GetWorktreeStatus(context.Context, *WorktreeStatusRequest) (*WorktreeStatus, error)
+	// ValidateBranchName returns INVALID_ARGUMENT when branch_name is not a
+	// valid branch name.
+	ValidateBranchName(context.Context, *ValidateBranchNameRequest) (*Empty, error)
+	GetUserIdentity(context.Context, *UserIdentityRequest) (*UserIdentity, error)
 }
 
 // UnimplementedVCSServer should be embedded to have
@@ -182,6 +225,15 @@ func (UnimplementedVCSServer) DetectProjectAtPath(context.Context, *DetectAtPath
 func (UnimplementedVCSServer) ListBranches(*ListBranchesRequest, grpc.ServerStreamingServer[Branch]) error {
 	return status.Error(codes.Unimplemented, "method ListBranches not implemented")
 }
+func (UnimplementedVCSServer) GetWorktreeStatus(context.Context, *WorktreeStatusRequest) (*WorktreeStatus, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetWorktreeStatus not implemented")
+}
+func (UnimplementedVCSServer) ValidateBranchName(context.Context, *ValidateBranchNameRequest) (*Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method ValidateBranchName not implemented")
+}
+func (UnimplementedVCSServer) GetUserIdentity(context.Context, *UserIdentityRequest) (*UserIdentity, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUserIdentity not implemented")
+}
 func (UnimplementedVCSServer) testEmbeddedByValue() {}
 
 // UnsafeVCSServer may be embedded to opt out of forward compatibility for this service.
@@ -314,6 +366,60 @@ func _VCS_ListBranches_Handler(srv interface{}, stream grpc.ServerStream) error
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type VCS_ListBranchesServer = grpc.ServerStreamingServer[Branch]
 
+func _VCS_GetWorktreeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorktreeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCSServer).GetWorktreeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VCS_GetWorktreeStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCSServer).GetWorktreeStatus(ctx, req.(*WorktreeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VCS_ValidateBranchName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateBranchNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCSServer).ValidateBranchName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VCS_ValidateBranchName_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCSServer).ValidateBranchName(ctx, req.(*ValidateBranchNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VCS_GetUserIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIdentityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCSServer).GetUserIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VCS_GetUserIdentity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCSServer).GetUserIdentity(ctx, req.(*UserIdentityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // VCS_ServiceDesc is the grpc.ServiceDesc for VCS service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -341,6 +447,18 @@ var VCS_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DetectProjectAtPath",
 			Handler:    _VCS_DetectProjectAtPath_Handler,
 		},
+		{
+			MethodName: "GetWorktreeStatus",
+			Handler:    _VCS_GetWorktreeStatus_Handler,
+		},
+		{
+			MethodName: "ValidateBranchName",
+			Handler:    _VCS_ValidateBranchName_Handler,
+		},
+		{
+			MethodName: "GetUserIdentity",
+			Handler:    _VCS_GetUserIdentity_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -24,6 +24,7 @@ PACKAGES = [
     "swm-plugin-forge-github",
     "swm-plugin-picker-fzf",
     "swm-plugin-session-tmux",
+    "swm-plugin-tracker-jira",
     "swm-plugin-vcs-git",
     "swm",
 ]
@@ -0,0 +1,39 @@
+// Package command starts the child processes of swm plugins so that they stop
+// when swm cancels the call that started them: the user pressed Ctrl-C, or
+// the call ran past its [plugins.timeouts] deadline.
+package command
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// WaitDelay is how long a cancelled command has to exit after it was asked
+// to before it is killed.
+const WaitDelay = 5 * time.Second
+
+// New returns the exec.Cmd to run name with args, like exec.CommandContext.
+// The command runs in its own process group; when ctx is done the whole group
+// gets SIGTERM, so that the processes the command started itself (the ssh of
+// a git fetch, for instance) stop too, and the command is killed after
+// WaitDelay.
+func New(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = WaitDelay
+
+	ownGroup(cmd)
+
+	return cmd
+}
+
+// NewForeground is New for commands that use the terminal, such as fzf. They
+// stay in swm's process group, which the terminal sends Ctrl-C to, and only
+// the command itself gets SIGTERM when ctx is done.
+func NewForeground(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = WaitDelay
+	cmd.Cancel = func() error { return terminate(cmd) }
+
+	return cmd
+}
@@ -0,0 +1,38 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalbasit/swm/sdk/go/command"
+)
+
+func TestNew_StopsChildrenOnCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	// sh forks sleep, which holds stdout open; Output returns before
+	// WaitDelay only if sleep is stopped along with sh.
+	start := time.Now()
+
+	_, err := command.New(ctx, "sh", "-c", "sleep 30; :").Output()
+	require.Error(t, err)
+	require.Less(t, time.Since(start), command.WaitDelay)
+}
+
+func TestNewForeground_StopsOnCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	require.Error(t, command.NewForeground(ctx, "sleep", "30").Run())
+	require.Less(t, time.Since(start), command.WaitDelay)
+}
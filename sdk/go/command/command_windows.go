@@ -0,0 +1,14 @@
+//go:build windows
+
+package command
+
+import "os/exec"
+
+// ownGroup keeps exec.CommandContext's default of killing cmd; Windows has no
+// process groups to signal.
+func ownGroup(*exec.Cmd) {}
+
+// terminate kills cmd; Windows cannot ask a process to exit.
+func terminate(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
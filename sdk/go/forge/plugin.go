@@ -15,6 +15,7 @@ import (
 
 	"github.com/kalbasit/swm/sdk/go/handshake"
 	"github.com/kalbasit/swm/sdk/go/internal/pluginlog"
+	"github.com/kalbasit/swm/sdk/go/trace"
 )
 
 // Plugin is the interface a forge plugin must implement.
@@ -55,10 +56,10 @@ func Serve(impl Plugin) error {
 			JSONFormat: true,
 			Output:     os.Stderr,
 		}),
-		Plugins: goplugin.PluginSet{
+		VersionedPlugins: handshake.VersionedPlugins(goplugin.PluginSet{
 			"forge": &GRPCPlugin{Impl: impl},
-		},
-		GRPCServer: goplugin.DefaultGRPCServer,
+		}),
+		GRPCServer: trace.GRPCServer,
 	})
 
 	return nil
@@ -1,6 +1,11 @@
 // Package handshake exports the go-plugin handshake constants shared by the
 // swm host and all plugins. Both sides must import this package to guarantee
 // they agree on the magic cookie and protocol version.
+//
+// The protocol version is negotiated: the host offers every version it
+// speaks, the plugin answers with the highest one it also speaks, and the
+// host dispenses the plugin set of that version. This lets a host that
+// speaks a future v2 proto keep loading plugins built against v1.
 package handshake
 
 import "github.com/hashicorp/go-plugin"
@@ -12,11 +17,13 @@ const (
 
 	// MagicCookieValue is the expected value of MagicCookieKey.
 	// A plugin binary launched directly (without this value) prints a user-friendly error
-	// and exits instead of starting the gRPC server.
+	// and exits instead of starting the gRPC server. It does not change with the
+	// protocol version.
 	MagicCookieValue = "swm-plugin-v1"
 
 	// ProtocolVersion is bumped when the host/plugin wire protocol changes in a
-	// backwards-incompatible way. Plugins must advertise the same version.
+	// backwards-incompatible way, e.g. when proto/swm/plugin/v2 ships. It is the
+	// version a plugin speaks when the host does not offer any.
 	ProtocolVersion = 1
 )
 
@@ -27,3 +34,12 @@ var Config = plugin.HandshakeConfig{ //nolint:gochecknoglobals // package-level
 	MagicCookieKey:   MagicCookieKey,
 	MagicCookieValue: MagicCookieValue,
 }
+
+// VersionedPlugins returns the go-plugin VersionedPlugins map serving set
+// under ProtocolVersion. Pass it as VersionedPlugins to plugin.Serve and
+// plugin.NewClient so that both sides negotiate the protocol version. Once a
+// newer protocol exists the host adds the plugin set of every older version
+// it still supports to the map.
+func VersionedPlugins(set plugin.PluginSet) map[int]plugin.PluginSet {
+	return map[int]plugin.PluginSet{ProtocolVersion: set}
+}
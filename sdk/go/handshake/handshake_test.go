@@ -0,0 +1,21 @@
+package handshake_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kalbasit/swm/sdk/go/handshake"
+	"github.com/kalbasit/swm/sdk/go/vcs"
+)
+
+func TestVersionedPlugins(t *testing.T) {
+	t.Parallel()
+
+	set := plugin.PluginSet{"vcs": &vcs.GRPCPlugin{}}
+
+	versioned := handshake.VersionedPlugins(set)
+	require.Len(t, versioned, 1)
+	require.Equal(t, set, versioned[handshake.ProtocolVersion])
+}
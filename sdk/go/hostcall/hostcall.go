@@ -0,0 +1,213 @@
+// Package hostcall lets a plugin call the other plugins swm runs. Plugins
+// never dial each other: their calls go through the host's
+// Host.CallCapability, which checks that the calling plugin declared the
+// capability in the requires or optional of its Info(), and logs the call.
+package hostcall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+)
+
+const (
+	// CallerTokenEnv is the variable holding the token the host issues a
+	// plugin when it launches it. The token identifies the plugin's
+	// Host.CallCapability calls; each launch gets a new one.
+	CallerTokenEnv = "SWM_CALLER_TOKEN"
+	// CallerTokenKey is the gRPC metadata key carrying the token of the
+	// plugin making a Host.CallCapability call. Calls made through Conn
+	// carry it from CallerTokenEnv; the host refuses calls without a token
+	// it issued.
+	CallerTokenKey = "swm-caller-token"
+)
+
+var errNotMessage = errors.New("not a protobuf message")
+
+// Conn returns a connection to the plugin providing capability whose calls
+// go through host's CallCapability, for use with the generated clients:
+//
+//	vcs := pluginv1.NewVCSClient(hostcall.Conn(host, pluginv1.CapabilityType_CAPABILITY_TYPE_VCS))
+//
+// Unary and server-streaming methods are supported; the responses of a
+// stream arrive once the plugin sent them all.
+func Conn(host pluginv1.HostClient, capability pluginv1.CapabilityType) grpc.ClientConnInterface {
+	return &conn{host: host, capability: capability}
+}
+
+// Forge returns a client of the forge plugin handling the project_id of
+// each request.
+func Forge(host pluginv1.HostClient) pluginv1.ForgeClient {
+	return pluginv1.NewForgeClient(Conn(host, pluginv1.CapabilityType_CAPABILITY_TYPE_FORGE))
+}
+
+// Session returns a client of the configured session plugin.
+func Session(host pluginv1.HostClient) pluginv1.SessionClient {
+	return pluginv1.NewSessionClient(Conn(host, pluginv1.CapabilityType_CAPABILITY_TYPE_SESSION))
+}
+
+// Tracker returns a client of the configured tracker plugin.
+func Tracker(host pluginv1.HostClient) pluginv1.TrackerClient {
+	return pluginv1.NewTrackerClient(Conn(host, pluginv1.CapabilityType_CAPABILITY_TYPE_TRACKER))
+}
+
+// VCS returns a client of the default vcs plugin.
+func VCS(host pluginv1.HostClient) pluginv1.VCSClient {
+	return pluginv1.NewVCSClient(Conn(host, pluginv1.CapabilityType_CAPABILITY_TYPE_VCS))
+}
+
+// conn sends the calls to one capability through the host.
+type conn struct {
+	host       pluginv1.HostClient
+	capability pluginv1.CapabilityType
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (c *conn) Invoke(ctx context.Context, method string, args, reply any, _ ...grpc.CallOption) error {
+	result, err := c.call(ctx, method, args)
+	if err != nil {
+		return err
+	}
+
+	return decode([]byte(result), reply)
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (c *conn) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, _ ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	if desc.ClientStreams {
+		return nil, status.Errorf(codes.Unimplemented, "%s streams requests, which the host cannot relay", method)
+	}
+
+	return &stream{ctx: ctx, conn: c, method: method}, nil
+}
+
+// call calls method, a full gRPC method name, with the request args and
+// returns the JSON of its result.
+func (c *conn) call(ctx context.Context, method string, args any) (string, error) {
+	req, ok := args.(proto.Message)
+	if !ok {
+		return "", fmt.Errorf("%w: %T", errNotMessage, args)
+	}
+
+	argsJSON, err := protojson.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("encoding %s request: %w", method, err)
+	}
+
+	if token := os.Getenv(CallerTokenEnv); token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, CallerTokenKey, token)
+	}
+
+	resp, err := c.host.CallCapability(ctx, &pluginv1.CallCapabilityRequest{
+		Capability: c.capability,
+		Method:     method[strings.LastIndex(method, "/")+1:],
+		ArgsJson:   string(argsJSON),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.GetResultJson(), nil
+}
+
+// stream answers a server-streaming call with the responses the host
+// returned. The call is made on the first RecvMsg, after the request was
+// sent.
+type stream struct {
+	ctx    context.Context
+	conn   *conn
+	method string
+
+	mu        sync.Mutex
+	req       any
+	called    bool
+	responses []json.RawMessage
+}
+
+// CloseSend implements grpc.ClientStream.
+func (s *stream) CloseSend() error {
+	return nil
+}
+
+// Context implements grpc.ClientStream.
+func (s *stream) Context() context.Context {
+	return s.ctx
+}
+
+// Header implements grpc.ClientStream.
+func (s *stream) Header() (metadata.MD, error) {
+	return metadata.MD{}, nil
+}
+
+// RecvMsg implements grpc.ClientStream.
+func (s *stream) RecvMsg(m any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.called {
+		s.called = true
+
+		result, err := s.conn.call(s.ctx, s.method, s.req)
+		if err != nil {
+			return err
+		}
+
+		if err := json.Unmarshal([]byte(result), &s.responses); err != nil {
+			return fmt.Errorf("decoding %s responses: %w", s.method, err)
+		}
+	}
+
+	if len(s.responses) == 0 {
+		return io.EOF
+	}
+
+	next := s.responses[0]
+	s.responses = s.responses[1:]
+
+	return decode(next, m)
+}
+
+// SendMsg implements grpc.ClientStream.
+func (s *stream) SendMsg(m any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.req = m
+
+	return nil
+}
+
+// Trailer implements grpc.ClientStream.
+func (s *stream) Trailer() metadata.MD {
+	return metadata.MD{}
+}
+
+// decode decodes the JSON data of a response into m.
+func decode(data []byte, m any) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%w: %T", errNotMessage, m)
+	}
+
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("decoding %T: %w", m, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package hostcall_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/sdk/go/hostcall"
+)
+
+// fakeHost answers CallCapability with canned JSON. GetUserIdentity returns
+// the caller token as the name and the request's JSON as the email.
+type fakeHost struct {
+	pluginv1.UnimplementedHostServer
+}
+
+func (fakeHost) CallCapability(
+	ctx context.Context, req *pluginv1.CallCapabilityRequest,
+) (*pluginv1.CallCapabilityResponse, error) {
+	if req.GetCapability() != pluginv1.CapabilityType_CAPABILITY_TYPE_VCS {
+		return nil, status.Error(codes.PermissionDenied, "not declared")
+	}
+
+	switch req.GetMethod() {
+	case "GetUserIdentity":
+		result, err := json.Marshal(map[string]string{
+			"name":  strings.Join(metadata.ValueFromIncomingContext(ctx, hostcall.CallerTokenKey), ","),
+			"email": req.GetArgsJson(),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		return &pluginv1.CallCapabilityResponse{ResultJson: string(result)}, nil
+	case "ListBranches":
+		return &pluginv1.CallCapabilityResponse{ResultJson: `[{"name":"main"},{"name":"feat","isRemote":true}]`}, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "no method %q", req.GetMethod())
+	}
+}
+
+// dialHost returns a client of a fakeHost.
+func dialHost(t *testing.T) pluginv1.HostClient {
+	t.Helper()
+
+	lis, err := net.Listen("unix", filepath.Join(t.TempDir(), "host.sock"))
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	pluginv1.RegisterHostServer(srv, fakeHost{})
+
+	go srv.Serve(lis) //nolint:errcheck // stopped below
+
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("unix://"+lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	return pluginv1.NewHostClient(conn)
+}
+
+func TestVCS(t *testing.T) {
+	// Cannot use t.Parallel with t.Setenv.
+	t.Setenv(hostcall.CallerTokenEnv, "token-1")
+
+	host := dialHost(t)
+	vcs := hostcall.VCS(host)
+
+	id, err := vcs.GetUserIdentity(t.Context(), &pluginv1.UserIdentityRequest{RepoPath: "/repo"})
+	require.NoError(t, err)
+	require.Equal(t, "token-1", id.GetName())
+	require.JSONEq(t, `{"repoPath":"/repo"}`, id.GetEmail())
+
+	stream, err := vcs.ListBranches(t.Context(), &pluginv1.ListBranchesRequest{RepoPath: "/repo"})
+	require.NoError(t, err)
+
+	var names []string
+
+	for {
+		b, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		require.NoError(t, err)
+
+		names = append(names, b.GetName())
+	}
+
+	require.Equal(t, []string{"main", "feat"}, names)
+
+	// The host's errors keep their status.
+	_, err = vcs.ValidateBranchName(t.Context(), &pluginv1.ValidateBranchNameRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = hostcall.Tracker(host).GetIssue(t.Context(), &pluginv1.GetIssueRequest{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
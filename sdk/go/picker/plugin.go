@@ -14,6 +14,7 @@ import (
 
 	"github.com/kalbasit/swm/sdk/go/handshake"
 	"github.com/kalbasit/swm/sdk/go/internal/pluginlog"
+	"github.com/kalbasit/swm/sdk/go/trace"
 )
 
 // Plugin is the interface a picker plugin must implement.
@@ -54,10 +55,10 @@ func Serve(impl Plugin) error {
 			JSONFormat: true,
 			Output:     os.Stderr,
 		}),
-		Plugins: goplugin.PluginSet{
+		VersionedPlugins: handshake.VersionedPlugins(goplugin.PluginSet{
 			"picker": &GRPCPlugin{Impl: impl},
-		},
-		GRPCServer: goplugin.DefaultGRPCServer,
+		}),
+		GRPCServer: trace.GRPCServer,
 	})
 
 	return nil
@@ -0,0 +1,215 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// MetadataKey carries the trace context of a call from the host to the
+	// plugin: the id of the host's span of the call.
+	MetadataKey = "swm-trace"
+
+	// trailerKey carries the plugin's spans of a call back to the host.
+	trailerKey = "swm-trace-bin"
+
+	catGRPC = "grpc"
+)
+
+// UnaryClientInterceptor records a span for each call made with a traced
+// context, and the spans the plugin recorded serving it.
+func UnaryClientInterceptor(
+	ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	ctx, span := startCall(ctx, method)
+	if span == nil {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	defer span.End()
+
+	var trailer metadata.MD
+
+	err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Trailer(&trailer))...)
+	span.r.add(decodeEvents(trailer)...)
+
+	return err
+}
+
+// StreamClientInterceptor is UnaryClientInterceptor for streams. The span
+// lasts until the stream ends.
+func StreamClientInterceptor(
+	ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+	streamer grpc.Streamer, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	ctx, span := startCall(ctx, method)
+	if span == nil {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		span.End()
+
+		return nil, err
+	}
+
+	return &tracedStream{ClientStream: stream, span: span, serverStreams: desc.ServerStreams}, nil
+}
+
+// GRPCServer is a go-plugin ServeConfig.GRPCServer that records the calls a
+// tracing host makes, and the spans started from their context, and returns
+// them to the host.
+func GRPCServer(opts []grpc.ServerOption) *grpc.Server {
+	s := &server{process: filepath.Base(os.Args[0]), lanes: &lanes{}}
+
+	return grpc.NewServer(append(opts,
+		grpc.ChainUnaryInterceptor(s.unary),
+		grpc.ChainStreamInterceptor(s.stream),
+	)...)
+}
+
+// server records the spans of the calls a plugin serves.
+type server struct {
+	process string
+	lanes   *lanes // shared by the calls, which may run concurrently
+}
+
+// serve starts the span of the call to method in ctx if the host traces it.
+func (s *server) serve(ctx context.Context, method string) (context.Context, *Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	ids := md.Get(MetadataKey)
+	if len(ids) != 1 {
+		return ctx, nil
+	}
+
+	ctx, span := start(WithRecorder(ctx, newRecorder(s.process, s.lanes)), catGRPC, spanName(method))
+	span.flowIn = ids[0]
+
+	return ctx, span
+}
+
+func (s *server) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := s.serve(ss.Context(), info.FullMethod)
+	if span == nil {
+		return handler(srv, ss)
+	}
+
+	err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+
+	span.End()
+	ss.SetTrailer(encodeEvents(span.r))
+
+	return err
+}
+
+func (s *server) unary(
+	ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	ctx, span := s.serve(ctx, info.FullMethod)
+	if span == nil {
+		return handler(ctx, req)
+	}
+
+	resp, err := handler(ctx, req)
+
+	span.End()
+	grpc.SetTrailer(ctx, encodeEvents(span.r)) //nolint:errcheck,gosec // the host just gets no plugin spans
+
+	return resp, err
+}
+
+// serverStream is a ServerStream with the traced context.
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+// Context implements grpc.ServerStream.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// tracedStream ends the span of a stream when the stream ends: on an error
+// or io.EOF, or on its only response.
+type tracedStream struct {
+	grpc.ClientStream
+
+	span          *Span
+	serverStreams bool
+	done          bool
+}
+
+// RecvMsg implements grpc.ClientStream.
+func (s *tracedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if (err != nil || !s.serverStreams) && !s.done {
+		s.done = true
+		s.span.r.add(decodeEvents(s.Trailer())...)
+		s.span.End()
+	}
+
+	return err
+}
+
+// startCall starts the span of a call to method, and passes its id to the
+// plugin along with a flow event linking the two.
+func startCall(ctx context.Context, method string) (context.Context, *Span) {
+	ctx, span := start(ctx, catGRPC, spanName(method))
+	if span == nil {
+		return ctx, nil
+	}
+
+	id := strconv.FormatUint(span.id, 16)
+
+	span.r.add(event{
+		Name: "call", Cat: catGRPC, Ph: phaseFlowStart,
+		Ts: span.start.UnixMicro(), Pid: span.r.pid, Tid: span.tid, ID: id,
+	})
+
+	return metadata.AppendToOutgoingContext(ctx, MetadataKey, id), span
+}
+
+// spanName turns "/swm.plugin.v1.VCS/CreateWorktree" into
+// "VCS.CreateWorktree".
+func spanName(method string) string {
+	service, name, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+
+	return service[strings.LastIndex(service, ".")+1:] + "." + name
+}
+
+// encodeEvents returns the trailer carrying the events of r.
+func encodeEvents(r *Recorder) metadata.MD {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.Marshal(r.events)
+	if err != nil {
+		return nil
+	}
+
+	return metadata.Pairs(trailerKey, string(data))
+}
+
+// decodeEvents returns the events in a plugin's trailer.
+func decodeEvents(trailer metadata.MD) []event {
+	var events []event
+
+	for _, data := range trailer.Get(trailerKey) {
+		var batch []event
+		if json.Unmarshal([]byte(data), &batch) == nil {
+			events = append(events, batch...)
+		}
+	}
+
+	return events
+}
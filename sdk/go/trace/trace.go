@@ -0,0 +1,254 @@
+// Package trace records spans of swm and its plugins in the Chrome
+// trace-event format, which Perfetto (ui.perfetto.dev) and chrome://tracing
+// display.
+//
+// swm records a trace when run with --trace or SWM_TRACE. The host's gRPC
+// calls carry the trace context to the plugin, whose spans travel back in
+// the call's trailer, so a plugin only has to start spans from the context
+// of the call:
+//
+//	func (g *Git) CreateWorktree(ctx context.Context, req *pluginv1.CreateWorktreeRequest) (...) {
+//		ctx, span := trace.Start(ctx, "git worktree add", "path", req.GetPath())
+//		defer span.End()
+//		...
+//	}
+//
+// Without a trace, Start returns a nil span, whose End does nothing.
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Trace-event phases, see the Trace Event Format document.
+const (
+	phaseComplete  = "X"
+	phaseMetadata  = "M"
+	phaseFlowStart = "s"
+	phaseFlowEnd   = "f"
+)
+
+type (
+	recorderKey struct{}
+	spanKey     struct{}
+)
+
+// event is a Chrome trace event. Timestamps and durations are in
+// microseconds; ts is the wall clock, so that events of the host and its
+// plugins line up.
+type event struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	Ts   int64          `json:"ts"`
+	Dur  int64          `json:"dur,omitempty"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	ID   string         `json:"id,omitempty"`
+	Bp   string         `json:"bp,omitempty"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// Recorder collects the spans of one process.
+type Recorder struct {
+	pid   int
+	lanes *lanes
+
+	mu     sync.Mutex
+	events []event
+	named  map[int]bool // pids with a process_name event
+}
+
+// NewRecorder returns a Recorder of this process, shown as process in the
+// trace.
+func NewRecorder(process string) *Recorder {
+	return newRecorder(process, &lanes{})
+}
+
+func newRecorder(process string, l *lanes) *Recorder {
+	r := &Recorder{pid: os.Getpid(), lanes: l, named: make(map[int]bool)}
+	r.add(event{Name: "process_name", Ph: phaseMetadata, Pid: r.pid, Args: map[string]any{"name": process}})
+
+	return r
+}
+
+// WriteFile writes the recorded trace to path as JSON.
+func (r *Recorder) WriteFile(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.Marshal(struct {
+		TraceEvents     []event `json:"traceEvents"`
+		DisplayTimeUnit string  `json:"displayTimeUnit"`
+	}{r.events, "ms"})
+	if err != nil {
+		return fmt.Errorf("encoding trace: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing trace: %w", err)
+	}
+
+	return nil
+}
+
+// add records events, dropping the process name of a process already named.
+func (r *Recorder) add(events ...event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range events {
+		if e.Ph == phaseMetadata {
+			if r.named[e.Pid] {
+				continue
+			}
+
+			r.named[e.Pid] = true
+		}
+
+		r.events = append(r.events, e)
+	}
+}
+
+// WithRecorder returns a copy of ctx in which spans are recorded to r.
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// Span is an operation being timed. A nil *Span is valid and does nothing.
+type Span struct {
+	r      *Recorder
+	id     uint64
+	name   string
+	cat    string
+	tid    int
+	start  time.Time
+	args   map[string]any
+	flowIn string // id of the flow from the host call, for the handler span
+}
+
+// Start starts a span named name as a child of the span in ctx, and returns
+// a context carrying it. args are key-value pairs shown with the span, as
+// with log/slog. Start returns a nil span when ctx is not being traced.
+func Start(ctx context.Context, name string, args ...any) (context.Context, *Span) {
+	return start(ctx, "", name, args...)
+}
+
+// End records the span.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.r.lanes.release(s.tid, s.id)
+
+	events := []event{{
+		Name: s.name,
+		Cat:  s.cat,
+		Ph:   phaseComplete,
+		Ts:   s.start.UnixMicro(),
+		Dur:  max(time.Since(s.start).Microseconds(), 1),
+		Pid:  s.r.pid,
+		Tid:  s.tid,
+		Args: s.args,
+	}}
+
+	if s.flowIn != "" {
+		events = append(events, event{
+			Name: "call", Cat: catGRPC, Ph: phaseFlowEnd, Bp: "e",
+			Ts: s.start.UnixMicro(), Pid: s.r.pid, Tid: s.tid, ID: s.flowIn,
+		})
+	}
+
+	s.r.add(events...)
+}
+
+// SetArg adds key and value to the arguments shown with the span.
+func (s *Span) SetArg(key string, value any) {
+	if s == nil {
+		return
+	}
+
+	if s.args == nil {
+		s.args = make(map[string]any)
+	}
+
+	s.args[key] = fmt.Sprint(value)
+}
+
+// start is Start with a category.
+func start(ctx context.Context, cat, name string, args ...any) (context.Context, *Span) {
+	r, ok := ctx.Value(recorderKey{}).(*Recorder)
+	if !ok {
+		return ctx, nil
+	}
+
+	var parent uint64
+	if p, ok := ctx.Value(spanKey{}).(*Span); ok && p.r == r {
+		parent = p.id
+	}
+
+	s := &Span{r: r, id: rand.Uint64(), name: name, cat: cat, start: time.Now()} //nolint:gosec // not a secret
+	s.tid = r.lanes.take(parent, s.id)
+
+	for i := 0; i+1 < len(args); i += 2 {
+		s.SetArg(fmt.Sprint(args[i]), args[i+1])
+	}
+
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// lanes assigns spans to the threads of the trace. Complete events of one
+// thread must nest, so a span goes on the thread of its parent when the
+// parent is the innermost span open there, and on a free thread otherwise,
+// as when a command runs calls concurrently.
+type lanes struct {
+	mu   sync.Mutex
+	open [][]uint64 // the ids of the spans open on thread i+1, innermost last
+}
+
+// take places span id, a child of parent (0 for none), and returns its thread.
+func (l *lanes) take(parent, id uint64) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lane := -1
+
+	for i, open := range l.open {
+		if parent != 0 && len(open) > 0 && open[len(open)-1] == parent {
+			lane = i
+
+			break
+		}
+
+		if lane < 0 && len(open) == 0 {
+			lane = i
+		}
+	}
+
+	if lane < 0 {
+		l.open = append(l.open, nil)
+		lane = len(l.open) - 1
+	}
+
+	l.open[lane] = append(l.open[lane], id)
+
+	return lane + 1
+}
+
+// release removes span id from thread tid.
+func (l *lanes) release(tid int, id uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if i := slices.Index(l.open[tid-1], id); i >= 0 {
+		l.open[tid-1] = slices.Delete(l.open[tid-1], i, i+1)
+	}
+}
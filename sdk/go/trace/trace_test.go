@@ -0,0 +1,154 @@
+package trace_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/sdk/go/trace"
+)
+
+// traceEvent is the part of a trace event the tests look at.
+type traceEvent struct {
+	Name string         `json:"name"`
+	Ph   string         `json:"ph"`
+	Tid  int            `json:"tid"`
+	ID   string         `json:"id"`
+	Args map[string]any `json:"args"`
+}
+
+// readTrace writes r to a file and reads its events back.
+func readTrace(t *testing.T, r *trace.Recorder) []traceEvent {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	require.NoError(t, r.WriteFile(path))
+
+	data, err := os.ReadFile(path) //nolint:gosec // test file
+	require.NoError(t, err)
+
+	var out struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}
+
+	require.NoError(t, json.Unmarshal(data, &out))
+
+	return out.TraceEvents
+}
+
+// find returns the events named name with phase ph.
+func find(events []traceEvent, name, ph string) []traceEvent {
+	var found []traceEvent
+
+	for _, e := range events {
+		if e.Name == name && e.Ph == ph {
+			found = append(found, e)
+		}
+	}
+
+	return found
+}
+
+func TestStart_NotTraced(t *testing.T) {
+	t.Parallel()
+
+	ctx, span := trace.Start(t.Context(), "work")
+	require.Nil(t, span)
+	require.Equal(t, t.Context(), ctx)
+
+	span.SetArg("key", "value")
+	span.End()
+}
+
+func TestRecorder_NestsSpans(t *testing.T) {
+	t.Parallel()
+
+	r := trace.NewRecorder("swm")
+	ctx := trace.WithRecorder(t.Context(), r)
+
+	ctx, parent := trace.Start(ctx, "parent", "story", "feat")
+	_, first := trace.Start(ctx, "first")
+	// A sibling started while first runs cannot nest on its thread.
+	_, second := trace.Start(ctx, "second")
+	second.End()
+	first.End()
+	parent.End()
+
+	events := readTrace(t, r)
+	require.Equal(t, "swm", find(events, "process_name", "M")[0].Args["name"])
+
+	p, f, s := find(events, "parent", "X")[0], find(events, "first", "X")[0], find(events, "second", "X")[0]
+	require.Equal(t, "feat", p.Args["story"])
+	require.Equal(t, p.Tid, f.Tid)
+	require.NotEqual(t, p.Tid, s.Tid)
+}
+
+// identityServer records a span while serving GetUserIdentity.
+type identityServer struct {
+	pluginv1.UnimplementedVCSServer
+}
+
+func (identityServer) GetUserIdentity(
+	ctx context.Context, _ *pluginv1.UserIdentityRequest,
+) (*pluginv1.UserIdentity, error) {
+	_, span := trace.Start(ctx, "git config")
+	defer span.End()
+
+	return &pluginv1.UserIdentity{Name: "Fake"}, nil
+}
+
+func TestInterceptors(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "plugin.sock")
+
+	lis, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	srv := trace.GRPCServer(nil)
+	pluginv1.RegisterVCSServer(srv, identityServer{})
+
+	go srv.Serve(lis) //nolint:errcheck // stopped below
+
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("unix://"+socket,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(trace.UnaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(trace.StreamClientInterceptor),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() }) //nolint:errcheck,gosec // best-effort in test cleanup
+
+	client := pluginv1.NewVCSClient(conn)
+
+	// Calls without a trace are not recorded.
+	_, err = client.GetUserIdentity(t.Context(), &pluginv1.UserIdentityRequest{})
+	require.NoError(t, err)
+
+	r := trace.NewRecorder("swm")
+
+	_, err = client.GetUserIdentity(trace.WithRecorder(t.Context(), r), &pluginv1.UserIdentityRequest{})
+	require.NoError(t, err)
+
+	events := readTrace(t, r)
+
+	// The host's span of the call, and the plugin's span serving it.
+	require.Len(t, find(events, "VCS.GetUserIdentity", "X"), 2)
+	require.Len(t, find(events, "git config", "X"), 1)
+
+	// A flow links the two.
+	start, end := find(events, "call", "s"), find(events, "call", "f")
+	require.Len(t, start, 1)
+	require.Len(t, end, 1)
+	require.Equal(t, start[0].ID, end[0].ID)
+}
@@ -0,0 +1,66 @@
+// Package tracker provides the SDK surface for swm tracker plugins.
+// A tracker plugin talks to an issue tracker (e.g. Jira) to look up issues
+// and update them as work on a story progresses.
+package tracker
+
+import (
+	"context"
+	"os"
+
+	"google.golang.org/grpc"
+
+	hclog "github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	pluginv1 "github.com/kalbasit/swm/proto/swm/plugin/v1"
+
+	"github.com/kalbasit/swm/sdk/go/handshake"
+	"github.com/kalbasit/swm/sdk/go/internal/pluginlog"
+	"github.com/kalbasit/swm/sdk/go/trace"
+)
+
+// Plugin is the interface a tracker plugin must implement.
+// It is identical to pluginv1.TrackerServer, so implementors can embed
+// pluginv1.UnimplementedTrackerServer and override only the methods they need.
+type Plugin = pluginv1.TrackerServer
+
+// GRPCPlugin implements go-plugin's GRPCPlugin interface for the Tracker capability.
+type GRPCPlugin struct {
+	goplugin.NetRPCUnsupportedPlugin
+	Impl Plugin
+}
+
+// GRPCClient returns a TrackerClient backed by the provided connection.
+func (p *GRPCPlugin) GRPCClient(_ context.Context, _ *goplugin.GRPCBroker, conn *grpc.ClientConn) (any, error) {
+	return pluginv1.NewTrackerClient(conn), nil
+}
+
+// GRPCServer registers the Tracker gRPC server with the provided gRPC server instance.
+func (p *GRPCPlugin) GRPCServer(_ *goplugin.GRPCBroker, s *grpc.Server) error {
+	pluginv1.RegisterTrackerServer(s, p.Impl)
+
+	return nil
+}
+
+// NewClient returns a TrackerClient backed by the provided connection.
+func NewClient(conn *grpc.ClientConn) pluginv1.TrackerClient {
+	return pluginv1.NewTrackerClient(conn)
+}
+
+// Serve starts the go-plugin gRPC server for the given Plugin implementation.
+// It blocks until the host signals the plugin to exit.
+func Serve(impl Plugin) error {
+	goplugin.Serve(&goplugin.ServeConfig{
+		HandshakeConfig: handshake.Config,
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Level:      pluginlog.Level(),
+			JSONFormat: true,
+			Output:     os.Stderr,
+		}),
+		VersionedPlugins: handshake.VersionedPlugins(goplugin.PluginSet{
+			"tracker": &GRPCPlugin{Impl: impl},
+		}),
+		GRPCServer: trace.GRPCServer,
+	})
+
+	return nil
+}
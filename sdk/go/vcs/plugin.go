@@ -15,6 +15,7 @@ import (
 
 	"github.com/kalbasit/swm/sdk/go/handshake"
 	"github.com/kalbasit/swm/sdk/go/internal/pluginlog"
+	"github.com/kalbasit/swm/sdk/go/trace"
 )
 
 // Plugin is the interface a VCS plugin must implement.
@@ -50,10 +51,10 @@ func Serve(impl Plugin) error {
 			JSONFormat: true,
 			Output:     os.Stderr,
 		}),
-		Plugins: goplugin.PluginSet{
+		VersionedPlugins: handshake.VersionedPlugins(goplugin.PluginSet{
 			"vcs": &GRPCPlugin{Impl: impl},
-		},
-		GRPCServer: goplugin.DefaultGRPCServer,
+		}),
+		GRPCServer: trace.GRPCServer,
 	})
 
 	return nil